@@ -5,6 +5,8 @@ import { useEffect, useState } from "react";
 export interface ScanProgressData {
 	current: number;
 	total: number;
+	processed: number;
+	skipped: number;
 	lastFile: string;
 }
 
@@ -47,7 +49,8 @@ export const useScanProgress = () => {
 				isScanning: true,
 				current: data.current,
 				total: data.total,
-				message: `Processing: ${data.lastFile}`, // Krótszy tekst
+				// Pominięte = niezmienione od ostatniego skanu (bez hashowania)
+				message: `Processed ${data.processed}, unchanged ${data.skipped}`,
 				progress: percent,
 			});
 		});
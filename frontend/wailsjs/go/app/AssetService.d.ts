@@ -5,42 +5,106 @@ import {context} from '../models';
 
 export function AddAssetToMaterialSet(arg1:number,arg2:number):Promise<void>;
 
+export function ApplyBatchRename(arg1:app.BatchRenameRequest):Promise<app.BatchResult>;
+
+export function BatchAddTags(arg1:app.BatchTarget,arg2:Array<string>):Promise<app.BatchResult>;
+
+export function BatchAddToMaterialSet(arg1:app.BatchTarget,arg2:number):Promise<app.BatchResult>;
+
+export function BatchRemoveFromMaterialSet(arg1:app.BatchTarget,arg2:number):Promise<app.BatchResult>;
+
+export function BatchRemoveTags(arg1:app.BatchTarget,arg2:Array<string>):Promise<app.BatchResult>;
+
+export function BatchSetFavorite(arg1:app.BatchTarget,arg2:boolean):Promise<app.BatchResult>;
+
+export function BatchSetHidden(arg1:app.BatchTarget,arg2:boolean):Promise<app.BatchResult>;
+
+export function BatchSetRating(arg1:app.BatchTarget,arg2:number):Promise<app.BatchResult>;
+
+export function BatchSetType(arg1:app.BatchTarget,arg2:string):Promise<app.BatchResult>;
+
+export function CleanupExpiredAssets():Promise<void>;
+
+export function ClearHistory():Promise<void>;
+
+export function CopyAssets(arg1:Array<number>,arg2:string):Promise<app.BatchResult>;
+
 export function DeleteAssetsPermanently(arg1:Array<number>):Promise<void>;
 
+export function DetachAssets(arg1:Array<number>):Promise<string>;
+
+export function FindSimilar(arg1:number,arg2:number):Promise<Array<app.SimilarAssetMatch>>;
+
 export function GetAssetById(arg1:number):Promise<app.AssetDetails>;
 
+export function GetAssetGroup(arg1:string):Promise<app.AssetGroup>;
+
 export function GetAssetVersions(arg1:number):Promise<Array<app.AssetDetails>>;
 
 export function GetAssets(arg1:app.AssetQueryFilters):Promise<app.PagedAssetResult>;
 
+export function GetAssetsBySavedSearch(arg1:number,arg2:number,arg3:number):Promise<app.PagedAssetResult>;
+
 export function GetAvailableColors():Promise<Array<string>>;
 
+export function GetDuplicates():Promise<app.DuplicateReport>;
+
+export function GetHistory(arg1:number):Promise<Array<app.HistoryEntry>>;
+
 export function GetLibraryStats():Promise<app.LibraryStats>;
 
+export function GetMaterialChannels(arg1:string):Promise<Array<app.MaterialChannel>>;
+
 export function GetSidebarStats():Promise<app.SidebarStats>;
 
+export function GetSimilarImageClusters(arg1:number):Promise<Array<app.SimilarAssetCluster>>;
+
 export function GetThumbnailData(arg1:number):Promise<string>;
 
+export function GetTrashedAssets():Promise<Array<app.TrashedAsset>>;
+
+export function MergeGroups(arg1:Array<string>):Promise<string>;
+
 export function MigrateThumbnailPaths():Promise<void>;
 
+export function MoveAssets(arg1:Array<number>,arg2:string):Promise<app.BatchResult>;
+
+export function PreviewBatchRename(arg1:app.BatchRenameRequest):Promise<app.BatchRenamePreview>;
+
+export function PurgeTrashedAssets(arg1:Array<number>):Promise<void>;
+
+export function Redo():Promise<app.HistoryEntry>;
+
 export function RemoveAssetFromMaterialSet(arg1:number,arg2:number):Promise<void>;
 
 export function RenameAsset(arg1:number,arg2:string):Promise<void>;
 
+export function ResolveDuplicates(arg1:app.DedupRequest):Promise<app.DedupResult>;
+
 export function RestoreAssets(arg1:Array<number>):Promise<void>;
 
+export function RestoreDeletedAsset(arg1:number):Promise<number>;
+
 export function SetAssetHidden(arg1:number,arg2:boolean):Promise<void>;
 
 export function SetAssetRating(arg1:number,arg2:number):Promise<void>;
 
+export function SetGroupLocked(arg1:string,arg2:boolean):Promise<void>;
+
+export function SetGroupRepresentative(arg1:string,arg2:number):Promise<void>;
+
 export function SoftDeleteAssets(arg1:Array<number>):Promise<void>;
 
 export function Startup(arg1:context.Context):Promise<void>;
 
 export function ToggleAssetFavorite(arg1:number):Promise<void>;
 
+export function Undo():Promise<app.HistoryEntry>;
+
 export function UpdateAssetMetadata(arg1:number,arg2:app.UpdateAssetRequest):Promise<app.AssetDetails>;
 
 export function UpdateAssetType(arg1:number,arg2:string):Promise<void>;
 
 export function UpdateTags(arg1:number,arg2:Array<string>):Promise<void>;
+
+export function ValidateSearchQuery(arg1:string):Promise<app.SearchQueryError>;
@@ -6,14 +6,74 @@ export function AddAssetToMaterialSet(arg1, arg2) {
   return window['go']['app']['AssetService']['AddAssetToMaterialSet'](arg1, arg2);
 }
 
+export function ApplyBatchRename(arg1) {
+  return window['go']['app']['AssetService']['ApplyBatchRename'](arg1);
+}
+
+export function BatchAddTags(arg1, arg2) {
+  return window['go']['app']['AssetService']['BatchAddTags'](arg1, arg2);
+}
+
+export function BatchAddToMaterialSet(arg1, arg2) {
+  return window['go']['app']['AssetService']['BatchAddToMaterialSet'](arg1, arg2);
+}
+
+export function BatchRemoveFromMaterialSet(arg1, arg2) {
+  return window['go']['app']['AssetService']['BatchRemoveFromMaterialSet'](arg1, arg2);
+}
+
+export function BatchRemoveTags(arg1, arg2) {
+  return window['go']['app']['AssetService']['BatchRemoveTags'](arg1, arg2);
+}
+
+export function BatchSetFavorite(arg1, arg2) {
+  return window['go']['app']['AssetService']['BatchSetFavorite'](arg1, arg2);
+}
+
+export function BatchSetHidden(arg1, arg2) {
+  return window['go']['app']['AssetService']['BatchSetHidden'](arg1, arg2);
+}
+
+export function BatchSetRating(arg1, arg2) {
+  return window['go']['app']['AssetService']['BatchSetRating'](arg1, arg2);
+}
+
+export function BatchSetType(arg1, arg2) {
+  return window['go']['app']['AssetService']['BatchSetType'](arg1, arg2);
+}
+
+export function CleanupExpiredAssets() {
+  return window['go']['app']['AssetService']['CleanupExpiredAssets']();
+}
+
+export function ClearHistory() {
+  return window['go']['app']['AssetService']['ClearHistory']();
+}
+
+export function CopyAssets(arg1, arg2) {
+  return window['go']['app']['AssetService']['CopyAssets'](arg1, arg2);
+}
+
 export function DeleteAssetsPermanently(arg1) {
   return window['go']['app']['AssetService']['DeleteAssetsPermanently'](arg1);
 }
 
+export function DetachAssets(arg1) {
+  return window['go']['app']['AssetService']['DetachAssets'](arg1);
+}
+
+export function FindSimilar(arg1, arg2) {
+  return window['go']['app']['AssetService']['FindSimilar'](arg1, arg2);
+}
+
 export function GetAssetById(arg1) {
   return window['go']['app']['AssetService']['GetAssetById'](arg1);
 }
 
+export function GetAssetGroup(arg1) {
+  return window['go']['app']['AssetService']['GetAssetGroup'](arg1);
+}
+
 export function GetAssetVersions(arg1) {
   return window['go']['app']['AssetService']['GetAssetVersions'](arg1);
 }
@@ -22,26 +82,70 @@ export function GetAssets(arg1) {
   return window['go']['app']['AssetService']['GetAssets'](arg1);
 }
 
+export function GetAssetsBySavedSearch(arg1, arg2, arg3) {
+  return window['go']['app']['AssetService']['GetAssetsBySavedSearch'](arg1, arg2, arg3);
+}
+
 export function GetAvailableColors() {
   return window['go']['app']['AssetService']['GetAvailableColors']();
 }
 
+export function GetDuplicates() {
+  return window['go']['app']['AssetService']['GetDuplicates']();
+}
+
+export function GetHistory(arg1) {
+  return window['go']['app']['AssetService']['GetHistory'](arg1);
+}
+
 export function GetLibraryStats() {
   return window['go']['app']['AssetService']['GetLibraryStats']();
 }
 
+export function GetMaterialChannels(arg1) {
+  return window['go']['app']['AssetService']['GetMaterialChannels'](arg1);
+}
+
 export function GetSidebarStats() {
   return window['go']['app']['AssetService']['GetSidebarStats']();
 }
 
+export function GetSimilarImageClusters(arg1) {
+  return window['go']['app']['AssetService']['GetSimilarImageClusters'](arg1);
+}
+
 export function GetThumbnailData(arg1) {
   return window['go']['app']['AssetService']['GetThumbnailData'](arg1);
 }
 
+export function GetTrashedAssets() {
+  return window['go']['app']['AssetService']['GetTrashedAssets']();
+}
+
+export function MergeGroups(arg1) {
+  return window['go']['app']['AssetService']['MergeGroups'](arg1);
+}
+
 export function MigrateThumbnailPaths() {
   return window['go']['app']['AssetService']['MigrateThumbnailPaths']();
 }
 
+export function MoveAssets(arg1, arg2) {
+  return window['go']['app']['AssetService']['MoveAssets'](arg1, arg2);
+}
+
+export function PreviewBatchRename(arg1) {
+  return window['go']['app']['AssetService']['PreviewBatchRename'](arg1);
+}
+
+export function PurgeTrashedAssets(arg1) {
+  return window['go']['app']['AssetService']['PurgeTrashedAssets'](arg1);
+}
+
+export function Redo() {
+  return window['go']['app']['AssetService']['Redo']();
+}
+
 export function RemoveAssetFromMaterialSet(arg1, arg2) {
   return window['go']['app']['AssetService']['RemoveAssetFromMaterialSet'](arg1, arg2);
 }
@@ -50,10 +154,18 @@ export function RenameAsset(arg1, arg2) {
   return window['go']['app']['AssetService']['RenameAsset'](arg1, arg2);
 }
 
+export function ResolveDuplicates(arg1) {
+  return window['go']['app']['AssetService']['ResolveDuplicates'](arg1);
+}
+
 export function RestoreAssets(arg1) {
   return window['go']['app']['AssetService']['RestoreAssets'](arg1);
 }
 
+export function RestoreDeletedAsset(arg1) {
+  return window['go']['app']['AssetService']['RestoreDeletedAsset'](arg1);
+}
+
 export function SetAssetHidden(arg1, arg2) {
   return window['go']['app']['AssetService']['SetAssetHidden'](arg1, arg2);
 }
@@ -62,6 +174,14 @@ export function SetAssetRating(arg1, arg2) {
   return window['go']['app']['AssetService']['SetAssetRating'](arg1, arg2);
 }
 
+export function SetGroupLocked(arg1, arg2) {
+  return window['go']['app']['AssetService']['SetGroupLocked'](arg1, arg2);
+}
+
+export function SetGroupRepresentative(arg1, arg2) {
+  return window['go']['app']['AssetService']['SetGroupRepresentative'](arg1, arg2);
+}
+
 export function SoftDeleteAssets(arg1) {
   return window['go']['app']['AssetService']['SoftDeleteAssets'](arg1);
 }
@@ -74,6 +194,10 @@ export function ToggleAssetFavorite(arg1) {
   return window['go']['app']['AssetService']['ToggleAssetFavorite'](arg1);
 }
 
+export function Undo() {
+  return window['go']['app']['AssetService']['Undo']();
+}
+
 export function UpdateAssetMetadata(arg1, arg2) {
   return window['go']['app']['AssetService']['UpdateAssetMetadata'](arg1, arg2);
 }
@@ -85,3 +209,7 @@ export function UpdateAssetType(arg1, arg2) {
 export function UpdateTags(arg1, arg2) {
   return window['go']['app']['AssetService']['UpdateTags'](arg1, arg2);
 }
+
+export function ValidateSearchQuery(arg1) {
+  return window['go']['app']['AssetService']['ValidateSearchQuery'](arg1);
+}
@@ -1,5 +1,47 @@
 export namespace app {
 	
+	export class AssetColor {
+	    hex: string;
+	    paletteName: string;
+	    paletteHex: string;
+	    weight: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new AssetColor(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.hex = source["hex"];
+	        this.paletteName = source["paletteName"];
+	        this.paletteHex = source["paletteHex"];
+	        this.weight = source["weight"];
+	    }
+	}
+	export class AssetModel {
+	    vertexCount: number;
+	    triangleCount: number;
+	    meshCount: number;
+	    materialCount: number;
+	    textureCount: number;
+	    textures: string[];
+	    size: number[];
+	
+	    static createFrom(source: any = {}) {
+	        return new AssetModel(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.vertexCount = source["vertexCount"];
+	        this.triangleCount = source["triangleCount"];
+	        this.meshCount = source["meshCount"];
+	        this.materialCount = source["materialCount"];
+	        this.textureCount = source["textureCount"];
+	        this.textures = source["textures"];
+	        this.size = source["size"];
+	    }
+	}
 	export class AssetMaterialSet {
 	    id: number;
 	    name: string;
@@ -41,6 +83,10 @@ export namespace app {
 	    tags: string[];
 	    materialSets: AssetMaterialSet[];
 	    dominantColor: string;
+	    colors: AssetColor[];
+	    properties: Record<string, string>;
+	    model?: AssetModel;
+	    materialChannel: string;
 	
 	    static createFrom(source: any = {}) {
 	        return new AssetDetails(source);
@@ -70,6 +116,10 @@ export namespace app {
 	        this.tags = source["tags"];
 	        this.materialSets = this.convertValues(source["materialSets"], AssetMaterialSet);
 	        this.dominantColor = source["dominantColor"];
+	        this.colors = this.convertValues(source["colors"], AssetColor);
+	        this.properties = source["properties"];
+	        this.model = this.convertValues(source["model"], AssetModel);
+	        this.materialChannel = source["materialChannel"];
 	    }
 	
 		convertValues(a: any, classs: any, asMap: boolean = false): any {
@@ -90,7 +140,38 @@ export namespace app {
 		    return a;
 		}
 	}
+	export class AssetGroup {
+	    groupId: string;
+	    isLocked: boolean;
+	    representativeId?: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new AssetGroup(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.groupId = source["groupId"];
+	        this.isLocked = source["isLocked"];
+	        this.representativeId = source["representativeId"];
+	    }
+	}
+	
+	
+	export class ColorSearchFilter {
+	    hex: string;
+	    tolerance: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new ColorSearchFilter(source);
+	    }
 	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.hex = source["hex"];
+	        this.tolerance = source["tolerance"];
+	    }
+	}
 	export class AssetQueryFilters {
 	    page: number;
 	    pageSize: number;
@@ -99,13 +180,17 @@ export namespace app {
 	    matchAllTags: boolean;
 	    fileTypes: string[];
 	    colors: string[];
+	    colorWeightThreshold: number;
+	    colorSearch?: ColorSearchFilter;
 	    ratingRange: number[];
 	    widthRange: number[];
 	    heightRange: number[];
 	    fileSizeRange: number[];
+	    polyCountRange: number[];
 	    // Go type: struct { From *string "json:\"from\""; To *string "json:\"to\"" }
 	    dateRange: any;
 	    hasAlpha?: boolean;
+	    hasTextures?: boolean;
 	    onlyFavorites: boolean;
 	    onlyUncategorized: boolean;
 	    isDeleted: boolean;
@@ -128,12 +213,16 @@ export namespace app {
 	        this.matchAllTags = source["matchAllTags"];
 	        this.fileTypes = source["fileTypes"];
 	        this.colors = source["colors"];
+	        this.colorWeightThreshold = source["colorWeightThreshold"];
+	        this.colorSearch = this.convertValues(source["colorSearch"], ColorSearchFilter);
 	        this.ratingRange = source["ratingRange"];
 	        this.widthRange = source["widthRange"];
 	        this.heightRange = source["heightRange"];
 	        this.fileSizeRange = source["fileSizeRange"];
+	        this.polyCountRange = source["polyCountRange"];
 	        this.dateRange = this.convertValues(source["dateRange"], Object);
 	        this.hasAlpha = source["hasAlpha"];
+	        this.hasTextures = source["hasTextures"];
 	        this.onlyFavorites = source["onlyFavorites"];
 	        this.onlyUncategorized = source["onlyUncategorized"];
 	        this.isDeleted = source["isDeleted"];
@@ -162,41 +251,60 @@ export namespace app {
 		    return a;
 		}
 	}
-	export class CreateMaterialSetRequest {
-	    name: string;
-	    description?: string;
-	    coverAssetId?: number;
-	    customCoverUrl?: string;
-	    customColor?: string;
+	export class BatchItemResult {
+	    assetId: number;
+	    status: string;
+	    error?: string;
+	    newAssetId?: number;
 	
 	    static createFrom(source: any = {}) {
-	        return new CreateMaterialSetRequest(source);
+	        return new BatchItemResult(source);
 	    }
 	
 	    constructor(source: any = {}) {
 	        if ('string' === typeof source) source = JSON.parse(source);
-	        this.name = source["name"];
-	        this.description = source["description"];
-	        this.coverAssetId = source["coverAssetId"];
-	        this.customCoverUrl = source["customCoverUrl"];
-	        this.customColor = source["customColor"];
+	        this.assetId = source["assetId"];
+	        this.status = source["status"];
+	        this.error = source["error"];
+	        this.newAssetId = source["newAssetId"];
 	    }
 	}
-	export class LibraryStats {
-	    totalAssets: number;
-	    totalSize: number;
-	    // Go type: time
-	    lastScan?: any;
+	export class BatchRenameItem {
+	    assetId: number;
+	    oldName: string;
+	    newName: string;
+	    status: string;
+	    reason?: string;
 	
 	    static createFrom(source: any = {}) {
-	        return new LibraryStats(source);
+	        return new BatchRenameItem(source);
 	    }
 	
 	    constructor(source: any = {}) {
 	        if ('string' === typeof source) source = JSON.parse(source);
-	        this.totalAssets = source["totalAssets"];
-	        this.totalSize = source["totalSize"];
-	        this.lastScan = this.convertValues(source["lastScan"], null);
+	        this.assetId = source["assetId"];
+	        this.oldName = source["oldName"];
+	        this.newName = source["newName"];
+	        this.status = source["status"];
+	        this.reason = source["reason"];
+	    }
+	}
+	export class BatchRenamePreview {
+	    items: BatchRenameItem[];
+	    ready: number;
+	    unchanged: number;
+	    conflicts: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new BatchRenamePreview(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.items = this.convertValues(source["items"], BatchRenameItem);
+	        this.ready = source["ready"];
+	        this.unchanged = source["unchanged"];
+	        this.conflicts = source["conflicts"];
 	    }
 	
 		convertValues(a: any, classs: any, asMap: boolean = false): any {
@@ -217,36 +325,18 @@ export namespace app {
 		    return a;
 		}
 	}
-	export class MaterialSet {
-	    id: number;
-	    name: string;
-	    description?: string;
-	    coverAssetId?: number;
-	    customCoverUrl?: string;
-	    customColor?: string;
-	    thumbnailPath: string;
-	    // Go type: time
-	    dateAdded: any;
-	    // Go type: time
-	    lastModified: any;
-	    totalAssets: number;
+	export class BatchTarget {
+	    ids: number[];
+	    filters?: AssetQueryFilters;
 	
 	    static createFrom(source: any = {}) {
-	        return new MaterialSet(source);
+	        return new BatchTarget(source);
 	    }
 	
 	    constructor(source: any = {}) {
 	        if ('string' === typeof source) source = JSON.parse(source);
-	        this.id = source["id"];
-	        this.name = source["name"];
-	        this.description = source["description"];
-	        this.coverAssetId = source["coverAssetId"];
-	        this.customCoverUrl = source["customCoverUrl"];
-	        this.customColor = source["customColor"];
-	        this.thumbnailPath = source["thumbnailPath"];
-	        this.dateAdded = this.convertValues(source["dateAdded"], null);
-	        this.lastModified = this.convertValues(source["lastModified"], null);
-	        this.totalAssets = source["totalAssets"];
+	        this.ids = source["ids"];
+	        this.filters = this.convertValues(source["filters"], AssetQueryFilters);
 	    }
 	
 		convertValues(a: any, classs: any, asMap: boolean = false): any {
@@ -267,22 +357,26 @@ export namespace app {
 		    return a;
 		}
 	}
-	export class PagedAssetResult {
-	    items: AssetDetails[];
-	    totalCount: number;
-	    page: number;
-	    pageSize: number;
+	export class BatchRenameRequest {
+	    target: BatchTarget;
+	    template: string;
+	    find: string;
+	    replace: string;
+	    case: string;
+	    counterStart?: number;
 	
 	    static createFrom(source: any = {}) {
-	        return new PagedAssetResult(source);
+	        return new BatchRenameRequest(source);
 	    }
 	
 	    constructor(source: any = {}) {
 	        if ('string' === typeof source) source = JSON.parse(source);
-	        this.items = this.convertValues(source["items"], AssetDetails);
-	        this.totalCount = source["totalCount"];
-	        this.page = source["page"];
-	        this.pageSize = source["pageSize"];
+	        this.target = this.convertValues(source["target"], BatchTarget);
+	        this.template = source["template"];
+	        this.find = source["find"];
+	        this.replace = source["replace"];
+	        this.case = source["case"];
+	        this.counterStart = source["counterStart"];
 	    }
 	
 		convertValues(a: any, classs: any, asMap: boolean = false): any {
@@ -303,122 +397,157 @@ export namespace app {
 		    return a;
 		}
 	}
-	export class SidebarStats {
-	    totalAssets: number;
-	    totalUncategorized: number;
-	    totalFavorites: number;
-	    totalTrash: number;
-	    totalHidden: number;
+	export class BatchResult {
+	    requested: number;
+	    changed: number;
+	    unchanged: number;
+	    failed: number;
+	    items: BatchItemResult[];
 	
 	    static createFrom(source: any = {}) {
-	        return new SidebarStats(source);
+	        return new BatchResult(source);
 	    }
 	
 	    constructor(source: any = {}) {
 	        if ('string' === typeof source) source = JSON.parse(source);
-	        this.totalAssets = source["totalAssets"];
-	        this.totalUncategorized = source["totalUncategorized"];
-	        this.totalFavorites = source["totalFavorites"];
-	        this.totalTrash = source["totalTrash"];
-	        this.totalHidden = source["totalHidden"];
+	        this.requested = source["requested"];
+	        this.changed = source["changed"];
+	        this.unchanged = source["unchanged"];
+	        this.failed = source["failed"];
+	        this.items = this.convertValues(source["items"], BatchItemResult);
 	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
 	}
-	export class Tag {
+	
+	export class ColorPalette {
 	    id: number;
 	    name: string;
-	    assetCount: number;
+	    colors: config.PaletteColor[];
+	    isActive: boolean;
+	    // Go type: time
+	    dateAdded: any;
 	
 	    static createFrom(source: any = {}) {
-	        return new Tag(source);
+	        return new ColorPalette(source);
 	    }
 	
 	    constructor(source: any = {}) {
 	        if ('string' === typeof source) source = JSON.parse(source);
 	        this.id = source["id"];
 	        this.name = source["name"];
-	        this.assetCount = source["assetCount"];
+	        this.colors = this.convertValues(source["colors"], config.PaletteColor);
+	        this.isActive = source["isActive"];
+	        this.dateAdded = this.convertValues(source["dateAdded"], null);
 	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
 	}
-	export class UpdateAssetRequest {
-	    Description?: string;
-	    Rating?: number;
-	    IsFavorite?: boolean;
+	
+	export class CreateMaterialSetRequest {
+	    name: string;
+	    description?: string;
+	    coverAssetId?: number;
+	    customCoverUrl?: string;
+	    customColor?: string;
 	
 	    static createFrom(source: any = {}) {
-	        return new UpdateAssetRequest(source);
+	        return new CreateMaterialSetRequest(source);
 	    }
 	
 	    constructor(source: any = {}) {
 	        if ('string' === typeof source) source = JSON.parse(source);
-	        this.Description = source["Description"];
-	        this.Rating = source["Rating"];
-	        this.IsFavorite = source["IsFavorite"];
+	        this.name = source["name"];
+	        this.description = source["description"];
+	        this.coverAssetId = source["coverAssetId"];
+	        this.customCoverUrl = source["customCoverUrl"];
+	        this.customColor = source["customColor"];
 	    }
 	}
-
-}
-
-export namespace config {
-	
-	export class PaletteColor {
-	    name: string;
-	    hex: string;
+	export class DedupChange {
+	    fileHash: string;
+	    keepId: number;
+	    keepPath: string;
+	    assetId: number;
+	    filePath: string;
+	    freedBytes: number;
+	    error?: string;
 	
 	    static createFrom(source: any = {}) {
-	        return new PaletteColor(source);
+	        return new DedupChange(source);
 	    }
 	
 	    constructor(source: any = {}) {
 	        if ('string' === typeof source) source = JSON.parse(source);
-	        this.name = source["name"];
-	        this.hex = source["hex"];
+	        this.fileHash = source["fileHash"];
+	        this.keepId = source["keepId"];
+	        this.keepPath = source["keepPath"];
+	        this.assetId = source["assetId"];
+	        this.filePath = source["filePath"];
+	        this.freedBytes = source["freedBytes"];
+	        this.error = source["error"];
 	    }
 	}
-
-}
-
-export namespace database {
+	export class DuplicateResolution {
+	    fileHash: string;
+	    keepId: number;
 	
-	export class CreateAssetParams {
-	    scanFolderId: sql.NullInt64;
-	    fileName: string;
-	    filePath: string;
-	    fileType: string;
-	    fileSize: number;
-	    thumbnailPath: string;
-	    fileHash: sql.NullString;
-	    imageWidth: sql.NullInt64;
-	    imageHeight: sql.NullInt64;
-	    dominantColor: sql.NullString;
-	    bitDepth: sql.NullInt64;
-	    hasAlphaChannel: sql.NullBool;
-	    // Go type: time
-	    lastModified: any;
-	    // Go type: time
-	    lastScanned: any;
-	    groupId: string;
+	    static createFrom(source: any = {}) {
+	        return new DuplicateResolution(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.fileHash = source["fileHash"];
+	        this.keepId = source["keepId"];
+	    }
+	}
+	export class DedupRequest {
+	    action: string;
+	    resolutions: DuplicateResolution[];
+	    dryRun: boolean;
 	
 	    static createFrom(source: any = {}) {
-	        return new CreateAssetParams(source);
+	        return new DedupRequest(source);
 	    }
 	
 	    constructor(source: any = {}) {
 	        if ('string' === typeof source) source = JSON.parse(source);
-	        this.scanFolderId = this.convertValues(source["scanFolderId"], sql.NullInt64);
-	        this.fileName = source["fileName"];
-	        this.filePath = source["filePath"];
-	        this.fileType = source["fileType"];
-	        this.fileSize = source["fileSize"];
-	        this.thumbnailPath = source["thumbnailPath"];
-	        this.fileHash = this.convertValues(source["fileHash"], sql.NullString);
-	        this.imageWidth = this.convertValues(source["imageWidth"], sql.NullInt64);
-	        this.imageHeight = this.convertValues(source["imageHeight"], sql.NullInt64);
-	        this.dominantColor = this.convertValues(source["dominantColor"], sql.NullString);
-	        this.bitDepth = this.convertValues(source["bitDepth"], sql.NullInt64);
-	        this.hasAlphaChannel = this.convertValues(source["hasAlphaChannel"], sql.NullBool);
-	        this.lastModified = this.convertValues(source["lastModified"], null);
-	        this.lastScanned = this.convertValues(source["lastScanned"], null);
-	        this.groupId = source["groupId"];
+	        this.action = source["action"];
+	        this.resolutions = this.convertValues(source["resolutions"], DuplicateResolution);
+	        this.dryRun = source["dryRun"];
 	    }
 	
 		convertValues(a: any, classs: any, asMap: boolean = false): any {
@@ -439,20 +568,770 @@ export namespace database {
 		    return a;
 		}
 	}
-	export class UpdateAssetFromScanParams {
-	    filePath: sql.NullString;
-	    scanFolderId: sql.NullInt64;
-	    isDeleted: sql.NullBool;
-	    fileSize: sql.NullInt64;
-	    fileHash: sql.NullString;
-	    lastModified: sql.NullTime;
-	    lastScanned: sql.NullTime;
-	    thumbnailPath: sql.NullString;
-	    imageWidth: sql.NullInt64;
-	    imageHeight: sql.NullInt64;
-	    dominantColor: sql.NullString;
-	    bitDepth: sql.NullInt64;
+	export class DedupResult {
+	    action: string;
+	    dryRun: boolean;
+	    changes: DedupChange[];
+	    freedBytes: number;
+	    failed: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new DedupResult(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.action = source["action"];
+	        this.dryRun = source["dryRun"];
+	        this.changes = this.convertValues(source["changes"], DedupChange);
+	        this.freedBytes = source["freedBytes"];
+	        this.failed = source["failed"];
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class DuplicateCopy {
+	    id: number;
+	    fileName: string;
+	    filePath: string;
+	    thumbnailPath: string;
+	    groupId: string;
+	    // Go type: time
+	    dateAdded: any;
+	    linked: boolean;
+	
+	    static createFrom(source: any = {}) {
+	        return new DuplicateCopy(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.id = source["id"];
+	        this.fileName = source["fileName"];
+	        this.filePath = source["filePath"];
+	        this.thumbnailPath = source["thumbnailPath"];
+	        this.groupId = source["groupId"];
+	        this.dateAdded = this.convertValues(source["dateAdded"], null);
+	        this.linked = source["linked"];
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class DuplicateSet {
+	    fileHash: string;
+	    fileSize: number;
+	    copies: DuplicateCopy[];
+	    wastedBytes: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new DuplicateSet(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.fileHash = source["fileHash"];
+	        this.fileSize = source["fileSize"];
+	        this.copies = this.convertValues(source["copies"], DuplicateCopy);
+	        this.wastedBytes = source["wastedBytes"];
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class DuplicateReport {
+	    sets: DuplicateSet[];
+	    totalWastedBytes: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new DuplicateReport(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.sets = this.convertValues(source["sets"], DuplicateSet);
+	        this.totalWastedBytes = source["totalWastedBytes"];
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	
+	
+	export class HistoryEntry {
+	    id: number;
+	    kind: string;
+	    description: string;
+	    undone: boolean;
+	    // Go type: time
+	    createdAt: any;
+	
+	    static createFrom(source: any = {}) {
+	        return new HistoryEntry(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.id = source["id"];
+	        this.kind = source["kind"];
+	        this.description = source["description"];
+	        this.undone = source["undone"];
+	        this.createdAt = this.convertValues(source["createdAt"], null);
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class LibraryStats {
+	    totalAssets: number;
+	    totalSize: number;
+	    // Go type: time
+	    lastScan?: any;
+	
+	    static createFrom(source: any = {}) {
+	        return new LibraryStats(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.totalAssets = source["totalAssets"];
+	        this.totalSize = source["totalSize"];
+	        this.lastScan = this.convertValues(source["lastScan"], null);
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class MaterialChannel {
+	    role: string;
+	    assetId: number;
+	    fileName: string;
+	    filePath: string;
+	    thumbnailPath: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new MaterialChannel(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.role = source["role"];
+	        this.assetId = source["assetId"];
+	        this.fileName = source["fileName"];
+	        this.filePath = source["filePath"];
+	        this.thumbnailPath = source["thumbnailPath"];
+	    }
+	}
+	export class MaterialSet {
+	    id: number;
+	    name: string;
+	    description?: string;
+	    coverAssetId?: number;
+	    customCoverUrl?: string;
+	    customColor?: string;
+	    thumbnailPath: string;
+	    // Go type: time
+	    dateAdded: any;
+	    // Go type: time
+	    lastModified: any;
+	    totalAssets: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new MaterialSet(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.id = source["id"];
+	        this.name = source["name"];
+	        this.description = source["description"];
+	        this.coverAssetId = source["coverAssetId"];
+	        this.customCoverUrl = source["customCoverUrl"];
+	        this.customColor = source["customColor"];
+	        this.thumbnailPath = source["thumbnailPath"];
+	        this.dateAdded = this.convertValues(source["dateAdded"], null);
+	        this.lastModified = this.convertValues(source["lastModified"], null);
+	        this.totalAssets = source["totalAssets"];
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class PagedAssetResult {
+	    items: AssetDetails[];
+	    totalCount: number;
+	    page: number;
+	    pageSize: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new PagedAssetResult(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.items = this.convertValues(source["items"], AssetDetails);
+	        this.totalCount = source["totalCount"];
+	        this.page = source["page"];
+	        this.pageSize = source["pageSize"];
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class SavedSearch {
+	    id: number;
+	    name: string;
+	    filters: AssetQueryFilters;
+	    // Go type: time
+	    dateAdded: any;
+	
+	    static createFrom(source: any = {}) {
+	        return new SavedSearch(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.id = source["id"];
+	        this.name = source["name"];
+	        this.filters = this.convertValues(source["filters"], AssetQueryFilters);
+	        this.dateAdded = this.convertValues(source["dateAdded"], null);
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class SearchQueryError {
+	    pos: number;
+	    message: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new SearchQueryError(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.pos = source["pos"];
+	        this.message = source["message"];
+	    }
+	}
+	export class SidebarStats {
+	    totalAssets: number;
+	    totalUncategorized: number;
+	    totalFavorites: number;
+	    totalTrash: number;
+	    totalHidden: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new SidebarStats(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.totalAssets = source["totalAssets"];
+	        this.totalUncategorized = source["totalUncategorized"];
+	        this.totalFavorites = source["totalFavorites"];
+	        this.totalTrash = source["totalTrash"];
+	        this.totalHidden = source["totalHidden"];
+	    }
+	}
+	export class SimilarAsset {
+	    id: number;
+	    fileName: string;
+	    filePath: string;
+	    thumbnailPath: string;
+	    fileSize: number;
+	    imageWidth: number;
+	    imageHeight: number;
+	    distance: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new SimilarAsset(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.id = source["id"];
+	        this.fileName = source["fileName"];
+	        this.filePath = source["filePath"];
+	        this.thumbnailPath = source["thumbnailPath"];
+	        this.fileSize = source["fileSize"];
+	        this.imageWidth = source["imageWidth"];
+	        this.imageHeight = source["imageHeight"];
+	        this.distance = source["distance"];
+	    }
+	}
+	export class SimilarAssetCluster {
+	    assets: SimilarAsset[];
+	
+	    static createFrom(source: any = {}) {
+	        return new SimilarAssetCluster(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.assets = this.convertValues(source["assets"], SimilarAsset);
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class SimilarAssetMatch {
+	    asset: AssetDetails;
+	    distance: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new SimilarAssetMatch(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.asset = this.convertValues(source["asset"], AssetDetails);
+	        this.distance = source["distance"];
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class Tag {
+	    id: number;
+	    name: string;
+	    assetCount: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new Tag(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.id = source["id"];
+	        this.name = source["name"];
+	        this.assetCount = source["assetCount"];
+	    }
+	}
+	export class TrashedAsset {
+	    id: number;
+	    fileName: string;
+	    fileType: string;
+	    fileSize: number;
+	    originalPath: string;
+	    thumbnailPath: string;
+	    backend: string;
+	    trashPath: string;
+	    tags: string[];
+	    // Go type: time
+	    deletedAt: any;
+	    restorable: boolean;
+	
+	    static createFrom(source: any = {}) {
+	        return new TrashedAsset(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.id = source["id"];
+	        this.fileName = source["fileName"];
+	        this.fileType = source["fileType"];
+	        this.fileSize = source["fileSize"];
+	        this.originalPath = source["originalPath"];
+	        this.thumbnailPath = source["thumbnailPath"];
+	        this.backend = source["backend"];
+	        this.trashPath = source["trashPath"];
+	        this.tags = source["tags"];
+	        this.deletedAt = this.convertValues(source["deletedAt"], null);
+	        this.restorable = source["restorable"];
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class UpdateAssetRequest {
+	    Description?: string;
+	    Rating?: number;
+	    IsFavorite?: boolean;
+	
+	    static createFrom(source: any = {}) {
+	        return new UpdateAssetRequest(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.Description = source["Description"];
+	        this.Rating = source["Rating"];
+	        this.IsFavorite = source["IsFavorite"];
+	    }
+	}
+
+}
+
+export namespace config {
+	
+	export class GroupingRules {
+	    stripPatterns: string[];
+	    ignorePatterns: string[];
+	    minBaseLength: number;
+	    crossFolders: boolean;
+	
+	    static createFrom(source: any = {}) {
+	        return new GroupingRules(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.stripPatterns = source["stripPatterns"];
+	        this.ignorePatterns = source["ignorePatterns"];
+	        this.minBaseLength = source["minBaseLength"];
+	        this.crossFolders = source["crossFolders"];
+	    }
+	}
+	export class GroupingRuleSet {
+	    global: GroupingRules;
+	    folders: Record<number, GroupingRules>;
+	
+	    static createFrom(source: any = {}) {
+	        return new GroupingRuleSet(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.global = this.convertValues(source["global"], GroupingRules);
+	        this.folders = this.convertValues(source["folders"], GroupingRules, true);
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	
+	export class MaterialChannel {
+	    role: string;
+	    synonyms: string[];
+	
+	    static createFrom(source: any = {}) {
+	        return new MaterialChannel(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.role = source["role"];
+	        this.synonyms = source["synonyms"];
+	    }
+	}
+	export class PaletteColor {
+	    name: string;
+	    hex: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new PaletteColor(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.name = source["name"];
+	        this.hex = source["hex"];
+	    }
+	}
+	export class ThumbnailProvider {
+	    name: string;
+	    extensions: string[];
+	    command: string;
+	    timeoutSeconds: number;
+	    maxConcurrent: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new ThumbnailProvider(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.name = source["name"];
+	        this.extensions = source["extensions"];
+	        this.command = source["command"];
+	        this.timeoutSeconds = source["timeoutSeconds"];
+	        this.maxConcurrent = source["maxConcurrent"];
+	    }
+	}
+
+}
+
+export namespace database {
+	
+	export class CreateAssetParams {
+	    scanFolderId: sql.NullInt64;
+	    fileName: string;
+	    filePath: string;
+	    fileType: string;
+	    fileSize: number;
+	    thumbnailPath: string;
+	    fileHash: sql.NullString;
+	    imageWidth: sql.NullInt64;
+	    imageHeight: sql.NullInt64;
+	    dominantColor: sql.NullString;
+	    bitDepth: sql.NullInt64;
+	    hasAlphaChannel: sql.NullBool;
+	    // Go type: time
+	    lastModified: any;
+	    // Go type: time
+	    lastScanned: any;
+	    groupId: string;
+	    perceptualHash: sql.NullString;
+	    materialChannel: string;
+	    analysisVersion: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new CreateAssetParams(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.scanFolderId = this.convertValues(source["scanFolderId"], sql.NullInt64);
+	        this.fileName = source["fileName"];
+	        this.filePath = source["filePath"];
+	        this.fileType = source["fileType"];
+	        this.fileSize = source["fileSize"];
+	        this.thumbnailPath = source["thumbnailPath"];
+	        this.fileHash = this.convertValues(source["fileHash"], sql.NullString);
+	        this.imageWidth = this.convertValues(source["imageWidth"], sql.NullInt64);
+	        this.imageHeight = this.convertValues(source["imageHeight"], sql.NullInt64);
+	        this.dominantColor = this.convertValues(source["dominantColor"], sql.NullString);
+	        this.bitDepth = this.convertValues(source["bitDepth"], sql.NullInt64);
+	        this.hasAlphaChannel = this.convertValues(source["hasAlphaChannel"], sql.NullBool);
+	        this.lastModified = this.convertValues(source["lastModified"], null);
+	        this.lastScanned = this.convertValues(source["lastScanned"], null);
+	        this.groupId = source["groupId"];
+	        this.perceptualHash = this.convertValues(source["perceptualHash"], sql.NullString);
+	        this.materialChannel = source["materialChannel"];
+	        this.analysisVersion = source["analysisVersion"];
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class UpdateAssetFromScanParams {
+	    filePath: sql.NullString;
+	    scanFolderId: sql.NullInt64;
+	    isDeleted: sql.NullBool;
+	    fileSize: sql.NullInt64;
+	    fileHash: sql.NullString;
+	    lastModified: sql.NullTime;
+	    lastScanned: sql.NullTime;
+	    thumbnailPath: sql.NullString;
+	    imageWidth: sql.NullInt64;
+	    imageHeight: sql.NullInt64;
+	    dominantColor: sql.NullString;
+	    bitDepth: sql.NullInt64;
 	    hasAlphaChannel: sql.NullBool;
+	    materialChannel: sql.NullString;
+	    analysisVersion: sql.NullInt64;
 	    id: number;
 	
 	    static createFrom(source: any = {}) {
@@ -474,6 +1353,8 @@ export namespace database {
 	        this.dominantColor = this.convertValues(source["dominantColor"], sql.NullString);
 	        this.bitDepth = this.convertValues(source["bitDepth"], sql.NullInt64);
 	        this.hasAlphaChannel = this.convertValues(source["hasAlphaChannel"], sql.NullBool);
+	        this.materialChannel = this.convertValues(source["materialChannel"], sql.NullString);
+	        this.analysisVersion = this.convertValues(source["analysisVersion"], sql.NullInt64);
 	        this.id = source["id"];
 	    }
 	
@@ -500,12 +1381,198 @@ export namespace database {
 
 export namespace scanner {
 	
+	export class PreviewAsset {
+	    id: number;
+	    fileName: string;
+	    currentGroupId: string;
+	
+	    static createFrom(source: any = {}) {
+	        return new PreviewAsset(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.id = source["id"];
+	        this.fileName = source["fileName"];
+	        this.currentGroupId = source["currentGroupId"];
+	    }
+	}
+	export class PreviewGroup {
+	    baseName: string;
+	    groupId: string;
+	    assets: PreviewAsset[];
+	
+	    static createFrom(source: any = {}) {
+	        return new PreviewGroup(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.baseName = source["baseName"];
+	        this.groupId = source["groupId"];
+	        this.assets = this.convertValues(source["assets"], PreviewAsset);
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class GroupingPreview {
+	    assetCount: number;
+	    groupsBefore: number;
+	    groupsAfter: number;
+	    changedGroups: number;
+	    groups: PreviewGroup[];
+	    truncated: boolean;
+	
+	    static createFrom(source: any = {}) {
+	        return new GroupingPreview(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.assetCount = source["assetCount"];
+	        this.groupsBefore = source["groupsBefore"];
+	        this.groupsAfter = source["groupsAfter"];
+	        this.changedGroups = source["changedGroups"];
+	        this.groups = this.convertValues(source["groups"], PreviewGroup);
+	        this.truncated = source["truncated"];
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	export class ModelMetadata {
+	    VertexCount: number;
+	    TriangleCount: number;
+	    MeshCount: number;
+	    MaterialCount: number;
+	    TextureCount: number;
+	    Textures: string[];
+	    Size: number[];
+	
+	    static createFrom(source: any = {}) {
+	        return new ModelMetadata(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.VertexCount = source["VertexCount"];
+	        this.TriangleCount = source["TriangleCount"];
+	        this.MeshCount = source["MeshCount"];
+	        this.MaterialCount = source["MaterialCount"];
+	        this.TextureCount = source["TextureCount"];
+	        this.Textures = source["Textures"];
+	        this.Size = source["Size"];
+	    }
+	}
+	export class WeightedColor {
+	    Hex: string;
+	    PaletteName: string;
+	    PaletteHex: string;
+	    Weight: number;
+	
+	    static createFrom(source: any = {}) {
+	        return new WeightedColor(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.Hex = source["Hex"];
+	        this.PaletteName = source["PaletteName"];
+	        this.PaletteHex = source["PaletteHex"];
+	        this.Weight = source["Weight"];
+	    }
+	}
+	export class ImageMetadata {
+	    Width: number;
+	    Height: number;
+	    HasAlphaChannel: boolean;
+	    BitDepth: number;
+	    DominantColor: string;
+	    PerceptualHash: string;
+	    ColorHistogram: number[];
+	    Colors: WeightedColor[];
+	    Properties: Record<string, string>;
+	    Model?: ModelMetadata;
+	
+	    static createFrom(source: any = {}) {
+	        return new ImageMetadata(source);
+	    }
+	
+	    constructor(source: any = {}) {
+	        if ('string' === typeof source) source = JSON.parse(source);
+	        this.Width = source["Width"];
+	        this.Height = source["Height"];
+	        this.HasAlphaChannel = source["HasAlphaChannel"];
+	        this.BitDepth = source["BitDepth"];
+	        this.DominantColor = source["DominantColor"];
+	        this.PerceptualHash = source["PerceptualHash"];
+	        this.ColorHistogram = source["ColorHistogram"];
+	        this.Colors = this.convertValues(source["Colors"], WeightedColor);
+	        this.Properties = source["Properties"];
+	        this.Model = this.convertValues(source["Model"], ModelMetadata);
+	    }
+	
+		convertValues(a: any, classs: any, asMap: boolean = false): any {
+		    if (!a) {
+		        return a;
+		    }
+		    if (a.slice && a.map) {
+		        return (a as any[]).map(elem => this.convertValues(elem, classs));
+		    } else if ("object" === typeof a) {
+		        if (asMap) {
+		            for (const key of Object.keys(a)) {
+		                a[key] = new classs(a[key]);
+		            }
+		            return a;
+		        }
+		        return new classs(a);
+		    }
+		    return a;
+		}
+	}
+	
+	
+	
 	export class ScanResult {
 	    Path: string;
 	    Err: any;
 	    NewAsset?: database.CreateAssetParams;
 	    ModifiedAsset?: database.UpdateAssetFromScanParams;
 	    ExistingPath: string;
+	    Metadata: ImageMetadata;
+	    Skipped: boolean;
+	    Refreshed: boolean;
 	
 	    static createFrom(source: any = {}) {
 	        return new ScanResult(source);
@@ -518,6 +1585,9 @@ export namespace scanner {
 	        this.NewAsset = this.convertValues(source["NewAsset"], database.CreateAssetParams);
 	        this.ModifiedAsset = this.convertValues(source["ModifiedAsset"], database.UpdateAssetFromScanParams);
 	        this.ExistingPath = source["ExistingPath"];
+	        this.Metadata = this.convertValues(source["Metadata"], ImageMetadata);
+	        this.Skipped = source["Skipped"];
+	        this.Refreshed = source["Refreshed"];
 	    }
 	
 		convertValues(a: any, classs: any, asMap: boolean = false): any {
@@ -13,24 +13,36 @@ export function Collector(arg1:context.Context,arg2:number,arg3:any):Promise<Rec
 
 export function GetConfig():Promise<scanner.ScannerConfigSnapshot>;
 
+export function GetGroupingRules():Promise<config.GroupingRuleSet>;
+
 export function GetPredefinedPalette():Promise<Array<config.PaletteColor>>;
 
 export function IsExtensionAllowed(arg1:string):Promise<boolean>;
 
 export function ListenToWatcher(arg1:any):Promise<void>;
 
+export function PreviewGrouping(arg1:number,arg2:config.GroupingRules):Promise<scanner.GroupingPreview>;
+
+export function RegroupAssets(arg1:number):Promise<number>;
+
 export function RemoveExtension(arg1:string):Promise<void>;
 
+export function ResetFolderGroupingRules(arg1:number):Promise<void>;
+
 export function ScanFile(arg1:context.Context,arg2:string):Promise<void>;
 
+export function SetGroupingRules(arg1:number,arg2:config.GroupingRules):Promise<void>;
+
 export function Shutdown():Promise<void>;
 
 export function StartScan():Promise<void>;
 
+export function StartScanFolders(arg1:Array<number>):Promise<void>;
+
 export function Startup(arg1:context.Context):Promise<void>;
 
 export function StopScan():Promise<void>;
 
 export function TryHeuristicMatch(arg1:context.Context,arg2:number,arg3:string):Promise<string|boolean>;
 
-export function Worker(arg1:context.Context,arg2:sync.WaitGroup,arg3:any,arg4:any):Promise<void>;
+export function Worker(arg1:context.Context,arg2:sync.WaitGroup,arg3:Record<string, scanner.CachedAsset>,arg4:any,arg5:any):Promise<void>;
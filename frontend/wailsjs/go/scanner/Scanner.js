@@ -18,6 +18,10 @@ export function GetConfig() {
   return window['go']['scanner']['Scanner']['GetConfig']();
 }
 
+export function GetGroupingRules() {
+  return window['go']['scanner']['Scanner']['GetGroupingRules']();
+}
+
 export function GetPredefinedPalette() {
   return window['go']['scanner']['Scanner']['GetPredefinedPalette']();
 }
@@ -30,14 +34,30 @@ export function ListenToWatcher(arg1) {
   return window['go']['scanner']['Scanner']['ListenToWatcher'](arg1);
 }
 
+export function PreviewGrouping(arg1, arg2) {
+  return window['go']['scanner']['Scanner']['PreviewGrouping'](arg1, arg2);
+}
+
+export function RegroupAssets(arg1) {
+  return window['go']['scanner']['Scanner']['RegroupAssets'](arg1);
+}
+
 export function RemoveExtension(arg1) {
   return window['go']['scanner']['Scanner']['RemoveExtension'](arg1);
 }
 
+export function ResetFolderGroupingRules(arg1) {
+  return window['go']['scanner']['Scanner']['ResetFolderGroupingRules'](arg1);
+}
+
 export function ScanFile(arg1, arg2) {
   return window['go']['scanner']['Scanner']['ScanFile'](arg1, arg2);
 }
 
+export function SetGroupingRules(arg1, arg2) {
+  return window['go']['scanner']['Scanner']['SetGroupingRules'](arg1, arg2);
+}
+
 export function Shutdown() {
   return window['go']['scanner']['Scanner']['Shutdown']();
 }
@@ -46,6 +66,10 @@ export function StartScan() {
   return window['go']['scanner']['Scanner']['StartScan']();
 }
 
+export function StartScanFolders(arg1) {
+  return window['go']['scanner']['Scanner']['StartScanFolders'](arg1);
+}
+
 export function Startup(arg1) {
   return window['go']['scanner']['Scanner']['Startup'](arg1);
 }
@@ -58,6 +82,6 @@ export function TryHeuristicMatch(arg1, arg2, arg3) {
   return window['go']['scanner']['Scanner']['TryHeuristicMatch'](arg1, arg2, arg3);
 }
 
-export function Worker(arg1, arg2, arg3, arg4) {
-  return window['go']['scanner']['Scanner']['Worker'](arg1, arg2, arg3, arg4);
+export function Worker(arg1, arg2, arg3, arg4, arg5) {
+  return window['go']['scanner']['Scanner']['Worker'](arg1, arg2, arg3, arg4, arg5);
 }
@@ -1,6 +1,7 @@
 // Cynhyrchwyd y ffeil hon yn awtomatig. PEIDIWCH Â MODIWL
 // This file is automatically generated. DO NOT EDIT
 import {settings} from '../models';
+import {config} from '../models';
 import {context} from '../models';
 
 export function AddFolder(arg1:string):Promise<settings.ScanFolderDTO>;
@@ -11,6 +12,10 @@ export function GetConfig():Promise<settings.AppConfigDTO>;
 
 export function GetFolders():Promise<Array<settings.ScanFolderDTO>>;
 
+export function GetMaterialChannelSynonyms():Promise<Array<config.MaterialChannel>>;
+
+export function GetThumbnailProviders():Promise<Array<config.ThumbnailProvider>>;
+
 export function OpenFile(arg1:string):Promise<void>;
 
 export function OpenFolderPicker():Promise<string>;
@@ -21,6 +26,10 @@ export function SetAllowedExtensions(arg1:Array<string>):Promise<void>;
 
 export function SetDebugMode(arg1:boolean):Promise<void>;
 
+export function SetMaterialChannelSynonyms(arg1:Array<config.MaterialChannel>):Promise<void>;
+
+export function SetThumbnailProviders(arg1:Array<config.ThumbnailProvider>):Promise<void>;
+
 export function Startup(arg1:context.Context):Promise<void>;
 
 export function UpdateFolderStatus(arg1:number,arg2:boolean):Promise<settings.ScanFolderDTO>;
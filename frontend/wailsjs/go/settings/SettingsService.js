@@ -18,6 +18,14 @@ export function GetFolders() {
   return window['go']['settings']['SettingsService']['GetFolders']();
 }
 
+export function GetMaterialChannelSynonyms() {
+  return window['go']['settings']['SettingsService']['GetMaterialChannelSynonyms']();
+}
+
+export function GetThumbnailProviders() {
+  return window['go']['settings']['SettingsService']['GetThumbnailProviders']();
+}
+
 export function OpenFile(arg1) {
   return window['go']['settings']['SettingsService']['OpenFile'](arg1);
 }
@@ -38,6 +46,14 @@ export function SetDebugMode(arg1) {
   return window['go']['settings']['SettingsService']['SetDebugMode'](arg1);
 }
 
+export function SetMaterialChannelSynonyms(arg1) {
+  return window['go']['settings']['SettingsService']['SetMaterialChannelSynonyms'](arg1);
+}
+
+export function SetThumbnailProviders(arg1) {
+  return window['go']['settings']['SettingsService']['SetThumbnailProviders'](arg1);
+}
+
 export function Startup(arg1) {
   return window['go']['settings']['SettingsService']['Startup'](arg1);
 }
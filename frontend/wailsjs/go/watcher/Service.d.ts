@@ -6,6 +6,8 @@ export function Shutdown():Promise<void>;
 
 export function Startup(arg1:context.Context):Promise<void>;
 
+export function Suppress(arg1:Array<string>):Promise<any>;
+
 export function Unwatch(arg1:string):Promise<void>;
 
 export function Watch(arg1:string):Promise<void>;
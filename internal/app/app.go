@@ -26,6 +26,8 @@ type App struct {
 	AssetService       *AssetService
 	MaterialSetService *MaterialSetService
 	TagService         *TagService
+	SavedSearchService *SavedSearchService
+	PaletteService     *PaletteService
 	Scanner            *scanner.Scanner
 	SettingsService    *settings.SettingsService
 	Watcher            *watcher.Service
@@ -33,13 +35,15 @@ type App struct {
 }
 
 // NewApp creates a new App application struct with injected dependencies.
-func NewApp(db database.Querier, logger *slog.Logger, assetService *AssetService, materialSetService *MaterialSetService, tagService *TagService, scanner *scanner.Scanner, settingsService *settings.SettingsService, watcher *watcher.Service, updateService *update.UpdateService) *App {
+func NewApp(db database.Querier, logger *slog.Logger, assetService *AssetService, materialSetService *MaterialSetService, tagService *TagService, savedSearchService *SavedSearchService, paletteService *PaletteService, scanner *scanner.Scanner, settingsService *settings.SettingsService, watcher *watcher.Service, updateService *update.UpdateService) *App {
 	return &App{
 		db:                 db,
 		logger:             logger,
 		AssetService:       assetService,
 		MaterialSetService: materialSetService,
 		TagService:         tagService,
+		SavedSearchService: savedSearchService,
+		PaletteService:     paletteService,
 		Scanner:            scanner,
 		SettingsService:    settingsService,
 		Watcher:            watcher,
@@ -54,6 +58,8 @@ func (a *App) OnStartup(ctx context.Context) {
 	a.AssetService.Startup(ctx)
 	a.MaterialSetService.Startup(ctx)
 	a.TagService.Startup(ctx)
+	a.SavedSearchService.Startup(ctx)
+	a.PaletteService.Startup(ctx)
 	a.Scanner.Startup(ctx)
 	a.SettingsService.Startup(ctx)
 	a.Watcher.Startup(ctx)
@@ -3,8 +3,10 @@ package app
 import (
 	"context"
 	"database/sql"
+	"eclat/internal/config"
 	"eclat/internal/database"
 	"eclat/internal/feedback"
+	"eclat/internal/trash"
 	"encoding/base64"
 	"errors"
 	"fmt"
@@ -25,15 +27,23 @@ type AssetService struct {
 	logger        *slog.Logger
 	notifier      feedback.Notifier
 	thumbnailsDir string
+	bin           *trash.Bin
+	journal       *Journal
+	config        *config.ScannerConfig
+	watcher       WatchSuppressor
 }
 
-func NewAssetService(db database.Querier, sysDB *sql.DB, logger *slog.Logger, notifier feedback.Notifier, thumbnailsDir string) *AssetService {
+func NewAssetService(db database.Querier, sysDB *sql.DB, logger *slog.Logger, notifier feedback.Notifier, thumbnailsDir string, bin *trash.Bin, journal *Journal, cfg *config.ScannerConfig, watcher WatchSuppressor) *AssetService {
 	return &AssetService{
 		db:            db,
 		sysDB:         sysDB,
 		logger:        logger,
 		notifier:      notifier,
 		thumbnailsDir: thumbnailsDir,
+		bin:           bin,
+		journal:       journal,
+		config:        cfg,
+		watcher:       watcher,
 	}
 }
 
@@ -48,6 +58,27 @@ func (s *AssetService) Startup(ctx context.Context) {
 	}()
 }
 
+// ctxOrBackground returns the Wails context, or a background one before Startup (e.g. in tests).
+func (s *AssetService) ctxOrBackground() context.Context {
+	if s.ctx != nil {
+		return s.ctx
+	}
+	return context.Background()
+}
+
+// inTx runs fn against queries bound to a single transaction, committing only if fn succeeds.
+func inTx(ctx context.Context, sysDB *sql.DB, fn func(qtx *database.Queries) error) error {
+	tx, err := sysDB.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+	if err := fn(database.New(sysDB).WithTx(tx)); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
 // MigrateThumbnailPaths konwertuje bezwzględne ścieżki systemowe na ścieżki relatywne /thumbnails/
 func (s *AssetService) MigrateThumbnailPaths() error {
 	ctx := s.ctx
@@ -158,6 +189,12 @@ type AssetDetails struct {
 	Tags          []string           `json:"tags"`
 	MaterialSets  []AssetMaterialSet `json:"materialSets"`
 	DominantColor string             `json:"dominantColor"`
+	Colors        []AssetColor       `json:"colors"` // Main colors, most dominant first
+	// Properties are format specific details (e.g. PSD color mode and layer count), keyed by name.
+	Properties map[string]string `json:"properties"`
+	Model      *AssetModel       `json:"model"` // Geometry of glTF/GLB and OBJ files, nil for other assets
+	// MaterialChannel is the PBR map role of a texture (e.g. "normal"), empty for other files.
+	MaterialChannel string `json:"materialChannel"`
 }
 type AssetSibling struct {
 	ID       int64  `json:"id"`
@@ -165,6 +202,9 @@ type AssetSibling struct {
 	FileName string `json:"fileName"`
 }
 
+// MaxFileSizeMB is the top of the fileSizeRange slider; a max at or above it leaves the range open.
+const MaxFileSizeMB = 4096
+
 // AssetQueryFilters - Filtry z Frontendu
 type AssetQueryFilters struct {
 	Page         int      `json:"page"`
@@ -173,12 +213,16 @@ type AssetQueryFilters struct {
 	Tags         []string `json:"tags"`
 	MatchAllTags bool     `json:"matchAllTags"`
 	FileTypes    []string `json:"fileTypes"`
-	Colors       []string `json:"colors"`
+	Colors       []string `json:"colors"` // Palette hexes; an asset matches if any of them is a significant color
+
+	ColorWeightThreshold float64            `json:"colorWeightThreshold"` // Minimal color share 0..1, 0 = DefaultColorWeightThreshold
+	ColorSearch          *ColorSearchFilter `json:"colorSearch"`          // Arbitrary hex with a Lab tolerance
 
-	RatingRange   []int `json:"ratingRange"`   // [min, max]
-	WidthRange    []int `json:"widthRange"`    // [min, max]
-	HeightRange   []int `json:"heightRange"`   // [min, max]
-	FileSizeRange []int `json:"fileSizeRange"` // [min, max] MB
+	RatingRange    []int `json:"ratingRange"`    // [min, max]
+	WidthRange     []int `json:"widthRange"`     // [min, max]
+	HeightRange    []int `json:"heightRange"`    // [min, max]
+	FileSizeRange  []int `json:"fileSizeRange"`  // [min, max] MB
+	PolyCountRange []int `json:"polyCountRange"` // [min, max] triangles, models only; 0 leaves a bound open
 
 	DateRange struct {
 		From *string `json:"from"`
@@ -186,6 +230,7 @@ type AssetQueryFilters struct {
 	} `json:"dateRange"`
 
 	HasAlpha          *bool  `json:"hasAlpha"`
+	HasTextures       *bool  `json:"hasTextures"` // Models referencing or embedding textures
 	OnlyFavorites     bool   `json:"onlyFavorites"`
 	OnlyUncategorized bool   `json:"onlyUncategorized"`
 	IsDeleted         bool   `json:"isDeleted"`
@@ -242,151 +287,35 @@ func (s *AssetService) GetAssets(filters AssetQueryFilters) (*PagedAssetResult,
 	psql := sq.StatementBuilder.PlaceholderFormat(sq.Question)
 
 	// Bazowe zapytanie
-	lastModCol := "a.last_modified"
-	if filters.ShowRepresentativesOnly {
-		lastModCol = "MAX(a.last_modified) AS last_modified"
-	}
-
-	base := psql.Select(
+	columns := []string{
 		"a.id", "a.file_name", "a.file_path", "a.file_type", "a.file_size",
-		"a.thumbnail_path", "a.date_added", lastModCol,
+		"a.thumbnail_path", "a.date_added", "a.last_modified",
 		"a.image_width", "a.image_height", "a.dominant_color",
 		"a.rating", "a.is_favorite", "a.is_deleted", "a.is_hidden", "a.group_id",
 		"a.has_alpha_channel", "a.bit_depth", "a.file_hash", "a.description",
-	).From("assets a")
-
-	// Jeśli nie szukamy w koszu, filtrujemy po folderach
-	if !filters.IsDeleted {
-		base = base.Join("scan_folders f ON a.scan_folder_id = f.id").
-			Where(sq.Eq{"f.is_deleted": 0}).
-			Where(sq.Eq{"f.is_active": 1})
-	}
-
-	// Filtrowanie Podstawowe
-	base = base.Where(sq.Eq{"a.is_deleted": filters.IsDeleted})
-	if !filters.IsDeleted {
-		base = base.Where(sq.Eq{"a.is_hidden": filters.IsHidden})
-	}
-
-	if filters.OnlyFavorites {
-		base = base.Where(sq.Eq{"a.is_favorite": 1})
-	}
-
-	// Filtrowanie po Kolekcji
-	if filters.CollectionID != nil {
-		base = base.Join("asset_material_sets msa ON a.id = msa.asset_id").
-			Where(sq.Eq{"msa.material_set_id": *filters.CollectionID})
-	}
-
-	// Wyszukiwanie Tekstowe
-	if filters.Query != "" {
-		like := "%" + filters.Query + "%"
-		base = base.Where(sq.Or{
-			sq.Like{"a.file_name": like},
-			sq.Like{"a.file_path": like},
-		})
-	}
-
-	// === ZAKRESY (Poprawione Gte -> GtOrEq, Lte -> LtOrEq) ===
-
-	// Rating
-	if len(filters.RatingRange) == 2 {
-		if filters.RatingRange[0] > 0 {
-			base = base.Where(sq.GtOrEq{"a.rating": filters.RatingRange[0]})
-		}
-		if filters.RatingRange[1] < 5 {
-			base = base.Where(sq.LtOrEq{"a.rating": filters.RatingRange[1]})
-		}
-	}
-
-	// File Size (MB -> Bytes)
-	if len(filters.FileSizeRange) == 2 {
-		if filters.FileSizeRange[0] > 0 {
-			minBytes := int64(filters.FileSizeRange[0]) * 1024 * 1024
-			base = base.Where(sq.GtOrEq{"a.file_size": minBytes})
-		}
-		if filters.FileSizeRange[1] < 4096 { // 4GB max in UI usually
-			maxBytes := int64(filters.FileSizeRange[1]) * 1024 * 1024
-			base = base.Where(sq.LtOrEq{"a.file_size": maxBytes})
-		}
-	}
-
-	// Dimensions - FIXED: Only apply if min > 0 OR max < DEFAULT_MAX.
-	// This ensures that NULL values (models) are NOT filtered out when range is 0-MAX.
-	const defaultMaxDim = 8160 // Matches frontend UI_CONFIG.GALLERY.FilterOptions.MAX_DIMENSION
-	if len(filters.WidthRange) == 2 {
-		if filters.WidthRange[0] > 0 {
-			base = base.Where(sq.GtOrEq{"a.image_width": filters.WidthRange[0]})
-		}
-		if filters.WidthRange[1] > 0 && filters.WidthRange[1] < defaultMaxDim {
-			base = base.Where(sq.LtOrEq{"a.image_width": filters.WidthRange[1]})
-		}
-	}
-
-	if len(filters.HeightRange) == 2 {
-		if filters.HeightRange[0] > 0 {
-			base = base.Where(sq.GtOrEq{"a.image_height": filters.HeightRange[0]})
-		}
-		if filters.HeightRange[1] > 0 && filters.HeightRange[1] < defaultMaxDim {
-			base = base.Where(sq.LtOrEq{"a.image_height": filters.HeightRange[1]})
-		}
-	}
-
-	// Daty
-	if filters.DateRange.From != nil && *filters.DateRange.From != "" {
-		base = base.Where(sq.GtOrEq{"a.date_added": *filters.DateRange.From})
-	}
-	if filters.DateRange.To != nil && *filters.DateRange.To != "" {
-		base = base.Where(sq.LtOrEq{"a.date_added": *filters.DateRange.To})
 	}
+	base := psql.Select(columns...).From("assets a")
 
-	// Typy plików
-	if len(filters.FileTypes) > 0 {
-		base = base.Where(sq.Eq{"a.file_type": filters.FileTypes})
-	}
-
-	// Kolory
-	if len(filters.Colors) > 0 {
-		base = base.Where(sq.Eq{"a.dominant_color": filters.Colors})
-	}
-
-	// Alpha Channel
-	if filters.HasAlpha != nil {
-		val := 0
-		if *filters.HasAlpha {
-			val = 1
-		}
-		base = base.Where(sq.Eq{"a.has_alpha_channel": val})
+	if filters.ShowRepresentativesOnly {
+		base = base.LeftJoin("asset_groups ag ON ag.group_id = a.group_id")
 	}
 
-	// Tagi (Subquery)
-	if len(filters.Tags) > 0 {
-		tagSubQ := sq.Select("at.asset_id").
-			From("asset_tags at").
-			Join("tags t ON at.tag_id = t.id").
-			Where(sq.Eq{"t.name": filters.Tags})
-
-		if filters.MatchAllTags {
-			tagSubQ = tagSubQ.GroupBy("at.asset_id").
-				Having(sq.Eq{"COUNT(DISTINCT t.id)": len(filters.Tags)})
-		}
-
-		// Squirrel wymaga ręcznego SQL dla podzapytania w IN
-		subSql, subArgs, err := tagSubQ.ToSql()
-		if err != nil {
-			return nil, fmt.Errorf("failed to build tag subquery: %w", err)
-		}
-		base = base.Where(fmt.Sprintf("a.id IN (%s)", subSql), subArgs...)
-	} else if filters.OnlyUncategorized {
-		// Assets with NO tags
-		base = base.Where("NOT EXISTS (SELECT 1 FROM asset_tags at WHERE at.asset_id = a.id)")
+	base, hasRelevance, err := s.applyAssetFilters(ctx, base, &filters)
+	if err != nil {
+		return nil, err
 	}
+	relevanceCol := "fts.fts_rank"
 
 	// === GROUPING (REPRESENTATIVES) ===
 	if filters.ShowRepresentativesOnly {
-		// In SQLite, GROUP BY with an aggregate like MAX() returns the row matching that aggregate.
-		// We use MAX(last_modified) in the Select list to pick the most recently updated file as the group representative.
-		base = base.GroupBy("a.group_id")
+		// Each group shows one of its matching assets: the pinned representative (asset_groups)
+		// if it matches, otherwise the most recently modified one.
+		base = base.Column("ROW_NUMBER() OVER (PARTITION BY a.group_id ORDER BY a.id = ag.representative_id DESC, a.last_modified DESC, a.id DESC) AS group_rank")
+		if hasRelevance {
+			base = base.Column("fts.fts_rank")
+			relevanceCol = "a.fts_rank"
+		}
+		base = psql.Select(columns...).FromSelect(base, "a").Where("a.group_rank = 1")
 	}
 
 	// ==========================================
@@ -398,26 +327,22 @@ func (s *AssetService) GetAssets(filters AssetQueryFilters) (*PagedAssetResult,
 		return nil, fmt.Errorf("failed to build base sql: %w", err)
 	}
 
-	// Better way to count: if grouping, count distinct group_ids.
-	// We need to extract the FROM/WHERE part but carefully.
-	fromIndex := strings.Index(strings.ToUpper(sqlBase), "FROM")
-	if fromIndex == -1 {
-		return nil, fmt.Errorf("invalid sql generated (no FROM clause)")
-	}
-	
-	// Remove ORDER BY and LIMIT from the count query if they exist
-	countPart := sqlBase[fromIndex:]
-	if orderIdx := strings.Index(strings.ToUpper(countPart), "ORDER BY"); orderIdx != -1 {
-		countPart = countPart[:orderIdx]
-	}
-	if groupIdx := strings.Index(strings.ToUpper(countPart), "GROUP BY"); groupIdx != -1 {
-		countPart = countPart[:groupIdx]
-	}
-
 	var sqlCount string
 	if filters.ShowRepresentativesOnly {
-		sqlCount = "SELECT COUNT(DISTINCT a.group_id) " + countPart
+		// One row per group already; the window function inside rules out cutting the SQL apart.
+		sqlCount = "SELECT COUNT(*) FROM (" + sqlBase + ")"
 	} else {
+		// We need to extract the FROM/WHERE part but carefully.
+		fromIndex := strings.Index(strings.ToUpper(sqlBase), "FROM")
+		if fromIndex == -1 {
+			return nil, fmt.Errorf("invalid sql generated (no FROM clause)")
+		}
+
+		// Remove ORDER BY and LIMIT from the count query if they exist
+		countPart := sqlBase[fromIndex:]
+		if orderIdx := strings.Index(strings.ToUpper(countPart), "ORDER BY"); orderIdx != -1 {
+			countPart = countPart[:orderIdx]
+		}
 		sqlCount = "SELECT COUNT(*) " + countPart
 	}
 
@@ -444,12 +369,24 @@ func (s *AssetService) GetAssets(filters AssetQueryFilters) (*PagedAssetResult,
 		sortCol = "a.rating"
 	case "dateadded":
 		sortCol = "a.date_added"
+	case "relevance":
+		if hasRelevance {
+			sortCol = relevanceCol
+		}
 	}
 
 	sortDir := "DESC"
 	if !filters.SortDesc {
 		sortDir = "ASC"
 	}
+	if sortCol == relevanceCol {
+		// bm25() returns lower values for better matches, so "descending relevance" is ASC.
+		if filters.SortDesc {
+			sortDir = "ASC"
+		} else {
+			sortDir = "DESC"
+		}
+	}
 	base = base.OrderBy(fmt.Sprintf("%s %s", sortCol, sortDir))
 
 	offset := (filters.Page - 1) * filters.PageSize
@@ -588,6 +525,192 @@ func (s *AssetService) GetAssets(filters AssetQueryFilters) (*PagedAssetResult,
 	}, nil
 }
 
+// applyAssetFilters dodaje do zapytania po "assets a" wszystkie filtry galerii (bez grupowania,
+// sortowania i paginacji). Zwraca też, czy zapytanie ma ranking trafności FTS (fts.fts_rank).
+func (s *AssetService) applyAssetFilters(ctx context.Context, base sq.SelectBuilder, filters *AssetQueryFilters) (sq.SelectBuilder, bool, error) {
+	// Język zapytań w polu wyszukiwania (type:, tag:, rating>= ...) - reszta tekstu trafia do FTS
+	parsedQuery, err := parseSearchQuery(filters.Query, time.Now(), activeColorPalette(ctx, s.db))
+	if err != nil {
+		return base, false, err
+	}
+	queryConditions := parsedQuery.Apply(filters)
+
+	// Jeśli nie szukamy w koszu, filtrujemy po folderach
+	if !filters.IsDeleted {
+		base = base.Join("scan_folders f ON a.scan_folder_id = f.id").
+			Where(sq.Eq{"f.is_deleted": 0}).
+			Where(sq.Eq{"f.is_active": 1})
+	}
+
+	// Filtrowanie Podstawowe
+	base = base.Where(sq.Eq{"a.is_deleted": filters.IsDeleted})
+	if !filters.IsDeleted {
+		base = base.Where(sq.Eq{"a.is_hidden": filters.IsHidden})
+	}
+
+	if filters.OnlyFavorites {
+		base = base.Where(sq.Eq{"a.is_favorite": 1})
+	}
+
+	// Filtrowanie po Kolekcji
+	if filters.CollectionID != nil {
+		base = base.Join("asset_material_sets msa ON a.id = msa.asset_id").
+			Where(sq.Eq{"msa.material_set_id": *filters.CollectionID})
+	}
+
+	// Wyszukiwanie Tekstowe (FTS5: nazwa, ścieżka, opis, tagi)
+	hasRelevance := false
+	if filters.Query != "" {
+		if match := buildFTSMatchExpression(filters.Query); match != "" {
+			base = base.Join(
+				"(SELECT rowid AS fts_id, bm25(assets_fts, "+ftsColumnWeights+") AS fts_rank FROM assets_fts WHERE assets_fts MATCH ?) fts ON fts.fts_id = a.id",
+				match,
+			)
+			hasRelevance = true
+		} else {
+			// Nothing tokenizable (e.g. only punctuation) - fall back to a plain substring match.
+			like := "%" + filters.Query + "%"
+			base = base.Where(sq.Or{
+				sq.Like{"a.file_name": like},
+				sq.Like{"a.file_path": like},
+			})
+		}
+	}
+
+	if len(queryConditions) > 0 {
+		base = base.Where(queryConditions)
+	}
+
+	// === ZAKRESY (Poprawione Gte -> GtOrEq, Lte -> LtOrEq) ===
+
+	// Rating
+	if len(filters.RatingRange) == 2 {
+		if filters.RatingRange[0] > 0 {
+			base = base.Where(sq.GtOrEq{"a.rating": filters.RatingRange[0]})
+		}
+		if filters.RatingRange[1] < 5 {
+			base = base.Where(sq.LtOrEq{"a.rating": filters.RatingRange[1]})
+		}
+	}
+
+	// File Size (MB -> Bytes)
+	if len(filters.FileSizeRange) == 2 {
+		if filters.FileSizeRange[0] > 0 {
+			minBytes := int64(filters.FileSizeRange[0]) * 1024 * 1024
+			base = base.Where(sq.GtOrEq{"a.file_size": minBytes})
+		}
+		if filters.FileSizeRange[1] < MaxFileSizeMB {
+			maxBytes := int64(filters.FileSizeRange[1]) * 1024 * 1024
+			base = base.Where(sq.LtOrEq{"a.file_size": maxBytes})
+		}
+	}
+
+	// Dimensions - FIXED: Only apply if min > 0 OR max < DEFAULT_MAX.
+	// This ensures that NULL values (models) are NOT filtered out when range is 0-MAX.
+	const defaultMaxDim = 8160 // Matches frontend UI_CONFIG.GALLERY.FilterOptions.MAX_DIMENSION
+	if len(filters.WidthRange) == 2 {
+		if filters.WidthRange[0] > 0 {
+			base = base.Where(sq.GtOrEq{"a.image_width": filters.WidthRange[0]})
+		}
+		if filters.WidthRange[1] > 0 && filters.WidthRange[1] < defaultMaxDim {
+			base = base.Where(sq.LtOrEq{"a.image_width": filters.WidthRange[1]})
+		}
+	}
+
+	if len(filters.HeightRange) == 2 {
+		if filters.HeightRange[0] > 0 {
+			base = base.Where(sq.GtOrEq{"a.image_height": filters.HeightRange[0]})
+		}
+		if filters.HeightRange[1] > 0 && filters.HeightRange[1] < defaultMaxDim {
+			base = base.Where(sq.LtOrEq{"a.image_height": filters.HeightRange[1]})
+		}
+	}
+
+	// Daty
+	if filters.DateRange.From != nil && *filters.DateRange.From != "" {
+		base = base.Where(sq.GtOrEq{"a.date_added": *filters.DateRange.From})
+	}
+	if filters.DateRange.To != nil && *filters.DateRange.To != "" {
+		base = base.Where(sq.LtOrEq{"a.date_added": *filters.DateRange.To})
+	}
+
+	// Typy plików
+	if len(filters.FileTypes) > 0 {
+		base = base.Where(sq.Eq{"a.file_type": filters.FileTypes})
+	}
+
+	// Kolory
+	if len(filters.Colors) > 0 {
+		base = base.Where(paletteColorCondition(filters.Colors, colorWeightThreshold(filters)))
+	}
+	if filters.ColorSearch != nil && filters.ColorSearch.Hex != "" {
+		cond, err := hexColorCondition(filters.ColorSearch.Hex, filters.ColorSearch.Tolerance, colorWeightThreshold(filters))
+		if err != nil {
+			return base, false, err
+		}
+		base = base.Where(cond)
+	}
+
+	// Alpha Channel
+	if filters.HasAlpha != nil {
+		val := 0
+		if *filters.HasAlpha {
+			val = 1
+		}
+		base = base.Where(sq.Eq{"a.has_alpha_channel": val})
+	}
+
+	// Modele 3D (liczba trójkątów, tekstury)
+	if cond := modelCondition(filters.PolyCountRange, filters.HasTextures); cond != nil {
+		base = base.Where(cond)
+	}
+
+	// Tagi (Subquery)
+	if len(filters.Tags) > 0 {
+		tagSubQ := sq.Select("at.asset_id").
+			From("asset_tags at").
+			Join("tags t ON at.tag_id = t.id").
+			Where(sq.Eq{"t.name": filters.Tags})
+
+		if filters.MatchAllTags {
+			tagSubQ = tagSubQ.GroupBy("at.asset_id").
+				Having(sq.Eq{"COUNT(DISTINCT t.id)": len(filters.Tags)})
+		}
+
+		// Squirrel wymaga ręcznego SQL dla podzapytania w IN
+		subSql, subArgs, err := tagSubQ.ToSql()
+		if err != nil {
+			return base, false, fmt.Errorf("failed to build tag subquery: %w", err)
+		}
+		base = base.Where(fmt.Sprintf("a.id IN (%s)", subSql), subArgs...)
+	} else if filters.OnlyUncategorized {
+		// Assets with NO tags
+		base = base.Where("NOT EXISTS (SELECT 1 FROM asset_tags at WHERE at.asset_id = a.id)")
+	}
+
+	return base, hasRelevance, nil
+}
+
+// GetAssetsBySavedSearch runs a saved search (smart collection) against the current library state.
+// The stored filters are migrated to the current AssetQueryFilters shape before execution.
+func (s *AssetService) GetAssetsBySavedSearch(id int64, page int, pageSize int) (*PagedAssetResult, error) {
+	ctx := s.ctxOrBackground()
+
+	saved, err := s.db.GetSavedSearchById(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load saved search %d: %w", id, err)
+	}
+
+	filters, _, err := decodeSavedSearchFilters(saved.FilterJson)
+	if err != nil {
+		return nil, err
+	}
+
+	filters.Page = page
+	filters.PageSize = pageSize
+	return s.GetAssets(filters)
+}
+
 // GetAssetById pobiera pojedynczy asset wraz z jego tagami i groupID.
 func (s *AssetService) GetAssetById(id int64) (*AssetDetails, error) {
 	ctx := s.ctx
@@ -651,6 +774,11 @@ func (s *AssetService) GetAssetById(id int64) (*AssetDetails, error) {
 		GroupID:       &asset.GroupID,
 		Tags:          tagNames,
 		DominantColor: asset.DominantColor.String,
+		Colors:        s.getAssetColors(ctx, asset.ID),
+		Properties:    s.getAssetProperties(ctx, asset.ID),
+		Model:         s.getAssetModel(ctx, asset.ID),
+
+		MaterialChannel: asset.MaterialChannel,
 
 		// New fields
 		BitDepth:     asset.BitDepth.Int64,
@@ -666,6 +794,20 @@ func (s *AssetService) GetAssetById(id int64) (*AssetDetails, error) {
 	return details, nil
 }
 
+// getAssetProperties loads the format specific properties of an asset.
+func (s *AssetService) getAssetProperties(ctx context.Context, assetID int64) map[string]string {
+	rows, err := s.db.ListAssetProperties(ctx, assetID)
+	if err != nil {
+		s.logger.Error("Failed to fetch asset properties", "id", assetID, "error", err)
+		return nil
+	}
+	props := make(map[string]string, len(rows))
+	for _, r := range rows {
+		props[r.Key] = r.Value
+	}
+	return props
+}
+
 // GetLibraryStats zwraca ogólne statystyki (liczba plików, rozmiar).
 func (s *AssetService) GetLibraryStats() (*LibraryStats, error) {
 	ctx := s.ctx
@@ -794,6 +936,9 @@ func (s *AssetService) UpdateAssetMetadata(id int64, req UpdateAssetRequest) (*A
 	if err != nil {
 		return nil, err
 	}
+	if req.Description != nil {
+		s.reindexAssetSearch(s.ctx, s.db, updatedAsset.ID)
+	}
 
 	return s.GetAssetById(updatedAsset.ID)
 }
@@ -828,23 +973,12 @@ func (s *AssetService) SetAssetRating(id int64, rating int64) error {
 	if rating < 0 || rating > 5 {
 		return errors.New("rating must be between 0 and 5")
 	}
-	return s.db.SetAssetRating(s.ctx, database.SetAssetRatingParams{
-		Rating: rating,
-		ID:     id,
-	})
+	return s.journal.execute(s.ctxOrBackground(), &ratingOp{AssetID: id, After: rating})
 }
 
-// SoftDeleteAssets przenosi assety do kosza.
+// SoftDeleteAssets przenosi assety do kosza (operacja trafia do historii cofania).
 func (s *AssetService) SoftDeleteAssets(ids []int64) error {
-	const batchSize = 500
-	for i := 0; i < len(ids); i += batchSize {
-		end := min(i+batchSize, len(ids))
-		err := s.db.SoftDeleteAssets(s.ctx, ids[i:end])
-		if err != nil {
-			return err
-		}
-	}
-	return nil
+	return s.journal.execute(s.ctxOrBackground(), &softDeleteOp{AssetIDs: ids})
 }
 
 // RestoreAssets przywraca assety z kosza.
@@ -860,33 +994,11 @@ func (s *AssetService) RestoreAssets(ids []int64) error {
 	return nil
 }
 
-// DeleteAssetsPermanently usuwa assety z bazy na zawsze.
+// DeleteAssetsPermanently przenosi pliki assetów do kosza i usuwa je z biblioteki.
+// RestoreDeletedAsset potrafi je przywrócić.
 func (s *AssetService) DeleteAssetsPermanently(ids []int64) error {
 	for _, id := range ids {
-		// 1. Pobierz dane assetu, aby poznać ścieżkę pliku
-		asset, err := s.db.GetAssetById(s.ctx, id)
-		if err != nil {
-			s.logger.Error("Failed to get asset for deletion", "id", id, "error", err)
-			return fmt.Errorf("failed to retrieve asset %d: %w", id, err)
-		}
-
-		// 2. Usuń plik z dysku
-		if err := os.Remove(asset.FilePath); err != nil && !os.IsNotExist(err) {
-			s.logger.Error("Failed to delete file from disk", "path", asset.FilePath, "error", err)
-			return fmt.Errorf("failed to delete file %s: %w", asset.FilePath, err)
-		}
-
-		// 3. Usuń miniaturkę, jeśli istnieje i jest wygenerowana (nie jest placeholderem)
-		if asset.ThumbnailPath != "" && strings.HasPrefix(asset.ThumbnailPath, "/thumbnails/") {
-			thumbName := filepath.Base(asset.ThumbnailPath)
-			thumbFullPath := filepath.Join(s.thumbnailsDir, thumbName)
-			if err := os.Remove(thumbFullPath); err != nil && !os.IsNotExist(err) {
-				s.logger.Warn("Failed to delete thumbnail", "path", thumbFullPath, "error", err)
-			}
-		}
-
-		// 4. Usuń wpis z bazy danych
-		if err := s.db.DeleteAssetPermanent(s.ctx, id); err != nil {
+		if err := s.deleteAssetPermanently(id); err != nil {
 			return err
 		}
 	}
@@ -900,7 +1012,7 @@ func (s *AssetService) RenameAsset(id int64, newName string) error {
 	if newName == "" {
 		return errors.New("new name cannot be empty")
 	}
-	if strings.ContainsAny(newName, `/\:*?"<>|`) {
+	if strings.ContainsAny(newName, reservedFileNameChars) {
 		return errors.New("invalid characters in filename")
 	}
 
@@ -934,33 +1046,21 @@ func (s *AssetService) RenameAsset(id int64, newName string) error {
 		return errors.New("file with this name already exists")
 	}
 
-	// 5. Zmień nazwę pliku na dysku
-	if err := os.Rename(asset.FilePath, newPath); err != nil {
-		return fmt.Errorf("failed to rename file on disk: %w", err)
-	}
-
-	// 6. Zaktualizuj bazę danych
+	// 5. Zmień nazwę pliku na dysku i w bazie danych jako operację z historii cofania.
+	// Jeśli zapis w bazie się nie uda, plik wraca do starej nazwy.
 	// Nie zmieniamy miniatury, bo jej nazwa jest generowana dynamicznie/haszowana i nie zależy od nazwy pliku (według instrukcji).
-
-	params := database.RenameAssetParams{
-		FileName: newName,
-		FilePath: newPath,
-		ID:       id,
-	}
-
-	_, err = s.db.RenameAsset(s.ctx, params)
-	if err != nil {
-		// Rollback rename on disk?
-		// _ = os.Rename(newPath, asset.FilePath)
-		return fmt.Errorf("failed to update asset in db: %w", err)
-	}
-
-	return nil
+	return s.journal.execute(s.ctxOrBackground(), &renameOp{
+		AssetID:  id,
+		FromName: asset.FileName,
+		FromPath: asset.FilePath,
+		ToName:   newName,
+		ToPath:   newPath,
+	})
 }
 
 // GetAvailableColors zwraca listę wszystkich unikalnych kolorów dominujących z bazy danych.
 func (s *AssetService) GetAvailableColors() ([]string, error) {
-	nullColors, err := s.db.GetAllColors(s.ctx)
+	nullColors, err := s.db.GetAllColors(s.ctx, DefaultColorWeightThreshold)
 	if err != nil {
 		return nil, err
 	}
@@ -1018,69 +1118,22 @@ func (s *AssetService) UpdateAssetType(id int64, newType string) error {
 	if !allowedConversion[newType] {
 		return errors.New("invalid target type: only 'image' and 'texture' are allowed")
 	}
-	asset, err := s.db.GetAssetById(s.ctx, id)
-	if err != nil {
-		return err
-	}
-
-	if !allowedConversion[asset.FileType] {
-		return fmt.Errorf("cannot change type for asset of type '%s'", asset.FileType)
-	}
-	return s.db.UpdateAssetType(s.ctx, database.UpdateAssetTypeParams{
-		FileType: newType,
-		ID:       id,
-	})
+	return s.journal.execute(s.ctxOrBackground(), &assetTypeOp{AssetID: id, After: newType})
 }
 
 // UpdateTags aktualizuje listę tagów dla assetu.
 func (s *AssetService) UpdateTags(assetId int64, tags []string) error {
-	tx, err := s.sysDB.Begin()
-	if err != nil {
-		return err
-	}
-	defer tx.Rollback()
-
-	qtx := database.New(s.sysDB).WithTx(tx)
-
-	// 1. Clear existing tags
-	if err := qtx.ClearTagsForAsset(s.ctx, assetId); err != nil {
-		return err
-	}
-
-	// 2. Add new tags
-	for _, tagName := range tags {
-		// Ensure tag exists
-		tag, err := qtx.CreateTag(s.ctx, tagName)
-		if err != nil {
-			return err
-		}
-		// Link tag
-		err = qtx.AddTagToAsset(s.ctx, database.AddTagToAssetParams{
-			AssetID: assetId,
-			TagID:   tag.ID,
-		})
-		if err != nil {
-			return err
-		}
-	}
-
-	return tx.Commit()
+	return s.journal.execute(s.ctxOrBackground(), &tagsOp{AssetID: assetId, After: tags})
 }
 
 // AddAssetToMaterialSet dodaje asset do kolekcji.
 func (s *AssetService) AddAssetToMaterialSet(setId int64, assetId int64) error {
-	return s.db.AddAssetToMaterialSet(s.ctx, database.AddAssetToMaterialSetParams{
-		MaterialSetID: setId,
-		AssetID:       assetId,
-	})
+	return s.journal.execute(s.ctxOrBackground(), &materialSetMemberOp{SetID: setId, AssetID: assetId, Added: true})
 }
 
 // RemoveAssetFromMaterialSet usuwa asset z kolekcji.
 func (s *AssetService) RemoveAssetFromMaterialSet(setId int64, assetId int64) error {
-	return s.db.RemoveAssetFromMaterialSet(s.ctx, database.RemoveAssetFromMaterialSetParams{
-		MaterialSetID: setId,
-		AssetID:       assetId,
-	})
+	return s.journal.execute(s.ctxOrBackground(), &materialSetMemberOp{SetID: setId, AssetID: assetId})
 }
 
 // GetThumbnailData returns the thumbnail image as a base64 data URL.
@@ -0,0 +1,194 @@
+package app
+
+import (
+	"context"
+	"database/sql"
+	"eclat/internal/database"
+	"errors"
+	"fmt"
+	"slices"
+
+	"github.com/google/uuid"
+)
+
+// AssetGroup holds the manual decisions about an asset group.
+type AssetGroup struct {
+	GroupID string `json:"groupId"`
+	// IsLocked keeps the scanner from adding files to the group or regrouping its members.
+	IsLocked bool `json:"isLocked"`
+	// RepresentativeID is the pinned representative; nil when the most recently modified asset represents the group.
+	RepresentativeID *int64 `json:"representativeId"`
+}
+
+// GetAssetGroup returns the manual settings of a group. Groups nobody touched are unlocked
+// and have no pinned representative.
+func (s *AssetService) GetAssetGroup(groupId string) (*AssetGroup, error) {
+	return loadAssetGroup(s.ctxOrBackground(), s.db, groupId)
+}
+
+// MergeGroups moves all assets of the given groups into the first one and locks it, so that
+// rescans keep them together. It returns the ID of the merged group.
+func (s *AssetService) MergeGroups(groupIds []string) (string, error) {
+	var ids []string
+	for _, id := range groupIds {
+		if id != "" && !slices.Contains(ids, id) {
+			ids = append(ids, id)
+		}
+	}
+	if len(ids) < 2 {
+		return "", errors.New("at least two groups are needed to merge")
+	}
+	target := ids[0]
+	ctx := s.ctxOrBackground()
+
+	err := inTx(ctx, s.sysDB, func(qtx *database.Queries) error {
+		merged, err := loadAssetGroup(ctx, qtx, target)
+		if err != nil {
+			return err
+		}
+		for _, id := range ids[1:] {
+			group, err := loadAssetGroup(ctx, qtx, id)
+			if err != nil {
+				return err
+			}
+			// The target's pinned representative wins, otherwise the first one pinned in a merged group.
+			if merged.RepresentativeID == nil {
+				merged.RepresentativeID = group.RepresentativeID
+			}
+			if err := qtx.ReassignAssetGroup(ctx, database.ReassignAssetGroupParams{NewGroupID: target, OldGroupID: id}); err != nil {
+				return fmt.Errorf("failed to merge group %s: %w", id, err)
+			}
+			if err := qtx.DeleteAssetGroup(ctx, id); err != nil {
+				return err
+			}
+		}
+		merged.IsLocked = true
+		return saveAssetGroup(ctx, qtx, merged)
+	})
+	if err != nil {
+		s.logger.Error("Failed to merge groups", "groups", ids, "error", err)
+		return "", err
+	}
+
+	s.logger.Info("Merged asset groups", "target", target, "count", len(ids))
+	s.notifier.EmitAssetsChanged(ctx)
+	return target, nil
+}
+
+// DetachAssets moves the given assets out of their groups into a new, locked group and
+// returns its ID.
+func (s *AssetService) DetachAssets(ids []int64) (string, error) {
+	if len(ids) == 0 {
+		return "", errors.New("no assets to detach")
+	}
+	groupID := uuid.New().String()
+	ctx := s.ctxOrBackground()
+
+	err := inTx(ctx, s.sysDB, func(qtx *database.Queries) error {
+		for _, id := range ids {
+			asset, err := qtx.GetAssetById(ctx, id)
+			if err != nil {
+				return fmt.Errorf("failed to get asset %d: %w", id, err)
+			}
+			// A detached representative no longer represents its old group.
+			source, err := loadAssetGroup(ctx, qtx, asset.GroupID)
+			if err != nil {
+				return err
+			}
+			if source.RepresentativeID != nil && *source.RepresentativeID == id {
+				source.RepresentativeID = nil
+				if err := saveAssetGroup(ctx, qtx, source); err != nil {
+					return err
+				}
+			}
+			if err := qtx.UpdateAssetGroup(ctx, database.UpdateAssetGroupParams{GroupID: groupID, ID: id}); err != nil {
+				return fmt.Errorf("failed to detach asset %d: %w", id, err)
+			}
+		}
+		return saveAssetGroup(ctx, qtx, &AssetGroup{GroupID: groupID, IsLocked: true})
+	})
+	if err != nil {
+		s.logger.Error("Failed to detach assets", "ids", ids, "error", err)
+		return "", err
+	}
+
+	s.logger.Info("Detached assets into a new group", "group_id", groupID, "count", len(ids))
+	s.notifier.EmitAssetsChanged(ctx)
+	return groupID, nil
+}
+
+// SetGroupRepresentative pins the asset shown for its group when only representatives are
+// listed. Asset ID 0 unpins it again.
+func (s *AssetService) SetGroupRepresentative(groupId string, assetId int64) error {
+	ctx := s.ctxOrBackground()
+	group, err := loadAssetGroup(ctx, s.db, groupId)
+	if err != nil {
+		return err
+	}
+
+	group.RepresentativeID = nil
+	if assetId != 0 {
+		asset, err := s.db.GetAssetById(ctx, assetId)
+		if err != nil {
+			return fmt.Errorf("failed to get asset: %w", err)
+		}
+		if asset.GroupID != groupId {
+			return fmt.Errorf("asset %d does not belong to group %s", assetId, groupId)
+		}
+		group.RepresentativeID = &assetId
+	}
+
+	if err := saveAssetGroup(ctx, s.db, group); err != nil {
+		return err
+	}
+	s.notifier.EmitAssetsChanged(ctx)
+	return nil
+}
+
+// SetGroupLocked locks or unlocks a group. The scanner does not add files to a locked group
+// and does not regroup its members.
+func (s *AssetService) SetGroupLocked(groupId string, locked bool) error {
+	if groupId == "" {
+		return errors.New("group id cannot be empty")
+	}
+	ctx := s.ctxOrBackground()
+	group, err := loadAssetGroup(ctx, s.db, groupId)
+	if err != nil {
+		return err
+	}
+	group.IsLocked = locked
+	if err := saveAssetGroup(ctx, s.db, group); err != nil {
+		return err
+	}
+	s.notifier.EmitAssetsChanged(ctx)
+	return nil
+}
+
+func loadAssetGroup(ctx context.Context, q database.Querier, groupId string) (*AssetGroup, error) {
+	group := &AssetGroup{GroupID: groupId}
+	row, err := q.GetAssetGroup(ctx, groupId)
+	if errors.Is(err, sql.ErrNoRows) {
+		return group, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get group %s: %w", groupId, err)
+	}
+	group.IsLocked = row.IsLocked
+	if row.RepresentativeID.Valid {
+		id := row.RepresentativeID.Int64
+		group.RepresentativeID = &id
+	}
+	return group, nil
+}
+
+// saveAssetGroup stores the group settings; groups back to the defaults lose their row.
+func saveAssetGroup(ctx context.Context, q database.Querier, group *AssetGroup) error {
+	if !group.IsLocked && group.RepresentativeID == nil {
+		return q.DeleteAssetGroup(ctx, group.GroupID)
+	}
+	params := database.UpsertAssetGroupParams{GroupID: group.GroupID, IsLocked: group.IsLocked}
+	if group.RepresentativeID != nil {
+		params.RepresentativeID = sql.NullInt64{Int64: *group.RepresentativeID, Valid: true}
+	}
+	return q.UpsertAssetGroup(ctx, params)
+}
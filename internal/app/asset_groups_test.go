@@ -0,0 +1,128 @@
+package app
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func representativeIDs(t *testing.T, service *AssetService) []int64 {
+	res, err := service.GetAssets(AssetQueryFilters{Page: 1, PageSize: 10, ShowRepresentativesOnly: true, SortOption: "filename"})
+	require.NoError(t, err)
+	ids := make([]int64, 0, len(res.Items))
+	for _, a := range res.Items {
+		ids = append(ids, a.ID)
+	}
+	return ids
+}
+
+func TestAssetService_SetGroupRepresentative(t *testing.T) {
+	service, queries := setupAssetServiceTest(t)
+	first := insertTestAssetWithParamsAndGroup(t, queries, "a_rock.png", "/tmp/groups/a_rock.png", false, false, "G1")
+	insertTestAssetWithParamsAndGroup(t, queries, "b_rock_v2.png", "/tmp/groups/b_rock_v2.png", false, false, "G1")
+	latest := insertTestAssetWithParamsAndGroup(t, queries, "c_rock_v3.png", "/tmp/groups/c_rock_v3.png", false, false, "G1")
+
+	// Domyślnie reprezentantem jest najnowszy plik
+	assert.Equal(t, []int64{latest.ID}, representativeIDs(t, service))
+
+	require.NoError(t, service.SetGroupRepresentative("G1", first.ID))
+	assert.Equal(t, []int64{first.ID}, representativeIDs(t, service))
+	group, err := service.GetAssetGroup("G1")
+	require.NoError(t, err)
+	assert.Equal(t, first.ID, *group.RepresentativeID)
+	assert.False(t, group.IsLocked)
+
+	other := insertTestAssetWithParamsAndGroup(t, queries, "sky.png", "/tmp/groups/sky.png", false, false, "G2")
+	assert.Error(t, service.SetGroupRepresentative("G1", other.ID), "Asset z innej grupy")
+
+	require.NoError(t, service.SetGroupRepresentative("G1", 0))
+	assert.Equal(t, []int64{latest.ID, other.ID}, representativeIDs(t, service))
+}
+
+func TestAssetService_GetAssets_RepresentativeMatchesFilters(t *testing.T) {
+	service, queries := setupAssetServiceTest(t)
+	pinned := insertTestAssetWithParamsAndGroup(t, queries, "rock.psd", "/tmp/groups/rock.psd", false, false, "G1")
+	png := insertTestAssetWithParamsAndGroup(t, queries, "rock.png", "/tmp/groups/rock.png", false, false, "G1")
+	insertTestAssetWithParamsAndGroup(t, queries, "rock_v2.psd", "/tmp/groups/rock_v2.psd", false, false, "G1")
+	require.NoError(t, service.SetGroupRepresentative("G1", pinned.ID))
+
+	// Przypięty reprezentant nie pasuje do filtra - grupę reprezentuje pasujący plik
+	res, err := service.GetAssets(AssetQueryFilters{Page: 1, PageSize: 10, ShowRepresentativesOnly: true, Query: "ext:png", SortOption: "relevance", SortDesc: true})
+	require.NoError(t, err)
+	assert.Equal(t, 1, res.TotalCount)
+	if assert.Len(t, res.Items, 1) {
+		assert.Equal(t, png.ID, res.Items[0].ID)
+		assert.False(t, res.Items[0].LastModified.IsZero())
+	}
+
+	res, err = service.GetAssets(AssetQueryFilters{Page: 1, PageSize: 10, ShowRepresentativesOnly: true, Query: "rock", SortOption: "relevance", SortDesc: true})
+	require.NoError(t, err)
+	assert.Equal(t, 1, res.TotalCount)
+	if assert.Len(t, res.Items, 1) {
+		assert.Equal(t, pinned.ID, res.Items[0].ID)
+	}
+}
+
+func TestAssetService_MergeGroups(t *testing.T) {
+	service, queries := setupAssetServiceTest(t)
+	insertTestAssetWithParamsAndGroup(t, queries, "forest.psd", "/tmp/groups/forest.psd", false, false, "G1")
+	tree := insertTestAssetWithParamsAndGroup(t, queries, "tree.psd", "/tmp/groups/tree.psd", false, false, "G2")
+	insertTestAssetWithParamsAndGroup(t, queries, "bush.psd", "/tmp/groups/bush.psd", false, false, "G3")
+	require.NoError(t, service.SetGroupRepresentative("G2", tree.ID))
+
+	_, err := service.MergeGroups([]string{"G1", "", "G1"})
+	assert.Error(t, err, "Scalenie wymaga co najmniej dwóch grup")
+
+	target, err := service.MergeGroups([]string{"G1", "G2", "G3", "G2"})
+	require.NoError(t, err)
+	assert.Equal(t, "G1", target)
+
+	members, err := queries.GetAssetsByGroupID(service.ctx, "G1")
+	require.NoError(t, err)
+	assert.Len(t, members, 3)
+
+	group, err := service.GetAssetGroup("G1")
+	require.NoError(t, err)
+	assert.True(t, group.IsLocked, "Ręcznie scalona grupa jest zablokowana")
+	require.NotNil(t, group.RepresentativeID)
+	assert.Equal(t, tree.ID, *group.RepresentativeID, "Przypięty reprezentant przechodzi do scalonej grupy")
+
+	_, err = queries.GetAssetGroup(service.ctx, "G2")
+	assert.Error(t, err, "Ustawienia scalonych grup są usuwane")
+	assert.Equal(t, []int64{tree.ID}, representativeIDs(t, service))
+}
+
+func TestAssetService_DetachAssets(t *testing.T) {
+	service, queries := setupAssetServiceTest(t)
+	forest := insertTestAssetWithParamsAndGroup(t, queries, "forest.psd", "/tmp/groups/forest.psd", false, false, "G1")
+	desert := insertTestAssetWithParamsAndGroup(t, queries, "desert.psd", "/tmp/groups/desert.psd", false, false, "G1")
+	require.NoError(t, service.SetGroupRepresentative("G1", desert.ID))
+
+	_, err := service.DetachAssets(nil)
+	assert.Error(t, err)
+
+	groupID, err := service.DetachAssets([]int64{desert.ID})
+	require.NoError(t, err)
+	assert.NotEqual(t, "G1", groupID)
+
+	detached, err := queries.GetAssetById(service.ctx, desert.ID)
+	require.NoError(t, err)
+	assert.Equal(t, groupID, detached.GroupID)
+
+	group, err := service.GetAssetGroup(groupID)
+	require.NoError(t, err)
+	assert.True(t, group.IsLocked)
+
+	source, err := service.GetAssetGroup("G1")
+	require.NoError(t, err)
+	assert.Nil(t, source.RepresentativeID, "Odłączony reprezentant przestaje reprezentować starą grupę")
+	assert.ElementsMatch(t, []int64{forest.ID, desert.ID}, representativeIDs(t, service))
+
+	notifier := service.notifier.(*MockNotifier)
+	notifier.CallCount = 0
+	require.NoError(t, service.SetGroupLocked(groupID, false))
+	assert.Equal(t, 1, notifier.CallCount, "Galeria odświeża stan blokady")
+	_, err = queries.GetAssetGroup(service.ctx, groupID)
+	assert.Error(t, err, "Grupa z domyślnymi ustawieniami nie ma wpisu")
+}
@@ -0,0 +1,387 @@
+package app
+
+import (
+	"context"
+	"database/sql"
+	"eclat/internal/database"
+	"eclat/internal/feedback"
+	"eclat/internal/fsutil"
+	"eclat/internal/scanner"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// WatchSuppressor keeps the file watcher from reporting files the app moves or copies itself,
+// which the scanner would otherwise import as new assets.
+type WatchSuppressor interface {
+	Suppress(paths ...string) (release func())
+}
+
+// Operations reported in feedback.FileOperationProgressDTO.
+const (
+	fileOperationMove = "move"
+	fileOperationCopy = "copy"
+)
+
+// assetTransfer is an asset file to move or copy.
+type assetTransfer struct {
+	asset     database.Asset
+	dst       string
+	item      int    // Index in BatchResult.Items
+	thumbnail string // Thumbnail of the copy
+}
+
+// MoveAssets moves the files of the given assets into targetDir, which must be inside a scan
+// folder. All assets of their groups (versions and texture set maps) are moved along. The assets
+// keep their tags, material sets and groups. If moving a file fails, the files already moved are
+// moved back.
+func (s *AssetService) MoveAssets(ids []int64, targetDir string) (*BatchResult, error) {
+	return s.transferAssets(fileOperationMove, ids, targetDir)
+}
+
+// CopyAssets copies the files of the given assets, with all assets of their groups, into
+// targetDir, which must be inside a scan folder. The copies form new groups and get the tags,
+// material sets, rating and metadata of the originals.
+func (s *AssetService) CopyAssets(ids []int64, targetDir string) (*BatchResult, error) {
+	return s.transferAssets(fileOperationCopy, ids, targetDir)
+}
+
+func (s *AssetService) transferAssets(operation string, ids []int64, targetDir string) (*BatchResult, error) {
+	ctx := s.ctxOrBackground()
+	targetDir = filepath.Clean(targetDir)
+	if info, err := os.Stat(targetDir); err != nil || !info.IsDir() {
+		return nil, fmt.Errorf("target folder %s does not exist", targetDir)
+	}
+	folderID, err := scanner.ResolveFolderID(ctx, s.db, targetDir)
+	if err != nil {
+		return nil, fmt.Errorf("target folder %s is not inside a scan folder", targetDir)
+	}
+
+	result, transfers, err := s.planTransfers(ctx, operation, ids, targetDir)
+	if err != nil {
+		return nil, err
+	}
+	if len(transfers) > 0 {
+		if s.watcher != nil {
+			paths := make([]string, 0, 2*len(transfers))
+			for _, t := range transfers {
+				paths = append(paths, t.asset.FilePath, t.dst)
+			}
+			release := s.watcher.Suppress(paths...)
+			defer release()
+		}
+
+		if operation == fileOperationMove {
+			err = s.moveAssetFiles(ctx, transfers, folderID)
+		} else {
+			err = s.copyAssetFiles(ctx, transfers, folderID, result)
+		}
+		if err != nil {
+			s.logger.Error("Failed to "+operation+" assets", "target", targetDir, "count", len(transfers), "error", err)
+			return nil, err
+		}
+		for _, t := range transfers {
+			if result.Items[t.item].Status == "" {
+				result.Items[t.item].Status = BatchItemChanged
+			}
+		}
+	}
+
+	result.tally()
+	s.logger.Info("Transferred assets", "operation", operation, "target", targetDir, "changed", result.Changed, "failed", result.Failed)
+	s.notifier.EmitAssetsChanged(ctx)
+	return result, nil
+}
+
+// planTransfers expands the assets to their groups and picks the destination of each file. It
+// fails if any destination is taken, before a file is touched.
+func (s *AssetService) planTransfers(ctx context.Context, operation string, ids []int64, targetDir string) (*BatchResult, []*assetTransfer, error) {
+	result := &BatchResult{}
+	var transfers []*assetTransfer
+	seen := make(map[int64]bool)
+	groups := make(map[string]bool)
+	targets := make(map[string]string) // pathKey of the destination -> source
+
+	add := func(asset database.Asset) error {
+		if seen[asset.ID] {
+			return nil
+		}
+		seen[asset.ID] = true
+		result.Items = append(result.Items, BatchItemResult{AssetID: asset.ID})
+		item := &result.Items[len(result.Items)-1]
+		dst := filepath.Join(targetDir, asset.FileName)
+
+		switch {
+		case asset.IsDeleted:
+			item.Status, item.Error = BatchItemFailed, "the asset is in the trash"
+			return nil
+		case operation == fileOperationMove && filepath.Dir(asset.FilePath) == targetDir:
+			item.Status = BatchItemUnchanged
+			return nil
+		}
+		if _, err := os.Stat(asset.FilePath); err != nil {
+			item.Status, item.Error = BatchItemFailed, "the file is missing on disk"
+			return nil
+		}
+		if other, ok := targets[pathKey(dst)]; ok {
+			return fmt.Errorf("cannot %s %s and %s into the same folder: their names collide", operation, other, asset.FilePath)
+		}
+		if _, err := os.Lstat(dst); err == nil {
+			return fmt.Errorf("cannot %s %s: %s already exists", operation, asset.FileName, dst)
+		}
+		if _, err := s.db.GetAssetByPath(ctx, dst); err == nil {
+			return fmt.Errorf("cannot %s %s: %s is already in the library", operation, asset.FileName, dst)
+		}
+		targets[pathKey(dst)] = asset.FilePath
+		transfers = append(transfers, &assetTransfer{asset: asset, dst: dst, item: len(result.Items) - 1})
+		return nil
+	}
+
+	for _, id := range ids {
+		asset, err := s.db.GetAssetById(ctx, id)
+		if errors.Is(err, sql.ErrNoRows) {
+			if !seen[id] {
+				seen[id] = true
+				result.Items = append(result.Items, BatchItemResult{AssetID: id, Status: BatchItemFailed, Error: "asset not found"})
+			}
+			continue
+		}
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to get asset %d: %w", id, err)
+		}
+		if err := add(asset); err != nil {
+			return nil, nil, err
+		}
+		if asset.GroupID == "" || groups[asset.GroupID] || asset.IsDeleted {
+			continue
+		}
+		groups[asset.GroupID] = true
+
+		members, err := s.db.GetAssetsByGroupID(ctx, asset.GroupID)
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to get group %s: %w", asset.GroupID, err)
+		}
+		for _, m := range members {
+			if seen[m.ID] {
+				continue
+			}
+			member, err := s.db.GetAssetById(ctx, m.ID)
+			if err != nil {
+				return nil, nil, fmt.Errorf("failed to get asset %d: %w", m.ID, err)
+			}
+			if err := add(member); err != nil {
+				return nil, nil, err
+			}
+		}
+	}
+	return result, transfers, nil
+}
+
+// moveAssetFiles moves the files, then points the assets at their new paths in one transaction.
+func (s *AssetService) moveAssetFiles(ctx context.Context, transfers []*assetTransfer, folderID int64) error {
+	var moved []*assetTransfer
+	rollback := func() {
+		for i := len(moved) - 1; i >= 0; i-- {
+			t := moved[i]
+			if err := fsutil.MoveFile(t.dst, t.asset.FilePath); err != nil {
+				s.logger.Error("Failed to move file back", "from", t.dst, "to", t.asset.FilePath, "error", err)
+			}
+		}
+	}
+
+	for i, t := range transfers {
+		// MoveFile would replace a file created since the transfer was planned
+		if _, err := os.Lstat(t.dst); err == nil {
+			rollback()
+			return fmt.Errorf("%s already exists", t.dst)
+		}
+		if err := fsutil.MoveFile(t.asset.FilePath, t.dst); err != nil {
+			rollback()
+			return fmt.Errorf("failed to move %s: %w", t.asset.FilePath, err)
+		}
+		moved = append(moved, t)
+		s.sendTransferProgress(ctx, fileOperationMove, i+1, len(transfers), t.asset.FileName)
+	}
+
+	err := inTx(ctx, s.sysDB, func(qtx *database.Queries) error {
+		now := time.Now()
+		for _, t := range transfers {
+			err := qtx.UpdateAssetLocation(ctx, database.UpdateAssetLocationParams{
+				FilePath:     t.dst,
+				ScanFolderID: sql.NullInt64{Int64: folderID, Valid: true},
+				LastScanned:  now,
+				ID:           t.asset.ID,
+			})
+			if err != nil {
+				return fmt.Errorf("failed to update asset %d: %w", t.asset.ID, err)
+			}
+			reindexSearchEntry(ctx, qtx, s.logger, t.asset.ID)
+		}
+		return nil
+	})
+	if err != nil {
+		rollback()
+	}
+	return err
+}
+
+// copyAssetFiles copies the files with their thumbnails, then adds the copies to the library in
+// one transaction. An asset whose thumbnail cannot be copied fails on its own, because the copy
+// keeps the original's size and modification time and the scanner would never give it one.
+func (s *AssetService) copyAssetFiles(ctx context.Context, transfers []*assetTransfer, folderID int64, result *BatchResult) error {
+	var created []string // Removed if the copy fails
+	cleanup := func() {
+		for _, path := range created {
+			if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+				s.logger.Error("Failed to remove copied file", "path", path, "error", err)
+			}
+		}
+	}
+
+	var copied []*assetTransfer
+	for i, t := range transfers {
+		thumbnail, thumbFile, err := s.copyThumbnail(t.asset.ThumbnailPath)
+		if err != nil {
+			s.logger.Warn("Failed to copy thumbnail", "id", t.asset.ID, "thumbnail", t.asset.ThumbnailPath, "error", err)
+			result.Items[t.item].Status, result.Items[t.item].Error = BatchItemFailed, "the thumbnail could not be copied"
+			s.sendTransferProgress(ctx, fileOperationCopy, i+1, len(transfers), t.asset.FileName)
+			continue
+		}
+		if thumbFile != "" {
+			created = append(created, thumbFile)
+		}
+		if err := fsutil.CopyFile(t.asset.FilePath, t.dst); err != nil {
+			cleanup()
+			return fmt.Errorf("failed to copy %s: %w", t.asset.FilePath, err)
+		}
+		created = append(created, t.dst)
+		t.thumbnail = thumbnail
+		copied = append(copied, t)
+		s.sendTransferProgress(ctx, fileOperationCopy, i+1, len(transfers), t.asset.FileName)
+	}
+
+	copies := make(map[int64]int64, len(copied))
+	err := inTx(ctx, s.sysDB, func(qtx *database.Queries) error {
+		newGroups := make(map[string]string)
+		for _, t := range copied {
+			a := t.asset
+			groupID, ok := newGroups[a.GroupID]
+			if !ok {
+				groupID = uuid.New().String()
+				newGroups[a.GroupID] = groupID
+			}
+
+			asset, err := qtx.CreateAsset(ctx, database.CreateAssetParams{
+				ScanFolderID:    sql.NullInt64{Int64: folderID, Valid: true},
+				FileName:        a.FileName,
+				FilePath:        t.dst,
+				FileType:        a.FileType,
+				FileSize:        a.FileSize,
+				ThumbnailPath:   t.thumbnail,
+				FileHash:        a.FileHash,
+				ImageWidth:      a.ImageWidth,
+				ImageHeight:     a.ImageHeight,
+				DominantColor:   a.DominantColor,
+				BitDepth:        a.BitDepth,
+				HasAlphaChannel: a.HasAlphaChannel,
+				LastModified:    a.LastModified,
+				LastScanned:     time.Now(),
+				GroupID:         groupID,
+				PerceptualHash:  a.PerceptualHash,
+				MaterialChannel: a.MaterialChannel,
+				AnalysisVersion: a.AnalysisVersion,
+			})
+			if err != nil {
+				return fmt.Errorf("failed to add copy of asset %d: %w", a.ID, err)
+			}
+			_, err = qtx.UpdateAssetMetadata(ctx, database.UpdateAssetMetadataParams{
+				Description: a.Description,
+				Rating:      sql.NullInt64{Int64: a.Rating, Valid: true},
+				IsFavorite:  a.IsFavorite,
+				ID:          asset.ID,
+			})
+			if err != nil {
+				return err
+			}
+			if a.IsHidden {
+				if err := qtx.SetAssetHidden(ctx, database.SetAssetHiddenParams{IsHidden: true, ID: asset.ID}); err != nil {
+					return err
+				}
+			}
+			if err := copyAssetData(ctx, qtx, a.ID, asset.ID); err != nil {
+				return fmt.Errorf("failed to copy data of asset %d: %w", a.ID, err)
+			}
+			reindexSearchEntry(ctx, qtx, s.logger, asset.ID)
+			copies[a.ID] = asset.ID
+		}
+		return nil
+	})
+	if err != nil {
+		cleanup()
+		return err
+	}
+	for _, t := range copied {
+		result.Items[t.item].NewAssetID = copies[t.asset.ID]
+	}
+	return nil
+}
+
+// copyAssetData copies the tags, material sets, colors, properties and model metadata of an asset.
+func copyAssetData(ctx context.Context, qtx *database.Queries, assetID, newAssetID int64) error {
+	copies := []func(context.Context, int64, int64) error{
+		func(ctx context.Context, from, to int64) error {
+			return qtx.CopyAssetTags(ctx, database.CopyAssetTagsParams{NewAssetID: to, AssetID: from})
+		},
+		func(ctx context.Context, from, to int64) error {
+			return qtx.CopyAssetMaterialSets(ctx, database.CopyAssetMaterialSetsParams{NewAssetID: to, AssetID: from})
+		},
+		func(ctx context.Context, from, to int64) error {
+			return qtx.CopyAssetColors(ctx, database.CopyAssetColorsParams{NewAssetID: to, AssetID: from})
+		},
+		func(ctx context.Context, from, to int64) error {
+			return qtx.CopyAssetFeatures(ctx, database.CopyAssetFeaturesParams{NewAssetID: to, AssetID: from})
+		},
+		func(ctx context.Context, from, to int64) error {
+			return qtx.CopyAssetProperties(ctx, database.CopyAssetPropertiesParams{NewAssetID: to, AssetID: from})
+		},
+		func(ctx context.Context, from, to int64) error {
+			return qtx.CopyModelMetadata(ctx, database.CopyModelMetadataParams{NewAssetID: to, AssetID: from})
+		},
+	}
+	for _, copyData := range copies {
+		if err := copyData(ctx, assetID, newAssetID); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// copyThumbnail gives a copied asset its own thumbnail file, so deleting either asset keeps the
+// other's. It returns the thumbnail path for the copy and the file created, if any. Placeholders
+// are shared.
+func (s *AssetService) copyThumbnail(thumbnailPath string) (webPath, file string, err error) {
+	if !strings.HasPrefix(thumbnailPath, "/thumbnails/") {
+		return thumbnailPath, "", nil
+	}
+	name := uuid.New().String() + filepath.Ext(thumbnailPath)
+	file = filepath.Join(s.thumbnailsDir, name)
+	if err := fsutil.CopyFile(filepath.Join(s.thumbnailsDir, filepath.Base(thumbnailPath)), file); err != nil {
+		return "", "", err
+	}
+	return "/thumbnails/" + name, file, nil
+}
+
+func (s *AssetService) sendTransferProgress(ctx context.Context, operation string, current, total int, lastFile string) {
+	s.notifier.SendFileOperationProgress(ctx, feedback.FileOperationProgressDTO{
+		Operation: operation,
+		Current:   current,
+		Total:     total,
+		LastFile:  lastFile,
+	})
+}
@@ -0,0 +1,156 @@
+package app
+
+import (
+	"context"
+	"eclat/internal/config"
+	"eclat/internal/database"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeSuppressor struct {
+	paths    []string
+	released int
+}
+
+func (f *fakeSuppressor) Suppress(paths ...string) func() {
+	f.paths = append(f.paths, paths...)
+	return func() { f.released++ }
+}
+
+// setupTransferTest tworzy folder źródłowy i docelowy, oba zarejestrowane jako foldery skanowania.
+func setupTransferTest(t *testing.T) (*AssetService, database.Querier, *fakeSuppressor, string, string) {
+	service, queries := setupAssetServiceTest(t)
+	watcher := &fakeSuppressor{}
+	service.watcher = watcher
+	service.thumbnailsDir = t.TempDir()
+	src, dst := t.TempDir(), t.TempDir()
+	for _, dir := range []string{src, dst} {
+		_, err := queries.CreateScanFolder(context.Background(), dir)
+		require.NoError(t, err)
+	}
+	return service, queries, watcher, src, dst
+}
+
+func TestMoveAssets_MovesWholeGroup(t *testing.T) {
+	service, queries, watcher, src, dst := setupTransferTest(t)
+	ctx := context.Background()
+	albedo := insertTextureMap(t, queries, filepath.Join(src, "rock_albedo.png"), config.ChannelBaseColor, "G-ROCK")
+	normal := insertTextureMap(t, queries, filepath.Join(src, "rock_normal.png"), config.ChannelNormal, "G-ROCK")
+	other := insertTextureMap(t, queries, filepath.Join(src, "sky.png"), "", "G-SKY")
+	require.NoError(t, service.UpdateTags(normal.ID, []string{"stone"}))
+	folder, err := queries.GetScanFolderByPath(ctx, dst)
+	require.NoError(t, err)
+	notifier := service.notifier.(*MockNotifier)
+
+	result, err := service.MoveAssets([]int64{albedo.ID}, dst)
+	require.NoError(t, err)
+	assert.Equal(t, 2, result.Changed, "Cała grupa przenosi się razem")
+
+	for _, asset := range []database.Asset{albedo, normal} {
+		moved, err := queries.GetAssetById(ctx, asset.ID)
+		require.NoError(t, err)
+		assert.Equal(t, filepath.Join(dst, asset.FileName), moved.FilePath)
+		assert.Equal(t, folder.ID, moved.ScanFolderID.Int64)
+		assert.Equal(t, "G-ROCK", moved.GroupID)
+		assert.FileExists(t, moved.FilePath)
+		assert.NoFileExists(t, asset.FilePath)
+	}
+	assert.FileExists(t, other.FilePath, "Inne grupy zostają")
+
+	tags, err := queries.GetTagsNamesByAssetID(ctx, normal.ID)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"stone"}, tags)
+
+	assert.Contains(t, watcher.paths, albedo.FilePath)
+	assert.Contains(t, watcher.paths, filepath.Join(dst, "rock_normal.png"))
+	assert.Equal(t, 1, watcher.released)
+	require.Len(t, notifier.FileProgress, 2)
+	assert.Equal(t, "move", notifier.FileProgress[1].Operation)
+	assert.Equal(t, 2, notifier.FileProgress[1].Current)
+	assert.Equal(t, 2, notifier.FileProgress[1].Total)
+
+	// Assety już w folderze docelowym są bez zmian
+	result, err = service.MoveAssets([]int64{normal.ID}, dst)
+	require.NoError(t, err)
+	assert.Equal(t, 2, result.Unchanged)
+}
+
+func TestMoveAssets_Conflicts(t *testing.T) {
+	service, queries, _, src, dst := setupTransferTest(t)
+	ctx := context.Background()
+	rock := insertTextureMap(t, queries, filepath.Join(src, "rock.png"), "", "G1")
+	deleted := insertTextureMap(t, queries, filepath.Join(src, "old.png"), "", "G2")
+	require.NoError(t, queries.SoftDeleteAsset(ctx, deleted.ID))
+	require.NoError(t, os.WriteFile(filepath.Join(dst, "rock.png"), []byte("taken"), 0644))
+
+	_, err := service.MoveAssets([]int64{rock.ID}, dst)
+	assert.ErrorContains(t, err, "already exists")
+	assert.FileExists(t, rock.FilePath, "Nic nie zostało przeniesione")
+
+	_, err = service.MoveAssets([]int64{rock.ID}, t.TempDir())
+	assert.ErrorContains(t, err, "not inside a scan folder")
+
+	require.NoError(t, os.Remove(filepath.Join(dst, "rock.png")))
+	result, err := service.MoveAssets([]int64{deleted.ID, 9999, rock.ID}, dst)
+	require.NoError(t, err)
+	assert.Equal(t, 1, result.Changed)
+	assert.Equal(t, 2, result.Failed)
+	assert.Equal(t, "the asset is in the trash", result.Items[0].Error)
+	assert.Equal(t, "asset not found", result.Items[1].Error)
+}
+
+func TestCopyAssets_CopiesDataIntoNewGroup(t *testing.T) {
+	service, queries, _, src, dst := setupTransferTest(t)
+	ctx := context.Background()
+	albedo := insertTextureMap(t, queries, filepath.Join(src, "rock_albedo.png"), config.ChannelBaseColor, "G-ROCK")
+	normal := insertTextureMap(t, queries, filepath.Join(src, "rock_normal.png"), config.ChannelNormal, "G-ROCK")
+	require.NoError(t, os.WriteFile(filepath.Join(service.thumbnailsDir, filepath.Base(albedo.ThumbnailPath)), []byte("thumb"), 0644))
+	require.NoError(t, service.UpdateTags(albedo.ID, []string{"stone"}))
+	_, err := service.sysDB.Exec("UPDATE assets SET analysis_version = 1 WHERE id = ?", albedo.ID)
+	require.NoError(t, err)
+	set, err := queries.CreateMaterialSet(ctx, database.CreateMaterialSetParams{Name: "Rocks"})
+	require.NoError(t, err)
+	require.NoError(t, service.AddAssetToMaterialSet(set.ID, albedo.ID))
+
+	result, err := service.CopyAssets([]int64{albedo.ID}, dst)
+	require.NoError(t, err)
+	assert.Equal(t, 1, result.Changed)
+	require.NotZero(t, result.Items[0].NewAssetID)
+
+	original, err := queries.GetAssetById(ctx, albedo.ID)
+	require.NoError(t, err)
+	assert.Equal(t, albedo.FilePath, original.FilePath, "Oryginał zostaje na miejscu")
+	assert.FileExists(t, albedo.FilePath)
+
+	copied, err := queries.GetAssetById(ctx, result.Items[0].NewAssetID)
+	require.NoError(t, err)
+	assert.Equal(t, filepath.Join(dst, "rock_albedo.png"), copied.FilePath)
+	assert.NotEqual(t, "G-ROCK", copied.GroupID, "Kopia tworzy nową grupę")
+	assert.Equal(t, config.ChannelBaseColor, copied.MaterialChannel)
+	assert.NotEqual(t, albedo.ThumbnailPath, copied.ThumbnailPath, "Kopia ma własną miniaturę")
+	assert.FileExists(t, filepath.Join(service.thumbnailsDir, filepath.Base(copied.ThumbnailPath)))
+	assert.Equal(t, int64(1), copied.AnalysisVersion, "Skaner nie analizuje kopii ponownie")
+
+	// Bez pliku miniatury kopia nigdy by jej nie dostała - asset jest pomijany
+	assert.Equal(t, normal.ID, result.Items[1].AssetID)
+	assert.Equal(t, BatchItemFailed, result.Items[1].Status)
+	assert.Equal(t, "the thumbnail could not be copied", result.Items[1].Error)
+	assert.Zero(t, result.Items[1].NewAssetID)
+	assert.NoFileExists(t, filepath.Join(dst, "rock_normal.png"))
+
+	tags, err := queries.GetTagsNamesByAssetID(ctx, copied.ID)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"stone"}, tags)
+	setIDs, err := queries.ListMaterialSetIDsByAsset(ctx, copied.ID)
+	require.NoError(t, err)
+	assert.Equal(t, []int64{set.ID}, setIDs)
+
+	// Druga kopia w to samo miejsce koliduje
+	_, err = service.CopyAssets([]int64{albedo.ID}, dst)
+	assert.ErrorContains(t, err, "already exists")
+}
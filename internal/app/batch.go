@@ -0,0 +1,353 @@
+package app
+
+import (
+	"context"
+	"database/sql"
+	"eclat/internal/database"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+
+	sq "github.com/Masterminds/squirrel"
+)
+
+// BatchTarget selects the assets a batch operation works on: the given IDs, or, when Filters
+// is set, every asset matching them. Paging, sorting and grouping of the filters are ignored,
+// so all versions of a group are included.
+type BatchTarget struct {
+	IDs     []int64            `json:"ids"`
+	Filters *AssetQueryFilters `json:"filters"`
+}
+
+// Outcome of a batch operation for a single asset.
+const (
+	BatchItemChanged   = "changed"
+	BatchItemUnchanged = "unchanged" // The asset was already in the requested state
+	BatchItemFailed    = "failed"
+)
+
+type BatchItemResult struct {
+	AssetID    int64  `json:"assetId"`
+	Status     string `json:"status"`
+	Error      string `json:"error,omitempty"`
+	NewAssetID int64  `json:"newAssetId,omitempty"` // The copy made by CopyAssets
+}
+
+// BatchResult summarizes a batch operation. Failed assets are skipped; the others are changed
+// in one transaction and undone together.
+type BatchResult struct {
+	Requested int               `json:"requested"`
+	Changed   int               `json:"changed"`
+	Unchanged int               `json:"unchanged"`
+	Failed    int               `json:"failed"`
+	Items     []BatchItemResult `json:"items"`
+}
+
+// BatchAddTags adds tags to the selected assets, keeping their other tags.
+func (s *AssetService) BatchAddTags(target BatchTarget, tags []string) (*BatchResult, error) {
+	names, err := batchTagNames(tags)
+	if err != nil {
+		return nil, err
+	}
+	return s.runBatch(target, "Add tags "+strings.Join(names, ", "), func(id int64) journalOp {
+		return &tagsOp{AssetID: id, add: names}
+	})
+}
+
+// BatchRemoveTags removes tags from the selected assets.
+func (s *AssetService) BatchRemoveTags(target BatchTarget, tags []string) (*BatchResult, error) {
+	names, err := batchTagNames(tags)
+	if err != nil {
+		return nil, err
+	}
+	return s.runBatch(target, "Remove tags "+strings.Join(names, ", "), func(id int64) journalOp {
+		return &tagsOp{AssetID: id, remove: names}
+	})
+}
+
+// BatchSetRating sets the rating of the selected assets.
+func (s *AssetService) BatchSetRating(target BatchTarget, rating int64) (*BatchResult, error) {
+	if rating < 0 || rating > 5 {
+		return nil, errors.New("rating must be between 0 and 5")
+	}
+	return s.runBatch(target, fmt.Sprintf("Rate %d/5", rating), func(id int64) journalOp {
+		return &ratingOp{AssetID: id, After: rating}
+	})
+}
+
+// BatchSetFavorite adds the selected assets to favorites or removes them.
+func (s *AssetService) BatchSetFavorite(target BatchTarget, favorite bool) (*BatchResult, error) {
+	action := "Remove from favorites"
+	if favorite {
+		action = "Add to favorites"
+	}
+	return s.runBatch(target, action, func(id int64) journalOp {
+		return &flagOp{Flag: flagFavorite, AssetID: id, After: favorite}
+	})
+}
+
+// BatchSetHidden hides or unhides the selected assets.
+func (s *AssetService) BatchSetHidden(target BatchTarget, hidden bool) (*BatchResult, error) {
+	action := "Unhide"
+	if hidden {
+		action = "Hide"
+	}
+	return s.runBatch(target, action, func(id int64) journalOp {
+		return &flagOp{Flag: flagHidden, AssetID: id, After: hidden}
+	})
+}
+
+// BatchAddToMaterialSet adds the selected assets to a material set.
+func (s *AssetService) BatchAddToMaterialSet(target BatchTarget, setId int64) (*BatchResult, error) {
+	set, err := s.db.GetMaterialSetById(s.ctxOrBackground(), setId)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get material set %d: %w", setId, err)
+	}
+	return s.runBatch(target, "Add to "+set.Name, func(id int64) journalOp {
+		return &materialSetMemberOp{SetID: setId, AssetID: id, Added: true}
+	})
+}
+
+// BatchRemoveFromMaterialSet removes the selected assets from a material set.
+func (s *AssetService) BatchRemoveFromMaterialSet(target BatchTarget, setId int64) (*BatchResult, error) {
+	set, err := s.db.GetMaterialSetById(s.ctxOrBackground(), setId)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get material set %d: %w", setId, err)
+	}
+	return s.runBatch(target, "Remove from "+set.Name, func(id int64) journalOp {
+		return &materialSetMemberOp{SetID: setId, AssetID: id}
+	})
+}
+
+// BatchSetType switches the selected assets between the image and texture types. Assets of
+// other types fail individually.
+func (s *AssetService) BatchSetType(target BatchTarget, newType string) (*BatchResult, error) {
+	if newType != "image" && newType != "texture" {
+		return nil, errors.New("invalid target type: only 'image' and 'texture' are allowed")
+	}
+	return s.runBatch(target, "Change type to "+newType, func(id int64) journalOp {
+		return &assetTypeOp{AssetID: id, After: newType}
+	})
+}
+
+// runBatch applies the operation built by newItem to every selected asset as a single history
+// entry, and emits one assets changed event.
+func (s *AssetService) runBatch(target BatchTarget, action string, newItem func(id int64) journalOp) (*BatchResult, error) {
+	ctx := s.ctxOrBackground()
+	ids, err := s.resolveBatchTarget(ctx, target)
+	if err != nil {
+		return nil, err
+	}
+	return s.executeBatch(ctx, action, ids, newItem)
+}
+
+// executeBatch applies the operations of the given assets in their order.
+func (s *AssetService) executeBatch(ctx context.Context, action string, ids []int64, newItem func(id int64) journalOp) (*BatchResult, error) {
+	result := &BatchResult{Items: make([]BatchItemResult, len(ids))}
+	op := &batchOp{Action: action, results: make([]*BatchItemResult, len(ids))}
+	for i, id := range ids {
+		item := newItem(id)
+		op.ItemKind = item.kind()
+		op.Items = append(op.Items, item)
+		result.Items[i] = BatchItemResult{AssetID: id}
+		op.results[i] = &result.Items[i]
+	}
+	if len(ids) > 0 {
+		if err := s.journal.execute(ctx, op); err != nil {
+			s.logger.Error("Batch operation failed", "action", action, "count", len(ids), "error", err)
+			return nil, err
+		}
+	}
+
+	result.tally()
+	s.logger.Info("Applied batch operation", "action", action, "changed", result.Changed, "unchanged", result.Unchanged, "failed", result.Failed)
+	s.notifier.EmitAssetsChanged(ctx)
+	return result, nil
+}
+
+// tally counts the items by their status.
+func (r *BatchResult) tally() {
+	r.Requested, r.Changed, r.Unchanged, r.Failed = len(r.Items), 0, 0, 0
+	for _, item := range r.Items {
+		switch item.Status {
+		case BatchItemChanged:
+			r.Changed++
+		case BatchItemUnchanged:
+			r.Unchanged++
+		case BatchItemFailed:
+			r.Failed++
+		}
+	}
+}
+
+// resolveBatchTarget returns the IDs of the selected assets, without duplicates.
+func (s *AssetService) resolveBatchTarget(ctx context.Context, target BatchTarget) ([]int64, error) {
+	if target.Filters == nil {
+		ids := make([]int64, 0, len(target.IDs))
+		seen := make(map[int64]bool, len(target.IDs))
+		for _, id := range target.IDs {
+			if !seen[id] {
+				seen[id] = true
+				ids = append(ids, id)
+			}
+		}
+		return ids, nil
+	}
+
+	filters := *target.Filters
+	base := sq.StatementBuilder.PlaceholderFormat(sq.Question).Select("a.id").Distinct().From("assets a")
+	base, _, err := s.applyAssetFilters(ctx, base, &filters)
+	if err != nil {
+		return nil, err
+	}
+	query, args, err := base.OrderBy("a.id").ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("failed to build batch query: %w", err)
+	}
+	rows, err := s.sysDB.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to select batch assets: %w", err)
+	}
+	defer rows.Close()
+
+	var ids []int64
+	for rows.Next() {
+		var id int64
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		ids = append(ids, id)
+	}
+	return ids, rows.Err()
+}
+
+// batchTagNames trims the tag names and drops empty and repeated ones.
+func batchTagNames(tags []string) ([]string, error) {
+	var names []string
+	for _, tag := range tags {
+		tag = strings.TrimSpace(tag)
+		if tag != "" && !slices.Contains(names, tag) {
+			names = append(names, tag)
+		}
+	}
+	if len(names) == 0 {
+		return nil, errors.New("no tags given")
+	}
+	return names, nil
+}
+
+// batchOp applies operations of one kind to many assets as a single history entry. Only the
+// assets it changed are recorded.
+type batchOp struct {
+	Action   string
+	ItemKind string
+	Items    []journalOp
+
+	results []*BatchItemResult // Per item outcome, set only while the batch first runs
+}
+
+type batchPayload struct {
+	Action   string            `json:"action"`
+	ItemKind string            `json:"itemKind"`
+	Items    []json.RawMessage `json:"items"`
+}
+
+func (op *batchOp) MarshalJSON() ([]byte, error) {
+	payload := batchPayload{Action: op.Action, ItemKind: op.ItemKind, Items: make([]json.RawMessage, len(op.Items))}
+	for i, item := range op.Items {
+		raw, err := json.Marshal(item)
+		if err != nil {
+			return nil, err
+		}
+		payload.Items[i] = raw
+	}
+	return json.Marshal(payload)
+}
+
+func (op *batchOp) UnmarshalJSON(data []byte) error {
+	var payload batchPayload
+	if err := json.Unmarshal(data, &payload); err != nil {
+		return err
+	}
+	op.Action, op.ItemKind, op.Items = payload.Action, payload.ItemKind, nil
+	for _, raw := range payload.Items {
+		item, err := newJournalOp(payload.ItemKind)
+		if err != nil {
+			return err
+		}
+		if err := json.Unmarshal(raw, item); err != nil {
+			return err
+		}
+		op.Items = append(op.Items, item)
+	}
+	return nil
+}
+
+func (op *batchOp) kind() string { return opBatch }
+
+func (op *batchOp) describe() string {
+	if len(op.Items) == 1 {
+		return op.Action + " (1 asset)"
+	}
+	return fmt.Sprintf("%s (%d assets)", op.Action, len(op.Items))
+}
+
+// prepare leaves out the assets whose operation cannot start, e.g. because they do not exist.
+func (op *batchOp) prepare(ctx context.Context, q *database.Queries) error {
+	var items []journalOp
+	var results []*BatchItemResult
+	for i, item := range op.Items {
+		if err := item.prepare(ctx, q); err != nil {
+			if errors.Is(err, sql.ErrNoRows) {
+				err = errors.New("asset not found")
+			}
+			op.results[i].Status, op.results[i].Error = BatchItemFailed, err.Error()
+			continue
+		}
+		items = append(items, item)
+		results = append(results, op.results[i])
+	}
+	op.Items, op.results = items, results
+	return nil
+}
+
+func (op *batchOp) apply(ctx context.Context, j *Journal, q *database.Queries, undo bool) (func(), error) {
+	var reverts []func()
+	revert := func() {
+		for i := len(reverts) - 1; i >= 0; i-- {
+			reverts[i]()
+		}
+	}
+
+	var changed []journalOp
+	for n := range op.Items {
+		i := n
+		if undo {
+			i = len(op.Items) - 1 - n
+		}
+		itemRevert, err := op.Items[i].apply(ctx, j, q, undo)
+		if itemRevert != nil {
+			reverts = append(reverts, itemRevert)
+		}
+		status := BatchItemChanged
+		if errors.Is(err, errNoChange) {
+			status = BatchItemUnchanged
+		} else if err != nil {
+			return revert, err
+		} else {
+			changed = append(changed, op.Items[i])
+		}
+		if op.results != nil {
+			op.results[i].Status = status
+		}
+	}
+	if len(changed) == 0 {
+		return revert, errNoChange
+	}
+	if op.results != nil {
+		op.Items = changed
+	}
+	return revert, nil
+}
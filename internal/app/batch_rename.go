@@ -0,0 +1,395 @@
+package app
+
+import (
+	"context"
+	"database/sql"
+	"eclat/internal/database"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// reservedFileNameChars cannot appear in file names on at least one supported platform.
+const reservedFileNameChars = `/\:*?"<>|`
+
+// maxFileNameLength is the longest file name (in bytes) common file systems accept.
+const maxFileNameLength = 255
+
+// Status of an asset in a batch rename preview.
+const (
+	RenameReady     = "ready"
+	RenameUnchanged = "unchanged"
+	RenameCollision = "collision"
+	RenameInvalid   = "invalid"
+)
+
+// BatchRenameRequest describes how batch rename builds the new file names: the template is
+// expanded first, then Find is replaced and the case transformed. Find and the case apply to
+// the name without its extension, which never changes.
+//
+// Template fields are {name} (the old name without extension), {ext} (with the dot), {group}
+// (the material name shared by the maps of a texture set, e.g. "rock" for rock_albedo.png),
+// {channel}, {width}, {height}, {type}, {folder} and {counter}, which can be zero padded as
+// {counter:03}. Separators next to a field without a value are dropped.
+type BatchRenameRequest struct {
+	Target   BatchTarget `json:"target"`
+	Template string      `json:"template"` // Empty keeps the old name, e.g. for find/replace only
+	Find     string      `json:"find"`     // Regular expression
+	Replace  string      `json:"replace"`  // Can refer to groups of Find ($1, ${name})
+	Case     string      `json:"case"`     // "lower", "upper" or "title"; empty keeps the case
+	// CounterStart is the {counter} of the first asset (1 when not set). Assets are counted in
+	// the order of Target.IDs, or by ID when selected by filters.
+	CounterStart *int `json:"counterStart"`
+}
+
+type BatchRenameItem struct {
+	AssetID int64  `json:"assetId"`
+	OldName string `json:"oldName"`
+	NewName string `json:"newName"`
+	Status  string `json:"status"`
+	Reason  string `json:"reason,omitempty"` // Why the name collides or is invalid
+}
+
+// BatchRenamePreview lists the new names without renaming anything. The rename can only run
+// when there are no conflicts.
+type BatchRenamePreview struct {
+	Items     []BatchRenameItem `json:"items"`
+	Ready     int               `json:"ready"`
+	Unchanged int               `json:"unchanged"`
+	Conflicts int               `json:"conflicts"` // Collisions and invalid names
+}
+
+// PreviewBatchRename computes the new names of the selected assets and flags names colliding
+// with each other or with existing files.
+func (s *AssetService) PreviewBatchRename(req BatchRenameRequest) (*BatchRenamePreview, error) {
+	plan, err := s.planBatchRename(s.ctxOrBackground(), req)
+	if err != nil {
+		return nil, err
+	}
+	return plan.preview(), nil
+}
+
+// ApplyBatchRename renames the selected assets as previewed by PreviewBatchRename, as a single
+// history entry. It refuses to run if any name conflicts. If renaming a file fails, the files
+// already renamed get their old names back. Assets keep their group and thumbnail.
+func (s *AssetService) ApplyBatchRename(req BatchRenameRequest) (*BatchResult, error) {
+	ctx := s.ctxOrBackground()
+	plan, err := s.planBatchRename(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	if preview := plan.preview(); preview.Conflicts > 0 {
+		return nil, fmt.Errorf("cannot rename: %d new names collide or are invalid", preview.Conflicts)
+	}
+	return s.executeBatch(ctx, "Rename", plan.order, func(id int64) journalOp {
+		return plan.ops[id]
+	})
+}
+
+// renamePlan is a computed batch rename.
+type renamePlan struct {
+	items []BatchRenameItem
+	ops   map[int64]*renameOp
+	// order runs renames after the ones moving their target out of the way.
+	order []int64
+}
+
+func (p *renamePlan) preview() *BatchRenamePreview {
+	preview := &BatchRenamePreview{Items: p.items}
+	for _, item := range p.items {
+		switch item.Status {
+		case RenameReady:
+			preview.Ready++
+		case RenameUnchanged:
+			preview.Unchanged++
+		default:
+			preview.Conflicts++
+		}
+	}
+	return preview
+}
+
+func (s *AssetService) planBatchRename(ctx context.Context, req BatchRenameRequest) (*renamePlan, error) {
+	template, err := parseRenameTemplate(req.Template)
+	if err != nil {
+		return nil, err
+	}
+	var find *regexp.Regexp
+	if req.Find != "" {
+		if find, err = regexp.Compile(req.Find); err != nil {
+			return nil, fmt.Errorf("invalid find pattern: %w", err)
+		}
+	}
+	transformCase, err := renameCase(req.Case)
+	if err != nil {
+		return nil, err
+	}
+	ids, err := s.resolveBatchTarget(ctx, req.Target)
+	if err != nil {
+		return nil, err
+	}
+	counter := 1
+	if req.CounterStart != nil {
+		counter = *req.CounterStart
+	}
+
+	plan := &renamePlan{items: make([]BatchRenameItem, len(ids)), ops: make(map[int64]*renameOp)}
+	targets := make(map[string][]int) // pathKey of the new path -> items
+	sources := make(map[string]int)   // pathKey of the old path -> item renamed away from it
+	for i, id := range ids {
+		item := &plan.items[i]
+		item.AssetID = id
+		asset, err := s.db.GetAssetById(ctx, id)
+		if err != nil {
+			if !errors.Is(err, sql.ErrNoRows) {
+				return nil, fmt.Errorf("failed to get asset %d: %w", id, err)
+			}
+			item.Status, item.Reason = RenameInvalid, "asset not found"
+			continue
+		}
+
+		ext := filepath.Ext(asset.FileName)
+		expanded := expandRenameTemplate(template, s.renameFields(asset), counter+i)
+		item.OldName = asset.FileName
+		item.NewName = finishRename(expanded, ext, find, req.Replace, transformCase)
+		if reason := invalidFileName(item.NewName, ext); reason != "" {
+			item.Status, item.Reason = RenameInvalid, reason
+			continue
+		}
+		newPath := filepath.Join(filepath.Dir(asset.FilePath), item.NewName)
+		targets[pathKey(newPath)] = append(targets[pathKey(newPath)], i)
+		op := &renameOp{AssetID: id, FromName: asset.FileName, FromPath: asset.FilePath, ToName: item.NewName, ToPath: newPath}
+		plan.ops[id] = op
+		if item.NewName == asset.FileName {
+			item.Status = RenameUnchanged
+			continue
+		}
+		item.Status = RenameReady
+		sources[pathKey(asset.FilePath)] = i
+	}
+
+	after := make(map[int][]int) // Item -> items that must be renamed before it
+	for i := range plan.items {
+		item := &plan.items[i]
+		if item.Status != RenameReady {
+			continue
+		}
+		op := plan.ops[item.AssetID]
+		target := pathKey(op.ToPath)
+		switch {
+		case len(targets[target]) > 1:
+			item.Status, item.Reason = RenameCollision, "another file in the batch gets the same name"
+		case target == pathKey(op.FromPath):
+			// Only the case changes
+		default:
+			if j, ok := sources[target]; ok {
+				after[i] = append(after[i], j)
+			} else if _, err := os.Lstat(op.ToPath); err == nil {
+				item.Status, item.Reason = RenameCollision, "a file with this name already exists"
+			} else if _, err := s.db.GetAssetByPath(ctx, op.ToPath); err == nil {
+				item.Status, item.Reason = RenameCollision, "an asset with this name is already in the library"
+			}
+		}
+	}
+	plan.order = plan.orderRenames(after)
+	return plan, nil
+}
+
+// orderRenames returns the assets to rename (unchanged ones first) so that each file is renamed
+// after the file holding its new name. Renames waiting on each other in a cycle are flagged as
+// collisions.
+func (p *renamePlan) orderRenames(after map[int][]int) []int64 {
+	var order []int64
+	for _, item := range p.items {
+		if item.Status == RenameUnchanged {
+			order = append(order, item.AssetID)
+		}
+	}
+
+	const (
+		visiting = 1
+		done     = 2
+	)
+	state := make(map[int]int)
+	var visit func(i int) bool
+	visit = func(i int) bool {
+		switch state[i] {
+		case visiting:
+			return false
+		case done:
+			return true
+		}
+		state[i] = visiting
+		for _, j := range after[i] {
+			if !visit(j) {
+				p.items[i].Status, p.items[i].Reason = RenameCollision, "the renames form a cycle"
+				return false
+			}
+		}
+		state[i] = done
+		order = append(order, p.items[i].AssetID)
+		return true
+	}
+	for i := range p.items {
+		if p.items[i].Status == RenameReady {
+			visit(i)
+		}
+	}
+	return order
+}
+
+// renameFields returns the values of the template fields for an asset.
+func (s *AssetService) renameFields(asset database.Asset) map[string]string {
+	ext := filepath.Ext(asset.FileName)
+	stem := strings.TrimSuffix(asset.FileName, ext)
+	group := stem
+	if asset.MaterialChannel != "" && s.config != nil {
+		// The matched material is lower-cased; keep the case of the file name where possible
+		if material, _, ok := s.config.MatchMaterialChannel(stem); ok {
+			group = material
+			if len(material) <= len(stem) && strings.EqualFold(stem[:len(material)], material) {
+				group = stem[:len(material)]
+			}
+		}
+	}
+
+	fields := map[string]string{
+		"name":    stem,
+		"ext":     ext,
+		"group":   group,
+		"channel": asset.MaterialChannel,
+		"type":    asset.FileType,
+		"folder":  filepath.Base(filepath.Dir(asset.FilePath)),
+		"width":   "",
+		"height":  "",
+	}
+	if asset.ImageWidth.Valid {
+		fields["width"] = strconv.FormatInt(asset.ImageWidth.Int64, 10)
+	}
+	if asset.ImageHeight.Valid {
+		fields["height"] = strconv.FormatInt(asset.ImageHeight.Int64, 10)
+	}
+	return fields
+}
+
+var renameFieldRe = regexp.MustCompile(`\{(\w+)(?::(\d+))?\}`)
+
+// emptyField marks a field without a value until the separators next to it are dropped.
+const emptyField = "\x00"
+
+var (
+	emptyFieldAtEdgeRe = regexp.MustCompile(`^[_\-. ]*\x00[_\-. ]*|[_\-. ]*\x00[_\-. ]*$`)
+	emptyFieldRe       = regexp.MustCompile(`\x00[_\-. ]*`)
+)
+
+// parseRenameTemplate checks the fields of a template. An empty template keeps the old name.
+func parseRenameTemplate(template string) (string, error) {
+	if strings.TrimSpace(template) == "" {
+		return "{name}{ext}", nil
+	}
+	for _, m := range renameFieldRe.FindAllStringSubmatch(template, -1) {
+		switch m[1] {
+		case "counter":
+		case "name", "ext", "group", "channel", "width", "height", "type", "folder":
+			if m[2] != "" {
+				return "", fmt.Errorf("template field {%s} has no format", m[1])
+			}
+		default:
+			return "", fmt.Errorf("unknown template field {%s}", m[1])
+		}
+	}
+	return template, nil
+}
+
+func expandRenameTemplate(template string, fields map[string]string, counter int) string {
+	return renameFieldRe.ReplaceAllStringFunc(template, func(field string) string {
+		m := renameFieldRe.FindStringSubmatch(field)
+		value := fields[m[1]]
+		if m[1] == "counter" {
+			width, _ := strconv.Atoi(m[2])
+			value = fmt.Sprintf("%0*d", width, counter)
+		}
+		if value == "" {
+			return emptyField
+		}
+		return value
+	})
+}
+
+// finishRename turns an expanded template into the new file name, keeping the extension.
+func finishRename(expanded, ext string, find *regexp.Regexp, replace string, transformCase func(string) string) string {
+	stem := expanded
+	if strings.HasSuffix(strings.ToLower(stem), strings.ToLower(ext)) {
+		stem = stem[:len(stem)-len(ext)]
+	}
+	for strings.Contains(stem, emptyField) {
+		cleaned := emptyFieldAtEdgeRe.ReplaceAllString(stem, "")
+		if cleaned == stem {
+			cleaned = emptyFieldRe.ReplaceAllString(stem, "")
+		}
+		stem = cleaned
+	}
+	if find != nil {
+		stem = find.ReplaceAllString(stem, replace)
+	}
+	return transformCase(stem) + ext
+}
+
+// pathKey returns the key under which paths differing only in case match. Such paths name the
+// same file on the case-insensitive file systems of Windows and macOS, so renames, moves and
+// copies treat them as colliding on every platform.
+func pathKey(path string) string {
+	return strings.ToLower(path)
+}
+
+// invalidFileName returns why a new file name cannot be used, or an empty string.
+func invalidFileName(name, ext string) string {
+	switch {
+	case strings.TrimSpace(strings.TrimSuffix(name, ext)) == "":
+		return "the name is empty"
+	case strings.ContainsAny(name, reservedFileNameChars):
+		return "the name contains reserved characters"
+	case strings.ContainsFunc(name, unicode.IsControl):
+		return "the name contains control characters"
+	case len(name) > maxFileNameLength:
+		return "the name is too long"
+	case strings.HasSuffix(name, ".") || strings.HasSuffix(name, " "):
+		return "the name ends with a dot or a space"
+	}
+	return ""
+}
+
+func renameCase(mode string) (func(string) string, error) {
+	switch mode {
+	case "":
+		return func(s string) string { return s }, nil
+	case "lower":
+		return strings.ToLower, nil
+	case "upper":
+		return strings.ToUpper, nil
+	case "title":
+		return titleCase, nil
+	}
+	return nil, fmt.Errorf("unknown case %q", mode)
+}
+
+// titleCase capitalizes each word of a file name, words being separated by "_", "-", "." or
+// spaces, and lower-cases the rest.
+func titleCase(s string) string {
+	var b strings.Builder
+	start := true
+	for _, r := range s {
+		if start {
+			b.WriteRune(unicode.ToUpper(r))
+		} else {
+			b.WriteRune(unicode.ToLower(r))
+		}
+		start = strings.ContainsRune("_-. ", r)
+	}
+	return b.String()
+}
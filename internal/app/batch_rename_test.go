@@ -0,0 +1,188 @@
+package app
+
+import (
+	"context"
+	"database/sql"
+	"eclat/internal/config"
+	"eclat/internal/database"
+	"os"
+	"path/filepath"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// insertTextureMap tworzy plik mapy tekstury 2048x1024 i jego asset w grupie.
+func insertTextureMap(t *testing.T, q database.Querier, path, channel, groupID string) database.Asset {
+	require.NoError(t, os.WriteFile(path, []byte(filepath.Base(path)), 0644))
+	asset, err := q.CreateAsset(context.Background(), database.CreateAssetParams{
+		FileName:        filepath.Base(path),
+		FilePath:        path,
+		FileType:        "texture",
+		ThumbnailPath:   "/thumbnails/" + groupID + filepath.Base(path) + ".webp",
+		ImageWidth:      sql.NullInt64{Int64: 2048, Valid: true},
+		ImageHeight:     sql.NullInt64{Int64: 1024, Valid: true},
+		GroupID:         groupID,
+		MaterialChannel: channel,
+		LastModified:    time.Now(),
+		LastScanned:     time.Now(),
+	})
+	require.NoError(t, err)
+	return asset
+}
+
+func TestBatchRename_TemplateApplyAndUndo(t *testing.T) {
+	service, queries := setupAssetServiceTest(t)
+	ctx := context.Background()
+	dir := t.TempDir()
+	albedo := insertTextureMap(t, queries, filepath.Join(dir, "Old Rock_Albedo.png"), config.ChannelBaseColor, "G-ROCK")
+	normal := insertTextureMap(t, queries, filepath.Join(dir, "Old Rock_nrm.png"), config.ChannelNormal, "G-ROCK")
+	plain := insertTextureMap(t, queries, filepath.Join(dir, "preview.jpg"), "", "G-PREVIEW")
+
+	req := BatchRenameRequest{
+		Target:   BatchTarget{IDs: []int64{albedo.ID, normal.ID, plain.ID}},
+		Template: "{group}_{channel}_{width}x{height}_{counter:03}{ext}",
+		Find:     `\s+`,
+		Replace:  "-",
+		Case:     "lower",
+	}
+	preview, err := service.PreviewBatchRename(req)
+	require.NoError(t, err)
+	assert.Equal(t, 3, preview.Ready)
+	assert.Zero(t, preview.Conflicts)
+	assert.Equal(t, "old-rock_basecolor_2048x1024_001.png", preview.Items[0].NewName)
+	assert.Equal(t, "old-rock_normal_2048x1024_002.png", preview.Items[1].NewName)
+	assert.Equal(t, "preview_2048x1024_003.jpg", preview.Items[2].NewName, "Pusty kanał nie zostawia podwójnego separatora")
+	assert.FileExists(t, albedo.FilePath, "Podgląd niczego nie zmienia")
+
+	result, err := service.ApplyBatchRename(req)
+	require.NoError(t, err)
+	assert.Equal(t, 3, result.Changed)
+	renamed, err := queries.GetAssetById(ctx, albedo.ID)
+	require.NoError(t, err)
+	assert.Equal(t, filepath.Join(dir, "old-rock_basecolor_2048x1024_001.png"), renamed.FilePath)
+	assert.FileExists(t, renamed.FilePath)
+	assert.NoFileExists(t, albedo.FilePath)
+	assert.Equal(t, "G-ROCK", renamed.GroupID, "Grupa zostaje")
+	assert.Equal(t, albedo.ThumbnailPath, renamed.ThumbnailPath, "Miniatura zostaje")
+
+	entry, err := service.Undo()
+	require.NoError(t, err)
+	assert.Equal(t, "Rename (3 assets)", entry.Description)
+	for _, asset := range []database.Asset{albedo, normal, plain} {
+		assert.FileExists(t, asset.FilePath)
+		restored, err := queries.GetAssetById(ctx, asset.ID)
+		require.NoError(t, err)
+		assert.Equal(t, asset.FileName, restored.FileName)
+	}
+}
+
+func TestBatchRename_Collisions(t *testing.T) {
+	service, queries := setupAssetServiceTest(t)
+	dir := t.TempDir()
+	first := insertTextureMap(t, queries, filepath.Join(dir, "tile_1.png"), "", "G1")
+	second := insertTextureMap(t, queries, filepath.Join(dir, "tile_2.png"), "", "G2")
+	other := insertTextureMap(t, queries, filepath.Join(dir, "other.png"), "", "G3")
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "taken.png"), []byte("taken"), 0644))
+
+	// Ta sama nazwa w partii i istniejący plik
+	preview, err := service.PreviewBatchRename(BatchRenameRequest{
+		Target:   BatchTarget{IDs: []int64{first.ID, second.ID, other.ID}},
+		Template: "{name}{ext}",
+		Find:     `^(tile_\d|other)$`,
+		Replace:  "tile",
+	})
+	require.NoError(t, err)
+	assert.Equal(t, 3, preview.Conflicts)
+	assert.Equal(t, RenameCollision, preview.Items[0].Status)
+	assert.Equal(t, "another file in the batch gets the same name", preview.Items[0].Reason)
+
+	preview, err = service.PreviewBatchRename(BatchRenameRequest{Target: BatchTarget{IDs: []int64{other.ID}}, Template: "taken{ext}"})
+	require.NoError(t, err)
+	assert.Equal(t, "a file with this name already exists", preview.Items[0].Reason)
+	_, err = service.ApplyBatchRename(BatchRenameRequest{Target: BatchTarget{IDs: []int64{other.ID}}, Template: "taken{ext}"})
+	assert.ErrorContains(t, err, "collide")
+
+	// Zamiana nazw w cyklu jest kolizją
+	preview, err = service.PreviewBatchRename(BatchRenameRequest{Target: BatchTarget{IDs: []int64{second.ID, first.ID}}, Template: "tile_{counter}{ext}"})
+	require.NoError(t, err)
+	assert.Equal(t, 2, preview.Conflicts)
+	assert.Equal(t, "the renames form a cycle", preview.Items[0].Reason)
+
+	// Łańcuch zmian nazw wykonuje się od końca
+	start := 2
+	result, err := service.ApplyBatchRename(BatchRenameRequest{
+		Target:       BatchTarget{IDs: []int64{first.ID, second.ID}},
+		Template:     "tile_{counter}{ext}",
+		CounterStart: &start,
+	})
+	require.NoError(t, err)
+	assert.Equal(t, 2, result.Changed)
+	assert.NoFileExists(t, first.FilePath)
+	content, err := os.ReadFile(filepath.Join(dir, "tile_3.png"))
+	require.NoError(t, err)
+	assert.Equal(t, "tile_2.png", string(content))
+
+	preview, err = service.PreviewBatchRename(BatchRenameRequest{Target: BatchTarget{IDs: []int64{other.ID}}, Template: "{nope}"})
+	assert.ErrorContains(t, err, "unknown template field")
+	assert.Nil(t, preview)
+	preview, err = service.PreviewBatchRename(BatchRenameRequest{Target: BatchTarget{IDs: []int64{other.ID}}, Template: "a:b{ext}"})
+	require.NoError(t, err)
+	assert.Equal(t, RenameInvalid, preview.Items[0].Status)
+}
+
+func TestBatchRename_RollbackOnFailure(t *testing.T) {
+	service, queries := setupAssetServiceTest(t)
+	ctx := context.Background()
+	dir := t.TempDir()
+	rock := insertTextureMap(t, queries, filepath.Join(dir, "rock.png"), "", "G1")
+	sky := insertTextureMap(t, queries, filepath.Join(dir, "sky.png"), "", "G2")
+
+	// Druga zmiana nazwy nie może się udać - katalog docelowy nie istnieje
+	ops := map[int64]*renameOp{
+		rock.ID: {AssetID: rock.ID, FromName: "rock.png", FromPath: rock.FilePath, ToName: "stone.png", ToPath: filepath.Join(dir, "stone.png")},
+		sky.ID:  {AssetID: sky.ID, FromName: "sky.png", FromPath: sky.FilePath, ToName: "sky.png", ToPath: filepath.Join(dir, "missing", "sky.png")},
+	}
+	_, err := service.executeBatch(ctx, "Rename", []int64{rock.ID, sky.ID}, func(id int64) journalOp { return ops[id] })
+	require.Error(t, err)
+
+	assert.FileExists(t, rock.FilePath, "Plik wraca do starej nazwy")
+	assert.NoFileExists(t, filepath.Join(dir, "stone.png"))
+	unchanged, err := queries.GetAssetById(ctx, rock.ID)
+	require.NoError(t, err)
+	assert.Equal(t, rock.FilePath, unchanged.FilePath)
+	history, err := service.GetHistory(0)
+	require.NoError(t, err)
+	assert.Empty(t, history)
+}
+
+func TestFinishRename(t *testing.T) {
+	fields := map[string]string{"name": "Rock", "ext": ".png", "channel": ""}
+	tests := []struct {
+		template, caseMode, want string
+	}{
+		{"{channel}_{name}{ext}", "", "Rock.png"},
+		{"{name}_{channel}{ext}", "upper", "ROCK.png"},
+		{"{name}-{channel}-{counter:02}", "", "Rock-07.png"},
+		{"{name} big_stone{ext}", "title", "Rock Big_Stone.png"},
+	}
+	for _, tt := range tests {
+		template, err := parseRenameTemplate(tt.template)
+		require.NoError(t, err)
+		transformCase, err := renameCase(tt.caseMode)
+		require.NoError(t, err)
+		got := finishRename(expandRenameTemplate(template, fields, 7), ".png", nil, "", transformCase)
+		assert.Equal(t, tt.want, got, tt.template)
+	}
+
+	got := finishRename("rock_v2.png", ".png", regexp.MustCompile(`_v(\d+)$`), "-ver$1", func(s string) string { return s })
+	assert.Equal(t, "rock-ver2.png", got)
+
+	_, err := parseRenameTemplate("{name:03}")
+	assert.Error(t, err)
+	_, err = renameCase("sponge")
+	assert.Error(t, err)
+}
@@ -0,0 +1,159 @@
+package app
+
+import (
+	"context"
+	"eclat/internal/database"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBatch_TagsByIDs(t *testing.T) {
+	service, queries := setupAssetServiceTest(t)
+	ctx := context.Background()
+	rock := insertTestAssetWithParams(t, queries, "rock.png", "/tmp/test/rock.png", false, false)
+	sky := insertTestAssetWithParams(t, queries, "sky.png", "/tmp/test/sky.png", false, false)
+	require.NoError(t, service.UpdateTags(rock.ID, []string{"stone"}))
+	require.NoError(t, service.UpdateTags(sky.ID, []string{"blue", "nature"}))
+	notifier := service.notifier.(*MockNotifier)
+	notifier.CallCount = 0
+
+	result, err := service.BatchAddTags(BatchTarget{IDs: []int64{rock.ID, sky.ID, rock.ID, 9999}}, []string{" nature ", "", "scan"})
+	require.NoError(t, err)
+	assert.Equal(t, 3, result.Requested, "Powtórzone ID liczy się raz")
+	assert.Equal(t, 2, result.Changed)
+	assert.Equal(t, 1, result.Failed)
+	require.Len(t, result.Items, 3)
+	assert.Equal(t, BatchItemFailed, result.Items[2].Status)
+	assert.Equal(t, "asset not found", result.Items[2].Error)
+	assert.Equal(t, 1, notifier.CallCount, "Jedno zdarzenie na całą operację")
+
+	tags, err := queries.GetTagsNamesByAssetID(ctx, rock.ID)
+	require.NoError(t, err)
+	assert.ElementsMatch(t, []string{"stone", "nature", "scan"}, tags)
+
+	result, err = service.BatchRemoveTags(BatchTarget{IDs: []int64{rock.ID, sky.ID}}, []string{"stone"})
+	require.NoError(t, err)
+	assert.Equal(t, 1, result.Changed)
+	assert.Equal(t, 1, result.Unchanged)
+
+	history, err := service.GetHistory(0)
+	require.NoError(t, err)
+	assert.Equal(t, "Remove tags stone (1 asset)", history[0].Description)
+	assert.Equal(t, "Add tags nature, scan (2 assets)", history[1].Description)
+
+	// Cofnięcie całej operacji jednym krokiem
+	for range 2 {
+		_, err := service.Undo()
+		require.NoError(t, err)
+	}
+	tags, err = queries.GetTagsNamesByAssetID(ctx, sky.ID)
+	require.NoError(t, err)
+	assert.ElementsMatch(t, []string{"blue", "nature"}, tags)
+	tags, err = queries.GetTagsNamesByAssetID(ctx, rock.ID)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"stone"}, tags)
+
+	_, err = service.BatchAddTags(BatchTarget{IDs: []int64{rock.ID}}, []string{" "})
+	assert.Error(t, err)
+}
+
+func TestBatch_FlagsRatingAndTypeByFilters(t *testing.T) {
+	service, queries := setupAssetServiceTest(t)
+	ctx := context.Background()
+	rock := insertTestAssetWithParams(t, queries, "rock.png", "/tmp/test/rock.png", false, false)
+	granite := insertTestAssetWithParams(t, queries, "granite.png", "/tmp/test/granite.png", false, false)
+	sky := insertTestAssetWithParams(t, queries, "sky.png", "/tmp/test/sky.png", false, false)
+	hidden := insertTestAssetWithParams(t, queries, "rock_hidden.png", "/tmp/test/rock_hidden.png", false, true)
+
+	// Filtr obejmuje wszystkie pasujące assety, niezależnie od paginacji
+	ids, err := service.resolveBatchTarget(ctx, BatchTarget{Filters: &AssetQueryFilters{FileTypes: []string{"image"}, Page: 1, PageSize: 1}})
+	require.NoError(t, err)
+	assert.Equal(t, []int64{rock.ID, granite.ID, sky.ID}, ids, "Ukryte assety nie pasują do filtrów galerii")
+
+	target := BatchTarget{Filters: &AssetQueryFilters{FileTypes: []string{"image"}, RatingRange: []int{0, 0}}}
+	result, err := service.BatchSetRating(target, 3)
+	require.NoError(t, err)
+	assert.Equal(t, 3, result.Changed)
+
+	result, err = service.BatchSetFavorite(BatchTarget{IDs: []int64{rock.ID, granite.ID}}, true)
+	require.NoError(t, err)
+	assert.Equal(t, 2, result.Changed)
+	result, err = service.BatchSetHidden(BatchTarget{Filters: &AssetQueryFilters{OnlyFavorites: true}}, true)
+	require.NoError(t, err)
+	assert.Equal(t, 2, result.Changed)
+	result, err = service.BatchSetType(BatchTarget{IDs: []int64{sky.ID, hidden.ID}}, "texture")
+	require.NoError(t, err)
+	assert.Equal(t, 2, result.Changed)
+
+	updated, err := queries.GetAssetById(ctx, granite.ID)
+	require.NoError(t, err)
+	assert.Equal(t, int64(3), updated.Rating)
+	assert.True(t, updated.IsFavorite.Bool)
+	assert.True(t, updated.IsHidden)
+	updated, err = queries.GetAssetById(ctx, hidden.ID)
+	require.NoError(t, err)
+	assert.Zero(t, updated.Rating)
+	assert.Equal(t, "texture", updated.FileType)
+
+	_, err = service.BatchSetRating(target, 6)
+	assert.Error(t, err)
+	_, err = service.BatchSetType(target, "model")
+	assert.Error(t, err)
+
+	for range 4 {
+		_, err := service.Undo()
+		require.NoError(t, err)
+	}
+	for _, id := range []int64{rock.ID, granite.ID, sky.ID} {
+		restored, err := queries.GetAssetById(ctx, id)
+		require.NoError(t, err)
+		assert.Zero(t, restored.Rating)
+		assert.False(t, restored.IsFavorite.Bool)
+		assert.False(t, restored.IsHidden)
+		assert.Equal(t, "image", restored.FileType)
+	}
+}
+
+func TestBatch_MaterialSets(t *testing.T) {
+	service, queries := setupAssetServiceTest(t)
+	ctx := context.Background()
+	rock := insertTestAssetWithParams(t, queries, "rock.png", "/tmp/test/rock.png", false, false)
+	sky := insertTestAssetWithParams(t, queries, "sky.png", "/tmp/test/sky.png", false, false)
+	set, err := queries.CreateMaterialSet(ctx, database.CreateMaterialSetParams{Name: "Rocks"})
+	require.NoError(t, err)
+	require.NoError(t, service.AddAssetToMaterialSet(set.ID, rock.ID))
+
+	result, err := service.BatchAddToMaterialSet(BatchTarget{IDs: []int64{rock.ID, sky.ID}}, set.ID)
+	require.NoError(t, err)
+	assert.Equal(t, 1, result.Changed)
+	assert.Equal(t, 1, result.Unchanged)
+
+	ids, err := service.resolveBatchTarget(ctx, BatchTarget{Filters: &AssetQueryFilters{CollectionID: &set.ID}})
+	require.NoError(t, err)
+	assert.Equal(t, []int64{rock.ID, sky.ID}, ids)
+
+	result, err = service.BatchRemoveFromMaterialSet(BatchTarget{Filters: &AssetQueryFilters{CollectionID: &set.ID}}, set.ID)
+	require.NoError(t, err)
+	assert.Equal(t, 2, result.Changed)
+
+	entry, err := service.Undo()
+	require.NoError(t, err)
+	assert.Equal(t, "Remove from Rocks (2 assets)", entry.Description)
+	setIDs, err := queries.ListMaterialSetIDsByAsset(ctx, sky.ID)
+	require.NoError(t, err)
+	assert.Equal(t, []int64{set.ID}, setIDs)
+
+	// Nic do zmiany - brak wpisu w historii
+	result, err = service.BatchAddToMaterialSet(BatchTarget{IDs: []int64{rock.ID, sky.ID}}, set.ID)
+	require.NoError(t, err)
+	assert.Equal(t, 2, result.Unchanged)
+	history, err := service.GetHistory(0)
+	require.NoError(t, err)
+	assert.Equal(t, "Remove from Rocks (2 assets)", history[0].Description)
+	assert.True(t, history[0].Undone)
+
+	_, err = service.BatchAddToMaterialSet(BatchTarget{IDs: []int64{rock.ID}}, 9999)
+	assert.Error(t, err)
+}
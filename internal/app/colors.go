@@ -0,0 +1,103 @@
+package app
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/lucasb-eyer/go-colorful"
+)
+
+const (
+	// DefaultColorWeightThreshold is the minimal share of the image a palette color needs to
+	// count as "the asset is red". Without it every asset with a red speck would match.
+	DefaultColorWeightThreshold = 0.15
+	// DefaultColorTolerance is the CIE76 ΔE used by hex color search when the caller passes 0.
+	// Around 10 the colors still read as "the same" to most people.
+	DefaultColorTolerance = 10.0
+	// MaxColorTolerance is the largest accepted ΔE (black vs white).
+	MaxColorTolerance = 100.0
+)
+
+// AssetColor is one of the main colors of an asset, as shown in the details panel.
+type AssetColor struct {
+	Hex         string  `json:"hex"`
+	PaletteName string  `json:"paletteName"`
+	PaletteHex  string  `json:"paletteHex"`
+	Weight      float64 `json:"weight"`
+}
+
+// ColorSearchFilter finds assets having a significant color close to an arbitrary hex value.
+type ColorSearchFilter struct {
+	Hex       string  `json:"hex"`
+	Tolerance float64 `json:"tolerance"` // CIE76 ΔE, 0 means DefaultColorTolerance
+}
+
+// colorWeightThreshold returns the threshold from the filters or the default one.
+func colorWeightThreshold(filters *AssetQueryFilters) float64 {
+	if filters == nil || filters.ColorWeightThreshold <= 0 {
+		return DefaultColorWeightThreshold
+	}
+	return filters.ColorWeightThreshold
+}
+
+// paletteColorCondition matches assets having one of the palette colors as a significant color.
+// Assets scanned before palettes were extracted only have assets.dominant_color, so it is
+// still checked as a fallback.
+func paletteColorCondition(paletteHexes []string, threshold float64) sq.Sqlizer {
+	placeholders := sq.Placeholders(len(paletteHexes))
+	args := make([]interface{}, 0, len(paletteHexes)*2+1)
+	for _, h := range paletteHexes {
+		args = append(args, strings.ToUpper(h))
+	}
+	args = append(args, threshold)
+	for _, h := range paletteHexes {
+		args = append(args, strings.ToUpper(h))
+	}
+	return sq.Expr(fmt.Sprintf(
+		"(EXISTS (SELECT 1 FROM asset_colors ac WHERE ac.asset_id = a.id AND ac.palette_hex IN (%s) AND ac.weight >= ?) "+
+			"OR (a.dominant_color COLLATE NOCASE IN (%s) AND NOT EXISTS (SELECT 1 FROM asset_colors acx WHERE acx.asset_id = a.id)))",
+		placeholders, placeholders), args...)
+}
+
+// hexColorCondition matches assets having a significant color within tolerance (ΔE) of hex.
+// Distances are compared squared, in the go-colorful Lab scale (ΔE / 100).
+func hexColorCondition(hex string, tolerance, threshold float64) (sq.Sqlizer, error) {
+	c, err := colorful.Hex(hex)
+	if err != nil {
+		return nil, fmt.Errorf("invalid color %q (expected #RRGGBB)", hex)
+	}
+	if tolerance <= 0 {
+		tolerance = DefaultColorTolerance
+	}
+	if tolerance > MaxColorTolerance {
+		return nil, fmt.Errorf("color tolerance must be between 0 and %.0f", MaxColorTolerance)
+	}
+	l, a, b := c.Lab()
+	maxDist := tolerance / 100
+	return sq.Expr(
+		"EXISTS (SELECT 1 FROM asset_colors ac WHERE ac.asset_id = a.id AND ac.weight >= ? "+
+			"AND (ac.lab_l - ?) * (ac.lab_l - ?) + (ac.lab_a - ?) * (ac.lab_a - ?) + (ac.lab_b - ?) * (ac.lab_b - ?) <= ?)",
+		threshold, l, l, a, a, b, b, maxDist*maxDist,
+	), nil
+}
+
+// getAssetColors loads the palette of an asset, most dominant color first.
+func (s *AssetService) getAssetColors(ctx context.Context, assetID int64) []AssetColor {
+	rows, err := s.db.ListAssetColors(ctx, assetID)
+	if err != nil {
+		s.logger.Error("Failed to fetch asset colors", "id", assetID, "error", err)
+		return nil
+	}
+	colors := make([]AssetColor, 0, len(rows))
+	for _, r := range rows {
+		colors = append(colors, AssetColor{
+			Hex:         r.Hex,
+			PaletteName: r.PaletteName,
+			PaletteHex:  r.PaletteHex,
+			Weight:      r.Weight,
+		})
+	}
+	return colors
+}
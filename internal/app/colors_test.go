@@ -0,0 +1,85 @@
+package app
+
+import (
+	"context"
+	"eclat/internal/database"
+	"testing"
+
+	"github.com/lucasb-eyer/go-colorful"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAssetService_GetAssets_ColorFilters(t *testing.T) {
+	service, queries := setupAssetServiceTest(t)
+	ctx := context.Background()
+
+	type swatch struct {
+		hex, paletteHex string
+		weight          float64
+	}
+	storeColors := func(id int64, colors ...swatch) {
+		for i, c := range colors {
+			parsed, err := colorful.Hex(c.hex)
+			assert.NoError(t, err)
+			l, a, b := parsed.Lab()
+			err = queries.InsertAssetColor(ctx, database.InsertAssetColorParams{
+				AssetID: id, Position: int64(i), Hex: c.hex, PaletteHex: c.paletteHex,
+				Weight: c.weight, LabL: l, LabA: a, LabB: b,
+			})
+			assert.NoError(t, err)
+		}
+	}
+
+	brick := insertTestAssetWithParams(t, queries, "brick.png", "/lib/brick.png", false, false)
+	rust := insertTestAssetWithParams(t, queries, "rust.png", "/lib/rust.png", false, false)
+	bark := insertTestAssetWithParams(t, queries, "bark.png", "/lib/bark.png", false, false)
+	legacy := insertTestAssetWithParams(t, queries, "legacy.png", "/lib/legacy.png", false, false)
+
+	storeColors(brick.ID, swatch{"#E01010", "#FF0000", 0.6}, swatch{"#8A4A20", "#8B4513", 0.4})
+	storeColors(rust.ID, swatch{"#F0A010", "#FFA500", 0.9}, swatch{"#F01010", "#FF0000", 0.1})
+	storeColors(bark.ID, swatch{"#7C5432", "#8B4513", 0.8}, swatch{"#303030", "#404040", 0.2})
+	// Asset przeskanowany przed wprowadzeniem palet - tylko dominant_color
+	_, err := service.sysDB.Exec("UPDATE assets SET dominant_color = '#FF0000' WHERE id = ?", legacy.ID)
+	assert.NoError(t, err)
+
+	search := func(filters AssetQueryFilters) []int64 {
+		filters.Page, filters.PageSize, filters.SortOption = 1, 10, "filename"
+		res, err := service.GetAssets(filters)
+		assert.NoError(t, err)
+		if res == nil {
+			return nil
+		}
+		var ids []int64
+		for _, item := range res.Items {
+			ids = append(ids, item.ID)
+		}
+		return ids
+	}
+
+	assert.Equal(t, []int64{brick.ID, legacy.ID}, search(AssetQueryFilters{Colors: []string{"#FF0000"}}),
+		"A 10% red speck does not make rust red")
+	assert.Equal(t, []int64{brick.ID, legacy.ID, rust.ID}, search(AssetQueryFilters{Colors: []string{"#FF0000"}, ColorWeightThreshold: 0.05}))
+	assert.Equal(t, []int64{bark.ID, brick.ID}, search(AssetQueryFilters{Colors: []string{"#8b4513"}}), "Secondary colors match too")
+
+	assert.Equal(t, []int64{bark.ID}, search(AssetQueryFilters{ColorSearch: &ColorSearchFilter{Hex: "#7A5230"}}))
+	assert.Equal(t, []int64{bark.ID, brick.ID}, search(AssetQueryFilters{ColorSearch: &ColorSearchFilter{Hex: "#7A5230", Tolerance: 20}}))
+
+	_, err = service.GetAssets(AssetQueryFilters{Page: 1, PageSize: 10, ColorSearch: &ColorSearchFilter{Hex: "brown"}})
+	assert.Error(t, err)
+
+	t.Run("Query language", func(t *testing.T) {
+		assert.Equal(t, []int64{brick.ID, legacy.ID}, search(AssetQueryFilters{Query: "color:red"}))
+		assert.Equal(t, []int64{bark.ID}, search(AssetQueryFilters{Query: "color:#7A5230"}))
+		assert.Equal(t, []int64{bark.ID, brick.ID}, search(AssetQueryFilters{Query: "color:#7A5230~20"}))
+		assert.NotNil(t, service.ValidateSearchQuery("color:#7A5230~500"))
+	})
+
+	t.Run("Details expose the palette", func(t *testing.T) {
+		details, err := service.GetAssetById(brick.ID)
+		assert.NoError(t, err)
+		if assert.Len(t, details.Colors, 2) {
+			assert.Equal(t, "#E01010", details.Colors[0].Hex)
+			assert.Equal(t, 0.6, details.Colors[0].Weight)
+		}
+	})
+}
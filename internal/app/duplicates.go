@@ -0,0 +1,249 @@
+package app
+
+import (
+	"cmp"
+	"database/sql"
+	"eclat/internal/database"
+	"eclat/internal/scanner"
+	"fmt"
+	"os"
+	"slices"
+	"time"
+)
+
+// Duplicate resolution actions, applied to every copy except the kept one.
+const (
+	DedupActionTrash    = "trash"    // Move the copies to the trash and remove them from the library
+	DedupActionHardlink = "hardlink" // Replace the copies with hardlinks to the kept file
+	DedupActionHide     = "hide"     // Only hide the copies in the library
+)
+
+// DuplicateCopy is one of the assets sharing the same content.
+type DuplicateCopy struct {
+	ID            int64     `json:"id"`
+	FileName      string    `json:"fileName"`
+	FilePath      string    `json:"filePath"`
+	ThumbnailPath string    `json:"thumbnailPath"`
+	GroupID       string    `json:"groupId"`
+	DateAdded     time.Time `json:"dateAdded"`
+	Linked        bool      `json:"linked"` // Same file on disk as an earlier copy (hardlink)
+}
+
+// DuplicateSet groups the visible assets with the same file hash, oldest first.
+type DuplicateSet struct {
+	FileHash    string          `json:"fileHash"`
+	FileSize    int64           `json:"fileSize"`
+	Copies      []DuplicateCopy `json:"copies"`
+	WastedBytes int64           `json:"wastedBytes"` // Disk space taken by all but one copy; hardlinks take none
+}
+
+// DuplicateReport lists the duplicate sets, most wasted space first.
+type DuplicateReport struct {
+	Sets             []DuplicateSet `json:"sets"`
+	TotalWastedBytes int64          `json:"totalWastedBytes"`
+}
+
+// DuplicateResolution selects the copy of a set to keep.
+type DuplicateResolution struct {
+	FileHash string `json:"fileHash"`
+	KeepID   int64  `json:"keepId"` // 0 keeps the oldest copy
+}
+
+// DedupRequest resolves duplicate sets with one of the DedupAction* actions.
+type DedupRequest struct {
+	Action      string                `json:"action"`
+	Resolutions []DuplicateResolution `json:"resolutions"`
+	DryRun      bool                  `json:"dryRun"` // Only report what would change
+}
+
+// DedupChange is the action taken (or planned) for one redundant copy.
+type DedupChange struct {
+	FileHash   string `json:"fileHash"`
+	KeepID     int64  `json:"keepId"`
+	KeepPath   string `json:"keepPath"`
+	AssetID    int64  `json:"assetId"`
+	FilePath   string `json:"filePath"`
+	FreedBytes int64  `json:"freedBytes"`
+	Error      string `json:"error,omitempty"` // Why this copy could not be resolved
+}
+
+// DedupResult summarizes a ResolveDuplicates call.
+type DedupResult struct {
+	Action     string        `json:"action"`
+	DryRun     bool          `json:"dryRun"`
+	Changes    []DedupChange `json:"changes"`
+	FreedBytes int64         `json:"freedBytes"`
+	Failed     int           `json:"failed"`
+}
+
+// GetDuplicates lists sets of visible assets with identical content (the same file hash),
+// regardless of their groups. Sets whose copies are already hardlinks of one file are left out.
+func (s *AssetService) GetDuplicates() (*DuplicateReport, error) {
+	rows, err := s.db.ListDuplicateAssets(s.ctxOrBackground())
+	if err != nil {
+		s.logger.Error("Failed to list duplicates", "error", err)
+		return nil, err
+	}
+
+	report := &DuplicateReport{Sets: []DuplicateSet{}}
+	for start := 0; start < len(rows); {
+		end := start + 1
+		for end < len(rows) && rows[end].FileHash == rows[start].FileHash {
+			end++
+		}
+		set := newDuplicateSet(rows[start:end])
+		start = end
+
+		if set.WastedBytes > 0 {
+			report.Sets = append(report.Sets, set)
+			report.TotalWastedBytes += set.WastedBytes
+		}
+	}
+	slices.SortStableFunc(report.Sets, func(a, b DuplicateSet) int {
+		return cmp.Compare(b.WastedBytes, a.WastedBytes)
+	})
+	return report, nil
+}
+
+func newDuplicateSet(rows []database.ListDuplicateAssetsRow) DuplicateSet {
+	set := DuplicateSet{FileHash: rows[0].FileHash.String, FileSize: rows[0].FileSize}
+	var files []os.FileInfo
+	for _, r := range rows {
+		c := DuplicateCopy{
+			ID:            r.ID,
+			FileName:      r.FileName,
+			FilePath:      r.FilePath,
+			ThumbnailPath: r.ThumbnailPath,
+			GroupID:       r.GroupID,
+			DateAdded:     r.DateAdded,
+		}
+		// Files gone from disk take no space; the next scan removes them.
+		if info, err := os.Stat(r.FilePath); err == nil {
+			c.Linked = slices.ContainsFunc(files, func(f os.FileInfo) bool { return os.SameFile(f, info) })
+			if !c.Linked {
+				files = append(files, info)
+			}
+		}
+		set.Copies = append(set.Copies, c)
+	}
+	if len(files) > 1 {
+		set.WastedBytes = set.FileSize * int64(len(files)-1)
+	}
+	return set
+}
+
+// ResolveDuplicates keeps one copy of each given duplicate set and trashes, hardlinks or hides
+// the others. Before files are touched, the kept copy (and for hardlinks every replaced copy)
+// is hashed again to make sure it still has the set's content. Copies that fail are reported
+// in the result; the rest are still resolved.
+func (s *AssetService) ResolveDuplicates(req DedupRequest) (*DedupResult, error) {
+	switch req.Action {
+	case DedupActionTrash, DedupActionHardlink, DedupActionHide:
+	default:
+		return nil, fmt.Errorf("unknown duplicate action %q", req.Action)
+	}
+	ctx := s.ctxOrBackground()
+
+	result := &DedupResult{Action: req.Action, DryRun: req.DryRun, Changes: []DedupChange{}}
+	applied := false
+	for _, res := range req.Resolutions {
+		copies, err := s.db.ListAssetsByHash(ctx, sql.NullString{String: res.FileHash, Valid: true})
+		if err != nil {
+			return nil, fmt.Errorf("failed to load duplicates of %s: %w", res.FileHash, err)
+		}
+		if len(copies) < 2 {
+			continue
+		}
+		keepIdx := 0
+		if res.KeepID != 0 {
+			keepIdx = slices.IndexFunc(copies, func(c database.ListAssetsByHashRow) bool { return c.ID == res.KeepID })
+			if keepIdx < 0 {
+				return nil, fmt.Errorf("asset %d is not a copy of %s", res.KeepID, res.FileHash)
+			}
+		}
+		keep := copies[keepIdx]
+		keepInfo, keepErr := os.Stat(keep.FilePath)
+		if keepErr == nil && !req.DryRun && req.Action != DedupActionHide {
+			keepErr = verifyFileHash(keep.FilePath, res.FileHash)
+		}
+
+		for i, c := range copies {
+			if i == keepIdx {
+				continue
+			}
+			change := DedupChange{FileHash: res.FileHash, KeepID: keep.ID, KeepPath: keep.FilePath, AssetID: c.ID, FilePath: c.FilePath}
+			linked := false
+			if info, err := os.Stat(c.FilePath); err == nil && keepInfo != nil {
+				linked = os.SameFile(keepInfo, info)
+			}
+			if linked && req.Action == DedupActionHardlink {
+				continue // Already resolved
+			}
+			if !linked && req.Action != DedupActionHide {
+				change.FreedBytes = c.FileSize
+			}
+
+			// A missing kept copy fails the dry run as well.
+			err := keepErr
+			if err == nil && !req.DryRun {
+				err = s.resolveDuplicate(req.Action, keep.FilePath, c)
+			}
+			if err != nil {
+				s.logger.Error("Failed to resolve duplicate", "action", req.Action, "path", c.FilePath, "error", err)
+				change.Error = err.Error()
+				change.FreedBytes = 0
+				result.Failed++
+			} else if !req.DryRun {
+				applied = true
+			}
+			result.FreedBytes += change.FreedBytes
+			result.Changes = append(result.Changes, change)
+		}
+	}
+
+	if applied {
+		s.logger.Info("Resolved duplicates", "action", req.Action, "changes", len(result.Changes), "failed", result.Failed, "freed_bytes", result.FreedBytes)
+		s.notifier.EmitAssetsChanged(ctx)
+	}
+	return result, nil
+}
+
+func (s *AssetService) resolveDuplicate(action, keepPath string, c database.ListAssetsByHashRow) error {
+	switch action {
+	case DedupActionTrash:
+		return s.deleteAssetPermanently(c.ID)
+	case DedupActionHide:
+		return s.db.SetAssetHidden(s.ctxOrBackground(), database.SetAssetHiddenParams{IsHidden: true, ID: c.ID})
+	default:
+		if err := verifyFileHash(c.FilePath, c.FileHash.String); err != nil {
+			return err
+		}
+		return replaceWithHardlink(keepPath, c.FilePath)
+	}
+}
+
+// verifyFileHash checks that a file still has the content recorded in the library.
+func verifyFileHash(path, hash string) error {
+	current, err := scanner.CalculateFileHash(path, 0)
+	if err != nil {
+		return fmt.Errorf("failed to hash %s: %w", path, err)
+	}
+	if current != hash {
+		return fmt.Errorf("%s changed since the last scan", path)
+	}
+	return nil
+}
+
+// replaceWithHardlink swaps path for a hardlink to target. The link is created next to path
+// and renamed over it, so path is never missing.
+func replaceWithHardlink(target, path string) error {
+	tmp := path + ".eclat-link"
+	if err := os.Link(target, tmp); err != nil {
+		return fmt.Errorf("failed to create hardlink: %w", err)
+	}
+	if err := os.Rename(tmp, path); err != nil {
+		_ = os.Remove(tmp)
+		return fmt.Errorf("failed to replace %s: %w", path, err)
+	}
+	return nil
+}
@@ -0,0 +1,142 @@
+package app
+
+import (
+	"context"
+	"database/sql"
+	"eclat/internal/database"
+	"eclat/internal/scanner"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// insertDuplicateFile zapisuje plik na dysku i dodaje go do bazy z prawdziwym hashem.
+func insertDuplicateFile(t *testing.T, q database.Querier, path, content, groupID string) database.Asset {
+	require.NoError(t, os.WriteFile(path, []byte(content), 0644))
+	hash, err := scanner.CalculateFileHash(path, 0)
+	require.NoError(t, err)
+	asset, err := q.CreateAsset(context.Background(), database.CreateAssetParams{
+		FileName:     filepath.Base(path),
+		FilePath:     path,
+		FileType:     "image",
+		FileSize:     int64(len(content)),
+		FileHash:     sql.NullString{String: hash, Valid: true},
+		GroupID:      groupID,
+		LastModified: time.Now(),
+		LastScanned:  time.Now(),
+	})
+	require.NoError(t, err)
+	return asset
+}
+
+func TestAssetService_GetDuplicates(t *testing.T) {
+	service, queries := setupAssetServiceTest(t)
+	dir := t.TempDir()
+	rock := insertDuplicateFile(t, queries, filepath.Join(dir, "rock.png"), "rock-bytes", "G1")
+	insertDuplicateFile(t, queries, filepath.Join(dir, "rock_copy.png"), "rock-bytes", "G1")
+	insertDuplicateFile(t, queries, filepath.Join(dir, "stone.png"), "rock-bytes", "G2")
+	insertDuplicateFile(t, queries, filepath.Join(dir, "big.png"), "big-big-big-bytes", "G3")
+	insertDuplicateFile(t, queries, filepath.Join(dir, "big_v2.png"), "big-big-big-bytes", "G3")
+	insertDuplicateFile(t, queries, filepath.Join(dir, "unique.png"), "unique", "G4")
+
+	report, err := service.GetDuplicates()
+	require.NoError(t, err)
+	require.Len(t, report.Sets, 2)
+	assert.Equal(t, int64(20), report.Sets[0].WastedBytes, "3 kopie po 10 bajtów")
+	assert.Equal(t, int64(17), report.Sets[1].WastedBytes)
+	assert.Equal(t, int64(37), report.TotalWastedBytes)
+	assert.Equal(t, rock.ID, report.Sets[0].Copies[0].ID, "Najstarsza kopia jest pierwsza")
+	assert.Equal(t, rock.FileHash.String, report.Sets[0].FileHash)
+
+	// Ukryte kopie nie są już duplikatami
+	require.NoError(t, service.SetAssetHidden(rock.ID, true))
+	report, err = service.GetDuplicates()
+	require.NoError(t, err)
+	assert.Equal(t, int64(27), report.TotalWastedBytes)
+}
+
+func TestAssetService_ResolveDuplicates(t *testing.T) {
+	service, queries := setupAssetServiceTest(t)
+	dir := t.TempDir()
+	keep := insertDuplicateFile(t, queries, filepath.Join(dir, "rock.png"), "rock-bytes", "G1")
+	copy1 := insertDuplicateFile(t, queries, filepath.Join(dir, "rock_copy.png"), "rock-bytes", "G1")
+	copy2 := insertDuplicateFile(t, queries, filepath.Join(dir, "stone.png"), "rock-bytes", "G2")
+	req := DedupRequest{Action: DedupActionHardlink, Resolutions: []DuplicateResolution{{FileHash: keep.FileHash.String}}, DryRun: true}
+
+	_, err := service.ResolveDuplicates(DedupRequest{Action: "shred"})
+	assert.Error(t, err)
+	_, err = service.ResolveDuplicates(DedupRequest{Action: DedupActionHide, Resolutions: []DuplicateResolution{{FileHash: keep.FileHash.String, KeepID: 999}}})
+	assert.Error(t, err, "Zachowywana kopia musi należeć do zestawu")
+
+	// Dry run niczego nie zmienia
+	preview, err := service.ResolveDuplicates(req)
+	require.NoError(t, err)
+	assert.True(t, preview.DryRun)
+	assert.Len(t, preview.Changes, 2)
+	assert.Equal(t, int64(20), preview.FreedBytes)
+	keepInfo, err := os.Stat(keep.FilePath)
+	require.NoError(t, err)
+	copyInfo, err := os.Stat(copy1.FilePath)
+	require.NoError(t, err)
+	assert.False(t, os.SameFile(keepInfo, copyInfo))
+
+	// Zmieniony plik nie jest zastępowany
+	require.NoError(t, os.WriteFile(copy2.FilePath, []byte("edited-rock"), 0644))
+	req.DryRun = false
+	result, err := service.ResolveDuplicates(req)
+	require.NoError(t, err)
+	assert.Equal(t, 1, result.Failed)
+	assert.Equal(t, int64(10), result.FreedBytes)
+	assert.NotEmpty(t, result.Changes[1].Error)
+
+	copyInfo, err = os.Stat(copy1.FilePath)
+	require.NoError(t, err)
+	assert.True(t, os.SameFile(keepInfo, copyInfo), "Kopia jest teraz twardym dowiązaniem")
+	content, err := os.ReadFile(copy2.FilePath)
+	require.NoError(t, err)
+	assert.Equal(t, "edited-rock", string(content))
+
+	// Zestaw z dowiązaniem znika z raportu, ponowne dowiązanie nic nie robi
+	require.NoError(t, os.WriteFile(copy2.FilePath, []byte("rock-bytes"), 0644))
+	result, err = service.ResolveDuplicates(req)
+	require.NoError(t, err)
+	assert.Len(t, result.Changes, 1)
+	report, err := service.GetDuplicates()
+	require.NoError(t, err)
+	assert.Empty(t, report.Sets)
+}
+
+func TestAssetService_ResolveDuplicates_TrashAndHide(t *testing.T) {
+	service, queries := setupAssetServiceTest(t)
+	dir := t.TempDir()
+	rock := insertDuplicateFile(t, queries, filepath.Join(dir, "rock.png"), "rock-bytes", "G1")
+	rockCopy := insertDuplicateFile(t, queries, filepath.Join(dir, "rock_copy.png"), "rock-bytes", "G1")
+	sky := insertDuplicateFile(t, queries, filepath.Join(dir, "sky.png"), "sky-bytes", "G2")
+	skyCopy := insertDuplicateFile(t, queries, filepath.Join(dir, "sky_copy.png"), "sky-bytes", "G2")
+
+	result, err := service.ResolveDuplicates(DedupRequest{
+		Action:      DedupActionTrash,
+		Resolutions: []DuplicateResolution{{FileHash: rock.FileHash.String, KeepID: rockCopy.ID}},
+	})
+	require.NoError(t, err)
+	assert.Zero(t, result.Failed)
+	assert.NoFileExists(t, rock.FilePath)
+	assert.FileExists(t, rockCopy.FilePath)
+	_, err = queries.GetAssetById(context.Background(), rock.ID)
+	assert.ErrorIs(t, err, sql.ErrNoRows)
+
+	result, err = service.ResolveDuplicates(DedupRequest{
+		Action:      DedupActionHide,
+		Resolutions: []DuplicateResolution{{FileHash: sky.FileHash.String}},
+	})
+	require.NoError(t, err)
+	assert.Zero(t, result.FreedBytes, "Ukrycie nie zwalnia miejsca")
+	hidden, err := queries.GetAssetById(context.Background(), skyCopy.ID)
+	require.NoError(t, err)
+	assert.True(t, hidden.IsHidden)
+	assert.FileExists(t, skyCopy.FilePath)
+}
@@ -0,0 +1,239 @@
+package app
+
+import (
+	"context"
+	"database/sql"
+	"eclat/internal/database"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"sync"
+	"time"
+)
+
+const (
+	maxJournalEntries   = 500 // Older operations can no longer be undone
+	defaultHistoryLimit = 50
+)
+
+// ErrHistoryConflict is returned when an operation cannot be undone or redone because the
+// library (or the file on disk) changed since. The operation stays in the history.
+var ErrHistoryConflict = errors.New("the library changed since this operation")
+
+// errNoChange is returned by an operation whose target state is already in place.
+var errNoChange = errors.New("nothing to change")
+
+// HistoryEntry is an operation in the undo history.
+type HistoryEntry struct {
+	ID          int64     `json:"id"`
+	Kind        string    `json:"kind"`
+	Description string    `json:"description"`
+	Undone      bool      `json:"undone"` // Undone operations can be redone, newest undone last
+	CreatedAt   time.Time `json:"createdAt"`
+}
+
+// journalOp is a library mutation that can be applied in both directions. Its exported fields
+// are stored as the journal payload.
+type journalOp interface {
+	kind() string
+	describe() string
+	// prepare captures the state the operation starts from, before it is applied the first time.
+	prepare(ctx context.Context, q *database.Queries) error
+	// apply moves the library to the state after the operation, or back before it when undo is
+	// set. It returns errNoChange if that state is already in place, and ErrHistoryConflict if
+	// the library is in neither state. Operations touching files return a func reverting them,
+	// used when the transaction fails afterwards.
+	apply(ctx context.Context, j *Journal, q *database.Queries, undo bool) (revert func(), err error)
+}
+
+// Journal runs library mutations as operations recorded in the operation journal, so they can
+// be undone and redone. Each operation is applied in the same transaction as its journal entry.
+type Journal struct {
+	sysDB  *sql.DB
+	logger *slog.Logger
+	mu     sync.Mutex
+}
+
+func NewJournal(sysDB *sql.DB, logger *slog.Logger) *Journal {
+	return &Journal{sysDB: sysDB, logger: logger}
+}
+
+// execute applies a new operation and records it. Operations undone before are dropped, as
+// they can no longer be redone on top of it.
+func (j *Journal) execute(ctx context.Context, op journalOp) error {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+
+	return j.inTx(ctx, func(q *database.Queries) (func(), error) {
+		if err := op.prepare(ctx, q); err != nil {
+			return nil, err
+		}
+		revert, err := op.apply(ctx, j, q, false)
+		if errors.Is(err, errNoChange) {
+			return nil, nil
+		}
+		if err != nil {
+			return revert, err
+		}
+
+		payload, err := json.Marshal(op)
+		if err != nil {
+			return revert, err
+		}
+		if err := q.DeleteUndoneJournalEntries(ctx); err != nil {
+			return revert, err
+		}
+		_, err = q.CreateJournalEntry(ctx, database.CreateJournalEntryParams{
+			Kind:        op.kind(),
+			Description: op.describe(),
+			Payload:     string(payload),
+		})
+		if err != nil {
+			return revert, fmt.Errorf("failed to record operation: %w", err)
+		}
+		return revert, q.PruneJournal(ctx, maxJournalEntries)
+	})
+}
+
+// step undoes the most recent operation or redoes the oldest undone one. It returns nil when
+// there is nothing to undo or redo.
+func (j *Journal) step(ctx context.Context, undo bool) (*HistoryEntry, error) {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+
+	var entry *HistoryEntry
+	err := j.inTx(ctx, func(q *database.Queries) (func(), error) {
+		row, err := q.GetFirstUndoneJournalEntry(ctx)
+		if undo {
+			row, err = q.GetLastActiveJournalEntry(ctx)
+		}
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		op, err := decodeJournalOp(row)
+		if err != nil {
+			return nil, err
+		}
+		revert, err := op.apply(ctx, j, q, undo)
+		if err != nil && !errors.Is(err, errNoChange) {
+			return revert, fmt.Errorf("cannot %s %q: %w", stepVerb(undo), row.Description, err)
+		}
+		if err := q.SetJournalEntryUndone(ctx, database.SetJournalEntryUndoneParams{IsUndone: undo, ID: row.ID}); err != nil {
+			return revert, err
+		}
+		entry = toHistoryEntry(row)
+		entry.Undone = undo
+		return revert, nil
+	})
+	if err != nil {
+		j.logger.Error("Failed to "+stepVerb(undo)+" operation", "error", err)
+		return nil, err
+	}
+	if entry != nil {
+		j.logger.Info("Applied history step", "action", stepVerb(undo), "id", entry.ID, "kind", entry.Kind)
+	}
+	return entry, nil
+}
+
+func (j *Journal) history(ctx context.Context, limit int) ([]HistoryEntry, error) {
+	if limit <= 0 {
+		limit = defaultHistoryLimit
+	}
+	rows, err := database.New(j.sysDB).ListJournalEntries(ctx, int64(limit))
+	if err != nil {
+		return nil, err
+	}
+	result := make([]HistoryEntry, len(rows))
+	for i, row := range rows {
+		result[i] = *toHistoryEntry(row)
+	}
+	return result, nil
+}
+
+func (j *Journal) clear(ctx context.Context) error {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	return database.New(j.sysDB).ClearJournal(ctx)
+}
+
+// inTx runs fn in a transaction. The revert func returned by fn is called if fn fails or the
+// commit does, to undo file changes the rolled back transaction cannot.
+func (j *Journal) inTx(ctx context.Context, fn func(q *database.Queries) (func(), error)) error {
+	tx, err := j.sysDB.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	revert, err := fn(database.New(j.sysDB).WithTx(tx))
+	if err == nil {
+		err = tx.Commit()
+	}
+	if err != nil && revert != nil {
+		revert()
+	}
+	return err
+}
+
+func (j *Journal) reindex(ctx context.Context, q database.Querier, assetID int64) {
+	reindexSearchEntry(ctx, q, j.logger, assetID)
+}
+
+func toHistoryEntry(row database.OperationJournal) *HistoryEntry {
+	return &HistoryEntry{
+		ID:          row.ID,
+		Kind:        row.Kind,
+		Description: row.Description,
+		Undone:      row.IsUndone,
+		CreatedAt:   row.CreatedAt,
+	}
+}
+
+func stepVerb(undo bool) string {
+	if undo {
+		return "undo"
+	}
+	return "redo"
+}
+
+// historyConflict wraps ErrHistoryConflict with the reason.
+func historyConflict(format string, args ...any) error {
+	return fmt.Errorf("%w: %s", ErrHistoryConflict, fmt.Sprintf(format, args...))
+}
+
+// Undo reverts the most recent library operation (rename, tags, rating, trash, type or
+// material set change) and returns it, or nil when there is nothing to undo. If the library or
+// the file changed since, it fails with ErrHistoryConflict.
+func (s *AssetService) Undo() (*HistoryEntry, error) {
+	return s.stepHistory(true)
+}
+
+// Redo applies the most recently undone operation again and returns it, or nil when there is
+// nothing to redo.
+func (s *AssetService) Redo() (*HistoryEntry, error) {
+	return s.stepHistory(false)
+}
+
+// GetHistory returns up to limit recorded operations, newest first.
+func (s *AssetService) GetHistory(limit int) ([]HistoryEntry, error) {
+	return s.journal.history(s.ctxOrBackground(), limit)
+}
+
+// ClearHistory forgets all recorded operations, e.g. after a conflict blocks undoing further.
+func (s *AssetService) ClearHistory() error {
+	return s.journal.clear(s.ctxOrBackground())
+}
+
+func (s *AssetService) stepHistory(undo bool) (*HistoryEntry, error) {
+	ctx := s.ctxOrBackground()
+	entry, err := s.journal.step(ctx, undo)
+	if entry != nil {
+		s.notifier.EmitAssetsChanged(ctx)
+	}
+	return entry, err
+}
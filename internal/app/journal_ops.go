@@ -0,0 +1,511 @@
+package app
+
+import (
+	"context"
+	"database/sql"
+	"eclat/internal/database"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"slices"
+	"time"
+)
+
+// Operation kinds stored in the journal.
+const (
+	opRename            = "rename"
+	opTags              = "tags"
+	opRating            = "rating"
+	opSoftDelete        = "soft_delete"
+	opAssetType         = "asset_type"
+	opMaterialSetMember = "material_set_member"
+	opFlag              = "flag"
+	opBatch             = "batch"
+)
+
+func decodeJournalOp(row database.OperationJournal) (journalOp, error) {
+	op, err := newJournalOp(row.Kind)
+	if err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal([]byte(row.Payload), op); err != nil {
+		return nil, fmt.Errorf("invalid history entry %d: %w", row.ID, err)
+	}
+	return op, nil
+}
+
+// newJournalOp returns an empty operation of the given kind to decode a payload into.
+func newJournalOp(kind string) (journalOp, error) {
+	var op journalOp
+	switch kind {
+	case opRename:
+		op = &renameOp{}
+	case opTags:
+		op = &tagsOp{}
+	case opRating:
+		op = &ratingOp{}
+	case opSoftDelete:
+		op = &softDeleteOp{}
+	case opAssetType:
+		op = &assetTypeOp{}
+	case opMaterialSetMember:
+		op = &materialSetMemberOp{}
+	case opFlag:
+		op = &flagOp{}
+	case opBatch:
+		op = &batchOp{}
+	default:
+		return nil, fmt.Errorf("unknown operation %q in the history", kind)
+	}
+	return op, nil
+}
+
+// journalAsset loads an asset an operation works on; a deleted one is a conflict.
+func journalAsset(ctx context.Context, q *database.Queries, id int64) (database.Asset, error) {
+	asset, err := q.GetAssetById(ctx, id)
+	if errors.Is(err, sql.ErrNoRows) {
+		return asset, historyConflict("asset %d no longer exists", id)
+	}
+	return asset, err
+}
+
+// renameOp renames an asset's file on disk. The file's size and modification time are kept
+// to detect a file that was replaced or edited in the meantime.
+type renameOp struct {
+	AssetID  int64     `json:"assetId"`
+	FromName string    `json:"fromName"`
+	FromPath string    `json:"fromPath"`
+	ToName   string    `json:"toName"`
+	ToPath   string    `json:"toPath"`
+	Size     int64     `json:"size"`
+	ModTime  time.Time `json:"modTime"`
+}
+
+func (op *renameOp) kind() string { return opRename }
+
+func (op *renameOp) describe() string {
+	return fmt.Sprintf("Rename %s to %s", op.FromName, op.ToName)
+}
+
+func (op *renameOp) prepare(ctx context.Context, q *database.Queries) error {
+	info, err := os.Stat(op.FromPath)
+	if err != nil {
+		return fmt.Errorf("failed to rename file on disk: %w", err)
+	}
+	op.Size, op.ModTime = info.Size(), info.ModTime()
+	return nil
+}
+
+func (op *renameOp) apply(ctx context.Context, j *Journal, q *database.Queries, undo bool) (func(), error) {
+	srcPath, dstName, dstPath := op.FromPath, op.ToName, op.ToPath
+	if undo {
+		srcPath, dstName, dstPath = op.ToPath, op.FromName, op.FromPath
+	}
+	asset, err := journalAsset(ctx, q, op.AssetID)
+	if err != nil {
+		return nil, err
+	}
+	if asset.FilePath == dstPath {
+		return nil, errNoChange
+	}
+	if asset.FilePath != srcPath {
+		return nil, historyConflict("%s was moved to %s", srcPath, asset.FilePath)
+	}
+	info, err := os.Stat(srcPath)
+	if err != nil {
+		return nil, historyConflict("%s is no longer on disk", srcPath)
+	}
+	if info.Size() != op.Size || !info.ModTime().Equal(op.ModTime) {
+		return nil, historyConflict("%s was modified since it was renamed", srcPath)
+	}
+	// A case-only rename on a case-insensitive file system finds the file itself
+	if dstInfo, err := os.Lstat(dstPath); err == nil && !os.SameFile(info, dstInfo) {
+		return nil, historyConflict("%s already exists", dstPath)
+	}
+
+	if err := os.Rename(srcPath, dstPath); err != nil {
+		return nil, fmt.Errorf("failed to rename file on disk: %w", err)
+	}
+	revert := func() {
+		if err := os.Rename(dstPath, srcPath); err != nil {
+			j.logger.Error("Failed to revert rename", "from", dstPath, "to", srcPath, "error", err)
+		}
+	}
+	if _, err := q.RenameAsset(ctx, database.RenameAssetParams{FileName: dstName, FilePath: dstPath, ID: op.AssetID}); err != nil {
+		return revert, fmt.Errorf("failed to update asset in db: %w", err)
+	}
+	j.reindex(ctx, q, op.AssetID)
+	return revert, nil
+}
+
+// tagsOp replaces the tags of an asset. When add or remove is set, After is computed from
+// the current tags instead.
+type tagsOp struct {
+	AssetID  int64    `json:"assetId"`
+	FileName string   `json:"fileName"`
+	Before   []string `json:"before"`
+	After    []string `json:"after"`
+
+	add, remove []string
+}
+
+func (op *tagsOp) kind() string { return opTags }
+
+func (op *tagsOp) describe() string { return "Change tags of " + op.FileName }
+
+func (op *tagsOp) prepare(ctx context.Context, q *database.Queries) error {
+	asset, err := q.GetAssetById(ctx, op.AssetID)
+	if err != nil {
+		return fmt.Errorf("failed to get asset: %w", err)
+	}
+	op.FileName = asset.FileName
+	op.Before, err = q.GetTagsNamesByAssetID(ctx, op.AssetID)
+	if err != nil || (op.add == nil && op.remove == nil) {
+		return err
+	}
+	op.After = nil
+	for _, name := range append(slices.Clone(op.Before), op.add...) {
+		if !slices.Contains(op.remove, name) && !slices.Contains(op.After, name) {
+			op.After = append(op.After, name)
+		}
+	}
+	return nil
+}
+
+func (op *tagsOp) apply(ctx context.Context, j *Journal, q *database.Queries, undo bool) (func(), error) {
+	from, to := op.Before, op.After
+	if undo {
+		from, to = to, from
+	}
+	if _, err := journalAsset(ctx, q, op.AssetID); err != nil {
+		return nil, err
+	}
+	current, err := q.GetTagsNamesByAssetID(ctx, op.AssetID)
+	if err != nil {
+		return nil, err
+	}
+	switch {
+	case sameTagSet(current, to):
+		return nil, errNoChange
+	case !sameTagSet(current, from):
+		return nil, historyConflict("the tags of %s changed", op.FileName)
+	}
+
+	if err := q.ClearTagsForAsset(ctx, op.AssetID); err != nil {
+		return nil, err
+	}
+	for _, name := range to {
+		tag, err := q.CreateTag(ctx, name)
+		if err != nil {
+			return nil, err
+		}
+		if err := q.AddTagToAsset(ctx, database.AddTagToAssetParams{AssetID: op.AssetID, TagID: tag.ID}); err != nil {
+			return nil, err
+		}
+	}
+	j.reindex(ctx, q, op.AssetID)
+	return nil, nil
+}
+
+func sameTagSet(a, b []string) bool {
+	a, b = slices.Clone(a), slices.Clone(b)
+	slices.Sort(a)
+	slices.Sort(b)
+	return slices.Equal(slices.Compact(a), slices.Compact(b))
+}
+
+// ratingOp changes the rating of an asset.
+type ratingOp struct {
+	AssetID  int64  `json:"assetId"`
+	FileName string `json:"fileName"`
+	Before   int64  `json:"before"`
+	After    int64  `json:"after"`
+}
+
+func (op *ratingOp) kind() string { return opRating }
+
+func (op *ratingOp) describe() string {
+	return fmt.Sprintf("Rate %s %d/5", op.FileName, op.After)
+}
+
+func (op *ratingOp) prepare(ctx context.Context, q *database.Queries) error {
+	asset, err := q.GetAssetById(ctx, op.AssetID)
+	if err != nil {
+		return fmt.Errorf("failed to get asset: %w", err)
+	}
+	op.FileName, op.Before = asset.FileName, asset.Rating
+	return nil
+}
+
+func (op *ratingOp) apply(ctx context.Context, j *Journal, q *database.Queries, undo bool) (func(), error) {
+	from, to := op.Before, op.After
+	if undo {
+		from, to = to, from
+	}
+	asset, err := journalAsset(ctx, q, op.AssetID)
+	if err != nil {
+		return nil, err
+	}
+	switch asset.Rating {
+	case to:
+		return nil, errNoChange
+	case from:
+		return nil, q.SetAssetRating(ctx, database.SetAssetRatingParams{Rating: to, ID: op.AssetID})
+	default:
+		return nil, historyConflict("the rating of %s changed", op.FileName)
+	}
+}
+
+// assetTypeOp switches an asset between the image and texture types.
+type assetTypeOp struct {
+	AssetID  int64  `json:"assetId"`
+	FileName string `json:"fileName"`
+	Before   string `json:"before"`
+	After    string `json:"after"`
+}
+
+func (op *assetTypeOp) kind() string { return opAssetType }
+
+func (op *assetTypeOp) describe() string {
+	return fmt.Sprintf("Change type of %s to %s", op.FileName, op.After)
+}
+
+func (op *assetTypeOp) prepare(ctx context.Context, q *database.Queries) error {
+	asset, err := q.GetAssetById(ctx, op.AssetID)
+	if err != nil {
+		return err
+	}
+	// Models, audio etc. keep the type given by their extension
+	if asset.FileType != "image" && asset.FileType != "texture" {
+		return fmt.Errorf("cannot change type for asset of type '%s'", asset.FileType)
+	}
+	op.FileName, op.Before = asset.FileName, asset.FileType
+	return nil
+}
+
+func (op *assetTypeOp) apply(ctx context.Context, j *Journal, q *database.Queries, undo bool) (func(), error) {
+	from, to := op.Before, op.After
+	if undo {
+		from, to = to, from
+	}
+	asset, err := journalAsset(ctx, q, op.AssetID)
+	if err != nil {
+		return nil, err
+	}
+	switch asset.FileType {
+	case to:
+		return nil, errNoChange
+	case from:
+		return nil, q.UpdateAssetType(ctx, database.UpdateAssetTypeParams{FileType: to, ID: op.AssetID})
+	default:
+		return nil, historyConflict("the type of %s changed", op.FileName)
+	}
+}
+
+// Flags toggled by flagOp.
+const (
+	flagFavorite = "favorite"
+	flagHidden   = "hidden"
+)
+
+// flagOp sets the favorite or hidden flag of an asset.
+type flagOp struct {
+	Flag     string `json:"flag"`
+	AssetID  int64  `json:"assetId"`
+	FileName string `json:"fileName"`
+	Before   bool   `json:"before"`
+	After    bool   `json:"after"`
+}
+
+func (op *flagOp) kind() string { return opFlag }
+
+func (op *flagOp) describe() string {
+	switch {
+	case op.Flag == flagFavorite && op.After:
+		return "Add " + op.FileName + " to favorites"
+	case op.Flag == flagFavorite:
+		return "Remove " + op.FileName + " from favorites"
+	case op.After:
+		return "Hide " + op.FileName
+	default:
+		return "Unhide " + op.FileName
+	}
+}
+
+func (op *flagOp) prepare(ctx context.Context, q *database.Queries) error {
+	asset, err := q.GetAssetById(ctx, op.AssetID)
+	if err != nil {
+		return fmt.Errorf("failed to get asset: %w", err)
+	}
+	op.FileName, op.Before = asset.FileName, op.value(asset)
+	return nil
+}
+
+func (op *flagOp) value(asset database.Asset) bool {
+	if op.Flag == flagFavorite {
+		return asset.IsFavorite.Valid && asset.IsFavorite.Bool
+	}
+	return asset.IsHidden
+}
+
+func (op *flagOp) apply(ctx context.Context, j *Journal, q *database.Queries, undo bool) (func(), error) {
+	to := op.After
+	if undo {
+		to = op.Before
+	}
+	asset, err := journalAsset(ctx, q, op.AssetID)
+	if err != nil {
+		return nil, err
+	}
+	if op.value(asset) == to {
+		return nil, errNoChange
+	}
+
+	if op.Flag == flagFavorite {
+		_, err = q.UpdateAssetMetadata(ctx, database.UpdateAssetMetadataParams{
+			IsFavorite: sql.NullBool{Bool: to, Valid: true},
+			ID:         op.AssetID,
+		})
+		return nil, err
+	}
+	return nil, q.SetAssetHidden(ctx, database.SetAssetHiddenParams{IsHidden: to, ID: op.AssetID})
+}
+
+// softDeleteOp moves assets to the app's trash. Moving them there unhides them, so the hidden
+// ones are remembered for undo.
+type softDeleteOp struct {
+	AssetIDs  []int64 `json:"assetIds"`
+	HiddenIDs []int64 `json:"hiddenIds"`
+}
+
+func (op *softDeleteOp) kind() string { return opSoftDelete }
+
+func (op *softDeleteOp) describe() string {
+	if len(op.AssetIDs) == 1 {
+		return "Move 1 asset to the trash"
+	}
+	return fmt.Sprintf("Move %d assets to the trash", len(op.AssetIDs))
+}
+
+// prepare leaves out assets already in the trash; undoing must not restore those.
+func (op *softDeleteOp) prepare(ctx context.Context, q *database.Queries) error {
+	var ids []int64
+	seen := make(map[int64]bool, len(op.AssetIDs))
+	for _, id := range op.AssetIDs {
+		if seen[id] {
+			continue
+		}
+		seen[id] = true
+		asset, err := q.GetAssetById(ctx, id)
+		if err != nil {
+			return fmt.Errorf("failed to get asset %d: %w", id, err)
+		}
+		if asset.IsDeleted {
+			continue
+		}
+		ids = append(ids, id)
+		if asset.IsHidden {
+			op.HiddenIDs = append(op.HiddenIDs, id)
+		}
+	}
+	op.AssetIDs = ids
+	return nil
+}
+
+func (op *softDeleteOp) apply(ctx context.Context, j *Journal, q *database.Queries, undo bool) (func(), error) {
+	deleted := !undo
+	var pending []int64
+	for _, id := range op.AssetIDs {
+		asset, err := journalAsset(ctx, q, id)
+		if err != nil {
+			return nil, err
+		}
+		if asset.IsDeleted != deleted {
+			pending = append(pending, id)
+		}
+	}
+	if len(pending) == 0 {
+		return nil, errNoChange
+	}
+
+	const batchSize = 500
+	for i := 0; i < len(pending); i += batchSize {
+		batch := pending[i:min(i+batchSize, len(pending))]
+		var err error
+		if deleted {
+			err = q.SoftDeleteAssets(ctx, batch)
+		} else {
+			err = q.RestoreAssets(ctx, batch)
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+	if !deleted {
+		for _, id := range op.HiddenIDs {
+			if !slices.Contains(pending, id) {
+				continue
+			}
+			if err := q.SetAssetHidden(ctx, database.SetAssetHiddenParams{IsHidden: true, ID: id}); err != nil {
+				return nil, err
+			}
+		}
+	}
+	return nil, nil
+}
+
+// materialSetMemberOp adds an asset to a material set or removes it from one.
+type materialSetMemberOp struct {
+	SetID    int64  `json:"setId"`
+	SetName  string `json:"setName"`
+	AssetID  int64  `json:"assetId"`
+	FileName string `json:"fileName"`
+	Added    bool   `json:"added"` // Whether the operation added the asset (or removed it)
+}
+
+func (op *materialSetMemberOp) kind() string { return opMaterialSetMember }
+
+func (op *materialSetMemberOp) describe() string {
+	if op.Added {
+		return fmt.Sprintf("Add %s to %s", op.FileName, op.SetName)
+	}
+	return fmt.Sprintf("Remove %s from %s", op.FileName, op.SetName)
+}
+
+func (op *materialSetMemberOp) prepare(ctx context.Context, q *database.Queries) error {
+	set, err := q.GetMaterialSetById(ctx, op.SetID)
+	if err != nil {
+		return fmt.Errorf("failed to get material set %d: %w", op.SetID, err)
+	}
+	asset, err := q.GetAssetById(ctx, op.AssetID)
+	if err != nil {
+		return fmt.Errorf("failed to get asset %d: %w", op.AssetID, err)
+	}
+	op.SetName, op.FileName = set.Name, asset.FileName
+	return nil
+}
+
+func (op *materialSetMemberOp) apply(ctx context.Context, j *Journal, q *database.Queries, undo bool) (func(), error) {
+	add := op.Added != undo
+	if _, err := q.GetMaterialSetById(ctx, op.SetID); errors.Is(err, sql.ErrNoRows) {
+		return nil, historyConflict("material set %s no longer exists", op.SetName)
+	} else if err != nil {
+		return nil, err
+	}
+	if _, err := journalAsset(ctx, q, op.AssetID); err != nil {
+		return nil, err
+	}
+	setIDs, err := q.ListMaterialSetIDsByAsset(ctx, op.AssetID)
+	if err != nil {
+		return nil, err
+	}
+	if slices.Contains(setIDs, op.SetID) == add {
+		return nil, errNoChange
+	}
+
+	if add {
+		return nil, q.AddAssetToMaterialSet(ctx, database.AddAssetToMaterialSetParams{MaterialSetID: op.SetID, AssetID: op.AssetID})
+	}
+	return nil, q.RemoveAssetFromMaterialSet(ctx, database.RemoveAssetFromMaterialSetParams{MaterialSetID: op.SetID, AssetID: op.AssetID})
+}
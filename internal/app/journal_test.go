@@ -0,0 +1,169 @@
+package app
+
+import (
+	"context"
+	"eclat/internal/database"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestJournal_RenameUndoRedo(t *testing.T) {
+	service, queries := setupAssetServiceTest(t)
+	ctx := context.Background()
+	dir := t.TempDir()
+	oldPath := filepath.Join(dir, "rock.png")
+	newPath := filepath.Join(dir, "granite.png")
+	asset := insertDuplicateFile(t, queries, oldPath, "rock-bytes", "G-ROCK")
+
+	require.NoError(t, service.RenameAsset(asset.ID, "granite"))
+	assert.FileExists(t, newPath)
+
+	entry, err := service.Undo()
+	require.NoError(t, err)
+	require.NotNil(t, entry)
+	assert.Equal(t, "Rename rock.png to granite.png", entry.Description)
+	assert.True(t, entry.Undone)
+	assert.FileExists(t, oldPath)
+	assert.NoFileExists(t, newPath)
+	undone, err := queries.GetAssetById(ctx, asset.ID)
+	require.NoError(t, err)
+	assert.Equal(t, "rock.png", undone.FileName)
+	assert.Equal(t, oldPath, undone.FilePath)
+
+	entry, err = service.Redo()
+	require.NoError(t, err)
+	require.NotNil(t, entry)
+	assert.False(t, entry.Undone)
+	assert.FileExists(t, newPath)
+
+	entry, err = service.Redo()
+	require.NoError(t, err)
+	assert.Nil(t, entry, "Nie ma nic do ponowienia")
+
+	// Plik zmieniony po operacji blokuje cofnięcie
+	require.NoError(t, os.WriteFile(newPath, []byte("edited granite"), 0644))
+	_, err = service.Undo()
+	assert.ErrorIs(t, err, ErrHistoryConflict)
+	assert.FileExists(t, newPath)
+	assert.NoFileExists(t, oldPath)
+
+	history, err := service.GetHistory(10)
+	require.NoError(t, err)
+	require.Len(t, history, 1)
+	assert.False(t, history[0].Undone, "Operacja z konfliktem zostaje w historii")
+
+	require.NoError(t, service.ClearHistory())
+	entry, err = service.Undo()
+	require.NoError(t, err)
+	assert.Nil(t, entry)
+}
+
+func TestJournal_RenameConflictWithNewFile(t *testing.T) {
+	service, queries := setupAssetServiceTest(t)
+	dir := t.TempDir()
+	oldPath := filepath.Join(dir, "sky.png")
+	asset := insertDuplicateFile(t, queries, oldPath, "sky-bytes", "G-SKY")
+	require.NoError(t, service.RenameAsset(asset.ID, "clouds.png"))
+
+	// Ktoś utworzył nowy plik pod starą nazwą
+	require.NoError(t, os.WriteFile(oldPath, []byte("other sky"), 0644))
+	_, err := service.Undo()
+	assert.ErrorIs(t, err, ErrHistoryConflict)
+	assert.ErrorContains(t, err, "already exists")
+	content, err := os.ReadFile(oldPath)
+	require.NoError(t, err)
+	assert.Equal(t, "other sky", string(content))
+}
+
+func TestJournal_MetadataUndoRedo(t *testing.T) {
+	service, queries := setupAssetServiceTest(t)
+	ctx := context.Background()
+	asset := insertTestAsset(t, queries)
+	other := insertTestAssetWithParams(t, queries, "hidden.png", "/tmp/test/hidden.png", false, true)
+	set, err := queries.CreateMaterialSet(ctx, database.CreateMaterialSetParams{Name: "Rocks"})
+	require.NoError(t, err)
+
+	require.NoError(t, service.UpdateTags(asset.ID, []string{"rock"}))
+	require.NoError(t, service.SetAssetRating(asset.ID, 4))
+	require.NoError(t, service.SetAssetRating(asset.ID, 4), "Ta sama ocena nie trafia do historii")
+	require.NoError(t, service.UpdateAssetType(asset.ID, "texture"))
+	require.NoError(t, service.AddAssetToMaterialSet(set.ID, asset.ID))
+	require.NoError(t, service.SoftDeleteAssets([]int64{asset.ID, other.ID}))
+
+	history, err := service.GetHistory(0)
+	require.NoError(t, err)
+	require.Len(t, history, 5)
+	assert.Equal(t, "Move 2 assets to the trash", history[0].Description)
+	assert.Equal(t, "Add test_file.png to Rocks", history[1].Description)
+	assert.Equal(t, "Change tags of test_file.png", history[4].Description)
+
+	for range history {
+		_, err := service.Undo()
+		require.NoError(t, err)
+	}
+	restored, err := queries.GetAssetById(ctx, asset.ID)
+	require.NoError(t, err)
+	assert.False(t, restored.IsDeleted)
+	assert.Equal(t, "image", restored.FileType)
+	assert.Zero(t, restored.Rating)
+	tags, err := queries.GetTagsNamesByAssetID(ctx, asset.ID)
+	require.NoError(t, err)
+	assert.Empty(t, tags)
+	sets, err := queries.ListMaterialSetIDsByAsset(ctx, asset.ID)
+	require.NoError(t, err)
+	assert.Empty(t, sets)
+	hidden, err := queries.GetAssetById(ctx, other.ID)
+	require.NoError(t, err)
+	assert.True(t, hidden.IsHidden, "Cofnięcie usunięcia przywraca ukrycie")
+
+	// Ponowienie dwóch operacji, a nowa operacja kasuje resztę do ponowienia
+	for range 2 {
+		_, err := service.Redo()
+		require.NoError(t, err)
+	}
+	rated, err := queries.GetAssetById(ctx, asset.ID)
+	require.NoError(t, err)
+	assert.Equal(t, int64(4), rated.Rating)
+
+	require.NoError(t, service.SetAssetRating(asset.ID, 2))
+	entry, err := service.Redo()
+	require.NoError(t, err)
+	assert.Nil(t, entry)
+	history, err = service.GetHistory(0)
+	require.NoError(t, err)
+	assert.Len(t, history, 3)
+
+	// Zmiana poza historią jest konfliktem
+	require.NoError(t, queries.SetAssetRating(ctx, database.SetAssetRatingParams{Rating: 5, ID: asset.ID}))
+	_, err = service.Undo()
+	assert.ErrorIs(t, err, ErrHistoryConflict)
+}
+
+func TestJournal_SharedWithMaterialSetService(t *testing.T) {
+	service, queries := setupAssetServiceTest(t)
+	msService := NewMaterialSetService(queries, service.logger, &MockThumbGen{}, service.journal)
+	msService.Startup(context.Background())
+	asset := insertTestAsset(t, queries)
+	ms, err := msService.Create(CreateMaterialSetRequest{Name: "Bricks"})
+	require.NoError(t, err)
+
+	require.NoError(t, msService.AddAsset(ms.ID, asset.ID))
+	entry, err := service.Undo()
+	require.NoError(t, err)
+	require.NotNil(t, entry)
+	assert.Equal(t, "Add test_file.png to Bricks", entry.Description)
+	updated, err := msService.GetById(ms.ID)
+	require.NoError(t, err)
+	assert.Zero(t, updated.TotalAssets)
+
+	// Usunięta kolekcja blokuje ponowienie
+	require.NoError(t, msService.Delete(ms.ID))
+	_, err = service.Redo()
+	assert.ErrorIs(t, err, ErrHistoryConflict)
+	assert.WithinDuration(t, time.Now(), entry.CreatedAt, time.Minute)
+}
@@ -0,0 +1,50 @@
+package app
+
+import (
+	"eclat/internal/config"
+	"slices"
+)
+
+// MaterialChannel is one texture map of a PBR material, as shown in the inspector.
+type MaterialChannel struct {
+	Role          string `json:"role"` // One of config.MaterialChannelRoles
+	AssetID       int64  `json:"assetId"`
+	FileName      string `json:"fileName"`
+	FilePath      string `json:"filePath"`
+	ThumbnailPath string `json:"thumbnailPath"`
+}
+
+// GetMaterialChannels returns the texture maps of an asset group with their channel roles,
+// base color first. Groups that are not texture sets have no channels.
+func (s *AssetService) GetMaterialChannels(groupId string) ([]MaterialChannel, error) {
+	ctx := s.ctxOrBackground()
+
+	rows, err := s.db.ListGroupMaterialChannels(ctx, groupId)
+	if err != nil {
+		s.logger.Error("Failed to fetch material channels", "group_id", groupId, "error", err)
+		return nil, err
+	}
+	channels := make([]MaterialChannel, 0, len(rows))
+	for _, r := range rows {
+		channels = append(channels, MaterialChannel{
+			Role:          r.MaterialChannel,
+			AssetID:       r.ID,
+			FileName:      r.FileName,
+			FilePath:      r.FilePath,
+			ThumbnailPath: r.ThumbnailPath,
+		})
+	}
+	// Rows come sorted by file name, which keeps the order stable within a role.
+	slices.SortStableFunc(channels, func(a, b MaterialChannel) int {
+		return roleOrder(a.Role) - roleOrder(b.Role)
+	})
+	return channels, nil
+}
+
+// roleOrder is the position of a role in config.MaterialChannelRoles; unknown roles go last.
+func roleOrder(role string) int {
+	if i := slices.Index(config.MaterialChannelRoles, role); i >= 0 {
+		return i
+	}
+	return len(config.MaterialChannelRoles)
+}
@@ -0,0 +1,48 @@
+package app
+
+import (
+	"context"
+	"eclat/internal/config"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAssetService_GetMaterialChannels(t *testing.T) {
+	service, queries := setupAssetServiceTest(t)
+	ctx := context.Background()
+
+	setChannel := func(name, channel string) {
+		asset := insertTestAssetWithParamsAndGroup(t, queries, name, "/lib/rock/"+name, false, false, "rock-group")
+		_, err := service.sysDB.ExecContext(ctx, "UPDATE assets SET material_channel = ? WHERE id = ?", channel, asset.ID)
+		require.NoError(t, err)
+	}
+	setChannel("rock_roughness.png", config.ChannelRoughness)
+	setChannel("rock_normal_gl.png", config.ChannelNormal)
+	setChannel("rock_albedo.png", config.ChannelBaseColor)
+	setChannel("rock_normal_dx.png", config.ChannelNormal)
+	setChannel("rock_preview.png", "") // Nie jest mapą materiału
+
+	channels, err := service.GetMaterialChannels("rock-group")
+	require.NoError(t, err)
+	require.Len(t, channels, 4)
+
+	var names, roles []string
+	for _, c := range channels {
+		names = append(names, c.FileName)
+		roles = append(roles, c.Role)
+	}
+	// Kolejność ról z config.MaterialChannelRoles, w obrębie roli po nazwie pliku
+	assert.Equal(t, []string{"rock_albedo.png", "rock_normal_dx.png", "rock_normal_gl.png", "rock_roughness.png"}, names)
+	assert.Equal(t, []string{config.ChannelBaseColor, config.ChannelNormal, config.ChannelNormal, config.ChannelRoughness}, roles)
+	assert.Equal(t, "/lib/rock/rock_albedo.png", channels[0].FilePath)
+
+	details, err := service.GetAssetById(channels[0].AssetID)
+	require.NoError(t, err)
+	assert.Equal(t, config.ChannelBaseColor, details.MaterialChannel)
+
+	empty, err := service.GetMaterialChannels("no-such-group")
+	require.NoError(t, err)
+	assert.Empty(t, empty)
+}
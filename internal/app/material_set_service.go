@@ -15,13 +15,15 @@ type MaterialSetService struct {
 	db       database.Querier
 	logger   *slog.Logger
 	thumbGen scanner.ThumbnailGenerator
+	journal  *Journal
 }
 
-func NewMaterialSetService(db database.Querier, logger *slog.Logger, thumbGen scanner.ThumbnailGenerator) *MaterialSetService {
+func NewMaterialSetService(db database.Querier, logger *slog.Logger, thumbGen scanner.ThumbnailGenerator, journal *Journal) *MaterialSetService {
 	return &MaterialSetService{
 		db:       db,
 		logger:   logger,
 		thumbGen: thumbGen,
+		journal:  journal,
 	}
 }
 
@@ -248,20 +250,14 @@ func (s *MaterialSetService) GetById(id int64) (*MaterialSet, error) {
 	}, nil
 }
 
-// AddAsset adds an asset to a material set.
+// AddAsset adds an asset to a material set. The change can be undone.
 func (s *MaterialSetService) AddAsset(setId int64, assetId int64) error {
-	return s.db.AddAssetToMaterialSet(s.ctx, database.AddAssetToMaterialSetParams{
-		MaterialSetID: setId,
-		AssetID:       assetId,
-	})
+	return s.journal.execute(s.ctx, &materialSetMemberOp{SetID: setId, AssetID: assetId, Added: true})
 }
 
-// RemoveAsset removes an asset from a material set.
+// RemoveAsset removes an asset from a material set. The change can be undone.
 func (s *MaterialSetService) RemoveAsset(setId int64, assetId int64) error {
-	return s.db.RemoveAssetFromMaterialSet(s.ctx, database.RemoveAssetFromMaterialSetParams{
-		MaterialSetID: setId,
-		AssetID:       assetId,
-	})
+	return s.journal.execute(s.ctx, &materialSetMemberOp{SetID: setId, AssetID: assetId})
 }
 
 // Helpers
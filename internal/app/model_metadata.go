@@ -0,0 +1,78 @@
+package app
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+
+	sq "github.com/Masterminds/squirrel"
+)
+
+// MaxPolyCount is the largest triangle count accepted by the polyCountRange filter.
+const MaxPolyCount = 1 << 40
+
+// AssetModel is the geometry of a glTF/GLB or OBJ model, as shown in the details panel.
+type AssetModel struct {
+	VertexCount   int64      `json:"vertexCount"`
+	TriangleCount int64      `json:"triangleCount"` // Polygons count as the triangles they split into
+	MeshCount     int64      `json:"meshCount"`
+	MaterialCount int64      `json:"materialCount"`
+	TextureCount  int64      `json:"textureCount"` // Includes textures embedded in the file
+	Textures      []string   `json:"textures"`     // Referenced texture files, relative to the model
+	Size          [3]float64 `json:"size"`         // Bounding box size in model units (x, y, z)
+}
+
+// getAssetModel loads the model geometry of an asset, nil for assets that are not parsed models.
+func (s *AssetService) getAssetModel(ctx context.Context, assetID int64) *AssetModel {
+	row, err := s.db.GetModelMetadata(ctx, assetID)
+	if err != nil {
+		if !errors.Is(err, sql.ErrNoRows) {
+			s.logger.Error("Failed to fetch model metadata", "id", assetID, "error", err)
+		}
+		return nil
+	}
+	model := &AssetModel{
+		VertexCount:   row.VertexCount,
+		TriangleCount: row.TriangleCount,
+		MeshCount:     row.MeshCount,
+		MaterialCount: row.MaterialCount,
+		TextureCount:  row.TextureCount,
+		Textures:      []string{},
+		Size:          [3]float64{row.SizeX, row.SizeY, row.SizeZ},
+	}
+	if err := json.Unmarshal([]byte(row.TextureFiles), &model.Textures); err != nil {
+		s.logger.Warn("Invalid model texture list", "id", assetID, "error", err)
+	}
+	return model
+}
+
+// modelCondition matches models by triangle count and texture use. Bounds of 0 are open, like
+// the dimension filters; any active condition leaves out assets without model metadata.
+func modelCondition(polyCountRange []int, hasTextures *bool) sq.Sqlizer {
+	sub := sq.Select("mm.asset_id").From("model_metadata mm")
+	active := false
+	if len(polyCountRange) == 2 {
+		if polyCountRange[0] > 0 {
+			sub = sub.Where(sq.GtOrEq{"mm.triangle_count": polyCountRange[0]})
+			active = true
+		}
+		if polyCountRange[1] > 0 {
+			sub = sub.Where(sq.LtOrEq{"mm.triangle_count": polyCountRange[1]})
+			active = true
+		}
+	}
+	if hasTextures != nil {
+		if *hasTextures {
+			sub = sub.Where(sq.Gt{"mm.texture_count": 0})
+		} else {
+			sub = sub.Where(sq.Eq{"mm.texture_count": 0})
+		}
+		active = true
+	}
+	if !active {
+		return nil
+	}
+	subSql, subArgs, _ := sub.ToSql()
+	return sq.Expr("a.id IN ("+subSql+")", subArgs...)
+}
@@ -0,0 +1,74 @@
+package app
+
+import (
+	"context"
+	"eclat/internal/database"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAssetService_ModelMetadata(t *testing.T) {
+	service, queries := setupAssetServiceTest(t)
+	ctx := context.Background()
+
+	storeModel := func(id, triangles, textures int64, files string) {
+		err := queries.UpsertModelMetadata(ctx, database.UpsertModelMetadataParams{
+			AssetID: id, VertexCount: triangles * 2, TriangleCount: triangles, MeshCount: 1, MaterialCount: 1,
+			TextureCount: textures, TextureFiles: files, SizeX: 1, SizeY: 2, SizeZ: 3,
+		})
+		require.NoError(t, err)
+	}
+
+	crate := insertTestAssetWithParams(t, queries, "crate.obj", "/lib/crate.obj", false, false)
+	statue := insertTestAssetWithParams(t, queries, "statue.glb", "/lib/statue.glb", false, false)
+	rock := insertTestAssetWithParams(t, queries, "rock.gltf", "/lib/rock.gltf", false, false)
+	photo := insertTestAssetWithParams(t, queries, "photo.png", "/lib/photo.png", false, false)
+
+	storeModel(crate.ID, 12, 2, `["textures/crate_albedo.png","textures/crate normal.png"]`)
+	storeModel(statue.ID, 250000, 1, `[]`) // Tekstura osadzona w GLB
+	storeModel(rock.ID, 3000, 0, `[]`)
+
+	t.Run("Details", func(t *testing.T) {
+		details, err := service.GetAssetById(crate.ID)
+		require.NoError(t, err)
+		require.NotNil(t, details.Model)
+		assert.Equal(t, AssetModel{
+			VertexCount: 24, TriangleCount: 12, MeshCount: 1, MaterialCount: 1, TextureCount: 2,
+			Textures: []string{"textures/crate_albedo.png", "textures/crate normal.png"},
+			Size:     [3]float64{1, 2, 3},
+		}, *details.Model)
+
+		details, err = service.GetAssetById(photo.ID)
+		require.NoError(t, err)
+		assert.Nil(t, details.Model, "Images have no model metadata")
+	})
+
+	search := func(filters AssetQueryFilters) []int64 {
+		filters.Page, filters.PageSize, filters.SortOption = 1, 10, "filename"
+		res, err := service.GetAssets(filters)
+		require.NoError(t, err)
+		var ids []int64
+		for _, item := range res.Items {
+			ids = append(ids, item.ID)
+		}
+		return ids
+	}
+	yes, no := true, false
+
+	assert.Equal(t, []int64{crate.ID, rock.ID}, search(AssetQueryFilters{PolyCountRange: []int{0, 5000}}),
+		"Max bound alone leaves out heavy models and non-models")
+	assert.Equal(t, []int64{rock.ID, statue.ID}, search(AssetQueryFilters{PolyCountRange: []int{1000, 0}}))
+	assert.Equal(t, []int64{crate.ID, photo.ID, rock.ID, statue.ID}, search(AssetQueryFilters{PolyCountRange: []int{0, 0}}),
+		"An open range does not filter")
+	assert.Equal(t, []int64{crate.ID, statue.ID}, search(AssetQueryFilters{HasTextures: &yes}))
+	assert.Equal(t, []int64{rock.ID}, search(AssetQueryFilters{HasTextures: &no}))
+	assert.Equal(t, []int64{crate.ID}, search(AssetQueryFilters{PolyCountRange: []int{0, 5000}, HasTextures: &yes}))
+}
+
+func TestValidateAssetQueryFilters_PolyCountRange(t *testing.T) {
+	assert.NoError(t, validateAssetQueryFilters(AssetQueryFilters{PolyCountRange: []int{0, 100000}}, nil))
+	assert.Error(t, validateAssetQueryFilters(AssetQueryFilters{PolyCountRange: []int{5000, 100}}, nil))
+	assert.Error(t, validateAssetQueryFilters(AssetQueryFilters{PolyCountRange: []int{-1, 100}}, nil))
+}
@@ -0,0 +1,472 @@
+package app
+
+import (
+	"context"
+	"database/sql"
+	"eclat/internal/config"
+	"eclat/internal/database"
+	"eclat/internal/feedback"
+	"eclat/internal/scanner"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	// maxPaletteColors keeps nearest-color lookups (one per stored color) cheap.
+	maxPaletteColors = 256
+	// paletteRemapBatchSize is the number of asset_colors rows re-mapped per transaction.
+	paletteRemapBatchSize = 2000
+)
+
+// ColorPalette is the DTO of a named color palette.
+type ColorPalette struct {
+	ID        int64                 `json:"id"`
+	Name      string                `json:"name"`
+	Colors    []config.PaletteColor `json:"colors"`
+	IsActive  bool                  `json:"isActive"`
+	DateAdded time.Time             `json:"dateAdded"`
+}
+
+// PaletteService manages user-defined color palettes (brand palettes, "biome" palettes...).
+// Exactly one palette is active: the scanner snaps extracted colors to it and the color
+// filters offer its entries. Switching or editing the active palette re-maps the stored
+// colors in the background, using the raw hex values kept in asset_colors - source files
+// are never read again.
+type PaletteService struct {
+	ctx      context.Context
+	db       database.Querier
+	sysDB    *sql.DB
+	logger   *slog.Logger
+	notifier feedback.Notifier
+	config   *config.ScannerConfig
+
+	remapMu     sync.Mutex
+	remapCancel context.CancelFunc
+	remapDone   chan struct{}
+}
+
+func NewPaletteService(db database.Querier, sysDB *sql.DB, logger *slog.Logger, notifier feedback.Notifier, cfg *config.ScannerConfig) *PaletteService {
+	return &PaletteService{
+		db:       db,
+		sysDB:    sysDB,
+		logger:   logger,
+		notifier: notifier,
+		config:   cfg,
+	}
+}
+
+func (s *PaletteService) Startup(ctx context.Context) {
+	s.ctx = ctx
+}
+
+// LoadActivePalette copies the active palette from the database into the shared scanner config.
+// Called once on startup, before the first scan.
+func (s *PaletteService) LoadActivePalette(ctx context.Context) error {
+	row, err := s.db.GetActiveColorPalette(ctx)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil // built-in palette stays in use
+		}
+		return err
+	}
+	colors, err := decodePaletteColors(row.ColorsJson)
+	if err != nil {
+		return err
+	}
+	s.config.SetColorPalette(colors)
+	return nil
+}
+
+// GetPalettes returns all palettes ordered by name.
+func (s *PaletteService) GetPalettes() ([]ColorPalette, error) {
+	rows, err := s.db.ListColorPalettes(s.ctxOrBackground())
+	if err != nil {
+		return nil, err
+	}
+	palettes := make([]ColorPalette, 0, len(rows))
+	for _, r := range rows {
+		p, err := toColorPaletteDTO(r)
+		if err != nil {
+			s.logger.Error("Failed to decode color palette", "id", r.ID, "error", err)
+			continue
+		}
+		palettes = append(palettes, p)
+	}
+	return palettes, nil
+}
+
+// GetActivePalette returns the palette currently used for color matching.
+func (s *PaletteService) GetActivePalette() (*ColorPalette, error) {
+	row, err := s.db.GetActiveColorPalette(s.ctxOrBackground())
+	if err != nil {
+		return nil, err
+	}
+	p, err := toColorPaletteDTO(row)
+	if err != nil {
+		return nil, err
+	}
+	return &p, nil
+}
+
+// CreatePalette stores a new, inactive palette.
+func (s *PaletteService) CreatePalette(name string, colors []config.PaletteColor) (*ColorPalette, error) {
+	name, err := validatePaletteName(name)
+	if err != nil {
+		return nil, err
+	}
+	colors, err = normalizePaletteColors(colors)
+	if err != nil {
+		return nil, err
+	}
+
+	ctx := s.ctxOrBackground()
+	if _, err := s.db.GetColorPaletteByName(ctx, name); err == nil {
+		return nil, fmt.Errorf("palette %q already exists", name)
+	}
+
+	payload, err := json.Marshal(colors)
+	if err != nil {
+		return nil, err
+	}
+	row, err := s.db.CreateColorPalette(ctx, database.CreateColorPaletteParams{
+		Name:       name,
+		ColorsJson: string(payload),
+	})
+	if err != nil {
+		return nil, err
+	}
+	p, err := toColorPaletteDTO(row)
+	if err != nil {
+		return nil, err
+	}
+	return &p, nil
+}
+
+// UpdatePalette renames a palette and replaces its colors. Editing the active palette
+// re-maps the library in the background.
+func (s *PaletteService) UpdatePalette(id int64, name string, colors []config.PaletteColor) (*ColorPalette, error) {
+	name, err := validatePaletteName(name)
+	if err != nil {
+		return nil, err
+	}
+	colors, err = normalizePaletteColors(colors)
+	if err != nil {
+		return nil, err
+	}
+
+	ctx := s.ctxOrBackground()
+	if existing, err := s.db.GetColorPaletteByName(ctx, name); err == nil && existing.ID != id {
+		return nil, fmt.Errorf("palette %q already exists", name)
+	}
+
+	payload, err := json.Marshal(colors)
+	if err != nil {
+		return nil, err
+	}
+	row, err := s.db.UpdateColorPalette(ctx, database.UpdateColorPaletteParams{
+		ID:         id,
+		Name:       sql.NullString{String: name, Valid: true},
+		ColorsJson: sql.NullString{String: string(payload), Valid: true},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if row.IsActive {
+		s.config.SetColorPalette(colors)
+		s.startRemap(colors)
+	}
+
+	p, err := toColorPaletteDTO(row)
+	if err != nil {
+		return nil, err
+	}
+	return &p, nil
+}
+
+// DeletePalette removes an inactive palette. The active one has to be switched first,
+// so there is always a palette to match against.
+func (s *PaletteService) DeletePalette(id int64) error {
+	ctx := s.ctxOrBackground()
+	row, err := s.db.GetColorPaletteById(ctx, id)
+	if err != nil {
+		return err
+	}
+	if row.IsActive {
+		return errors.New("cannot delete the active palette, activate another one first")
+	}
+	return s.db.DeleteColorPalette(ctx, id)
+}
+
+// SetActivePalette makes the palette the one used for color matching and starts re-mapping
+// the existing assets to it.
+func (s *PaletteService) SetActivePalette(id int64) (*ColorPalette, error) {
+	ctx := s.ctxOrBackground()
+	row, err := s.db.GetColorPaletteById(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	p, err := toColorPaletteDTO(row)
+	if err != nil {
+		return nil, err
+	}
+	if row.IsActive {
+		return &p, nil
+	}
+
+	err = inTx(ctx, s.sysDB, func(qtx *database.Queries) error {
+		if err := qtx.DeactivateColorPalettes(ctx); err != nil {
+			return err
+		}
+		return qtx.ActivateColorPalette(ctx, id)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	s.logger.Info("Active color palette changed", "id", id, "name", p.Name)
+	s.config.SetColorPalette(p.Colors)
+	s.startRemap(p.Colors)
+
+	p.IsActive = true
+	return &p, nil
+}
+
+// startRemap cancels a running re-map job and queues a new one for the given palette.
+// Jobs never overlap: the new one waits until the cancelled one has stopped.
+func (s *PaletteService) startRemap(palette []config.PaletteColor) {
+	s.remapMu.Lock()
+	defer s.remapMu.Unlock()
+
+	if s.remapCancel != nil {
+		s.remapCancel()
+	}
+	ctx, cancel := context.WithCancel(s.ctxOrBackground())
+	previous := s.remapDone
+	done := make(chan struct{})
+	s.remapCancel = cancel
+	s.remapDone = done
+
+	go func() {
+		defer close(done)
+		defer cancel()
+		if previous != nil {
+			<-previous
+		}
+
+		start := time.Now()
+		count, err := s.remapAssetColors(ctx, palette)
+		if err != nil {
+			if errors.Is(err, context.Canceled) {
+				s.logger.Info("Color re-mapping superseded by a newer palette")
+				return
+			}
+			s.logger.Error("Color re-mapping failed", "error", err)
+			s.notifier.SendToast(s.ctx, feedback.ToastField{
+				Type:    "error",
+				Title:   "Palette Update Failed",
+				Message: "Could not re-map asset colors to the new palette.",
+			})
+			return
+		}
+
+		s.logger.Info("Asset colors re-mapped", "colors", count, "duration", time.Since(start))
+		s.notifier.EmitAssetsChanged(s.ctx)
+	}()
+}
+
+// waitForRemap blocks until the current re-map job (if any) finishes.
+func (s *PaletteService) waitForRemap() {
+	s.remapMu.Lock()
+	done := s.remapDone
+	s.remapMu.Unlock()
+	if done != nil {
+		<-done
+	}
+}
+
+// remapAssetColors snaps every stored color (and the dominant color derived from it) to the
+// palette. Assets scanned before palettes were extracted only have assets.dominant_color,
+// which is then re-mapped from its previous palette value.
+func (s *PaletteService) remapAssetColors(ctx context.Context, palette []config.PaletteColor) (int, error) {
+	// Libraries share a lot of colors, so each distinct hex is matched once.
+	cache := make(map[string]config.PaletteColor)
+	nearest := func(hex string) config.PaletteColor {
+		if entry, ok := cache[hex]; ok {
+			return entry
+		}
+		entry, err := scanner.FindClosestPaletteEntry(hex, palette)
+		if err != nil {
+			s.logger.Warn("Cannot match color to palette", "hex", hex, "error", err)
+		}
+		cache[hex] = entry
+		return entry
+	}
+
+	count := 0
+	var afterAsset, afterPosition int64
+	for {
+		if err := ctx.Err(); err != nil {
+			return count, err
+		}
+		rows, err := s.db.ListAssetColorsPage(ctx, database.ListAssetColorsPageParams{
+			AfterAssetID:  afterAsset,
+			AfterPosition: afterPosition,
+			Limit:         paletteRemapBatchSize,
+		})
+		if err != nil {
+			return count, err
+		}
+		if len(rows) == 0 {
+			break
+		}
+
+		err = inTx(ctx, s.sysDB, func(qtx *database.Queries) error {
+			for _, r := range rows {
+				entry := nearest(r.Hex)
+				err := qtx.UpdateAssetColorPalette(ctx, database.UpdateAssetColorPaletteParams{
+					PaletteName: entry.Name,
+					PaletteHex:  entry.Hex,
+					AssetID:     r.AssetID,
+					Position:    r.Position,
+				})
+				if err != nil {
+					return err
+				}
+				if r.Position == 0 {
+					err := qtx.UpdateAssetDominantColor(ctx, database.UpdateAssetDominantColorParams{
+						DominantColor: sql.NullString{String: entry.Hex, Valid: entry.Hex != ""},
+						ID:            r.AssetID,
+					})
+					if err != nil {
+						return err
+					}
+				}
+			}
+			return nil
+		})
+		if err != nil {
+			return count, err
+		}
+
+		count += len(rows)
+		last := rows[len(rows)-1]
+		afterAsset, afterPosition = last.AssetID, last.Position
+	}
+
+	legacy, err := s.db.ListLegacyDominantColors(ctx)
+	if err != nil {
+		return count, err
+	}
+	if len(legacy) > 0 {
+		err = inTx(ctx, s.sysDB, func(qtx *database.Queries) error {
+			for _, r := range legacy {
+				entry := nearest(r.DominantColor.String)
+				err := qtx.UpdateAssetDominantColor(ctx, database.UpdateAssetDominantColorParams{
+					DominantColor: sql.NullString{String: entry.Hex, Valid: entry.Hex != ""},
+					ID:            r.ID,
+				})
+				if err != nil {
+					return err
+				}
+			}
+			return nil
+		})
+		if err != nil {
+			return count, err
+		}
+		count += len(legacy)
+	}
+	return count, nil
+}
+
+func (s *PaletteService) ctxOrBackground() context.Context {
+	if s.ctx != nil {
+		return s.ctx
+	}
+	return context.Background()
+}
+
+// activeColorPalette returns the colors of the active palette, falling back to the built-in one.
+func activeColorPalette(ctx context.Context, db database.Querier) []config.PaletteColor {
+	row, err := db.GetActiveColorPalette(ctx)
+	if err != nil {
+		return config.PredefinedPalette
+	}
+	colors, err := decodePaletteColors(row.ColorsJson)
+	if err != nil {
+		return config.PredefinedPalette
+	}
+	return colors
+}
+
+func toColorPaletteDTO(row database.ColorPalette) (ColorPalette, error) {
+	colors, err := decodePaletteColors(row.ColorsJson)
+	if err != nil {
+		return ColorPalette{}, err
+	}
+	return ColorPalette{
+		ID:        row.ID,
+		Name:      row.Name,
+		Colors:    colors,
+		IsActive:  row.IsActive,
+		DateAdded: row.DateAdded,
+	}, nil
+}
+
+func decodePaletteColors(payload string) ([]config.PaletteColor, error) {
+	var colors []config.PaletteColor
+	if err := json.Unmarshal([]byte(payload), &colors); err != nil {
+		return nil, fmt.Errorf("invalid palette json: %w", err)
+	}
+	if len(colors) == 0 {
+		return nil, errors.New("palette has no colors")
+	}
+	return colors, nil
+}
+
+func validatePaletteName(name string) (string, error) {
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return "", errors.New("palette name cannot be empty")
+	}
+	if len(name) > 100 {
+		return "", errors.New("palette name too long (max 100 chars)")
+	}
+	return name, nil
+}
+
+// normalizePaletteColors validates the entries and upper-cases the hex values, so they compare
+// equal to the ones stored by the scanner. Names must be unique, search resolves color:<name>.
+func normalizePaletteColors(colors []config.PaletteColor) ([]config.PaletteColor, error) {
+	if len(colors) == 0 {
+		return nil, errors.New("palette must contain at least one color")
+	}
+	if len(colors) > maxPaletteColors {
+		return nil, fmt.Errorf("palette too large (max %d colors)", maxPaletteColors)
+	}
+
+	seen := make(map[string]bool, len(colors))
+	result := make([]config.PaletteColor, 0, len(colors))
+	for _, c := range colors {
+		name := strings.TrimSpace(c.Name)
+		if name == "" {
+			return nil, fmt.Errorf("color %s has no name", c.Hex)
+		}
+		if !hexColorPattern.MatchString(c.Hex) {
+			return nil, fmt.Errorf("invalid color %q (expected #RRGGBB)", c.Hex)
+		}
+		key := normalizeColorName(name)
+		if seen[key] {
+			return nil, fmt.Errorf("duplicate color name %q", name)
+		}
+		seen[key] = true
+		result = append(result, config.PaletteColor{Name: name, Hex: strings.ToUpper(c.Hex)})
+	}
+	return result, nil
+}
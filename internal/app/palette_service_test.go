@@ -0,0 +1,100 @@
+package app
+
+import (
+	"context"
+	"eclat/internal/config"
+	"eclat/internal/database"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPaletteService_CRUD(t *testing.T) {
+	service, _, _ := setupPaletteServiceTest(t)
+
+	palettes, err := service.GetPalettes()
+	assert.NoError(t, err)
+	if assert.Len(t, palettes, 1, "Migration seeds the built-in palette") {
+		assert.Equal(t, "Default", palettes[0].Name)
+		assert.True(t, palettes[0].IsActive)
+		assert.Equal(t, config.PredefinedPalette, palettes[0].Colors)
+	}
+
+	biome, err := service.CreatePalette("  Biome ", []config.PaletteColor{{Name: "Moss", Hex: "#4a5d23"}})
+	assert.NoError(t, err)
+	assert.Equal(t, "Biome", biome.Name)
+	assert.Equal(t, "#4A5D23", biome.Colors[0].Hex, "Hex is normalized to upper case")
+	assert.False(t, biome.IsActive)
+
+	_, err = service.CreatePalette("Biome", []config.PaletteColor{{Name: "Moss", Hex: "#4A5D23"}})
+	assert.Error(t, err, "Duplicate palette name")
+	_, err = service.CreatePalette("Brand", []config.PaletteColor{{Name: "Logo", Hex: "red"}})
+	assert.Error(t, err, "Invalid hex")
+	_, err = service.CreatePalette("Brand", []config.PaletteColor{{Name: "Sky Blue", Hex: "#87CEEB"}, {Name: "sky-blue", Hex: "#87CEEA"}})
+	assert.Error(t, err, "Names must stay unique for color:<name> search")
+	_, err = service.CreatePalette("Brand", nil)
+	assert.Error(t, err)
+
+	updated, err := service.UpdatePalette(biome.ID, "Forest", []config.PaletteColor{{Name: "Moss", Hex: "#4A5D23"}, {Name: "Bark", Hex: "#5C4033"}})
+	assert.NoError(t, err)
+	assert.Equal(t, "Forest", updated.Name)
+	assert.Len(t, updated.Colors, 2)
+
+	assert.Error(t, service.DeletePalette(palettes[0].ID), "Active palette cannot be deleted")
+	assert.NoError(t, service.DeletePalette(biome.ID))
+}
+
+func TestPaletteService_SetActivePalette_RemapsAssets(t *testing.T) {
+	service, assetService, queries := setupPaletteServiceTest(t)
+	ctx := context.Background()
+
+	bark := insertTestAssetWithParams(t, queries, "bark.png", "/lib/bark.png", false, false)
+	legacy := insertTestAssetWithParams(t, queries, "legacy.png", "/lib/legacy.png", false, false)
+
+	for i, hex := range []string{"#7C5432", "#C01010"} {
+		err := queries.InsertAssetColor(ctx, database.InsertAssetColorParams{
+			AssetID: bark.ID, Position: int64(i), Hex: hex, PaletteName: "Old", PaletteHex: "#000001", Weight: 0.5,
+		})
+		assert.NoError(t, err)
+	}
+	_, err := assetService.sysDB.Exec("UPDATE assets SET dominant_color = '#FF0000' WHERE id = ?", legacy.ID)
+	assert.NoError(t, err)
+
+	biome, err := service.CreatePalette("Biome", []config.PaletteColor{
+		{Name: "Dirt", Hex: "#704828"},
+		{Name: "Blood", Hex: "#AA0000"},
+		{Name: "Moss", Hex: "#4A5D23"},
+	})
+	assert.NoError(t, err)
+
+	active, err := service.SetActivePalette(biome.ID)
+	assert.NoError(t, err)
+	assert.True(t, active.IsActive)
+	service.waitForRemap()
+
+	assert.Equal(t, biome.Colors, service.config.GetColorPalette(), "Scanner picks up the palette immediately")
+
+	colors, err := queries.ListAssetColors(ctx, bark.ID)
+	assert.NoError(t, err)
+	if assert.Len(t, colors, 2) {
+		assert.Equal(t, "Dirt", colors[0].PaletteName)
+		assert.Equal(t, "#704828", colors[0].PaletteHex)
+		assert.Equal(t, "#7C5432", colors[0].Hex, "Raw colors are kept")
+		assert.Equal(t, "Blood", colors[1].PaletteName)
+	}
+
+	barkRow, err := queries.GetAssetById(ctx, bark.ID)
+	assert.NoError(t, err)
+	assert.Equal(t, "#704828", barkRow.DominantColor.String)
+	legacyRow, err := queries.GetAssetById(ctx, legacy.ID)
+	assert.NoError(t, err)
+	assert.Equal(t, "#AA0000", legacyRow.DominantColor.String, "Assets without a stored palette are re-mapped from dominant_color")
+
+	// Nazwy kolorów w wyszukiwarce pochodzą z aktywnej palety
+	res, err := assetService.GetAssets(AssetQueryFilters{Page: 1, PageSize: 10, Query: "color:dirt"})
+	assert.NoError(t, err)
+	if assert.Len(t, res.Items, 1) {
+		assert.Equal(t, bark.ID, res.Items[0].ID)
+	}
+	assert.NotNil(t, assetService.ValidateSearchQuery("color:red"), "Red is not part of the active palette")
+}
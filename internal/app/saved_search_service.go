@@ -0,0 +1,414 @@
+package app
+
+import (
+	"context"
+	"database/sql"
+	"eclat/internal/config"
+	"eclat/internal/database"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"regexp"
+	"slices"
+	"strings"
+	"time"
+)
+
+// savedSearchSchemaVersion is the current version of the JSON document stored in saved_searches.filter_json.
+// Bump it (and register a migration in savedSearchMigrations) only on breaking changes to AssetQueryFilters:
+// renamed or removed fields, or fields whose meaning changes. New optional fields decode fine without a bump.
+const savedSearchSchemaVersion = 1
+
+// savedSearchMigrations upgrades a raw filter object from version N to N+1.
+// Version 0 is the legacy format: a bare AssetQueryFilters object without the versioned envelope.
+var savedSearchMigrations = map[int]func(map[string]any) map[string]any{
+	0: func(f map[string]any) map[string]any {
+		// Legacy documents could carry pagination state; saved searches must not.
+		delete(f, "page")
+		delete(f, "pageSize")
+		return f
+	},
+}
+
+// savedSearchDocument is the versioned envelope persisted in the database.
+type savedSearchDocument struct {
+	Version int             `json:"version"`
+	Filters json.RawMessage `json:"filters"`
+}
+
+var hexColorPattern = regexp.MustCompile(`^#[0-9A-Fa-f]{6}$`)
+
+// SavedSearchService manages "smart collections" - named AssetQueryFilters that are
+// re-evaluated every time they are opened, so they stay live as the library changes.
+type SavedSearchService struct {
+	ctx    context.Context
+	db     database.Querier
+	logger *slog.Logger
+}
+
+func NewSavedSearchService(db database.Querier, logger *slog.Logger) *SavedSearchService {
+	return &SavedSearchService{
+		db:     db,
+		logger: logger,
+	}
+}
+
+func (s *SavedSearchService) Startup(ctx context.Context) {
+	s.ctx = ctx
+}
+
+// SavedSearch is the DTO returned to the frontend.
+type SavedSearch struct {
+	ID        int64             `json:"id"`
+	Name      string            `json:"name"`
+	Filters   AssetQueryFilters `json:"filters"`
+	DateAdded time.Time         `json:"dateAdded"`
+}
+
+// GetAll returns all saved searches ordered by name.
+func (s *SavedSearchService) GetAll() ([]SavedSearch, error) {
+	ctx := s.ctxOrBackground()
+	rows, err := s.db.ListSavedSearches(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	results := make([]SavedSearch, 0, len(rows))
+	for _, r := range rows {
+		ss, err := s.toDTO(ctx, r)
+		if err != nil {
+			// A single corrupted row should not hide the rest of the list.
+			s.logger.Error("Failed to decode saved search", "id", r.ID, "error", err)
+			continue
+		}
+		results = append(results, ss)
+	}
+	return results, nil
+}
+
+// GetById returns a single saved search.
+func (s *SavedSearchService) GetById(id int64) (*SavedSearch, error) {
+	ctx := s.ctxOrBackground()
+	row, err := s.db.GetSavedSearchById(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	ss, err := s.toDTO(ctx, row)
+	if err != nil {
+		return nil, err
+	}
+	return &ss, nil
+}
+
+// Create validates the filters and stores them under the given name.
+func (s *SavedSearchService) Create(name string, filters AssetQueryFilters) (*SavedSearch, error) {
+	name, err := validateSavedSearchName(name)
+	if err != nil {
+		return nil, err
+	}
+	if err := validateAssetQueryFilters(filters, activeColorPalette(s.ctxOrBackground(), s.db)); err != nil {
+		return nil, err
+	}
+	payload, err := encodeSavedSearchFilters(filters)
+	if err != nil {
+		return nil, err
+	}
+
+	ctx := s.ctxOrBackground()
+	if _, err := s.db.GetSavedSearchByName(ctx, name); err == nil {
+		return nil, fmt.Errorf("saved search %q already exists", name)
+	}
+
+	row, err := s.db.CreateSavedSearch(ctx, database.CreateSavedSearchParams{
+		Name:       name,
+		FilterJson: payload,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return s.GetById(row.ID)
+}
+
+// Rename changes the display name of a saved search.
+func (s *SavedSearchService) Rename(id int64, name string) (*SavedSearch, error) {
+	name, err := validateSavedSearchName(name)
+	if err != nil {
+		return nil, err
+	}
+
+	ctx := s.ctxOrBackground()
+	if existing, err := s.db.GetSavedSearchByName(ctx, name); err == nil && existing.ID != id {
+		return nil, fmt.Errorf("saved search %q already exists", name)
+	}
+
+	_, err = s.db.UpdateSavedSearch(ctx, database.UpdateSavedSearchParams{
+		ID:   id,
+		Name: sql.NullString{String: name, Valid: true},
+	})
+	if err != nil {
+		return nil, err
+	}
+	return s.GetById(id)
+}
+
+// Update replaces the filters of a saved search, keeping its name.
+func (s *SavedSearchService) Update(id int64, filters AssetQueryFilters) (*SavedSearch, error) {
+	if err := validateAssetQueryFilters(filters, activeColorPalette(s.ctxOrBackground(), s.db)); err != nil {
+		return nil, err
+	}
+	payload, err := encodeSavedSearchFilters(filters)
+	if err != nil {
+		return nil, err
+	}
+
+	_, err = s.db.UpdateSavedSearch(s.ctxOrBackground(), database.UpdateSavedSearchParams{
+		ID:         id,
+		FilterJson: sql.NullString{String: payload, Valid: true},
+	})
+	if err != nil {
+		return nil, err
+	}
+	return s.GetById(id)
+}
+
+// Duplicate copies a saved search. If newName is empty, a unique "<name> (copy)" name is generated.
+func (s *SavedSearchService) Duplicate(id int64, newName string) (*SavedSearch, error) {
+	original, err := s.GetById(id)
+	if err != nil {
+		return nil, err
+	}
+
+	if strings.TrimSpace(newName) == "" {
+		newName = s.nextCopyName(original.Name)
+	}
+	return s.Create(newName, original.Filters)
+}
+
+// Delete removes a saved search.
+func (s *SavedSearchService) Delete(id int64) error {
+	return s.db.DeleteSavedSearch(s.ctxOrBackground(), id)
+}
+
+// nextCopyName finds the first free "<name> (copy)", "<name> (copy 2)", ... name.
+func (s *SavedSearchService) nextCopyName(name string) string {
+	ctx := s.ctxOrBackground()
+	candidate := name + " (copy)"
+	for i := 2; ; i++ {
+		if _, err := s.db.GetSavedSearchByName(ctx, candidate); err != nil {
+			return candidate
+		}
+		candidate = fmt.Sprintf("%s (copy %d)", name, i)
+	}
+}
+
+// toDTO decodes the stored filters and lazily persists the upgraded document if a migration ran.
+func (s *SavedSearchService) toDTO(ctx context.Context, row database.SavedSearch) (SavedSearch, error) {
+	filters, migrated, err := decodeSavedSearchFilters(row.FilterJson)
+	if err != nil {
+		return SavedSearch{}, err
+	}
+
+	if migrated {
+		if payload, err := encodeSavedSearchFilters(filters); err == nil {
+			_, err := s.db.UpdateSavedSearch(ctx, database.UpdateSavedSearchParams{
+				ID:         row.ID,
+				FilterJson: sql.NullString{String: payload, Valid: true},
+			})
+			if err != nil {
+				s.logger.Warn("Failed to persist migrated saved search", "id", row.ID, "error", err)
+			} else {
+				s.logger.Info("Migrated saved search", "id", row.ID, "version", savedSearchSchemaVersion)
+			}
+		}
+	}
+
+	return SavedSearch{
+		ID:        row.ID,
+		Name:      row.Name,
+		Filters:   filters,
+		DateAdded: row.DateAdded,
+	}, nil
+}
+
+func (s *SavedSearchService) ctxOrBackground() context.Context {
+	if s.ctx != nil {
+		return s.ctx
+	}
+	return context.Background()
+}
+
+// encodeSavedSearchFilters serializes filters into the current versioned envelope.
+// Pagination is stripped, because it belongs to the view, not to the search.
+func encodeSavedSearchFilters(filters AssetQueryFilters) (string, error) {
+	filters.Page = 0
+	filters.PageSize = 0
+
+	raw, err := json.Marshal(filters)
+	if err != nil {
+		return "", fmt.Errorf("failed to encode filters: %w", err)
+	}
+	doc, err := json.Marshal(savedSearchDocument{
+		Version: savedSearchSchemaVersion,
+		Filters: raw,
+	})
+	if err != nil {
+		return "", fmt.Errorf("failed to encode saved search: %w", err)
+	}
+	return string(doc), nil
+}
+
+// decodeSavedSearchFilters parses a stored document of any known version into the current
+// AssetQueryFilters. The boolean result reports whether a migration was applied.
+func decodeSavedSearchFilters(payload string) (AssetQueryFilters, bool, error) {
+	var filters AssetQueryFilters
+
+	var probe map[string]json.RawMessage
+	if err := json.Unmarshal([]byte(payload), &probe); err != nil {
+		return filters, false, fmt.Errorf("invalid saved search json: %w", err)
+	}
+
+	version := 0
+	rawFilters := []byte(payload)
+	if _, hasVersion := probe["version"]; hasVersion {
+		var doc savedSearchDocument
+		if err := json.Unmarshal([]byte(payload), &doc); err != nil {
+			return filters, false, fmt.Errorf("invalid saved search envelope: %w", err)
+		}
+		version = doc.Version
+		rawFilters = doc.Filters
+	}
+
+	if version > savedSearchSchemaVersion {
+		return filters, false, fmt.Errorf("saved search version %d is newer than supported version %d", version, savedSearchSchemaVersion)
+	}
+
+	migrated := version < savedSearchSchemaVersion
+	if migrated {
+		var generic map[string]any
+		if err := json.Unmarshal(rawFilters, &generic); err != nil {
+			return filters, false, fmt.Errorf("invalid saved search filters: %w", err)
+		}
+		if generic == nil {
+			generic = map[string]any{}
+		}
+		for v := version; v < savedSearchSchemaVersion; v++ {
+			if migrate, ok := savedSearchMigrations[v]; ok {
+				generic = migrate(generic)
+			}
+		}
+		upgraded, err := json.Marshal(generic)
+		if err != nil {
+			return filters, false, err
+		}
+		rawFilters = upgraded
+	}
+
+	if err := json.Unmarshal(rawFilters, &filters); err != nil {
+		return filters, false, fmt.Errorf("invalid saved search filters: %w", err)
+	}
+	return filters, migrated, nil
+}
+
+func validateSavedSearchName(name string) (string, error) {
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return "", errors.New("saved search name cannot be empty")
+	}
+	if len(name) > 100 {
+		return "", errors.New("saved search name too long (max 100 chars)")
+	}
+	return name, nil
+}
+
+// validateAssetQueryFilters rejects filters that GetAssets would silently misinterpret.
+// palette is the active color palette, used to check color names in the query.
+func validateAssetQueryFilters(f AssetQueryFilters, palette []config.PaletteColor) error {
+	checkRange := func(name string, r []int, min, max int) error {
+		if len(r) == 0 {
+			return nil
+		}
+		if len(r) != 2 {
+			return fmt.Errorf("%s must have exactly 2 values [min, max]", name)
+		}
+		if r[0] < min || r[1] > max {
+			return fmt.Errorf("%s must be within [%d, %d]", name, min, max)
+		}
+		if r[0] > r[1] {
+			return fmt.Errorf("%s min is greater than max", name)
+		}
+		return nil
+	}
+
+	if err := checkRange("ratingRange", f.RatingRange, 0, 5); err != nil {
+		return err
+	}
+	if err := checkRange("widthRange", f.WidthRange, 0, 1<<20); err != nil {
+		return err
+	}
+	if err := checkRange("heightRange", f.HeightRange, 0, 1<<20); err != nil {
+		return err
+	}
+	if err := checkRange("fileSizeRange", f.FileSizeRange, 0, MaxFileSizeMB); err != nil {
+		return err
+	}
+	if err := checkRange("polyCountRange", f.PolyCountRange, 0, MaxPolyCount); err != nil {
+		return err
+	}
+
+	validTypes := []string{"image", "model", "texture", "other"}
+	for _, t := range f.FileTypes {
+		if !slices.Contains(validTypes, t) {
+			return fmt.Errorf("unknown file type %q", t)
+		}
+	}
+
+	for _, c := range f.Colors {
+		if !hexColorPattern.MatchString(c) {
+			return fmt.Errorf("invalid color %q (expected #RRGGBB)", c)
+		}
+	}
+	if f.ColorWeightThreshold < 0 || f.ColorWeightThreshold > 1 {
+		return fmt.Errorf("colorWeightThreshold must be between 0 and 1")
+	}
+	if f.ColorSearch != nil && f.ColorSearch.Hex != "" {
+		if !hexColorPattern.MatchString(f.ColorSearch.Hex) {
+			return fmt.Errorf("invalid color %q (expected #RRGGBB)", f.ColorSearch.Hex)
+		}
+		if f.ColorSearch.Tolerance < 0 || f.ColorSearch.Tolerance > MaxColorTolerance {
+			return fmt.Errorf("color tolerance must be between 0 and %.0f", MaxColorTolerance)
+		}
+	}
+
+	for _, d := range []*string{f.DateRange.From, f.DateRange.To} {
+		if d == nil || *d == "" {
+			continue
+		}
+		if !isValidFilterDate(*d) {
+			return fmt.Errorf("invalid date %q", *d)
+		}
+	}
+
+	switch strings.ToLower(f.SortOption) {
+	case "", "filename", "filesize", "lastmodified", "rating", "dateadded", "relevance":
+	default:
+		return fmt.Errorf("unknown sort option %q", f.SortOption)
+	}
+
+	if len(f.Query) > 500 {
+		return errors.New("search query too long (max 500 chars)")
+	}
+	if _, err := parseSearchQuery(f.Query, time.Now(), palette); err != nil {
+		return err
+	}
+	return nil
+}
+
+func isValidFilterDate(v string) bool {
+	for _, layout := range []string{"2006-01-02", "2006-01-02 15:04:05", time.RFC3339} {
+		if _, err := time.Parse(layout, v); err == nil {
+			return true
+		}
+	}
+	return false
+}
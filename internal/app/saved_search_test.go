@@ -0,0 +1,146 @@
+package app
+
+import (
+	"context"
+	"database/sql"
+	"eclat/internal/config"
+	"eclat/internal/database"
+	"io"
+	"log/slog"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func setupSavedSearchServiceTest(t *testing.T) (*SavedSearchService, *AssetService, database.Querier) {
+	sysDB, queries := setupTestDB(t)
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	service := NewSavedSearchService(queries, logger)
+	service.Startup(context.Background())
+	assetService := NewAssetService(queries, sysDB, logger, &MockNotifier{}, "/tmp", newTestTrashBin(t, logger), NewJournal(sysDB, logger), config.NewScannerConfig(), nil)
+	assetService.Startup(context.Background())
+	return service, assetService, queries
+}
+
+func TestSavedSearchService_CRUD(t *testing.T) {
+	service, _, _ := setupSavedSearchServiceTest(t)
+
+	created, err := service.Create("Red 4K textures", AssetQueryFilters{
+		Page:       3,
+		PageSize:   50,
+		FileTypes:  []string{"texture"},
+		Colors:     []string{"#FF0000"},
+		WidthRange: []int{3840, 8160},
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, "Red 4K textures", created.Name)
+	assert.Equal(t, []string{"texture"}, created.Filters.FileTypes)
+	assert.Equal(t, 0, created.Filters.Page, "Pagination should not be persisted")
+
+	// Duplicate names are rejected
+	_, err = service.Create("Red 4K textures", AssetQueryFilters{})
+	assert.Error(t, err)
+
+	renamed, err := service.Rename(created.ID, "Red UHD textures")
+	assert.NoError(t, err)
+	assert.Equal(t, "Red UHD textures", renamed.Name)
+
+	updated, err := service.Update(created.ID, AssetQueryFilters{Colors: []string{"#0000FF"}})
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"#0000FF"}, updated.Filters.Colors)
+	assert.Empty(t, updated.Filters.FileTypes)
+
+	dup, err := service.Duplicate(created.ID, "")
+	assert.NoError(t, err)
+	assert.Equal(t, "Red UHD textures (copy)", dup.Name)
+	dup2, err := service.Duplicate(created.ID, "")
+	assert.NoError(t, err)
+	assert.Equal(t, "Red UHD textures (copy 2)", dup2.Name)
+
+	all, err := service.GetAll()
+	assert.NoError(t, err)
+	assert.Len(t, all, 3)
+
+	assert.NoError(t, service.Delete(dup.ID))
+	all, _ = service.GetAll()
+	assert.Len(t, all, 2)
+}
+
+func TestSavedSearchService_Validation(t *testing.T) {
+	service, _, _ := setupSavedSearchServiceTest(t)
+
+	cases := map[string]AssetQueryFilters{
+		"bad rating":  {RatingRange: []int{0, 9}},
+		"inverted":    {WidthRange: []int{4096, 1024}},
+		"short range": {HeightRange: []int{100}},
+		"size in MB":  {FileSizeRange: []int{0, 10 << 20}},
+		"bad type":    {FileTypes: []string{"audio"}},
+		"bad color":   {Colors: []string{"red"}},
+		"bad sort":    {SortOption: "random"},
+	}
+	for name, f := range cases {
+		t.Run(name, func(t *testing.T) {
+			_, err := service.Create(name, f)
+			assert.Error(t, err)
+		})
+	}
+
+	_, err := service.Create("   ", AssetQueryFilters{})
+	assert.Error(t, err, "Empty name should be rejected")
+}
+
+func TestSavedSearchService_MigratesLegacyJSON(t *testing.T) {
+	service, _, queries := setupSavedSearchServiceTest(t)
+	ctx := context.Background()
+
+	// Legacy rows stored a bare AssetQueryFilters object without the versioned envelope.
+	row, err := queries.CreateSavedSearch(ctx, database.CreateSavedSearchParams{
+		Name:       "Legacy",
+		FilterJson: `{"page":2,"pageSize":20,"searchQuery":"rock","onlyFavorites":true}`,
+	})
+	assert.NoError(t, err)
+
+	ss, err := service.GetById(row.ID)
+	assert.NoError(t, err)
+	assert.Equal(t, "rock", ss.Filters.Query)
+	assert.True(t, ss.Filters.OnlyFavorites)
+	assert.Equal(t, 0, ss.Filters.Page)
+
+	// The upgraded document is written back.
+	stored, err := queries.GetSavedSearchById(ctx, row.ID)
+	assert.NoError(t, err)
+	assert.Contains(t, stored.FilterJson, `"version":1`)
+
+	// Documents from a newer app version are refused instead of misread.
+	_, err = queries.UpdateSavedSearch(ctx, database.UpdateSavedSearchParams{
+		ID:         row.ID,
+		FilterJson: sql.NullString{String: `{"version":99,"filters":{}}`, Valid: true},
+	})
+	assert.NoError(t, err)
+	_, err = service.GetById(row.ID)
+	assert.Error(t, err)
+}
+
+func TestAssetService_GetAssetsBySavedSearch(t *testing.T) {
+	service, assetService, queries := setupSavedSearchServiceTest(t)
+
+	insertTestAssetWithParams(t, queries, "rock_albedo.png", "/tmp/lib/rock_albedo.png", false, false)
+	insertTestAssetWithParams(t, queries, "grass.png", "/tmp/lib/grass.png", false, false)
+
+	ss, err := service.Create("Rocks", AssetQueryFilters{Query: "rock"})
+	assert.NoError(t, err)
+
+	res, err := assetService.GetAssetsBySavedSearch(ss.ID, 1, 10)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, res.TotalCount)
+	assert.Equal(t, "rock_albedo.png", res.Items[0].FileName)
+
+	// The view stays live: new matching assets show up without touching the saved search.
+	insertTestAssetWithParams(t, queries, "rock_normal.png", "/tmp/lib/rock_normal.png", false, false)
+	res, err = assetService.GetAssetsBySavedSearch(ss.ID, 1, 10)
+	assert.NoError(t, err)
+	assert.Equal(t, 2, res.TotalCount)
+
+	_, err = assetService.GetAssetsBySavedSearch(9999, 1, 10)
+	assert.Error(t, err)
+}
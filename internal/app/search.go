@@ -0,0 +1,90 @@
+package app
+
+import (
+	"context"
+	"eclat/internal/database"
+	"log/slog"
+	"strings"
+	"unicode"
+)
+
+// ftsColumnWeights are the bm25() weights for the assets_fts columns:
+// file_name, file_path, description, tags. A hit in the name or a tag matters
+// more than a hit somewhere in a long folder path.
+const ftsColumnWeights = "10.0, 2.0, 1.0, 5.0"
+
+// buildFTSMatchExpression converts free text typed into the gallery search box
+// into a safe FTS5 MATCH expression.
+//
+//   - bare words become prefix queries:  rock alb   -> "rock"* "alb"*
+//   - quoted text becomes a phrase:      "old oak"  -> "old oak"
+//   - separators inside a word are kept as a prefix phrase: rock_alb -> "rock alb"*
+//
+// All terms are ANDed. An empty string is returned when nothing searchable is left.
+func buildFTSMatchExpression(query string) string {
+	var terms []string
+
+	emit := func(text string, prefix bool) {
+		words := splitSearchWords(text)
+		if len(words) == 0 {
+			return
+		}
+		term := `"` + strings.Join(words, " ") + `"`
+		if prefix {
+			term += "*"
+		}
+		terms = append(terms, term)
+	}
+
+	rest := query
+	for {
+		rest = strings.TrimLeftFunc(rest, unicode.IsSpace)
+		if rest == "" {
+			break
+		}
+
+		if rest[0] == '"' {
+			end := strings.IndexByte(rest[1:], '"')
+			if end == -1 {
+				// Unterminated quote - treat the remainder as a phrase.
+				emit(rest[1:], false)
+				break
+			}
+			emit(rest[1:end+1], false)
+			rest = rest[end+2:]
+			continue
+		}
+
+		end := strings.IndexFunc(rest, unicode.IsSpace)
+		if end == -1 {
+			end = len(rest)
+		}
+		emit(rest[:end], true)
+		rest = rest[end:]
+	}
+
+	return strings.Join(terms, " ")
+}
+
+// splitSearchWords splits text the same way the unicode61 tokenizer does:
+// letters and digits form words, everything else is a separator.
+// Splitting here also strips FTS5 operators and quotes from user input.
+func splitSearchWords(text string) []string {
+	return strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+}
+
+// reindexAssetSearch refreshes the full-text search entry of a single asset
+// after its name, path, description or tags change.
+func (s *AssetService) reindexAssetSearch(ctx context.Context, q database.Querier, assetID int64) {
+	reindexSearchEntry(ctx, q, s.logger, assetID)
+}
+
+// reindexSearchEntry refreshes a search entry, logging failures only: a stale entry
+// must not fail the change that made it stale.
+func reindexSearchEntry(ctx context.Context, q database.Querier, logger *slog.Logger, assetID int64) {
+	if err := database.ReindexAssetSearch(ctx, q, assetID); err != nil {
+		logger.Warn("Failed to refresh search index entry", "id", assetID, "error", err)
+	}
+}
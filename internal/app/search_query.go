@@ -0,0 +1,673 @@
+package app
+
+import (
+	"eclat/internal/config"
+	"errors"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+	"unicode/utf8"
+
+	sq "github.com/Masterminds/squirrel"
+)
+
+// The gallery search box understands a small query language on top of plain text:
+//
+//	type:texture tag:wood -tag:wip rating>=4 w>=2048 ext:exr color:red added:<30d folder:"Megascans"
+//
+//   - terms separated by whitespace are ANDed
+//   - OR (upper case) or | between two terms makes an OR group; OR binds tighter than AND,
+//     so "type:texture tag:wood OR tag:metal" means texture AND (wood OR metal)
+//   - parentheses group terms, a leading - negates a term or a group
+//   - comma separated values are alternatives: type:texture,model
+//   - everything that is not a field term is free text for the full-text search
+//
+// Supported fields and operators (":" and "=" mean equality):
+//
+//	type, tag, ext, color, folder   :
+//	rating, w/width, h/height       : = > >= < <=
+//	size                            : = > >= < <=   (units: b, kb, mb, gb; default mb)
+//	added                           : = > >= < <=   (YYYY-MM-DD or an age like 30d, 12h, 2w, 6m, 1y)
+//
+// For ages the comparison is on the age, not on the date: added:<30d means "added less than 30 days ago".
+// color:red matches assets where the palette color is a significant one, color:#7A5230 matches
+// colors within ΔE 10 of the given one (color:#7A5230~20 widens the tolerance).
+
+// SearchQueryError describes a syntax or value error in a search query.
+// Pos is the zero-based character (rune) offset of the offending token, so the UI can highlight it.
+type SearchQueryError struct {
+	Pos     int    `json:"pos"`
+	Message string `json:"message"`
+}
+
+func (e *SearchQueryError) Error() string {
+	return fmt.Sprintf("invalid search query at character %d: %s", e.Pos+1, e.Message)
+}
+
+// ValidateSearchQuery checks the search box text without running it.
+// Returns nil when the query is valid, so the frontend can highlight errors while the user types.
+func (s *AssetService) ValidateSearchQuery(query string) *SearchQueryError {
+	ctx := s.ctxOrBackground()
+	if _, err := parseSearchQuery(query, time.Now(), activeColorPalette(ctx, s.db)); err != nil {
+		var qErr *SearchQueryError
+		if errors.As(err, &qErr) {
+			return qErr
+		}
+		return &SearchQueryError{Message: err.Error()}
+	}
+	return nil
+}
+
+// searchQueryFields maps every accepted field name (including aliases) to its canonical name.
+var searchQueryFields = map[string]string{
+	"type":   "type",
+	"tag":    "tag",
+	"ext":    "ext",
+	"color":  "color",
+	"colour": "color",
+	"folder": "folder",
+	"rating": "rating",
+	"w":      "width",
+	"width":  "width",
+	"h":      "height",
+	"height": "height",
+	"size":   "size",
+	"added":  "added",
+}
+
+// searchQueryComparators are the operators allowed after a field name, longest first.
+var searchQueryComparators = []string{">=", "<=", ">", "<", "="}
+
+// searchQuery is the result of parsing the search box: free text for the FTS search,
+// the rating range folded into the existing filter field, and everything else as a SQL condition.
+type searchQuery struct {
+	Text        string
+	RatingRange []int
+	Conditions  sq.And
+}
+
+// Apply merges the parsed query into filters and returns the extra WHERE conditions.
+func (q *searchQuery) Apply(filters *AssetQueryFilters) sq.And {
+	filters.Query = q.Text
+	if q.RatingRange != nil {
+		if len(filters.RatingRange) == 2 {
+			filters.RatingRange = []int{max(filters.RatingRange[0], q.RatingRange[0]), min(filters.RatingRange[1], q.RatingRange[1])}
+		} else {
+			filters.RatingRange = q.RatingRange
+		}
+	}
+	return q.Conditions
+}
+
+// parseSearchQuery parses the search box text. now anchors relative dates (added:<30d),
+// palette resolves color names (color:red).
+func parseSearchQuery(input string, now time.Time, palette []config.PaletteColor) (*searchQuery, error) {
+	tokens, err := tokenizeSearchQuery(input)
+	if err != nil {
+		return nil, err
+	}
+
+	p := &searchQueryParser{tokens: tokens, now: now, palette: palette}
+	root, err := p.parseAnd(0)
+	if err != nil {
+		return nil, err
+	}
+	if tok := p.peek(); tok.kind != tokEOF {
+		return nil, &SearchQueryError{Pos: tok.pos, Message: fmt.Sprintf("unexpected %q", tok.text)}
+	}
+
+	result := &searchQuery{}
+	var text []string
+	for _, node := range root.children {
+		switch {
+		case node.kind == nodeText:
+			// Top-level text keeps its quotes so the FTS search can still tell phrases from words.
+			text = append(text, node.term.raw)
+		case node.kind == nodeTerm && node.term.field == "rating":
+			result.RatingRange = foldRatingRange(result.RatingRange, node.term)
+		default:
+			cond, err := p.compile(node)
+			if err != nil {
+				return nil, err
+			}
+			result.Conditions = append(result.Conditions, cond)
+		}
+	}
+	result.Text = strings.Join(text, " ")
+	return result, nil
+}
+
+// ==========================================
+// Tokenizer
+// ==========================================
+
+type searchTokenKind int
+
+const (
+	tokEOF searchTokenKind = iota
+	tokWord
+	tokLParen
+	tokRParen
+	tokOr
+	tokNot
+)
+
+type searchToken struct {
+	kind searchTokenKind
+	text string
+	pos  int
+}
+
+func tokenizeSearchQuery(input string) ([]searchToken, error) {
+	runes := []rune(input)
+	var tokens []searchToken
+
+	i := 0
+	for i < len(runes) {
+		r := runes[i]
+		switch {
+		case unicode.IsSpace(r):
+			i++
+		case r == '(':
+			tokens = append(tokens, searchToken{kind: tokLParen, text: "(", pos: i})
+			i++
+		case r == ')':
+			tokens = append(tokens, searchToken{kind: tokRParen, text: ")", pos: i})
+			i++
+		case r == '|':
+			tokens = append(tokens, searchToken{kind: tokOr, text: "|", pos: i})
+			i++
+		case r == '-' && i+1 < len(runes) && !unicode.IsSpace(runes[i+1]) && runes[i+1] != ')':
+			tokens = append(tokens, searchToken{kind: tokNot, text: "-", pos: i})
+			i++
+		default:
+			start := i
+			for i < len(runes) && !unicode.IsSpace(runes[i]) && runes[i] != '(' && runes[i] != ')' {
+				if runes[i] == '"' {
+					end := slices.Index(runes[i+1:], '"')
+					if end == -1 {
+						return nil, &SearchQueryError{Pos: i, Message: "unterminated quote"}
+					}
+					i += end + 2
+					continue
+				}
+				i++
+			}
+			text := string(runes[start:i])
+			kind := tokWord
+			if text == "OR" {
+				kind = tokOr
+			}
+			tokens = append(tokens, searchToken{kind: kind, text: text, pos: start})
+		}
+	}
+	return append(tokens, searchToken{kind: tokEOF, pos: len(runes)}), nil
+}
+
+// ==========================================
+// Parser
+// ==========================================
+
+type searchNodeKind int
+
+const (
+	nodeText searchNodeKind = iota
+	nodeTerm
+	nodeAnd
+	nodeOr
+	nodeNot
+)
+
+type searchNode struct {
+	kind     searchNodeKind
+	term     searchTerm
+	children []*searchNode
+}
+
+// searchTerm is a single word: either free text or field:value.
+type searchTerm struct {
+	raw      string
+	field    string
+	op       string
+	value    string
+	pos      int
+	valuePos int
+}
+
+type searchQueryParser struct {
+	tokens  []searchToken
+	i       int
+	now     time.Time
+	palette []config.PaletteColor
+}
+
+func (p *searchQueryParser) peek() searchToken { return p.tokens[p.i] }
+
+func (p *searchQueryParser) next() searchToken {
+	tok := p.tokens[p.i]
+	if tok.kind != tokEOF {
+		p.i++
+	}
+	return tok
+}
+
+// parseAnd reads OR groups until the end of input or a closing parenthesis.
+func (p *searchQueryParser) parseAnd(depth int) (*searchNode, error) {
+	node := &searchNode{kind: nodeAnd}
+	for {
+		tok := p.peek()
+		if tok.kind == tokEOF || (tok.kind == tokRParen && depth > 0) {
+			return node, nil
+		}
+		child, err := p.parseOr(depth)
+		if err != nil {
+			return nil, err
+		}
+		node.children = append(node.children, child)
+	}
+}
+
+func (p *searchQueryParser) parseOr(depth int) (*searchNode, error) {
+	if tok := p.peek(); tok.kind == tokOr {
+		return nil, &SearchQueryError{Pos: tok.pos, Message: "OR needs a term on its left side"}
+	}
+	first, err := p.parseUnary(depth)
+	if err != nil {
+		return nil, err
+	}
+	if p.peek().kind != tokOr {
+		return first, nil
+	}
+
+	node := &searchNode{kind: nodeOr, children: []*searchNode{first}}
+	for p.peek().kind == tokOr {
+		op := p.next()
+		switch p.peek().kind {
+		case tokEOF, tokRParen, tokOr:
+			return nil, &SearchQueryError{Pos: op.pos, Message: "OR needs a term on its right side"}
+		}
+		child, err := p.parseUnary(depth)
+		if err != nil {
+			return nil, err
+		}
+		node.children = append(node.children, child)
+	}
+	return node, nil
+}
+
+func (p *searchQueryParser) parseUnary(depth int) (*searchNode, error) {
+	tok := p.next()
+	switch tok.kind {
+	case tokNot:
+		child, err := p.parseUnary(depth)
+		if err != nil {
+			return nil, err
+		}
+		return &searchNode{kind: nodeNot, children: []*searchNode{child}}, nil
+	case tokLParen:
+		group, err := p.parseAnd(depth + 1)
+		if err != nil {
+			return nil, err
+		}
+		if closing := p.next(); closing.kind != tokRParen {
+			return nil, &SearchQueryError{Pos: tok.pos, Message: "unclosed parenthesis"}
+		}
+		if len(group.children) == 0 {
+			return nil, &SearchQueryError{Pos: tok.pos, Message: "empty parentheses"}
+		}
+		if len(group.children) == 1 {
+			return group.children[0], nil
+		}
+		return group, nil
+	case tokRParen:
+		return nil, &SearchQueryError{Pos: tok.pos, Message: "unmatched closing parenthesis"}
+	case tokEOF:
+		return nil, &SearchQueryError{Pos: tok.pos, Message: "unexpected end of query"}
+	}
+	return p.parseTerm(tok)
+}
+
+// parseTerm splits a word into field, operator and value. Words that do not start with
+// a letters-only key followed by ":" or a comparator are free text.
+func (p *searchQueryParser) parseTerm(tok searchToken) (*searchNode, error) {
+	keyLen := strings.IndexFunc(tok.text, func(r rune) bool { return !unicode.IsLetter(r) })
+	if keyLen <= 0 {
+		return &searchNode{kind: nodeText, term: searchTerm{raw: tok.text, pos: tok.pos}}, nil
+	}
+	rest := tok.text[keyLen:]
+
+	op := ""
+	if strings.HasPrefix(rest, ":") {
+		op = ":"
+		rest = rest[1:]
+	}
+	for _, cmp := range searchQueryComparators {
+		if strings.HasPrefix(rest, cmp) {
+			op = cmp
+			rest = rest[len(cmp):]
+			break
+		}
+	}
+	if op == "" {
+		return &searchNode{kind: nodeText, term: searchTerm{raw: tok.text, pos: tok.pos}}, nil
+	}
+
+	key := strings.ToLower(tok.text[:keyLen])
+	field, ok := searchQueryFields[key]
+	if !ok {
+		return nil, &SearchQueryError{Pos: tok.pos, Message: fmt.Sprintf("unknown filter %q", key)}
+	}
+
+	valuePos := tok.pos + utf8.RuneCountInString(tok.text) - utf8.RuneCountInString(rest)
+	value := rest
+	if len(value) >= 2 && strings.HasPrefix(value, `"`) && strings.HasSuffix(value, `"`) {
+		value = value[1 : len(value)-1]
+	}
+	if strings.TrimSpace(value) == "" {
+		return nil, &SearchQueryError{Pos: valuePos, Message: fmt.Sprintf("missing value for %s", key)}
+	}
+
+	term := searchTerm{raw: tok.text, field: field, op: op, value: value, pos: tok.pos, valuePos: valuePos}
+	// Validate eagerly, so errors are reported even for terms that end up folded into filter fields.
+	if _, err := p.compileTerm(term); err != nil {
+		return nil, err
+	}
+	return &searchNode{kind: nodeTerm, term: term}, nil
+}
+
+// ==========================================
+// Compilation to SQL
+// ==========================================
+
+// notExpr negates a condition. COALESCE makes NULL columns (e.g. models without width) count as "not matching",
+// so "-w>=2048" still returns them.
+type notExpr struct {
+	cond sq.Sqlizer
+}
+
+func (n notExpr) ToSql() (string, []interface{}, error) {
+	s, args, err := n.cond.ToSql()
+	if err != nil {
+		return "", nil, err
+	}
+	return "NOT COALESCE((" + s + "), 0)", args, nil
+}
+
+func (p *searchQueryParser) compile(node *searchNode) (sq.Sqlizer, error) {
+	switch node.kind {
+	case nodeText:
+		return ftsCondition(node.term.raw), nil
+	case nodeTerm:
+		return p.compileTerm(node.term)
+	case nodeNot:
+		cond, err := p.compile(node.children[0])
+		if err != nil {
+			return nil, err
+		}
+		return notExpr{cond: cond}, nil
+	case nodeOr, nodeAnd:
+		conds := make([]sq.Sqlizer, 0, len(node.children))
+		for _, child := range node.children {
+			cond, err := p.compile(child)
+			if err != nil {
+				return nil, err
+			}
+			conds = append(conds, cond)
+		}
+		if node.kind == nodeOr {
+			return sq.Or(conds), nil
+		}
+		return sq.And(conds), nil
+	}
+	return nil, fmt.Errorf("unknown search node kind %d", node.kind)
+}
+
+// ftsCondition matches free text that cannot be handled by the main FTS join (negated or inside an OR group).
+func ftsCondition(text string) sq.Sqlizer {
+	if match := buildFTSMatchExpression(text); match != "" {
+		return sq.Expr("a.id IN (SELECT rowid FROM assets_fts WHERE assets_fts MATCH ?)", match)
+	}
+	like := "%" + escapeLike(text) + "%"
+	return sq.Or{
+		sq.Expr(`a.file_name LIKE ? ESCAPE '\'`, like),
+		sq.Expr(`a.file_path LIKE ? ESCAPE '\'`, like),
+	}
+}
+
+func (p *searchQueryParser) compileTerm(t searchTerm) (sq.Sqlizer, error) {
+	switch t.field {
+	case "type", "tag", "ext", "color", "folder":
+		if t.op != ":" && t.op != "=" {
+			return nil, &SearchQueryError{Pos: t.valuePos - 1, Message: fmt.Sprintf("operator %q is not supported for %s", t.op, t.field)}
+		}
+		var alternatives sq.Or
+		for _, v := range strings.Split(t.value, ",") {
+			v = strings.TrimSpace(v)
+			if v == "" {
+				return nil, &SearchQueryError{Pos: t.valuePos, Message: fmt.Sprintf("empty value in %s list", t.field)}
+			}
+			cond, err := compileTextField(t, v, p.palette)
+			if err != nil {
+				return nil, err
+			}
+			alternatives = append(alternatives, cond)
+		}
+		if len(alternatives) == 1 {
+			return alternatives[0], nil
+		}
+		return alternatives, nil
+
+	case "rating", "width", "height":
+		n, err := strconv.Atoi(t.value)
+		if err != nil || n < 0 {
+			return nil, &SearchQueryError{Pos: t.valuePos, Message: fmt.Sprintf("%s expects a whole number, got %q", t.field, t.value)}
+		}
+		if t.field == "rating" && n > 5 {
+			return nil, &SearchQueryError{Pos: t.valuePos, Message: "rating must be between 0 and 5"}
+		}
+		column := map[string]string{"rating": "a.rating", "width": "a.image_width", "height": "a.image_height"}[t.field]
+		return compareExpr(column, t.op, n), nil
+
+	case "size":
+		bytes, err := parseSearchSize(t.value)
+		if err != nil {
+			return nil, &SearchQueryError{Pos: t.valuePos, Message: err.Error()}
+		}
+		return compareExpr("a.file_size", t.op, bytes), nil
+
+	case "added":
+		return p.compileAdded(t)
+	}
+	return nil, &SearchQueryError{Pos: t.pos, Message: fmt.Sprintf("unknown filter %q", t.field)}
+}
+
+func compileTextField(t searchTerm, v string, palette []config.PaletteColor) (sq.Sqlizer, error) {
+	switch t.field {
+	case "type":
+		v = strings.ToLower(v)
+		if !slices.Contains([]string{"image", "model", "texture", "other"}, v) {
+			return nil, &SearchQueryError{Pos: t.valuePos, Message: fmt.Sprintf("unknown type %q (expected image, model, texture or other)", v)}
+		}
+		return sq.Eq{"a.file_type": v}, nil
+	case "tag":
+		return sq.Expr("EXISTS (SELECT 1 FROM asset_tags qat JOIN tags qt ON qt.id = qat.tag_id WHERE qat.asset_id = a.id AND qt.name = ? COLLATE NOCASE)", v), nil
+	case "ext":
+		return sq.Expr(`a.file_name LIKE ? ESCAPE '\'`, "%."+escapeLike(strings.TrimPrefix(v, "."))), nil
+	case "color":
+		// #RRGGBB (optionally #RRGGBB~tolerance) searches by Lab distance, a name by palette color.
+		if strings.HasPrefix(v, "#") {
+			hex, tol, hasTol := strings.Cut(v, "~")
+			tolerance := DefaultColorTolerance
+			if hasTol {
+				parsed, err := strconv.ParseFloat(tol, 64)
+				if err != nil || parsed <= 0 || parsed > MaxColorTolerance {
+					return nil, &SearchQueryError{Pos: t.valuePos, Message: fmt.Sprintf("invalid color tolerance %q (expected 1-%.0f)", tol, MaxColorTolerance)}
+				}
+				tolerance = parsed
+			}
+			if !hexColorPattern.MatchString(hex) {
+				return nil, &SearchQueryError{Pos: t.valuePos, Message: fmt.Sprintf("invalid color %q (expected #RRGGBB)", hex)}
+			}
+			return hexColorCondition(hex, tolerance, DefaultColorWeightThreshold)
+		}
+		hex, ok := resolveSearchColor(v, palette)
+		if !ok {
+			return nil, &SearchQueryError{Pos: t.valuePos, Message: fmt.Sprintf("unknown color %q (use a palette name or #RRGGBB)", v)}
+		}
+		return paletteColorCondition([]string{hex}, DefaultColorWeightThreshold), nil
+	case "folder":
+		// Match against the directory part only, so folder:rock does not match rock_albedo.png.
+		return sq.Expr(`substr(a.file_path, 1, length(a.file_path) - length(a.file_name)) LIKE ? ESCAPE '\'`, "%"+escapeLike(v)+"%"), nil
+	}
+	return nil, &SearchQueryError{Pos: t.pos, Message: fmt.Sprintf("unknown filter %q", t.field)}
+}
+
+func (p *searchQueryParser) compileAdded(t searchTerm) (sq.Sqlizer, error) {
+	const layout = "2006-01-02 15:04:05"
+
+	if day, err := time.ParseInLocation("2006-01-02", t.value, time.Local); err == nil {
+		from := day.UTC().Format(layout)
+		to := day.AddDate(0, 0, 1).UTC().Format(layout)
+		switch t.op {
+		case ">":
+			return sq.GtOrEq{"a.date_added": to}, nil
+		case ">=":
+			return sq.GtOrEq{"a.date_added": from}, nil
+		case "<":
+			return sq.Lt{"a.date_added": from}, nil
+		case "<=":
+			return sq.Lt{"a.date_added": to}, nil
+		default:
+			return sq.And{sq.GtOrEq{"a.date_added": from}, sq.Lt{"a.date_added": to}}, nil
+		}
+	}
+
+	age, err := parseSearchAge(t.value)
+	if err != nil {
+		return nil, &SearchQueryError{Pos: t.valuePos, Message: err.Error()}
+	}
+	// Comparing ages: a smaller age means a later date.
+	since := p.now.Add(-age).UTC().Format(layout)
+	switch t.op {
+	case ">":
+		return sq.Lt{"a.date_added": since}, nil
+	case ">=":
+		return sq.LtOrEq{"a.date_added": since}, nil
+	case "<=":
+		return sq.GtOrEq{"a.date_added": since}, nil
+	default:
+		// "<", ":" and "=" all read as "within the last ...".
+		return sq.Gt{"a.date_added": since}, nil
+	}
+}
+
+func compareExpr(column, op string, value any) sq.Sqlizer {
+	switch op {
+	case ">":
+		return sq.Gt{column: value}
+	case ">=":
+		return sq.GtOrEq{column: value}
+	case "<":
+		return sq.Lt{column: value}
+	case "<=":
+		return sq.LtOrEq{column: value}
+	}
+	return sq.Eq{column: value}
+}
+
+// foldRatingRange intersects a top-level rating term with the range collected so far.
+func foldRatingRange(current []int, t searchTerm) []int {
+	r := []int{0, 5}
+	if current != nil {
+		r = current
+	}
+	n, _ := strconv.Atoi(t.value)
+	switch t.op {
+	case ">":
+		r[0] = max(r[0], n+1)
+	case ">=":
+		r[0] = max(r[0], n)
+	case "<":
+		r[1] = min(r[1], n-1)
+	case "<=":
+		r[1] = min(r[1], n)
+	default:
+		r[0], r[1] = max(r[0], n), min(r[1], n)
+	}
+	return r
+}
+
+// resolveSearchColor accepts a name from the active palette ("sky blue", "skyblue", "sky-blue").
+func resolveSearchColor(v string, palette []config.PaletteColor) (string, bool) {
+	want := normalizeColorName(v)
+	for _, c := range palette {
+		if normalizeColorName(c.Name) == want {
+			return c.Hex, true
+		}
+	}
+	return "", false
+}
+
+// normalizeColorName folds case and separators, so "Sky Blue" == "sky-blue" == "skyblue".
+func normalizeColorName(name string) string {
+	return strings.Map(func(r rune) rune {
+		if r == ' ' || r == '-' || r == '_' {
+			return -1
+		}
+		return unicode.ToLower(r)
+	}, name)
+}
+
+// parseSearchSize parses "500kb", "2.5gb", "10" (MB, like the size slider) into bytes.
+func parseSearchSize(v string) (int64, error) {
+	lower := strings.ToLower(v)
+	multiplier := float64(1024 * 1024)
+	for _, unit := range []struct {
+		suffix string
+		mult   float64
+	}{{"kb", 1024}, {"mb", 1024 * 1024}, {"gb", 1024 * 1024 * 1024}, {"b", 1}} {
+		if strings.HasSuffix(lower, unit.suffix) {
+			lower = strings.TrimSuffix(lower, unit.suffix)
+			multiplier = unit.mult
+			break
+		}
+	}
+	n, err := strconv.ParseFloat(lower, 64)
+	if err != nil || n < 0 {
+		return 0, fmt.Errorf("invalid size %q (expected e.g. 500kb, 10mb, 2gb)", v)
+	}
+	return int64(n * multiplier), nil
+}
+
+// parseSearchAge parses "12h", "30d", "2w", "6m" (months) and "1y".
+func parseSearchAge(v string) (time.Duration, error) {
+	invalid := fmt.Errorf("invalid date %q (expected YYYY-MM-DD or an age like 30d, 2w, 6m)", v)
+	if len(v) < 2 {
+		return 0, invalid
+	}
+	n, err := strconv.Atoi(v[:len(v)-1])
+	if err != nil || n < 0 {
+		return 0, invalid
+	}
+	day := 24 * time.Hour
+	switch unicode.ToLower(rune(v[len(v)-1])) {
+	case 'h':
+		return time.Duration(n) * time.Hour, nil
+	case 'd':
+		return time.Duration(n) * day, nil
+	case 'w':
+		return time.Duration(n) * 7 * day, nil
+	case 'm':
+		return time.Duration(n) * 30 * day, nil
+	case 'y':
+		return time.Duration(n) * 365 * day, nil
+	}
+	return 0, invalid
+}
+
+func escapeLike(s string) string {
+	return strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`).Replace(s)
+}
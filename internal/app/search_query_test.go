@@ -0,0 +1,112 @@
+package app
+
+import (
+	"eclat/internal/config"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseSearchQuery_Errors(t *testing.T) {
+	tests := []struct {
+		input string
+		pos   int
+	}{
+		{`foo:bar`, 0},
+		{`rock tag:`, 9},
+		{`rating>=9`, 8},
+		{`w>=big`, 3},
+		{`type:audio`, 5},
+		{`color:reddish`, 6},
+		{`tag>3`, 3},
+		{`added:<30x`, 7},
+		{`folder:"Mega scans`, 7},
+		{`(tag:wood OR tag:metal`, 0},
+		{`tag:wood)`, 8},
+		{`OR tag:wood`, 0},
+		{`tag:wood OR`, 9},
+		{`()`, 0},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			_, err := parseSearchQuery(tt.input, time.Now(), config.PredefinedPalette)
+			var qErr *SearchQueryError
+			if assert.True(t, errors.As(err, &qErr), "expected SearchQueryError, got %v", err) {
+				assert.Equal(t, tt.pos, qErr.Pos, qErr.Message)
+			}
+		})
+	}
+}
+
+func TestParseSearchQuery_Structure(t *testing.T) {
+	q, err := parseSearchQuery(`old "oak bark" type:texture rating>=3 rating<5 -tag:wip`, time.Now(), config.PredefinedPalette)
+	assert.NoError(t, err)
+	assert.Equal(t, `old "oak bark"`, q.Text, "Free text keeps quotes for the FTS search")
+	assert.Equal(t, []int{3, 4}, q.RatingRange, "Top-level rating terms fold into RatingRange")
+	assert.Len(t, q.Conditions, 2)
+
+	// Plain text still behaves like before
+	q, err = parseSearchQuery("rock - albedo", time.Now(), config.PredefinedPalette)
+	assert.NoError(t, err)
+	assert.Equal(t, "rock - albedo", q.Text)
+	assert.Empty(t, q.Conditions)
+}
+
+func TestAssetService_GetAssets_StructuredQuery(t *testing.T) {
+	service, queries := setupAssetServiceTest(t)
+
+	wood := insertTestAssetWithParams(t, queries, "oak_albedo.exr", "/lib/Megascans/oak/oak_albedo.exr", false, false)
+	metal := insertTestAssetWithParams(t, queries, "steel.png", "/lib/Megascans/steel/steel.png", false, false)
+	wip := insertTestAssetWithParams(t, queries, "plank.png", "/lib/Other/plank.png", false, false)
+	model := insertTestAssetWithParams(t, queries, "megascans_crate.obj", "/lib/Other/megascans_crate.obj", false, false)
+
+	exec := func(query string, args ...any) {
+		_, err := service.sysDB.Exec(query, args...)
+		assert.NoError(t, err)
+	}
+	exec("UPDATE assets SET file_type = 'texture', image_width = 4096, rating = 5, dominant_color = '#FF0000' WHERE id = ?", wood.ID)
+	exec("UPDATE assets SET file_type = 'texture', image_width = 2048, rating = 3 WHERE id = ?", metal.ID)
+	exec("UPDATE assets SET image_width = 1024, rating = 4 WHERE id = ?", wip.ID)
+	exec("UPDATE assets SET file_type = 'model', image_width = NULL, image_height = NULL WHERE id = ?", model.ID)
+	exec("UPDATE assets SET date_added = ? WHERE id = ?", time.Now().AddDate(0, 0, -90).UTC().Format("2006-01-02 15:04:05"), metal.ID)
+	assert.NoError(t, service.UpdateTags(wood.ID, []string{"wood"}))
+	assert.NoError(t, service.UpdateTags(metal.ID, []string{"metal"}))
+	assert.NoError(t, service.UpdateTags(wip.ID, []string{"wood", "WIP"}))
+
+	search := func(q string) []int64 {
+		res, err := service.GetAssets(AssetQueryFilters{Page: 1, PageSize: 10, Query: q, SortOption: "filename"})
+		assert.NoError(t, err, q)
+		if res == nil {
+			return nil
+		}
+		var ids []int64
+		for _, item := range res.Items {
+			ids = append(ids, item.ID)
+		}
+		return ids
+	}
+
+	assert.Equal(t, []int64{wood.ID, metal.ID}, search("type:texture"))
+	assert.Equal(t, []int64{wood.ID}, search("tag:wood -tag:wip"))
+	assert.Equal(t, []int64{wood.ID, metal.ID}, search("type:texture tag:wood OR tag:metal"))
+	assert.Equal(t, []int64{wood.ID, wip.ID}, search("rating>=4"))
+	assert.Equal(t, []int64{wood.ID, metal.ID}, search("w>=2048"))
+	assert.Equal(t, []int64{model.ID, wip.ID}, search("-w>=2048"), "Negation keeps assets without dimensions")
+	assert.Equal(t, []int64{wood.ID}, search("ext:exr"))
+	assert.Equal(t, []int64{wood.ID}, search("color:red"))
+	assert.Equal(t, []int64{model.ID, wood.ID, wip.ID}, search("added:<30d"))
+	assert.Equal(t, []int64{metal.ID}, search("added:>30d"))
+	assert.Equal(t, []int64{wood.ID, metal.ID}, search(`folder:"Megascans"`), "Folder matches the directory, not the file name")
+	assert.Equal(t, []int64{model.ID, wood.ID}, search("type:model,texture (oak OR crate)"))
+	assert.Equal(t, []int64{wood.ID}, search("albedo -type:model"))
+	assert.Equal(t, []int64{model.ID, wood.ID, wip.ID, metal.ID}, search("-%"), "LIKE wildcards are matched literally")
+	assert.Equal(t, []int64{model.ID}, search("% OR type:model"))
+
+	_, err := service.GetAssets(AssetQueryFilters{Page: 1, PageSize: 10, Query: "rating>=9"})
+	assert.Error(t, err)
+	assert.NotNil(t, service.ValidateSearchQuery("foo:bar"))
+	assert.Nil(t, service.ValidateSearchQuery("type:texture oak"))
+}
@@ -0,0 +1,77 @@
+package app
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBuildFTSMatchExpression(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+	}{
+		{"rock", `"rock"*`},
+		{"Rock Alb", `"rock"* "alb"*`},
+		{"rock_alb", `"rock alb"*`},
+		{`"old oak" bark`, `"old oak" "bark"*`},
+		{`"unterminated phrase`, `"unterminated phrase"`},
+		{`foo" OR 1=1 --`, `"foo"* "or"* "1 1"*`},
+		{"  ", ""},
+		{"%%%", ""},
+		{"żółć", `"żółć"*`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			assert.Equal(t, tt.expected, buildFTSMatchExpression(tt.input))
+		})
+	}
+}
+
+func TestAssetService_GetAssets_FullTextSearch(t *testing.T) {
+	service, queries := setupAssetServiceTest(t)
+
+	megascans := filepath.Join(t.TempDir(), "megascans")
+	assert.NoError(t, os.MkdirAll(megascans, 0755))
+	rockPath := filepath.Join(megascans, "rock_albedo.png")
+	assert.NoError(t, os.WriteFile(rockPath, []byte("rock"), 0644))
+
+	rock := insertTestAssetWithParams(t, queries, "rock_albedo.png", rockPath, false, false)
+	bark := insertTestAssetWithParams(t, queries, "bark.png", "/tmp/library/bark.png", false, false)
+	moss := insertTestAssetWithParams(t, queries, "moss.png", "/tmp/library/moss.png", false, false)
+
+	desc := "Old oak bark, scanned in the forest"
+	_, err := service.UpdateAssetMetadata(bark.ID, UpdateAssetRequest{Description: &desc})
+	assert.NoError(t, err)
+	assert.NoError(t, service.UpdateTags(moss.ID, []string{"forest", "ground"}))
+
+	search := func(q string, sort string) []int64 {
+		res, err := service.GetAssets(AssetQueryFilters{Page: 1, PageSize: 10, Query: q, SortOption: sort, SortDesc: true})
+		assert.NoError(t, err)
+		var ids []int64
+		for _, item := range res.Items {
+			ids = append(ids, item.ID)
+		}
+		return ids
+	}
+
+	// Prefix match on a word inside the file name
+	assert.Equal(t, []int64{rock.ID}, search("albe", ""))
+	// Path component
+	assert.Equal(t, []int64{rock.ID}, search("megascans", ""))
+	// Description (phrase)
+	assert.Equal(t, []int64{bark.ID}, search(`"old oak"`, ""))
+	assert.Empty(t, search(`"oak old"`, ""))
+	// Tags
+	assert.Equal(t, []int64{moss.ID}, search("ground", ""))
+	// Relevance: a tag hit outranks a description hit
+	assert.Equal(t, []int64{moss.ID, bark.ID}, search("forest", "relevance"))
+
+	// Renames are re-indexed
+	assert.NoError(t, service.RenameAsset(rock.ID, "granite_albedo.png"))
+	assert.Empty(t, search("rock", ""))
+	assert.Equal(t, []int64{rock.ID}, search("granite", ""))
+}
@@ -0,0 +1,337 @@
+package app
+
+import (
+	"cmp"
+	"database/sql"
+	"eclat/internal/database"
+	"eclat/internal/scanner"
+	"errors"
+	"fmt"
+	"path/filepath"
+	"slices"
+	"strings"
+)
+
+const (
+	// DefaultSimilarityDistance is used when the caller passes 0. Six bits out of 64 catches
+	// re-exported JPGs and resized copies without merging different textures of the same material.
+	DefaultSimilarityDistance = 6
+	// MaxSimilarityDistance caps the threshold; beyond it unrelated images start to cluster.
+	MaxSimilarityDistance = 20
+)
+
+// SimilarAsset is a member of a cluster of visually similar images.
+type SimilarAsset struct {
+	ID            int64  `json:"id"`
+	FileName      string `json:"fileName"`
+	FilePath      string `json:"filePath"`
+	ThumbnailPath string `json:"thumbnailPath"`
+	FileSize      int64  `json:"fileSize"`
+	ImageWidth    int64  `json:"imageWidth"`
+	ImageHeight   int64  `json:"imageHeight"`
+	Distance      int    `json:"distance"` // Hamming distance to the first asset of the cluster
+}
+
+// SimilarAssetCluster groups near-duplicate images. The first asset is the one worth keeping
+// (largest resolution, then largest file).
+type SimilarAssetCluster struct {
+	Assets []SimilarAsset `json:"assets"`
+}
+
+// GetSimilarImageClusters returns clusters of images whose perceptual hashes differ by at most
+// maxDistance bits (0 means DefaultSimilarityDistance). Clustering is transitive: if A~B and B~C,
+// all three land in one cluster.
+func (s *AssetService) GetSimilarImageClusters(maxDistance int) ([]SimilarAssetCluster, error) {
+	ctx := s.ctxOrBackground()
+
+	if maxDistance == 0 {
+		maxDistance = DefaultSimilarityDistance
+	}
+	if maxDistance < 0 || maxDistance > MaxSimilarityDistance {
+		return nil, fmt.Errorf("similarity distance must be between 0 and %d", MaxSimilarityDistance)
+	}
+
+	rows, err := s.db.ListPerceptualHashes(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load perceptual hashes: %w", err)
+	}
+
+	assets := make([]SimilarAsset, 0, len(rows))
+	hashes := make([]uint64, 0, len(rows))
+	for _, r := range rows {
+		hash, err := scanner.ParsePerceptualHash(r.PerceptualHash.String)
+		if err != nil {
+			s.logger.Warn("Skipping invalid perceptual hash", "id", r.ID, "hash", r.PerceptualHash.String)
+			continue
+		}
+		assets = append(assets, SimilarAsset{
+			ID:            r.ID,
+			FileName:      r.FileName,
+			FilePath:      r.FilePath,
+			ThumbnailPath: r.ThumbnailPath,
+			FileSize:      r.FileSize,
+			ImageWidth:    r.ImageWidth.Int64,
+			ImageHeight:   r.ImageHeight.Int64,
+		})
+		hashes = append(hashes, hash)
+	}
+
+	// BK-tree lookups keep this well below O(n²) for small distances.
+	tree := &hammingBKTree{}
+	for i, h := range hashes {
+		tree.Add(h, i)
+	}
+
+	parent := make([]int, len(hashes))
+	for i := range parent {
+		parent[i] = i
+	}
+	var find func(int) int
+	find = func(i int) int {
+		if parent[i] != i {
+			parent[i] = find(parent[i])
+		}
+		return parent[i]
+	}
+	for i, h := range hashes {
+		tree.Search(h, maxDistance, func(j int) {
+			if ri, rj := find(i), find(j); ri != rj {
+				parent[rj] = ri
+			}
+		})
+	}
+
+	members := make(map[int][]int)
+	for i := range hashes {
+		root := find(i)
+		members[root] = append(members[root], i)
+	}
+
+	var clusters []SimilarAssetCluster
+	for _, idx := range members {
+		if len(idx) < 2 {
+			continue
+		}
+		slices.SortFunc(idx, func(a, b int) int {
+			pa := assets[a].ImageWidth * assets[a].ImageHeight
+			pb := assets[b].ImageWidth * assets[b].ImageHeight
+			return cmp.Or(cmp.Compare(pb, pa), cmp.Compare(assets[b].FileSize, assets[a].FileSize), cmp.Compare(assets[a].ID, assets[b].ID))
+		})
+
+		cluster := SimilarAssetCluster{Assets: make([]SimilarAsset, 0, len(idx))}
+		for _, i := range idx {
+			a := assets[i]
+			a.Distance = scanner.HammingDistance(hashes[idx[0]], hashes[i])
+			cluster.Assets = append(cluster.Assets, a)
+		}
+		clusters = append(clusters, cluster)
+	}
+
+	// Biggest clusters first - they free the most space when cleaned up.
+	slices.SortFunc(clusters, func(a, b SimilarAssetCluster) int {
+		return cmp.Or(cmp.Compare(len(b.Assets), len(a.Assets)), cmp.Compare(a.Assets[0].ID, b.Assets[0].ID))
+	})
+	return clusters, nil
+}
+
+// hammingBKTree is a Burkhard-Keller tree over 64-bit hashes with the Hamming metric.
+type hammingBKTree struct {
+	root *bkNode
+}
+
+type bkNode struct {
+	hash     uint64
+	items    []int
+	children map[int]*bkNode
+}
+
+func (t *hammingBKTree) Add(hash uint64, item int) {
+	if t.root == nil {
+		t.root = &bkNode{hash: hash, items: []int{item}}
+		return
+	}
+	node := t.root
+	for {
+		d := scanner.HammingDistance(node.hash, hash)
+		if d == 0 {
+			node.items = append(node.items, item)
+			return
+		}
+		child, ok := node.children[d]
+		if !ok {
+			if node.children == nil {
+				node.children = make(map[int]*bkNode)
+			}
+			node.children[d] = &bkNode{hash: hash, items: []int{item}}
+			return
+		}
+		node = child
+	}
+}
+
+// Search calls fn for every item within maxDistance of hash.
+func (t *hammingBKTree) Search(hash uint64, maxDistance int, fn func(item int)) {
+	if t.root == nil {
+		return
+	}
+	stack := []*bkNode{t.root}
+	for len(stack) > 0 {
+		node := stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+
+		d := scanner.HammingDistance(node.hash, hash)
+		if d <= maxDistance {
+			for _, item := range node.items {
+				fn(item)
+			}
+		}
+		for cd, child := range node.children {
+			if cd >= d-maxDistance && cd <= d+maxDistance {
+				stack = append(stack, child)
+			}
+		}
+	}
+}
+
+const (
+	// Weights of the two parts of the visual distance used by FindSimilar.
+	similarColorWeight     = 0.6
+	similarStructureWeight = 0.4
+
+	defaultSimilarLimit = 50
+	maxSimilarLimit     = 500
+)
+
+// SimilarAssetMatch is a single "find similar" result.
+type SimilarAssetMatch struct {
+	Asset    AssetDetails `json:"asset"`
+	Distance float64      `json:"distance"` // 0 = looks identical, 1 = nothing in common
+}
+
+// visualFeatures is the in-memory form of an asset_features row.
+type visualFeatures struct {
+	histogram []byte
+	phash     uint64
+	hasPHash  bool
+}
+
+func newVisualFeatures(histogram []byte, phash sql.NullString) visualFeatures {
+	f := visualFeatures{histogram: histogram}
+	if phash.Valid {
+		if h, err := scanner.ParsePerceptualHash(phash.String); err == nil {
+			f.phash = h
+			f.hasPHash = true
+		}
+	}
+	return f
+}
+
+// distance blends the Lab histogram distance with the pHash distance. Without a hash on
+// both sides only the colors are compared.
+func (f visualFeatures) distance(other visualFeatures) float64 {
+	colorDist := scanner.ColorHistogramDistance(f.histogram, other.histogram)
+	if !f.hasPHash || !other.hasPHash {
+		return colorDist
+	}
+	structureDist := float64(scanner.HammingDistance(f.phash, other.phash)) / 64
+	return similarColorWeight*colorDist + similarStructureWeight*structureDist
+}
+
+// FindSimilar ranks the library by visual distance to the given asset and returns the closest
+// matches (the asset itself excluded). A brute-force pass over the stored feature vectors is
+// fast enough for libraries in the 100k range (see BenchmarkFindSimilar).
+func (s *AssetService) FindSimilar(assetId int64, limit int) ([]SimilarAssetMatch, error) {
+	ctx := s.ctxOrBackground()
+
+	if limit <= 0 {
+		limit = defaultSimilarLimit
+	}
+	limit = min(limit, maxSimilarLimit)
+
+	source, err := s.db.GetAssetFeatures(ctx, assetId)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, fmt.Errorf("asset %d has no visual features yet (only images are analyzed)", assetId)
+		}
+		return nil, err
+	}
+	sourceFeatures := newVisualFeatures(source.ColorHistogram, source.PerceptualHash)
+
+	rows, err := s.db.ListAssetFeatures(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load visual features: %w", err)
+	}
+
+	type candidate struct {
+		id       int64
+		distance float64
+	}
+	candidates := make([]candidate, 0, len(rows))
+	for _, r := range rows {
+		if r.AssetID == assetId {
+			continue
+		}
+		candidates = append(candidates, candidate{
+			id:       r.AssetID,
+			distance: sourceFeatures.distance(newVisualFeatures(r.ColorHistogram, r.PerceptualHash)),
+		})
+	}
+	slices.SortFunc(candidates, func(a, b candidate) int {
+		return cmp.Or(cmp.Compare(a.distance, b.distance), cmp.Compare(a.id, b.id))
+	})
+
+	candidates = candidates[:min(limit, len(candidates))]
+
+	// One query for all matches instead of a lookup per result.
+	ids := make([]int64, len(candidates))
+	for i, c := range candidates {
+		ids[i] = c.id
+	}
+	matched, err := s.db.ListAssetsByIDs(ctx, ids)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load similar assets: %w", err)
+	}
+	assets := make(map[int64]database.Asset, len(matched))
+	for _, a := range matched {
+		assets[a.ID] = a
+	}
+
+	results := make([]SimilarAssetMatch, 0, len(candidates))
+	for _, c := range candidates {
+		a, ok := assets[c.id]
+		if !ok {
+			continue
+		}
+		results = append(results, SimilarAssetMatch{Asset: assetListDetails(a), Distance: c.distance})
+	}
+	return results, nil
+}
+
+// assetListDetails maps an asset row to the fields shown on a gallery card. Tags, colors and
+// the other related rows are left out; the inspector loads them with GetAssetById.
+func assetListDetails(a database.Asset) AssetDetails {
+	groupID := a.GroupID
+	return AssetDetails{
+		ID:              a.ID,
+		FilePath:        a.FilePath,
+		FileName:        a.FileName,
+		FileType:        a.FileType,
+		ThumbnailPath:   a.ThumbnailPath,
+		DateAdded:       a.DateAdded,
+		LastModified:    a.LastModified,
+		FileSize:        a.FileSize,
+		ImageWidth:      a.ImageWidth.Int64,
+		ImageHeight:     a.ImageHeight.Int64,
+		FileExtension:   strings.ToLower(filepath.Ext(a.FileName)),
+		Rating:          a.Rating,
+		IsFavorite:      a.IsFavorite.Bool,
+		Description:     a.Description.String,
+		IsDeleted:       a.IsDeleted,
+		IsHidden:        a.IsHidden,
+		BitDepth:        a.BitDepth.Int64,
+		FileHash:        a.FileHash.String,
+		GroupID:         &groupID,
+		DominantColor:   a.DominantColor.String,
+		MaterialChannel: a.MaterialChannel,
+	}
+}
@@ -0,0 +1,156 @@
+package app
+
+import (
+	"context"
+	"eclat/internal/database"
+	"eclat/internal/scanner"
+	"fmt"
+	"math/rand/v2"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAssetService_GetSimilarImageClusters(t *testing.T) {
+	service, queries := setupAssetServiceTest(t)
+
+	setHash := func(id int64, hash string, width int) {
+		_, err := service.sysDB.Exec("UPDATE assets SET perceptual_hash = ?, image_width = ?, image_height = ? WHERE id = ?", hash, width, width, id)
+		assert.NoError(t, err)
+	}
+
+	original := insertTestAssetWithParams(t, queries, "rock.png", "/lib/rock.png", false, false)
+	resized := insertTestAssetWithParams(t, queries, "rock_small.jpg", "/lib/rock_small.jpg", false, false)
+	reexport := insertTestAssetWithParams(t, queries, "rock_export.jpg", "/lib/export/rock_export.jpg", false, false)
+	other := insertTestAssetWithParams(t, queries, "grass.png", "/lib/grass.png", false, false)
+	deleted := insertTestAssetWithParams(t, queries, "rock_old.png", "/lib/rock_old.png", true, false)
+	insertTestAssetWithParams(t, queries, "no_hash.png", "/lib/no_hash.png", false, false)
+
+	setHash(original.ID, "f0f0f0f0f0f0f0f0", 4096)
+	setHash(resized.ID, "f0f0f0f0f0f0f0f3", 1024)  // 2 bits from original
+	setHash(reexport.ID, "f0f0f0f0f0f0f00f", 4096) // 6 bits from original, chained through nothing else
+	setHash(other.ID, "0f0f0f0f0f0f0f0f", 4096)
+	setHash(deleted.ID, "f0f0f0f0f0f0f0f0", 4096)
+
+	clusters, err := service.GetSimilarImageClusters(2)
+	assert.NoError(t, err)
+	if assert.Len(t, clusters, 1) {
+		ids := []int64{clusters[0].Assets[0].ID, clusters[0].Assets[1].ID}
+		assert.Equal(t, []int64{original.ID, resized.ID}, ids, "Largest resolution comes first")
+		assert.Equal(t, 2, clusters[0].Assets[1].Distance)
+	}
+
+	// Default distance (6) also pulls in the re-export
+	clusters, err = service.GetSimilarImageClusters(0)
+	assert.NoError(t, err)
+	if assert.Len(t, clusters, 1) {
+		assert.Len(t, clusters[0].Assets, 3)
+	}
+
+	_, err = service.GetSimilarImageClusters(MaxSimilarityDistance + 1)
+	assert.Error(t, err)
+}
+
+func TestAssetService_FindSimilar(t *testing.T) {
+	service, queries := setupAssetServiceTest(t)
+	ctx := context.Background()
+
+	histogram := func(bins map[int]byte) []byte {
+		h := make([]byte, scanner.ColorHistogramSize)
+		for i, v := range bins {
+			h[i] = v
+		}
+		return h
+	}
+	store := func(id int64, h []byte, phash string) {
+		assert.NoError(t, queries.UpsertAssetFeatures(ctx, database.UpsertAssetFeaturesParams{AssetID: id, ColorHistogram: h}))
+		if phash != "" {
+			_, err := service.sysDB.Exec("UPDATE assets SET perceptual_hash = ? WHERE id = ?", phash, id)
+			assert.NoError(t, err)
+		}
+	}
+
+	source := insertTestAssetWithParams(t, queries, "rock.png", "/lib/rock.png", false, false)
+	sameColors := insertTestAssetWithParams(t, queries, "rock_copy.jpg", "/lib/rock_copy.jpg", false, false)
+	similar := insertTestAssetWithParams(t, queries, "stone.png", "/lib/stone.png", false, false)
+	different := insertTestAssetWithParams(t, queries, "grass.png", "/lib/grass.png", false, false)
+	hidden := insertTestAssetWithParams(t, queries, "rock_hidden.png", "/lib/rock_hidden.png", false, true)
+	model := insertTestAssetWithParams(t, queries, "crate.obj", "/lib/crate.obj", false, false)
+
+	store(source.ID, histogram(map[int]byte{10: 200, 11: 55}), "ffff0000ffff0000")
+	store(sameColors.ID, histogram(map[int]byte{10: 200, 11: 55}), "ffff0000ffff0000")
+	store(similar.ID, histogram(map[int]byte{10: 150, 11: 105}), "ffff0000ffff00ff")
+	store(different.ID, histogram(map[int]byte{90: 255}), "0000ffff0000ffff")
+	store(hidden.ID, histogram(map[int]byte{10: 200, 11: 55}), "")
+
+	matches, err := service.FindSimilar(source.ID, 10)
+	assert.NoError(t, err)
+	var ids []int64
+	for _, m := range matches {
+		ids = append(ids, m.Asset.ID)
+	}
+	assert.Equal(t, []int64{sameColors.ID, similar.ID, different.ID}, ids, "Ranked by distance; self, hidden and assets without features are skipped")
+	assert.Equal(t, 0.0, matches[0].Distance)
+	assert.InDelta(t, 1.0, matches[2].Distance, 0.01)
+
+	matches, err = service.FindSimilar(source.ID, 1)
+	assert.NoError(t, err)
+	assert.Len(t, matches, 1)
+
+	_, err = service.FindSimilar(model.ID, 10)
+	assert.Error(t, err, "Assets without features cannot be used as the query")
+}
+
+// BenchmarkFindSimilar ranks a library of 100k images with random feature vectors.
+func BenchmarkFindSimilar(b *testing.B) {
+	const assetCount = 100_000
+	service, queries := setupAssetServiceTest(b)
+	ctx := context.Background()
+
+	folder, err := queries.CreateScanFolder(ctx, "/lib")
+	if err != nil {
+		b.Fatal(err)
+	}
+	tx, err := service.sysDB.Begin()
+	if err != nil {
+		b.Fatal(err)
+	}
+	insertAsset, err := tx.Prepare(`INSERT INTO assets (id, scan_folder_id, group_id, file_name, file_path, file_type, last_scanned, last_modified, perceptual_hash)
+		VALUES (?, ?, ?, ?, ?, 'image', CURRENT_TIMESTAMP, CURRENT_TIMESTAMP, ?)`)
+	if err != nil {
+		b.Fatal(err)
+	}
+	insertFeatures, err := tx.Prepare("INSERT INTO asset_features (asset_id, color_histogram) VALUES (?, ?)")
+	if err != nil {
+		b.Fatal(err)
+	}
+	rng := rand.New(rand.NewPCG(1, 2))
+	for id := int64(1); id <= assetCount; id++ {
+		name := fmt.Sprintf("texture_%d.png", id)
+		phash := fmt.Sprintf("%016x", rng.Uint64())
+		if _, err := insertAsset.Exec(id, folder.ID, name, name, "/lib/"+name, phash); err != nil {
+			b.Fatal(err)
+		}
+		histogram := make([]byte, scanner.ColorHistogramSize)
+		for i := range histogram {
+			histogram[i] = byte(rng.IntN(256))
+		}
+		if _, err := insertFeatures.Exec(id, histogram); err != nil {
+			b.Fatal(err)
+		}
+	}
+	if err := tx.Commit(); err != nil {
+		b.Fatal(err)
+	}
+
+	b.ReportAllocs()
+	for b.Loop() {
+		matches, err := service.FindSimilar(1, maxSimilarLimit)
+		if err != nil {
+			b.Fatal(err)
+		}
+		if len(matches) != maxSimilarLimit {
+			b.Fatalf("got %d matches, want %d", len(matches), maxSimilarLimit)
+		}
+	}
+}
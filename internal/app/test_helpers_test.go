@@ -3,9 +3,11 @@ package app
 import (
 	"context"
 	"database/sql"
+	"eclat/internal/config"
 	"eclat/internal/database"
 	"eclat/internal/feedback"
 	"eclat/internal/scanner"
+	"eclat/internal/trash"
 	"fmt"
 	"io"
 	"log/slog"
@@ -18,7 +20,7 @@ import (
 )
 
 // setupTestDB initializes an in-memory SQLite database and applies migrations.
-func setupTestDB(t *testing.T) (*sql.DB, database.Querier) {
+func setupTestDB(t testing.TB) (*sql.DB, database.Querier) {
 	// Use a unique name for each test to avoid collisions in shared memory
 	dsn := "file:" + t.Name() + "?mode=memory&cache=shared&_time_format=sqlite"
 	db, err := sql.Open("sqlite", dsn)
@@ -44,25 +46,40 @@ func setupTestDB(t *testing.T) (*sql.DB, database.Querier) {
 }
 
 // setupAssetServiceTest creates an AssetService with a test DB and logger.
-func setupAssetServiceTest(t *testing.T) (*AssetService, database.Querier) {
+func setupAssetServiceTest(t testing.TB) (*AssetService, database.Querier) {
 	sysDB, queries := setupTestDB(t)
 	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
 	notifier := &MockNotifier{}
-	service := NewAssetService(queries, sysDB, logger, notifier, "/tmp")
+	service := NewAssetService(queries, sysDB, logger, notifier, "/tmp", newTestTrashBin(t, logger), NewJournal(sysDB, logger), config.NewScannerConfig(), nil)
 	service.Startup(context.Background())
 	return service, queries
 }
 
+// newTestTrashBin returns a trash bin quarantining files in a temporary directory, so tests
+// never touch the system trash.
+func newTestTrashBin(t testing.TB, logger *slog.Logger) *trash.Bin {
+	return trash.NewWithBackends(logger, trash.NewQuarantine(t.TempDir()))
+}
+
 // setupMaterialSetServiceTest creates a MaterialSetService with a test DB and logger.
 func setupMaterialSetServiceTest(t *testing.T) (*MaterialSetService, database.Querier) {
-	_, queries := setupTestDB(t)
+	sysDB, queries := setupTestDB(t)
 	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
 	thumbGen := &MockThumbGen{}
-	service := NewMaterialSetService(queries, logger, thumbGen)
+	service := NewMaterialSetService(queries, logger, thumbGen, NewJournal(sysDB, logger))
 	service.Startup(context.Background())
 	return service, queries
 }
 
+// setupPaletteServiceTest creates a PaletteService sharing the DB (and scanner config) with an AssetService.
+func setupPaletteServiceTest(t *testing.T) (*PaletteService, *AssetService, database.Querier) {
+	assetService, queries := setupAssetServiceTest(t)
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	service := NewPaletteService(queries, assetService.sysDB, logger, &MockNotifier{}, config.NewScannerConfig())
+	service.Startup(context.Background())
+	return service, assetService, queries
+}
+
 type MockThumbGen struct {
 	ShouldFail bool
 }
@@ -78,8 +95,9 @@ func (m *MockThumbGen) Generate(ctx context.Context, sourcePath string) (scanner
 }
 
 type MockNotifier struct {
-	LastMsg   feedback.ToastField
-	CallCount int
+	LastMsg      feedback.ToastField
+	CallCount    int
+	FileProgress []feedback.FileOperationProgressDTO
 }
 
 func (m *MockNotifier) SendToast(ctx context.Context, msg feedback.ToastField) {
@@ -91,7 +109,12 @@ func (m *MockNotifier) SendScannerStatus(ctx context.Context, status feedback.St
 	m.CallCount++
 }
 
-func (m *MockNotifier) SendScanProgress(ctx context.Context, current, total int, lastFile string) {
+func (m *MockNotifier) SendScanProgress(ctx context.Context, progress feedback.ScanProgressDTO) {
+	m.CallCount++
+}
+
+func (m *MockNotifier) SendFileOperationProgress(ctx context.Context, progress feedback.FileOperationProgressDTO) {
+	m.FileProgress = append(m.FileProgress, progress)
 	m.CallCount++
 }
 
@@ -142,6 +165,10 @@ func insertTestAssetWithParamsAndGroup(t *testing.T, q database.Querier, fileNam
 	if err != nil {
 		t.Fatalf("Failed to create asset: %v", err)
 	}
+	// Mirror Scanner.ApplyBatch, which indexes every inserted asset for full-text search.
+	if err := q.IndexAssetForSearch(ctx, asset.ID); err != nil {
+		t.Fatalf("Failed to index asset: %v", err)
+	}
 
 	if isDeleted {
 		q.SoftDeleteAsset(ctx, asset.ID)
@@ -0,0 +1,390 @@
+package app
+
+import (
+	"context"
+	"database/sql"
+	"eclat/internal/database"
+	"eclat/internal/trash"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// TrashedAsset is an asset deleted permanently. Its file is in the trash and the library
+// keeps a snapshot of it, its tags and its material sets, so it can be restored.
+type TrashedAsset struct {
+	ID            int64     `json:"id"`
+	FileName      string    `json:"fileName"`
+	FileType      string    `json:"fileType"`
+	FileSize      int64     `json:"fileSize"`
+	OriginalPath  string    `json:"originalPath"`
+	ThumbnailPath string    `json:"thumbnailPath"`
+	Backend       string    `json:"backend"`   // Trash holding the file; empty if the file was not moved
+	TrashPath     string    `json:"trashPath"` // Where the file is now
+	Tags          []string  `json:"tags"`
+	DeletedAt     time.Time `json:"deletedAt"`
+	// Restorable is false when the file is no longer in the trash (e.g. the system trash was
+	// emptied) or something else took its original path.
+	Restorable bool `json:"restorable"`
+}
+
+// trashedSnapshot is the decoded content of a trashed_assets row.
+type trashedSnapshot struct {
+	asset          database.Asset
+	tags           []string
+	materialSetIDs []int64
+}
+
+// GetTrashedAssets lists the permanently deleted assets, most recent first.
+func (s *AssetService) GetTrashedAssets() ([]TrashedAsset, error) {
+	rows, err := s.db.ListTrashedAssets(s.ctxOrBackground())
+	if err != nil {
+		s.logger.Error("Failed to list trashed assets", "error", err)
+		return nil, err
+	}
+
+	result := make([]TrashedAsset, 0, len(rows))
+	for _, row := range rows {
+		snap, err := decodeTrashedAsset(row)
+		if err != nil {
+			s.logger.Warn("Skipping unreadable trash entry", "id", row.ID, "error", err)
+			continue
+		}
+		result = append(result, TrashedAsset{
+			ID:            row.ID,
+			FileName:      snap.asset.FileName,
+			FileType:      snap.asset.FileType,
+			FileSize:      snap.asset.FileSize,
+			OriginalPath:  row.OriginalPath,
+			ThumbnailPath: snap.asset.ThumbnailPath,
+			Backend:       row.Backend,
+			TrashPath:     row.TrashPath,
+			Tags:          snap.tags,
+			DeletedAt:     row.DeletedAt,
+			Restorable:    s.checkRestorable(row) == nil,
+		})
+	}
+	return result, nil
+}
+
+// RestoreDeletedAsset moves a permanently deleted asset back from the trash to its original
+// path and brings back its library entry with its tags and material sets (sets deleted in
+// the meantime are skipped). It returns the ID of the restored asset. Colors and other
+// extracted properties are refreshed by the next scan.
+func (s *AssetService) RestoreDeletedAsset(trashId int64) (int64, error) {
+	ctx := s.ctxOrBackground()
+	row, err := s.db.GetTrashedAsset(ctx, trashId)
+	if err != nil {
+		return 0, fmt.Errorf("failed to get trash entry %d: %w", trashId, err)
+	}
+	snap, err := decodeTrashedAsset(row)
+	if err != nil {
+		return 0, err
+	}
+	if err := s.checkRestorable(row); err != nil {
+		return 0, err
+	}
+
+	var assetID int64
+	moved := false
+	err = inTx(ctx, s.sysDB, func(qtx *database.Queries) error {
+		if assetID, err = restoreAssetRow(ctx, qtx, snap); err != nil {
+			return err
+		}
+		for _, name := range snap.tags {
+			tag, err := qtx.CreateTag(ctx, name)
+			if err != nil {
+				return err
+			}
+			if err := qtx.AddTagToAsset(ctx, database.AddTagToAssetParams{AssetID: assetID, TagID: tag.ID}); err != nil {
+				return err
+			}
+		}
+		for _, setID := range snap.materialSetIDs {
+			if _, err := qtx.GetMaterialSetById(ctx, setID); errors.Is(err, sql.ErrNoRows) {
+				continue
+			} else if err != nil {
+				return err
+			}
+			if err := qtx.AddAssetToMaterialSet(ctx, database.AddAssetToMaterialSetParams{MaterialSetID: setID, AssetID: assetID}); err != nil {
+				return err
+			}
+		}
+		s.reindexAssetSearch(ctx, qtx, assetID)
+		if err := qtx.DeleteTrashedAsset(ctx, row.ID); err != nil {
+			return err
+		}
+
+		// The file moves last, so a failure before leaves it in the trash.
+		if row.Backend == "" {
+			return nil
+		}
+		if err := s.bin.Restore(trashEntry(row), row.OriginalPath); err != nil {
+			return fmt.Errorf("failed to restore %s from the trash: %w", row.OriginalPath, err)
+		}
+		moved = true
+		return nil
+	})
+	if err != nil {
+		if moved {
+			// The file is back on disk; the next scan adds it to the library again.
+			s.logger.Error("Restored file but failed to update the library", "path", row.OriginalPath, "error", err)
+		} else {
+			s.logger.Error("Failed to restore asset", "trash_id", trashId, "error", err)
+		}
+		return 0, err
+	}
+
+	s.logger.Info("Restored deleted asset", "trash_id", trashId, "id", assetID, "path", row.OriginalPath)
+	s.notifier.EmitAssetsChanged(ctx)
+	return assetID, nil
+}
+
+// PurgeTrashedAssets deletes trashed files and their snapshots for good.
+func (s *AssetService) PurgeTrashedAssets(ids []int64) error {
+	ctx := s.ctxOrBackground()
+	for _, id := range ids {
+		row, err := s.db.GetTrashedAsset(ctx, id)
+		if err != nil {
+			return fmt.Errorf("failed to get trash entry %d: %w", id, err)
+		}
+		if err := s.purgeTrashedAsset(ctx, row, true); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// CleanupExpiredAssets runs at startup. Assets soft-deleted more than 7 days ago leave the
+// library but stay restorable from the trash list (their files are not touched). Trash
+// entries older than 30 days are purged: quarantined files are deleted, while files in the
+// system trash are left for the desktop to empty.
+func (s *AssetService) CleanupExpiredAssets() error {
+	ctx := s.ctxOrBackground()
+	ids, err := s.db.ListExpiredDeletedAssetIDs(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to list expired deleted assets: %w", err)
+	}
+	for _, id := range ids {
+		asset, err := s.db.GetAssetById(ctx, id)
+		if err != nil {
+			return err
+		}
+		err = inTx(ctx, s.sysDB, func(qtx *database.Queries) error {
+			return archiveAsset(ctx, qtx, asset, trash.Entry{})
+		})
+		if err != nil {
+			return fmt.Errorf("failed to archive deleted asset %d: %w", id, err)
+		}
+	}
+
+	expired, err := s.db.ListExpiredTrashedAssets(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to list expired trash entries: %w", err)
+	}
+	for _, row := range expired {
+		if err := s.purgeTrashedAsset(ctx, row, row.Backend == trash.BackendQuarantine); err != nil {
+			return err
+		}
+	}
+
+	if len(ids) > 0 || len(expired) > 0 {
+		s.logger.Info("Cleaned up expired assets", "archived", len(ids), "purged", len(expired))
+	}
+	return nil
+}
+
+// deleteAssetPermanently moves the file to the trash and replaces the library entry with a
+// snapshot, without notifying the UI. Files already gone from disk only lose their entry.
+func (s *AssetService) deleteAssetPermanently(id int64) error {
+	ctx := s.ctxOrBackground()
+	asset, err := s.db.GetAssetById(ctx, id)
+	if err != nil {
+		s.logger.Error("Failed to get asset for deletion", "id", id, "error", err)
+		return fmt.Errorf("failed to retrieve asset %d: %w", id, err)
+	}
+
+	var entry trash.Entry
+	if _, err := os.Lstat(asset.FilePath); err == nil {
+		if entry, err = s.bin.Trash(asset.FilePath); err != nil {
+			s.logger.Error("Failed to move file to the trash", "path", asset.FilePath, "error", err)
+			return err
+		}
+	} else if !os.IsNotExist(err) {
+		return fmt.Errorf("failed to check %s: %w", asset.FilePath, err)
+	}
+
+	err = inTx(ctx, s.sysDB, func(qtx *database.Queries) error {
+		return archiveAsset(ctx, qtx, asset, entry)
+	})
+	if err != nil {
+		s.logger.Error("Failed to delete asset", "id", id, "error", err)
+		// The asset stays in the library, so its file goes back too.
+		if entry.Backend != "" {
+			if rerr := s.bin.Restore(entry, asset.FilePath); rerr != nil {
+				s.logger.Error("Failed to move file back from the trash", "path", asset.FilePath, "trash_path", entry.Path, "error", rerr)
+			}
+		}
+		return fmt.Errorf("failed to delete asset %d: %w", id, err)
+	}
+	return nil
+}
+
+// archiveAsset stores a snapshot of the asset with its trash entry and removes it from the
+// library. The thumbnail is kept until the entry is purged.
+func archiveAsset(ctx context.Context, qtx *database.Queries, asset database.Asset, entry trash.Entry) error {
+	tags, err := qtx.GetTagsNamesByAssetID(ctx, asset.ID)
+	if err != nil {
+		return err
+	}
+	setIDs, err := qtx.ListMaterialSetIDsByAsset(ctx, asset.ID)
+	if err != nil {
+		return err
+	}
+	assetJSON, err := json.Marshal(asset)
+	if err != nil {
+		return err
+	}
+	tagsJSON, err := json.Marshal(append([]string{}, tags...))
+	if err != nil {
+		return err
+	}
+	setsJSON, err := json.Marshal(append([]int64{}, setIDs...))
+	if err != nil {
+		return err
+	}
+
+	_, err = qtx.CreateTrashedAsset(ctx, database.CreateTrashedAssetParams{
+		OriginalPath:       asset.FilePath,
+		Backend:            entry.Backend,
+		TrashPath:          entry.Path,
+		InfoPath:           entry.InfoPath,
+		AssetJson:          string(assetJSON),
+		TagsJson:           string(tagsJSON),
+		MaterialSetIdsJson: string(setsJSON),
+	})
+	if err != nil {
+		return err
+	}
+	return qtx.DeleteAssetPermanent(ctx, asset.ID)
+}
+
+// restoreAssetRow recreates the library entry of a snapshot. If a scan already added the file
+// back, that entry is used instead.
+func restoreAssetRow(ctx context.Context, qtx *database.Queries, snap trashedSnapshot) (int64, error) {
+	a := snap.asset
+	existing, err := qtx.GetAssetByPath(ctx, a.FilePath)
+	if err == nil {
+		return existing.ID, nil
+	}
+	if !errors.Is(err, sql.ErrNoRows) {
+		return 0, err
+	}
+
+	created, err := qtx.CreateAsset(ctx, database.CreateAssetParams{
+		ScanFolderID:    a.ScanFolderID,
+		FileName:        a.FileName,
+		FilePath:        a.FilePath,
+		FileType:        a.FileType,
+		FileSize:        a.FileSize,
+		ThumbnailPath:   a.ThumbnailPath,
+		FileHash:        a.FileHash,
+		ImageWidth:      a.ImageWidth,
+		ImageHeight:     a.ImageHeight,
+		DominantColor:   a.DominantColor,
+		BitDepth:        a.BitDepth,
+		HasAlphaChannel: a.HasAlphaChannel,
+		LastModified:    a.LastModified,
+		LastScanned:     a.LastScanned,
+		GroupID:         a.GroupID,
+		PerceptualHash:  a.PerceptualHash,
+		MaterialChannel: a.MaterialChannel,
+		// Analysis version 0 makes the next scan refresh what was dropped with the entry.
+		AnalysisVersion: 0,
+	})
+	if err != nil {
+		return 0, fmt.Errorf("failed to recreate asset: %w", err)
+	}
+	_, err = qtx.UpdateAssetMetadata(ctx, database.UpdateAssetMetadataParams{
+		Description: a.Description,
+		Rating:      sql.NullInt64{Int64: a.Rating, Valid: true},
+		IsFavorite:  a.IsFavorite,
+		ID:          created.ID,
+	})
+	if err != nil {
+		return 0, err
+	}
+	if a.IsHidden {
+		if err := qtx.SetAssetHidden(ctx, database.SetAssetHiddenParams{IsHidden: true, ID: created.ID}); err != nil {
+			return 0, err
+		}
+	}
+	return created.ID, nil
+}
+
+// checkRestorable returns why a trash entry cannot be restored, or nil.
+func (s *AssetService) checkRestorable(row database.TrashedAsset) error {
+	_, err := os.Lstat(row.OriginalPath)
+	if row.Backend == "" {
+		// Nothing to move back; the file has to be in its place again.
+		if err != nil {
+			return fmt.Errorf("%s is no longer on disk", row.OriginalPath)
+		}
+		return nil
+	}
+	if err == nil {
+		return fmt.Errorf("%s already exists", row.OriginalPath)
+	}
+	if !s.bin.Exists(trashEntry(row)) {
+		return fmt.Errorf("%s is no longer in the trash", filepath.Base(row.OriginalPath))
+	}
+	return nil
+}
+
+// purgeTrashedAsset drops a trash entry and its thumbnail, deleting the trashed file too if
+// purgeFile is set.
+func (s *AssetService) purgeTrashedAsset(ctx context.Context, row database.TrashedAsset, purgeFile bool) error {
+	if purgeFile && row.Backend != "" {
+		if err := s.bin.Purge(trashEntry(row)); err != nil {
+			s.logger.Error("Failed to purge trashed file", "path", row.TrashPath, "error", err)
+			return fmt.Errorf("failed to purge %s: %w", row.TrashPath, err)
+		}
+	}
+	if snap, err := decodeTrashedAsset(row); err == nil {
+		s.removeThumbnail(snap.asset.ThumbnailPath)
+	}
+	return s.db.DeleteTrashedAsset(ctx, row.ID)
+}
+
+// removeThumbnail deletes a generated thumbnail; placeholders are left alone.
+func (s *AssetService) removeThumbnail(thumbnailPath string) {
+	if thumbnailPath == "" || !strings.HasPrefix(thumbnailPath, "/thumbnails/") {
+		return
+	}
+	thumbFullPath := filepath.Join(s.thumbnailsDir, filepath.Base(thumbnailPath))
+	if err := os.Remove(thumbFullPath); err != nil && !os.IsNotExist(err) {
+		s.logger.Warn("Failed to delete thumbnail", "path", thumbFullPath, "error", err)
+	}
+}
+
+func trashEntry(row database.TrashedAsset) trash.Entry {
+	return trash.Entry{Backend: row.Backend, Path: row.TrashPath, InfoPath: row.InfoPath}
+}
+
+func decodeTrashedAsset(row database.TrashedAsset) (trashedSnapshot, error) {
+	var snap trashedSnapshot
+	if err := json.Unmarshal([]byte(row.AssetJson), &snap.asset); err != nil {
+		return snap, fmt.Errorf("invalid snapshot of trash entry %d: %w", row.ID, err)
+	}
+	if err := json.Unmarshal([]byte(row.TagsJson), &snap.tags); err != nil {
+		return snap, fmt.Errorf("invalid tags of trash entry %d: %w", row.ID, err)
+	}
+	if err := json.Unmarshal([]byte(row.MaterialSetIdsJson), &snap.materialSetIDs); err != nil {
+		return snap, fmt.Errorf("invalid material sets of trash entry %d: %w", row.ID, err)
+	}
+	return snap, nil
+}
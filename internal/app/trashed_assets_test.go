@@ -0,0 +1,120 @@
+package app
+
+import (
+	"context"
+	"database/sql"
+	"eclat/internal/database"
+	"eclat/internal/trash"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAssetService_DeleteAndRestoreAsset(t *testing.T) {
+	service, queries := setupAssetServiceTest(t)
+	ctx := context.Background()
+	path := filepath.Join(t.TempDir(), "rock.png")
+	asset := insertDuplicateFile(t, queries, path, "rock-bytes", "G-ROCK")
+
+	set, err := queries.CreateMaterialSet(ctx, database.CreateMaterialSetParams{Name: "Rocks"})
+	require.NoError(t, err)
+	require.NoError(t, service.AddAssetToMaterialSet(set.ID, asset.ID))
+	require.NoError(t, service.UpdateTags(asset.ID, []string{"rock", "stone"}))
+	require.NoError(t, service.SetAssetRating(asset.ID, 4))
+
+	require.NoError(t, service.DeleteAssetsPermanently([]int64{asset.ID}))
+	assert.NoFileExists(t, path)
+	_, err = queries.GetAssetById(ctx, asset.ID)
+	assert.ErrorIs(t, err, sql.ErrNoRows)
+
+	trashed, err := service.GetTrashedAssets()
+	require.NoError(t, err)
+	require.Len(t, trashed, 1)
+	assert.Equal(t, trash.BackendQuarantine, trashed[0].Backend)
+	assert.Equal(t, path, trashed[0].OriginalPath)
+	assert.ElementsMatch(t, []string{"rock", "stone"}, trashed[0].Tags)
+	assert.True(t, trashed[0].Restorable)
+	assert.FileExists(t, trashed[0].TrashPath)
+
+	restoredID, err := service.RestoreDeletedAsset(trashed[0].ID)
+	require.NoError(t, err)
+	assert.FileExists(t, path)
+
+	restored, err := queries.GetAssetById(ctx, restoredID)
+	require.NoError(t, err)
+	assert.Equal(t, path, restored.FilePath)
+	assert.Equal(t, "G-ROCK", restored.GroupID)
+	assert.Equal(t, int64(4), restored.Rating)
+	assert.True(t, asset.LastModified.Equal(restored.LastModified), "Data modyfikacji wraca ze snapshotu")
+	assert.Zero(t, restored.AnalysisVersion, "Następny skan odświeża analizę")
+	tags, err := queries.GetTagsNamesByAssetID(ctx, restoredID)
+	require.NoError(t, err)
+	assert.ElementsMatch(t, []string{"rock", "stone"}, tags)
+	sets, err := queries.ListMaterialSetIDsByAsset(ctx, restoredID)
+	require.NoError(t, err)
+	assert.Equal(t, []int64{set.ID}, sets)
+
+	trashed, err = service.GetTrashedAssets()
+	require.NoError(t, err)
+	assert.Empty(t, trashed)
+}
+
+func TestAssetService_RestoreDeletedAssetConflictAndPurge(t *testing.T) {
+	service, queries := setupAssetServiceTest(t)
+	path := filepath.Join(t.TempDir(), "sky.png")
+	asset := insertDuplicateFile(t, queries, path, "sky-bytes", "G-SKY")
+	require.NoError(t, service.DeleteAssetsPermanently([]int64{asset.ID}))
+
+	// Nowy plik pod tą samą ścieżką nie zostaje nadpisany
+	require.NoError(t, os.WriteFile(path, []byte("new sky"), 0644))
+	trashed, err := service.GetTrashedAssets()
+	require.NoError(t, err)
+	require.Len(t, trashed, 1)
+	assert.False(t, trashed[0].Restorable)
+
+	_, err = service.RestoreDeletedAsset(trashed[0].ID)
+	assert.ErrorContains(t, err, "already exists")
+	content, err := os.ReadFile(path)
+	require.NoError(t, err)
+	assert.Equal(t, "new sky", string(content))
+
+	require.NoError(t, service.PurgeTrashedAssets([]int64{trashed[0].ID}))
+	assert.NoFileExists(t, trashed[0].TrashPath)
+	trashed, err = service.GetTrashedAssets()
+	require.NoError(t, err)
+	assert.Empty(t, trashed)
+}
+
+func TestAssetService_CleanupExpiredAssets(t *testing.T) {
+	service, queries := setupAssetServiceTest(t)
+	ctx := context.Background()
+	expired := insertTestAssetWithParams(t, queries, "old.png", "/tmp/test/old.png", true, false)
+	recent := insertTestAssetWithParams(t, queries, "recent.png", "/tmp/test/recent.png", true, false)
+	_, err := service.sysDB.Exec("UPDATE assets SET deleted_at = datetime('now', '-8 days') WHERE id = ?", expired.ID)
+	require.NoError(t, err)
+	_, err = service.sysDB.Exec("UPDATE assets SET deleted_at = datetime('now', '-1 days') WHERE id = ?", recent.ID)
+	require.NoError(t, err)
+
+	require.NoError(t, service.CleanupExpiredAssets())
+	_, err = queries.GetAssetById(ctx, expired.ID)
+	assert.ErrorIs(t, err, sql.ErrNoRows)
+	_, err = queries.GetAssetById(ctx, recent.ID)
+	assert.NoError(t, err, "Asset usunięty niedawno zostaje w koszu aplikacji")
+
+	trashed, err := service.GetTrashedAssets()
+	require.NoError(t, err)
+	require.Len(t, trashed, 1)
+	assert.Equal(t, "old.png", trashed[0].FileName)
+	assert.Empty(t, trashed[0].Backend, "Plik nie jest przenoszony")
+
+	// Wpisy starsze niż 30 dni znikają
+	_, err = service.sysDB.Exec("UPDATE trashed_assets SET deleted_at = datetime('now', '-31 days')")
+	require.NoError(t, err)
+	require.NoError(t, service.CleanupExpiredAssets())
+	trashed, err = service.GetTrashedAssets()
+	require.NoError(t, err)
+	assert.Empty(t, trashed)
+}
@@ -20,6 +20,7 @@ import (
 	"eclat/internal/feedback"
 	"eclat/internal/scanner"
 	"eclat/internal/settings"
+	"eclat/internal/trash"
 	"eclat/internal/update"
 	"eclat/internal/watcher"
 
@@ -66,6 +67,8 @@ type Dependencies struct {
 	AssetService       *app.AssetService
 	MaterialSetService *app.MaterialSetService
 	TagService         *app.TagService
+	SavedSearchService *app.SavedSearchService
+	PaletteService     *app.PaletteService
 	ScannerService     *scanner.Scanner
 	SettingsService    *settings.SettingsService
 	WatcherService     *watcher.Service
@@ -173,11 +176,47 @@ func Initialize(migrations embed.FS) (*Dependencies, error) {
 		programLogger.Info("ℹ️ No custom settings found in DB, using defaults")
 	}
 
+	storedProvidersJSON, err := queries.GetSystemSetting(ctx, "thumbnail_providers")
+	if err == nil && storedProvidersJSON != "" {
+		var providers []config.ThumbnailProvider
+		if err := json.Unmarshal([]byte(storedProvidersJSON), &providers); err == nil {
+			programLogger.Info("✅ Restored thumbnail providers from DB", "count", len(providers))
+			sharedConfig.SetThumbnailProviders(providers)
+		} else {
+			programLogger.Error("❌ Failed to unmarshal thumbnail providers from DB", "error", err)
+		}
+	}
+
+	storedChannelsJSON, err := queries.GetSystemSetting(ctx, "material_channels")
+	if err == nil && storedChannelsJSON != "" {
+		var channels []config.MaterialChannel
+		if err := json.Unmarshal([]byte(storedChannelsJSON), &channels); err == nil {
+			programLogger.Info("✅ Restored material channel synonyms from DB", "count", len(channels))
+			sharedConfig.SetMaterialChannels(channels)
+		} else {
+			programLogger.Error("❌ Failed to unmarshal material channels from DB", "error", err)
+		}
+	}
+
+	storedGroupingJSON, err := queries.GetSystemSetting(ctx, "grouping_rules")
+	if err == nil && storedGroupingJSON != "" {
+		var rules config.GroupingRuleSet
+		if err := json.Unmarshal([]byte(storedGroupingJSON), &rules); err != nil {
+			programLogger.Error("❌ Failed to unmarshal grouping rules from DB", "error", err)
+		} else if err := sharedConfig.SetGroupingRules(rules); err != nil {
+			programLogger.Error("❌ Invalid grouping rules in DB, using defaults", "error", err)
+		} else {
+			programLogger.Info("✅ Restored grouping rules from DB", "folder_overrides", len(rules.Folders))
+		}
+	}
+
 	// 6. Initialize Services
 	notifier := feedback.NewNotifier()
 	diskThumbGen := scanner.NewDiskThumbnailGenerator(thumbsFolder, programLogger)
+	// External tools (Blender, ...) get the first shot, the disk generator is the fallback.
+	thumbGen := scanner.NewExternalThumbnailGenerator(thumbsFolder, programLogger, sharedConfig, diskThumbGen)
 
-	scannerService := scanner.NewScanner(db, queries, diskThumbGen, programLogger, notifier, sharedConfig)
+	scannerService := scanner.NewScanner(db, queries, thumbGen, programLogger, notifier, sharedConfig)
 
 	watcherService, err := watcher.NewService(queries, programLogger, sharedConfig)
 	if err != nil {
@@ -185,16 +224,25 @@ func Initialize(migrations embed.FS) (*Dependencies, error) {
 		log.Fatal("Failed to create watcher:", err)
 	}
 
-	settingsService := settings.NewSettingsService(queries, programLogger, logLevel, notifier, watcherService, sharedConfig)
-	assetService := app.NewAssetService(queries, db, programLogger, notifier, thumbsFolder)
-	materialSetService := app.NewMaterialSetService(queries, programLogger, diskThumbGen)
+	settingsService := settings.NewSettingsService(queries, programLogger, logLevel, notifier, watcherService, scannerService, sharedConfig)
+	// Deleted files go to the system trash, or to the app's quarantine folder where there is none.
+	trashBin := trash.New(filepath.Join(appCachePath, "trash"), programLogger)
+	// Shared undo history of the asset and material set services.
+	journal := app.NewJournal(db, programLogger)
+	assetService := app.NewAssetService(queries, db, programLogger, notifier, thumbsFolder, trashBin, journal, sharedConfig, watcherService)
+	materialSetService := app.NewMaterialSetService(queries, programLogger, diskThumbGen, journal)
 	tagService := app.NewTagService(queries, programLogger)
+	savedSearchService := app.NewSavedSearchService(queries, programLogger)
+	paletteService := app.NewPaletteService(queries, db, programLogger, notifier, sharedConfig)
+	if err := paletteService.LoadActivePalette(ctx); err != nil {
+		programLogger.Error("❌ Failed to load the active color palette, using the built-in one", "error", err)
+	}
 	updateService := update.NewUpdateService(programLogger)
 
-	myApp := app.NewApp(queries, programLogger, assetService, materialSetService, tagService, scannerService, settingsService, watcherService, updateService)
+	myApp := app.NewApp(queries, programLogger, assetService, materialSetService, tagService, savedSearchService, paletteService, scannerService, settingsService, watcherService, updateService)
 
 	// 7. Cleanup Old Data (Logs and Soft-Deleted Assets older than 7 days)
-	cleanupOldData(logsFolder, queries, programLogger)
+	cleanupOldData(logsFolder, assetService, programLogger)
 
 	return &Dependencies{
 		DB:                 db,
@@ -205,6 +253,8 @@ func Initialize(migrations embed.FS) (*Dependencies, error) {
 		AssetService:       assetService,
 		MaterialSetService: materialSetService,
 		TagService:         tagService,
+		SavedSearchService: savedSearchService,
+		PaletteService:     paletteService,
 		ScannerService:     scannerService,
 		SettingsService:    settingsService,
 		WatcherService:     watcherService,
@@ -213,8 +263,9 @@ func Initialize(migrations embed.FS) (*Dependencies, error) {
 	}, nil
 }
 
-// cleanupOldData removes log files and database entries that are older than 7 days.
-func cleanupOldData(logsFolder string, queries *database.Queries, logger *slog.Logger) {
+// cleanupOldData removes log files older than 7 days and moves assets soft-deleted more than
+// 7 days ago out of the library (they stay restorable from the trash).
+func cleanupOldData(logsFolder string, assetService *app.AssetService, logger *slog.Logger) {
 	// 1. Cleanup Logs older than 7 days
 	files, err := os.ReadDir(logsFolder)
 	if err == nil {
@@ -238,10 +289,7 @@ func cleanupOldData(logsFolder string, queries *database.Queries, logger *slog.L
 	}
 
 	// 2. Cleanup Old Deleted Assets (Database entries)
-	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
-	defer cancel()
-
-	if err := queries.CleanupOldDeletedAssets(ctx); err != nil {
+	if err := assetService.CleanupExpiredAssets(); err != nil {
 		logger.Error("❌ Failed to cleanup old deleted assets", "error", err)
 	} else {
 		logger.Info("🧹 Successfully cleaned up old deleted assets (older than 7 days)")
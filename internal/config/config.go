@@ -20,12 +20,14 @@ var DefaultAllowedExtensions = []string{
 	".ztl", ".zpr", ".exr", ".hdr", ".tif", ".tiff", ".max", ".ma", ".mb",
 	".zbr", ".spp", ".sbs", ".sbsar", ".hip", ".hipnc", ".hiplc", ".psd",
 	".psb", ".ai", ".eps", ".uasset", ".umap", ".unity", ".prefab", ".mat", ".asset",
+	".bmp", ".tga", ".dds", ".ktx2",
 }
 
 // DangerousExtensions is a blacklist of extensions that should never be scanned for security reasons.
 var DangerousExtensions = []string{".exe", ".dll", ".bat", ".cmd", ".sh", ".vbs", ".msi", ".com", ".scr", ".js", ".ps1", ".bin"}
 
-// PredefinedPalette is a list of colors used for dominant color matching.
+// PredefinedPalette is the built-in list of colors used for dominant color matching.
+// It is the initial content of the "Default" palette; the active palette lives in ScannerConfig.
 var PredefinedPalette = []PaletteColor{
 	{"Black", "#000000"}, {"White", "#FFFFFF"}, {"Dark Gray", "#404040"}, {"Gray", "#808080"}, {"Light Gray", "#C0C0C0"},
 	{"Dark Red", "#8B0000"}, {"Red", "#FF0000"}, {"Crimson", "#DC143C"}, {"Pink", "#FFC0CB"}, {"Hot Pink", "#FF69B4"}, {"Coral", "#FF7F50"},
@@ -40,6 +42,12 @@ var PredefinedPalette = []PaletteColor{
 type ScannerConfig struct {
 	allowedExtensions    []string
 	maxAllowHashFileSize int64
+	colorPalette         []PaletteColor
+	thumbnailProviders   []ThumbnailProvider
+	materialChannels     []MaterialChannel
+	groupingRules        GroupingRuleSet
+	compiledGlobalRules  *CompiledGroupingRules
+	compiledFolderRules  map[int64]*CompiledGroupingRules
 	mu                   sync.RWMutex
 }
 
@@ -48,10 +56,17 @@ func NewScannerConfig() *ScannerConfig {
 	exts := make([]string, len(DefaultAllowedExtensions))
 	copy(exts, DefaultAllowedExtensions)
 
-	return &ScannerConfig{
+	cfg := &ScannerConfig{
 		allowedExtensions:    exts,
 		maxAllowHashFileSize: 1024 * 1024 * 256, // 256 MB
+		colorPalette:         slices.Clone(PredefinedPalette),
+		materialChannels:     slices.Clone(DefaultMaterialChannels),
 	}
+	// The defaults are static, so like regexp.MustCompile an error here is a programming bug.
+	if err := cfg.SetGroupingRules(GroupingRuleSet{Global: DefaultGroupingRules}); err != nil {
+		panic(err)
+	}
+	return cfg
 }
 
 // GetAllowedExtensions returns a copy of the currently allowed file extensions.
@@ -72,6 +87,20 @@ func (c *ScannerConfig) SetAllowedExtensions(exts []string) {
 	c.allowedExtensions = newExts
 }
 
+// GetColorPalette returns a copy of the active color palette.
+func (c *ScannerConfig) GetColorPalette() []PaletteColor {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	return slices.Clone(c.colorPalette)
+}
+
+// SetColorPalette safely replaces the active color palette.
+func (c *ScannerConfig) SetColorPalette(palette []PaletteColor) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.colorPalette = slices.Clone(palette)
+}
+
 // GetMaxHashFileSize returns the maximum file size (in bytes) that will be hashed.
 // Files larger than this will be skipped during hash calculation to preserve performance.
 func (c *ScannerConfig) GetMaxHashFileSize() int64 {
@@ -1,6 +1,7 @@
 package config
 
 import (
+	"encoding/json"
 	"sync"
 	"testing"
 
@@ -64,6 +65,18 @@ func TestNewScannerConfig_SliceIsolation(t *testing.T) {
 	assert.NotContains(t, newConfigExts, ".HACKED", "Config nie powinien przyjąć modyfikacji lokalnej kopii")
 }
 
+func TestScannerConfig_ColorPalette(t *testing.T) {
+	cfg := NewScannerConfig()
+	assert.Equal(t, PredefinedPalette, cfg.GetColorPalette(), "Domyślnie aktywna jest wbudowana paleta")
+
+	palette := cfg.GetColorPalette()
+	palette[0].Hex = "#123456"
+	assert.Equal(t, "#000000", PredefinedPalette[0].Hex, "CRITICAL: Globalna paleta została naruszona!")
+
+	cfg.SetColorPalette([]PaletteColor{{Name: "Moss", Hex: "#4A5D23"}})
+	assert.Equal(t, []PaletteColor{{Name: "Moss", Hex: "#4A5D23"}}, cfg.GetColorPalette())
+}
+
 func TestScannerConfig_Concurrency(t *testing.T) {
 	cfg := NewScannerConfig()
 	var wg sync.WaitGroup
@@ -86,3 +99,126 @@ func TestScannerConfig_Concurrency(t *testing.T) {
 	wg.Wait()
 	assert.True(t, cfg.IsExtensionAllowed("test.jpg"))
 }
+
+func TestThumbnailProvider_ArgsAndValidate(t *testing.T) {
+	p := ThumbnailProvider{
+		Name:       " Blender ",
+		Extensions: []string{"BLEND", ".blend1", ".blend"},
+		Command:    `blender -b {in} --python "C:\tools\render thumb.py" -- {out}`,
+	}.Normalize()
+	assert.NoError(t, p.Validate())
+	assert.Equal(t, "Blender", p.Name)
+	assert.Equal(t, []string{".blend", ".blend1"}, p.Extensions)
+	assert.True(t, p.Handles(".BLEND"))
+
+	args, err := p.Args("/lib/my scene.blend", "/tmp/out.png")
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"blender", "-b", "/lib/my scene.blend", "--python", `C:\tools\render thumb.py`, "--", "/tmp/out.png"}, args)
+
+	assert.Equal(t, DefaultThumbnailProviderTimeout, p.Timeout())
+	assert.Equal(t, DefaultThumbnailProviderConcurrency, p.Concurrency())
+
+	missingOut := p
+	missingOut.Command = "blender -b {in}"
+	assert.Error(t, missingOut.Validate())
+
+	unterminated := p
+	unterminated.Command = `blender "{in} {out}`
+	assert.Error(t, unterminated.Validate())
+
+	dangerous := p
+	dangerous.Extensions = []string{".exe"}
+	assert.Error(t, dangerous.Validate())
+}
+
+func TestScannerConfig_MatchMaterialChannel(t *testing.T) {
+	cfg := NewScannerConfig()
+	tests := []struct {
+		name, material, role string
+	}{
+		{"rock_albedo", "rock", ChannelBaseColor},
+		{"Old Rock_Base-Color", "old rock", ChannelBaseColor}, // "base_color" wygrywa z krótszym "color"
+		{"rock-nrm", "rock", ChannelNormal},
+		{"rock.metalness", "rock", ChannelMetallic},
+		{"rock__ao", "rock", ChannelAO},
+	}
+	for _, tt := range tests {
+		material, role, ok := cfg.MatchMaterialChannel(tt.name)
+		assert.True(t, ok, tt.name)
+		assert.Equal(t, tt.material, material, tt.name)
+		assert.Equal(t, tt.role, role, tt.name)
+	}
+
+	for _, name := range []string{"rock", "albedo", "_albedo", "rockalbedo", "rock_albedo_extra"} {
+		_, _, ok := cfg.MatchMaterialChannel(name)
+		assert.False(t, ok, name)
+	}
+}
+
+func TestMaterialChannel_NormalizeAndValidate(t *testing.T) {
+	m := MaterialChannel{Role: " normal ", Synonyms: []string{"NRM", "Normal-GL", "nrm", " "}}.Normalize()
+	assert.NoError(t, m.Validate())
+	assert.Equal(t, "normal", m.Role)
+	assert.Equal(t, []string{"nrm", "normal_gl"}, m.Synonyms)
+
+	assert.Error(t, MaterialChannel{Role: "specular", Synonyms: []string{"spec"}}.Validate())
+	assert.Error(t, MaterialChannel{Role: ChannelAO}.Normalize().Validate())
+	assert.Error(t, MaterialChannel{Role: ChannelAO, Synonyms: []string{"--"}}.Normalize().Validate())
+}
+
+func TestScannerConfig_GroupingRules(t *testing.T) {
+	cfg := NewScannerConfig()
+	global := cfg.GroupingRulesFor(0)
+	assert.Equal(t, DefaultGroupingMinBaseLength, global.MinBaseLength)
+	assert.Len(t, global.Strip, len(DefaultGroupingRules.StripPatterns))
+	assert.False(t, cfg.HasFolderGroupingRules(7))
+
+	set := cfg.GetGroupingRules()
+	set.Folders[7] = GroupingRules{StripPatterns: []string{`_lod\d+$`, "  "}, IgnorePatterns: []string{`^thumb`}, CrossFolders: true}
+	assert.NoError(t, cfg.SetGroupingRules(set))
+
+	assert.True(t, cfg.HasFolderGroupingRules(7))
+	folder := cfg.GroupingRulesFor(7)
+	assert.Len(t, folder.Strip, 1, "Puste wzorce są pomijane")
+	assert.Equal(t, DefaultGroupingMinBaseLength, folder.MinBaseLength, "0 oznacza wartość domyślną")
+	assert.True(t, folder.CrossFolders)
+	assert.True(t, folder.Ignores("Thumb_Rock.png"), "Wzorce dopasowują małe litery")
+	assert.Same(t, cfg.GroupingRulesFor(0), cfg.GroupingRulesFor(8), "Foldery bez nadpisania używają reguł globalnych")
+
+	// Kopia zwrócona przez Get nie zmienia konfiguracji
+	cfg.GetGroupingRules().Folders[7].StripPatterns[0] = "changed"
+	assert.Equal(t, `_lod\d+$`, cfg.GetGroupingRules().Folders[7].StripPatterns[0])
+}
+
+func TestScannerConfig_SetGroupingRulesInvalid(t *testing.T) {
+	cfg := NewScannerConfig()
+	before := cfg.GetGroupingRules()
+
+	set := cfg.GetGroupingRules()
+	set.Global.MinBaseLength = 5
+	set.Folders[3] = GroupingRules{IgnorePatterns: []string{"(unclosed"}}
+	err := cfg.SetGroupingRules(set)
+	assert.ErrorContains(t, err, "folder 3")
+	assert.ErrorContains(t, err, "invalid ignore pattern")
+	assert.Equal(t, before, cfg.GetGroupingRules(), "Błędne reguły niczego nie zmieniają")
+
+	assert.Error(t, cfg.SetGroupingRules(GroupingRuleSet{Global: GroupingRules{MinBaseLength: -1}}))
+	assert.Error(t, cfg.SetGroupingRules(GroupingRuleSet{Global: GroupingRules{StripPatterns: []string{"[a-"}}}))
+}
+
+func TestGroupingRuleSet_JSONRoundTrip(t *testing.T) {
+	cfg := NewScannerConfig()
+	set := cfg.GetGroupingRules()
+	set.Folders[12] = GroupingRules{StripPatterns: []string{`_final$`}, MinBaseLength: 4}
+	assert.NoError(t, cfg.SetGroupingRules(set))
+
+	data, err := json.Marshal(cfg.GetGroupingRules())
+	assert.NoError(t, err)
+	var restored GroupingRuleSet
+	assert.NoError(t, json.Unmarshal(data, &restored))
+
+	other := NewScannerConfig()
+	assert.NoError(t, other.SetGroupingRules(restored))
+	assert.Equal(t, cfg.GetGroupingRules(), other.GetGroupingRules())
+	assert.Equal(t, 4, other.GroupingRulesFor(12).MinBaseLength)
+}
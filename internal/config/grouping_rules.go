@@ -0,0 +1,178 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"maps"
+	"regexp"
+	"slices"
+	"strings"
+)
+
+// DefaultGroupingMinBaseLength applies when rules have no minimum base length configured.
+const DefaultGroupingMinBaseLength = 3
+
+// GroupingRules decide which files the scanner groups by name. Patterns are regular expressions
+// matched against the lower-case file name.
+type GroupingRules struct {
+	// StripPatterns are removed from the name without extension, in order, until it stops
+	// changing; files reducing to the same base name are grouped.
+	StripPatterns []string `json:"stripPatterns"`
+	// IgnorePatterns mark files that are never grouped by name (duplicates still are).
+	IgnorePatterns []string `json:"ignorePatterns"`
+	MinBaseLength  int      `json:"minBaseLength"` // Shorter base names are not grouped; 0 means DefaultGroupingMinBaseLength
+	CrossFolders   bool     `json:"crossFolders"`  // Look for siblings in all scan folders, not only the file's own
+}
+
+// GroupingRuleSet holds the global rules and the scan folders that override them.
+type GroupingRuleSet struct {
+	Global  GroupingRules           `json:"global"`
+	Folders map[int64]GroupingRules `json:"folders"` // Keyed by scan folder ID
+}
+
+// DefaultGroupingRules strip common versioning conventions.
+var DefaultGroupingRules = GroupingRules{
+	StripPatterns: []string{
+		`^\d+[._ -]+`,                          // 01_, 7. - leading numbers
+		`[_ -]v\d+$`,                           // _v1, -v02, v3
+		`[_ -]ver\d+$`,                         // _ver1
+		`[_ -]version\s*\d+$`,                  // _version 1
+		`[_ -]copy(\s*\d+)?$`,                  // _copy, -copy 2
+		`\s*\(\d+\)$`,                          // (1), (2) - Windows/Linux system duplicates
+		`[_ -]final$`,                          // _final
+		`[_ -]robocze$`,                        // _robocze (Polish for working)
+		`[_ -](work|working|backup|temp|old)$`, // Common variants
+		`[_ -]\d+$`,                            // _001, -02, etc. (trailing numbers with separator)
+	},
+	MinBaseLength: DefaultGroupingMinBaseLength,
+}
+
+// CompiledGroupingRules are validated GroupingRules, ready for matching.
+type CompiledGroupingRules struct {
+	Strip         []*regexp.Regexp
+	Ignore        []*regexp.Regexp
+	MinBaseLength int
+	CrossFolders  bool
+}
+
+// Ignores reports whether the file name is kept out of name grouping.
+func (r *CompiledGroupingRules) Ignores(filename string) bool {
+	name := strings.ToLower(filename)
+	return slices.ContainsFunc(r.Ignore, func(re *regexp.Regexp) bool { return re.MatchString(name) })
+}
+
+// Normalize returns a copy without blank patterns and with the default minimum base length.
+func (r GroupingRules) Normalize() GroupingRules {
+	clean := func(patterns []string) []string {
+		out := make([]string, 0, len(patterns))
+		for _, p := range patterns {
+			if p = strings.TrimSpace(p); p != "" {
+				out = append(out, p)
+			}
+		}
+		return out
+	}
+	n := r
+	n.StripPatterns = clean(r.StripPatterns)
+	n.IgnorePatterns = clean(r.IgnorePatterns)
+	if n.MinBaseLength == 0 {
+		n.MinBaseLength = DefaultGroupingMinBaseLength
+	}
+	return n
+}
+
+// Compile validates the patterns. It expects normalized rules.
+func (r GroupingRules) Compile() (*CompiledGroupingRules, error) {
+	if r.MinBaseLength < 1 {
+		return nil, errors.New("minimum base length must be at least 1")
+	}
+	compile := func(kind string, patterns []string) ([]*regexp.Regexp, error) {
+		out := make([]*regexp.Regexp, 0, len(patterns))
+		for _, p := range patterns {
+			re, err := regexp.Compile(p)
+			if err != nil {
+				return nil, fmt.Errorf("invalid %s pattern %q: %w", kind, p, err)
+			}
+			out = append(out, re)
+		}
+		return out, nil
+	}
+	strip, err := compile("strip", r.StripPatterns)
+	if err != nil {
+		return nil, err
+	}
+	ignore, err := compile("ignore", r.IgnorePatterns)
+	if err != nil {
+		return nil, err
+	}
+	return &CompiledGroupingRules{
+		Strip:         strip,
+		Ignore:        ignore,
+		MinBaseLength: r.MinBaseLength,
+		CrossFolders:  r.CrossFolders,
+	}, nil
+}
+
+func (r GroupingRules) clone() GroupingRules {
+	r.StripPatterns = slices.Clone(r.StripPatterns)
+	r.IgnorePatterns = slices.Clone(r.IgnorePatterns)
+	return r
+}
+
+// GetGroupingRules returns a copy of the global and per folder grouping rules.
+func (c *ScannerConfig) GetGroupingRules() GroupingRuleSet {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	set := GroupingRuleSet{Global: c.groupingRules.Global.clone(), Folders: make(map[int64]GroupingRules, len(c.groupingRules.Folders))}
+	for id, r := range c.groupingRules.Folders {
+		set.Folders[id] = r.clone()
+	}
+	return set
+}
+
+// SetGroupingRules normalizes, validates and replaces all grouping rules. Nothing changes if
+// any of the rules is invalid.
+func (c *ScannerConfig) SetGroupingRules(set GroupingRuleSet) error {
+	global := set.Global.Normalize()
+	compiledGlobal, err := global.Compile()
+	if err != nil {
+		return err
+	}
+	folders := make(map[int64]GroupingRules, len(set.Folders))
+	compiledFolders := make(map[int64]*CompiledGroupingRules, len(set.Folders))
+	for _, id := range slices.Sorted(maps.Keys(set.Folders)) {
+		rules := set.Folders[id].Normalize()
+		compiled, err := rules.Compile()
+		if err != nil {
+			return fmt.Errorf("folder %d: %w", id, err)
+		}
+		folders[id] = rules
+		compiledFolders[id] = compiled
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.groupingRules = GroupingRuleSet{Global: global, Folders: folders}
+	c.compiledGlobalRules = compiledGlobal
+	c.compiledFolderRules = compiledFolders
+	return nil
+}
+
+// GroupingRulesFor returns the rules that apply to files of a scan folder: its own if it
+// overrides the global ones. Folder ID 0 returns the global rules.
+func (c *ScannerConfig) GroupingRulesFor(folderID int64) *CompiledGroupingRules {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	if r, ok := c.compiledFolderRules[folderID]; ok {
+		return r
+	}
+	return c.compiledGlobalRules
+}
+
+// HasFolderGroupingRules reports whether a scan folder overrides the global grouping rules.
+func (c *ScannerConfig) HasFolderGroupingRules(folderID int64) bool {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	_, ok := c.compiledFolderRules[folderID]
+	return ok
+}
@@ -0,0 +1,132 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// Material channel roles of the texture maps in a PBR set.
+const (
+	ChannelBaseColor = "baseColor"
+	ChannelNormal    = "normal"
+	ChannelRoughness = "roughness"
+	ChannelMetallic  = "metallic"
+	ChannelAO        = "ao"
+	ChannelHeight    = "height"
+	ChannelOpacity   = "opacity"
+)
+
+// MaterialChannelRoles lists the known roles in the order a material's maps are shown.
+var MaterialChannelRoles = []string{
+	ChannelBaseColor, ChannelNormal, ChannelRoughness, ChannelMetallic, ChannelAO, ChannelHeight, ChannelOpacity,
+}
+
+// MaterialChannel maps the file name suffixes that mark a texture map to its role,
+// e.g. "rock_albedo.png" and "rock_diffuse.png" are both the base color of "rock".
+type MaterialChannel struct {
+	Role     string   `json:"role"`
+	Synonyms []string `json:"synonyms"`
+}
+
+// DefaultMaterialChannels contains the suffixes exported by common texturing tools.
+var DefaultMaterialChannels = []MaterialChannel{
+	{ChannelBaseColor, []string{"basecolor", "base_color", "albedo", "diffuse", "diff", "color", "col"}},
+	{ChannelNormal, []string{"normal", "normalgl", "normaldx", "nrm", "nor", "norm"}},
+	{ChannelRoughness, []string{"roughness", "rough", "rgh"}},
+	{ChannelMetallic, []string{"metallic", "metalness", "metal", "mtl"}},
+	{ChannelAO, []string{"ao", "ambientocclusion", "ambient_occlusion", "occlusion", "occ"}},
+	{ChannelHeight, []string{"height", "displacement", "disp", "bump"}},
+	{ChannelOpacity, []string{"opacity", "alpha", "transparency"}},
+}
+
+// Normalize returns a copy with lower-case synonyms whose separators are unified to "_".
+func (m MaterialChannel) Normalize() MaterialChannel {
+	n := MaterialChannel{Role: strings.TrimSpace(m.Role), Synonyms: make([]string, 0, len(m.Synonyms))}
+	for _, syn := range m.Synonyms {
+		syn = canonicalChannelName(strings.TrimSpace(syn))
+		if syn != "" && !slices.Contains(n.Synonyms, syn) {
+			n.Synonyms = append(n.Synonyms, syn)
+		}
+	}
+	return n
+}
+
+// Validate checks that the role is known and has suffixes. It expects a normalized channel.
+func (m MaterialChannel) Validate() error {
+	if !slices.Contains(MaterialChannelRoles, m.Role) {
+		return fmt.Errorf("unknown material channel %q", m.Role)
+	}
+	if len(m.Synonyms) == 0 {
+		return fmt.Errorf("material channel %q has no synonyms", m.Role)
+	}
+	for _, syn := range m.Synonyms {
+		if strings.Trim(syn, "_") == "" {
+			return errors.New("material channel synonyms cannot be only separators")
+		}
+	}
+	return nil
+}
+
+// canonicalChannelName lower-cases a name and turns the separators used in file names into "_".
+func canonicalChannelName(s string) string {
+	return strings.Map(func(r rune) rune {
+		if isChannelSeparator(r) {
+			return '_'
+		}
+		return r
+	}, strings.ToLower(s))
+}
+
+func isChannelSeparator(r rune) bool {
+	return r == '_' || r == ' ' || r == '-' || r == '.'
+}
+
+// GetMaterialChannels returns a copy of the configured material channel synonyms.
+func (c *ScannerConfig) GetMaterialChannels() []MaterialChannel {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	result := make([]MaterialChannel, len(c.materialChannels))
+	for i, m := range c.materialChannels {
+		m.Synonyms = slices.Clone(m.Synonyms)
+		result[i] = m
+	}
+	return result
+}
+
+// SetMaterialChannels safely replaces the material channel synonyms.
+func (c *ScannerConfig) SetMaterialChannels(channels []MaterialChannel) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.materialChannels = make([]MaterialChannel, len(channels))
+	for i, m := range channels {
+		m.Synonyms = slices.Clone(m.Synonyms)
+		c.materialChannels[i] = m
+	}
+}
+
+// MatchMaterialChannel splits a file name without extension into the lower-case material name
+// and the channel role of its suffix ("Rock_Albedo" -> "rock", "baseColor"). The suffix must
+// follow a separator; when several synonyms match, the longest wins.
+func (c *ScannerConfig) MatchMaterialChannel(name string) (material, role string, ok bool) {
+	lower := strings.ToLower(name)
+	// Only single-byte separators are replaced, so offsets in name match those in lower.
+	name = canonicalChannelName(lower)
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	best := 0
+	for _, m := range c.materialChannels {
+		for _, syn := range m.Synonyms {
+			cut := len(name) - len(syn) - 1
+			if len(syn) <= best || cut < 1 || name[cut] != '_' || name[cut+1:] != syn {
+				continue
+			}
+			if base := strings.TrimRightFunc(lower[:cut], isChannelSeparator); base != "" {
+				material, role, best = base, m.Role, len(syn)
+			}
+		}
+	}
+	return material, role, best > 0
+}
@@ -0,0 +1,175 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+)
+
+const (
+	// DefaultThumbnailProviderTimeout applies when a provider has no timeout configured.
+	DefaultThumbnailProviderTimeout = 60 * time.Second
+	// DefaultThumbnailProviderConcurrency applies when a provider has no concurrency limit configured.
+	// DCC tools are heavy, so by default only one instance runs at a time.
+	DefaultThumbnailProviderConcurrency = 1
+
+	// ThumbnailInputPlaceholder is replaced with the source file path in a provider command.
+	ThumbnailInputPlaceholder = "{in}"
+	// ThumbnailOutputPlaceholder is replaced with the path of the image the provider must write.
+	ThumbnailOutputPlaceholder = "{out}"
+)
+
+// ThumbnailProvider is an external tool that renders thumbnails for some file extensions,
+// e.g. `blender -b {in} --python render_thumb.py -- {out}` for .blend files.
+type ThumbnailProvider struct {
+	Name           string   `json:"name"`
+	Extensions     []string `json:"extensions"`
+	Command        string   `json:"command"`
+	TimeoutSeconds int      `json:"timeoutSeconds"` // 0 means DefaultThumbnailProviderTimeout
+	MaxConcurrent  int      `json:"maxConcurrent"`  // 0 means DefaultThumbnailProviderConcurrency
+}
+
+// Timeout returns the maximum run time of a single render.
+func (p ThumbnailProvider) Timeout() time.Duration {
+	if p.TimeoutSeconds <= 0 {
+		return DefaultThumbnailProviderTimeout
+	}
+	return time.Duration(p.TimeoutSeconds) * time.Second
+}
+
+// Concurrency returns how many renders of this provider may run at the same time.
+func (p ThumbnailProvider) Concurrency() int {
+	if p.MaxConcurrent <= 0 {
+		return DefaultThumbnailProviderConcurrency
+	}
+	return p.MaxConcurrent
+}
+
+// Handles reports whether the provider is configured for the extension (e.g. ".blend").
+func (p ThumbnailProvider) Handles(ext string) bool {
+	return slices.Contains(p.Extensions, strings.ToLower(ext))
+}
+
+// Args splits the command template and substitutes the input and output paths.
+// Placeholders are replaced after splitting, so paths with spaces stay a single argument.
+func (p ThumbnailProvider) Args(in, out string) ([]string, error) {
+	fields, err := splitCommandTemplate(p.Command)
+	if err != nil {
+		return nil, err
+	}
+	if len(fields) == 0 {
+		return nil, errors.New("command is empty")
+	}
+	for i, f := range fields {
+		f = strings.ReplaceAll(f, ThumbnailInputPlaceholder, in)
+		fields[i] = strings.ReplaceAll(f, ThumbnailOutputPlaceholder, out)
+	}
+	return fields, nil
+}
+
+// Normalize returns a copy with trimmed fields and lower-case, dot-prefixed extensions.
+func (p ThumbnailProvider) Normalize() ThumbnailProvider {
+	n := p
+	n.Name = strings.TrimSpace(p.Name)
+	n.Command = strings.TrimSpace(p.Command)
+	n.Extensions = make([]string, 0, len(p.Extensions))
+	for _, ext := range p.Extensions {
+		ext = strings.ToLower(strings.TrimSpace(ext))
+		if ext != "" && !strings.HasPrefix(ext, ".") {
+			ext = "." + ext
+		}
+		if ext != "" && !slices.Contains(n.Extensions, ext) {
+			n.Extensions = append(n.Extensions, ext)
+		}
+	}
+	return n
+}
+
+// Validate checks that the provider can be run. It expects a normalized provider.
+func (p ThumbnailProvider) Validate() error {
+	if p.Name == "" {
+		return errors.New("provider name is required")
+	}
+	if len(p.Extensions) == 0 {
+		return fmt.Errorf("provider %q has no extensions", p.Name)
+	}
+	for _, ext := range p.Extensions {
+		if !IsExtensionValid(ext) {
+			return fmt.Errorf("provider %q: invalid extension %q", p.Name, ext)
+		}
+	}
+	if !strings.Contains(p.Command, ThumbnailInputPlaceholder) || !strings.Contains(p.Command, ThumbnailOutputPlaceholder) {
+		return fmt.Errorf("provider %q: command must contain %s and %s", p.Name, ThumbnailInputPlaceholder, ThumbnailOutputPlaceholder)
+	}
+	if _, err := p.Args("", ""); err != nil {
+		return fmt.Errorf("provider %q: %w", p.Name, err)
+	}
+	if p.TimeoutSeconds < 0 || p.MaxConcurrent < 0 {
+		return fmt.Errorf("provider %q: timeout and concurrency cannot be negative", p.Name)
+	}
+	return nil
+}
+
+// splitCommandTemplate splits a command line on whitespace, honouring single and double quotes.
+// Backslashes are kept as-is so Windows paths work without escaping.
+func splitCommandTemplate(cmd string) ([]string, error) {
+	var fields []string
+	var current strings.Builder
+	inField := false
+	var quote rune
+
+	for _, r := range cmd {
+		switch {
+		case quote != 0:
+			if r == quote {
+				quote = 0
+			} else {
+				current.WriteRune(r)
+			}
+		case r == '"' || r == '\'':
+			quote = r
+			inField = true
+		case r == ' ' || r == '\t' || r == '\n' || r == '\r':
+			if inField {
+				fields = append(fields, current.String())
+				current.Reset()
+				inField = false
+			}
+		default:
+			current.WriteRune(r)
+			inField = true
+		}
+	}
+	if quote != 0 {
+		return nil, errors.New("unterminated quote in command")
+	}
+	if inField {
+		fields = append(fields, current.String())
+	}
+	return fields, nil
+}
+
+// GetThumbnailProviders returns a copy of the configured external thumbnail providers.
+func (c *ScannerConfig) GetThumbnailProviders() []ThumbnailProvider {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	result := make([]ThumbnailProvider, len(c.thumbnailProviders))
+	for i, p := range c.thumbnailProviders {
+		p.Extensions = slices.Clone(p.Extensions)
+		result[i] = p
+	}
+	return result
+}
+
+// SetThumbnailProviders safely replaces the external thumbnail providers.
+func (c *ScannerConfig) SetThumbnailProviders(providers []ThumbnailProvider) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.thumbnailProviders = make([]ThumbnailProvider, len(providers))
+	for i, p := range providers {
+		p.Extensions = slices.Clone(p.Extensions)
+		c.thumbnailProviders[i] = p
+	}
+}
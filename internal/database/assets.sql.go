@@ -30,13 +30,99 @@ func (q *Queries) ClaimAssetsForPath(ctx context.Context, arg ClaimAssetsForPath
 	return err
 }
 
-const cleanupOldDeletedAssets = `-- name: CleanupOldDeletedAssets :exec
-DELETE FROM assets
-WHERE is_deleted = 1 AND deleted_at < datetime('now', '-7 days')
+const copyAssetColors = `-- name: CopyAssetColors :exec
+INSERT INTO asset_colors (asset_id, position, hex, palette_name, palette_hex, weight, lab_l, lab_a, lab_b)
+SELECT CAST(? AS INTEGER), position, hex, palette_name, palette_hex, weight, lab_l, lab_a, lab_b
+FROM asset_colors WHERE asset_id = ?
 `
 
-func (q *Queries) CleanupOldDeletedAssets(ctx context.Context) error {
-	_, err := q.exec(ctx, q.cleanupOldDeletedAssetsStmt, cleanupOldDeletedAssets)
+type CopyAssetColorsParams struct {
+	NewAssetID int64 `json:"newAssetId"`
+	AssetID    int64 `json:"assetId"`
+}
+
+func (q *Queries) CopyAssetColors(ctx context.Context, arg CopyAssetColorsParams) error {
+	_, err := q.exec(ctx, q.copyAssetColorsStmt, copyAssetColors, arg.NewAssetID, arg.AssetID)
+	return err
+}
+
+const copyAssetFeatures = `-- name: CopyAssetFeatures :exec
+INSERT INTO asset_features (asset_id, color_histogram)
+SELECT CAST(? AS INTEGER), color_histogram
+FROM asset_features WHERE asset_id = ?
+`
+
+type CopyAssetFeaturesParams struct {
+	NewAssetID int64 `json:"newAssetId"`
+	AssetID    int64 `json:"assetId"`
+}
+
+func (q *Queries) CopyAssetFeatures(ctx context.Context, arg CopyAssetFeaturesParams) error {
+	_, err := q.exec(ctx, q.copyAssetFeaturesStmt, copyAssetFeatures, arg.NewAssetID, arg.AssetID)
+	return err
+}
+
+const copyAssetMaterialSets = `-- name: CopyAssetMaterialSets :exec
+INSERT INTO asset_material_sets (asset_id, material_set_id)
+SELECT CAST(? AS INTEGER), material_set_id
+FROM asset_material_sets WHERE asset_id = ?
+`
+
+type CopyAssetMaterialSetsParams struct {
+	NewAssetID int64 `json:"newAssetId"`
+	AssetID    int64 `json:"assetId"`
+}
+
+func (q *Queries) CopyAssetMaterialSets(ctx context.Context, arg CopyAssetMaterialSetsParams) error {
+	_, err := q.exec(ctx, q.copyAssetMaterialSetsStmt, copyAssetMaterialSets, arg.NewAssetID, arg.AssetID)
+	return err
+}
+
+const copyAssetProperties = `-- name: CopyAssetProperties :exec
+INSERT INTO asset_properties (asset_id, key, value)
+SELECT CAST(? AS INTEGER), key, value
+FROM asset_properties WHERE asset_id = ?
+`
+
+type CopyAssetPropertiesParams struct {
+	NewAssetID int64 `json:"newAssetId"`
+	AssetID    int64 `json:"assetId"`
+}
+
+func (q *Queries) CopyAssetProperties(ctx context.Context, arg CopyAssetPropertiesParams) error {
+	_, err := q.exec(ctx, q.copyAssetPropertiesStmt, copyAssetProperties, arg.NewAssetID, arg.AssetID)
+	return err
+}
+
+const copyAssetTags = `-- name: CopyAssetTags :exec
+INSERT INTO asset_tags (asset_id, tag_id)
+SELECT CAST(? AS INTEGER), tag_id
+FROM asset_tags WHERE asset_id = ?
+`
+
+type CopyAssetTagsParams struct {
+	NewAssetID int64 `json:"newAssetId"`
+	AssetID    int64 `json:"assetId"`
+}
+
+func (q *Queries) CopyAssetTags(ctx context.Context, arg CopyAssetTagsParams) error {
+	_, err := q.exec(ctx, q.copyAssetTagsStmt, copyAssetTags, arg.NewAssetID, arg.AssetID)
+	return err
+}
+
+const copyModelMetadata = `-- name: CopyModelMetadata :exec
+INSERT INTO model_metadata (asset_id, vertex_count, triangle_count, mesh_count, material_count, texture_count, texture_files, size_x, size_y, size_z)
+SELECT CAST(? AS INTEGER), vertex_count, triangle_count, mesh_count, material_count, texture_count, texture_files, size_x, size_y, size_z
+FROM model_metadata WHERE asset_id = ?
+`
+
+type CopyModelMetadataParams struct {
+	NewAssetID int64 `json:"newAssetId"`
+	AssetID    int64 `json:"assetId"`
+}
+
+func (q *Queries) CopyModelMetadata(ctx context.Context, arg CopyModelMetadataParams) error {
+	_, err := q.exec(ctx, q.copyModelMetadataStmt, copyModelMetadata, arg.NewAssetID, arg.AssetID)
 	return err
 }
 
@@ -45,11 +131,11 @@ INSERT INTO assets (
     scan_folder_id, file_name, file_path, file_type, file_size,
     thumbnail_path, file_hash,
     image_width, image_height, dominant_color, bit_depth, has_alpha_channel,
-    last_modified, last_scanned, group_id
+    last_modified, last_scanned, group_id, perceptual_hash, material_channel, analysis_version
 ) VALUES (
-    ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?
+    ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?
 )
-RETURNING id, scan_folder_id, group_id, file_name, file_path, file_type, file_size, thumbnail_path, rating, description, is_favorite, image_width, image_height, dominant_color, bit_depth, has_alpha_channel, date_added, last_scanned, last_modified, file_hash, is_deleted, deleted_at, is_hidden
+RETURNING id, scan_folder_id, group_id, file_name, file_path, file_type, file_size, thumbnail_path, rating, description, is_favorite, image_width, image_height, dominant_color, bit_depth, has_alpha_channel, date_added, last_scanned, last_modified, file_hash, is_deleted, deleted_at, is_hidden, perceptual_hash, material_channel, analysis_version
 `
 
 type CreateAssetParams struct {
@@ -68,6 +154,9 @@ type CreateAssetParams struct {
 	LastModified    time.Time      `json:"lastModified"`
 	LastScanned     time.Time      `json:"lastScanned"`
 	GroupID         string         `json:"groupId"`
+	PerceptualHash  sql.NullString `json:"perceptualHash"`
+	MaterialChannel string         `json:"materialChannel"`
+	AnalysisVersion int64          `json:"analysisVersion"`
 }
 
 func (q *Queries) CreateAsset(ctx context.Context, arg CreateAssetParams) (Asset, error) {
@@ -87,6 +176,9 @@ func (q *Queries) CreateAsset(ctx context.Context, arg CreateAssetParams) (Asset
 		arg.LastModified,
 		arg.LastScanned,
 		arg.GroupID,
+		arg.PerceptualHash,
+		arg.MaterialChannel,
+		arg.AnalysisVersion,
 	)
 	var i Asset
 	err := row.Scan(
@@ -113,6 +205,9 @@ func (q *Queries) CreateAsset(ctx context.Context, arg CreateAssetParams) (Asset
 		&i.IsDeleted,
 		&i.DeletedAt,
 		&i.IsHidden,
+		&i.PerceptualHash,
+		&i.MaterialChannel,
+		&i.AnalysisVersion,
 	)
 	return i, err
 }
@@ -185,6 +280,49 @@ func (q *Queries) FindPotentialSiblings(ctx context.Context, arg FindPotentialSi
 	return items, nil
 }
 
+const findPotentialSiblingsInLibrary = `-- name: FindPotentialSiblingsInLibrary :many
+SELECT id, group_id, file_name
+FROM assets
+WHERE file_name LIKE ?
+  AND id != ?
+LIMIT ?
+`
+
+type FindPotentialSiblingsInLibraryParams struct {
+	FileName string `json:"fileName"`
+	ID       int64  `json:"id"`
+	Limit    int64  `json:"limit"`
+}
+
+type FindPotentialSiblingsInLibraryRow struct {
+	ID       int64  `json:"id"`
+	GroupID  string `json:"groupId"`
+	FileName string `json:"fileName"`
+}
+
+func (q *Queries) FindPotentialSiblingsInLibrary(ctx context.Context, arg FindPotentialSiblingsInLibraryParams) ([]FindPotentialSiblingsInLibraryRow, error) {
+	rows, err := q.query(ctx, q.findPotentialSiblingsInLibraryStmt, findPotentialSiblingsInLibrary, arg.FileName, arg.ID, arg.Limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []FindPotentialSiblingsInLibraryRow
+	for rows.Next() {
+		var i FindPotentialSiblingsInLibraryRow
+		if err := rows.Scan(&i.ID, &i.GroupID, &i.FileName); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const getAllColors = `-- name: GetAllColors :many
 SELECT DISTINCT dominant_color
 FROM assets a
@@ -194,10 +332,21 @@ WHERE a.is_deleted = 0
   AND f.is_active = 1
   AND is_hidden = 0
   AND dominant_color IS NOT NULL AND dominant_color != ''
+UNION
+SELECT DISTINCT ac.palette_hex
+FROM asset_colors ac
+JOIN assets a ON a.id = ac.asset_id
+JOIN scan_folders f ON a.scan_folder_id = f.id
+WHERE a.is_deleted = 0
+  AND f.is_deleted = 0
+  AND f.is_active = 1
+  AND a.is_hidden = 0
+  AND ac.palette_hex != ''
+  AND ac.weight >= ?
 `
 
-func (q *Queries) GetAllColors(ctx context.Context) ([]sql.NullString, error) {
-	rows, err := q.query(ctx, q.getAllColorsStmt, getAllColors)
+func (q *Queries) GetAllColors(ctx context.Context, weight float64) ([]sql.NullString, error) {
+	rows, err := q.query(ctx, q.getAllColorsStmt, getAllColors, weight)
 	if err != nil {
 		return nil, err
 	}
@@ -220,7 +369,7 @@ func (q *Queries) GetAllColors(ctx context.Context) ([]sql.NullString, error) {
 }
 
 const getAssetByHash = `-- name: GetAssetByHash :one
-SELECT id, scan_folder_id, group_id, file_name, file_path, file_type, file_size, thumbnail_path, rating, description, is_favorite, image_width, image_height, dominant_color, bit_depth, has_alpha_channel, date_added, last_scanned, last_modified, file_hash, is_deleted, deleted_at, is_hidden FROM assets
+SELECT id, scan_folder_id, group_id, file_name, file_path, file_type, file_size, thumbnail_path, rating, description, is_favorite, image_width, image_height, dominant_color, bit_depth, has_alpha_channel, date_added, last_scanned, last_modified, file_hash, is_deleted, deleted_at, is_hidden, perceptual_hash, material_channel, analysis_version FROM assets
 WHERE file_hash = ? AND file_hash IS NOT NULL
 LIMIT 1
 `
@@ -252,12 +401,15 @@ func (q *Queries) GetAssetByHash(ctx context.Context, fileHash sql.NullString) (
 		&i.IsDeleted,
 		&i.DeletedAt,
 		&i.IsHidden,
+		&i.PerceptualHash,
+		&i.MaterialChannel,
+		&i.AnalysisVersion,
 	)
 	return i, err
 }
 
 const getAssetById = `-- name: GetAssetById :one
-SELECT id, scan_folder_id, group_id, file_name, file_path, file_type, file_size, thumbnail_path, rating, description, is_favorite, image_width, image_height, dominant_color, bit_depth, has_alpha_channel, date_added, last_scanned, last_modified, file_hash, is_deleted, deleted_at, is_hidden FROM assets
+SELECT id, scan_folder_id, group_id, file_name, file_path, file_type, file_size, thumbnail_path, rating, description, is_favorite, image_width, image_height, dominant_color, bit_depth, has_alpha_channel, date_added, last_scanned, last_modified, file_hash, is_deleted, deleted_at, is_hidden, perceptual_hash, material_channel, analysis_version FROM assets
 WHERE id = ? LIMIT 1
 `
 
@@ -288,12 +440,15 @@ func (q *Queries) GetAssetById(ctx context.Context, id int64) (Asset, error) {
 		&i.IsDeleted,
 		&i.DeletedAt,
 		&i.IsHidden,
+		&i.PerceptualHash,
+		&i.MaterialChannel,
+		&i.AnalysisVersion,
 	)
 	return i, err
 }
 
 const getAssetByPath = `-- name: GetAssetByPath :one
-SELECT id, scan_folder_id, group_id, file_name, file_path, file_type, file_size, thumbnail_path, rating, description, is_favorite, image_width, image_height, dominant_color, bit_depth, has_alpha_channel, date_added, last_scanned, last_modified, file_hash, is_deleted, deleted_at, is_hidden FROM assets
+SELECT id, scan_folder_id, group_id, file_name, file_path, file_type, file_size, thumbnail_path, rating, description, is_favorite, image_width, image_height, dominant_color, bit_depth, has_alpha_channel, date_added, last_scanned, last_modified, file_hash, is_deleted, deleted_at, is_hidden, perceptual_hash, material_channel, analysis_version FROM assets
 WHERE file_path = ? LIMIT 1
 `
 
@@ -324,6 +479,9 @@ func (q *Queries) GetAssetByPath(ctx context.Context, filePath string) (Asset, e
 		&i.IsDeleted,
 		&i.DeletedAt,
 		&i.IsHidden,
+		&i.PerceptualHash,
+		&i.MaterialChannel,
+		&i.AnalysisVersion,
 	)
 	return i, err
 }
@@ -444,7 +602,7 @@ func (q *Queries) GetSidebarStats(ctx context.Context) (GetSidebarStatsRow, erro
 }
 
 const listAssets = `-- name: ListAssets :many
-SELECT a.id, a.scan_folder_id, a.group_id, a.file_name, a.file_path, a.file_type, a.file_size, a.thumbnail_path, a.rating, a.description, a.is_favorite, a.image_width, a.image_height, a.dominant_color, a.bit_depth, a.has_alpha_channel, a.date_added, a.last_scanned, a.last_modified, a.file_hash, a.is_deleted, a.deleted_at, a.is_hidden FROM assets a
+SELECT a.id, a.scan_folder_id, a.group_id, a.file_name, a.file_path, a.file_type, a.file_size, a.thumbnail_path, a.rating, a.description, a.is_favorite, a.image_width, a.image_height, a.dominant_color, a.bit_depth, a.has_alpha_channel, a.date_added, a.last_scanned, a.last_modified, a.file_hash, a.is_deleted, a.deleted_at, a.is_hidden, a.perceptual_hash, a.material_channel, a.analysis_version FROM assets a
 JOIN scan_folders f ON a.scan_folder_id = f.id
 WHERE a.is_deleted = 0
   AND f.is_deleted = 0
@@ -492,6 +650,124 @@ func (q *Queries) ListAssets(ctx context.Context, arg ListAssetsParams) ([]Asset
 			&i.IsDeleted,
 			&i.DeletedAt,
 			&i.IsHidden,
+			&i.PerceptualHash,
+			&i.MaterialChannel,
+			&i.AnalysisVersion,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listAssetsByHash = `-- name: ListAssetsByHash :many
+SELECT id, file_name, file_path, file_size, file_hash, group_id, thumbnail_path, date_added
+FROM assets
+WHERE file_hash = ? AND is_deleted = 0 AND is_hidden = 0
+ORDER BY date_added, id
+`
+
+type ListAssetsByHashRow struct {
+	ID            int64          `json:"id"`
+	FileName      string         `json:"fileName"`
+	FilePath      string         `json:"filePath"`
+	FileSize      int64          `json:"fileSize"`
+	FileHash      sql.NullString `json:"fileHash"`
+	GroupID       string         `json:"groupId"`
+	ThumbnailPath string         `json:"thumbnailPath"`
+	DateAdded     time.Time      `json:"dateAdded"`
+}
+
+func (q *Queries) ListAssetsByHash(ctx context.Context, fileHash sql.NullString) ([]ListAssetsByHashRow, error) {
+	rows, err := q.query(ctx, q.listAssetsByHashStmt, listAssetsByHash, fileHash)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ListAssetsByHashRow
+	for rows.Next() {
+		var i ListAssetsByHashRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.FileName,
+			&i.FilePath,
+			&i.FileSize,
+			&i.FileHash,
+			&i.GroupID,
+			&i.ThumbnailPath,
+			&i.DateAdded,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listAssetsByIDs = `-- name: ListAssetsByIDs :many
+SELECT id, scan_folder_id, group_id, file_name, file_path, file_type, file_size, thumbnail_path, rating, description, is_favorite, image_width, image_height, dominant_color, bit_depth, has_alpha_channel, date_added, last_scanned, last_modified, file_hash, is_deleted, deleted_at, is_hidden, perceptual_hash, material_channel, analysis_version FROM assets
+WHERE id IN (/*SLICE:ids*/?)
+`
+
+func (q *Queries) ListAssetsByIDs(ctx context.Context, ids []int64) ([]Asset, error) {
+	query := listAssetsByIDs
+	var queryParams []interface{}
+	if len(ids) > 0 {
+		for _, v := range ids {
+			queryParams = append(queryParams, v)
+		}
+		query = strings.Replace(query, "/*SLICE:ids*/?", strings.Repeat(",?", len(ids))[1:], 1)
+	} else {
+		query = strings.Replace(query, "/*SLICE:ids*/?", "NULL", 1)
+	}
+	rows, err := q.query(ctx, nil, query, queryParams...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []Asset
+	for rows.Next() {
+		var i Asset
+		if err := rows.Scan(
+			&i.ID,
+			&i.ScanFolderID,
+			&i.GroupID,
+			&i.FileName,
+			&i.FilePath,
+			&i.FileType,
+			&i.FileSize,
+			&i.ThumbnailPath,
+			&i.Rating,
+			&i.Description,
+			&i.IsFavorite,
+			&i.ImageWidth,
+			&i.ImageHeight,
+			&i.DominantColor,
+			&i.BitDepth,
+			&i.HasAlphaChannel,
+			&i.DateAdded,
+			&i.LastScanned,
+			&i.LastModified,
+			&i.FileHash,
+			&i.IsDeleted,
+			&i.DeletedAt,
+			&i.IsHidden,
+			&i.PerceptualHash,
+			&i.MaterialChannel,
+			&i.AnalysisVersion,
 		); err != nil {
 			return nil, err
 		}
@@ -507,15 +783,18 @@ func (q *Queries) ListAssets(ctx context.Context, arg ListAssetsParams) ([]Asset
 }
 
 const listAssetsForCache = `-- name: ListAssetsForCache :many
-SELECT id,file_path,last_modified,is_deleted,scan_folder_id FROM assets
+SELECT id,file_path,file_size,last_modified,is_deleted,scan_folder_id,analysis_version
+FROM assets
 `
 
 type ListAssetsForCacheRow struct {
-	ID           int64         `json:"id"`
-	FilePath     string        `json:"filePath"`
-	LastModified time.Time     `json:"lastModified"`
-	IsDeleted    bool          `json:"isDeleted"`
-	ScanFolderID sql.NullInt64 `json:"scanFolderId"`
+	ID              int64         `json:"id"`
+	FilePath        string        `json:"filePath"`
+	FileSize        int64         `json:"fileSize"`
+	LastModified    time.Time     `json:"lastModified"`
+	IsDeleted       bool          `json:"isDeleted"`
+	ScanFolderID    sql.NullInt64 `json:"scanFolderId"`
+	AnalysisVersion int64         `json:"analysisVersion"`
 }
 
 func (q *Queries) ListAssetsForCache(ctx context.Context) ([]ListAssetsForCacheRow, error) {
@@ -530,9 +809,55 @@ func (q *Queries) ListAssetsForCache(ctx context.Context) ([]ListAssetsForCacheR
 		if err := rows.Scan(
 			&i.ID,
 			&i.FilePath,
+			&i.FileSize,
 			&i.LastModified,
 			&i.IsDeleted,
 			&i.ScanFolderID,
+			&i.AnalysisVersion,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listAssetsForGrouping = `-- name: ListAssetsForGrouping :many
+SELECT id, scan_folder_id, group_id, file_name, file_hash
+FROM assets
+WHERE is_deleted = 0
+ORDER BY id
+`
+
+type ListAssetsForGroupingRow struct {
+	ID           int64          `json:"id"`
+	ScanFolderID sql.NullInt64  `json:"scanFolderId"`
+	GroupID      string         `json:"groupId"`
+	FileName     string         `json:"fileName"`
+	FileHash     sql.NullString `json:"fileHash"`
+}
+
+func (q *Queries) ListAssetsForGrouping(ctx context.Context) ([]ListAssetsForGroupingRow, error) {
+	rows, err := q.query(ctx, q.listAssetsForGroupingStmt, listAssetsForGrouping)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ListAssetsForGroupingRow
+	for rows.Next() {
+		var i ListAssetsForGroupingRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.ScanFolderID,
+			&i.GroupID,
+			&i.FileName,
+			&i.FileHash,
 		); err != nil {
 			return nil, err
 		}
@@ -548,7 +873,7 @@ func (q *Queries) ListAssetsForCache(ctx context.Context) ([]ListAssetsForCacheR
 }
 
 const listDeletedAssets = `-- name: ListDeletedAssets :many
-SELECT id, scan_folder_id, group_id, file_name, file_path, file_type, file_size, thumbnail_path, rating, description, is_favorite, image_width, image_height, dominant_color, bit_depth, has_alpha_channel, date_added, last_scanned, last_modified, file_hash, is_deleted, deleted_at, is_hidden FROM assets
+SELECT id, scan_folder_id, group_id, file_name, file_path, file_type, file_size, thumbnail_path, rating, description, is_favorite, image_width, image_height, dominant_color, bit_depth, has_alpha_channel, date_added, last_scanned, last_modified, file_hash, is_deleted, deleted_at, is_hidden, perceptual_hash, material_channel, analysis_version FROM assets
 WHERE is_deleted = 1 AND is_hidden = 0
 ORDER BY deleted_at DESC
 LIMIT ? OFFSET ?
@@ -592,6 +917,64 @@ func (q *Queries) ListDeletedAssets(ctx context.Context, arg ListDeletedAssetsPa
 			&i.IsDeleted,
 			&i.DeletedAt,
 			&i.IsHidden,
+			&i.PerceptualHash,
+			&i.MaterialChannel,
+			&i.AnalysisVersion,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listDuplicateAssets = `-- name: ListDuplicateAssets :many
+SELECT id, file_name, file_path, file_size, file_hash, group_id, thumbnail_path, date_added
+FROM assets
+WHERE is_deleted = 0 AND is_hidden = 0 AND file_hash IN (
+    SELECT file_hash FROM assets
+    WHERE is_deleted = 0 AND is_hidden = 0 AND file_hash IS NOT NULL AND file_hash != ''
+    GROUP BY file_hash
+    HAVING COUNT(*) > 1
+)
+ORDER BY file_hash, date_added, id
+`
+
+type ListDuplicateAssetsRow struct {
+	ID            int64          `json:"id"`
+	FileName      string         `json:"fileName"`
+	FilePath      string         `json:"filePath"`
+	FileSize      int64          `json:"fileSize"`
+	FileHash      sql.NullString `json:"fileHash"`
+	GroupID       string         `json:"groupId"`
+	ThumbnailPath string         `json:"thumbnailPath"`
+	DateAdded     time.Time      `json:"dateAdded"`
+}
+
+func (q *Queries) ListDuplicateAssets(ctx context.Context) ([]ListDuplicateAssetsRow, error) {
+	rows, err := q.query(ctx, q.listDuplicateAssetsStmt, listDuplicateAssets)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ListDuplicateAssetsRow
+	for rows.Next() {
+		var i ListDuplicateAssetsRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.FileName,
+			&i.FilePath,
+			&i.FileSize,
+			&i.FileHash,
+			&i.GroupID,
+			&i.ThumbnailPath,
+			&i.DateAdded,
 		); err != nil {
 			return nil, err
 		}
@@ -606,8 +989,37 @@ func (q *Queries) ListDeletedAssets(ctx context.Context, arg ListDeletedAssetsPa
 	return items, nil
 }
 
+const listExpiredDeletedAssetIDs = `-- name: ListExpiredDeletedAssetIDs :many
+SELECT id FROM assets
+WHERE is_deleted = 1 AND deleted_at < datetime('now', '-7 days')
+ORDER BY id
+`
+
+func (q *Queries) ListExpiredDeletedAssetIDs(ctx context.Context) ([]int64, error) {
+	rows, err := q.query(ctx, q.listExpiredDeletedAssetIDsStmt, listExpiredDeletedAssetIDs)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []int64
+	for rows.Next() {
+		var id int64
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		items = append(items, id)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const listFavoriteAssets = `-- name: ListFavoriteAssets :many
-SELECT a.id, a.scan_folder_id, a.group_id, a.file_name, a.file_path, a.file_type, a.file_size, a.thumbnail_path, a.rating, a.description, a.is_favorite, a.image_width, a.image_height, a.dominant_color, a.bit_depth, a.has_alpha_channel, a.date_added, a.last_scanned, a.last_modified, a.file_hash, a.is_deleted, a.deleted_at, a.is_hidden FROM assets a
+SELECT a.id, a.scan_folder_id, a.group_id, a.file_name, a.file_path, a.file_type, a.file_size, a.thumbnail_path, a.rating, a.description, a.is_favorite, a.image_width, a.image_height, a.dominant_color, a.bit_depth, a.has_alpha_channel, a.date_added, a.last_scanned, a.last_modified, a.file_hash, a.is_deleted, a.deleted_at, a.is_hidden, a.perceptual_hash, a.material_channel, a.analysis_version FROM assets a
 JOIN scan_folders f ON a.scan_folder_id = f.id
 WHERE a.is_favorite = 1
   AND a.is_deleted = 0
@@ -656,6 +1068,53 @@ func (q *Queries) ListFavoriteAssets(ctx context.Context, arg ListFavoriteAssets
 			&i.IsDeleted,
 			&i.DeletedAt,
 			&i.IsHidden,
+			&i.PerceptualHash,
+			&i.MaterialChannel,
+			&i.AnalysisVersion,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listGroupMaterialChannels = `-- name: ListGroupMaterialChannels :many
+SELECT id, file_name, file_path, thumbnail_path, material_channel
+FROM assets
+WHERE group_id = ? AND material_channel != '' AND is_deleted = 0
+ORDER BY file_name
+`
+
+type ListGroupMaterialChannelsRow struct {
+	ID              int64  `json:"id"`
+	FileName        string `json:"fileName"`
+	FilePath        string `json:"filePath"`
+	ThumbnailPath   string `json:"thumbnailPath"`
+	MaterialChannel string `json:"materialChannel"`
+}
+
+func (q *Queries) ListGroupMaterialChannels(ctx context.Context, groupID string) ([]ListGroupMaterialChannelsRow, error) {
+	rows, err := q.query(ctx, q.listGroupMaterialChannelsStmt, listGroupMaterialChannels, groupID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ListGroupMaterialChannelsRow
+	for rows.Next() {
+		var i ListGroupMaterialChannelsRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.FileName,
+			&i.FilePath,
+			&i.ThumbnailPath,
+			&i.MaterialChannel,
 		); err != nil {
 			return nil, err
 		}
@@ -671,7 +1130,7 @@ func (q *Queries) ListFavoriteAssets(ctx context.Context, arg ListFavoriteAssets
 }
 
 const listHiddenAssets = `-- name: ListHiddenAssets :many
-SELECT id, scan_folder_id, group_id, file_name, file_path, file_type, file_size, thumbnail_path, rating, description, is_favorite, image_width, image_height, dominant_color, bit_depth, has_alpha_channel, date_added, last_scanned, last_modified, file_hash, is_deleted, deleted_at, is_hidden FROM assets
+SELECT id, scan_folder_id, group_id, file_name, file_path, file_type, file_size, thumbnail_path, rating, description, is_favorite, image_width, image_height, dominant_color, bit_depth, has_alpha_channel, date_added, last_scanned, last_modified, file_hash, is_deleted, deleted_at, is_hidden, perceptual_hash, material_channel, analysis_version FROM assets
 WHERE is_hidden = 1 AND is_deleted = 0
 ORDER BY deleted_at DESC
 LIMIT ? OFFSET ?
@@ -715,6 +1174,98 @@ func (q *Queries) ListHiddenAssets(ctx context.Context, arg ListHiddenAssetsPara
 			&i.IsDeleted,
 			&i.DeletedAt,
 			&i.IsHidden,
+			&i.PerceptualHash,
+			&i.MaterialChannel,
+			&i.AnalysisVersion,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listLegacyDominantColors = `-- name: ListLegacyDominantColors :many
+SELECT a.id, a.dominant_color
+FROM assets a
+WHERE a.dominant_color IS NOT NULL AND a.dominant_color != ''
+  AND NOT EXISTS (SELECT 1 FROM asset_colors ac WHERE ac.asset_id = a.id)
+`
+
+type ListLegacyDominantColorsRow struct {
+	ID            int64          `json:"id"`
+	DominantColor sql.NullString `json:"dominantColor"`
+}
+
+func (q *Queries) ListLegacyDominantColors(ctx context.Context) ([]ListLegacyDominantColorsRow, error) {
+	rows, err := q.query(ctx, q.listLegacyDominantColorsStmt, listLegacyDominantColors)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ListLegacyDominantColorsRow
+	for rows.Next() {
+		var i ListLegacyDominantColorsRow
+		if err := rows.Scan(&i.ID, &i.DominantColor); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const listPerceptualHashes = `-- name: ListPerceptualHashes :many
+SELECT a.id, a.file_name, a.file_path, a.thumbnail_path, a.file_size, a.image_width, a.image_height, a.perceptual_hash
+FROM assets a
+JOIN scan_folders f ON a.scan_folder_id = f.id
+WHERE a.perceptual_hash IS NOT NULL
+  AND a.is_deleted = 0
+  AND a.is_hidden = 0
+  AND f.is_deleted = 0
+  AND f.is_active = 1
+`
+
+type ListPerceptualHashesRow struct {
+	ID             int64          `json:"id"`
+	FileName       string         `json:"fileName"`
+	FilePath       string         `json:"filePath"`
+	ThumbnailPath  string         `json:"thumbnailPath"`
+	FileSize       int64          `json:"fileSize"`
+	ImageWidth     sql.NullInt64  `json:"imageWidth"`
+	ImageHeight    sql.NullInt64  `json:"imageHeight"`
+	PerceptualHash sql.NullString `json:"perceptualHash"`
+}
+
+func (q *Queries) ListPerceptualHashes(ctx context.Context) ([]ListPerceptualHashesRow, error) {
+	rows, err := q.query(ctx, q.listPerceptualHashesStmt, listPerceptualHashes)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []ListPerceptualHashesRow
+	for rows.Next() {
+		var i ListPerceptualHashesRow
+		if err := rows.Scan(
+			&i.ID,
+			&i.FileName,
+			&i.FilePath,
+			&i.ThumbnailPath,
+			&i.FileSize,
+			&i.ImageWidth,
+			&i.ImageHeight,
+			&i.PerceptualHash,
 		); err != nil {
 			return nil, err
 		}
@@ -730,7 +1281,7 @@ func (q *Queries) ListHiddenAssets(ctx context.Context, arg ListHiddenAssetsPara
 }
 
 const listUntaggedAssets = `-- name: ListUntaggedAssets :many
-SELECT a.id, a.scan_folder_id, a.group_id, a.file_name, a.file_path, a.file_type, a.file_size, a.thumbnail_path, a.rating, a.description, a.is_favorite, a.image_width, a.image_height, a.dominant_color, a.bit_depth, a.has_alpha_channel, a.date_added, a.last_scanned, a.last_modified, a.file_hash, a.is_deleted, a.deleted_at, a.is_hidden FROM assets a
+SELECT a.id, a.scan_folder_id, a.group_id, a.file_name, a.file_path, a.file_type, a.file_size, a.thumbnail_path, a.rating, a.description, a.is_favorite, a.image_width, a.image_height, a.dominant_color, a.bit_depth, a.has_alpha_channel, a.date_added, a.last_scanned, a.last_modified, a.file_hash, a.is_deleted, a.deleted_at, a.is_hidden, a.perceptual_hash, a.material_channel, a.analysis_version FROM assets a
 LEFT JOIN asset_tags at ON a.id = at.asset_id
 JOIN scan_folders f ON a.scan_folder_id = f.id
 WHERE at.tag_id IS NULL
@@ -780,6 +1331,9 @@ func (q *Queries) ListUntaggedAssets(ctx context.Context, arg ListUntaggedAssets
 			&i.IsDeleted,
 			&i.DeletedAt,
 			&i.IsHidden,
+			&i.PerceptualHash,
+			&i.MaterialChannel,
+			&i.AnalysisVersion,
 		); err != nil {
 			return nil, err
 		}
@@ -808,6 +1362,20 @@ func (q *Queries) MoveAssetsToFolder(ctx context.Context, arg MoveAssetsToFolder
 	return err
 }
 
+const reassignAssetGroup = `-- name: ReassignAssetGroup :exec
+UPDATE assets SET group_id = ? WHERE group_id = ?
+`
+
+type ReassignAssetGroupParams struct {
+	NewGroupID string `json:"newGroupId"`
+	OldGroupID string `json:"oldGroupId"`
+}
+
+func (q *Queries) ReassignAssetGroup(ctx context.Context, arg ReassignAssetGroupParams) error {
+	_, err := q.exec(ctx, q.reassignAssetGroupStmt, reassignAssetGroup, arg.NewGroupID, arg.OldGroupID)
+	return err
+}
+
 const refreshAssetTechnicalMetadata = `-- name: RefreshAssetTechnicalMetadata :exec
 UPDATE assets
 SET
@@ -856,7 +1424,7 @@ const renameAsset = `-- name: RenameAsset :one
 UPDATE assets
 SET file_name = ?, file_path = ?
 WHERE id = ?
-RETURNING id, scan_folder_id, group_id, file_name, file_path, file_type, file_size, thumbnail_path, rating, description, is_favorite, image_width, image_height, dominant_color, bit_depth, has_alpha_channel, date_added, last_scanned, last_modified, file_hash, is_deleted, deleted_at, is_hidden
+RETURNING id, scan_folder_id, group_id, file_name, file_path, file_type, file_size, thumbnail_path, rating, description, is_favorite, image_width, image_height, dominant_color, bit_depth, has_alpha_channel, date_added, last_scanned, last_modified, file_hash, is_deleted, deleted_at, is_hidden, perceptual_hash, material_channel, analysis_version
 `
 
 type RenameAssetParams struct {
@@ -892,6 +1460,9 @@ func (q *Queries) RenameAsset(ctx context.Context, arg RenameAssetParams) (Asset
 		&i.IsDeleted,
 		&i.DeletedAt,
 		&i.IsHidden,
+		&i.PerceptualHash,
+		&i.MaterialChannel,
+		&i.AnalysisVersion,
 	)
 	return i, err
 }
@@ -1013,6 +1584,20 @@ func (q *Queries) ToggleAssetFavorite(ctx context.Context, id int64) error {
 	return err
 }
 
+const updateAssetDominantColor = `-- name: UpdateAssetDominantColor :exec
+UPDATE assets SET dominant_color = ? WHERE id = ?
+`
+
+type UpdateAssetDominantColorParams struct {
+	DominantColor sql.NullString `json:"dominantColor"`
+	ID            int64          `json:"id"`
+}
+
+func (q *Queries) UpdateAssetDominantColor(ctx context.Context, arg UpdateAssetDominantColorParams) error {
+	_, err := q.exec(ctx, q.updateAssetDominantColorStmt, updateAssetDominantColor, arg.DominantColor, arg.ID)
+	return err
+}
+
 const updateAssetFromScan = `-- name: UpdateAssetFromScan :one
 UPDATE assets
 SET
@@ -1033,9 +1618,11 @@ SET
     image_height = COALESCE(?10, image_height),
     dominant_color = COALESCE(?11, dominant_color),
     bit_depth = COALESCE(?12, bit_depth),
-    has_alpha_channel = COALESCE(?13, has_alpha_channel)
-WHERE id = ?14
-RETURNING id, scan_folder_id, group_id, file_name, file_path, file_type, file_size, thumbnail_path, rating, description, is_favorite, image_width, image_height, dominant_color, bit_depth, has_alpha_channel, date_added, last_scanned, last_modified, file_hash, is_deleted, deleted_at, is_hidden
+    has_alpha_channel = COALESCE(?13, has_alpha_channel),
+    material_channel = COALESCE(?14, material_channel),
+    analysis_version = COALESCE(?15, analysis_version)
+WHERE id = ?16
+RETURNING id, scan_folder_id, group_id, file_name, file_path, file_type, file_size, thumbnail_path, rating, description, is_favorite, image_width, image_height, dominant_color, bit_depth, has_alpha_channel, date_added, last_scanned, last_modified, file_hash, is_deleted, deleted_at, is_hidden, perceptual_hash, material_channel, analysis_version
 `
 
 type UpdateAssetFromScanParams struct {
@@ -1052,6 +1639,8 @@ type UpdateAssetFromScanParams struct {
 	DominantColor   sql.NullString `json:"dominantColor"`
 	BitDepth        sql.NullInt64  `json:"bitDepth"`
 	HasAlphaChannel sql.NullBool   `json:"hasAlphaChannel"`
+	MaterialChannel sql.NullString `json:"materialChannel"`
+	AnalysisVersion sql.NullInt64  `json:"analysisVersion"`
 	ID              int64          `json:"id"`
 }
 
@@ -1070,6 +1659,8 @@ func (q *Queries) UpdateAssetFromScan(ctx context.Context, arg UpdateAssetFromSc
 		arg.DominantColor,
 		arg.BitDepth,
 		arg.HasAlphaChannel,
+		arg.MaterialChannel,
+		arg.AnalysisVersion,
 		arg.ID,
 	)
 	var i Asset
@@ -1097,10 +1688,27 @@ func (q *Queries) UpdateAssetFromScan(ctx context.Context, arg UpdateAssetFromSc
 		&i.IsDeleted,
 		&i.DeletedAt,
 		&i.IsHidden,
+		&i.PerceptualHash,
+		&i.MaterialChannel,
+		&i.AnalysisVersion,
 	)
 	return i, err
 }
 
+const updateAssetGroup = `-- name: UpdateAssetGroup :exec
+UPDATE assets SET group_id = ? WHERE id = ?
+`
+
+type UpdateAssetGroupParams struct {
+	GroupID string `json:"groupId"`
+	ID      int64  `json:"id"`
+}
+
+func (q *Queries) UpdateAssetGroup(ctx context.Context, arg UpdateAssetGroupParams) error {
+	_, err := q.exec(ctx, q.updateAssetGroupStmt, updateAssetGroup, arg.GroupID, arg.ID)
+	return err
+}
+
 const updateAssetLocation = `-- name: UpdateAssetLocation :exec
 UPDATE assets
 SET file_path = ?, scan_folder_id = ?, is_deleted = false, last_scanned = ?
@@ -1132,7 +1740,7 @@ SET
     is_favorite = COALESCE(?3, is_favorite),
     thumbnail_path = COALESCE(?4, thumbnail_path)
 WHERE id = ?5
-RETURNING id, scan_folder_id, group_id, file_name, file_path, file_type, file_size, thumbnail_path, rating, description, is_favorite, image_width, image_height, dominant_color, bit_depth, has_alpha_channel, date_added, last_scanned, last_modified, file_hash, is_deleted, deleted_at, is_hidden
+RETURNING id, scan_folder_id, group_id, file_name, file_path, file_type, file_size, thumbnail_path, rating, description, is_favorite, image_width, image_height, dominant_color, bit_depth, has_alpha_channel, date_added, last_scanned, last_modified, file_hash, is_deleted, deleted_at, is_hidden, perceptual_hash, material_channel, analysis_version
 `
 
 type UpdateAssetMetadataParams struct {
@@ -1176,10 +1784,27 @@ func (q *Queries) UpdateAssetMetadata(ctx context.Context, arg UpdateAssetMetada
 		&i.IsDeleted,
 		&i.DeletedAt,
 		&i.IsHidden,
+		&i.PerceptualHash,
+		&i.MaterialChannel,
+		&i.AnalysisVersion,
 	)
 	return i, err
 }
 
+const updateAssetPerceptualHash = `-- name: UpdateAssetPerceptualHash :exec
+UPDATE assets SET perceptual_hash = ? WHERE id = ?
+`
+
+type UpdateAssetPerceptualHashParams struct {
+	PerceptualHash sql.NullString `json:"perceptualHash"`
+	ID             int64          `json:"id"`
+}
+
+func (q *Queries) UpdateAssetPerceptualHash(ctx context.Context, arg UpdateAssetPerceptualHashParams) error {
+	_, err := q.exec(ctx, q.updateAssetPerceptualHashStmt, updateAssetPerceptualHash, arg.PerceptualHash, arg.ID)
+	return err
+}
+
 const updateAssetScanStatus = `-- name: UpdateAssetScanStatus :exec
 UPDATE assets
 SET last_scanned = ?, file_size = ?, last_modified = ?
@@ -24,6 +24,9 @@ func New(db DBTX) *Queries {
 func Prepare(ctx context.Context, db DBTX) (*Queries, error) {
 	q := Queries{db: db}
 	var err error
+	if q.activateColorPaletteStmt, err = db.PrepareContext(ctx, activateColorPalette); err != nil {
+		return nil, fmt.Errorf("error preparing query ActivateColorPalette: %w", err)
+	}
 	if q.addAssetToMaterialSetStmt, err = db.PrepareContext(ctx, addAssetToMaterialSet); err != nil {
 		return nil, fmt.Errorf("error preparing query AddAssetToMaterialSet: %w", err)
 	}
@@ -33,15 +36,39 @@ func Prepare(ctx context.Context, db DBTX) (*Queries, error) {
 	if q.claimAssetsForPathStmt, err = db.PrepareContext(ctx, claimAssetsForPath); err != nil {
 		return nil, fmt.Errorf("error preparing query ClaimAssetsForPath: %w", err)
 	}
-	if q.cleanupOldDeletedAssetsStmt, err = db.PrepareContext(ctx, cleanupOldDeletedAssets); err != nil {
-		return nil, fmt.Errorf("error preparing query CleanupOldDeletedAssets: %w", err)
+	if q.clearJournalStmt, err = db.PrepareContext(ctx, clearJournal); err != nil {
+		return nil, fmt.Errorf("error preparing query ClearJournal: %w", err)
 	}
 	if q.clearTagsForAssetStmt, err = db.PrepareContext(ctx, clearTagsForAsset); err != nil {
 		return nil, fmt.Errorf("error preparing query ClearTagsForAsset: %w", err)
 	}
+	if q.copyAssetColorsStmt, err = db.PrepareContext(ctx, copyAssetColors); err != nil {
+		return nil, fmt.Errorf("error preparing query CopyAssetColors: %w", err)
+	}
+	if q.copyAssetFeaturesStmt, err = db.PrepareContext(ctx, copyAssetFeatures); err != nil {
+		return nil, fmt.Errorf("error preparing query CopyAssetFeatures: %w", err)
+	}
+	if q.copyAssetMaterialSetsStmt, err = db.PrepareContext(ctx, copyAssetMaterialSets); err != nil {
+		return nil, fmt.Errorf("error preparing query CopyAssetMaterialSets: %w", err)
+	}
+	if q.copyAssetPropertiesStmt, err = db.PrepareContext(ctx, copyAssetProperties); err != nil {
+		return nil, fmt.Errorf("error preparing query CopyAssetProperties: %w", err)
+	}
+	if q.copyAssetTagsStmt, err = db.PrepareContext(ctx, copyAssetTags); err != nil {
+		return nil, fmt.Errorf("error preparing query CopyAssetTags: %w", err)
+	}
+	if q.copyModelMetadataStmt, err = db.PrepareContext(ctx, copyModelMetadata); err != nil {
+		return nil, fmt.Errorf("error preparing query CopyModelMetadata: %w", err)
+	}
 	if q.createAssetStmt, err = db.PrepareContext(ctx, createAsset); err != nil {
 		return nil, fmt.Errorf("error preparing query CreateAsset: %w", err)
 	}
+	if q.createColorPaletteStmt, err = db.PrepareContext(ctx, createColorPalette); err != nil {
+		return nil, fmt.Errorf("error preparing query CreateColorPalette: %w", err)
+	}
+	if q.createJournalEntryStmt, err = db.PrepareContext(ctx, createJournalEntry); err != nil {
+		return nil, fmt.Errorf("error preparing query CreateJournalEntry: %w", err)
+	}
 	if q.createMaterialSetStmt, err = db.PrepareContext(ctx, createMaterialSet); err != nil {
 		return nil, fmt.Errorf("error preparing query CreateMaterialSet: %w", err)
 	}
@@ -54,21 +81,60 @@ func Prepare(ctx context.Context, db DBTX) (*Queries, error) {
 	if q.createTagStmt, err = db.PrepareContext(ctx, createTag); err != nil {
 		return nil, fmt.Errorf("error preparing query CreateTag: %w", err)
 	}
+	if q.createTrashedAssetStmt, err = db.PrepareContext(ctx, createTrashedAsset); err != nil {
+		return nil, fmt.Errorf("error preparing query CreateTrashedAsset: %w", err)
+	}
+	if q.deactivateColorPalettesStmt, err = db.PrepareContext(ctx, deactivateColorPalettes); err != nil {
+		return nil, fmt.Errorf("error preparing query DeactivateColorPalettes: %w", err)
+	}
 	if q.deleteAssetByFolderStmt, err = db.PrepareContext(ctx, deleteAssetByFolder); err != nil {
 		return nil, fmt.Errorf("error preparing query DeleteAssetByFolder: %w", err)
 	}
+	if q.deleteAssetColorsStmt, err = db.PrepareContext(ctx, deleteAssetColors); err != nil {
+		return nil, fmt.Errorf("error preparing query DeleteAssetColors: %w", err)
+	}
+	if q.deleteAssetFeaturesStmt, err = db.PrepareContext(ctx, deleteAssetFeatures); err != nil {
+		return nil, fmt.Errorf("error preparing query DeleteAssetFeatures: %w", err)
+	}
+	if q.deleteAssetGroupStmt, err = db.PrepareContext(ctx, deleteAssetGroup); err != nil {
+		return nil, fmt.Errorf("error preparing query DeleteAssetGroup: %w", err)
+	}
 	if q.deleteAssetPermanentStmt, err = db.PrepareContext(ctx, deleteAssetPermanent); err != nil {
 		return nil, fmt.Errorf("error preparing query DeleteAssetPermanent: %w", err)
 	}
+	if q.deleteAssetPropertiesStmt, err = db.PrepareContext(ctx, deleteAssetProperties); err != nil {
+		return nil, fmt.Errorf("error preparing query DeleteAssetProperties: %w", err)
+	}
+	if q.deleteAssetSearchIndexStmt, err = db.PrepareContext(ctx, deleteAssetSearchIndex); err != nil {
+		return nil, fmt.Errorf("error preparing query DeleteAssetSearchIndex: %w", err)
+	}
+	if q.deleteColorPaletteStmt, err = db.PrepareContext(ctx, deleteColorPalette); err != nil {
+		return nil, fmt.Errorf("error preparing query DeleteColorPalette: %w", err)
+	}
 	if q.deleteMaterialSetStmt, err = db.PrepareContext(ctx, deleteMaterialSet); err != nil {
 		return nil, fmt.Errorf("error preparing query DeleteMaterialSet: %w", err)
 	}
+	if q.deleteModelMetadataStmt, err = db.PrepareContext(ctx, deleteModelMetadata); err != nil {
+		return nil, fmt.Errorf("error preparing query DeleteModelMetadata: %w", err)
+	}
 	if q.deleteSavedSearchStmt, err = db.PrepareContext(ctx, deleteSavedSearch); err != nil {
 		return nil, fmt.Errorf("error preparing query DeleteSavedSearch: %w", err)
 	}
+	if q.deleteTrashedAssetStmt, err = db.PrepareContext(ctx, deleteTrashedAsset); err != nil {
+		return nil, fmt.Errorf("error preparing query DeleteTrashedAsset: %w", err)
+	}
+	if q.deleteUndoneJournalEntriesStmt, err = db.PrepareContext(ctx, deleteUndoneJournalEntries); err != nil {
+		return nil, fmt.Errorf("error preparing query DeleteUndoneJournalEntries: %w", err)
+	}
 	if q.findPotentialSiblingsStmt, err = db.PrepareContext(ctx, findPotentialSiblings); err != nil {
 		return nil, fmt.Errorf("error preparing query FindPotentialSiblings: %w", err)
 	}
+	if q.findPotentialSiblingsInLibraryStmt, err = db.PrepareContext(ctx, findPotentialSiblingsInLibrary); err != nil {
+		return nil, fmt.Errorf("error preparing query FindPotentialSiblingsInLibrary: %w", err)
+	}
+	if q.getActiveColorPaletteStmt, err = db.PrepareContext(ctx, getActiveColorPalette); err != nil {
+		return nil, fmt.Errorf("error preparing query GetActiveColorPalette: %w", err)
+	}
 	if q.getAllColorsStmt, err = db.PrepareContext(ctx, getAllColors); err != nil {
 		return nil, fmt.Errorf("error preparing query GetAllColors: %w", err)
 	}
@@ -84,15 +150,42 @@ func Prepare(ctx context.Context, db DBTX) (*Queries, error) {
 	if q.getAssetByPathStmt, err = db.PrepareContext(ctx, getAssetByPath); err != nil {
 		return nil, fmt.Errorf("error preparing query GetAssetByPath: %w", err)
 	}
+	if q.getAssetFeaturesStmt, err = db.PrepareContext(ctx, getAssetFeatures); err != nil {
+		return nil, fmt.Errorf("error preparing query GetAssetFeatures: %w", err)
+	}
+	if q.getAssetGroupStmt, err = db.PrepareContext(ctx, getAssetGroup); err != nil {
+		return nil, fmt.Errorf("error preparing query GetAssetGroup: %w", err)
+	}
 	if q.getAssetsByGroupIDStmt, err = db.PrepareContext(ctx, getAssetsByGroupID); err != nil {
 		return nil, fmt.Errorf("error preparing query GetAssetsByGroupID: %w", err)
 	}
+	if q.getColorPaletteByIdStmt, err = db.PrepareContext(ctx, getColorPaletteById); err != nil {
+		return nil, fmt.Errorf("error preparing query GetColorPaletteById: %w", err)
+	}
+	if q.getColorPaletteByNameStmt, err = db.PrepareContext(ctx, getColorPaletteByName); err != nil {
+		return nil, fmt.Errorf("error preparing query GetColorPaletteByName: %w", err)
+	}
+	if q.getFirstUndoneJournalEntryStmt, err = db.PrepareContext(ctx, getFirstUndoneJournalEntry); err != nil {
+		return nil, fmt.Errorf("error preparing query GetFirstUndoneJournalEntry: %w", err)
+	}
+	if q.getLastActiveJournalEntryStmt, err = db.PrepareContext(ctx, getLastActiveJournalEntry); err != nil {
+		return nil, fmt.Errorf("error preparing query GetLastActiveJournalEntry: %w", err)
+	}
 	if q.getLibraryStatsStmt, err = db.PrepareContext(ctx, getLibraryStats); err != nil {
 		return nil, fmt.Errorf("error preparing query GetLibraryStats: %w", err)
 	}
 	if q.getMaterialSetByIdStmt, err = db.PrepareContext(ctx, getMaterialSetById); err != nil {
 		return nil, fmt.Errorf("error preparing query GetMaterialSetById: %w", err)
 	}
+	if q.getModelMetadataStmt, err = db.PrepareContext(ctx, getModelMetadata); err != nil {
+		return nil, fmt.Errorf("error preparing query GetModelMetadata: %w", err)
+	}
+	if q.getSavedSearchByIdStmt, err = db.PrepareContext(ctx, getSavedSearchById); err != nil {
+		return nil, fmt.Errorf("error preparing query GetSavedSearchById: %w", err)
+	}
+	if q.getSavedSearchByNameStmt, err = db.PrepareContext(ctx, getSavedSearchByName); err != nil {
+		return nil, fmt.Errorf("error preparing query GetSavedSearchByName: %w", err)
+	}
 	if q.getScanFolderByIdStmt, err = db.PrepareContext(ctx, getScanFolderById); err != nil {
 		return nil, fmt.Errorf("error preparing query GetScanFolderById: %w", err)
 	}
@@ -114,27 +207,90 @@ func Prepare(ctx context.Context, db DBTX) (*Queries, error) {
 	if q.getTagsNamesByAssetIDStmt, err = db.PrepareContext(ctx, getTagsNamesByAssetID); err != nil {
 		return nil, fmt.Errorf("error preparing query GetTagsNamesByAssetID: %w", err)
 	}
+	if q.getTrashedAssetStmt, err = db.PrepareContext(ctx, getTrashedAsset); err != nil {
+		return nil, fmt.Errorf("error preparing query GetTrashedAsset: %w", err)
+	}
+	if q.indexAssetForSearchStmt, err = db.PrepareContext(ctx, indexAssetForSearch); err != nil {
+		return nil, fmt.Errorf("error preparing query IndexAssetForSearch: %w", err)
+	}
+	if q.insertAssetColorStmt, err = db.PrepareContext(ctx, insertAssetColor); err != nil {
+		return nil, fmt.Errorf("error preparing query InsertAssetColor: %w", err)
+	}
+	if q.insertAssetPropertyStmt, err = db.PrepareContext(ctx, insertAssetProperty); err != nil {
+		return nil, fmt.Errorf("error preparing query InsertAssetProperty: %w", err)
+	}
+	if q.isGroupLockedStmt, err = db.PrepareContext(ctx, isGroupLocked); err != nil {
+		return nil, fmt.Errorf("error preparing query IsGroupLocked: %w", err)
+	}
+	if q.listAssetColorsStmt, err = db.PrepareContext(ctx, listAssetColors); err != nil {
+		return nil, fmt.Errorf("error preparing query ListAssetColors: %w", err)
+	}
+	if q.listAssetColorsPageStmt, err = db.PrepareContext(ctx, listAssetColorsPage); err != nil {
+		return nil, fmt.Errorf("error preparing query ListAssetColorsPage: %w", err)
+	}
+	if q.listAssetFeaturesStmt, err = db.PrepareContext(ctx, listAssetFeatures); err != nil {
+		return nil, fmt.Errorf("error preparing query ListAssetFeatures: %w", err)
+	}
+	if q.listAssetPropertiesStmt, err = db.PrepareContext(ctx, listAssetProperties); err != nil {
+		return nil, fmt.Errorf("error preparing query ListAssetProperties: %w", err)
+	}
 	if q.listAssetsStmt, err = db.PrepareContext(ctx, listAssets); err != nil {
 		return nil, fmt.Errorf("error preparing query ListAssets: %w", err)
 	}
+	if q.listAssetsByHashStmt, err = db.PrepareContext(ctx, listAssetsByHash); err != nil {
+		return nil, fmt.Errorf("error preparing query ListAssetsByHash: %w", err)
+	}
 	if q.listAssetsForCacheStmt, err = db.PrepareContext(ctx, listAssetsForCache); err != nil {
 		return nil, fmt.Errorf("error preparing query ListAssetsForCache: %w", err)
 	}
+	if q.listAssetsForGroupingStmt, err = db.PrepareContext(ctx, listAssetsForGrouping); err != nil {
+		return nil, fmt.Errorf("error preparing query ListAssetsForGrouping: %w", err)
+	}
 	if q.listAssetsInMaterialSetStmt, err = db.PrepareContext(ctx, listAssetsInMaterialSet); err != nil {
 		return nil, fmt.Errorf("error preparing query ListAssetsInMaterialSet: %w", err)
 	}
+	if q.listColorPalettesStmt, err = db.PrepareContext(ctx, listColorPalettes); err != nil {
+		return nil, fmt.Errorf("error preparing query ListColorPalettes: %w", err)
+	}
 	if q.listDeletedAssetsStmt, err = db.PrepareContext(ctx, listDeletedAssets); err != nil {
 		return nil, fmt.Errorf("error preparing query ListDeletedAssets: %w", err)
 	}
+	if q.listDuplicateAssetsStmt, err = db.PrepareContext(ctx, listDuplicateAssets); err != nil {
+		return nil, fmt.Errorf("error preparing query ListDuplicateAssets: %w", err)
+	}
+	if q.listExpiredDeletedAssetIDsStmt, err = db.PrepareContext(ctx, listExpiredDeletedAssetIDs); err != nil {
+		return nil, fmt.Errorf("error preparing query ListExpiredDeletedAssetIDs: %w", err)
+	}
+	if q.listExpiredTrashedAssetsStmt, err = db.PrepareContext(ctx, listExpiredTrashedAssets); err != nil {
+		return nil, fmt.Errorf("error preparing query ListExpiredTrashedAssets: %w", err)
+	}
 	if q.listFavoriteAssetsStmt, err = db.PrepareContext(ctx, listFavoriteAssets); err != nil {
 		return nil, fmt.Errorf("error preparing query ListFavoriteAssets: %w", err)
 	}
+	if q.listGroupMaterialChannelsStmt, err = db.PrepareContext(ctx, listGroupMaterialChannels); err != nil {
+		return nil, fmt.Errorf("error preparing query ListGroupMaterialChannels: %w", err)
+	}
 	if q.listHiddenAssetsStmt, err = db.PrepareContext(ctx, listHiddenAssets); err != nil {
 		return nil, fmt.Errorf("error preparing query ListHiddenAssets: %w", err)
 	}
+	if q.listJournalEntriesStmt, err = db.PrepareContext(ctx, listJournalEntries); err != nil {
+		return nil, fmt.Errorf("error preparing query ListJournalEntries: %w", err)
+	}
+	if q.listLegacyDominantColorsStmt, err = db.PrepareContext(ctx, listLegacyDominantColors); err != nil {
+		return nil, fmt.Errorf("error preparing query ListLegacyDominantColors: %w", err)
+	}
+	if q.listLockedGroupIDsStmt, err = db.PrepareContext(ctx, listLockedGroupIDs); err != nil {
+		return nil, fmt.Errorf("error preparing query ListLockedGroupIDs: %w", err)
+	}
+	if q.listMaterialSetIDsByAssetStmt, err = db.PrepareContext(ctx, listMaterialSetIDsByAsset); err != nil {
+		return nil, fmt.Errorf("error preparing query ListMaterialSetIDsByAsset: %w", err)
+	}
 	if q.listMaterialSetsStmt, err = db.PrepareContext(ctx, listMaterialSets); err != nil {
 		return nil, fmt.Errorf("error preparing query ListMaterialSets: %w", err)
 	}
+	if q.listPerceptualHashesStmt, err = db.PrepareContext(ctx, listPerceptualHashes); err != nil {
+		return nil, fmt.Errorf("error preparing query ListPerceptualHashes: %w", err)
+	}
 	if q.listSavedSearchesStmt, err = db.PrepareContext(ctx, listSavedSearches); err != nil {
 		return nil, fmt.Errorf("error preparing query ListSavedSearches: %w", err)
 	}
@@ -144,12 +300,21 @@ func Prepare(ctx context.Context, db DBTX) (*Queries, error) {
 	if q.listTagsStmt, err = db.PrepareContext(ctx, listTags); err != nil {
 		return nil, fmt.Errorf("error preparing query ListTags: %w", err)
 	}
+	if q.listTrashedAssetsStmt, err = db.PrepareContext(ctx, listTrashedAssets); err != nil {
+		return nil, fmt.Errorf("error preparing query ListTrashedAssets: %w", err)
+	}
 	if q.listUntaggedAssetsStmt, err = db.PrepareContext(ctx, listUntaggedAssets); err != nil {
 		return nil, fmt.Errorf("error preparing query ListUntaggedAssets: %w", err)
 	}
 	if q.moveAssetsToFolderStmt, err = db.PrepareContext(ctx, moveAssetsToFolder); err != nil {
 		return nil, fmt.Errorf("error preparing query MoveAssetsToFolder: %w", err)
 	}
+	if q.pruneJournalStmt, err = db.PrepareContext(ctx, pruneJournal); err != nil {
+		return nil, fmt.Errorf("error preparing query PruneJournal: %w", err)
+	}
+	if q.reassignAssetGroupStmt, err = db.PrepareContext(ctx, reassignAssetGroup); err != nil {
+		return nil, fmt.Errorf("error preparing query ReassignAssetGroup: %w", err)
+	}
 	if q.refreshAssetTechnicalMetadataStmt, err = db.PrepareContext(ctx, refreshAssetTechnicalMetadata); err != nil {
 		return nil, fmt.Errorf("error preparing query RefreshAssetTechnicalMetadata: %w", err)
 	}
@@ -180,6 +345,9 @@ func Prepare(ctx context.Context, db DBTX) (*Queries, error) {
 	if q.setAssetsHiddenByFolderIdStmt, err = db.PrepareContext(ctx, setAssetsHiddenByFolderId); err != nil {
 		return nil, fmt.Errorf("error preparing query SetAssetsHiddenByFolderId: %w", err)
 	}
+	if q.setJournalEntryUndoneStmt, err = db.PrepareContext(ctx, setJournalEntryUndone); err != nil {
+		return nil, fmt.Errorf("error preparing query SetJournalEntryUndone: %w", err)
+	}
 	if q.setSystemSettingStmt, err = db.PrepareContext(ctx, setSystemSetting); err != nil {
 		return nil, fmt.Errorf("error preparing query SetSystemSetting: %w", err)
 	}
@@ -195,15 +363,27 @@ func Prepare(ctx context.Context, db DBTX) (*Queries, error) {
 	if q.toggleAssetFavoriteStmt, err = db.PrepareContext(ctx, toggleAssetFavorite); err != nil {
 		return nil, fmt.Errorf("error preparing query ToggleAssetFavorite: %w", err)
 	}
+	if q.updateAssetColorPaletteStmt, err = db.PrepareContext(ctx, updateAssetColorPalette); err != nil {
+		return nil, fmt.Errorf("error preparing query UpdateAssetColorPalette: %w", err)
+	}
+	if q.updateAssetDominantColorStmt, err = db.PrepareContext(ctx, updateAssetDominantColor); err != nil {
+		return nil, fmt.Errorf("error preparing query UpdateAssetDominantColor: %w", err)
+	}
 	if q.updateAssetFromScanStmt, err = db.PrepareContext(ctx, updateAssetFromScan); err != nil {
 		return nil, fmt.Errorf("error preparing query UpdateAssetFromScan: %w", err)
 	}
+	if q.updateAssetGroupStmt, err = db.PrepareContext(ctx, updateAssetGroup); err != nil {
+		return nil, fmt.Errorf("error preparing query UpdateAssetGroup: %w", err)
+	}
 	if q.updateAssetLocationStmt, err = db.PrepareContext(ctx, updateAssetLocation); err != nil {
 		return nil, fmt.Errorf("error preparing query UpdateAssetLocation: %w", err)
 	}
 	if q.updateAssetMetadataStmt, err = db.PrepareContext(ctx, updateAssetMetadata); err != nil {
 		return nil, fmt.Errorf("error preparing query UpdateAssetMetadata: %w", err)
 	}
+	if q.updateAssetPerceptualHashStmt, err = db.PrepareContext(ctx, updateAssetPerceptualHash); err != nil {
+		return nil, fmt.Errorf("error preparing query UpdateAssetPerceptualHash: %w", err)
+	}
 	if q.updateAssetScanStatusStmt, err = db.PrepareContext(ctx, updateAssetScanStatus); err != nil {
 		return nil, fmt.Errorf("error preparing query UpdateAssetScanStatus: %w", err)
 	}
@@ -213,20 +393,40 @@ func Prepare(ctx context.Context, db DBTX) (*Queries, error) {
 	if q.updateAssetsLastScannedInFolderStmt, err = db.PrepareContext(ctx, updateAssetsLastScannedInFolder); err != nil {
 		return nil, fmt.Errorf("error preparing query UpdateAssetsLastScannedInFolder: %w", err)
 	}
+	if q.updateColorPaletteStmt, err = db.PrepareContext(ctx, updateColorPalette); err != nil {
+		return nil, fmt.Errorf("error preparing query UpdateColorPalette: %w", err)
+	}
 	if q.updateMaterialSetStmt, err = db.PrepareContext(ctx, updateMaterialSet); err != nil {
 		return nil, fmt.Errorf("error preparing query UpdateMaterialSet: %w", err)
 	}
+	if q.updateSavedSearchStmt, err = db.PrepareContext(ctx, updateSavedSearch); err != nil {
+		return nil, fmt.Errorf("error preparing query UpdateSavedSearch: %w", err)
+	}
 	if q.updateScanFolderLastScannedStmt, err = db.PrepareContext(ctx, updateScanFolderLastScanned); err != nil {
 		return nil, fmt.Errorf("error preparing query UpdateScanFolderLastScanned: %w", err)
 	}
 	if q.updateScanFolderStatusStmt, err = db.PrepareContext(ctx, updateScanFolderStatus); err != nil {
 		return nil, fmt.Errorf("error preparing query UpdateScanFolderStatus: %w", err)
 	}
+	if q.upsertAssetFeaturesStmt, err = db.PrepareContext(ctx, upsertAssetFeatures); err != nil {
+		return nil, fmt.Errorf("error preparing query UpsertAssetFeatures: %w", err)
+	}
+	if q.upsertAssetGroupStmt, err = db.PrepareContext(ctx, upsertAssetGroup); err != nil {
+		return nil, fmt.Errorf("error preparing query UpsertAssetGroup: %w", err)
+	}
+	if q.upsertModelMetadataStmt, err = db.PrepareContext(ctx, upsertModelMetadata); err != nil {
+		return nil, fmt.Errorf("error preparing query UpsertModelMetadata: %w", err)
+	}
 	return &q, nil
 }
 
 func (q *Queries) Close() error {
 	var err error
+	if q.activateColorPaletteStmt != nil {
+		if cerr := q.activateColorPaletteStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing activateColorPaletteStmt: %w", cerr)
+		}
+	}
 	if q.addAssetToMaterialSetStmt != nil {
 		if cerr := q.addAssetToMaterialSetStmt.Close(); cerr != nil {
 			err = fmt.Errorf("error closing addAssetToMaterialSetStmt: %w", cerr)
@@ -242,9 +442,9 @@ func (q *Queries) Close() error {
 			err = fmt.Errorf("error closing claimAssetsForPathStmt: %w", cerr)
 		}
 	}
-	if q.cleanupOldDeletedAssetsStmt != nil {
-		if cerr := q.cleanupOldDeletedAssetsStmt.Close(); cerr != nil {
-			err = fmt.Errorf("error closing cleanupOldDeletedAssetsStmt: %w", cerr)
+	if q.clearJournalStmt != nil {
+		if cerr := q.clearJournalStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing clearJournalStmt: %w", cerr)
 		}
 	}
 	if q.clearTagsForAssetStmt != nil {
@@ -252,11 +452,51 @@ func (q *Queries) Close() error {
 			err = fmt.Errorf("error closing clearTagsForAssetStmt: %w", cerr)
 		}
 	}
+	if q.copyAssetColorsStmt != nil {
+		if cerr := q.copyAssetColorsStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing copyAssetColorsStmt: %w", cerr)
+		}
+	}
+	if q.copyAssetFeaturesStmt != nil {
+		if cerr := q.copyAssetFeaturesStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing copyAssetFeaturesStmt: %w", cerr)
+		}
+	}
+	if q.copyAssetMaterialSetsStmt != nil {
+		if cerr := q.copyAssetMaterialSetsStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing copyAssetMaterialSetsStmt: %w", cerr)
+		}
+	}
+	if q.copyAssetPropertiesStmt != nil {
+		if cerr := q.copyAssetPropertiesStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing copyAssetPropertiesStmt: %w", cerr)
+		}
+	}
+	if q.copyAssetTagsStmt != nil {
+		if cerr := q.copyAssetTagsStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing copyAssetTagsStmt: %w", cerr)
+		}
+	}
+	if q.copyModelMetadataStmt != nil {
+		if cerr := q.copyModelMetadataStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing copyModelMetadataStmt: %w", cerr)
+		}
+	}
 	if q.createAssetStmt != nil {
 		if cerr := q.createAssetStmt.Close(); cerr != nil {
 			err = fmt.Errorf("error closing createAssetStmt: %w", cerr)
 		}
 	}
+	if q.createColorPaletteStmt != nil {
+		if cerr := q.createColorPaletteStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing createColorPaletteStmt: %w", cerr)
+		}
+	}
+	if q.createJournalEntryStmt != nil {
+		if cerr := q.createJournalEntryStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing createJournalEntryStmt: %w", cerr)
+		}
+	}
 	if q.createMaterialSetStmt != nil {
 		if cerr := q.createMaterialSetStmt.Close(); cerr != nil {
 			err = fmt.Errorf("error closing createMaterialSetStmt: %w", cerr)
@@ -277,31 +517,96 @@ func (q *Queries) Close() error {
 			err = fmt.Errorf("error closing createTagStmt: %w", cerr)
 		}
 	}
+	if q.createTrashedAssetStmt != nil {
+		if cerr := q.createTrashedAssetStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing createTrashedAssetStmt: %w", cerr)
+		}
+	}
+	if q.deactivateColorPalettesStmt != nil {
+		if cerr := q.deactivateColorPalettesStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing deactivateColorPalettesStmt: %w", cerr)
+		}
+	}
 	if q.deleteAssetByFolderStmt != nil {
 		if cerr := q.deleteAssetByFolderStmt.Close(); cerr != nil {
 			err = fmt.Errorf("error closing deleteAssetByFolderStmt: %w", cerr)
 		}
 	}
+	if q.deleteAssetColorsStmt != nil {
+		if cerr := q.deleteAssetColorsStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing deleteAssetColorsStmt: %w", cerr)
+		}
+	}
+	if q.deleteAssetFeaturesStmt != nil {
+		if cerr := q.deleteAssetFeaturesStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing deleteAssetFeaturesStmt: %w", cerr)
+		}
+	}
+	if q.deleteAssetGroupStmt != nil {
+		if cerr := q.deleteAssetGroupStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing deleteAssetGroupStmt: %w", cerr)
+		}
+	}
 	if q.deleteAssetPermanentStmt != nil {
 		if cerr := q.deleteAssetPermanentStmt.Close(); cerr != nil {
 			err = fmt.Errorf("error closing deleteAssetPermanentStmt: %w", cerr)
 		}
 	}
+	if q.deleteAssetPropertiesStmt != nil {
+		if cerr := q.deleteAssetPropertiesStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing deleteAssetPropertiesStmt: %w", cerr)
+		}
+	}
+	if q.deleteAssetSearchIndexStmt != nil {
+		if cerr := q.deleteAssetSearchIndexStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing deleteAssetSearchIndexStmt: %w", cerr)
+		}
+	}
+	if q.deleteColorPaletteStmt != nil {
+		if cerr := q.deleteColorPaletteStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing deleteColorPaletteStmt: %w", cerr)
+		}
+	}
 	if q.deleteMaterialSetStmt != nil {
 		if cerr := q.deleteMaterialSetStmt.Close(); cerr != nil {
 			err = fmt.Errorf("error closing deleteMaterialSetStmt: %w", cerr)
 		}
 	}
+	if q.deleteModelMetadataStmt != nil {
+		if cerr := q.deleteModelMetadataStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing deleteModelMetadataStmt: %w", cerr)
+		}
+	}
 	if q.deleteSavedSearchStmt != nil {
 		if cerr := q.deleteSavedSearchStmt.Close(); cerr != nil {
 			err = fmt.Errorf("error closing deleteSavedSearchStmt: %w", cerr)
 		}
 	}
+	if q.deleteTrashedAssetStmt != nil {
+		if cerr := q.deleteTrashedAssetStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing deleteTrashedAssetStmt: %w", cerr)
+		}
+	}
+	if q.deleteUndoneJournalEntriesStmt != nil {
+		if cerr := q.deleteUndoneJournalEntriesStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing deleteUndoneJournalEntriesStmt: %w", cerr)
+		}
+	}
 	if q.findPotentialSiblingsStmt != nil {
 		if cerr := q.findPotentialSiblingsStmt.Close(); cerr != nil {
 			err = fmt.Errorf("error closing findPotentialSiblingsStmt: %w", cerr)
 		}
 	}
+	if q.findPotentialSiblingsInLibraryStmt != nil {
+		if cerr := q.findPotentialSiblingsInLibraryStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing findPotentialSiblingsInLibraryStmt: %w", cerr)
+		}
+	}
+	if q.getActiveColorPaletteStmt != nil {
+		if cerr := q.getActiveColorPaletteStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing getActiveColorPaletteStmt: %w", cerr)
+		}
+	}
 	if q.getAllColorsStmt != nil {
 		if cerr := q.getAllColorsStmt.Close(); cerr != nil {
 			err = fmt.Errorf("error closing getAllColorsStmt: %w", cerr)
@@ -327,11 +632,41 @@ func (q *Queries) Close() error {
 			err = fmt.Errorf("error closing getAssetByPathStmt: %w", cerr)
 		}
 	}
+	if q.getAssetFeaturesStmt != nil {
+		if cerr := q.getAssetFeaturesStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing getAssetFeaturesStmt: %w", cerr)
+		}
+	}
+	if q.getAssetGroupStmt != nil {
+		if cerr := q.getAssetGroupStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing getAssetGroupStmt: %w", cerr)
+		}
+	}
 	if q.getAssetsByGroupIDStmt != nil {
 		if cerr := q.getAssetsByGroupIDStmt.Close(); cerr != nil {
 			err = fmt.Errorf("error closing getAssetsByGroupIDStmt: %w", cerr)
 		}
 	}
+	if q.getColorPaletteByIdStmt != nil {
+		if cerr := q.getColorPaletteByIdStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing getColorPaletteByIdStmt: %w", cerr)
+		}
+	}
+	if q.getColorPaletteByNameStmt != nil {
+		if cerr := q.getColorPaletteByNameStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing getColorPaletteByNameStmt: %w", cerr)
+		}
+	}
+	if q.getFirstUndoneJournalEntryStmt != nil {
+		if cerr := q.getFirstUndoneJournalEntryStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing getFirstUndoneJournalEntryStmt: %w", cerr)
+		}
+	}
+	if q.getLastActiveJournalEntryStmt != nil {
+		if cerr := q.getLastActiveJournalEntryStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing getLastActiveJournalEntryStmt: %w", cerr)
+		}
+	}
 	if q.getLibraryStatsStmt != nil {
 		if cerr := q.getLibraryStatsStmt.Close(); cerr != nil {
 			err = fmt.Errorf("error closing getLibraryStatsStmt: %w", cerr)
@@ -342,6 +677,21 @@ func (q *Queries) Close() error {
 			err = fmt.Errorf("error closing getMaterialSetByIdStmt: %w", cerr)
 		}
 	}
+	if q.getModelMetadataStmt != nil {
+		if cerr := q.getModelMetadataStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing getModelMetadataStmt: %w", cerr)
+		}
+	}
+	if q.getSavedSearchByIdStmt != nil {
+		if cerr := q.getSavedSearchByIdStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing getSavedSearchByIdStmt: %w", cerr)
+		}
+	}
+	if q.getSavedSearchByNameStmt != nil {
+		if cerr := q.getSavedSearchByNameStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing getSavedSearchByNameStmt: %w", cerr)
+		}
+	}
 	if q.getScanFolderByIdStmt != nil {
 		if cerr := q.getScanFolderByIdStmt.Close(); cerr != nil {
 			err = fmt.Errorf("error closing getScanFolderByIdStmt: %w", cerr)
@@ -377,41 +727,146 @@ func (q *Queries) Close() error {
 			err = fmt.Errorf("error closing getTagsNamesByAssetIDStmt: %w", cerr)
 		}
 	}
+	if q.getTrashedAssetStmt != nil {
+		if cerr := q.getTrashedAssetStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing getTrashedAssetStmt: %w", cerr)
+		}
+	}
+	if q.indexAssetForSearchStmt != nil {
+		if cerr := q.indexAssetForSearchStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing indexAssetForSearchStmt: %w", cerr)
+		}
+	}
+	if q.insertAssetColorStmt != nil {
+		if cerr := q.insertAssetColorStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing insertAssetColorStmt: %w", cerr)
+		}
+	}
+	if q.insertAssetPropertyStmt != nil {
+		if cerr := q.insertAssetPropertyStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing insertAssetPropertyStmt: %w", cerr)
+		}
+	}
+	if q.isGroupLockedStmt != nil {
+		if cerr := q.isGroupLockedStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing isGroupLockedStmt: %w", cerr)
+		}
+	}
+	if q.listAssetColorsStmt != nil {
+		if cerr := q.listAssetColorsStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing listAssetColorsStmt: %w", cerr)
+		}
+	}
+	if q.listAssetColorsPageStmt != nil {
+		if cerr := q.listAssetColorsPageStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing listAssetColorsPageStmt: %w", cerr)
+		}
+	}
+	if q.listAssetFeaturesStmt != nil {
+		if cerr := q.listAssetFeaturesStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing listAssetFeaturesStmt: %w", cerr)
+		}
+	}
+	if q.listAssetPropertiesStmt != nil {
+		if cerr := q.listAssetPropertiesStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing listAssetPropertiesStmt: %w", cerr)
+		}
+	}
 	if q.listAssetsStmt != nil {
 		if cerr := q.listAssetsStmt.Close(); cerr != nil {
 			err = fmt.Errorf("error closing listAssetsStmt: %w", cerr)
 		}
 	}
+	if q.listAssetsByHashStmt != nil {
+		if cerr := q.listAssetsByHashStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing listAssetsByHashStmt: %w", cerr)
+		}
+	}
 	if q.listAssetsForCacheStmt != nil {
 		if cerr := q.listAssetsForCacheStmt.Close(); cerr != nil {
 			err = fmt.Errorf("error closing listAssetsForCacheStmt: %w", cerr)
 		}
 	}
+	if q.listAssetsForGroupingStmt != nil {
+		if cerr := q.listAssetsForGroupingStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing listAssetsForGroupingStmt: %w", cerr)
+		}
+	}
 	if q.listAssetsInMaterialSetStmt != nil {
 		if cerr := q.listAssetsInMaterialSetStmt.Close(); cerr != nil {
 			err = fmt.Errorf("error closing listAssetsInMaterialSetStmt: %w", cerr)
 		}
 	}
+	if q.listColorPalettesStmt != nil {
+		if cerr := q.listColorPalettesStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing listColorPalettesStmt: %w", cerr)
+		}
+	}
 	if q.listDeletedAssetsStmt != nil {
 		if cerr := q.listDeletedAssetsStmt.Close(); cerr != nil {
 			err = fmt.Errorf("error closing listDeletedAssetsStmt: %w", cerr)
 		}
 	}
+	if q.listDuplicateAssetsStmt != nil {
+		if cerr := q.listDuplicateAssetsStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing listDuplicateAssetsStmt: %w", cerr)
+		}
+	}
+	if q.listExpiredDeletedAssetIDsStmt != nil {
+		if cerr := q.listExpiredDeletedAssetIDsStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing listExpiredDeletedAssetIDsStmt: %w", cerr)
+		}
+	}
+	if q.listExpiredTrashedAssetsStmt != nil {
+		if cerr := q.listExpiredTrashedAssetsStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing listExpiredTrashedAssetsStmt: %w", cerr)
+		}
+	}
 	if q.listFavoriteAssetsStmt != nil {
 		if cerr := q.listFavoriteAssetsStmt.Close(); cerr != nil {
 			err = fmt.Errorf("error closing listFavoriteAssetsStmt: %w", cerr)
 		}
 	}
+	if q.listGroupMaterialChannelsStmt != nil {
+		if cerr := q.listGroupMaterialChannelsStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing listGroupMaterialChannelsStmt: %w", cerr)
+		}
+	}
 	if q.listHiddenAssetsStmt != nil {
 		if cerr := q.listHiddenAssetsStmt.Close(); cerr != nil {
 			err = fmt.Errorf("error closing listHiddenAssetsStmt: %w", cerr)
 		}
 	}
+	if q.listJournalEntriesStmt != nil {
+		if cerr := q.listJournalEntriesStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing listJournalEntriesStmt: %w", cerr)
+		}
+	}
+	if q.listLegacyDominantColorsStmt != nil {
+		if cerr := q.listLegacyDominantColorsStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing listLegacyDominantColorsStmt: %w", cerr)
+		}
+	}
+	if q.listLockedGroupIDsStmt != nil {
+		if cerr := q.listLockedGroupIDsStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing listLockedGroupIDsStmt: %w", cerr)
+		}
+	}
+	if q.listMaterialSetIDsByAssetStmt != nil {
+		if cerr := q.listMaterialSetIDsByAssetStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing listMaterialSetIDsByAssetStmt: %w", cerr)
+		}
+	}
 	if q.listMaterialSetsStmt != nil {
 		if cerr := q.listMaterialSetsStmt.Close(); cerr != nil {
 			err = fmt.Errorf("error closing listMaterialSetsStmt: %w", cerr)
 		}
 	}
+	if q.listPerceptualHashesStmt != nil {
+		if cerr := q.listPerceptualHashesStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing listPerceptualHashesStmt: %w", cerr)
+		}
+	}
 	if q.listSavedSearchesStmt != nil {
 		if cerr := q.listSavedSearchesStmt.Close(); cerr != nil {
 			err = fmt.Errorf("error closing listSavedSearchesStmt: %w", cerr)
@@ -427,6 +882,11 @@ func (q *Queries) Close() error {
 			err = fmt.Errorf("error closing listTagsStmt: %w", cerr)
 		}
 	}
+	if q.listTrashedAssetsStmt != nil {
+		if cerr := q.listTrashedAssetsStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing listTrashedAssetsStmt: %w", cerr)
+		}
+	}
 	if q.listUntaggedAssetsStmt != nil {
 		if cerr := q.listUntaggedAssetsStmt.Close(); cerr != nil {
 			err = fmt.Errorf("error closing listUntaggedAssetsStmt: %w", cerr)
@@ -437,6 +897,16 @@ func (q *Queries) Close() error {
 			err = fmt.Errorf("error closing moveAssetsToFolderStmt: %w", cerr)
 		}
 	}
+	if q.pruneJournalStmt != nil {
+		if cerr := q.pruneJournalStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing pruneJournalStmt: %w", cerr)
+		}
+	}
+	if q.reassignAssetGroupStmt != nil {
+		if cerr := q.reassignAssetGroupStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing reassignAssetGroupStmt: %w", cerr)
+		}
+	}
 	if q.refreshAssetTechnicalMetadataStmt != nil {
 		if cerr := q.refreshAssetTechnicalMetadataStmt.Close(); cerr != nil {
 			err = fmt.Errorf("error closing refreshAssetTechnicalMetadataStmt: %w", cerr)
@@ -487,6 +957,11 @@ func (q *Queries) Close() error {
 			err = fmt.Errorf("error closing setAssetsHiddenByFolderIdStmt: %w", cerr)
 		}
 	}
+	if q.setJournalEntryUndoneStmt != nil {
+		if cerr := q.setJournalEntryUndoneStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing setJournalEntryUndoneStmt: %w", cerr)
+		}
+	}
 	if q.setSystemSettingStmt != nil {
 		if cerr := q.setSystemSettingStmt.Close(); cerr != nil {
 			err = fmt.Errorf("error closing setSystemSettingStmt: %w", cerr)
@@ -512,11 +987,26 @@ func (q *Queries) Close() error {
 			err = fmt.Errorf("error closing toggleAssetFavoriteStmt: %w", cerr)
 		}
 	}
+	if q.updateAssetColorPaletteStmt != nil {
+		if cerr := q.updateAssetColorPaletteStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing updateAssetColorPaletteStmt: %w", cerr)
+		}
+	}
+	if q.updateAssetDominantColorStmt != nil {
+		if cerr := q.updateAssetDominantColorStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing updateAssetDominantColorStmt: %w", cerr)
+		}
+	}
 	if q.updateAssetFromScanStmt != nil {
 		if cerr := q.updateAssetFromScanStmt.Close(); cerr != nil {
 			err = fmt.Errorf("error closing updateAssetFromScanStmt: %w", cerr)
 		}
 	}
+	if q.updateAssetGroupStmt != nil {
+		if cerr := q.updateAssetGroupStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing updateAssetGroupStmt: %w", cerr)
+		}
+	}
 	if q.updateAssetLocationStmt != nil {
 		if cerr := q.updateAssetLocationStmt.Close(); cerr != nil {
 			err = fmt.Errorf("error closing updateAssetLocationStmt: %w", cerr)
@@ -527,6 +1017,11 @@ func (q *Queries) Close() error {
 			err = fmt.Errorf("error closing updateAssetMetadataStmt: %w", cerr)
 		}
 	}
+	if q.updateAssetPerceptualHashStmt != nil {
+		if cerr := q.updateAssetPerceptualHashStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing updateAssetPerceptualHashStmt: %w", cerr)
+		}
+	}
 	if q.updateAssetScanStatusStmt != nil {
 		if cerr := q.updateAssetScanStatusStmt.Close(); cerr != nil {
 			err = fmt.Errorf("error closing updateAssetScanStatusStmt: %w", cerr)
@@ -542,11 +1037,21 @@ func (q *Queries) Close() error {
 			err = fmt.Errorf("error closing updateAssetsLastScannedInFolderStmt: %w", cerr)
 		}
 	}
+	if q.updateColorPaletteStmt != nil {
+		if cerr := q.updateColorPaletteStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing updateColorPaletteStmt: %w", cerr)
+		}
+	}
 	if q.updateMaterialSetStmt != nil {
 		if cerr := q.updateMaterialSetStmt.Close(); cerr != nil {
 			err = fmt.Errorf("error closing updateMaterialSetStmt: %w", cerr)
 		}
 	}
+	if q.updateSavedSearchStmt != nil {
+		if cerr := q.updateSavedSearchStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing updateSavedSearchStmt: %w", cerr)
+		}
+	}
 	if q.updateScanFolderLastScannedStmt != nil {
 		if cerr := q.updateScanFolderLastScannedStmt.Close(); cerr != nil {
 			err = fmt.Errorf("error closing updateScanFolderLastScannedStmt: %w", cerr)
@@ -557,6 +1062,21 @@ func (q *Queries) Close() error {
 			err = fmt.Errorf("error closing updateScanFolderStatusStmt: %w", cerr)
 		}
 	}
+	if q.upsertAssetFeaturesStmt != nil {
+		if cerr := q.upsertAssetFeaturesStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing upsertAssetFeaturesStmt: %w", cerr)
+		}
+	}
+	if q.upsertAssetGroupStmt != nil {
+		if cerr := q.upsertAssetGroupStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing upsertAssetGroupStmt: %w", cerr)
+		}
+	}
+	if q.upsertModelMetadataStmt != nil {
+		if cerr := q.upsertModelMetadataStmt.Close(); cerr != nil {
+			err = fmt.Errorf("error closing upsertModelMetadataStmt: %w", cerr)
+		}
+	}
 	return err
 }
 
@@ -596,29 +1116,60 @@ func (q *Queries) queryRow(ctx context.Context, stmt *sql.Stmt, query string, ar
 type Queries struct {
 	db                                  DBTX
 	tx                                  *sql.Tx
+	activateColorPaletteStmt            *sql.Stmt
 	addAssetToMaterialSetStmt           *sql.Stmt
 	addTagToAssetStmt                   *sql.Stmt
 	claimAssetsForPathStmt              *sql.Stmt
-	cleanupOldDeletedAssetsStmt         *sql.Stmt
+	clearJournalStmt                    *sql.Stmt
 	clearTagsForAssetStmt               *sql.Stmt
+	copyAssetColorsStmt                 *sql.Stmt
+	copyAssetFeaturesStmt               *sql.Stmt
+	copyAssetMaterialSetsStmt           *sql.Stmt
+	copyAssetPropertiesStmt             *sql.Stmt
+	copyAssetTagsStmt                   *sql.Stmt
+	copyModelMetadataStmt               *sql.Stmt
 	createAssetStmt                     *sql.Stmt
+	createColorPaletteStmt              *sql.Stmt
+	createJournalEntryStmt              *sql.Stmt
 	createMaterialSetStmt               *sql.Stmt
 	createSavedSearchStmt               *sql.Stmt
 	createScanFolderStmt                *sql.Stmt
 	createTagStmt                       *sql.Stmt
+	createTrashedAssetStmt              *sql.Stmt
+	deactivateColorPalettesStmt         *sql.Stmt
 	deleteAssetByFolderStmt             *sql.Stmt
+	deleteAssetColorsStmt               *sql.Stmt
+	deleteAssetFeaturesStmt             *sql.Stmt
+	deleteAssetGroupStmt                *sql.Stmt
 	deleteAssetPermanentStmt            *sql.Stmt
+	deleteAssetPropertiesStmt           *sql.Stmt
+	deleteAssetSearchIndexStmt          *sql.Stmt
+	deleteColorPaletteStmt              *sql.Stmt
 	deleteMaterialSetStmt               *sql.Stmt
+	deleteModelMetadataStmt             *sql.Stmt
 	deleteSavedSearchStmt               *sql.Stmt
+	deleteTrashedAssetStmt              *sql.Stmt
+	deleteUndoneJournalEntriesStmt      *sql.Stmt
 	findPotentialSiblingsStmt           *sql.Stmt
+	findPotentialSiblingsInLibraryStmt  *sql.Stmt
+	getActiveColorPaletteStmt           *sql.Stmt
 	getAllColorsStmt                    *sql.Stmt
 	getAllTagsStmt                      *sql.Stmt
 	getAssetByHashStmt                  *sql.Stmt
 	getAssetByIdStmt                    *sql.Stmt
 	getAssetByPathStmt                  *sql.Stmt
+	getAssetFeaturesStmt                *sql.Stmt
+	getAssetGroupStmt                   *sql.Stmt
 	getAssetsByGroupIDStmt              *sql.Stmt
+	getColorPaletteByIdStmt             *sql.Stmt
+	getColorPaletteByNameStmt           *sql.Stmt
+	getFirstUndoneJournalEntryStmt      *sql.Stmt
+	getLastActiveJournalEntryStmt       *sql.Stmt
 	getLibraryStatsStmt                 *sql.Stmt
 	getMaterialSetByIdStmt              *sql.Stmt
+	getModelMetadataStmt                *sql.Stmt
+	getSavedSearchByIdStmt              *sql.Stmt
+	getSavedSearchByNameStmt            *sql.Stmt
 	getScanFolderByIdStmt               *sql.Stmt
 	getScanFolderByPathStmt             *sql.Stmt
 	getSidebarStatsStmt                 *sql.Stmt
@@ -626,18 +1177,42 @@ type Queries struct {
 	getTagByNameStmt                    *sql.Stmt
 	getTagsByAssetIDStmt                *sql.Stmt
 	getTagsNamesByAssetIDStmt           *sql.Stmt
+	getTrashedAssetStmt                 *sql.Stmt
+	indexAssetForSearchStmt             *sql.Stmt
+	insertAssetColorStmt                *sql.Stmt
+	insertAssetPropertyStmt             *sql.Stmt
+	isGroupLockedStmt                   *sql.Stmt
+	listAssetColorsStmt                 *sql.Stmt
+	listAssetColorsPageStmt             *sql.Stmt
+	listAssetFeaturesStmt               *sql.Stmt
+	listAssetPropertiesStmt             *sql.Stmt
 	listAssetsStmt                      *sql.Stmt
+	listAssetsByHashStmt                *sql.Stmt
 	listAssetsForCacheStmt              *sql.Stmt
+	listAssetsForGroupingStmt           *sql.Stmt
 	listAssetsInMaterialSetStmt         *sql.Stmt
+	listColorPalettesStmt               *sql.Stmt
 	listDeletedAssetsStmt               *sql.Stmt
+	listDuplicateAssetsStmt             *sql.Stmt
+	listExpiredDeletedAssetIDsStmt      *sql.Stmt
+	listExpiredTrashedAssetsStmt        *sql.Stmt
 	listFavoriteAssetsStmt              *sql.Stmt
+	listGroupMaterialChannelsStmt       *sql.Stmt
 	listHiddenAssetsStmt                *sql.Stmt
+	listJournalEntriesStmt              *sql.Stmt
+	listLegacyDominantColorsStmt        *sql.Stmt
+	listLockedGroupIDsStmt              *sql.Stmt
+	listMaterialSetIDsByAssetStmt       *sql.Stmt
 	listMaterialSetsStmt                *sql.Stmt
+	listPerceptualHashesStmt            *sql.Stmt
 	listSavedSearchesStmt               *sql.Stmt
 	listScanFoldersStmt                 *sql.Stmt
 	listTagsStmt                        *sql.Stmt
+	listTrashedAssetsStmt               *sql.Stmt
 	listUntaggedAssetsStmt              *sql.Stmt
 	moveAssetsToFolderStmt              *sql.Stmt
+	pruneJournalStmt                    *sql.Stmt
+	reassignAssetGroupStmt              *sql.Stmt
 	refreshAssetTechnicalMetadataStmt   *sql.Stmt
 	removeAssetFromMaterialSetStmt      *sql.Stmt
 	removeTagFromAssetStmt              *sql.Stmt
@@ -648,49 +1223,90 @@ type Queries struct {
 	setAssetHiddenStmt                  *sql.Stmt
 	setAssetRatingStmt                  *sql.Stmt
 	setAssetsHiddenByFolderIdStmt       *sql.Stmt
+	setJournalEntryUndoneStmt           *sql.Stmt
 	setSystemSettingStmt                *sql.Stmt
 	softDeleteAssetStmt                 *sql.Stmt
 	softDeleteAssetsStmt                *sql.Stmt
 	softDeleteScanFolderStmt            *sql.Stmt
 	toggleAssetFavoriteStmt             *sql.Stmt
+	updateAssetColorPaletteStmt         *sql.Stmt
+	updateAssetDominantColorStmt        *sql.Stmt
 	updateAssetFromScanStmt             *sql.Stmt
+	updateAssetGroupStmt                *sql.Stmt
 	updateAssetLocationStmt             *sql.Stmt
 	updateAssetMetadataStmt             *sql.Stmt
+	updateAssetPerceptualHashStmt       *sql.Stmt
 	updateAssetScanStatusStmt           *sql.Stmt
 	updateAssetTypeStmt                 *sql.Stmt
 	updateAssetsLastScannedInFolderStmt *sql.Stmt
+	updateColorPaletteStmt              *sql.Stmt
 	updateMaterialSetStmt               *sql.Stmt
+	updateSavedSearchStmt               *sql.Stmt
 	updateScanFolderLastScannedStmt     *sql.Stmt
 	updateScanFolderStatusStmt          *sql.Stmt
+	upsertAssetFeaturesStmt             *sql.Stmt
+	upsertAssetGroupStmt                *sql.Stmt
+	upsertModelMetadataStmt             *sql.Stmt
 }
 
 func (q *Queries) WithTx(tx *sql.Tx) *Queries {
 	return &Queries{
 		db:                                  tx,
 		tx:                                  tx,
+		activateColorPaletteStmt:            q.activateColorPaletteStmt,
 		addAssetToMaterialSetStmt:           q.addAssetToMaterialSetStmt,
 		addTagToAssetStmt:                   q.addTagToAssetStmt,
 		claimAssetsForPathStmt:              q.claimAssetsForPathStmt,
-		cleanupOldDeletedAssetsStmt:         q.cleanupOldDeletedAssetsStmt,
+		clearJournalStmt:                    q.clearJournalStmt,
 		clearTagsForAssetStmt:               q.clearTagsForAssetStmt,
+		copyAssetColorsStmt:                 q.copyAssetColorsStmt,
+		copyAssetFeaturesStmt:               q.copyAssetFeaturesStmt,
+		copyAssetMaterialSetsStmt:           q.copyAssetMaterialSetsStmt,
+		copyAssetPropertiesStmt:             q.copyAssetPropertiesStmt,
+		copyAssetTagsStmt:                   q.copyAssetTagsStmt,
+		copyModelMetadataStmt:               q.copyModelMetadataStmt,
 		createAssetStmt:                     q.createAssetStmt,
+		createColorPaletteStmt:              q.createColorPaletteStmt,
+		createJournalEntryStmt:              q.createJournalEntryStmt,
 		createMaterialSetStmt:               q.createMaterialSetStmt,
 		createSavedSearchStmt:               q.createSavedSearchStmt,
 		createScanFolderStmt:                q.createScanFolderStmt,
 		createTagStmt:                       q.createTagStmt,
+		createTrashedAssetStmt:              q.createTrashedAssetStmt,
+		deactivateColorPalettesStmt:         q.deactivateColorPalettesStmt,
 		deleteAssetByFolderStmt:             q.deleteAssetByFolderStmt,
+		deleteAssetColorsStmt:               q.deleteAssetColorsStmt,
+		deleteAssetFeaturesStmt:             q.deleteAssetFeaturesStmt,
+		deleteAssetGroupStmt:                q.deleteAssetGroupStmt,
 		deleteAssetPermanentStmt:            q.deleteAssetPermanentStmt,
+		deleteAssetPropertiesStmt:           q.deleteAssetPropertiesStmt,
+		deleteAssetSearchIndexStmt:          q.deleteAssetSearchIndexStmt,
+		deleteColorPaletteStmt:              q.deleteColorPaletteStmt,
 		deleteMaterialSetStmt:               q.deleteMaterialSetStmt,
+		deleteModelMetadataStmt:             q.deleteModelMetadataStmt,
 		deleteSavedSearchStmt:               q.deleteSavedSearchStmt,
+		deleteTrashedAssetStmt:              q.deleteTrashedAssetStmt,
+		deleteUndoneJournalEntriesStmt:      q.deleteUndoneJournalEntriesStmt,
 		findPotentialSiblingsStmt:           q.findPotentialSiblingsStmt,
+		findPotentialSiblingsInLibraryStmt:  q.findPotentialSiblingsInLibraryStmt,
+		getActiveColorPaletteStmt:           q.getActiveColorPaletteStmt,
 		getAllColorsStmt:                    q.getAllColorsStmt,
 		getAllTagsStmt:                      q.getAllTagsStmt,
 		getAssetByHashStmt:                  q.getAssetByHashStmt,
 		getAssetByIdStmt:                    q.getAssetByIdStmt,
 		getAssetByPathStmt:                  q.getAssetByPathStmt,
+		getAssetFeaturesStmt:                q.getAssetFeaturesStmt,
+		getAssetGroupStmt:                   q.getAssetGroupStmt,
 		getAssetsByGroupIDStmt:              q.getAssetsByGroupIDStmt,
+		getColorPaletteByIdStmt:             q.getColorPaletteByIdStmt,
+		getColorPaletteByNameStmt:           q.getColorPaletteByNameStmt,
+		getFirstUndoneJournalEntryStmt:      q.getFirstUndoneJournalEntryStmt,
+		getLastActiveJournalEntryStmt:       q.getLastActiveJournalEntryStmt,
 		getLibraryStatsStmt:                 q.getLibraryStatsStmt,
 		getMaterialSetByIdStmt:              q.getMaterialSetByIdStmt,
+		getModelMetadataStmt:                q.getModelMetadataStmt,
+		getSavedSearchByIdStmt:              q.getSavedSearchByIdStmt,
+		getSavedSearchByNameStmt:            q.getSavedSearchByNameStmt,
 		getScanFolderByIdStmt:               q.getScanFolderByIdStmt,
 		getScanFolderByPathStmt:             q.getScanFolderByPathStmt,
 		getSidebarStatsStmt:                 q.getSidebarStatsStmt,
@@ -698,18 +1314,42 @@ func (q *Queries) WithTx(tx *sql.Tx) *Queries {
 		getTagByNameStmt:                    q.getTagByNameStmt,
 		getTagsByAssetIDStmt:                q.getTagsByAssetIDStmt,
 		getTagsNamesByAssetIDStmt:           q.getTagsNamesByAssetIDStmt,
+		getTrashedAssetStmt:                 q.getTrashedAssetStmt,
+		indexAssetForSearchStmt:             q.indexAssetForSearchStmt,
+		insertAssetColorStmt:                q.insertAssetColorStmt,
+		insertAssetPropertyStmt:             q.insertAssetPropertyStmt,
+		isGroupLockedStmt:                   q.isGroupLockedStmt,
+		listAssetColorsStmt:                 q.listAssetColorsStmt,
+		listAssetColorsPageStmt:             q.listAssetColorsPageStmt,
+		listAssetFeaturesStmt:               q.listAssetFeaturesStmt,
+		listAssetPropertiesStmt:             q.listAssetPropertiesStmt,
 		listAssetsStmt:                      q.listAssetsStmt,
+		listAssetsByHashStmt:                q.listAssetsByHashStmt,
 		listAssetsForCacheStmt:              q.listAssetsForCacheStmt,
+		listAssetsForGroupingStmt:           q.listAssetsForGroupingStmt,
 		listAssetsInMaterialSetStmt:         q.listAssetsInMaterialSetStmt,
+		listColorPalettesStmt:               q.listColorPalettesStmt,
 		listDeletedAssetsStmt:               q.listDeletedAssetsStmt,
+		listDuplicateAssetsStmt:             q.listDuplicateAssetsStmt,
+		listExpiredDeletedAssetIDsStmt:      q.listExpiredDeletedAssetIDsStmt,
+		listExpiredTrashedAssetsStmt:        q.listExpiredTrashedAssetsStmt,
 		listFavoriteAssetsStmt:              q.listFavoriteAssetsStmt,
+		listGroupMaterialChannelsStmt:       q.listGroupMaterialChannelsStmt,
 		listHiddenAssetsStmt:                q.listHiddenAssetsStmt,
+		listJournalEntriesStmt:              q.listJournalEntriesStmt,
+		listLegacyDominantColorsStmt:        q.listLegacyDominantColorsStmt,
+		listLockedGroupIDsStmt:              q.listLockedGroupIDsStmt,
+		listMaterialSetIDsByAssetStmt:       q.listMaterialSetIDsByAssetStmt,
 		listMaterialSetsStmt:                q.listMaterialSetsStmt,
+		listPerceptualHashesStmt:            q.listPerceptualHashesStmt,
 		listSavedSearchesStmt:               q.listSavedSearchesStmt,
 		listScanFoldersStmt:                 q.listScanFoldersStmt,
 		listTagsStmt:                        q.listTagsStmt,
+		listTrashedAssetsStmt:               q.listTrashedAssetsStmt,
 		listUntaggedAssetsStmt:              q.listUntaggedAssetsStmt,
 		moveAssetsToFolderStmt:              q.moveAssetsToFolderStmt,
+		pruneJournalStmt:                    q.pruneJournalStmt,
+		reassignAssetGroupStmt:              q.reassignAssetGroupStmt,
 		refreshAssetTechnicalMetadataStmt:   q.refreshAssetTechnicalMetadataStmt,
 		removeAssetFromMaterialSetStmt:      q.removeAssetFromMaterialSetStmt,
 		removeTagFromAssetStmt:              q.removeTagFromAssetStmt,
@@ -720,19 +1360,29 @@ func (q *Queries) WithTx(tx *sql.Tx) *Queries {
 		setAssetHiddenStmt:                  q.setAssetHiddenStmt,
 		setAssetRatingStmt:                  q.setAssetRatingStmt,
 		setAssetsHiddenByFolderIdStmt:       q.setAssetsHiddenByFolderIdStmt,
+		setJournalEntryUndoneStmt:           q.setJournalEntryUndoneStmt,
 		setSystemSettingStmt:                q.setSystemSettingStmt,
 		softDeleteAssetStmt:                 q.softDeleteAssetStmt,
 		softDeleteAssetsStmt:                q.softDeleteAssetsStmt,
 		softDeleteScanFolderStmt:            q.softDeleteScanFolderStmt,
 		toggleAssetFavoriteStmt:             q.toggleAssetFavoriteStmt,
+		updateAssetColorPaletteStmt:         q.updateAssetColorPaletteStmt,
+		updateAssetDominantColorStmt:        q.updateAssetDominantColorStmt,
 		updateAssetFromScanStmt:             q.updateAssetFromScanStmt,
+		updateAssetGroupStmt:                q.updateAssetGroupStmt,
 		updateAssetLocationStmt:             q.updateAssetLocationStmt,
 		updateAssetMetadataStmt:             q.updateAssetMetadataStmt,
+		updateAssetPerceptualHashStmt:       q.updateAssetPerceptualHashStmt,
 		updateAssetScanStatusStmt:           q.updateAssetScanStatusStmt,
 		updateAssetTypeStmt:                 q.updateAssetTypeStmt,
 		updateAssetsLastScannedInFolderStmt: q.updateAssetsLastScannedInFolderStmt,
+		updateColorPaletteStmt:              q.updateColorPaletteStmt,
 		updateMaterialSetStmt:               q.updateMaterialSetStmt,
+		updateSavedSearchStmt:               q.updateSavedSearchStmt,
 		updateScanFolderLastScannedStmt:     q.updateScanFolderLastScannedStmt,
 		updateScanFolderStatusStmt:          q.updateScanFolderStatusStmt,
+		upsertAssetFeaturesStmt:             q.upsertAssetFeaturesStmt,
+		upsertAssetGroupStmt:                q.upsertAssetGroupStmt,
+		upsertModelMetadataStmt:             q.upsertModelMetadataStmt,
 	}
 }
@@ -113,7 +113,7 @@ func (q *Queries) GetMaterialSetById(ctx context.Context, id int64) (GetMaterial
 }
 
 const listAssetsInMaterialSet = `-- name: ListAssetsInMaterialSet :many
-SELECT a.id, a.scan_folder_id, a.group_id, a.file_name, a.file_path, a.file_type, a.file_size, a.thumbnail_path, a.rating, a.description, a.is_favorite, a.image_width, a.image_height, a.dominant_color, a.bit_depth, a.has_alpha_channel, a.date_added, a.last_scanned, a.last_modified, a.file_hash, a.is_deleted, a.deleted_at, a.is_hidden FROM assets a
+SELECT a.id, a.scan_folder_id, a.group_id, a.file_name, a.file_path, a.file_type, a.file_size, a.thumbnail_path, a.rating, a.description, a.is_favorite, a.image_width, a.image_height, a.dominant_color, a.bit_depth, a.has_alpha_channel, a.date_added, a.last_scanned, a.last_modified, a.file_hash, a.is_deleted, a.deleted_at, a.is_hidden, a.perceptual_hash, a.material_channel, a.analysis_version FROM assets a
 JOIN asset_material_sets ams ON a.id = ams.asset_id
 WHERE ams.material_set_id = ? AND a.is_deleted = 0
 ORDER BY a.date_added DESC
@@ -159,6 +159,9 @@ func (q *Queries) ListAssetsInMaterialSet(ctx context.Context, arg ListAssetsInM
 			&i.IsDeleted,
 			&i.DeletedAt,
 			&i.IsHidden,
+			&i.PerceptualHash,
+			&i.MaterialChannel,
+			&i.AnalysisVersion,
 		); err != nil {
 			return nil, err
 		}
@@ -173,6 +176,33 @@ func (q *Queries) ListAssetsInMaterialSet(ctx context.Context, arg ListAssetsInM
 	return items, nil
 }
 
+const listMaterialSetIDsByAsset = `-- name: ListMaterialSetIDsByAsset :many
+SELECT material_set_id FROM asset_material_sets WHERE asset_id = ? ORDER BY material_set_id
+`
+
+func (q *Queries) ListMaterialSetIDsByAsset(ctx context.Context, assetID int64) ([]int64, error) {
+	rows, err := q.query(ctx, q.listMaterialSetIDsByAssetStmt, listMaterialSetIDsByAsset, assetID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	var items []int64
+	for rows.Next() {
+		var material_set_id int64
+		if err := rows.Scan(&material_set_id); err != nil {
+			return nil, err
+		}
+		items = append(items, material_set_id)
+	}
+	if err := rows.Close(); err != nil {
+		return nil, err
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
 const listMaterialSets = `-- name: ListMaterialSets :many
 SELECT
     ms.id, ms.name, ms.description, ms.cover_asset_id, ms.custom_cover_url, ms.custom_color, ms.date_added, ms.last_modified,
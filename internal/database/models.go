@@ -33,6 +33,33 @@ type Asset struct {
 	IsDeleted       bool           `json:"isDeleted"`
 	DeletedAt       sql.NullTime   `json:"deletedAt"`
 	IsHidden        bool           `json:"isHidden"`
+	PerceptualHash  sql.NullString `json:"perceptualHash"`
+	MaterialChannel string         `json:"materialChannel"`
+	AnalysisVersion int64          `json:"analysisVersion"`
+}
+
+type AssetColor struct {
+	AssetID     int64   `json:"assetId"`
+	Position    int64   `json:"position"`
+	Hex         string  `json:"hex"`
+	PaletteName string  `json:"paletteName"`
+	PaletteHex  string  `json:"paletteHex"`
+	Weight      float64 `json:"weight"`
+	LabL        float64 `json:"labL"`
+	LabA        float64 `json:"labA"`
+	LabB        float64 `json:"labB"`
+}
+
+type AssetFeature struct {
+	AssetID        int64  `json:"assetId"`
+	ColorHistogram []byte `json:"colorHistogram"`
+}
+
+type AssetGroup struct {
+	GroupID          string        `json:"groupId"`
+	IsLocked         bool          `json:"isLocked"`
+	RepresentativeID sql.NullInt64 `json:"representativeId"`
+	UpdatedAt        time.Time     `json:"updatedAt"`
 }
 
 type AssetMaterialSet struct {
@@ -40,11 +67,32 @@ type AssetMaterialSet struct {
 	MaterialSetID int64 `json:"materialSetId"`
 }
 
+type AssetProperty struct {
+	AssetID int64  `json:"assetId"`
+	Key     string `json:"key"`
+	Value   string `json:"value"`
+}
+
 type AssetTag struct {
 	AssetID int64 `json:"assetId"`
 	TagID   int64 `json:"tagId"`
 }
 
+type AssetsFt struct {
+	FileName    string `json:"fileName"`
+	FilePath    string `json:"filePath"`
+	Description string `json:"description"`
+	Tags        string `json:"tags"`
+}
+
+type ColorPalette struct {
+	ID         int64     `json:"id"`
+	Name       string    `json:"name"`
+	ColorsJson string    `json:"colorsJson"`
+	IsActive   bool      `json:"isActive"`
+	DateAdded  time.Time `json:"dateAdded"`
+}
+
 type MaterialSet struct {
 	ID             int64          `json:"id"`
 	Name           string         `json:"name"`
@@ -56,6 +104,28 @@ type MaterialSet struct {
 	LastModified   time.Time      `json:"lastModified"`
 }
 
+type ModelMetadatum struct {
+	AssetID       int64   `json:"assetId"`
+	VertexCount   int64   `json:"vertexCount"`
+	TriangleCount int64   `json:"triangleCount"`
+	MeshCount     int64   `json:"meshCount"`
+	MaterialCount int64   `json:"materialCount"`
+	TextureCount  int64   `json:"textureCount"`
+	TextureFiles  string  `json:"textureFiles"`
+	SizeX         float64 `json:"sizeX"`
+	SizeY         float64 `json:"sizeY"`
+	SizeZ         float64 `json:"sizeZ"`
+}
+
+type OperationJournal struct {
+	ID          int64     `json:"id"`
+	Kind        string    `json:"kind"`
+	Description string    `json:"description"`
+	Payload     string    `json:"payload"`
+	IsUndone    bool      `json:"isUndone"`
+	CreatedAt   time.Time `json:"createdAt"`
+}
+
 type SavedSearch struct {
 	ID         int64     `json:"id"`
 	Name       string    `json:"name"`
@@ -82,3 +152,15 @@ type Tag struct {
 	Name        string    `json:"name"`
 	DateCreated time.Time `json:"dateCreated"`
 }
+
+type TrashedAsset struct {
+	ID                 int64     `json:"id"`
+	OriginalPath       string    `json:"originalPath"`
+	Backend            string    `json:"backend"`
+	TrashPath          string    `json:"trashPath"`
+	InfoPath           string    `json:"infoPath"`
+	AssetJson          string    `json:"assetJson"`
+	TagsJson           string    `json:"tagsJson"`
+	MaterialSetIdsJson string    `json:"materialSetIdsJson"`
+	DeletedAt          time.Time `json:"deletedAt"`
+}
@@ -10,29 +10,60 @@ import (
 )
 
 type Querier interface {
+	ActivateColorPalette(ctx context.Context, id int64) error
 	AddAssetToMaterialSet(ctx context.Context, arg AddAssetToMaterialSetParams) error
 	AddTagToAsset(ctx context.Context, arg AddTagToAssetParams) error
 	ClaimAssetsForPath(ctx context.Context, arg ClaimAssetsForPathParams) error
-	CleanupOldDeletedAssets(ctx context.Context) error
+	ClearJournal(ctx context.Context) error
 	ClearTagsForAsset(ctx context.Context, assetID int64) error
+	CopyAssetColors(ctx context.Context, arg CopyAssetColorsParams) error
+	CopyAssetFeatures(ctx context.Context, arg CopyAssetFeaturesParams) error
+	CopyAssetMaterialSets(ctx context.Context, arg CopyAssetMaterialSetsParams) error
+	CopyAssetProperties(ctx context.Context, arg CopyAssetPropertiesParams) error
+	CopyAssetTags(ctx context.Context, arg CopyAssetTagsParams) error
+	CopyModelMetadata(ctx context.Context, arg CopyModelMetadataParams) error
 	CreateAsset(ctx context.Context, arg CreateAssetParams) (Asset, error)
+	CreateColorPalette(ctx context.Context, arg CreateColorPaletteParams) (ColorPalette, error)
+	CreateJournalEntry(ctx context.Context, arg CreateJournalEntryParams) (OperationJournal, error)
 	CreateMaterialSet(ctx context.Context, arg CreateMaterialSetParams) (MaterialSet, error)
 	CreateSavedSearch(ctx context.Context, arg CreateSavedSearchParams) (SavedSearch, error)
 	CreateScanFolder(ctx context.Context, path string) (ScanFolder, error)
 	CreateTag(ctx context.Context, name string) (Tag, error)
+	CreateTrashedAsset(ctx context.Context, arg CreateTrashedAssetParams) (TrashedAsset, error)
+	DeactivateColorPalettes(ctx context.Context) error
 	DeleteAssetByFolder(ctx context.Context, scanFolderID sql.NullInt64) error
+	DeleteAssetColors(ctx context.Context, assetID int64) error
+	DeleteAssetFeatures(ctx context.Context, assetID int64) error
+	DeleteAssetGroup(ctx context.Context, groupID string) error
 	DeleteAssetPermanent(ctx context.Context, id int64) error
+	DeleteAssetProperties(ctx context.Context, assetID int64) error
+	DeleteAssetSearchIndex(ctx context.Context, rowid int64) error
+	DeleteColorPalette(ctx context.Context, id int64) error
 	DeleteMaterialSet(ctx context.Context, id int64) error
+	DeleteModelMetadata(ctx context.Context, assetID int64) error
 	DeleteSavedSearch(ctx context.Context, id int64) error
+	DeleteTrashedAsset(ctx context.Context, id int64) error
+	DeleteUndoneJournalEntries(ctx context.Context) error
 	FindPotentialSiblings(ctx context.Context, arg FindPotentialSiblingsParams) ([]FindPotentialSiblingsRow, error)
-	GetAllColors(ctx context.Context) ([]sql.NullString, error)
+	FindPotentialSiblingsInLibrary(ctx context.Context, arg FindPotentialSiblingsInLibraryParams) ([]FindPotentialSiblingsInLibraryRow, error)
+	GetActiveColorPalette(ctx context.Context) (ColorPalette, error)
+	GetAllColors(ctx context.Context, weight float64) ([]sql.NullString, error)
 	GetAllTags(ctx context.Context) ([]Tag, error)
 	GetAssetByHash(ctx context.Context, fileHash sql.NullString) (Asset, error)
 	GetAssetById(ctx context.Context, id int64) (Asset, error)
 	GetAssetByPath(ctx context.Context, filePath string) (Asset, error)
+	GetAssetFeatures(ctx context.Context, assetID int64) (GetAssetFeaturesRow, error)
+	GetAssetGroup(ctx context.Context, groupID string) (AssetGroup, error)
 	GetAssetsByGroupID(ctx context.Context, groupID string) ([]GetAssetsByGroupIDRow, error)
+	GetColorPaletteById(ctx context.Context, id int64) (ColorPalette, error)
+	GetColorPaletteByName(ctx context.Context, name string) (ColorPalette, error)
+	GetFirstUndoneJournalEntry(ctx context.Context) (OperationJournal, error)
+	GetLastActiveJournalEntry(ctx context.Context) (OperationJournal, error)
 	GetLibraryStats(ctx context.Context) (GetLibraryStatsRow, error)
 	GetMaterialSetById(ctx context.Context, id int64) (GetMaterialSetByIdRow, error)
+	GetModelMetadata(ctx context.Context, assetID int64) (ModelMetadatum, error)
+	GetSavedSearchById(ctx context.Context, id int64) (SavedSearch, error)
+	GetSavedSearchByName(ctx context.Context, name string) (SavedSearch, error)
 	GetScanFolderById(ctx context.Context, id int64) (ScanFolder, error)
 	GetScanFolderByPath(ctx context.Context, path string) (ScanFolder, error)
 	GetSidebarStats(ctx context.Context) (GetSidebarStatsRow, error)
@@ -40,18 +71,43 @@ type Querier interface {
 	GetTagByName(ctx context.Context, name string) (Tag, error)
 	GetTagsByAssetID(ctx context.Context, assetID int64) ([]Tag, error)
 	GetTagsNamesByAssetID(ctx context.Context, assetID int64) ([]string, error)
+	GetTrashedAsset(ctx context.Context, id int64) (TrashedAsset, error)
+	IndexAssetForSearch(ctx context.Context, id int64) error
+	InsertAssetColor(ctx context.Context, arg InsertAssetColorParams) error
+	InsertAssetProperty(ctx context.Context, arg InsertAssetPropertyParams) error
+	IsGroupLocked(ctx context.Context, groupID string) (int64, error)
+	ListAssetColors(ctx context.Context, assetID int64) ([]AssetColor, error)
+	ListAssetColorsPage(ctx context.Context, arg ListAssetColorsPageParams) ([]ListAssetColorsPageRow, error)
+	ListAssetFeatures(ctx context.Context) ([]ListAssetFeaturesRow, error)
+	ListAssetProperties(ctx context.Context, assetID int64) ([]AssetProperty, error)
 	ListAssets(ctx context.Context, arg ListAssetsParams) ([]Asset, error)
+	ListAssetsByHash(ctx context.Context, fileHash sql.NullString) ([]ListAssetsByHashRow, error)
+	ListAssetsByIDs(ctx context.Context, ids []int64) ([]Asset, error)
 	ListAssetsForCache(ctx context.Context) ([]ListAssetsForCacheRow, error)
+	ListAssetsForGrouping(ctx context.Context) ([]ListAssetsForGroupingRow, error)
 	ListAssetsInMaterialSet(ctx context.Context, arg ListAssetsInMaterialSetParams) ([]Asset, error)
+	ListColorPalettes(ctx context.Context) ([]ColorPalette, error)
 	ListDeletedAssets(ctx context.Context, arg ListDeletedAssetsParams) ([]Asset, error)
+	ListDuplicateAssets(ctx context.Context) ([]ListDuplicateAssetsRow, error)
+	ListExpiredDeletedAssetIDs(ctx context.Context) ([]int64, error)
+	ListExpiredTrashedAssets(ctx context.Context) ([]TrashedAsset, error)
 	ListFavoriteAssets(ctx context.Context, arg ListFavoriteAssetsParams) ([]Asset, error)
+	ListGroupMaterialChannels(ctx context.Context, groupID string) ([]ListGroupMaterialChannelsRow, error)
 	ListHiddenAssets(ctx context.Context, arg ListHiddenAssetsParams) ([]Asset, error)
+	ListJournalEntries(ctx context.Context, limit int64) ([]OperationJournal, error)
+	ListLegacyDominantColors(ctx context.Context) ([]ListLegacyDominantColorsRow, error)
+	ListLockedGroupIDs(ctx context.Context) ([]string, error)
+	ListMaterialSetIDsByAsset(ctx context.Context, assetID int64) ([]int64, error)
 	ListMaterialSets(ctx context.Context) ([]ListMaterialSetsRow, error)
+	ListPerceptualHashes(ctx context.Context) ([]ListPerceptualHashesRow, error)
 	ListSavedSearches(ctx context.Context) ([]SavedSearch, error)
 	ListScanFolders(ctx context.Context) ([]ScanFolder, error)
 	ListTags(ctx context.Context) ([]ListTagsRow, error)
+	ListTrashedAssets(ctx context.Context) ([]TrashedAsset, error)
 	ListUntaggedAssets(ctx context.Context, arg ListUntaggedAssetsParams) ([]Asset, error)
 	MoveAssetsToFolder(ctx context.Context, arg MoveAssetsToFolderParams) error
+	PruneJournal(ctx context.Context, limit int64) error
+	ReassignAssetGroup(ctx context.Context, arg ReassignAssetGroupParams) error
 	RefreshAssetTechnicalMetadata(ctx context.Context, arg RefreshAssetTechnicalMetadataParams) error
 	RemoveAssetFromMaterialSet(ctx context.Context, arg RemoveAssetFromMaterialSetParams) error
 	RemoveTagFromAsset(ctx context.Context, arg RemoveTagFromAssetParams) error
@@ -62,20 +118,30 @@ type Querier interface {
 	SetAssetHidden(ctx context.Context, arg SetAssetHiddenParams) error
 	SetAssetRating(ctx context.Context, arg SetAssetRatingParams) error
 	SetAssetsHiddenByFolderId(ctx context.Context, arg SetAssetsHiddenByFolderIdParams) error
+	SetJournalEntryUndone(ctx context.Context, arg SetJournalEntryUndoneParams) error
 	SetSystemSetting(ctx context.Context, arg SetSystemSettingParams) error
 	SoftDeleteAsset(ctx context.Context, id int64) error
 	SoftDeleteAssets(ctx context.Context, ids []int64) error
 	SoftDeleteScanFolder(ctx context.Context, id int64) error
 	ToggleAssetFavorite(ctx context.Context, id int64) error
+	UpdateAssetColorPalette(ctx context.Context, arg UpdateAssetColorPaletteParams) error
+	UpdateAssetDominantColor(ctx context.Context, arg UpdateAssetDominantColorParams) error
 	UpdateAssetFromScan(ctx context.Context, arg UpdateAssetFromScanParams) (Asset, error)
+	UpdateAssetGroup(ctx context.Context, arg UpdateAssetGroupParams) error
 	UpdateAssetLocation(ctx context.Context, arg UpdateAssetLocationParams) error
 	UpdateAssetMetadata(ctx context.Context, arg UpdateAssetMetadataParams) (Asset, error)
+	UpdateAssetPerceptualHash(ctx context.Context, arg UpdateAssetPerceptualHashParams) error
 	UpdateAssetScanStatus(ctx context.Context, arg UpdateAssetScanStatusParams) error
 	UpdateAssetType(ctx context.Context, arg UpdateAssetTypeParams) error
 	UpdateAssetsLastScannedInFolder(ctx context.Context, arg UpdateAssetsLastScannedInFolderParams) error
+	UpdateColorPalette(ctx context.Context, arg UpdateColorPaletteParams) (ColorPalette, error)
 	UpdateMaterialSet(ctx context.Context, arg UpdateMaterialSetParams) error
+	UpdateSavedSearch(ctx context.Context, arg UpdateSavedSearchParams) (SavedSearch, error)
 	UpdateScanFolderLastScanned(ctx context.Context, arg UpdateScanFolderLastScannedParams) error
 	UpdateScanFolderStatus(ctx context.Context, arg UpdateScanFolderStatusParams) error
+	UpsertAssetFeatures(ctx context.Context, arg UpsertAssetFeaturesParams) error
+	UpsertAssetGroup(ctx context.Context, arg UpsertAssetGroupParams) error
+	UpsertModelMetadata(ctx context.Context, arg UpsertModelMetadataParams) error
 }
 
 var _ Querier = (*Queries)(nil)
@@ -7,6 +7,7 @@ package database
 
 import (
 	"context"
+	"database/sql"
 )
 
 const createSavedSearch = `-- name: CreateSavedSearch :one
@@ -39,6 +40,38 @@ func (q *Queries) DeleteSavedSearch(ctx context.Context, id int64) error {
 	return err
 }
 
+const getSavedSearchById = `-- name: GetSavedSearchById :one
+SELECT id, name, filter_json, date_added FROM saved_searches WHERE id = ? LIMIT 1
+`
+
+func (q *Queries) GetSavedSearchById(ctx context.Context, id int64) (SavedSearch, error) {
+	row := q.queryRow(ctx, q.getSavedSearchByIdStmt, getSavedSearchById, id)
+	var i SavedSearch
+	err := row.Scan(
+		&i.ID,
+		&i.Name,
+		&i.FilterJson,
+		&i.DateAdded,
+	)
+	return i, err
+}
+
+const getSavedSearchByName = `-- name: GetSavedSearchByName :one
+SELECT id, name, filter_json, date_added FROM saved_searches WHERE name = ? LIMIT 1
+`
+
+func (q *Queries) GetSavedSearchByName(ctx context.Context, name string) (SavedSearch, error) {
+	row := q.queryRow(ctx, q.getSavedSearchByNameStmt, getSavedSearchByName, name)
+	var i SavedSearch
+	err := row.Scan(
+		&i.ID,
+		&i.Name,
+		&i.FilterJson,
+		&i.DateAdded,
+	)
+	return i, err
+}
+
 const listSavedSearches = `-- name: ListSavedSearches :many
 SELECT id, name, filter_json, date_added FROM saved_searches ORDER BY name
 `
@@ -70,3 +103,30 @@ func (q *Queries) ListSavedSearches(ctx context.Context) ([]SavedSearch, error)
 	}
 	return items, nil
 }
+
+const updateSavedSearch = `-- name: UpdateSavedSearch :one
+UPDATE saved_searches
+SET
+    name = COALESCE(?1, name),
+    filter_json = COALESCE(?2, filter_json)
+WHERE id = ?3
+RETURNING id, name, filter_json, date_added
+`
+
+type UpdateSavedSearchParams struct {
+	Name       sql.NullString `json:"name"`
+	FilterJson sql.NullString `json:"filterJson"`
+	ID         int64          `json:"id"`
+}
+
+func (q *Queries) UpdateSavedSearch(ctx context.Context, arg UpdateSavedSearchParams) (SavedSearch, error) {
+	row := q.queryRow(ctx, q.updateSavedSearchStmt, updateSavedSearch, arg.Name, arg.FilterJson, arg.ID)
+	var i SavedSearch
+	err := row.Scan(
+		&i.ID,
+		&i.Name,
+		&i.FilterJson,
+		&i.DateAdded,
+	)
+	return i, err
+}
@@ -0,0 +1,18 @@
+package database
+
+import (
+	"context"
+	"fmt"
+)
+
+// ReindexAssetSearch replaces the full-text search entry of a single asset, e.g. after its
+// name, path, description or tags change.
+func ReindexAssetSearch(ctx context.Context, q Querier, assetID int64) error {
+	if err := q.DeleteAssetSearchIndex(ctx, assetID); err != nil {
+		return fmt.Errorf("failed to clear search index entry: %w", err)
+	}
+	if err := q.IndexAssetForSearch(ctx, assetID); err != nil {
+		return fmt.Errorf("failed to index asset for search: %w", err)
+	}
+	return nil
+}
@@ -24,10 +24,22 @@ type ToastField struct {
 }
 
 // ScanProgressDTO defines the data structure for progress updates sent to the frontend.
+// Current counts every file handled so far; Processed and Skipped split it into files that were
+// hashed and analysed and unchanged files taken over from the previous scan.
 type ScanProgressDTO struct {
-	Current  int    `json:"current"`
-	Total    int    `json:"total"`
-	LastFile string `json:"lastFile"`
+	Current   int    `json:"current"`
+	Total     int    `json:"total"`
+	Processed int    `json:"processed"`
+	Skipped   int    `json:"skipped"`
+	LastFile  string `json:"lastFile"`
+}
+
+// FileOperationProgressDTO reports the progress of moving or copying assets from within the app.
+type FileOperationProgressDTO struct {
+	Operation string `json:"operation"` // "move" or "copy"
+	Current   int    `json:"current"`
+	Total     int    `json:"total"`
+	LastFile  string `json:"lastFile"`
 }
 
 // Notifier defines the interface for sending notifications and updates to the user interface.
@@ -35,9 +47,11 @@ type Notifier interface {
 	// SendToast sends a temporary popup notification.
 	SendToast(ctx context.Context, msg ToastField)
 	// SendScanProgress updates the progress bar with current scan statistics.
-	SendScanProgress(ctx context.Context, current, total int, lastFile string)
+	SendScanProgress(ctx context.Context, progress ScanProgressDTO)
 	// SendScannerStatus updates the overall status of the scanner (e.g., Idle, Scanning).
 	SendScannerStatus(ctx context.Context, status Status)
+	// SendFileOperationProgress updates the progress of moving or copying assets.
+	SendFileOperationProgress(ctx context.Context, progress FileOperationProgressDTO)
 	// EmitAssetsChanged signals that the asset library has changed and views should refresh.
 	EmitAssetsChanged(ctx context.Context)
 }
@@ -67,16 +81,19 @@ func (n *WailsNotifier) SendScannerStatus(ctx context.Context, status Status) {
 }
 
 // SendScanProgress emits a "scan_progress" event to the frontend.
-func (n *WailsNotifier) SendScanProgress(ctx context.Context, current, total int, lastFile string) {
+func (n *WailsNotifier) SendScanProgress(ctx context.Context, progress ScanProgressDTO) {
 	if ctx == nil {
 		return
 	}
-	payload := ScanProgressDTO{
-		Current:  current,
-		Total:    total,
-		LastFile: lastFile,
+	runtime.EventsEmit(ctx, "scan_progress", progress)
+}
+
+// SendFileOperationProgress emits a "file_operation_progress" event to the frontend.
+func (n *WailsNotifier) SendFileOperationProgress(ctx context.Context, progress FileOperationProgressDTO) {
+	if ctx == nil {
+		return
 	}
-	runtime.EventsEmit(ctx, "scan_progress", payload)
+	runtime.EventsEmit(ctx, "file_operation_progress", progress)
 }
 
 // EmitAssetsChanged emits an "assets:changed" event to trigger a frontend refresh.
@@ -0,0 +1,8 @@
+//go:build !windows
+
+package fsutil
+
+import "syscall"
+
+// errCrossDevice is the error a rename returns when src and dst are on different file systems.
+const errCrossDevice = syscall.EXDEV
@@ -0,0 +1,7 @@
+package fsutil
+
+import "syscall"
+
+// errCrossDevice is ERROR_NOT_SAME_DEVICE, returned by MoveFileEx when src and dst are on
+// different volumes.
+const errCrossDevice = syscall.Errno(17)
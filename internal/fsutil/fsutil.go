@@ -0,0 +1,62 @@
+// Package fsutil moves and copies library files, also across file systems.
+package fsutil
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+)
+
+// MoveFile renames src to dst, copying the file when they are on different file systems.
+// Other rename errors are returned as is. dst must not exist; a rename would replace it on
+// some platforms.
+func MoveFile(src, dst string) error {
+	renameErr := os.Rename(src, dst)
+	if renameErr == nil {
+		return nil
+	}
+	if !errors.Is(renameErr, errCrossDevice) {
+		return renameErr
+	}
+	if err := CopyFile(src, dst); err != nil {
+		return renameErr
+	}
+	if err := os.Remove(src); err != nil {
+		os.Remove(dst)
+		return err
+	}
+	return nil
+}
+
+// CopyFile copies a regular file with its permissions and modification time. It fails if dst
+// exists.
+func CopyFile(src, dst string) error {
+	in, err := os.Open(src)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+	info, err := in.Stat()
+	if err != nil {
+		return err
+	}
+	if !info.Mode().IsRegular() {
+		return fmt.Errorf("%s is not a regular file", src)
+	}
+
+	out, err := os.OpenFile(dst, os.O_WRONLY|os.O_CREATE|os.O_EXCL, info.Mode().Perm())
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(out, in); err != nil {
+		out.Close()
+		os.Remove(dst)
+		return err
+	}
+	if err := out.Close(); err != nil {
+		os.Remove(dst)
+		return err
+	}
+	return os.Chtimes(dst, info.ModTime(), info.ModTime())
+}
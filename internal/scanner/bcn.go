@@ -0,0 +1,115 @@
+package scanner
+
+import (
+	"encoding/binary"
+	"image/color"
+	"math"
+)
+
+// Block compressed (BCn / DXTn) textures store 4x4 pixel blocks of 8 or 16 bytes.
+// The decoders fill dst in row-major order.
+
+// decodeBC1 decodes a DXT1 block. In the three color mode (color0 <= color1) index 3 is
+// transparent black; BC2 and BC3 always use the four color mode (fourColor).
+func decodeBC1(src []byte, dst *[16]color.NRGBA, fourColor bool) {
+	c0 := binary.LittleEndian.Uint16(src[0:])
+	c1 := binary.LittleEndian.Uint16(src[2:])
+	r0, g0, b0 := rgb565(c0)
+	r1, g1, b1 := rgb565(c1)
+
+	var palette [4]color.NRGBA
+	palette[0] = color.NRGBA{R: r0, G: g0, B: b0, A: 255}
+	palette[1] = color.NRGBA{R: r1, G: g1, B: b1, A: 255}
+	if fourColor || c0 > c1 {
+		palette[2] = color.NRGBA{R: bcLerp3(r0, r1), G: bcLerp3(g0, g1), B: bcLerp3(b0, b1), A: 255}
+		palette[3] = color.NRGBA{R: bcLerp3(r1, r0), G: bcLerp3(g1, g0), B: bcLerp3(b1, b0), A: 255}
+	} else {
+		palette[2] = color.NRGBA{R: bcAverage(r0, r1), G: bcAverage(g0, g1), B: bcAverage(b0, b1), A: 255}
+		palette[3] = color.NRGBA{}
+	}
+
+	indices := binary.LittleEndian.Uint32(src[4:])
+	for i := range dst {
+		dst[i] = palette[indices>>(2*i)&3]
+	}
+}
+
+// decodeBC2 decodes a DXT2/DXT3 block: explicit 4-bit alpha followed by a BC1 color block.
+func decodeBC2(src []byte, dst *[16]color.NRGBA) {
+	decodeBC1(src[8:], dst, true)
+	alpha := binary.LittleEndian.Uint64(src)
+	for i := range dst {
+		a := uint8(alpha >> (4 * i) & 0xf)
+		dst[i].A = a<<4 | a
+	}
+}
+
+// decodeBC3 decodes a DXT4/DXT5 block: interpolated alpha followed by a BC1 color block.
+func decodeBC3(src []byte, dst *[16]color.NRGBA) {
+	decodeBC1(src[8:], dst, true)
+	alpha := decodeBC4(src, false)
+	for i := range dst {
+		dst[i].A = alpha[i]
+	}
+}
+
+// decodeBC4 decodes a single channel block (also the alpha block of BC3). Signed blocks
+// are mapped from [-1, 1] to [0, 255].
+func decodeBC4(src []byte, signed bool) [16]uint8 {
+	var palette [8]float64
+	e0, e1 := float64(src[0]), float64(src[1])
+	lo, hi := 0.0, 255.0
+	if signed {
+		e0, e1 = math.Max(-127, float64(int8(src[0]))), math.Max(-127, float64(int8(src[1])))
+		lo, hi = -127, 127
+	}
+	palette[0], palette[1] = e0, e1
+	if e0 > e1 {
+		for i := 1; i < 7; i++ {
+			palette[i+1] = (float64(7-i)*e0 + float64(i)*e1) / 7
+		}
+	} else {
+		for i := 1; i < 5; i++ {
+			palette[i+1] = (float64(5-i)*e0 + float64(i)*e1) / 5
+		}
+		palette[6], palette[7] = lo, hi
+	}
+
+	var out [16]uint8
+	bits := uint64(src[2]) | uint64(src[3])<<8 | uint64(src[4])<<16 | uint64(src[5])<<24 | uint64(src[6])<<32 | uint64(src[7])<<40
+	for i := range out {
+		v := palette[bits>>(3*i)&7]
+		if signed {
+			v = (v + 127) * 255 / 254
+		}
+		out[i] = uint8(v + 0.5)
+	}
+	return out
+}
+
+// decodeBC5 decodes a two channel block. Two channel textures are almost always tangent space
+// normal maps, so blue is reconstructed as the Z component to make the preview look familiar.
+func decodeBC5(src []byte, dst *[16]color.NRGBA, signed bool) {
+	red := decodeBC4(src[0:8], signed)
+	green := decodeBC4(src[8:16], signed)
+	for i := range dst {
+		x := float64(red[i])/127.5 - 1
+		y := float64(green[i])/127.5 - 1
+		z := math.Sqrt(math.Max(0, 1-x*x-y*y))
+		dst[i] = color.NRGBA{R: red[i], G: green[i], B: uint8((z+1)*127.5 + 0.5), A: 255}
+	}
+}
+
+func rgb565(c uint16) (uint8, uint8, uint8) {
+	r, g, b := c>>11&0x1f, c>>5&0x3f, c&0x1f
+	return uint8(r<<3 | r>>2), uint8(g<<2 | g>>4), uint8(b<<3 | b>>2)
+}
+
+// bcLerp3 returns (2a + b) / 3.
+func bcLerp3(a, b uint8) uint8 {
+	return uint8((2*int(a) + int(b) + 1) / 3)
+}
+
+func bcAverage(a, b uint8) uint8 {
+	return uint8((int(a) + int(b)) / 2)
+}
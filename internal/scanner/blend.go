@@ -0,0 +1,217 @@
+package scanner
+
+import (
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"eclat/internal/zstd"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"image"
+	"io"
+	"os"
+	"strconv"
+)
+
+const (
+	blendMagic = "BLENDER"
+	// blendThumbMaxSize bounds the embedded preview; Blender writes 128x128 (256x256 in newer versions).
+	blendThumbMaxSize = 1024
+)
+
+var (
+	gzipMagic = []byte{0x1f, 0x8b}
+	zstdMagic = []byte{0x28, 0xb5, 0x2f, 0xfd}
+)
+
+// ErrBlendNoThumbnail is returned when a .blend file was saved without a preview (or by a
+// Blender too old to write one). The header is still valid.
+var ErrBlendNoThumbnail = errors.New("blend file has no embedded thumbnail")
+
+// BlendInfo describes the header of a .blend file.
+type BlendInfo struct {
+	Version     string // Blender version that saved the file, e.g. "4.2"
+	PointerSize int    // 4 or 8 bytes
+	BigEndian   bool
+	Compression string // "none", "gzip" or "zstd"
+}
+
+// Properties returns the details stored as asset properties.
+func (i BlendInfo) Properties() map[string]string {
+	return map[string]string{
+		PropertyBlenderVersion: i.Version,
+		PropertyCompression:    i.Compression,
+	}
+}
+
+func isBlendExt(ext string) bool {
+	return ext == ".blend" || ext == ".blend1"
+}
+
+// ReadBlendFile reads the header and the embedded thumbnail (the TEST block) of a .blend file.
+// Files compressed by Blender (gzip before 3.0, Zstandard since) are decompressed on the fly;
+// only the blocks up to the thumbnail are read. Without a thumbnail, the info is returned with
+// ErrBlendNoThumbnail.
+func ReadBlendFile(path string) (image.Image, BlendInfo, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, BlendInfo{}, err
+	}
+	defer f.Close()
+	return ReadBlend(f)
+}
+
+// ReadBlend reads a .blend stream; see ReadBlendFile.
+func ReadBlend(r io.Reader) (image.Image, BlendInfo, error) {
+	br := bufio.NewReader(r)
+	magic, _ := br.Peek(4)
+
+	var info BlendInfo
+	var src io.Reader = br
+	switch {
+	case bytes.HasPrefix(magic, zstdMagic):
+		info.Compression = "zstd"
+		src = zstd.NewReader(br)
+	case bytes.HasPrefix(magic, gzipMagic):
+		info.Compression = "gzip"
+		zr, err := gzip.NewReader(br)
+		if err != nil {
+			return nil, info, fmt.Errorf("opening gzip stream: %w", err)
+		}
+		defer zr.Close()
+		src = zr
+	default:
+		info.Compression = "none"
+	}
+
+	headSize, err := readBlendHeader(src, &info)
+	if err != nil {
+		return nil, info, err
+	}
+	order := binary.ByteOrder(binary.LittleEndian)
+	if info.BigEndian {
+		order = binary.BigEndian
+	}
+
+	head := make([]byte, headSize)
+	for {
+		if _, err := io.ReadFull(src, head); err != nil {
+			return nil, info, fmt.Errorf("reading block header: %w", err)
+		}
+		code := string(head[:4])
+		var length int64
+		switch headSize {
+		case 20, 24: // BHead4, SmallBHead8: code, len, old pointer, SDNA index, count
+			length = int64(int32(order.Uint32(head[4:])))
+		case 32: // LargeBHead8: code, SDNA index, old pointer, len, count
+			length = int64(order.Uint64(head[16:]))
+		}
+		if length < 0 {
+			return nil, info, fmt.Errorf("invalid length of block %q", code)
+		}
+
+		switch code {
+		case "TEST":
+			img, err := readBlendThumbnail(src, length, order)
+			return img, info, err
+		case "GLOB", "DNA1", "ENDB":
+			// Blender writes the thumbnail before the global block; past it there is none.
+			return nil, info, ErrBlendNoThumbnail
+		}
+		if _, err := io.CopyN(io.Discard, src, length); err != nil {
+			return nil, info, fmt.Errorf("skipping block %q: %w", code, err)
+		}
+	}
+}
+
+// readBlendHeader parses the file header and returns the size of the block headers that follow.
+// Before Blender 5.0 the header is 12 bytes ("BLENDER-v402"), since then it starts with its own
+// size and carries a file format version ("BLENDER17-01v0500").
+func readBlendHeader(r io.Reader, info *BlendInfo) (int, error) {
+	var hdr [12]byte
+	if _, err := io.ReadFull(r, hdr[:]); err != nil {
+		return 0, fmt.Errorf("reading blend header: %w", err)
+	}
+	if string(hdr[:7]) != blendMagic {
+		return 0, errors.New("not a blend file")
+	}
+
+	if hdr[7] == '_' || hdr[7] == '-' {
+		if err := parseBlendHeaderFields(info, hdr[7], hdr[8], string(hdr[9:12])); err != nil {
+			return 0, err
+		}
+		if info.PointerSize == 4 {
+			return 20, nil
+		}
+		return 24, nil
+	}
+
+	size, err := strconv.Atoi(string(hdr[7:9]))
+	if err != nil || size < 17 {
+		return 0, fmt.Errorf("invalid blend header %q", hdr[:])
+	}
+	full := make([]byte, size)
+	copy(full, hdr[:])
+	if _, err := io.ReadFull(r, full[len(hdr):]); err != nil {
+		return 0, fmt.Errorf("reading blend header: %w", err)
+	}
+	if format := string(full[10:12]); format != "01" {
+		return 0, fmt.Errorf("unsupported blend file format %s", format)
+	}
+	if err := parseBlendHeaderFields(info, full[9], full[12], string(full[13:17])); err != nil {
+		return 0, err
+	}
+	if info.PointerSize != 8 {
+		return 0, errors.New("invalid pointer size in blend header")
+	}
+	return 32, nil
+}
+
+func parseBlendHeaderFields(info *BlendInfo, pointer, endian byte, version string) error {
+	switch pointer {
+	case '_':
+		info.PointerSize = 4
+	case '-':
+		info.PointerSize = 8
+	default:
+		return fmt.Errorf("invalid pointer size %q in blend header", pointer)
+	}
+	switch endian {
+	case 'v':
+	case 'V':
+		info.BigEndian = true
+	default:
+		return fmt.Errorf("invalid endianness %q in blend header", endian)
+	}
+	v, err := strconv.Atoi(version)
+	if err != nil {
+		return fmt.Errorf("invalid version %q in blend header", version)
+	}
+	// "279" is 2.79, "402" is 4.2, "0500" is 5.0
+	info.Version = fmt.Sprintf("%d.%d", v/100, v%100)
+	return nil
+}
+
+// readBlendThumbnail decodes the TEST block: width and height as ints, then RGBA rows from the bottom up.
+func readBlendThumbnail(r io.Reader, length int64, order binary.ByteOrder) (image.Image, error) {
+	var size [8]byte
+	if length < 8 {
+		return nil, errors.New("truncated blend thumbnail")
+	}
+	if _, err := io.ReadFull(r, size[:]); err != nil {
+		return nil, fmt.Errorf("reading blend thumbnail: %w", err)
+	}
+	w, h := int(int32(order.Uint32(size[:]))), int(int32(order.Uint32(size[4:])))
+	if w <= 0 || h <= 0 || w > blendThumbMaxSize || h > blendThumbMaxSize || int64(w*h*4) > length-8 {
+		return nil, fmt.Errorf("invalid blend thumbnail size %dx%d", w, h)
+	}
+
+	img := image.NewNRGBA(image.Rect(0, 0, w, h))
+	for y := h - 1; y >= 0; y-- {
+		if _, err := io.ReadFull(r, img.Pix[y*img.Stride:y*img.Stride+w*4]); err != nil {
+			return nil, fmt.Errorf("reading blend thumbnail: %w", err)
+		}
+	}
+	return img, nil
+}
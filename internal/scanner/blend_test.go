@@ -0,0 +1,252 @@
+package scanner
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"encoding/binary"
+	"image"
+	"image/color"
+	"io"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// testBlend buduje minimalny plik .blend: nagłówek, blok REND, opcjonalnie TEST z miniaturą, GLOB i ENDB.
+// Długość nagłówka wybiera układ nagłówków bloków (12 bajtów: BHead4/SmallBHead8, 17: LargeBHead8).
+func testBlend(header string, thumb *image.NRGBA) []byte {
+	var order binary.AppendByteOrder = binary.LittleEndian
+	endian := header[8]
+	if len(header) > 12 {
+		endian = header[12]
+	}
+	if endian == 'V' {
+		order = binary.BigEndian
+	}
+	out := []byte(header)
+	block := func(code string, data []byte) {
+		out = append(out, code...)
+		switch {
+		case len(header) > 12: // LargeBHead8
+			out = order.AppendUint32(out, 0)
+			out = order.AppendUint64(out, 0x1000)
+			out = order.AppendUint64(out, uint64(len(data)))
+			out = order.AppendUint64(out, 1)
+		case header[7] == '_': // BHead4
+			out = order.AppendUint32(out, uint32(len(data)))
+			out = order.AppendUint32(out, 0x1000)
+			out = order.AppendUint32(out, 0)
+			out = order.AppendUint32(out, 1)
+		default: // SmallBHead8
+			out = order.AppendUint32(out, uint32(len(data)))
+			out = order.AppendUint64(out, 0x1000)
+			out = order.AppendUint32(out, 0)
+			out = order.AppendUint32(out, 1)
+		}
+		out = append(out, data...)
+	}
+
+	block("REND", make([]byte, 72))
+	if thumb != nil {
+		w, h := thumb.Rect.Dx(), thumb.Rect.Dy()
+		data := order.AppendUint32(nil, uint32(w))
+		data = order.AppendUint32(data, uint32(h))
+		// Blender zapisuje wiersze od dołu.
+		for y := h - 1; y >= 0; y-- {
+			data = append(data, thumb.Pix[y*thumb.Stride:y*thumb.Stride+w*4]...)
+		}
+		block("TEST", data)
+	}
+	block("GLOB", make([]byte, 16))
+	block("ENDB", nil)
+	return out
+}
+
+// testBlendThumb to miniatura 4x2: czerwona górna połowa, niebieska dolna.
+func testBlendThumb() *image.NRGBA {
+	img := image.NewNRGBA(image.Rect(0, 0, 4, 2))
+	for x := 0; x < 4; x++ {
+		img.SetNRGBA(x, 0, color.NRGBA{R: 255, A: 255})
+		img.SetNRGBA(x, 1, color.NRGBA{B: 255, A: 255})
+	}
+	return img
+}
+
+// testBlendGradient to miniatura 64x64 z gradientem - dość danych, by koder zstd użył
+// bloków skompresowanych (Huffman, FSE, sekwencje), a nie surowych.
+func testBlendGradient() *image.NRGBA {
+	img := image.NewNRGBA(image.Rect(0, 0, 64, 64))
+	for y := 0; y < 64; y++ {
+		for x := 0; x < 64; x++ {
+			img.SetNRGBA(x, y, color.NRGBA{R: uint8(x * 4), G: uint8(y * 4), B: uint8((x + y) * 2), A: 255})
+		}
+	}
+	return img
+}
+
+func gzipBytes(t *testing.T, data []byte) []byte {
+	var buf bytes.Buffer
+	zw := gzip.NewWriter(&buf)
+	_, err := zw.Write(data)
+	require.NoError(t, err)
+	require.NoError(t, zw.Close())
+	return buf.Bytes()
+}
+
+// zstdRawFrame pakuje dane w ramkę Zstandard z samych nieskompresowanych bloków.
+func zstdRawFrame(data []byte) []byte {
+	out := []byte{0x28, 0xb5, 0x2f, 0xfd, 0x00, 0x50} // bez rozmiaru treści, okno 1 MiB
+	for {
+		n := min(len(data), 1<<16)
+		last := uint32(0)
+		if n == len(data) {
+			last = 1
+		}
+		hdr := last | uint32(n)<<3 // typ bloku 0: raw
+		out = append(out, byte(hdr), byte(hdr>>8), byte(hdr>>16))
+		out = append(out, data[:n]...)
+		data = data[n:]
+		if last == 1 {
+			return out
+		}
+	}
+}
+
+func TestReadBlend(t *testing.T) {
+	thumb := testBlendThumb()
+	tests := []struct {
+		name     string
+		data     []byte
+		expected BlendInfo
+	}{
+		{
+			name:     "Uncompressed 64-bit",
+			data:     testBlend("BLENDER-v402", thumb),
+			expected: BlendInfo{Version: "4.2", PointerSize: 8, Compression: "none"},
+		},
+		{
+			name:     "Big endian 32-bit",
+			data:     testBlend("BLENDER_V279", thumb),
+			expected: BlendInfo{Version: "2.79", PointerSize: 4, BigEndian: true, Compression: "none"},
+		},
+		{
+			name:     "Gzip",
+			data:     gzipBytes(t, testBlend("BLENDER-v293", thumb)),
+			expected: BlendInfo{Version: "2.93", PointerSize: 8, Compression: "gzip"},
+		},
+		{
+			name:     "Zstandard",
+			data:     zstdRawFrame(testBlend("BLENDER-v306", thumb)),
+			expected: BlendInfo{Version: "3.6", PointerSize: 8, Compression: "zstd"},
+		},
+		{
+			name:     "Blender 5 large block headers",
+			data:     zstdRawFrame(testBlend("BLENDER17-01v0500", thumb)),
+			expected: BlendInfo{Version: "5.0", PointerSize: 8, Compression: "zstd"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			img, info, err := ReadBlend(bytes.NewReader(tt.data))
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, info)
+			require.Equal(t, thumb.Bounds(), img.Bounds())
+			assert.Equal(t, thumb.Pix, img.(*image.NRGBA).Pix)
+		})
+	}
+}
+
+// testdata/gradient-zstd.blend to testBlend("BLENDER-v405", testBlendGradient()) spakowany
+// koderem zstd (zstd -19), więc przechodzi przez ścieżki Huffmana, FSE i sekwencji dekodera.
+func TestReadBlend_ZstdFixture(t *testing.T) {
+	f, err := os.Open(filepath.Join("testdata", "gradient-zstd.blend"))
+	require.NoError(t, err)
+	defer f.Close()
+
+	img, info, err := ReadBlend(f)
+	require.NoError(t, err)
+	assert.Equal(t, BlendInfo{Version: "4.5", PointerSize: 8, Compression: "zstd"}, info)
+	thumb := testBlendGradient()
+	require.Equal(t, thumb.Bounds(), img.Bounds())
+	assert.Equal(t, thumb.Pix, img.(*image.NRGBA).Pix)
+}
+
+func TestReadBlend_NoThumbnail(t *testing.T) {
+	img, info, err := ReadBlend(bytes.NewReader(testBlend("BLENDER-v401", nil)))
+	assert.ErrorIs(t, err, ErrBlendNoThumbnail)
+	assert.Nil(t, img)
+	assert.Equal(t, "4.1", info.Version)
+}
+
+func TestReadBlend_InvalidInput(t *testing.T) {
+	valid := testBlend("BLENDER-v402", testBlendThumb())
+	huge := testBlend("BLENDER-v402", image.NewNRGBA(image.Rect(0, 0, 1, 1)))
+	// Podmiana wymiarów miniatury (REND: 24+72 bajty, nagłówek TEST: 24) na 4096x4096.
+	binary.LittleEndian.PutUint32(huge[12+96+24:], 4096)
+	binary.LittleEndian.PutUint32(huge[12+96+28:], 4096)
+
+	for name, data := range map[string][]byte{
+		"Not blend":         append([]byte("BLENDOR"), valid[7:]...),
+		"Bad version":       append([]byte("BLENDER-vx02"), valid[12:]...),
+		"Unknown format":    []byte("BLENDER17-02v0500"),
+		"Truncated":         valid[:60],
+		"Oversized preview": huge,
+		"Corrupt gzip":      append([]byte{0x1f, 0x8b}, valid...),
+	} {
+		t.Run(name, func(t *testing.T) {
+			_, _, err := ReadBlend(bytes.NewReader(data))
+			assert.Error(t, err)
+			assert.NotErrorIs(t, err, ErrBlendNoThumbnail)
+		})
+	}
+}
+
+func TestDiskThumbnailGenerator_Blend(t *testing.T) {
+	dir := t.TempDir()
+	withThumb := filepath.Join(dir, "scene.blend")
+	withoutThumb := filepath.Join(dir, "scene.blend1")
+	require.NoError(t, os.WriteFile(withThumb, zstdRawFrame(testBlend("BLENDER-v402", testBlendThumb())), 0644))
+	require.NoError(t, os.WriteFile(withoutThumb, testBlend("BLENDER-v279", nil), 0644))
+
+	generator := NewDiskThumbnailGenerator(dir, slog.New(slog.NewTextHandler(io.Discard, nil)))
+
+	res, err := generator.Generate(context.Background(), withThumb)
+	require.NoError(t, err)
+	assert.False(t, res.IsPlaceholder)
+	assert.FileExists(t, filepath.Join(dir, filepath.Base(res.WebPath)))
+	assert.Equal(t, "4.2", res.Metadata.Properties[PropertyBlenderVersion])
+	assert.Equal(t, "zstd", res.Metadata.Properties[PropertyCompression])
+	assert.NotEmpty(t, res.Metadata.DominantColor)
+
+	// Bez podglądu zostaje placeholder, ale wersja Blendera trafia do metadanych.
+	res, err = generator.Generate(context.Background(), withoutThumb)
+	require.NoError(t, err)
+	assert.True(t, res.IsPlaceholder)
+	assert.Equal(t, "/placeholders/blend_placeholder.webp", res.WebPath)
+	assert.Equal(t, "2.79", res.Metadata.Properties[PropertyBlenderVersion])
+}
+
+// Plik bez podglądu dostaje placeholder raz; kolejne skany go pomijają zamiast ponownie rozpakowywać.
+func TestScanner_RescanSkipsBlendWithoutPreview(t *testing.T) {
+	_, queries, scanner, root := setupLogicTest(t)
+	notifier := scanner.notifier.(*MockNotifier)
+	scanner.thumbGen = NewDiskThumbnailGenerator(t.TempDir(), slog.New(slog.NewTextHandler(io.Discard, nil)))
+	scanner.config.SetAllowedExtensions([]string{".blend"})
+	path := filepath.Join(root, "scene.blend")
+	require.NoError(t, os.WriteFile(path, gzipBytes(t, testBlend("BLENDER-v402", nil)), 0644))
+
+	scanAndWait(t, scanner)
+	assert.Equal(t, 1, notifier.LastProgress.Processed)
+	asset, err := queries.GetAssetByPath(context.Background(), path)
+	require.NoError(t, err)
+	assert.Equal(t, "/placeholders/blend_placeholder.webp", asset.ThumbnailPath)
+
+	scanAndWait(t, scanner)
+	assert.Equal(t, 0, notifier.LastProgress.Processed)
+	assert.Equal(t, 1, notifier.LastProgress.Skipped)
+}
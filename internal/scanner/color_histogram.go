@@ -0,0 +1,91 @@
+package scanner
+
+import (
+	"errors"
+	"image"
+	"math"
+
+	"github.com/disintegration/imaging"
+	"github.com/lucasb-eyer/go-colorful"
+)
+
+const (
+	histLBins = 4
+	histABins = 6
+	histBBins = 6
+	// ColorHistogramSize is the length of the encoded histogram stored in asset_features.color_histogram.
+	ColorHistogramSize = histLBins * histABins * histBBins
+
+	// histSampleSize is the edge of the square the image is reduced to before counting pixels.
+	histSampleSize = 64
+	// histABRange clamps the a/b axes. Almost all real-world colors live within ±0.6,
+	// so spending bins on the extremes of the sRGB gamut would only blur the histogram.
+	histABRange = 0.6
+)
+
+// CalculateColorHistogram builds a coarse color histogram in CIELAB space.
+// Each bin is quantized to a byte (the share of pixels * 255), so the histogram is cheap to
+// store and to compare across a whole library. Mostly transparent pixels are ignored.
+func CalculateColorHistogram(img image.Image) ([]byte, error) {
+	if img == nil {
+		return nil, errors.New("image is nil")
+	}
+	if img.Bounds().Empty() {
+		return nil, errors.New("image is empty")
+	}
+
+	small := imaging.Resize(img, histSampleSize, histSampleSize, imaging.Box)
+
+	var counts [ColorHistogramSize]int
+	total := 0
+	for i := 0; i < len(small.Pix); i += 4 {
+		if small.Pix[i+3] < 128 {
+			continue
+		}
+		c := colorful.Color{
+			R: float64(small.Pix[i]) / 255,
+			G: float64(small.Pix[i+1]) / 255,
+			B: float64(small.Pix[i+2]) / 255,
+		}
+		l, a, b := c.Lab()
+		counts[histBin(l, a, b)]++
+		total++
+	}
+	if total == 0 {
+		return nil, errors.New("image is fully transparent")
+	}
+
+	hist := make([]byte, ColorHistogramSize)
+	for i, n := range counts {
+		hist[i] = byte(math.Round(float64(n) * 255 / float64(total)))
+	}
+	return hist, nil
+}
+
+func histBin(l, a, b float64) int {
+	quantize := func(v, lo, hi float64, bins int) int {
+		idx := int((v - lo) / (hi - lo) * float64(bins))
+		return min(max(idx, 0), bins-1)
+	}
+	li := quantize(l, 0, 1, histLBins)
+	ai := quantize(a, -histABRange, histABRange, histABins)
+	bi := quantize(b, -histABRange, histABRange, histBBins)
+	return (li*histABins+ai)*histBBins + bi
+}
+
+// ColorHistogramDistance returns the normalized L1 distance between two histograms:
+// 0 for identical color distributions, 1 for completely disjoint ones.
+func ColorHistogramDistance(a, b []byte) float64 {
+	if len(a) != ColorHistogramSize || len(b) != ColorHistogramSize {
+		return 1
+	}
+	sum := 0
+	for i := range a {
+		d := int(a[i]) - int(b[i])
+		if d < 0 {
+			d = -d
+		}
+		sum += d
+	}
+	return min(float64(sum)/(2*255), 1)
+}
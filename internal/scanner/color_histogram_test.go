@@ -0,0 +1,41 @@
+package scanner
+
+import (
+	"image"
+	"image/color"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func solidImage(c color.NRGBA) image.Image {
+	img := image.NewNRGBA(image.Rect(0, 0, 32, 32))
+	for y := 0; y < 32; y++ {
+		for x := 0; x < 32; x++ {
+			img.SetNRGBA(x, y, c)
+		}
+	}
+	return img
+}
+
+func TestCalculateColorHistogram(t *testing.T) {
+	red, err := CalculateColorHistogram(solidImage(color.NRGBA{R: 220, G: 30, B: 30, A: 255}))
+	assert.NoError(t, err)
+	assert.Len(t, red, ColorHistogramSize)
+
+	darkRed, _ := CalculateColorHistogram(solidImage(color.NRGBA{R: 200, G: 25, B: 35, A: 255}))
+	blue, _ := CalculateColorHistogram(solidImage(color.NRGBA{R: 30, G: 40, B: 220, A: 255}))
+
+	assert.Equal(t, 0.0, ColorHistogramDistance(red, red))
+	assert.Less(t, ColorHistogramDistance(red, darkRed), ColorHistogramDistance(red, blue))
+	assert.Equal(t, 1.0, ColorHistogramDistance(red, blue), "Disjoint colors are maximally distant")
+
+	t.Run("Ignores transparent pixels", func(t *testing.T) {
+		_, err := CalculateColorHistogram(solidImage(color.NRGBA{R: 255, A: 0}))
+		assert.Error(t, err)
+	})
+
+	t.Run("Mismatched sizes are treated as unrelated", func(t *testing.T) {
+		assert.Equal(t, 1.0, ColorHistogramDistance(red, []byte{1, 2, 3}))
+	})
+}
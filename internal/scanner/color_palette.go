@@ -0,0 +1,227 @@
+package scanner
+
+import (
+	"cmp"
+	"eclat/internal/config"
+	"errors"
+	"image"
+	"math"
+	"slices"
+	"strings"
+
+	"github.com/EdlinOrg/prominentcolor"
+	"github.com/disintegration/imaging"
+	"github.com/lucasb-eyer/go-colorful"
+)
+
+const (
+	// PaletteSize is the number of main colors stored per asset (asset_colors).
+	PaletteSize = 5
+
+	// paletteSampleSize is the longer edge the image is reduced to before clustering.
+	paletteSampleSize = prominentcolor.DefaultSize
+	// paletteMaxRounds bounds the k-means iterations; with a few hundred buckets it converges in a handful.
+	paletteMaxRounds = 20
+)
+
+// WeightedColor is one of the main colors of an image.
+type WeightedColor struct {
+	Hex         string  // Raw cluster color, #RRGGBB
+	PaletteName string  // Nearest entry of the palette
+	PaletteHex  string  // Hex of that palette entry
+	Weight      float64 // Share of the (non-background) pixels, 0..1
+}
+
+// paletteBucket is a group of nearly identical pixels (5 bits per channel).
+type paletteBucket struct {
+	l, a, b float64 // mean Lab of the pixels
+	weight  float64 // number of pixels
+}
+
+// CalculateColorPalette extracts up to k main colors from an image, ordered from the most to the
+// least dominant, and snaps each one to the nearest palette entry (left empty if the palette is empty).
+//
+// Solid backgrounds of isolated images are masked out the same way prominentcolor does it, then a
+// pixel-weighted k-means runs in CIELAB. The seeding is deterministic, so rescanning an unchanged
+// file yields the same palette.
+func CalculateColorPalette(img image.Image, k int, palette []config.PaletteColor) ([]WeightedColor, error) {
+	if img == nil {
+		return nil, errors.New("image is nil")
+	}
+	if img.Bounds().Empty() {
+		return nil, errors.New("image is empty")
+	}
+	if k <= 0 {
+		return nil, errors.New("palette size must be positive")
+	}
+
+	small := imaging.Fit(img, paletteSampleSize, paletteSampleSize, imaging.Box)
+	masked := prominentcolor.ProcessImg(prominentcolor.ArgumentNoCropping, prominentcolor.GetDefaultMasks(), small)
+
+	buckets, total := collectPaletteBuckets(masked)
+	if total == 0 {
+		return nil, errors.New("no opaque pixels left after masking the background")
+	}
+
+	clusters := weightedKMeans(buckets, k)
+
+	byHex := make(map[string]*WeightedColor, len(clusters))
+	var colors []*WeightedColor
+	for _, c := range clusters {
+		if c.weight == 0 {
+			continue
+		}
+		hex := colorful.Lab(c.l, c.a, c.b).Clamped().Hex()
+		if existing, ok := byHex[hex]; ok {
+			existing.Weight += c.weight / total
+			continue
+		}
+		wc := &WeightedColor{Hex: strings.ToUpper(hex), Weight: c.weight / total}
+		byHex[hex] = wc
+		colors = append(colors, wc)
+	}
+
+	result := make([]WeightedColor, 0, len(colors))
+	for _, c := range colors {
+		result = append(result, *c)
+	}
+	slices.SortFunc(result, func(x, y WeightedColor) int {
+		return cmp.Or(cmp.Compare(y.Weight, x.Weight), cmp.Compare(x.Hex, y.Hex))
+	})
+	return SnapToPalette(result, palette), nil
+}
+
+func collectPaletteBuckets(img image.Image) ([]paletteBucket, float64) {
+	bounds := img.Bounds()
+	index := make(map[uint16]int)
+	var buckets []paletteBucket
+	total := 0.0
+	for y := bounds.Min.Y; y < bounds.Max.Y; y++ {
+		for x := bounds.Min.X; x < bounds.Max.X; x++ {
+			r, g, b, a := img.At(x, y).RGBA()
+			if a < 0x8000 {
+				continue
+			}
+			// Un-premultiply, the masked image is RGBA.
+			r, g, b = r*0xffff/a, g*0xffff/a, b*0xffff/a
+			key := uint16(r>>11)<<10 | uint16(g>>11)<<5 | uint16(b>>11)
+
+			l, la, lb := colorful.Color{R: float64(r) / 0xffff, G: float64(g) / 0xffff, B: float64(b) / 0xffff}.Lab()
+			i, ok := index[key]
+			if !ok {
+				i = len(buckets)
+				index[key] = i
+				buckets = append(buckets, paletteBucket{})
+			}
+			bk := &buckets[i]
+			bk.weight++
+			// Running mean keeps the bucket color exact for flat areas.
+			bk.l += (l - bk.l) / bk.weight
+			bk.a += (la - bk.a) / bk.weight
+			bk.b += (lb - bk.b) / bk.weight
+			total++
+		}
+	}
+	return buckets, total
+}
+
+// weightedKMeans clusters the buckets into at most k clusters. Seeding starts from the heaviest
+// bucket and then repeatedly takes the bucket with the largest weight * distance² to the
+// chosen centroids (a deterministic k-means++).
+func weightedKMeans(buckets []paletteBucket, k int) []paletteBucket {
+	if len(buckets) == 0 {
+		return nil
+	}
+
+	dist2 := func(p, q paletteBucket) float64 {
+		dl, da, db := p.l-q.l, p.a-q.a, p.b-q.b
+		return dl*dl + da*da + db*db
+	}
+
+	heaviest := 0
+	for i, b := range buckets {
+		if b.weight > buckets[heaviest].weight {
+			heaviest = i
+		}
+	}
+	centroids := []paletteBucket{buckets[heaviest]}
+	minDist := make([]float64, len(buckets))
+	for i := range buckets {
+		minDist[i] = dist2(buckets[i], centroids[0])
+	}
+	for len(centroids) < k {
+		best, bestScore := -1, 0.0
+		for i, b := range buckets {
+			if score := b.weight * minDist[i]; score > bestScore {
+				best, bestScore = i, score
+			}
+		}
+		if best < 0 {
+			break // fewer distinct colors than k
+		}
+		centroids = append(centroids, buckets[best])
+		for i := range buckets {
+			minDist[i] = min(minDist[i], dist2(buckets[i], buckets[best]))
+		}
+	}
+
+	assignment := make([]int, len(buckets))
+	for i := range assignment {
+		assignment[i] = -1
+	}
+	for round := 0; round < paletteMaxRounds; round++ {
+		changed := false
+		for i, b := range buckets {
+			closest, closestDist := 0, math.MaxFloat64
+			for c, centroid := range centroids {
+				if d := dist2(b, centroid); d < closestDist {
+					closest, closestDist = c, d
+				}
+			}
+			if assignment[i] != closest {
+				assignment[i] = closest
+				changed = true
+			}
+		}
+
+		next := make([]paletteBucket, len(centroids))
+		for i, b := range buckets {
+			c := &next[assignment[i]]
+			c.l += b.l * b.weight
+			c.a += b.a * b.weight
+			c.b += b.b * b.weight
+			c.weight += b.weight
+		}
+		for c := range next {
+			if next[c].weight == 0 {
+				// Keep an emptied centroid where it was instead of collapsing it to black.
+				next[c] = paletteBucket{l: centroids[c].l, a: centroids[c].a, b: centroids[c].b}
+				continue
+			}
+			next[c].l /= next[c].weight
+			next[c].a /= next[c].weight
+			next[c].b /= next[c].weight
+		}
+		centroids = next
+
+		if !changed {
+			break
+		}
+	}
+	return centroids
+}
+
+// SnapToPalette returns a copy of colors with PaletteName/PaletteHex pointing at the nearest
+// entry of palette. Only the raw Hex is read, so stored palettes can be re-mapped without
+// touching the source files.
+func SnapToPalette(colors []WeightedColor, palette []config.PaletteColor) []WeightedColor {
+	snapped := slices.Clone(colors)
+	for i := range snapped {
+		snapped[i].PaletteName, snapped[i].PaletteHex = "", ""
+		if entry, err := FindClosestPaletteEntry(snapped[i].Hex, palette); err == nil {
+			snapped[i].PaletteName = entry.Name
+			snapped[i].PaletteHex = entry.Hex
+		}
+	}
+	return snapped
+}
@@ -0,0 +1,64 @@
+package scanner
+
+import (
+	"eclat/internal/config"
+	"image"
+	"image/color"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCalculateColorPalette(t *testing.T) {
+	palette := []config.PaletteColor{
+		{Name: "Red", Hex: "#FF0000"},
+		{Name: "Blue", Hex: "#0000FF"},
+	}
+
+	// 70% czerwieni, 30% niebieskiego
+	img := image.NewNRGBA(image.Rect(0, 0, 100, 100))
+	for y := 0; y < 100; y++ {
+		for x := 0; x < 100; x++ {
+			c := color.NRGBA{R: 220, G: 20, B: 20, A: 255}
+			if x >= 70 {
+				c = color.NRGBA{R: 20, G: 20, B: 200, A: 255}
+			}
+			img.SetNRGBA(x, y, c)
+		}
+	}
+
+	colors, err := CalculateColorPalette(img, PaletteSize, palette)
+	assert.NoError(t, err)
+	if assert.NotEmpty(t, colors) {
+		assert.Equal(t, "#DC1414", colors[0].Hex)
+		assert.Equal(t, "Red", colors[0].PaletteName)
+		assert.Equal(t, "#FF0000", colors[0].PaletteHex)
+		assert.InDelta(t, 0.7, colors[0].Weight, 0.05)
+	}
+
+	var total, blue float64
+	for _, c := range colors {
+		total += c.Weight
+		if c.PaletteHex == "#0000FF" {
+			blue += c.Weight
+		}
+	}
+	assert.InDelta(t, 1.0, total, 0.001)
+	assert.InDelta(t, 0.3, blue, 0.05)
+
+	t.Run("Is deterministic", func(t *testing.T) {
+		again, err := CalculateColorPalette(img, PaletteSize, palette)
+		assert.NoError(t, err)
+		assert.Equal(t, colors, again)
+	})
+
+	t.Run("Ignores transparent pixels", func(t *testing.T) {
+		_, err := CalculateColorPalette(image.NewNRGBA(image.Rect(0, 0, 16, 16)), PaletteSize, palette)
+		assert.Error(t, err)
+	})
+
+	t.Run("Should handle nil image safe", func(t *testing.T) {
+		_, err := CalculateColorPalette(nil, PaletteSize, palette)
+		assert.Error(t, err)
+	})
+}
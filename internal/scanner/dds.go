@@ -0,0 +1,431 @@
+package scanner
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"image"
+	"image/color"
+	"io"
+	"math"
+	"math/bits"
+	"os"
+)
+
+// ErrTexturePreviewUnsupported is returned together with a valid TextureInfo when the header could be
+// read but the pixel format cannot be decoded into a preview (BC6H, ASTC, ...).
+var ErrTexturePreviewUnsupported = errors.New("texture format has no preview decoder")
+
+const (
+	ddsMagic      = "DDS "
+	ddsHeaderSize = 124
+	ddsDX10Size   = 20
+)
+
+// Pixel format flags.
+const (
+	ddpfAlphaPixels = 0x1
+	ddpfAlpha       = 0x2
+	ddpfFourCC      = 0x4
+	ddpfRGB         = 0x40
+	ddpfLuminance   = 0x20000
+)
+
+// ddsAlpha says where HasAlpha comes from.
+type ddsAlpha int
+
+const (
+	ddsAlphaNone    ddsAlpha = iota
+	ddsAlphaAlways           // the format stores alpha
+	ddsAlphaContent          // BC1 and BC7 may or may not use alpha; decided by the decoded pixels
+)
+
+// ddsFormat describes how the first mip level of a DDS file is decoded. Exactly one of block,
+// pixel and pixelF is set.
+type ddsFormat struct {
+	name        string
+	compression string
+	alpha       ddsAlpha
+	bitDepth    int
+
+	blockSize int // bytes per 4x4 block
+	block     func(src []byte, dst *[16]color.NRGBA)
+
+	bitsPerPixel int
+	pixel        func(b []byte) color.NRGBA // 8-bit (or narrowed 16-bit) uncompressed data
+	pixelF       func(b []byte) [4]float32  // float data, tone mapped like HDR images
+}
+
+func bcFormat(name, compression string, size int, alpha ddsAlpha, block func(src []byte, dst *[16]color.NRGBA)) ddsFormat {
+	return ddsFormat{name: name, compression: compression, alpha: alpha, bitDepth: 8, blockSize: size, block: block}
+}
+
+func decodeBC1Block(src []byte, dst *[16]color.NRGBA)  { decodeBC1(src, dst, false) }
+func decodeBC4UBlock(src []byte, dst *[16]color.NRGBA) { bc4Gray(src, dst, false) }
+func decodeBC4SBlock(src []byte, dst *[16]color.NRGBA) { bc4Gray(src, dst, true) }
+func decodeBC5UBlock(src []byte, dst *[16]color.NRGBA) { decodeBC5(src, dst, false) }
+func decodeBC5SBlock(src []byte, dst *[16]color.NRGBA) { decodeBC5(src, dst, true) }
+
+func bc4Gray(src []byte, dst *[16]color.NRGBA, signed bool) {
+	for i, v := range decodeBC4(src, signed) {
+		dst[i] = color.NRGBA{R: v, G: v, B: v, A: 255}
+	}
+}
+
+var (
+	ddsBC1     = bcFormat("BC1_UNORM", "BC1", 8, ddsAlphaContent, decodeBC1Block)
+	ddsBC2     = bcFormat("BC2_UNORM", "BC2", 16, ddsAlphaAlways, decodeBC2)
+	ddsBC3     = bcFormat("BC3_UNORM", "BC3", 16, ddsAlphaAlways, decodeBC3)
+	ddsBC4U    = bcFormat("BC4_UNORM", "BC4", 8, ddsAlphaNone, decodeBC4UBlock)
+	ddsBC4S    = bcFormat("BC4_SNORM", "BC4", 8, ddsAlphaNone, decodeBC4SBlock)
+	ddsBC5U    = bcFormat("BC5_UNORM", "BC5", 16, ddsAlphaNone, decodeBC5UBlock)
+	ddsBC5S    = bcFormat("BC5_SNORM", "BC5", 16, ddsAlphaNone, decodeBC5SBlock)
+	ddsRGBA16F = ddsFormat{name: "R16G16B16A16_FLOAT", compression: "none", alpha: ddsAlphaAlways, bitDepth: 16, bitsPerPixel: 64, pixelF: func(b []byte) [4]float32 {
+		return [4]float32{halfToFloat32(le16(b)), halfToFloat32(le16(b[2:])), halfToFloat32(le16(b[4:])), halfToFloat32(le16(b[6:]))}
+	}}
+	ddsRGBA32F = ddsFormat{name: "R32G32B32A32_FLOAT", compression: "none", alpha: ddsAlphaAlways, bitDepth: 32, bitsPerPixel: 128, pixelF: func(b []byte) [4]float32 {
+		return [4]float32{f32(b), f32(b[4:]), f32(b[8:]), f32(b[12:])}
+	}}
+	ddsR16F = ddsFormat{name: "R16_FLOAT", compression: "none", bitDepth: 16, bitsPerPixel: 16, pixelF: func(b []byte) [4]float32 {
+		v := halfToFloat32(le16(b))
+		return [4]float32{v, v, v, 1}
+	}}
+	ddsR32F = ddsFormat{name: "R32_FLOAT", compression: "none", bitDepth: 32, bitsPerPixel: 32, pixelF: func(b []byte) [4]float32 {
+		v := f32(b)
+		return [4]float32{v, v, v, 1}
+	}}
+	ddsRGBA16 = ddsFormat{name: "R16G16B16A16_UNORM", compression: "none", alpha: ddsAlphaAlways, bitDepth: 16, bitsPerPixel: 64, pixel: func(b []byte) color.NRGBA {
+		return color.NRGBA{R: b[1], G: b[3], B: b[5], A: b[7]} // high bytes of little endian words
+	}}
+	ddsRGBA8 = ddsFormat{name: "R8G8B8A8_UNORM", compression: "none", alpha: ddsAlphaAlways, bitDepth: 8, bitsPerPixel: 32, pixel: func(b []byte) color.NRGBA {
+		return color.NRGBA{R: b[0], G: b[1], B: b[2], A: b[3]}
+	}}
+	ddsBGRA8 = ddsFormat{name: "B8G8R8A8_UNORM", compression: "none", alpha: ddsAlphaAlways, bitDepth: 8, bitsPerPixel: 32, pixel: func(b []byte) color.NRGBA {
+		return color.NRGBA{R: b[2], G: b[1], B: b[0], A: b[3]}
+	}}
+)
+
+// ddsFourCCFormats maps legacy FourCC codes (and the D3DFMT numbers stored in the same field).
+var ddsFourCCFormats = map[string]ddsFormat{
+	"DXT1": ddsBC1, "DXT2": ddsBC2, "DXT3": ddsBC2, "DXT4": ddsBC3, "DXT5": ddsBC3,
+	"ATI1": ddsBC4U, "BC4U": ddsBC4U, "BC4S": ddsBC4S,
+	"ATI2": ddsBC5U, "BC5U": ddsBC5U, "BC5S": ddsBC5S,
+	"\x24\x00\x00\x00": ddsRGBA16,  // D3DFMT_A16B16G16R16
+	"\x6f\x00\x00\x00": ddsR16F,    // D3DFMT_R16F
+	"\x71\x00\x00\x00": ddsRGBA16F, // D3DFMT_A16B16G16R16F
+	"\x72\x00\x00\x00": ddsR32F,    // D3DFMT_R32F
+	"\x74\x00\x00\x00": ddsRGBA32F, // D3DFMT_A32B32G32R32F
+}
+
+// dxgiFormat returns the decoder of a DXGI_FORMAT value from the DX10 header extension.
+func dxgiFormat(code uint32) (ddsFormat, bool) {
+	named := func(f ddsFormat, name string) ddsFormat {
+		f.name = name
+		return f
+	}
+	switch code {
+	case 2:
+		return ddsRGBA32F, true
+	case 10:
+		return ddsRGBA16F, true
+	case 11:
+		return ddsRGBA16, true
+	case 24:
+		return ddsFormat{name: "R10G10B10A2_UNORM", compression: "none", alpha: ddsAlphaAlways, bitDepth: 10, bitsPerPixel: 32, pixel: func(b []byte) color.NRGBA {
+			v := binary.LittleEndian.Uint32(b)
+			ten := func(c uint32) uint8 { return uint8(c & 0x3ff * 255 / 1023) }
+			return color.NRGBA{R: ten(v), G: ten(v >> 10), B: ten(v >> 20), A: uint8(v>>30) * 85}
+		}}, true
+	case 27:
+		return named(ddsRGBA8, "R8G8B8A8_TYPELESS"), true
+	case 28:
+		return ddsRGBA8, true
+	case 29:
+		return named(ddsRGBA8, "R8G8B8A8_UNORM_SRGB"), true
+	case 41:
+		return ddsR32F, true
+	case 54:
+		return ddsR16F, true
+	case 61:
+		return ddsFormat{name: "R8_UNORM", compression: "none", bitDepth: 8, bitsPerPixel: 8, pixel: func(b []byte) color.NRGBA {
+			return color.NRGBA{R: b[0], G: b[0], B: b[0], A: 255}
+		}}, true
+	case 70:
+		return named(ddsBC1, "BC1_TYPELESS"), true
+	case 71:
+		return ddsBC1, true
+	case 72:
+		return named(ddsBC1, "BC1_UNORM_SRGB"), true
+	case 73:
+		return named(ddsBC2, "BC2_TYPELESS"), true
+	case 74:
+		return ddsBC2, true
+	case 75:
+		return named(ddsBC2, "BC2_UNORM_SRGB"), true
+	case 76:
+		return named(ddsBC3, "BC3_TYPELESS"), true
+	case 77:
+		return ddsBC3, true
+	case 78:
+		return named(ddsBC3, "BC3_UNORM_SRGB"), true
+	case 79:
+		return named(ddsBC4U, "BC4_TYPELESS"), true
+	case 80:
+		return ddsBC4U, true
+	case 81:
+		return ddsBC4S, true
+	case 82:
+		return named(ddsBC5U, "BC5_TYPELESS"), true
+	case 83:
+		return ddsBC5U, true
+	case 84:
+		return ddsBC5S, true
+	case 87:
+		return ddsBGRA8, true
+	case 88:
+		f := named(ddsBGRA8, "B8G8R8X8_UNORM")
+		f.alpha = ddsAlphaNone
+		f.pixel = func(b []byte) color.NRGBA { return color.NRGBA{R: b[2], G: b[1], B: b[0], A: 255} }
+		return f, true
+	case 90:
+		return named(ddsBGRA8, "B8G8R8A8_TYPELESS"), true
+	case 91:
+		return named(ddsBGRA8, "B8G8R8A8_UNORM_SRGB"), true
+	case 94, 95, 96:
+		// HDR block compression; only described, not decoded.
+		return ddsFormat{name: [...]string{"BC6H_TYPELESS", "BC6H_UF16", "BC6H_SF16"}[code-94], compression: "BC6H", bitDepth: 16}, true
+	case 97:
+		return bcFormat("BC7_TYPELESS", "BC7", 16, ddsAlphaContent, decodeBC7), true
+	case 98:
+		return bcFormat("BC7_UNORM", "BC7", 16, ddsAlphaContent, decodeBC7), true
+	case 99:
+		return bcFormat("BC7_UNORM_SRGB", "BC7", 16, ddsAlphaContent, decodeBC7), true
+	}
+	return ddsFormat{}, false
+}
+
+// ddsMaskFormat builds a decoder for legacy uncompressed files described by bit masks.
+func ddsMaskFormat(flags, bitCount uint32, masks [4]uint32) (ddsFormat, error) {
+	if bitCount == 0 || bitCount > 32 || bitCount%8 != 0 {
+		return ddsFormat{}, fmt.Errorf("unsupported bit count %d", bitCount)
+	}
+	alpha := flags&(ddpfAlphaPixels|ddpfAlpha) != 0 && masks[3] != 0
+	if !alpha {
+		masks[3] = 0
+	}
+	f := ddsFormat{compression: "none", bitDepth: 8, bitsPerPixel: int(bitCount)}
+	if alpha {
+		f.alpha = ddsAlphaAlways
+	}
+	switch {
+	case flags&ddpfRGB != 0:
+		f.name = ddsMaskName(bitCount, masks)
+	case flags&ddpfLuminance != 0:
+		f.name = fmt.Sprintf("L%d", bits.OnesCount32(masks[0]))
+		if alpha {
+			f.name += fmt.Sprintf("A%d", bits.OnesCount32(masks[3]))
+		}
+		masks[1], masks[2] = masks[0], masks[0]
+	case flags&ddpfAlpha != 0:
+		f.name = fmt.Sprintf("A%d", bits.OnesCount32(masks[3]))
+	default:
+		return ddsFormat{}, errors.New("unknown pixel format")
+	}
+	if bits.OnesCount32(masks[0]) > 8 {
+		f.bitDepth = bits.OnesCount32(masks[0])
+	}
+
+	bytesPerPixel := int(bitCount / 8)
+	f.pixel = func(b []byte) color.NRGBA {
+		var v uint32
+		for i := 0; i < bytesPerPixel; i++ {
+			v |= uint32(b[i]) << (8 * i)
+		}
+		return color.NRGBA{R: maskChannel(v, masks[0], 0), G: maskChannel(v, masks[1], 0), B: maskChannel(v, masks[2], 0), A: maskChannel(v, masks[3], 255)}
+	}
+	return f, nil
+}
+
+// ddsMaskName names the common mask layouts like DXGI does, other layouts by their depth.
+func ddsMaskName(bitCount uint32, m [4]uint32) string {
+	switch {
+	case m == [4]uint32{0xff0000, 0xff00, 0xff, 0xff000000}:
+		return "B8G8R8A8_UNORM"
+	case m == [4]uint32{0xff0000, 0xff00, 0xff, 0} && bitCount == 32:
+		return "B8G8R8X8_UNORM"
+	case m == [4]uint32{0xff0000, 0xff00, 0xff, 0}:
+		return "B8G8R8_UNORM"
+	case m == [4]uint32{0xff, 0xff00, 0xff0000, 0xff000000}:
+		return "R8G8B8A8_UNORM"
+	case m == [4]uint32{0xf800, 0x7e0, 0x1f, 0}:
+		return "B5G6R5_UNORM"
+	case m == [4]uint32{0x7c00, 0x3e0, 0x1f, 0x8000}:
+		return "B5G5R5A1_UNORM"
+	case m == [4]uint32{0xf00, 0xf0, 0xf, 0xf000}:
+		return "B4G4R4A4_UNORM"
+	case m[3] != 0:
+		return fmt.Sprintf("RGBA%d", bitCount)
+	}
+	return fmt.Sprintf("RGB%d", bitCount)
+}
+
+// maskChannel extracts the bits of mask from v and scales them to 8 bits.
+func maskChannel(v, mask uint32, fallback uint8) uint8 {
+	if mask == 0 {
+		return fallback
+	}
+	n := bits.OnesCount32(mask)
+	c := uint64(v&mask) >> bits.TrailingZeros32(mask)
+	return uint8(c * 255 / (1<<n - 1))
+}
+
+func le16(b []byte) uint16 { return binary.LittleEndian.Uint16(b) }
+
+func f32(b []byte) float32 { return math.Float32frombits(binary.LittleEndian.Uint32(b)) }
+
+// DecodeDDSFile reads a preview and the info of a DirectDraw Surface file.
+func DecodeDDSFile(path string) (image.Image, TextureInfo, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, TextureInfo{}, err
+	}
+	defer f.Close()
+	return DecodeDDS(f)
+}
+
+// DecodeDDS decodes the first mip level (the first face of cube maps and arrays) of a DDS file.
+// Supported are BC1-BC5, BC7 and the common uncompressed formats; for other formats the info is
+// returned with ErrTexturePreviewUnsupported.
+func DecodeDDS(r io.Reader) (image.Image, TextureInfo, error) {
+	br := bufio.NewReaderSize(r, 64<<10)
+	var hdr [4 + ddsHeaderSize]byte
+	if _, err := io.ReadFull(br, hdr[:]); err != nil {
+		return nil, TextureInfo{}, fmt.Errorf("failed to read header: %w", err)
+	}
+	if !bytes.Equal(hdr[:4], []byte(ddsMagic)) || binary.LittleEndian.Uint32(hdr[4:]) != ddsHeaderSize {
+		return nil, TextureInfo{}, errors.New("not a DDS file")
+	}
+	h := hdr[4:]
+	u32 := func(off int) uint32 { return binary.LittleEndian.Uint32(h[off:]) }
+	height, width := int(u32(8)), int(u32(12))
+	info := TextureInfo{Width: width, Height: height, MipCount: max(1, int(u32(24)))}
+	if width <= 0 || height <= 0 || width > textureMaxDimension || height > textureMaxDimension {
+		return nil, info, fmt.Errorf("invalid dimensions %dx%d", width, height)
+	}
+
+	pfFlags, fourCC := u32(76), string(h[80:84])
+	var format ddsFormat
+	switch {
+	case pfFlags&ddpfFourCC != 0 && fourCC == "DX10":
+		var ext [ddsDX10Size]byte
+		if _, err := io.ReadFull(br, ext[:]); err != nil {
+			return nil, info, fmt.Errorf("failed to read DX10 header: %w", err)
+		}
+		code := binary.LittleEndian.Uint32(ext[:])
+		var ok bool
+		if format, ok = dxgiFormat(code); !ok {
+			info.Format = fmt.Sprintf("DXGI_%d", code)
+			return nil, info, fmt.Errorf("%w: DXGI format %d", ErrTexturePreviewUnsupported, code)
+		}
+	case pfFlags&ddpfFourCC != 0:
+		var ok bool
+		if format, ok = ddsFourCCFormats[fourCC]; !ok {
+			return nil, info, fmt.Errorf("%w: FourCC %q", ErrTexturePreviewUnsupported, fourCC)
+		}
+	default:
+		var err error
+		format, err = ddsMaskFormat(pfFlags, u32(84), [4]uint32{u32(88), u32(92), u32(96), u32(100)})
+		if err != nil {
+			return nil, info, err
+		}
+	}
+
+	info.Format = format.name
+	info.Compression = format.compression
+	info.BitDepth = format.bitDepth
+	info.HasAlpha = format.alpha == ddsAlphaAlways
+
+	switch {
+	case format.block != nil:
+		img, alpha, err := decodeDDSBlocks(br, width, height, format)
+		if format.alpha == ddsAlphaContent {
+			info.HasAlpha = alpha
+		}
+		return img, info, err
+	case format.pixel != nil:
+		preview := newTexturePreview(width, height)
+		err := readDDSRows(br, width, height, format.bitsPerPixel, func(x, y int, px []byte) {
+			preview.set(x, y, format.pixel(px))
+		}, preview.wantsRow)
+		if err != nil {
+			return nil, info, err
+		}
+		return preview.img, info, nil
+	case format.pixelF != nil:
+		preview := newHDRPreview(width, height)
+		err := readDDSRows(br, width, height, format.bitsPerPixel, func(x, y int, px []byte) {
+			for c, v := range format.pixelF(px) {
+				preview.set(x, y, c, v)
+			}
+		}, preview.wantsRow)
+		if err != nil {
+			return nil, info, err
+		}
+		return preview.toneMap(), info, nil
+	}
+	return nil, info, fmt.Errorf("%w: %s", ErrTexturePreviewUnsupported, format.name)
+}
+
+// decodeDDSBlocks decodes rows of 4x4 blocks. Block rows without a sampled pixel row are skipped.
+// alpha reports whether any decoded pixel is not fully opaque.
+func decodeDDSBlocks(r io.Reader, width, height int, format ddsFormat) (image.Image, bool, error) {
+	preview := newTexturePreview(width, height)
+	blocksWide := (width + 3) / 4
+	row := make([]byte, blocksWide*format.blockSize)
+	var pixels [16]color.NRGBA
+	alpha := false
+	for by := 0; by < (height+3)/4; by++ {
+		if _, err := io.ReadFull(r, row); err != nil {
+			return nil, false, fmt.Errorf("block row %d: %w", by, err)
+		}
+		wanted := false
+		for y := by * 4; y < min(by*4+4, height); y++ {
+			wanted = wanted || preview.wantsRow(y)
+		}
+		if !wanted {
+			continue
+		}
+		for bx := 0; bx < blocksWide; bx++ {
+			format.block(row[bx*format.blockSize:], &pixels)
+			for i, c := range pixels {
+				x, y := bx*4+i%4, by*4+i/4
+				if x >= width || y >= height {
+					continue
+				}
+				alpha = alpha || c.A != 255
+				preview.set(x, y, c)
+			}
+		}
+	}
+	return preview.img, alpha, nil
+}
+
+// readDDSRows reads uncompressed rows of byte aligned pixels and passes the wanted ones to set.
+func readDDSRows(r io.Reader, width, height, bitsPerPixel int, set func(x, y int, px []byte), wantsRow func(y int) bool) error {
+	bytesPerPixel := bitsPerPixel / 8
+	row := make([]byte, (width*bitsPerPixel+7)/8)
+	for y := 0; y < height; y++ {
+		if _, err := io.ReadFull(r, row); err != nil {
+			return fmt.Errorf("row %d: %w", y, err)
+		}
+		if !wantsRow(y) {
+			continue
+		}
+		for x := 0; x < width; x++ {
+			set(x, y, row[x*bytesPerPixel:])
+		}
+	}
+	return nil
+}
@@ -0,0 +1,343 @@
+package scanner
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"image/color"
+	"io"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// testDDS buduje plik .dds. fourCC == "DX10" dopisuje rozszerzony nagłówek z dxgi.
+func testDDS(w, h, mips int, flags uint32, fourCC string, dxgi uint32, bitCount uint32, masks [4]uint32, data []byte) []byte {
+	out := make([]byte, 4+ddsHeaderSize)
+	copy(out, ddsMagic)
+	hd := out[4:]
+	binary.LittleEndian.PutUint32(hd[0:], ddsHeaderSize)
+	binary.LittleEndian.PutUint32(hd[8:], uint32(h))
+	binary.LittleEndian.PutUint32(hd[12:], uint32(w))
+	binary.LittleEndian.PutUint32(hd[24:], uint32(mips))
+	binary.LittleEndian.PutUint32(hd[72:], 32)
+	binary.LittleEndian.PutUint32(hd[76:], flags)
+	copy(hd[80:84], fourCC)
+	binary.LittleEndian.PutUint32(hd[84:], bitCount)
+	for i, m := range masks {
+		binary.LittleEndian.PutUint32(hd[88+4*i:], m)
+	}
+	if fourCC == "DX10" {
+		ext := make([]byte, ddsDX10Size)
+		binary.LittleEndian.PutUint32(ext, dxgi)
+		out = append(out, ext...)
+	}
+	return append(out, data...)
+}
+
+// repeatBlock powtarza blok dla tekstury w x h.
+func repeatBlock(block []byte, w, h int) []byte {
+	return bytes.Repeat(block, ((w+3)/4)*((h+3)/4))
+}
+
+// bc7Writer zapisuje bity bloku BC7 od najmłodszego.
+type bc7Writer struct {
+	block [16]byte
+	pos   int
+}
+
+func (b *bc7Writer) write(v, n int) {
+	for i := 0; i < n; i++ {
+		if v>>i&1 == 1 {
+			b.block[b.pos>>3] |= 1 << (b.pos & 7)
+		}
+		b.pos++
+	}
+}
+
+func bc1Block(c0, c1 uint16, indices uint32) []byte {
+	b := make([]byte, 8)
+	binary.LittleEndian.PutUint16(b, c0)
+	binary.LittleEndian.PutUint16(b[2:], c1)
+	binary.LittleEndian.PutUint32(b[4:], indices)
+	return b
+}
+
+func TestDecodeBC1(t *testing.T) {
+	var px [16]color.NRGBA
+	decodeBC1(bc1Block(0xf800, 0x001f, 0b10_01_00), &px, false)
+	assert.Equal(t, color.NRGBA{R: 255, A: 255}, px[0])
+	assert.Equal(t, color.NRGBA{B: 255, A: 255}, px[1])
+	assert.Equal(t, color.NRGBA{R: 170, B: 85, A: 255}, px[2])
+
+	// color0 <= color1: tryb trzech kolorów z przezroczystym indeksem 3
+	decodeBC1(bc1Block(0x001f, 0xf800, 0b11_10), &px, false)
+	assert.Equal(t, color.NRGBA{R: 127, B: 127, A: 255}, px[0])
+	assert.Equal(t, color.NRGBA{}, px[1])
+}
+
+func TestDecodeBC4AndBC5(t *testing.T) {
+	// e0 > e1: 8 wartości; indeks 1 to e1, indeks 2 to 6/7 e0 + 1/7 e1
+	block := []byte{210, 70, 0b001_010_001, 0, 0, 0, 0, 0}
+	v := decodeBC4(block, false)
+	assert.Equal(t, uint8(70), v[0])
+	assert.Equal(t, uint8(190), v[1])
+	assert.Equal(t, uint8(210), v[3])
+
+	// Signed: -127..127 mapowane na 0..255
+	v = decodeBC4([]byte{0x81, 0x7f, 0b001_000, 0, 0, 0, 0, 0}, true)
+	assert.Equal(t, uint8(0), v[0])
+	assert.Equal(t, uint8(255), v[1])
+
+	// Płaska normalna (128, 128) daje Z = 1.
+	var px [16]color.NRGBA
+	flat := []byte{128, 128, 0, 0, 0, 0, 0, 0}
+	decodeBC5(append(flat, flat...), &px, false)
+	assert.Equal(t, uint8(128), px[5].R)
+	assert.Equal(t, uint8(255), px[5].B)
+}
+
+func TestDecodeBC7(t *testing.T) {
+	t.Run("Mode 6", func(t *testing.T) {
+		w := &bc7Writer{}
+		w.write(1<<6, 7)
+		for _, c := range [][2]int{{127, 0}, {0, 0}, {0, 0}, {127, 0}} { // R, G, B, A: e0, e1
+			w.write(c[0], 7)
+			w.write(c[1], 7)
+		}
+		w.write(1, 1) // p-bit e0
+		w.write(0, 1) // p-bit e1
+		for i := 0; i < 16; i++ {
+			idx := 0
+			if i == 5 {
+				idx = 15
+			}
+			n := 4
+			if i == 0 {
+				n = 3
+			}
+			w.write(idx, n)
+		}
+		require.Equal(t, 128, w.pos)
+
+		var px [16]color.NRGBA
+		decodeBC7(w.block[:], &px)
+		// p-bit e0 = 1 ustawia najmłodszy bit także w zerowych składowych
+		assert.Equal(t, color.NRGBA{R: 255, G: 1, B: 1, A: 255}, px[0])
+		assert.Equal(t, color.NRGBA{}, px[5])
+	})
+
+	mode5 := func(rotation, alpha0 int) [16]byte {
+		w := &bc7Writer{}
+		w.write(1<<5, 6)
+		w.write(rotation, 2)
+		for _, c := range [][2]int{{127, 0}, {0, 0}, {0, 127}} {
+			w.write(c[0], 7)
+			w.write(c[1], 7)
+		}
+		w.write(alpha0, 8)
+		w.write(0, 8)
+		for pass := 0; pass < 2; pass++ { // indeksy koloru, potem alfy
+			for i := 0; i < 16; i++ {
+				n := 2
+				if i == 0 {
+					n = 1
+				}
+				idx := 0
+				if i == 1 {
+					idx = 3
+				}
+				w.write(idx, n)
+			}
+		}
+		require.Equal(t, 128, w.pos)
+		return w.block
+	}
+
+	t.Run("Mode 5 with separate alpha", func(t *testing.T) {
+		block := mode5(0, 255)
+		var px [16]color.NRGBA
+		decodeBC7(block[:], &px)
+		assert.Equal(t, color.NRGBA{R: 255, A: 255}, px[0])
+		assert.Equal(t, color.NRGBA{B: 255}, px[1])
+	})
+
+	t.Run("Mode 5 rotation swaps alpha and red", func(t *testing.T) {
+		block := mode5(1, 100)
+		var px [16]color.NRGBA
+		decodeBC7(block[:], &px)
+		assert.Equal(t, color.NRGBA{R: 100, A: 255}, px[0])
+		assert.Equal(t, color.NRGBA{B: 255}, px[1])
+	})
+
+	t.Run("Mode 1 with two subsets", func(t *testing.T) {
+		w := &bc7Writer{}
+		w.write(1<<1, 2)
+		w.write(0, 6) // partycja 0: kolumny 2-3 należą do drugiego podzbioru
+		for _, c := range [][4]int{{63, 63, 0, 0}, {0, 0, 63, 63}, {0, 0, 0, 0}} {
+			for _, v := range c {
+				w.write(v, 6)
+			}
+		}
+		w.write(1, 1)
+		w.write(1, 1)
+		w.write(0, 46)
+		require.Equal(t, 128, w.pos)
+
+		var px [16]color.NRGBA
+		decodeBC7(w.block[:], &px)
+		assert.Equal(t, color.NRGBA{R: 255, G: 2, B: 2, A: 255}, px[0])
+		assert.Equal(t, color.NRGBA{R: 2, G: 255, B: 2, A: 255}, px[2])
+		assert.Equal(t, color.NRGBA{R: 2, G: 255, B: 2, A: 255}, px[15])
+	})
+
+	t.Run("Reserved mode", func(t *testing.T) {
+		var px [16]color.NRGBA
+		decodeBC7(make([]byte, 16), &px)
+		assert.Equal(t, color.NRGBA{}, px[0])
+	})
+}
+
+func TestBC7AnchorTables(t *testing.T) {
+	// Piksel kotwicy musi należeć do podzbioru, którego indeks zapisuje bez najstarszego bitu.
+	for p := 0; p < 64; p++ {
+		assert.Equal(t, uint8(0), bc7Partitions2[p][0], "partition %d", p)
+		assert.Equal(t, uint8(1), bc7Partitions2[p][bc7Anchors2[p]], "partition %d", p)
+		assert.Equal(t, uint8(0), bc7Partitions3[p][0], "partition %d", p)
+		assert.Equal(t, uint8(1), bc7Partitions3[p][bc7Anchors3Second[p]], "partition %d", p)
+		assert.Equal(t, uint8(2), bc7Partitions3[p][bc7Anchors3Third[p]], "partition %d", p)
+	}
+}
+
+func TestDecodeDDS(t *testing.T) {
+	t.Run("DXT1 with transparency", func(t *testing.T) {
+		data := testDDS(8, 6, 4, ddpfFourCC, "DXT1", 0, 0, [4]uint32{}, repeatBlock(bc1Block(0x001f, 0xf800, 0xffffffff), 8, 6))
+		img, info, err := DecodeDDS(bytes.NewReader(data))
+		require.NoError(t, err)
+		assert.Equal(t, TextureInfo{Width: 8, Height: 6, MipCount: 4, Format: "BC1_UNORM", Compression: "BC1", BitDepth: 8, HasAlpha: true}, info)
+		assert.Equal(t, 8, img.Bounds().Dx())
+		assert.Equal(t, 6, img.Bounds().Dy())
+	})
+
+	t.Run("Opaque DXT1", func(t *testing.T) {
+		data := testDDS(4, 4, 0, ddpfFourCC, "DXT1", 0, 0, [4]uint32{}, bc1Block(0xf800, 0x001f, 0))
+		img, info, err := DecodeDDS(bytes.NewReader(data))
+		require.NoError(t, err)
+		assert.False(t, info.HasAlpha)
+		assert.Equal(t, 1, info.MipCount)
+		assert.Equal(t, color.NRGBA{R: 255, A: 255}, img.At(3, 3))
+	})
+
+	t.Run("DX10 BC7 sRGB", func(t *testing.T) {
+		w := &bc7Writer{}
+		w.write(1<<6, 7)
+		for _, c := range [][2]int{{0, 0}, {127, 127}, {0, 0}, {127, 127}} {
+			w.write(c[0], 7)
+			w.write(c[1], 7)
+		}
+		w.write(0b11, 2)
+		data := testDDS(4, 4, 1, ddpfFourCC, "DX10", 99, 0, [4]uint32{}, w.block[:])
+		img, info, err := DecodeDDS(bytes.NewReader(data))
+		require.NoError(t, err)
+		assert.Equal(t, "BC7_UNORM_SRGB", info.Format)
+		assert.Equal(t, "BC7", info.Compression)
+		assert.False(t, info.HasAlpha)
+		assert.Equal(t, color.NRGBA{R: 1, G: 255, B: 1, A: 255}, img.At(1, 1))
+	})
+
+	t.Run("Legacy BGRA masks", func(t *testing.T) {
+		pixels := bytes.Repeat([]byte{255, 0, 0, 64}, 2*2) // BGRA
+		data := testDDS(2, 2, 1, ddpfRGB|ddpfAlphaPixels, "", 0, 32, [4]uint32{0xff0000, 0xff00, 0xff, 0xff000000}, pixels)
+		img, info, err := DecodeDDS(bytes.NewReader(data))
+		require.NoError(t, err)
+		assert.Equal(t, "B8G8R8A8_UNORM", info.Format)
+		assert.Equal(t, "none", info.Compression)
+		assert.True(t, info.HasAlpha)
+		assert.Equal(t, color.NRGBA{B: 255, A: 64}, img.At(1, 1))
+	})
+
+	t.Run("Legacy R5G6B5", func(t *testing.T) {
+		data := testDDS(1, 1, 1, ddpfRGB, "", 0, 16, [4]uint32{0xf800, 0x7e0, 0x1f, 0}, []byte{0x00, 0xf8})
+		img, info, err := DecodeDDS(bytes.NewReader(data))
+		require.NoError(t, err)
+		assert.Equal(t, "B5G6R5_UNORM", info.Format)
+		assert.False(t, info.HasAlpha)
+		assert.Equal(t, color.NRGBA{R: 255, A: 255}, img.At(0, 0))
+	})
+
+	t.Run("Half float is tone mapped", func(t *testing.T) {
+		var pixels []byte
+		for i := 0; i < 4; i++ {
+			for _, v := range []float32{8, 0.1, 0.1, 1} {
+				pixels = binary.LittleEndian.AppendUint16(pixels, float32ToHalf(v))
+			}
+		}
+		data := testDDS(2, 2, 1, ddpfFourCC, "DX10", 10, 0, [4]uint32{}, pixels)
+		img, info, err := DecodeDDS(bytes.NewReader(data))
+		require.NoError(t, err)
+		assert.Equal(t, "R16G16B16A16_FLOAT", info.Format)
+		assert.Equal(t, 16, info.BitDepth)
+		r, g, _, _ := img.At(0, 0).RGBA()
+		assert.Greater(t, r, g)
+	})
+
+	t.Run("BC6H keeps header info", func(t *testing.T) {
+		data := testDDS(16, 16, 5, ddpfFourCC, "DX10", 95, 0, [4]uint32{}, make([]byte, 256))
+		_, info, err := DecodeDDS(bytes.NewReader(data))
+		assert.ErrorIs(t, err, ErrTexturePreviewUnsupported)
+		assert.Equal(t, "BC6H_UF16", info.Format)
+		assert.Equal(t, 5, info.MipCount)
+		assert.Equal(t, 16, info.Width)
+	})
+}
+
+func TestDecodeDDS_InvalidInput(t *testing.T) {
+	valid := testDDS(8, 8, 1, ddpfFourCC, "DXT5", 0, 0, [4]uint32{}, make([]byte, 4*16))
+	for name, data := range map[string][]byte{
+		"Not DDS":        append([]byte("XXXX"), valid[4:]...),
+		"Truncated":      valid[:len(valid)-1],
+		"Zero width":     testDDS(0, 8, 1, ddpfFourCC, "DXT1", 0, 0, [4]uint32{}, nil),
+		"No pixel flags": testDDS(4, 4, 1, 0, "", 0, 32, [4]uint32{}, make([]byte, 64)),
+	} {
+		t.Run(name, func(t *testing.T) {
+			_, _, err := DecodeDDS(bytes.NewReader(data))
+			assert.Error(t, err)
+		})
+	}
+}
+
+func TestDiskThumbnailGenerator_DDS(t *testing.T) {
+	dir := t.TempDir()
+	generator := NewDiskThumbnailGenerator(dir, slog.New(slog.NewTextHandler(io.Discard, nil)))
+
+	t.Run("Decoded preview", func(t *testing.T) {
+		path := filepath.Join(dir, "normal.dds")
+		block := append(bytes.Repeat([]byte{255, 0, 0, 0, 0, 0, 0, 0}, 1), []byte{128, 128, 0, 0, 0, 0, 0, 0}...)
+		require.NoError(t, os.WriteFile(path, testDDS(32, 16, 6, ddpfFourCC, "ATI2", 0, 0, [4]uint32{}, repeatBlock(block, 32, 16)), 0644))
+
+		res, err := generator.Generate(context.Background(), path)
+		require.NoError(t, err)
+		assert.False(t, res.IsPlaceholder)
+		assert.Equal(t, 32, res.Metadata.Width)
+		assert.Equal(t, 16, res.Metadata.Height)
+		assert.Equal(t, "6", res.Metadata.Properties[PropertyMipCount])
+		assert.Equal(t, "BC5_UNORM", res.Metadata.Properties[PropertyPixelFormat])
+		assert.Equal(t, "BC5", res.Metadata.Properties[PropertyCompression])
+	})
+
+	t.Run("Unsupported format keeps metadata", func(t *testing.T) {
+		path := filepath.Join(dir, "sky.dds")
+		require.NoError(t, os.WriteFile(path, testDDS(64, 64, 7, ddpfFourCC, "DX10", 95, 0, [4]uint32{}, nil), 0644))
+
+		res, err := generator.Generate(context.Background(), path)
+		require.NoError(t, err)
+		assert.True(t, res.IsPlaceholder)
+		assert.Equal(t, 64, res.Metadata.Width)
+		assert.Equal(t, 16, res.Metadata.BitDepth)
+		assert.Equal(t, "BC6H", res.Metadata.Properties[PropertyCompression])
+	})
+}
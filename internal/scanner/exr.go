@@ -0,0 +1,638 @@
+package scanner
+
+import (
+	"bufio"
+	"bytes"
+	"compress/zlib"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"image"
+	"io"
+	"math"
+	"os"
+	"slices"
+	"strings"
+)
+
+const exrMagic = 20000630
+
+// Flags stored in the version field next to the format version.
+const (
+	exrFlagTiled     = 0x200
+	exrFlagNonImage  = 0x800 // deep data
+	exrFlagMultipart = 0x1000
+)
+
+// OpenEXR compression methods.
+const (
+	exrCompressionNone = 0
+	exrCompressionRLE  = 1
+	exrCompressionZIPS = 2
+	exrCompressionZIP  = 3
+	exrCompressionPIZ  = 4
+)
+
+var exrCompressionNames = []string{"none", "RLE", "ZIPS", "ZIP", "PIZ", "PXR24", "B44", "B44A", "DWAA", "DWAB"}
+
+// OpenEXR channel pixel types.
+const (
+	exrPixelUint  = 0
+	exrPixelHalf  = 1
+	exrPixelFloat = 2
+)
+
+// Preview roles of channels, see exrPreviewRoles.
+const (
+	exrRoleNone  = -1
+	exrRoleAlpha = 3
+	exrRoleGray  = 4 // luminance only (Y) or a single data channel
+)
+
+const (
+	// exrMaxAttributeSize guards against corrupt headers.
+	exrMaxAttributeSize = 16 << 20
+	// exrMaxTileSize guards against corrupt tile descriptions.
+	exrMaxTileSize = 1 << 16
+)
+
+type exrChannel struct {
+	name      string
+	pixelType int32
+	xSampling int
+	ySampling int
+}
+
+// size returns the number of bytes of one sample.
+func (c exrChannel) size() int {
+	if c.pixelType == exrPixelHalf {
+		return 2
+	}
+	return 4
+}
+
+// value converts the little endian sample at the start of b to a float.
+func (c exrChannel) value(b []byte) float32 {
+	switch c.pixelType {
+	case exrPixelHalf:
+		return halfToFloat32(binary.LittleEndian.Uint16(b))
+	case exrPixelFloat:
+		return math.Float32frombits(binary.LittleEndian.Uint32(b))
+	default:
+		return float32(binary.LittleEndian.Uint32(b))
+	}
+}
+
+// exrHeader holds the attributes of a single part image needed to decode it.
+type exrHeader struct {
+	channels    []exrChannel
+	compression int
+	xMin, yMin  int
+	xMax, yMax  int
+	tiled       bool
+	tileWidth   int
+	tileHeight  int
+}
+
+func (h *exrHeader) width() int  { return h.xMax - h.xMin + 1 }
+func (h *exrHeader) height() int { return h.yMax - h.yMin + 1 }
+
+// linesPerBlock returns how many scanlines a chunk of a scanline image holds.
+func (h *exrHeader) linesPerBlock() int {
+	switch h.compression {
+	case exrCompressionZIP:
+		return 16
+	case exrCompressionPIZ:
+		return 32
+	default:
+		return 1
+	}
+}
+
+// tileCounts returns the number of full resolution tiles in both directions.
+func (h *exrHeader) tileCounts() (int, int) {
+	return (h.width() + h.tileWidth - 1) / h.tileWidth, (h.height() + h.tileHeight - 1) / h.tileHeight
+}
+
+// chunkCount returns the number of full resolution chunks. Mipmap levels of tiled images follow
+// them in the offset table and are not read.
+func (h *exrHeader) chunkCount() int {
+	if h.tiled {
+		nx, ny := h.tileCounts()
+		return nx * ny
+	}
+	lines := h.linesPerBlock()
+	return (h.height() + lines - 1) / lines
+}
+
+// blockSize returns the uncompressed size of the pixel block [x0, x0+w) x [y0, y0+lines).
+func (h *exrHeader) blockSize(x0, y0, w, lines int) int {
+	size := 0
+	for y := y0; y < y0+lines; y++ {
+		for _, ch := range h.channels {
+			if exrFloorMod(y, ch.ySampling) == 0 {
+				size += exrNumSamples(ch.xSampling, x0, x0+w-1) * ch.size()
+			}
+		}
+	}
+	return size
+}
+
+// info summarises the header for the asset metadata.
+func (h *exrHeader) info() HDRInfo {
+	info := HDRInfo{
+		Width:       h.width(),
+		Height:      h.height(),
+		BitDepth:    16,
+		Compression: exrCompressionNames[h.compression],
+		Tiled:       h.tiled,
+	}
+	for _, ch := range h.channels {
+		info.Channels = append(info.Channels, ch.name)
+		if ch.pixelType != exrPixelHalf {
+			info.BitDepth = 32
+		}
+	}
+	return info
+}
+
+// DecodeEXRFile reads a tone mapped preview and the info of an OpenEXR file.
+func DecodeEXRFile(path string) (image.Image, HDRInfo, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, HDRInfo{}, err
+	}
+	defer f.Close()
+	return DecodeEXR(f)
+}
+
+// DecodeEXR decodes the full resolution level of a single part scanline or tiled OpenEXR image
+// compressed with none, RLE, ZIPS, ZIP or PIZ. The preview is built from the RGB(A) channels of
+// the default layer, the first layer that has them, or a single grayscale channel.
+func DecodeEXR(r io.ReadSeeker) (image.Image, HDRInfo, error) {
+	cr := &exrCountingReader{r: bufio.NewReader(r)}
+	h, err := readEXRHeader(cr)
+	if err != nil {
+		return nil, HDRInfo{}, err
+	}
+	info := h.info()
+
+	offsets := make([]uint64, h.chunkCount())
+	if err := binary.Read(cr, binary.LittleEndian, offsets); err != nil {
+		return nil, info, fmt.Errorf("failed to read offset table: %w", err)
+	}
+	tableEnd := uint64(cr.n)
+
+	roles := exrPreviewRoles(h.channels)
+	info.HasAlpha = slices.Contains(roles, exrRoleAlpha)
+
+	dec := &exrDecoder{header: h}
+	preview := newHDRPreview(h.width(), h.height())
+	for _, offset := range offsets {
+		if offset == 0 {
+			continue // chunk never written (interrupted render)
+		}
+		if offset < tableEnd {
+			return nil, info, fmt.Errorf("invalid chunk offset %d", offset)
+		}
+		if err := dec.readChunk(r, int64(offset), roles, preview); err != nil {
+			return nil, info, err
+		}
+	}
+	return preview.toneMap(), info, nil
+}
+
+// exrCountingReader tracks the position while the header is read through a buffered reader.
+type exrCountingReader struct {
+	r *bufio.Reader
+	n int64
+}
+
+func (c *exrCountingReader) Read(p []byte) (int, error) {
+	n, err := c.r.Read(p)
+	c.n += int64(n)
+	return n, err
+}
+
+func (c *exrCountingReader) ReadByte() (byte, error) {
+	b, err := c.r.ReadByte()
+	if err == nil {
+		c.n++
+	}
+	return b, err
+}
+
+func readEXRHeader(r *exrCountingReader) (*exrHeader, error) {
+	var start [2]uint32
+	if err := binary.Read(r, binary.LittleEndian, &start); err != nil || start[0] != exrMagic {
+		return nil, errors.New("not an OpenEXR file")
+	}
+	version := start[1]
+	if version&0xff != 2 {
+		return nil, fmt.Errorf("unsupported OpenEXR version %d", version&0xff)
+	}
+	if version&exrFlagMultipart != 0 {
+		return nil, errors.New("multi-part OpenEXR files are not supported")
+	}
+	if version&exrFlagNonImage != 0 {
+		return nil, errors.New("deep OpenEXR files are not supported")
+	}
+
+	h := &exrHeader{compression: -1, tiled: version&exrFlagTiled != 0}
+	hasWindow := false
+	for {
+		name, err := readEXRString(r)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read header: %w", err)
+		}
+		if name == "" {
+			break
+		}
+		typ, err := readEXRString(r)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read header: %w", err)
+		}
+		var size int32
+		if err := binary.Read(r, binary.LittleEndian, &size); err != nil {
+			return nil, fmt.Errorf("failed to read header: %w", err)
+		}
+		if size < 0 || size > exrMaxAttributeSize {
+			return nil, fmt.Errorf("invalid size of attribute %q", name)
+		}
+		value := make([]byte, size)
+		if _, err := io.ReadFull(r, value); err != nil {
+			return nil, fmt.Errorf("failed to read attribute %q: %w", name, err)
+		}
+
+		switch {
+		case name == "channels" && typ == "chlist":
+			if h.channels, err = parseEXRChannels(value); err != nil {
+				return nil, err
+			}
+		case name == "compression" && typ == "compression" && size == 1:
+			h.compression = int(value[0])
+		case name == "dataWindow" && typ == "box2i" && size == 16:
+			h.xMin = int(int32(binary.LittleEndian.Uint32(value[0:])))
+			h.yMin = int(int32(binary.LittleEndian.Uint32(value[4:])))
+			h.xMax = int(int32(binary.LittleEndian.Uint32(value[8:])))
+			h.yMax = int(int32(binary.LittleEndian.Uint32(value[12:])))
+			hasWindow = true
+		case name == "tiles" && typ == "tiledesc" && size == 9:
+			h.tileWidth = int(binary.LittleEndian.Uint32(value[0:]))
+			h.tileHeight = int(binary.LittleEndian.Uint32(value[4:]))
+		}
+	}
+
+	switch {
+	case len(h.channels) == 0:
+		return nil, errors.New("missing channel list")
+	case !hasWindow:
+		return nil, errors.New("missing data window")
+	case h.width() <= 0 || h.height() <= 0 || h.width() > hdrMaxDimension || h.height() > hdrMaxDimension:
+		return nil, fmt.Errorf("invalid data window %dx%d", h.width(), h.height())
+	case h.compression < 0 || h.compression >= len(exrCompressionNames):
+		return nil, fmt.Errorf("unknown compression %d", h.compression)
+	case h.compression > exrCompressionPIZ:
+		return nil, fmt.Errorf("unsupported compression %s", exrCompressionNames[h.compression])
+	case h.tiled && (h.tileWidth <= 0 || h.tileHeight <= 0 || h.tileWidth > exrMaxTileSize || h.tileHeight > exrMaxTileSize):
+		return nil, fmt.Errorf("invalid tile size %dx%d", h.tileWidth, h.tileHeight)
+	}
+	return h, nil
+}
+
+// readEXRString reads a null terminated attribute name or type.
+func readEXRString(r io.ByteReader) (string, error) {
+	var sb strings.Builder
+	for {
+		b, err := r.ReadByte()
+		if err != nil {
+			return "", err
+		}
+		if b == 0 {
+			return sb.String(), nil
+		}
+		if sb.Len() >= 255 {
+			return "", errors.New("attribute name too long")
+		}
+		sb.WriteByte(b)
+	}
+}
+
+func parseEXRChannels(data []byte) ([]exrChannel, error) {
+	var channels []exrChannel
+	for len(data) > 0 {
+		end := bytes.IndexByte(data, 0)
+		if end < 0 {
+			return nil, errors.New("corrupt channel list")
+		}
+		if end == 0 {
+			break
+		}
+		name := string(data[:end])
+		data = data[end+1:]
+		if len(data) < 16 {
+			return nil, errors.New("corrupt channel list")
+		}
+		ch := exrChannel{
+			name:      name,
+			pixelType: int32(binary.LittleEndian.Uint32(data[0:])),
+			xSampling: int(int32(binary.LittleEndian.Uint32(data[8:]))),
+			ySampling: int(int32(binary.LittleEndian.Uint32(data[12:]))),
+		}
+		data = data[16:]
+		if ch.pixelType < exrPixelUint || ch.pixelType > exrPixelFloat {
+			return nil, fmt.Errorf("channel %q has unknown pixel type %d", name, ch.pixelType)
+		}
+		if ch.xSampling < 1 || ch.ySampling < 1 {
+			return nil, fmt.Errorf("channel %q has invalid sampling", name)
+		}
+		channels = append(channels, ch)
+	}
+	return channels, nil
+}
+
+// exrPreviewRoles picks the channels shown in the preview: R, G, B and A of the default layer,
+// or of the first layer ("beauty.R") when the default layer has no color. Luminance (Y) is used
+// only without RGB, and when nothing matches the first channel is shown as grayscale.
+func exrPreviewRoles(channels []exrChannel) []int {
+	roles := make([]int, len(channels))
+	colorRole := func(suffix string) int {
+		switch suffix {
+		case "R":
+			return 0
+		case "G":
+			return 1
+		case "B":
+			return 2
+		case "A":
+			return exrRoleAlpha
+		case "Y":
+			return exrRoleGray
+		}
+		return exrRoleNone
+	}
+	split := func(name string) (string, string) {
+		if i := strings.LastIndexByte(name, '.'); i >= 0 {
+			return name[:i], name[i+1:]
+		}
+		return "", name
+	}
+
+	layer, found := "", false
+	for _, ch := range channels {
+		if prefix, suffix := split(ch.name); prefix == "" && colorRole(suffix) != exrRoleNone && suffix != "A" {
+			found = true
+			break
+		}
+	}
+	if !found {
+		for _, ch := range channels {
+			if prefix, suffix := split(ch.name); colorRole(suffix) != exrRoleNone && suffix != "A" {
+				layer, found = prefix, true
+				break
+			}
+		}
+	}
+
+	hasColor := false
+	for i, ch := range channels {
+		roles[i] = exrRoleNone
+		prefix, suffix := split(ch.name)
+		if !found || prefix != layer || ch.xSampling != 1 || ch.ySampling != 1 {
+			continue
+		}
+		roles[i] = colorRole(suffix)
+		if roles[i] >= 0 && roles[i] <= 2 {
+			hasColor = true
+		}
+	}
+	if hasColor {
+		for i, role := range roles {
+			if role == exrRoleGray {
+				roles[i] = exrRoleNone
+			}
+		}
+	}
+	if !found {
+		for i, ch := range channels {
+			if ch.xSampling == 1 && ch.ySampling == 1 {
+				roles[i] = exrRoleGray
+				break
+			}
+		}
+	}
+	return roles
+}
+
+// exrDecoder reads chunks of one image, reusing the decompression state between them.
+type exrDecoder struct {
+	header *exrHeader
+	zlib   io.ReadCloser
+	piz    *exrPIZ
+}
+
+// readChunk decodes the chunk at offset and copies its preview channels into preview.
+func (d *exrDecoder) readChunk(r io.ReadSeeker, offset int64, roles []int, preview *hdrPreview) error {
+	h := d.header
+	if _, err := r.Seek(offset, io.SeekStart); err != nil {
+		return err
+	}
+
+	var x0, y0, w, lines int
+	var size int32
+	if h.tiled {
+		var head [5]int32 // tile x, tile y, level x, level y, data size
+		if err := binary.Read(r, binary.LittleEndian, &head); err != nil {
+			return fmt.Errorf("failed to read tile header: %w", err)
+		}
+		nx, ny := h.tileCounts()
+		tx, ty := int(head[0]), int(head[1])
+		if head[2] != 0 || head[3] != 0 || tx < 0 || ty < 0 || tx >= nx || ty >= ny {
+			return fmt.Errorf("invalid tile %d,%d (level %d,%d)", tx, ty, head[2], head[3])
+		}
+		x0, y0 = h.xMin+tx*h.tileWidth, h.yMin+ty*h.tileHeight
+		w, lines = min(h.tileWidth, h.xMax-x0+1), min(h.tileHeight, h.yMax-y0+1)
+		size = head[4]
+	} else {
+		var head [2]int32 // first scanline, data size
+		if err := binary.Read(r, binary.LittleEndian, &head); err != nil {
+			return fmt.Errorf("failed to read chunk header: %w", err)
+		}
+		y := int(head[0])
+		if y < h.yMin || y > h.yMax || (y-h.yMin)%h.linesPerBlock() != 0 {
+			return fmt.Errorf("invalid chunk start line %d", y)
+		}
+		x0, y0 = h.xMin, y
+		w, lines = h.width(), min(h.linesPerBlock(), h.yMax-y+1)
+		size = head[1]
+	}
+
+	expected := h.blockSize(x0, y0, w, lines)
+	if size <= 0 || int64(size) > 2*int64(expected)+1024 {
+		return fmt.Errorf("invalid chunk size %d at line %d", size, y0)
+	}
+	data := make([]byte, size)
+	if _, err := io.ReadFull(r, data); err != nil {
+		return fmt.Errorf("failed to read chunk at line %d: %w", y0, err)
+	}
+	block, err := d.decompress(data, x0, y0, w, lines, expected)
+	if err != nil {
+		return fmt.Errorf("chunk at line %d: %w", y0, err)
+	}
+
+	pos := 0
+	for y := y0; y < y0+lines; y++ {
+		py := y - h.yMin
+		for i, ch := range h.channels {
+			if exrFloorMod(y, ch.ySampling) != 0 {
+				continue
+			}
+			n := exrNumSamples(ch.xSampling, x0, x0+w-1) * ch.size()
+			if role := roles[i]; role != exrRoleNone && preview.wantsRow(py) {
+				for x := 0; x < w; x++ {
+					v := ch.value(block[pos+x*ch.size():])
+					px := x0 + x - h.xMin
+					if role == exrRoleGray {
+						preview.set(px, py, 0, v)
+						preview.set(px, py, 1, v)
+						preview.set(px, py, 2, v)
+					} else {
+						preview.set(px, py, role, v)
+					}
+				}
+			}
+			pos += n
+		}
+	}
+	return nil
+}
+
+// decompress returns the uncompressed block. Chunks that would not shrink are stored raw
+// regardless of the compression of the file.
+func (d *exrDecoder) decompress(data []byte, x0, y0, w, lines, expected int) ([]byte, error) {
+	if len(data) == expected {
+		return data, nil
+	}
+	switch d.header.compression {
+	case exrCompressionRLE:
+		raw, err := exrUnRLE(data, expected)
+		if err != nil {
+			return nil, err
+		}
+		return exrReconstruct(raw), nil
+	case exrCompressionZIPS, exrCompressionZIP:
+		var err error
+		if d.zlib == nil {
+			d.zlib, err = zlib.NewReader(bytes.NewReader(data))
+		} else {
+			err = d.zlib.(zlib.Resetter).Reset(bytes.NewReader(data), nil)
+		}
+		if err != nil {
+			return nil, err
+		}
+		raw := make([]byte, expected)
+		if _, err := io.ReadFull(d.zlib, raw); err != nil {
+			return nil, fmt.Errorf("zip: %w", err)
+		}
+		return exrReconstruct(raw), nil
+	case exrCompressionPIZ:
+		if d.piz == nil {
+			d.piz = newEXRPIZ()
+		}
+		return d.piz.decode(data, d.header.channels, x0, y0, w, lines, expected)
+	}
+	return nil, fmt.Errorf("data size %d does not match %d", len(data), expected)
+}
+
+// exrUnRLE expands the byte oriented run-length encoding used by RLE compression.
+func exrUnRLE(data []byte, expected int) ([]byte, error) {
+	out := make([]byte, 0, expected)
+	for i := 0; i < len(data); {
+		count := int(int8(data[i]))
+		i++
+		if count < 0 {
+			count = -count
+			if i+count > len(data) || len(out)+count > expected {
+				return nil, errors.New("rle: literal run out of bounds")
+			}
+			out = append(out, data[i:i+count]...)
+			i += count
+			continue
+		}
+		if i >= len(data) || len(out)+count+1 > expected {
+			return nil, errors.New("rle: repeat run out of bounds")
+		}
+		for n := 0; n <= count; n++ {
+			out = append(out, data[i])
+		}
+		i++
+	}
+	if len(out) != expected {
+		return nil, fmt.Errorf("rle: got %d bytes, want %d", len(out), expected)
+	}
+	return out, nil
+}
+
+// exrReconstruct undoes the delta predictor and the byte split applied before RLE and ZIP
+// compression: the first half of the buffer holds the even bytes, the second half the odd ones.
+func exrReconstruct(t []byte) []byte {
+	for i := 1; i < len(t); i++ {
+		t[i] = byte(int(t[i-1]) + int(t[i]) - 128)
+	}
+	out := make([]byte, len(t))
+	half := (len(t) + 1) / 2
+	for i := range out {
+		if i%2 == 0 {
+			out[i] = t[i/2]
+		} else {
+			out[i] = t[half+i/2]
+		}
+	}
+	return out
+}
+
+// halfToFloat32 converts an IEEE 754 half precision number.
+func halfToFloat32(h uint16) float32 {
+	sign := uint32(h>>15) << 31
+	exp := uint32(h>>10) & 0x1f
+	mant := uint32(h & 0x3ff)
+	switch exp {
+	case 0:
+		if mant == 0 {
+			return math.Float32frombits(sign)
+		}
+		// Subnormal: normalize the mantissa.
+		e := uint32(127 - 15 + 1)
+		for mant&0x400 == 0 {
+			mant <<= 1
+			e--
+		}
+		return math.Float32frombits(sign | e<<23 | (mant&0x3ff)<<13)
+	case 0x1f:
+		return math.Float32frombits(sign | 0xff<<23 | mant<<13)
+	}
+	return math.Float32frombits(sign | (exp+127-15)<<23 | mant<<13)
+}
+
+// exrFloorDiv divides rounding towards negative infinity (data windows may start below zero).
+func exrFloorDiv(a, b int) int {
+	q := a / b
+	if a%b != 0 && (a < 0) != (b < 0) {
+		q--
+	}
+	return q
+}
+
+func exrFloorMod(a, b int) int {
+	return a - exrFloorDiv(a, b)*b
+}
+
+// exrNumSamples returns how many samples of a channel with sampling s lie in [a, b].
+func exrNumSamples(s, a, b int) int {
+	a1, b1 := exrFloorDiv(a, s), exrFloorDiv(b, s)
+	n := b1 - a1
+	if a1*s >= a {
+		n++
+	}
+	return n
+}
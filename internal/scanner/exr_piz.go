@@ -0,0 +1,437 @@
+package scanner
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+)
+
+// PIZ compression: the 16-bit words of every channel are remapped through a lookup table of the
+// values actually used, transformed with a 2D Haar wavelet and Huffman coded. This follows the
+// reference implementation (ImfPizCompressor, ImfHuf, ImfWav) closely, including its constants.
+const (
+	pizBitmapSize  = 1 << 13 // one bit per 16-bit value
+	pizUShortRange = 1 << 16
+
+	hufEncBits          = 16
+	hufDecBits          = 14
+	hufEncSize          = 1<<hufEncBits + 1 // one extra slot for the run-length pseudo symbol
+	hufDecSize          = 1 << hufDecBits
+	hufDecMask          = hufDecSize - 1
+	hufShortZeroCodeRun = 59
+	hufLongZeroCodeRun  = 63
+	hufShortestLongRun  = 2 + hufLongZeroCodeRun - hufShortZeroCodeRun
+)
+
+var errHufCorrupt = errors.New("piz: corrupt huffman data")
+
+// exrHufDec is an entry of the Huffman decoding table. Codes of up to hufDecBits bits are
+// resolved directly (len, lit); longer codes sharing the same prefix are listed in long.
+type exrHufDec struct {
+	len  int
+	lit  int
+	long []int
+}
+
+// exrPIZ holds the tables of the PIZ decoder, reused between chunks.
+type exrPIZ struct {
+	bitmap []byte
+	lut    []uint16
+	hcode  []uint64
+	hdec   []exrHufDec
+}
+
+func newEXRPIZ() *exrPIZ {
+	return &exrPIZ{
+		bitmap: make([]byte, pizBitmapSize),
+		lut:    make([]uint16, pizUShortRange),
+		hcode:  make([]uint64, hufEncSize),
+		hdec:   make([]exrHufDec, hufDecSize),
+	}
+}
+
+// decode returns the uncompressed block in the same layout as the other compressions.
+func (z *exrPIZ) decode(data []byte, channels []exrChannel, x0, y0, w, lines, expected int) ([]byte, error) {
+	if len(data) < 4 {
+		return nil, errors.New("piz: chunk too short")
+	}
+	minNonZero := int(binary.LittleEndian.Uint16(data[0:]))
+	maxNonZero := int(binary.LittleEndian.Uint16(data[2:]))
+	pos := 4
+	if maxNonZero >= pizBitmapSize {
+		return nil, errors.New("piz: invalid bitmap range")
+	}
+	clear(z.bitmap)
+	if minNonZero <= maxNonZero {
+		n := maxNonZero - minNonZero + 1
+		if pos+n > len(data) {
+			return nil, errors.New("piz: bitmap out of bounds")
+		}
+		copy(z.bitmap[minNonZero:], data[pos:pos+n])
+		pos += n
+	}
+	maxValue := exrReverseLUT(z.bitmap, z.lut)
+
+	if pos+4 > len(data) {
+		return nil, errors.New("piz: chunk too short")
+	}
+	length := int(int32(binary.LittleEndian.Uint32(data[pos:])))
+	pos += 4
+	if length < 0 || pos+length > len(data) {
+		return nil, errors.New("piz: invalid compressed length")
+	}
+
+	words := make([]uint16, expected/2)
+	if err := z.hufUncompress(data[pos:pos+length], words); err != nil {
+		return nil, err
+	}
+
+	// Channels are stored one after another, each as a nx * ny plane of (size / 2) word samples.
+	starts := make([]int, len(channels))
+	start := 0
+	for i, ch := range channels {
+		nx := exrNumSamples(ch.xSampling, x0, x0+w-1)
+		ny := exrNumSamples(ch.ySampling, y0, y0+lines-1)
+		wordsPerSample := ch.size() / 2
+		if start+nx*ny*wordsPerSample > len(words) {
+			return nil, errors.New("piz: channel data out of bounds")
+		}
+		for j := 0; j < wordsPerSample; j++ {
+			exrWav2Decode(words, start+j, nx, wordsPerSample, ny, nx*wordsPerSample, maxValue)
+		}
+		starts[i] = start
+		start += nx * ny * wordsPerSample
+	}
+	for i, v := range words {
+		words[i] = z.lut[v]
+	}
+
+	out := make([]byte, 0, expected)
+	for y := y0; y < y0+lines; y++ {
+		for i, ch := range channels {
+			if exrFloorMod(y, ch.ySampling) != 0 {
+				continue
+			}
+			n := exrNumSamples(ch.xSampling, x0, x0+w-1) * ch.size() / 2
+			for _, v := range words[starts[i] : starts[i]+n] {
+				out = binary.LittleEndian.AppendUint16(out, v)
+			}
+			starts[i] += n
+		}
+	}
+	return out, nil
+}
+
+// exrReverseLUT builds the table mapping the dense codes back to the used 16-bit values and
+// returns the largest code.
+func exrReverseLUT(bitmap []byte, lut []uint16) uint16 {
+	k := 0
+	for i := 0; i < pizUShortRange; i++ {
+		if i == 0 || bitmap[i>>3]&(1<<(i&7)) != 0 {
+			lut[k] = uint16(i)
+			k++
+		}
+	}
+	n := k - 1
+	for ; k < pizUShortRange; k++ {
+		lut[k] = 0
+	}
+	return uint16(n)
+}
+
+// hufUncompress decodes a Huffman stream: a 20 byte header (min and max symbol, table size,
+// bit count), the packed code lengths and the bits.
+func (z *exrPIZ) hufUncompress(data []byte, out []uint16) error {
+	if len(out) == 0 {
+		return nil
+	}
+	if len(data) < 20 {
+		return errHufCorrupt
+	}
+	im := binary.LittleEndian.Uint32(data[0:])
+	iM := binary.LittleEndian.Uint32(data[4:])
+	nBits := uint64(binary.LittleEndian.Uint32(data[12:]))
+	if im >= hufEncSize || iM >= hufEncSize || im > iM {
+		return errHufCorrupt
+	}
+
+	clear(z.hcode)
+	tableSize, err := exrHufUnpackEncTable(data[20:], int(im), int(iM), z.hcode)
+	if err != nil {
+		return err
+	}
+	bits := data[20+tableSize:]
+	if nBits > 8*uint64(len(bits)) {
+		return errHufCorrupt
+	}
+	if err := z.hufBuildDecTable(int(im), int(iM)); err != nil {
+		return err
+	}
+	return z.hufDecode(bits, int(nBits), int(iM), out)
+}
+
+// exrHufUnpackEncTable reads the 6-bit code lengths of symbols im..iM (with zero-run markers),
+// turns them into canonical codes and returns the number of bytes consumed.
+func exrHufUnpackEncTable(in []byte, im, iM int, hcode []uint64) (int, error) {
+	var c uint64
+	lc, pos := 0, 0
+	getBits := func(n int) (uint64, error) {
+		for lc < n {
+			if pos >= len(in) {
+				return 0, errHufCorrupt
+			}
+			c = c<<8 | uint64(in[pos])
+			pos++
+			lc += 8
+		}
+		lc -= n
+		return (c >> lc) & (1<<n - 1), nil
+	}
+
+	for ; im <= iM; im++ {
+		l, err := getBits(6)
+		if err != nil {
+			return 0, err
+		}
+		hcode[im] = l
+		zerun := 0
+		switch {
+		case l == hufLongZeroCodeRun:
+			n, err := getBits(8)
+			if err != nil {
+				return 0, err
+			}
+			zerun = int(n) + hufShortestLongRun
+		case l >= hufShortZeroCodeRun:
+			zerun = int(l) - hufShortZeroCodeRun + 2
+		default:
+			continue
+		}
+		if im+zerun > iM+1 {
+			return 0, errHufCorrupt
+		}
+		for ; zerun > 0; zerun-- {
+			hcode[im] = 0
+			im++
+		}
+		im--
+	}
+	exrHufCanonicalCodeTable(hcode)
+	return pos, nil
+}
+
+// exrHufCanonicalCodeTable replaces the code lengths with "length | code << 6". Codes are
+// assigned so that longer codes are numerically smaller, as in the reference encoder.
+func exrHufCanonicalCodeTable(hcode []uint64) {
+	var n [59]uint64
+	for _, l := range hcode {
+		n[l]++
+	}
+	var c uint64
+	for i := 58; i > 0; i-- {
+		nc := (c + n[i]) >> 1
+		n[i] = c
+		c = nc
+	}
+	for i, l := range hcode {
+		if l > 0 {
+			hcode[i] = l | n[l]<<6
+			n[l]++
+		}
+	}
+}
+
+func (z *exrPIZ) hufBuildDecTable(im, iM int) error {
+	for i := range z.hdec {
+		z.hdec[i] = exrHufDec{long: z.hdec[i].long[:0]}
+	}
+	for ; im <= iM; im++ {
+		c := z.hcode[im] >> 6
+		l := int(z.hcode[im] & 63)
+		if c>>l != 0 {
+			return errHufCorrupt
+		}
+		switch {
+		case l > hufDecBits:
+			pl := &z.hdec[c>>(l-hufDecBits)]
+			if pl.len != 0 {
+				return errHufCorrupt
+			}
+			pl.lit++
+			pl.long = append(pl.long, im)
+		case l > 0:
+			base := int(c << (hufDecBits - l))
+			for i := 0; i < 1<<(hufDecBits-l); i++ {
+				pl := &z.hdec[base+i]
+				if pl.len != 0 || len(pl.long) != 0 {
+					return errHufCorrupt
+				}
+				pl.len = l
+				pl.lit = im
+			}
+		}
+	}
+	return nil
+}
+
+// hufDecode decodes nBits bits of in. Symbol rlc is the run-length pseudo symbol: it is followed
+// by an 8-bit count of repetitions of the previous value.
+func (z *exrPIZ) hufDecode(in []byte, nBits, rlc int, out []uint16) error {
+	var c uint64
+	lc, i, o := 0, 0, 0
+	end := (nBits + 7) / 8
+
+	emit := func(sym int) error {
+		if sym != rlc {
+			if o >= len(out) {
+				return errHufCorrupt
+			}
+			out[o] = uint16(sym)
+			o++
+			return nil
+		}
+		if lc < 8 {
+			if i >= len(in) {
+				return errHufCorrupt
+			}
+			c = c<<8 | uint64(in[i])
+			i++
+			lc += 8
+		}
+		lc -= 8
+		count := int(byte(c >> lc))
+		if o == 0 || o+count > len(out) {
+			return errHufCorrupt
+		}
+		for v := out[o-1]; count > 0; count-- {
+			out[o] = v
+			o++
+		}
+		return nil
+	}
+
+	for i < end {
+		c = c<<8 | uint64(in[i])
+		i++
+		lc += 8
+		for lc >= hufDecBits {
+			pl := &z.hdec[(c>>(lc-hufDecBits))&hufDecMask]
+			if pl.len != 0 {
+				lc -= pl.len
+				if err := emit(pl.lit); err != nil {
+					return err
+				}
+				continue
+			}
+			if len(pl.long) == 0 {
+				return errHufCorrupt
+			}
+			matched := false
+			for _, sym := range pl.long {
+				l := int(z.hcode[sym] & 63)
+				for lc < l && i < end {
+					c = c<<8 | uint64(in[i])
+					i++
+					lc += 8
+				}
+				if lc >= l && z.hcode[sym]>>6 == (c>>(lc-l))&(1<<l-1) {
+					lc -= l
+					if err := emit(sym); err != nil {
+						return err
+					}
+					matched = true
+					break
+				}
+			}
+			if !matched {
+				return errHufCorrupt
+			}
+		}
+	}
+
+	// The last byte is padded; decode the remaining bits one code at a time.
+	pad := (8 - nBits%8) % 8
+	c >>= pad
+	lc -= pad
+	for lc > 0 {
+		pl := &z.hdec[(c<<(hufDecBits-lc))&hufDecMask]
+		if pl.len == 0 || pl.len > lc {
+			return errHufCorrupt
+		}
+		lc -= pl.len
+		if err := emit(pl.lit); err != nil {
+			return err
+		}
+	}
+	if o != len(out) {
+		return fmt.Errorf("piz: decoded %d values, want %d", o, len(out))
+	}
+	return nil
+}
+
+// exrWav2Decode inverts the 2D Haar wavelet transform of an nx * ny plane stored in buf starting
+// at index in, with ox and oy the distances between horizontal and vertical neighbours.
+// Values that fit in 14 bits use a lossless variant without modular arithmetic.
+func exrWav2Decode(buf []uint16, in, nx, ox, ny, oy int, mx uint16) {
+	dec := exrWdec16
+	if mx < 1<<14 {
+		dec = exrWdec14
+	}
+
+	n := min(nx, ny)
+	p := 1
+	for p <= n {
+		p <<= 1
+	}
+	p >>= 1
+	p2 := p
+	p >>= 1
+
+	for p >= 1 {
+		py := in
+		ey := in + oy*(ny-p2)
+		oy1, oy2 := oy*p, oy*p2
+		ox1, ox2 := ox*p, ox*p2
+
+		for ; py <= ey; py += oy2 {
+			px := py
+			ex := py + ox*(nx-p2)
+			for ; px <= ex; px += ox2 {
+				p01 := px + ox1
+				p10 := px + oy1
+				p11 := p10 + ox1
+				i00, i10 := dec(buf[px], buf[p10])
+				i01, i11 := dec(buf[p01], buf[p11])
+				buf[px], buf[p01] = dec(i00, i01)
+				buf[p10], buf[p11] = dec(i10, i11)
+			}
+			if nx&p != 0 {
+				p10 := px + oy1
+				buf[px], buf[p10] = dec(buf[px], buf[p10])
+			}
+		}
+		if ny&p != 0 {
+			px := py
+			ex := py + ox*(nx-p2)
+			for ; px <= ex; px += ox2 {
+				p01 := px + ox1
+				buf[px], buf[p01] = dec(buf[px], buf[p01])
+			}
+		}
+		p2 = p
+		p >>= 1
+	}
+}
+
+func exrWdec14(l, h uint16) (uint16, uint16) {
+	ls, hs := int(int16(l)), int(int16(h))
+	ai := ls + (hs & 1) + (hs >> 1)
+	return uint16(int16(ai)), uint16(int16(ai - hs))
+}
+
+func exrWdec16(l, h uint16) (uint16, uint16) {
+	m, d := int(l), int(h)
+	b := (m - (d >> 1)) & 0xffff
+	a := (d + b - 0x8000) & 0xffff
+	return uint16(a), uint16(b)
+}
@@ -0,0 +1,675 @@
+package scanner
+
+import (
+	"bytes"
+	"compress/zlib"
+	"context"
+	"encoding/binary"
+	"io"
+	"log/slog"
+	"math"
+	"math/rand"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// testEXR buduje minimalny plik OpenEXR (jedna część, scanline lub kafelki).
+// Koder PIZ poniżej to uproszczona wersja referencyjnej: stałej długości kody Huffmana
+// zamiast optymalnych, ale ten sam format strumienia.
+type testEXR struct {
+	width, height int
+	xMin, yMin    int
+	channels      []exrChannel
+	compression   int
+	tileW, tileH  int // > 0 oznacza plik kafelkowy
+	pixel         func(channel string, x, y int) float32
+}
+
+func (e testEXR) header() *exrHeader {
+	return &exrHeader{
+		channels:    e.channels,
+		compression: e.compression,
+		xMin:        e.xMin, yMin: e.yMin,
+		xMax: e.xMin + e.width - 1, yMax: e.yMin + e.height - 1,
+		tiled:     e.tileW > 0,
+		tileWidth: e.tileW, tileHeight: e.tileH,
+	}
+}
+
+func (e testEXR) bytes(t *testing.T) []byte {
+	h := e.header()
+	var buf bytes.Buffer
+	le := func(v any) { assert.NoError(t, binary.Write(&buf, binary.LittleEndian, v)) }
+	attr := func(name, typ string, value []byte) {
+		buf.WriteString(name + "\x00" + typ + "\x00")
+		le(int32(len(value)))
+		buf.Write(value)
+	}
+	leBytes := func(v any) []byte {
+		var b bytes.Buffer
+		assert.NoError(t, binary.Write(&b, binary.LittleEndian, v))
+		return b.Bytes()
+	}
+
+	version := uint32(2)
+	if h.tiled {
+		version |= exrFlagTiled
+	}
+	le(uint32(exrMagic))
+	le(version)
+
+	var chlist bytes.Buffer
+	for _, ch := range e.channels {
+		chlist.WriteString(ch.name + "\x00")
+		chlist.Write(leBytes([4]int32{ch.pixelType, 0, int32(ch.xSampling), int32(ch.ySampling)}))
+	}
+	chlist.WriteByte(0)
+	window := leBytes([4]int32{int32(h.xMin), int32(h.yMin), int32(h.xMax), int32(h.yMax)})
+	attr("channels", "chlist", chlist.Bytes())
+	attr("compression", "compression", []byte{byte(e.compression)})
+	attr("dataWindow", "box2i", window)
+	attr("displayWindow", "box2i", window)
+	attr("lineOrder", "lineOrder", []byte{0})
+	attr("pixelAspectRatio", "float", leBytes(float32(1)))
+	if h.tiled {
+		attr("tiles", "tiledesc", append(leBytes([2]uint32{uint32(e.tileW), uint32(e.tileH)}), 0))
+	}
+	buf.WriteByte(0)
+
+	type block struct{ x0, y0, w, lines, tx, ty int }
+	var blocks []block
+	if h.tiled {
+		nx, ny := h.tileCounts()
+		for ty := 0; ty < ny; ty++ {
+			for tx := 0; tx < nx; tx++ {
+				x0, y0 := h.xMin+tx*e.tileW, h.yMin+ty*e.tileH
+				blocks = append(blocks, block{x0, y0, min(e.tileW, h.xMax-x0+1), min(e.tileH, h.yMax-y0+1), tx, ty})
+			}
+		}
+	} else {
+		for y := h.yMin; y <= h.yMax; y += h.linesPerBlock() {
+			blocks = append(blocks, block{h.xMin, y, e.width, min(h.linesPerBlock(), h.yMax-y+1), 0, 0})
+		}
+	}
+
+	tableStart := buf.Len()
+	buf.Write(make([]byte, 8*len(blocks)))
+	for i, b := range blocks {
+		binary.LittleEndian.PutUint64(buf.Bytes()[tableStart+8*i:], uint64(buf.Len()))
+		raw := e.rawBlock(b.x0, b.y0, b.w, b.lines)
+		data := compressEXRBlock(t, raw, e.compression, e.channels, b.x0, b.y0, b.w, b.lines)
+		if h.tiled {
+			le([4]int32{int32(b.tx), int32(b.ty), 0, 0})
+		} else {
+			le(int32(b.y0))
+		}
+		le(int32(len(data)))
+		buf.Write(data)
+	}
+	return buf.Bytes()
+}
+
+func (e testEXR) rawBlock(x0, y0, w, lines int) []byte {
+	var out []byte
+	for y := y0; y < y0+lines; y++ {
+		for _, ch := range e.channels {
+			if exrFloorMod(y, ch.ySampling) != 0 {
+				continue
+			}
+			for x := x0; x < x0+w; x++ {
+				if exrFloorMod(x, ch.xSampling) != 0 {
+					continue
+				}
+				v := e.pixel(ch.name, x, y)
+				switch ch.pixelType {
+				case exrPixelHalf:
+					out = binary.LittleEndian.AppendUint16(out, float32ToHalf(v))
+				case exrPixelFloat:
+					out = binary.LittleEndian.AppendUint32(out, math.Float32bits(v))
+				default:
+					out = binary.LittleEndian.AppendUint32(out, uint32(v))
+				}
+			}
+		}
+	}
+	return out
+}
+
+// compressEXRBlock kompresuje blok jak zapisujący OpenEXR - łącznie z zapisem "na surowo",
+// gdy kompresja nic nie daje.
+func compressEXRBlock(t *testing.T, raw []byte, compression int, channels []exrChannel, x0, y0, w, lines int) []byte {
+	var data []byte
+	switch compression {
+	case exrCompressionNone:
+		return raw
+	case exrCompressionRLE:
+		data = rleEncode(splitAndPredict(raw))
+	case exrCompressionZIPS, exrCompressionZIP:
+		var b bytes.Buffer
+		zw := zlib.NewWriter(&b)
+		_, err := zw.Write(splitAndPredict(raw))
+		assert.NoError(t, err)
+		assert.NoError(t, zw.Close())
+		data = b.Bytes()
+	case exrCompressionPIZ:
+		data = pizCompress(raw, channels, x0, y0, w, lines)
+	}
+	if len(data) >= len(raw) {
+		return raw
+	}
+	return data
+}
+
+func splitAndPredict(raw []byte) []byte {
+	t := make([]byte, 0, len(raw))
+	for i := 0; i < len(raw); i += 2 {
+		t = append(t, raw[i])
+	}
+	for i := 1; i < len(raw); i += 2 {
+		t = append(t, raw[i])
+	}
+	for i := len(t) - 1; i > 0; i-- {
+		t[i] = byte(int(t[i]) - int(t[i-1]) + 128 + 256)
+	}
+	return t
+}
+
+func rleEncode(in []byte) []byte {
+	var out, literal []byte
+	flush := func() {
+		for len(literal) > 0 {
+			n := min(len(literal), 127)
+			out = append(out, byte(-int8(n)))
+			out = append(out, literal[:n]...)
+			literal = literal[n:]
+		}
+	}
+	for i := 0; i < len(in); {
+		run := 1
+		for i+run < len(in) && in[i+run] == in[i] && run < 128 {
+			run++
+		}
+		if run >= 3 {
+			flush()
+			out = append(out, byte(run-1), in[i])
+		} else {
+			literal = append(literal, in[i:i+run]...)
+		}
+		i += run
+	}
+	flush()
+	return out
+}
+
+func pizCompress(raw []byte, channels []exrChannel, x0, y0, w, lines int) []byte {
+	// Rozdzielenie bloku na płaszczyzny kanałów (odwrotność przeplotu w dekoderze).
+	type plane struct{ start, pos, nx, ny, size int }
+	planes := make([]plane, len(channels))
+	total := 0
+	for i, ch := range channels {
+		nx, ny := exrNumSamples(ch.xSampling, x0, x0+w-1), exrNumSamples(ch.ySampling, y0, y0+lines-1)
+		planes[i] = plane{start: total, pos: total, nx: nx, ny: ny, size: ch.size() / 2}
+		total += nx * ny * ch.size() / 2
+	}
+	words := make([]uint16, total)
+	in := 0
+	for y := y0; y < y0+lines; y++ {
+		for i, ch := range channels {
+			if exrFloorMod(y, ch.ySampling) != 0 {
+				continue
+			}
+			for n := 0; n < planes[i].nx*planes[i].size; n++ {
+				words[planes[i].pos] = binary.LittleEndian.Uint16(raw[in:])
+				planes[i].pos++
+				in += 2
+			}
+		}
+	}
+
+	bitmap := make([]byte, pizBitmapSize)
+	for _, v := range words {
+		bitmap[v>>3] |= 1 << (v & 7)
+	}
+	bitmap[0] &^= 1
+	minNonZero, maxNonZero := pizBitmapSize-1, 0
+	for i, b := range bitmap {
+		if b != 0 {
+			minNonZero = min(minNonZero, i)
+			maxNonZero = max(maxNonZero, i)
+		}
+	}
+	lut := make([]uint16, pizUShortRange)
+	k := 0
+	for i := range lut {
+		if i == 0 || bitmap[i>>3]&(1<<(i&7)) != 0 {
+			lut[i] = uint16(k)
+			k++
+		}
+	}
+	maxValue := uint16(k - 1)
+	for i, v := range words {
+		words[i] = lut[v]
+	}
+	for _, p := range planes {
+		for j := 0; j < p.size; j++ {
+			wav2Encode(words, p.start+j, p.nx, p.size, p.ny, p.nx*p.size, maxValue)
+		}
+	}
+
+	out := binary.LittleEndian.AppendUint16(nil, uint16(minNonZero))
+	out = binary.LittleEndian.AppendUint16(out, uint16(maxNonZero))
+	if minNonZero <= maxNonZero {
+		out = append(out, bitmap[minNonZero:maxNonZero+1]...)
+	}
+	huf := hufCompress(words)
+	out = binary.LittleEndian.AppendUint32(out, uint32(len(huf)))
+	return append(out, huf...)
+}
+
+type testBitWriter struct {
+	out  []byte
+	c    uint64
+	lc   int
+	bits int
+}
+
+func (w *testBitWriter) write(n int, v uint64) {
+	w.c = w.c<<n | v&(1<<n-1)
+	w.lc += n
+	w.bits += n
+	for w.lc >= 8 {
+		w.lc -= 8
+		w.out = append(w.out, byte(w.c>>w.lc))
+	}
+}
+
+func (w *testBitWriter) flush() []byte {
+	if w.lc > 0 {
+		w.out = append(w.out, byte(w.c<<(8-w.lc)))
+	}
+	return w.out
+}
+
+// hufCompress koduje słowa kodami o stałej długości i wstawia kod RLE dla powtórzeń.
+func hufCompress(words []uint16) []byte {
+	if len(words) == 0 {
+		return nil
+	}
+	hcode := make([]uint64, hufEncSize)
+	im, iM := int(words[0]), 0
+	for _, v := range words {
+		hcode[v] = 1
+		im, iM = min(im, int(v)), max(iM, int(v))
+	}
+	rlc := iM + 1
+	iM = rlc
+	hcode[rlc] = 1
+	symbols := 0
+	for _, used := range hcode {
+		symbols += int(used)
+	}
+	length := 1
+	for 1<<length < symbols {
+		length++
+	}
+	for i, used := range hcode {
+		hcode[i] = used * uint64(length)
+	}
+
+	table := &testBitWriter{}
+	for i := im; i <= iM; i++ {
+		if hcode[i] == 0 {
+			zerun := 1
+			for i+zerun <= iM && hcode[i+zerun] == 0 && zerun < hufShortestLongRun+255 {
+				zerun++
+			}
+			if zerun >= 2 {
+				if zerun >= hufShortestLongRun {
+					table.write(6, hufLongZeroCodeRun)
+					table.write(8, uint64(zerun-hufShortestLongRun))
+				} else {
+					table.write(6, uint64(hufShortZeroCodeRun+zerun-2))
+				}
+				i += zerun - 1
+				continue
+			}
+		}
+		table.write(6, hcode[i])
+	}
+	tableBytes := table.flush()
+	exrHufCanonicalCodeTable(hcode)
+
+	data := &testBitWriter{}
+	send := func(sym int) { data.write(int(hcode[sym]&63), hcode[sym]>>6) }
+	for i := 0; i < len(words); {
+		run := 0
+		for i+1+run < len(words) && words[i+1+run] == words[i] && run < 255 {
+			run++
+		}
+		send(int(words[i]))
+		if run >= 2 {
+			send(rlc)
+			data.write(8, uint64(run))
+		} else {
+			for r := 0; r < run; r++ {
+				send(int(words[i]))
+			}
+		}
+		i += 1 + run
+	}
+	nBits := data.bits
+	dataBytes := data.flush()
+
+	out := binary.LittleEndian.AppendUint32(nil, uint32(im))
+	out = binary.LittleEndian.AppendUint32(out, uint32(iM))
+	out = binary.LittleEndian.AppendUint32(out, uint32(len(tableBytes)))
+	out = binary.LittleEndian.AppendUint32(out, uint32(nBits))
+	out = binary.LittleEndian.AppendUint32(out, 0)
+	out = append(out, tableBytes...)
+	return append(out, dataBytes...)
+}
+
+func wav2Encode(buf []uint16, in, nx, ox, ny, oy int, mx uint16) {
+	enc := func(a, b uint16) (uint16, uint16) {
+		ao := (int(a) + 0x8000) & 0xffff
+		m := (ao + int(b)) >> 1
+		d := ao - int(b)
+		if d < 0 {
+			m = (m + 0x8000) & 0xffff
+		}
+		return uint16(m), uint16(d & 0xffff)
+	}
+	if mx < 1<<14 {
+		enc = func(a, b uint16) (uint16, uint16) {
+			as, bs := int(int16(a)), int(int16(b))
+			return uint16(int16((as + bs) >> 1)), uint16(int16(as - bs))
+		}
+	}
+
+	n := min(nx, ny)
+	p, p2 := 1, 2
+	for p2 <= n {
+		py := in
+		ey := in + oy*(ny-p2)
+		oy1, oy2, ox1, ox2 := oy*p, oy*p2, ox*p, ox*p2
+		for ; py <= ey; py += oy2 {
+			px := py
+			ex := py + ox*(nx-p2)
+			for ; px <= ex; px += ox2 {
+				p01, p10 := px+ox1, px+oy1
+				p11 := p10 + ox1
+				i00, i01 := enc(buf[px], buf[p01])
+				i10, i11 := enc(buf[p10], buf[p11])
+				buf[px], buf[p10] = enc(i00, i10)
+				buf[p01], buf[p11] = enc(i01, i11)
+			}
+			if nx&p != 0 {
+				p10 := px + oy1
+				buf[px], buf[p10] = enc(buf[px], buf[p10])
+			}
+		}
+		if ny&p != 0 {
+			px := py
+			ex := py + ox*(nx-p2)
+			for ; px <= ex; px += ox2 {
+				p01 := px + ox1
+				buf[px], buf[p01] = enc(buf[px], buf[p01])
+			}
+		}
+		p = p2
+		p2 <<= 1
+	}
+}
+
+func float32ToHalf(f float32) uint16 {
+	b := math.Float32bits(f)
+	sign := uint16(b>>16) & 0x8000
+	exp := int(b>>23&0xff) - 127 + 15
+	switch {
+	case f == 0 || exp <= 0:
+		return sign
+	case exp >= 31:
+		return sign | 0x7c00
+	}
+	return sign | uint16(exp)<<10 | uint16(b&0x7fffff>>13)
+}
+
+// redBlueEXR: lewa połowa czerwona, prawa niebieska, dolna połowa półprzezroczysta.
+func redBlueEXR(width, height int, pixelType int32) testEXR {
+	channels := []exrChannel{{name: "A"}, {name: "B"}, {name: "G"}, {name: "R"}}
+	for i := range channels {
+		channels[i].pixelType, channels[i].xSampling, channels[i].ySampling = pixelType, 1, 1
+	}
+	return testEXR{
+		width: width, height: height, channels: channels,
+		pixel: func(name string, x, y int) float32 {
+			red := x < width/2
+			grain := float32(x%5) / 16
+			switch {
+			case name == "A" && y >= height/2:
+				return 0.5
+			case name == "A":
+				return 1
+			case name == "R" && red, name == "B" && !red:
+				return 4 + grain
+			default:
+				return 0.05 + grain/10
+			}
+		},
+	}
+}
+
+func TestDecodeEXR_ScanlineCompressions(t *testing.T) {
+	for _, compression := range []int{exrCompressionNone, exrCompressionRLE, exrCompressionZIPS, exrCompressionZIP, exrCompressionPIZ} {
+		t.Run(exrCompressionNames[compression], func(t *testing.T) {
+			e := redBlueEXR(41, 37, exrPixelHalf)
+			e.compression = compression
+
+			img, info, err := DecodeEXR(bytes.NewReader(e.bytes(t)))
+			require.NoError(t, err)
+			assert.Equal(t, 41, info.Width)
+			assert.Equal(t, 37, info.Height)
+			assert.Equal(t, 16, info.BitDepth)
+			assert.True(t, info.HasAlpha)
+			assert.Equal(t, []string{"A", "B", "G", "R"}, info.Channels)
+			assert.Equal(t, exrCompressionNames[compression], info.Compression)
+
+			assert.Equal(t, 41, img.Bounds().Dx())
+			r, _, b, a := img.At(3, 3).RGBA()
+			assert.Greater(t, r, b, "Lewa strona powinna być czerwona")
+			assert.Equal(t, uint32(0xffff), a)
+			r, _, b, _ = img.At(38, 3).RGBA()
+			assert.Greater(t, b, r, "Prawa strona powinna być niebieska")
+			_, _, _, a = img.At(3, 30).RGBA()
+			assert.InDelta(t, 0x8000, a, 0x200, "Dolna połowa ma alfę 0.5")
+		})
+	}
+}
+
+func TestDecodeEXR_TiledFloat(t *testing.T) {
+	for _, compression := range []int{exrCompressionNone, exrCompressionZIP, exrCompressionPIZ} {
+		t.Run(exrCompressionNames[compression], func(t *testing.T) {
+			e := redBlueEXR(40, 35, exrPixelFloat)
+			e.compression = compression
+			e.tileW, e.tileH = 16, 16
+			e.xMin, e.yMin = -3, -2 // okno danych nie musi zaczynać się w (0, 0)
+			inner := e.pixel
+			e.pixel = func(name string, x, y int) float32 { return inner(name, x+3, y+2) }
+
+			img, info, err := DecodeEXR(bytes.NewReader(e.bytes(t)))
+			require.NoError(t, err)
+			assert.True(t, info.Tiled)
+			assert.Equal(t, 32, info.BitDepth)
+			assert.Equal(t, 40, info.Width)
+
+			r, _, b, _ := img.At(3, 3).RGBA()
+			assert.Greater(t, r, b)
+			r, _, b, _ = img.At(37, 33).RGBA()
+			assert.Greater(t, b, r)
+		})
+	}
+}
+
+func TestDecodeEXR_LayersAndLuminance(t *testing.T) {
+	t.Run("First layer with color is previewed", func(t *testing.T) {
+		e := testEXR{
+			width: 8, height: 8, compression: exrCompressionZIP,
+			channels: []exrChannel{
+				{name: "beauty.B", pixelType: exrPixelHalf, xSampling: 1, ySampling: 1},
+				{name: "beauty.G", pixelType: exrPixelHalf, xSampling: 1, ySampling: 1},
+				{name: "beauty.R", pixelType: exrPixelHalf, xSampling: 1, ySampling: 1},
+				{name: "depth.Z", pixelType: exrPixelFloat, xSampling: 1, ySampling: 1},
+			},
+			pixel: func(name string, x, y int) float32 {
+				if name == "beauty.G" {
+					return 2
+				}
+				return 0.01 * float32(x+1)
+			},
+		}
+		img, info, err := DecodeEXR(bytes.NewReader(e.bytes(t)))
+		require.NoError(t, err)
+		assert.Equal(t, 32, info.BitDepth, "Kanał float podnosi głębię")
+		assert.False(t, info.HasAlpha)
+		assert.Equal(t, "beauty.B,beauty.G,beauty.R,depth.Z", info.Properties()[PropertyChannels])
+		r, g, b, _ := img.At(4, 4).RGBA()
+		assert.Greater(t, g, r)
+		assert.Greater(t, g, b)
+	})
+
+	t.Run("Luminance with subsampled chroma", func(t *testing.T) {
+		e := testEXR{
+			width: 8, height: 6, compression: exrCompressionPIZ,
+			channels: []exrChannel{
+				{name: "BY", pixelType: exrPixelHalf, xSampling: 2, ySampling: 2},
+				{name: "RY", pixelType: exrPixelHalf, xSampling: 2, ySampling: 2},
+				{name: "Y", pixelType: exrPixelHalf, xSampling: 1, ySampling: 1},
+			},
+			pixel: func(name string, x, y int) float32 {
+				if name == "Y" {
+					return float32(x+1) / 4
+				}
+				return 0.5
+			},
+		}
+		img, info, err := DecodeEXR(bytes.NewReader(e.bytes(t)))
+		require.NoError(t, err)
+		assert.Equal(t, 3, len(info.Channels))
+		r1, g1, b1, _ := img.At(0, 2).RGBA()
+		r2, _, _, _ := img.At(7, 2).RGBA()
+		assert.Equal(t, r1, g1)
+		assert.Equal(t, g1, b1)
+		assert.Greater(t, r2, r1)
+	})
+}
+
+func TestDecodeEXR_SubsamplesLargeImages(t *testing.T) {
+	e := redBlueEXR(3000, 4, exrPixelHalf)
+	e.compression = exrCompressionZIP
+	img, info, err := DecodeEXR(bytes.NewReader(e.bytes(t)))
+	require.NoError(t, err)
+	assert.Equal(t, 3000, info.Width)
+	assert.Equal(t, 1000, img.Bounds().Dx())
+	assert.Equal(t, 2, img.Bounds().Dy())
+}
+
+func TestDecodeEXR_InvalidInput(t *testing.T) {
+	_, _, err := DecodeEXR(bytes.NewReader([]byte("definitely not an exr file")))
+	assert.Error(t, err)
+
+	valid := redBlueEXR(8, 8, exrPixelHalf)
+	data := valid.bytes(t)
+
+	multipart := bytes.Clone(data)
+	binary.LittleEndian.PutUint32(multipart[4:], 2|exrFlagMultipart)
+	_, _, err = DecodeEXR(bytes.NewReader(multipart))
+	assert.ErrorContains(t, err, "multi-part")
+
+	b44 := valid
+	b44.compression = 6
+	_, _, err = DecodeEXR(bytes.NewReader(b44.bytes(t)))
+	assert.ErrorContains(t, err, "B44")
+
+	_, _, err = DecodeEXR(bytes.NewReader(data[:len(data)-20]))
+	assert.Error(t, err, "Ucięty plik")
+}
+
+func TestEXRDecompress_RoundTrip(t *testing.T) {
+	rng := rand.New(rand.NewSource(1))
+	channels := []exrChannel{
+		{name: "B", pixelType: exrPixelFloat, xSampling: 1, ySampling: 1},
+		{name: "G", pixelType: exrPixelHalf, xSampling: 1, ySampling: 1},
+		{name: "R", pixelType: exrPixelHalf, xSampling: 1, ySampling: 1},
+	}
+	h := &exrHeader{channels: channels}
+	w, lines := 13, 11
+	size := h.blockSize(0, 0, w, lines)
+
+	inputs := map[string][]byte{
+		"random words (16-bit wavelet)": make([]byte, size),
+		"narrow range (14-bit wavelet)": make([]byte, size),
+		"constant":                      bytes.Repeat([]byte{0x3c}, size),
+	}
+	rng.Read(inputs["random words (16-bit wavelet)"])
+	for i := 0; i < size; i += 2 {
+		binary.LittleEndian.PutUint16(inputs["narrow range (14-bit wavelet)"][i:], uint16(rng.Intn(3000)))
+	}
+
+	for name, raw := range inputs {
+		for _, compression := range []int{exrCompressionRLE, exrCompressionZIP, exrCompressionPIZ} {
+			t.Run(name+"/"+exrCompressionNames[compression], func(t *testing.T) {
+				data := compressEXRBlock(t, raw, compression, channels, 0, 0, w, lines)
+				h.compression = compression
+				dec := &exrDecoder{header: h}
+				out, err := dec.decompress(data, 0, 0, w, lines, size)
+				require.NoError(t, err)
+				assert.Equal(t, raw, out)
+			})
+		}
+	}
+}
+
+func TestEXRHuffman_LongCodes(t *testing.T) {
+	// Ponad 2^14 symboli wymusza kody dłuższe niż tablica bezpośrednia dekodera.
+	words := make([]uint16, 20000)
+	for i := range words {
+		words[i] = uint16(i * 3)
+	}
+	words = append(words, 7, 7, 7, 7, 7) // powtórzenia kodowane RLE
+	out := make([]uint16, len(words))
+	require.NoError(t, newEXRPIZ().hufUncompress(hufCompress(words), out))
+	assert.Equal(t, words, out)
+}
+
+func TestHalfToFloat32(t *testing.T) {
+	assert.Equal(t, float32(1), halfToFloat32(0x3c00))
+	assert.Equal(t, float32(-2), halfToFloat32(0xc000))
+	assert.Equal(t, float32(65504), halfToFloat32(0x7bff))
+	assert.Equal(t, float32(math.Ldexp(1, -24)), halfToFloat32(0x0001), "Liczba subnormalna")
+	assert.True(t, math.IsInf(float64(halfToFloat32(0x7c00)), 1))
+	assert.True(t, math.IsNaN(float64(halfToFloat32(0x7e00))))
+}
+
+func TestDiskThumbnailGenerator_EXR(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "render.exr")
+	e := redBlueEXR(64, 32, exrPixelHalf)
+	e.compression = exrCompressionPIZ
+	assert.NoError(t, os.WriteFile(path, e.bytes(t), 0644))
+
+	generator := NewDiskThumbnailGenerator(dir, slog.New(slog.NewTextHandler(io.Discard, nil)))
+	res, err := generator.Generate(context.Background(), path)
+	assert.NoError(t, err)
+	assert.False(t, res.IsPlaceholder)
+	assert.Equal(t, 64, res.Metadata.Width)
+	assert.Equal(t, 32, res.Metadata.Height)
+	assert.Equal(t, 16, res.Metadata.BitDepth)
+	assert.True(t, res.Metadata.HasAlphaChannel)
+	assert.Equal(t, "A,B,G,R", res.Metadata.Properties[PropertyChannels])
+	assert.Equal(t, "PIZ", res.Metadata.Properties[PropertyCompression])
+	assert.NotEmpty(t, res.Metadata.PerceptualHash)
+}
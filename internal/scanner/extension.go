@@ -25,9 +25,9 @@ func (s *Scanner) GetConfig() ScannerConfigSnapshot {
 	}
 }
 
-// GetPredefinedPalette returns the list of predefined colors used for palette matching.
+// GetPredefinedPalette returns the colors of the active palette used for palette matching.
 func (s *Scanner) GetPredefinedPalette() []config.PaletteColor {
-	return config.PredefinedPalette
+	return s.config.GetColorPalette()
 }
 
 // IsExtensionAllowed checks if the given file extension is currently allowed by the configuration.
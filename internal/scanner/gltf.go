@@ -0,0 +1,406 @@
+package scanner
+
+import (
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+const (
+	glbMagic         = "glTF"
+	glbChunkJSON     = 0x4E4F534A
+	glbChunkBIN      = 0x004E4942
+	gltfMaxJSONBytes = 64 << 20
+)
+
+// glTF primitive topologies (mesh.primitive.mode).
+const (
+	gltfTriangles     = 4
+	gltfTriangleStrip = 5
+	gltfTriangleFan   = 6
+)
+
+// gltfDocument holds the parts of the glTF JSON needed for the statistics and the preview render.
+type gltfDocument struct {
+	ExtensionsRequired []string `json:"extensionsRequired"`
+	Scene              *int     `json:"scene"`
+	Scenes             []struct {
+		Nodes []int `json:"nodes"`
+	} `json:"scenes"`
+	Nodes   []gltfNode `json:"nodes"`
+	Meshes  []gltfMesh `json:"meshes"`
+	Buffers []struct {
+		URI string `json:"uri"`
+	} `json:"buffers"`
+	BufferViews []struct {
+		Buffer     int   `json:"buffer"`
+		ByteOffset int64 `json:"byteOffset"`
+		ByteLength int64 `json:"byteLength"`
+		ByteStride int64 `json:"byteStride"`
+	} `json:"bufferViews"`
+	Accessors []gltfAccessor `json:"accessors"`
+	Materials []gltfMaterial `json:"materials"`
+	Textures  []struct {
+		Source *int `json:"source"`
+	} `json:"textures"`
+	Images []struct {
+		URI        string `json:"uri"`
+		BufferView *int   `json:"bufferView"`
+	} `json:"images"`
+}
+
+type gltfNode struct {
+	Mesh        *int      `json:"mesh"`
+	Children    []int     `json:"children"`
+	Matrix      []float64 `json:"matrix"`
+	Translation []float64 `json:"translation"`
+	Rotation    []float64 `json:"rotation"`
+	Scale       []float64 `json:"scale"`
+}
+
+type gltfMesh struct {
+	Primitives []struct {
+		Attributes map[string]int `json:"attributes"`
+		Indices    *int           `json:"indices"`
+		Mode       *int           `json:"mode"`
+		Material   *int           `json:"material"`
+	} `json:"primitives"`
+}
+
+type gltfAccessor struct {
+	BufferView    *int      `json:"bufferView"`
+	ByteOffset    int64     `json:"byteOffset"`
+	ComponentType int       `json:"componentType"`
+	Normalized    bool      `json:"normalized"`
+	Count         int64     `json:"count"`
+	Type          string    `json:"type"`
+	Min           []float64 `json:"min"`
+	Max           []float64 `json:"max"`
+}
+
+type gltfMaterial struct {
+	PBR struct {
+		BaseColorFactor  []float64 `json:"baseColorFactor"`
+		BaseColorTexture *struct {
+			Index    int `json:"index"`
+			TexCoord int `json:"texCoord"`
+		} `json:"baseColorTexture"`
+	} `json:"pbrMetallicRoughness"`
+}
+
+// gltfMatrix is a column-major 4x4 matrix, the layout glTF uses.
+type gltfMatrix [16]float64
+
+var gltfIdentity = gltfMatrix{1, 0, 0, 0, 0, 1, 0, 0, 0, 0, 1, 0, 0, 0, 0, 1}
+
+// ReadGLTFFile reads the statistics of a .gltf file or the JSON chunk of a .glb file.
+func ReadGLTFFile(path string) (ModelMetadata, error) {
+	data, _, err := readGLTFFile(path, false)
+	if err != nil {
+		return ModelMetadata{}, err
+	}
+	return ParseGLTF(data)
+}
+
+// readGLTFFile returns the JSON document and, if withBinary is set, the binary chunk of a GLB file.
+func readGLTFFile(path string, withBinary bool) ([]byte, []byte, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer f.Close()
+
+	if strings.EqualFold(filepath.Ext(path), ".glb") {
+		return readGLB(f, withBinary)
+	}
+	data, err := io.ReadAll(io.LimitReader(f, gltfMaxJSONBytes+1))
+	if err == nil && len(data) > gltfMaxJSONBytes {
+		err = errors.New("glTF JSON too large")
+	}
+	return data, nil, err
+}
+
+// readGLB returns the JSON chunk, which the GLB container requires to come first, and the
+// optional BIN chunk after it.
+func readGLB(r io.Reader, withBinary bool) ([]byte, []byte, error) {
+	var hdr [20]byte
+	if _, err := io.ReadFull(r, hdr[:]); err != nil {
+		return nil, nil, fmt.Errorf("reading GLB header: %w", err)
+	}
+	if string(hdr[:4]) != glbMagic {
+		return nil, nil, errors.New("not a GLB file")
+	}
+	if v := binary.LittleEndian.Uint32(hdr[4:]); v != 2 {
+		return nil, nil, fmt.Errorf("unsupported GLB version %d", v)
+	}
+	length := binary.LittleEndian.Uint32(hdr[12:])
+	if binary.LittleEndian.Uint32(hdr[16:]) != glbChunkJSON {
+		return nil, nil, errors.New("GLB does not start with a JSON chunk")
+	}
+	if length > gltfMaxJSONBytes {
+		return nil, nil, fmt.Errorf("GLB JSON chunk too large (%d bytes)", length)
+	}
+	data := make([]byte, length)
+	if _, err := io.ReadFull(r, data); err != nil {
+		return nil, nil, fmt.Errorf("reading GLB JSON chunk: %w", err)
+	}
+	if !withBinary {
+		return data, nil, nil
+	}
+
+	var chunk [8]byte
+	if _, err := io.ReadFull(r, chunk[:]); err != nil {
+		return data, nil, nil // The BIN chunk is optional
+	}
+	length = binary.LittleEndian.Uint32(chunk[:])
+	if binary.LittleEndian.Uint32(chunk[4:]) != glbChunkBIN {
+		return data, nil, nil
+	}
+	if length > modelMaxFileSize {
+		return nil, nil, fmt.Errorf("GLB binary chunk too large (%d bytes)", length)
+	}
+	bin := make([]byte, length)
+	if _, err := io.ReadFull(r, bin); err != nil {
+		return nil, nil, fmt.Errorf("reading GLB binary chunk: %w", err)
+	}
+	return data, bin, nil
+}
+
+// ParseGLTF computes the statistics of a glTF 2.0 JSON document. Vertices and triangles are
+// counted once per mesh, not per node that instances it; the bounding box covers the scene.
+func ParseGLTF(data []byte) (ModelMetadata, error) {
+	var doc gltfDocument
+	if err := json.Unmarshal(data, &doc); err != nil {
+		return ModelMetadata{}, fmt.Errorf("parsing glTF JSON: %w", err)
+	}
+
+	meta := ModelMetadata{
+		MeshCount:     len(doc.Meshes),
+		MaterialCount: len(doc.Materials),
+		TextureCount:  len(doc.Images),
+	}
+	for _, img := range doc.Images {
+		if img.URI == "" || strings.HasPrefix(img.URI, "data:") {
+			continue
+		}
+		uri := img.URI
+		if unescaped, err := url.PathUnescape(uri); err == nil {
+			uri = unescaped
+		}
+		meta.Textures = appendUnique(meta.Textures, uri)
+	}
+
+	counted := make(map[int]bool)
+	for _, mesh := range doc.Meshes {
+		for _, prim := range mesh.Primitives {
+			pos, ok := prim.Attributes["POSITION"]
+			if !ok || !doc.validAccessor(pos) {
+				continue
+			}
+			if !counted[pos] {
+				counted[pos] = true
+				meta.VertexCount += doc.Accessors[pos].Count
+			}
+			n := doc.Accessors[pos].Count
+			if prim.Indices != nil && doc.validAccessor(*prim.Indices) {
+				n = doc.Accessors[*prim.Indices].Count
+			}
+			mode := gltfTriangles
+			if prim.Mode != nil {
+				mode = *prim.Mode
+			}
+			meta.TriangleCount += gltfTriangleCount(mode, n)
+		}
+	}
+
+	box := doc.bounds()
+	meta.Size = box.size()
+	return meta, nil
+}
+
+func (doc *gltfDocument) validAccessor(i int) bool {
+	return i >= 0 && i < len(doc.Accessors)
+}
+
+func gltfTriangleCount(mode int, n int64) int64 {
+	switch mode {
+	case gltfTriangles:
+		return n / 3
+	case gltfTriangleStrip, gltfTriangleFan:
+		return max(n-2, 0)
+	}
+	return 0
+}
+
+// bounds places the mesh boxes in the default scene. Files without nodes fall back to the
+// boxes of the meshes themselves.
+func (doc *gltfDocument) bounds() modelBounds {
+	var box modelBounds
+	_ = doc.walkMeshes(func(mesh int, world gltfMatrix) error {
+		doc.addMesh(&box, mesh, world)
+		return nil
+	})
+	if !box.valid {
+		for i := range doc.Meshes {
+			doc.addMesh(&box, i, gltfIdentity)
+		}
+	}
+	return box
+}
+
+// walkMeshes calls visit for every mesh instance of the default scene with its world transform.
+// Node cycles, which the spec forbids, are cut.
+func (doc *gltfDocument) walkMeshes(visit func(mesh int, world gltfMatrix) error) error {
+	onPath := make([]bool, len(doc.Nodes))
+	var walk func(node int, parent gltfMatrix) error
+	walk = func(node int, parent gltfMatrix) error {
+		if node < 0 || node >= len(doc.Nodes) || onPath[node] {
+			return nil
+		}
+		onPath[node] = true
+		defer func() { onPath[node] = false }()
+
+		n := doc.Nodes[node]
+		world := parent.mul(n.local())
+		if n.Mesh != nil && *n.Mesh >= 0 && *n.Mesh < len(doc.Meshes) {
+			if err := visit(*n.Mesh, world); err != nil {
+				return err
+			}
+		}
+		for _, child := range n.Children {
+			if err := walk(child, world); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	for _, root := range doc.rootNodes() {
+		if err := walk(root, gltfIdentity); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// rootNodes returns the nodes of the default scene, of every scene when none is set, or the
+// nodes nobody lists as a child when the file has no scenes.
+func (doc *gltfDocument) rootNodes() []int {
+	if doc.Scene != nil && *doc.Scene >= 0 && *doc.Scene < len(doc.Scenes) {
+		return doc.Scenes[*doc.Scene].Nodes
+	}
+	if len(doc.Scenes) > 0 {
+		var roots []int
+		for _, s := range doc.Scenes {
+			roots = append(roots, s.Nodes...)
+		}
+		return roots
+	}
+	child := make([]bool, len(doc.Nodes))
+	for _, n := range doc.Nodes {
+		for _, c := range n.Children {
+			if c >= 0 && c < len(child) {
+				child[c] = true
+			}
+		}
+	}
+	var roots []int
+	for i, isChild := range child {
+		if !isChild {
+			roots = append(roots, i)
+		}
+	}
+	return roots
+}
+
+// addMesh adds the corners of the POSITION min/max boxes of a mesh, transformed by m.
+func (doc *gltfDocument) addMesh(box *modelBounds, mesh int, m gltfMatrix) {
+	for _, prim := range doc.Meshes[mesh].Primitives {
+		pos, ok := prim.Attributes["POSITION"]
+		if !ok || !doc.validAccessor(pos) {
+			continue
+		}
+		acc := doc.Accessors[pos]
+		if len(acc.Min) < 3 || len(acc.Max) < 3 {
+			continue
+		}
+		for corner := range 8 {
+			var p [3]float64
+			for axis := range p {
+				if corner&(1<<axis) == 0 {
+					p[axis] = acc.Min[axis]
+				} else {
+					p[axis] = acc.Max[axis]
+				}
+			}
+			box.add(m.apply(p))
+		}
+	}
+}
+
+// local returns the node transform: its matrix, or translation * rotation * scale.
+func (n gltfNode) local() gltfMatrix {
+	if len(n.Matrix) == 16 {
+		return gltfMatrix(n.Matrix)
+	}
+	t := [3]float64{0, 0, 0}
+	r := [4]float64{0, 0, 0, 1}
+	s := [3]float64{1, 1, 1}
+	if len(n.Translation) == 3 {
+		t = [3]float64(n.Translation)
+	}
+	if len(n.Rotation) == 4 {
+		r = [4]float64(n.Rotation)
+	}
+	if len(n.Scale) == 3 {
+		s = [3]float64(n.Scale)
+	}
+
+	x, y, z, w := r[0], r[1], r[2], r[3]
+	rot := [9]float64{ // Column-major 3x3 from the unit quaternion
+		1 - 2*(y*y+z*z), 2 * (x*y + z*w), 2 * (x*z - y*w),
+		2 * (x*y - z*w), 1 - 2*(x*x+z*z), 2 * (y*z + x*w),
+		2 * (x*z + y*w), 2 * (y*z - x*w), 1 - 2*(x*x+y*y),
+	}
+	var m gltfMatrix
+	for col := range 3 {
+		for row := range 3 {
+			m[col*4+row] = rot[col*3+row] * s[col]
+		}
+	}
+	m[12], m[13], m[14], m[15] = t[0], t[1], t[2], 1
+	return m
+}
+
+func (a gltfMatrix) mul(b gltfMatrix) gltfMatrix {
+	var out gltfMatrix
+	for col := range 4 {
+		for row := range 4 {
+			var sum float64
+			for k := range 4 {
+				sum += a[k*4+row] * b[col*4+k]
+			}
+			out[col*4+row] = sum
+		}
+	}
+	return out
+}
+
+func (a gltfMatrix) apply(p [3]float64) [3]float64 {
+	var out [3]float64
+	for row := range 3 {
+		out[row] = a[row]*p[0] + a[4+row]*p[1] + a[8+row]*p[2] + a[12+row]
+	}
+	if w := a[3]*p[0] + a[7]*p[1] + a[11]*p[2] + a[15]; w != 0 && w != 1 && !math.IsNaN(w) {
+		for i := range out {
+			out[i] /= w
+		}
+	}
+	return out
+}
@@ -0,0 +1,432 @@
+package scanner
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"image"
+	"math"
+	"net/url"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// glTF accessor component types.
+const (
+	gltfByte          = 5120
+	gltfUnsignedByte  = 5121
+	gltfShort         = 5122
+	gltfUnsignedShort = 5123
+	gltfUnsignedInt   = 5125
+	gltfFloat         = 5126
+)
+
+// gltfCompressionExtensions store geometry in a form only their decoders can read.
+var gltfCompressionExtensions = []string{"KHR_draco_mesh_compression", "EXT_meshopt_compression"}
+
+// gltfLoader reads the binary data a glTF document points to. Buffers, accessors, materials and
+// images are decoded once, however many primitives or nodes use them.
+type gltfLoader struct {
+	ctx       context.Context
+	doc       *gltfDocument
+	dir       string
+	bin       []byte // GLB binary chunk
+	buffers   map[int][]byte
+	accessors map[int][]float64
+	materials map[int]int32
+	images    map[int]*image.NRGBA
+	mesh      *renderMesh
+	checked   int
+}
+
+// loadGLTFMesh reads the triangles of the default scene of a .gltf or .glb file for the preview
+// render, with the base colors and base color textures of their materials.
+func loadGLTFMesh(ctx context.Context, path string) (*renderMesh, error) {
+	data, bin, err := readGLTFFile(path, true)
+	if err != nil {
+		return nil, err
+	}
+	var doc gltfDocument
+	if err := json.Unmarshal(data, &doc); err != nil {
+		return nil, fmt.Errorf("parsing glTF JSON: %w", err)
+	}
+	for _, ext := range doc.ExtensionsRequired {
+		if slices.Contains(gltfCompressionExtensions, ext) {
+			return nil, fmt.Errorf("unsupported glTF extension %s", ext)
+		}
+	}
+
+	l := &gltfLoader{
+		ctx:       ctx,
+		doc:       &doc,
+		dir:       filepath.Dir(path),
+		bin:       bin,
+		buffers:   make(map[int][]byte),
+		accessors: make(map[int][]float64),
+		materials: make(map[int]int32),
+		images:    make(map[int]*image.NRGBA),
+		mesh:      &renderMesh{},
+	}
+	if err := doc.walkMeshes(l.addMesh); err != nil {
+		return nil, err
+	}
+	if len(l.mesh.faces) == 0 {
+		// Libraries of meshes without a scene
+		for i := range doc.Meshes {
+			if err := l.addMesh(i, gltfIdentity); err != nil {
+				return nil, err
+			}
+		}
+	}
+	return l.mesh, nil
+}
+
+// addMesh appends the triangle primitives of a mesh instance, transformed to world space.
+func (l *gltfLoader) addMesh(index int, world gltfMatrix) error {
+	for _, prim := range l.doc.Meshes[index].Primitives {
+		mode := gltfTriangles
+		if prim.Mode != nil {
+			mode = *prim.Mode
+		}
+		if mode != gltfTriangles && mode != gltfTriangleStrip && mode != gltfTriangleFan {
+			continue
+		}
+		posIndex, ok := prim.Attributes["POSITION"]
+		if !ok {
+			continue
+		}
+		positions, comps, err := l.accessor(posIndex)
+		if err != nil {
+			return err
+		}
+		if comps != 3 {
+			return fmt.Errorf("POSITION accessor %d is not VEC3", posIndex)
+		}
+		count := len(positions) / 3
+
+		material, texCoord := l.material(prim.Material)
+		uvBase := int32(-1)
+		if uvIndex, ok := prim.Attributes["TEXCOORD_"+strconv.Itoa(texCoord)]; ok && material >= 0 && l.mesh.materials[material].texture != nil {
+			if uvs, comps, err := l.accessor(uvIndex); err == nil && comps == 2 && len(uvs) == 2*count {
+				uvBase = int32(len(l.mesh.uvs) / 2)
+				for _, v := range uvs {
+					l.mesh.uvs = append(l.mesh.uvs, float32(v))
+				}
+			}
+		}
+
+		posBase := int32(len(l.mesh.positions) / 3)
+		for i := range count {
+			if err := l.check(); err != nil {
+				return err
+			}
+			p := world.apply([3]float64(positions[3*i : 3*i+3]))
+			l.mesh.positions = append(l.mesh.positions, float32(p[0]), float32(p[1]), float32(p[2]))
+		}
+
+		var indices []float64
+		if prim.Indices != nil {
+			if indices, _, err = l.accessor(*prim.Indices); err != nil {
+				return err
+			}
+		} else {
+			indices = make([]float64, count)
+			for i := range indices {
+				indices[i] = float64(i)
+			}
+		}
+
+		corner := func(i int) (meshCorner, bool) {
+			v := indices[i]
+			if v < 0 || v >= float64(count) {
+				return meshCorner{}, false
+			}
+			c := meshCorner{pos: posBase + int32(v), uv: -1}
+			if uvBase >= 0 {
+				c.uv = uvBase + int32(v)
+			}
+			return c, true
+		}
+		addFace := func(a, b, c int) error {
+			if err := l.check(); err != nil {
+				return err
+			}
+			ca, okA := corner(a)
+			cb, okB := corner(b)
+			cc, okC := corner(c)
+			if okA && okB && okC {
+				l.mesh.faces = append(l.mesh.faces, meshFace{corners: [3]meshCorner{ca, cb, cc}, material: material})
+			}
+			return nil
+		}
+
+		n := len(indices)
+		for i := 0; i+2 < n; {
+			var err error
+			switch mode {
+			case gltfTriangles:
+				err = addFace(i, i+1, i+2)
+				i += 3
+			case gltfTriangleStrip:
+				if i%2 == 0 {
+					err = addFace(i, i+1, i+2)
+				} else {
+					err = addFace(i+1, i, i+2)
+				}
+				i++
+			case gltfTriangleFan:
+				err = addFace(0, i+1, i+2)
+				i++
+			}
+			if err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// check returns the context error every modelCheckInterval calls.
+func (l *gltfLoader) check() error {
+	l.checked++
+	if l.checked%modelCheckInterval == 0 {
+		return l.ctx.Err()
+	}
+	return nil
+}
+
+// material returns the render material for a glTF material and the texture coordinate set of
+// its base color texture.
+func (l *gltfLoader) material(index *int) (int32, int) {
+	if index == nil || *index < 0 || *index >= len(l.doc.Materials) {
+		return -1, 0
+	}
+	m := l.doc.Materials[*index]
+	texCoord := 0
+	if m.PBR.BaseColorTexture != nil {
+		texCoord = m.PBR.BaseColorTexture.TexCoord
+	}
+	if idx, ok := l.materials[*index]; ok {
+		return idx, texCoord
+	}
+
+	mat := renderMaterial{color: [3]float32{1, 1, 1}}
+	if f := m.PBR.BaseColorFactor; len(f) >= 3 {
+		mat.color = [3]float32{linearToSRGB(f[0]), linearToSRGB(f[1]), linearToSRGB(f[2])}
+	}
+	if t := m.PBR.BaseColorTexture; t != nil && t.Index >= 0 && t.Index < len(l.doc.Textures) {
+		if src := l.doc.Textures[t.Index].Source; src != nil {
+			mat.texture = l.image(*src)
+		}
+	}
+
+	idx := int32(len(l.mesh.materials))
+	l.mesh.materials = append(l.mesh.materials, mat)
+	l.materials[*index] = idx
+	return idx, texCoord
+}
+
+// image decodes a glTF image; nil if it can't be read, so the base color is used alone.
+func (l *gltfLoader) image(index int) *image.NRGBA {
+	if img, ok := l.images[index]; ok {
+		return img
+	}
+	var img *image.NRGBA
+	if index >= 0 && index < len(l.doc.Images) && l.ctx.Err() == nil {
+		src := l.doc.Images[index]
+		switch {
+		case src.BufferView != nil:
+			if data, err := l.bufferView(*src.BufferView); err == nil {
+				img, _ = decodeModelTexture(data)
+			}
+		case strings.HasPrefix(src.URI, "data:"):
+			if data, err := decodeDataURI(src.URI); err == nil {
+				img, _ = decodeModelTexture(data)
+			}
+		case src.URI != "":
+			if path, err := l.resolveURI(src.URI); err == nil {
+				img, _ = loadModelTexture(path)
+			}
+		}
+	}
+	l.images[index] = img
+	return img
+}
+
+// accessor returns the values of an accessor as floats, with the number of components per element.
+// Normalized integers are mapped to 0..1 (or -1..1). Sparse substitutions are not applied.
+func (l *gltfLoader) accessor(index int) ([]float64, int, error) {
+	if index < 0 || index >= len(l.doc.Accessors) {
+		return nil, 0, fmt.Errorf("accessor %d out of range", index)
+	}
+	acc := l.doc.Accessors[index]
+	comps := map[string]int{"SCALAR": 1, "VEC2": 2, "VEC3": 3, "VEC4": 4}[acc.Type]
+	if comps == 0 {
+		return nil, 0, fmt.Errorf("unsupported accessor type %q", acc.Type)
+	}
+	if values, ok := l.accessors[index]; ok {
+		return values, comps, nil
+	}
+	componentSize := map[int]int64{gltfByte: 1, gltfUnsignedByte: 1, gltfShort: 2, gltfUnsignedShort: 2, gltfUnsignedInt: 4, gltfFloat: 4}[acc.ComponentType]
+	if componentSize == 0 {
+		return nil, 0, fmt.Errorf("unsupported component type %d", acc.ComponentType)
+	}
+	if acc.Count < 0 || acc.Count > modelMaxFileSize {
+		return nil, 0, fmt.Errorf("invalid accessor count %d", acc.Count)
+	}
+	if acc.BufferView == nil {
+		// All zeros unless sparse
+		values := make([]float64, acc.Count*int64(comps))
+		l.accessors[index] = values
+		return values, comps, nil
+	}
+
+	data, err := l.bufferView(*acc.BufferView)
+	if err != nil {
+		return nil, 0, err
+	}
+	elemSize := componentSize * int64(comps)
+	stride := l.doc.BufferViews[*acc.BufferView].ByteStride
+	if stride == 0 {
+		stride = elemSize
+	}
+	if acc.Count > 0 && (acc.ByteOffset < 0 || acc.ByteOffset+(acc.Count-1)*stride+elemSize > int64(len(data))) {
+		return nil, 0, fmt.Errorf("accessor %d exceeds its buffer view", index)
+	}
+
+	values := make([]float64, 0, acc.Count*int64(comps))
+	for e := range acc.Count {
+		if e%modelCheckInterval == 0 && l.ctx.Err() != nil {
+			return nil, 0, l.ctx.Err()
+		}
+		off := acc.ByteOffset + e*stride
+		for c := range int64(comps) {
+			values = append(values, gltfComponent(data[off+c*componentSize:], acc.ComponentType, acc.Normalized))
+		}
+	}
+	l.accessors[index] = values
+	return values, comps, nil
+}
+
+func gltfComponent(b []byte, componentType int, normalized bool) float64 {
+	switch componentType {
+	case gltfFloat:
+		return float64(math.Float32frombits(binary.LittleEndian.Uint32(b)))
+	case gltfUnsignedInt:
+		return float64(binary.LittleEndian.Uint32(b))
+	case gltfByte:
+		if normalized {
+			return max(float64(int8(b[0]))/127, -1)
+		}
+		return float64(int8(b[0]))
+	case gltfUnsignedByte:
+		if normalized {
+			return float64(b[0]) / 255
+		}
+		return float64(b[0])
+	case gltfShort:
+		v := int16(binary.LittleEndian.Uint16(b))
+		if normalized {
+			return max(float64(v)/32767, -1)
+		}
+		return float64(v)
+	case gltfUnsignedShort:
+		v := binary.LittleEndian.Uint16(b)
+		if normalized {
+			return float64(v) / 65535
+		}
+		return float64(v)
+	}
+	return 0
+}
+
+func (l *gltfLoader) bufferView(index int) ([]byte, error) {
+	if index < 0 || index >= len(l.doc.BufferViews) {
+		return nil, fmt.Errorf("buffer view %d out of range", index)
+	}
+	view := l.doc.BufferViews[index]
+	buf, err := l.buffer(view.Buffer)
+	if err != nil {
+		return nil, err
+	}
+	if view.ByteOffset < 0 || view.ByteLength < 0 || view.ByteOffset+view.ByteLength > int64(len(buf)) {
+		return nil, fmt.Errorf("buffer view %d exceeds its buffer", index)
+	}
+	return buf[view.ByteOffset : view.ByteOffset+view.ByteLength], nil
+}
+
+func (l *gltfLoader) buffer(index int) ([]byte, error) {
+	if data, ok := l.buffers[index]; ok {
+		return data, nil
+	}
+	if index < 0 || index >= len(l.doc.Buffers) {
+		return nil, fmt.Errorf("buffer %d out of range", index)
+	}
+	uri := l.doc.Buffers[index].URI
+	var data []byte
+	var err error
+	switch {
+	case uri == "" && index == 0 && l.bin != nil:
+		data = l.bin
+	case uri == "":
+		err = fmt.Errorf("buffer %d has no data", index)
+	case strings.HasPrefix(uri, "data:"):
+		data, err = decodeDataURI(uri)
+	default:
+		var path string
+		if path, err = l.resolveURI(uri); err == nil {
+			data, err = readModelFile(path)
+		}
+	}
+	if err != nil {
+		return nil, err
+	}
+	l.buffers[index] = data
+	return data, nil
+}
+
+// resolveURI turns a relative glTF URI into a path next to the model file.
+func (l *gltfLoader) resolveURI(uri string) (string, error) {
+	unescaped, err := url.PathUnescape(uri)
+	if err != nil {
+		return "", err
+	}
+	if strings.Contains(unescaped, "://") {
+		return "", fmt.Errorf("external URI %q not supported", uri)
+	}
+	return filepath.Join(l.dir, filepath.FromSlash(unescaped)), nil
+}
+
+// readModelFile reads a file referenced by a model, refusing ones above modelMaxFileSize.
+func readModelFile(path string) ([]byte, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, err
+	}
+	if info.Size() > modelMaxFileSize {
+		return nil, fmt.Errorf("%s too large (%d bytes)", filepath.Base(path), info.Size())
+	}
+	return os.ReadFile(path)
+}
+
+// decodeDataURI returns the payload of a base64 or percent-encoded data URI.
+func decodeDataURI(uri string) ([]byte, error) {
+	header, payload, ok := strings.Cut(strings.TrimPrefix(uri, "data:"), ",")
+	if !ok {
+		return nil, errors.New("invalid data URI")
+	}
+	if strings.HasSuffix(header, ";base64") {
+		if data, err := base64.StdEncoding.DecodeString(payload); err == nil {
+			return data, nil
+		}
+		return base64.RawStdEncoding.DecodeString(strings.TrimRight(payload, "="))
+	}
+	unescaped, err := url.PathUnescape(payload)
+	return []byte(unescaped), err
+}
@@ -0,0 +1,142 @@
+package scanner
+
+import (
+	"encoding/binary"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// Dwa węzły z tą samą siatką: drugi przesunięty o 10 w X i obrócony o 90° wokół Y.
+const testGLTF = `{
+  "asset": {"version": "2.0"},
+  "scene": 0,
+  "scenes": [{"nodes": [0]}],
+  "nodes": [
+    {"mesh": 0, "children": [1]},
+    {"mesh": 0, "translation": [10, 0, 0], "rotation": [0, 0.7071068, 0, 0.7071068]}
+  ],
+  "meshes": [
+    {"primitives": [
+      {"attributes": {"POSITION": 0, "NORMAL": 1}, "indices": 2, "material": 0},
+      {"attributes": {"POSITION": 0}, "mode": 5, "material": 1}
+    ]},
+    {"primitives": [{"attributes": {"POSITION": 3}, "mode": 1}]}
+  ],
+  "accessors": [
+    {"count": 24, "min": [-1, -2, -0.5], "max": [1, 2, 0.5]},
+    {"count": 24},
+    {"count": 36},
+    {"count": 2, "min": [0, 0, 0], "max": [100, 0, 0]}
+  ],
+  "materials": [{"name": "Body"}, {"name": "Trim"}],
+  "images": [
+    {"uri": "textures/body%20albedo.png"},
+    {"uri": "data:image/png;base64,iVBORw0KGgo="},
+    {"bufferView": 3, "mimeType": "image/png"},
+    {"uri": "textures/body%20albedo.png"}
+  ]
+}`
+
+func TestParseGLTF(t *testing.T) {
+	meta, err := ParseGLTF([]byte(testGLTF))
+	require.NoError(t, err)
+
+	assert.Equal(t, int64(26), meta.VertexCount, "Shared POSITION accessors count once")
+	assert.Equal(t, int64(12+22), meta.TriangleCount, "Indexed triangles plus a strip, lines don't count")
+	assert.Equal(t, 2, meta.MeshCount)
+	assert.Equal(t, 2, meta.MaterialCount)
+	assert.Equal(t, 4, meta.TextureCount)
+	assert.Equal(t, []string{"textures/body albedo.png"}, meta.Textures)
+
+	// Pierwsza instancja: x -1..1, druga (obrót) x 9.5..10.5; z -1..1 po obrocie.
+	assert.InDelta(t, 11.5, meta.Size[0], 1e-6)
+	assert.InDelta(t, 4, meta.Size[1], 1e-6)
+	assert.InDelta(t, 2, meta.Size[2], 1e-6)
+}
+
+func TestParseGLTF_Bounds(t *testing.T) {
+	t.Run("Matrix and scale", func(t *testing.T) {
+		meta, err := ParseGLTF([]byte(`{
+			"nodes": [
+				{"children": [1], "scale": [2, 2, 2]},
+				{"mesh": 0, "matrix": [1,0,0,0, 0,1,0,0, 0,0,1,0, 5,0,0,1]}
+			],
+			"meshes": [{"primitives": [{"attributes": {"POSITION": 0}}]}],
+			"accessors": [{"count": 3, "min": [0, 0, 0], "max": [1, 1, 1]}]
+		}`))
+		require.NoError(t, err)
+		assert.Equal(t, [3]float64{2, 2, 2}, meta.Size)
+		assert.Equal(t, int64(1), meta.TriangleCount)
+	})
+
+	t.Run("No nodes", func(t *testing.T) {
+		meta, err := ParseGLTF([]byte(`{
+			"meshes": [{"primitives": [{"attributes": {"POSITION": 0}}]}],
+			"accessors": [{"count": 6, "min": [0, 0, 0], "max": [3, 2, 1]}]
+		}`))
+		require.NoError(t, err)
+		assert.Equal(t, [3]float64{3, 2, 1}, meta.Size)
+	})
+
+	t.Run("Cyclic nodes", func(t *testing.T) {
+		meta, err := ParseGLTF([]byte(`{
+			"scenes": [{"nodes": [0]}],
+			"nodes": [{"mesh": 0, "children": [1]}, {"children": [0]}],
+			"meshes": [{"primitives": [{"attributes": {"POSITION": 0}}]}],
+			"accessors": [{"count": 3, "min": [0, 0, 0], "max": [1, 1, 1]}]
+		}`))
+		require.NoError(t, err)
+		assert.Equal(t, [3]float64{1, 1, 1}, meta.Size)
+	})
+}
+
+func TestReadGLTFFile(t *testing.T) {
+	dir := t.TempDir()
+
+	t.Run("glTF", func(t *testing.T) {
+		path := filepath.Join(dir, "statue.gltf")
+		require.NoError(t, os.WriteFile(path, []byte(testGLTF), 0644))
+		meta, err := ReadModelMetadata(path)
+		require.NoError(t, err)
+		assert.Equal(t, int64(26), meta.VertexCount)
+	})
+
+	glb := func(version uint32, chunkType uint32, json string) []byte {
+		out := []byte(glbMagic)
+		out = binary.LittleEndian.AppendUint32(out, version)
+		out = binary.LittleEndian.AppendUint32(out, uint32(20+len(json)))
+		out = binary.LittleEndian.AppendUint32(out, uint32(len(json)))
+		out = binary.LittleEndian.AppendUint32(out, chunkType)
+		out = append(out, json...)
+		// Chunk BIN nie jest czytany
+		return append(out, 0, 0, 0, 0, 'B', 'I', 'N', 0)
+	}
+
+	t.Run("GLB", func(t *testing.T) {
+		path := filepath.Join(dir, "statue.glb")
+		require.NoError(t, os.WriteFile(path, glb(2, glbChunkJSON, testGLTF), 0644))
+		meta, err := ReadModelMetadata(path)
+		require.NoError(t, err)
+		assert.Equal(t, int64(34), meta.TriangleCount)
+		assert.Equal(t, 2, meta.MaterialCount)
+	})
+
+	for name, data := range map[string][]byte{
+		"GLB version 1":     glb(1, glbChunkJSON, testGLTF),
+		"BIN chunk first":   glb(2, 0x004E4942, testGLTF),
+		"Truncated":         glb(2, glbChunkJSON, testGLTF)[:40],
+		"Invalid JSON":      glb(2, glbChunkJSON, `{"meshes": [`),
+		"Not a binary glTF": []byte(testGLTF),
+	} {
+		t.Run(name, func(t *testing.T) {
+			path := filepath.Join(dir, "broken.glb")
+			require.NoError(t, os.WriteFile(path, data, 0644))
+			_, err := ReadGLTFFile(path)
+			assert.Error(t, err)
+		})
+	}
+}
@@ -0,0 +1,332 @@
+package scanner
+
+import (
+	"cmp"
+	"context"
+	"database/sql"
+	"eclat/internal/config"
+	"eclat/internal/database"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"slices"
+
+	"github.com/google/uuid"
+)
+
+// maxPreviewGroups bounds the groups returned by PreviewGrouping.
+const maxPreviewGroups = 200
+
+// GroupingPreview shows the groups that grouping rules produce for the assets of a scope,
+// compared with their current groups.
+type GroupingPreview struct {
+	AssetCount    int            `json:"assetCount"`
+	GroupsBefore  int            `json:"groupsBefore"`
+	GroupsAfter   int            `json:"groupsAfter"`
+	ChangedGroups int            `json:"changedGroups"`
+	Groups        []PreviewGroup `json:"groups"`    // Changed groups, largest first
+	Truncated     bool           `json:"truncated"` // More than maxPreviewGroups groups changed
+}
+
+// PreviewGroup is a group the rules would produce.
+type PreviewGroup struct {
+	BaseName string         `json:"baseName"`
+	GroupID  string         `json:"groupId"` // Current group it keeps; empty for a new group
+	Assets   []PreviewAsset `json:"assets"`
+}
+
+// PreviewAsset is a member of a PreviewGroup.
+type PreviewAsset struct {
+	ID             int64  `json:"id"`
+	FileName       string `json:"fileName"`
+	CurrentGroupID string `json:"currentGroupId"`
+}
+
+// plannedGroup is a group computed by planGrouping. An empty groupID means a new group.
+type plannedGroup struct {
+	baseName string
+	groupID  string
+	members  []database.ListAssetsForGroupingRow
+}
+
+// GetGroupingRules returns the global grouping rules and the scan folders overriding them.
+func (s *Scanner) GetGroupingRules() config.GroupingRuleSet {
+	return s.config.GetGroupingRules()
+}
+
+// SetGroupingRules validates and persists the grouping rules of a scan folder, or the global
+// rules for folder ID 0. They apply to files scanned from now on; RegroupAssets applies them
+// to assets already in the library.
+func (s *Scanner) SetGroupingRules(folderID int64, rules config.GroupingRules) error {
+	set := s.config.GetGroupingRules()
+	if folderID == 0 {
+		set.Global = rules
+	} else {
+		folder, err := s.db.GetScanFolderById(s.ctxOrBackground(), folderID)
+		if errors.Is(err, sql.ErrNoRows) || (err == nil && folder.IsDeleted) {
+			return fmt.Errorf("scan folder %d not found", folderID)
+		}
+		if err != nil {
+			return err
+		}
+		set.Folders[folderID] = rules
+	}
+	return s.applyGroupingRules(set)
+}
+
+// ResetFolderGroupingRules makes a scan folder use the global grouping rules again.
+func (s *Scanner) ResetFolderGroupingRules(folderID int64) error {
+	set := s.config.GetGroupingRules()
+	if _, ok := set.Folders[folderID]; !ok {
+		return nil
+	}
+	delete(set.Folders, folderID)
+	return s.applyGroupingRules(set)
+}
+
+func (s *Scanner) applyGroupingRules(set config.GroupingRuleSet) error {
+	if err := s.config.SetGroupingRules(set); err != nil {
+		return err
+	}
+	if err := s.persistGroupingRules(s.config.GetGroupingRules()); err != nil {
+		s.logger.Error("Failed to persist grouping rules", "error", err)
+		return err
+	}
+	return nil
+}
+
+func (s *Scanner) persistGroupingRules(set config.GroupingRuleSet) error {
+	// Safety check for tests where db might be nil
+	if s.db == nil {
+		return nil
+	}
+
+	jsonBytes, err := json.Marshal(set)
+	if err != nil {
+		return err
+	}
+
+	return s.db.SetSystemSetting(s.ctxOrBackground(), database.SetSystemSettingParams{
+		Key:   "grouping_rules",
+		Value: string(jsonBytes),
+	})
+}
+
+// PreviewGrouping shows how the assets of a scan folder would be grouped under the given rules,
+// without changing anything. Folder ID 0 previews global rules for the folders that do not
+// override them.
+func (s *Scanner) PreviewGrouping(folderID int64, rules config.GroupingRules) (GroupingPreview, error) {
+	compiled, err := rules.Normalize().Compile()
+	if err != nil {
+		return GroupingPreview{}, err
+	}
+	groups, assets, err := s.planGrouping(s.ctxOrBackground(), folderID, func(int64) *config.CompiledGroupingRules { return compiled })
+	if err != nil {
+		return GroupingPreview{}, err
+	}
+
+	before := make(map[string]struct{})
+	for _, a := range assets {
+		before[a.GroupID] = struct{}{}
+	}
+	preview := GroupingPreview{
+		AssetCount:   len(assets),
+		GroupsBefore: len(before),
+		GroupsAfter:  len(groups),
+		Groups:       []PreviewGroup{},
+	}
+	for _, g := range groups {
+		if !g.changed() {
+			continue
+		}
+		preview.ChangedGroups++
+		if len(preview.Groups) == maxPreviewGroups {
+			preview.Truncated = true
+			continue
+		}
+		pg := PreviewGroup{BaseName: g.baseName, GroupID: g.groupID, Assets: make([]PreviewAsset, len(g.members))}
+		for i, m := range g.members {
+			pg.Assets[i] = PreviewAsset{ID: m.ID, FileName: m.FileName, CurrentGroupID: m.GroupID}
+		}
+		preview.Groups = append(preview.Groups, pg)
+	}
+	return preview, nil
+}
+
+// RegroupAssets groups the assets of a scan folder (or, for folder ID 0, of all folders using
+// the global rules) by the current grouping rules. It returns the number of assets that moved
+// to another group.
+func (s *Scanner) RegroupAssets(folderID int64) (int, error) {
+	if s.isScanning.Load() {
+		return 0, errors.New("cannot regroup assets while a scan is running")
+	}
+	ctx := s.ctxOrBackground()
+	groups, _, err := s.planGrouping(ctx, folderID, s.config.GroupingRulesFor)
+	if err != nil {
+		return 0, err
+	}
+
+	tx, err := s.conn.BeginTx(ctx, nil)
+	if err != nil {
+		return 0, err
+	}
+	defer tx.Rollback()
+	qtx := database.New(tx)
+
+	moved := 0
+	for _, g := range groups {
+		groupID := g.groupID
+		if groupID == "" {
+			groupID = uuid.New().String()
+		}
+		for _, m := range g.members {
+			if m.GroupID == groupID {
+				continue
+			}
+			if err := qtx.UpdateAssetGroup(ctx, database.UpdateAssetGroupParams{GroupID: groupID, ID: m.ID}); err != nil {
+				return 0, fmt.Errorf("updating group of asset %d: %w", m.ID, err)
+			}
+			moved++
+		}
+	}
+	if err := tx.Commit(); err != nil {
+		return 0, err
+	}
+
+	s.logger.Info("Regrouped assets", "folder_id", folderID, "moved", moved)
+	if moved > 0 && s.notifier != nil {
+		s.notifier.EmitAssetsChanged(ctx)
+	}
+	return moved, nil
+}
+
+// planGrouping groups the assets in scope the way the scanner does: by identical content and
+// by base name (per folder, or across folders if the rules say so). Assets in locked groups
+// are left out. Each group keeps the current group ID most of its members share, unless a
+// larger group already took it.
+func (s *Scanner) planGrouping(ctx context.Context, folderID int64, rulesFor func(int64) *config.CompiledGroupingRules) ([]plannedGroup, []database.ListAssetsForGroupingRow, error) {
+	all, err := s.db.ListAssetsForGrouping(ctx)
+	if err != nil {
+		return nil, nil, err
+	}
+	lockedIDs, err := s.db.ListLockedGroupIDs(ctx)
+	if err != nil {
+		return nil, nil, err
+	}
+	locked := make(map[string]bool, len(lockedIDs))
+	for _, id := range lockedIDs {
+		locked[id] = true
+	}
+
+	var assets []database.ListAssetsForGroupingRow
+	for _, a := range all {
+		id := a.ScanFolderID.Int64
+		if locked[a.GroupID] {
+			continue
+		}
+		if (folderID != 0 && id == folderID) || (folderID == 0 && !s.config.HasFolderGroupingRules(id)) {
+			assets = append(assets, a)
+		}
+	}
+
+	parent := make([]int, len(assets))
+	for i := range parent {
+		parent[i] = i
+	}
+	var find func(int) int
+	find = func(i int) int {
+		if parent[i] != i {
+			parent[i] = find(parent[i])
+		}
+		return parent[i]
+	}
+	union := func(a, b int) {
+		if ra, rb := find(a), find(b); ra != rb {
+			parent[max(ra, rb)] = min(ra, rb)
+		}
+	}
+
+	bases := make([]string, len(assets))
+	byKey := make(map[string]int)
+	byHash := make(map[string]int)
+	for i, a := range assets {
+		if a.FileHash.Valid && a.FileHash.String != "" {
+			if j, ok := byHash[a.FileHash.String]; ok {
+				union(i, j)
+			} else {
+				byHash[a.FileHash.String] = i
+			}
+		}
+
+		rules := rulesFor(a.ScanFolderID.Int64)
+		base, _ := s.splitBaseName(rules, a.FileName)
+		if rules.Ignores(a.FileName) || len(base) < rules.MinBaseLength {
+			continue
+		}
+		bases[i] = base
+		key := fmt.Sprintf("%d:%s", a.ScanFolderID.Int64, base)
+		if rules.CrossFolders {
+			key = "*:" + base
+		}
+		if j, ok := byKey[key]; ok {
+			union(i, j)
+		} else {
+			byKey[key] = i
+		}
+	}
+
+	clusters := make(map[int]*plannedGroup)
+	var groups []*plannedGroup
+	for i, a := range assets {
+		root := find(i)
+		g, ok := clusters[root]
+		if !ok {
+			g = &plannedGroup{}
+			clusters[root] = g
+			groups = append(groups, g)
+		}
+		if g.baseName == "" {
+			g.baseName = bases[i]
+		}
+		g.members = append(g.members, a)
+	}
+	slices.SortStableFunc(groups, func(a, b *plannedGroup) int {
+		return cmp.Compare(len(b.members), len(a.members))
+	})
+
+	claimed := make(map[string]bool)
+	result := make([]plannedGroup, len(groups))
+	for i, g := range groups {
+		if g.baseName == "" {
+			g.baseName, _ = s.splitBaseName(rulesFor(g.members[0].ScanFolderID.Int64), g.members[0].FileName)
+		}
+		counts := make(map[string]int)
+		best := ""
+		for _, m := range g.members {
+			counts[m.GroupID]++
+			if !claimed[m.GroupID] && (best == "" || counts[m.GroupID] > counts[best]) {
+				best = m.GroupID
+			}
+		}
+		if best != "" {
+			claimed[best] = true
+		}
+		g.groupID = best
+		result[i] = *g
+	}
+	return result, assets, nil
+}
+
+// changed reports whether any member would move to another group.
+func (g plannedGroup) changed() bool {
+	return slices.ContainsFunc(g.members, func(m database.ListAssetsForGroupingRow) bool {
+		return m.GroupID != g.groupID
+	})
+}
+
+func (s *Scanner) ctxOrBackground() context.Context {
+	if s.ctx != nil {
+		return s.ctx
+	}
+	return context.Background()
+}
@@ -0,0 +1,193 @@
+package scanner
+
+import (
+	"context"
+	"database/sql"
+	"eclat/internal/config"
+	"eclat/internal/database"
+	"encoding/json"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// insertGroupedAsset wstawia asset z ustaloną grupą i hashem.
+func insertGroupedAsset(t *testing.T, q database.Querier, folderID int64, name, groupID, hash string) database.Asset {
+	asset, err := q.CreateAsset(context.Background(), database.CreateAssetParams{
+		ScanFolderID: sql.NullInt64{Int64: folderID, Valid: true},
+		GroupID:      groupID,
+		FileName:     name,
+		FilePath:     filepath.Join("/library", name),
+		FileHash:     sql.NullString{String: hash, Valid: hash != ""},
+		LastModified: time.Now(),
+		LastScanned:  time.Now(),
+	})
+	require.NoError(t, err)
+	return asset
+}
+
+func TestGroupingRules_IgnoreAndMinLength(t *testing.T) {
+	_, queries, scanner, _ := setupLogicTest(t)
+	ctx := context.Background()
+	insertGroupedAsset(t, queries, 1, "Forest.psd", "G-FOREST", "")
+	insertGroupedAsset(t, queries, 1, "Sky.psd", "G-SKY", "")
+
+	groupID, found := scanner.TryHeuristicMatch(ctx, 1, "Forest_v2.psd")
+	assert.True(t, found)
+	assert.Equal(t, "G-FOREST", groupID)
+	groupID, found = scanner.TryHeuristicMatch(ctx, 1, "Sky_v2.psd")
+	assert.True(t, found)
+	assert.Equal(t, "G-SKY", groupID)
+
+	rules := config.DefaultGroupingRules
+	rules.IgnorePatterns = []string{`^forest_`}
+	rules.MinBaseLength = 4
+	require.NoError(t, scanner.SetGroupingRules(0, rules))
+
+	_, found = scanner.TryHeuristicMatch(ctx, 1, "Forest_v2.psd")
+	assert.False(t, found, "Plik pasujący do wzorca ignorowania nie jest grupowany")
+	_, found = scanner.TryHeuristicMatch(ctx, 1, "Sky_v2.psd")
+	assert.False(t, found, "Nazwa bazowa 'sky' jest krótsza niż 4 znaki")
+}
+
+func TestGroupingRules_FolderOverrideAndCrossFolders(t *testing.T) {
+	_, queries, scanner, _ := setupLogicTest(t)
+	ctx := context.Background()
+	other, err := queries.CreateScanFolder(ctx, t.TempDir())
+	require.NoError(t, err)
+	insertGroupedAsset(t, queries, 1, "Tree.obj", "G-TREE", "")
+	insertGroupedAsset(t, queries, other.ID, "Rock.obj", "G-ROCK", "")
+
+	_, found := scanner.TryHeuristicMatch(ctx, 1, "Tree_lod1.obj")
+	assert.False(t, found, "Domyślne reguły nie znają sufiksu _lod")
+	_, found = scanner.TryHeuristicMatch(ctx, 1, "Rock_v2.obj")
+	assert.False(t, found, "Domyślnie grupujemy tylko w obrębie folderu")
+
+	require.NoError(t, scanner.SetGroupingRules(1, config.GroupingRules{
+		StripPatterns: []string{`_lod\d+$`, `_v\d+$`},
+		CrossFolders:  true,
+	}))
+
+	groupID, found := scanner.TryHeuristicMatch(ctx, 1, "Tree_lod1.obj")
+	assert.True(t, found)
+	assert.Equal(t, "G-TREE", groupID)
+	groupID, found = scanner.TryHeuristicMatch(ctx, 1, "Rock_v2.obj")
+	assert.True(t, found)
+	assert.Equal(t, "G-ROCK", groupID)
+	_, found = scanner.TryHeuristicMatch(ctx, other.ID, "Tree_lod1.obj")
+	assert.False(t, found, "Inne foldery nadal używają reguł globalnych")
+
+	require.NoError(t, scanner.ResetFolderGroupingRules(1))
+	_, found = scanner.TryHeuristicMatch(ctx, 1, "Tree_lod1.obj")
+	assert.False(t, found)
+}
+
+func TestGroupingRules_Persistence(t *testing.T) {
+	_, queries, scanner, _ := setupLogicTest(t)
+
+	err := scanner.SetGroupingRules(1, config.GroupingRules{StripPatterns: []string{"(unclosed"}})
+	assert.ErrorContains(t, err, "invalid strip pattern")
+	assert.False(t, scanner.config.HasFolderGroupingRules(1))
+
+	err = scanner.SetGroupingRules(999, config.GroupingRules{IgnorePatterns: []string{`\.tmp$`}})
+	assert.ErrorContains(t, err, "not found", "Reguły dla nieistniejącego folderu są odrzucane")
+	assert.False(t, scanner.config.HasFolderGroupingRules(999))
+
+	require.NoError(t, scanner.SetGroupingRules(1, config.GroupingRules{IgnorePatterns: []string{`\.tmp$`}}))
+	stored, err := queries.GetSystemSetting(context.Background(), "grouping_rules")
+	require.NoError(t, err)
+
+	var set config.GroupingRuleSet
+	require.NoError(t, json.Unmarshal([]byte(stored), &set))
+	assert.Equal(t, scanner.GetGroupingRules(), set)
+	assert.Equal(t, []string{`\.tmp$`}, set.Folders[1].IgnorePatterns)
+	assert.Equal(t, config.DefaultGroupingMinBaseLength, set.Folders[1].MinBaseLength)
+}
+
+func TestGroupingRules_PreviewAndRegroup(t *testing.T) {
+	_, queries, scanner, _ := setupLogicTest(t)
+	ctx := context.Background()
+	forest := insertGroupedAsset(t, queries, 1, "Forest.psd", "G1", "")
+	insertGroupedAsset(t, queries, 1, "Forest_v2.psd", "G2", "")
+	insertGroupedAsset(t, queries, 1, "Forest_final.psd", "G2", "")
+	insertGroupedAsset(t, queries, 1, "Desert.psd", "G3", "hash-1")
+	sand := insertGroupedAsset(t, queries, 1, "Sand.png", "G4", "hash-1")
+
+	_, err := scanner.PreviewGrouping(1, config.GroupingRules{StripPatterns: []string{"[a-"}})
+	assert.Error(t, err)
+
+	preview, err := scanner.PreviewGrouping(1, config.DefaultGroupingRules)
+	require.NoError(t, err)
+	assert.Equal(t, 5, preview.AssetCount)
+	assert.Equal(t, 4, preview.GroupsBefore)
+	assert.Equal(t, 2, preview.GroupsAfter)
+	assert.Equal(t, 2, preview.ChangedGroups)
+	assert.False(t, preview.Truncated)
+	require.Len(t, preview.Groups, 2)
+	assert.Equal(t, "forest", preview.Groups[0].BaseName)
+	assert.Equal(t, "G2", preview.Groups[0].GroupID, "Grupa zachowuje ID większości")
+	assert.Len(t, preview.Groups[0].Assets, 3)
+	assert.Equal(t, "G3", preview.Groups[1].GroupID, "Duplikaty trafiają do jednej grupy")
+
+	// Bez sufiksów wersji Forest_v2 i Forest_final się rozdzielają
+	preview, err = scanner.PreviewGrouping(1, config.GroupingRules{CrossFolders: true})
+	require.NoError(t, err)
+	assert.Equal(t, 4, preview.GroupsAfter)
+	assert.Equal(t, 2, preview.ChangedGroups)
+
+	// Podgląd niczego nie zmienia
+	unchanged, err := queries.GetAssetById(ctx, forest.ID)
+	require.NoError(t, err)
+	assert.Equal(t, "G1", unchanged.GroupID)
+
+	moved, err := scanner.RegroupAssets(1)
+	require.NoError(t, err)
+	assert.Equal(t, 2, moved)
+
+	regrouped, err := queries.GetAssetById(ctx, forest.ID)
+	require.NoError(t, err)
+	assert.Equal(t, "G2", regrouped.GroupID)
+	regrouped, err = queries.GetAssetById(ctx, sand.ID)
+	require.NoError(t, err)
+	assert.Equal(t, "G3", regrouped.GroupID)
+
+	moved, err = scanner.RegroupAssets(1)
+	require.NoError(t, err)
+	assert.Zero(t, moved, "Ponowne grupowanie niczego nie zmienia")
+}
+
+func TestGroupingRules_LockedGroups(t *testing.T) {
+	_, queries, scanner, root := setupLogicTest(t)
+	ctx := context.Background()
+	insertGroupedAsset(t, queries, 1, "Forest.psd", "G-LOCKED", "")
+	insertGroupedAsset(t, queries, 1, "Forest_v2.psd", "G-OTHER", "")
+	require.NoError(t, queries.UpsertAssetGroup(ctx, database.UpsertAssetGroupParams{GroupID: "G-LOCKED", IsLocked: true}))
+
+	// Heurystyka pomija zablokowaną grupę i wybiera kolejnego kandydata
+	groupID, found := scanner.TryHeuristicMatch(ctx, 1, "Forest_v3.psd")
+	assert.True(t, found)
+	assert.Equal(t, "G-OTHER", groupID)
+
+	// Przegrupowanie nie rusza zablokowanej grupy
+	moved, err := scanner.RegroupAssets(1)
+	require.NoError(t, err)
+	assert.Zero(t, moved)
+
+	// Duplikat pliku z zablokowanej grupy dostaje własną grupę
+	original := filepath.Join(root, "rock.txt")
+	createContentFile(t, original, "rock content")
+	require.NoError(t, scanner.ScanFile(ctx, original))
+	rock, err := queries.GetAssetByPath(ctx, original)
+	require.NoError(t, err)
+	require.NoError(t, queries.UpsertAssetGroup(ctx, database.UpsertAssetGroupParams{GroupID: rock.GroupID, IsLocked: true}))
+
+	dup := filepath.Join(root, "stone.txt")
+	createContentFile(t, dup, "rock content")
+	require.NoError(t, scanner.ScanFile(ctx, dup))
+	stone, err := queries.GetAssetByPath(ctx, dup)
+	require.NoError(t, err)
+	assert.NotEqual(t, rock.GroupID, stone.GroupID)
+}
@@ -0,0 +1,344 @@
+package scanner
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"image"
+	"io"
+	"math"
+	"os"
+	"strconv"
+	"strings"
+)
+
+const (
+	// hdrPreviewMaxSize is the longer edge of the decoded HDR preview. Like PSD composites,
+	// HDR images are subsampled while they are read.
+	hdrPreviewMaxSize = 1024
+	// hdrMaxDimension guards against corrupt headers.
+	hdrMaxDimension = 1 << 20
+	// hdrKeyValue is the "middle gray" the average scene luminance is mapped to.
+	hdrKeyValue = 0.18
+)
+
+// HDRInfo describes a high dynamic range image (.hdr, .exr).
+type HDRInfo struct {
+	Width       int
+	Height      int
+	BitDepth    int      // 16 for half float, 32 for float (and RGBE) data
+	Channels    []string // Channel names as stored in the file
+	Compression string
+	HasAlpha    bool
+	Tiled       bool
+}
+
+// Properties returns the format details stored in asset_properties.
+func (i HDRInfo) Properties() map[string]string {
+	return map[string]string{
+		PropertyChannels:     strings.Join(i.Channels, ","),
+		PropertyChannelCount: strconv.Itoa(len(i.Channels)),
+		PropertyCompression:  i.Compression,
+	}
+}
+
+// hdrPreview is a subsampled, linear light RGBA buffer filled by the HDR decoders.
+// Source pixels that are not on the sampling grid are ignored by set.
+type hdrPreview struct {
+	width, height int
+	step          int
+	pix           []float32
+}
+
+func newHDRPreview(srcWidth, srcHeight int) *hdrPreview {
+	step := 1
+	if longest := max(srcWidth, srcHeight); longest > hdrPreviewMaxSize {
+		step = (longest + hdrPreviewMaxSize - 1) / hdrPreviewMaxSize
+	}
+	p := &hdrPreview{
+		width:  (srcWidth + step - 1) / step,
+		height: (srcHeight + step - 1) / step,
+		step:   step,
+	}
+	p.pix = make([]float32, p.width*p.height*4)
+	for i := 3; i < len(p.pix); i += 4 {
+		p.pix[i] = 1
+	}
+	return p
+}
+
+// wantsRow reports whether source row y ends up in the preview.
+func (p *hdrPreview) wantsRow(y int) bool {
+	return y%p.step == 0
+}
+
+// set stores component c (0-3: R, G, B, A) of source pixel (x, y).
+func (p *hdrPreview) set(x, y, c int, v float32) {
+	if x%p.step != 0 || y%p.step != 0 {
+		return
+	}
+	p.pix[((y/p.step)*p.width+x/p.step)*4+c] = v
+}
+
+// toneMap maps the buffer to displayable sRGB with the global Reinhard operator. The exposure is
+// picked from the log-average luminance, so both dim renders and bright skies end up readable.
+func (p *hdrPreview) toneMap() *image.NRGBA {
+	var logSum float64
+	var count int
+	for i := 0; i < len(p.pix); i += 4 {
+		l := hdrLuminance(sanitizeHDR(p.pix[i]), sanitizeHDR(p.pix[i+1]), sanitizeHDR(p.pix[i+2]))
+		if l > 0 {
+			logSum += math.Log(l)
+			count++
+		}
+	}
+	scale := 1.0
+	if count > 0 {
+		scale = hdrKeyValue / math.Exp(logSum/float64(count))
+	}
+
+	img := image.NewNRGBA(image.Rect(0, 0, p.width, p.height))
+	for i := 0; i < len(p.pix); i += 4 {
+		r, g, b := sanitizeHDR(p.pix[i]), sanitizeHDR(p.pix[i+1]), sanitizeHDR(p.pix[i+2])
+		if l := hdrLuminance(r, g, b); l > 0 {
+			lm := l * scale
+			k := lm / (1 + lm) / l
+			r, g, b = r*k, g*k, b*k
+		} else {
+			r, g, b = 0, 0, 0
+		}
+		img.Pix[i] = linearToSRGB8(r)
+		img.Pix[i+1] = linearToSRGB8(g)
+		img.Pix[i+2] = linearToSRGB8(b)
+		img.Pix[i+3] = uint8(math.Max(0, math.Min(1, sanitizeHDR(p.pix[i+3])))*255 + 0.5)
+	}
+	return img
+}
+
+// sanitizeHDR drops NaNs and clamps infinities and negative (out of gamut) values.
+func sanitizeHDR(v float32) float64 {
+	f := float64(v)
+	switch {
+	case math.IsNaN(f) || f < 0:
+		return 0
+	case math.IsInf(f, 1):
+		return math.MaxFloat32
+	}
+	return f
+}
+
+func hdrLuminance(r, g, b float64) float64 {
+	return 0.2126*r + 0.7152*g + 0.0722*b
+}
+
+func linearToSRGB8(v float64) uint8 {
+	v = math.Max(0, math.Min(1, v))
+	if v <= 0.0031308 {
+		v *= 12.92
+	} else {
+		v = 1.055*math.Pow(v, 1/2.4) - 0.055
+	}
+	return uint8(v*255 + 0.5)
+}
+
+// DecodeRadianceFile reads a tone mapped preview and the info of a Radiance .hdr file.
+func DecodeRadianceFile(path string) (image.Image, HDRInfo, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, HDRInfo{}, err
+	}
+	defer f.Close()
+	return DecodeRadiance(f)
+}
+
+// DecodeRadiance decodes a Radiance RGBE (or XYZE) picture, flat or run-length encoded.
+func DecodeRadiance(r io.Reader) (image.Image, HDRInfo, error) {
+	br := bufio.NewReaderSize(r, 64<<10)
+
+	magic, err := br.ReadString('\n')
+	if err != nil || !strings.HasPrefix(magic, "#?") {
+		return nil, HDRInfo{}, errors.New("not a Radiance HDR file")
+	}
+	xyz := false
+	for {
+		line, err := br.ReadString('\n')
+		if err != nil {
+			return nil, HDRInfo{}, fmt.Errorf("failed to read header: %w", err)
+		}
+		line = strings.TrimSpace(line)
+		if line == "" {
+			break
+		}
+		if format, ok := strings.CutPrefix(line, "FORMAT="); ok {
+			switch format {
+			case "32-bit_rle_rgbe":
+			case "32-bit_rle_xyze":
+				xyz = true
+			default:
+				return nil, HDRInfo{}, fmt.Errorf("unsupported format %q", format)
+			}
+		}
+	}
+
+	resolution, err := br.ReadString('\n')
+	if err != nil {
+		return nil, HDRInfo{}, fmt.Errorf("failed to read resolution: %w", err)
+	}
+	fields := strings.Fields(resolution)
+	if len(fields) != 4 || (fields[0] != "-Y" && fields[0] != "+Y") || (fields[2] != "+X" && fields[2] != "-X") {
+		return nil, HDRInfo{}, fmt.Errorf("unsupported resolution string %q", strings.TrimSpace(resolution))
+	}
+	height, errH := strconv.Atoi(fields[1])
+	width, errW := strconv.Atoi(fields[3])
+	if errH != nil || errW != nil || width <= 0 || height <= 0 || width > hdrMaxDimension || height > hdrMaxDimension {
+		return nil, HDRInfo{}, fmt.Errorf("invalid dimensions %q", strings.TrimSpace(resolution))
+	}
+	flipY, flipX := fields[0] == "+Y", fields[2] == "-X"
+
+	info := HDRInfo{
+		Width:       width,
+		Height:      height,
+		BitDepth:    32,
+		Channels:    []string{"R", "G", "B"},
+		Compression: "none",
+	}
+	if xyz {
+		info.Channels = []string{"X", "Y", "Z"}
+	}
+
+	preview := newHDRPreview(width, height)
+	scanline := make([]byte, width*4)
+	for row := 0; row < height; row++ {
+		rle, err := readRGBEScanline(br, scanline)
+		if err != nil {
+			return nil, info, fmt.Errorf("scanline %d: %w", row, err)
+		}
+		if rle {
+			info.Compression = "RLE"
+		}
+		y := row
+		if flipY {
+			y = height - 1 - row
+		}
+		if !preview.wantsRow(y) {
+			continue
+		}
+		for i := 0; i < width; i++ {
+			x := i
+			if flipX {
+				x = width - 1 - i
+			}
+			c0, c1, c2 := rgbeToFloat(scanline[i*4:])
+			if xyz {
+				c0, c1, c2 = xyzToLinearSRGB(c0, c1, c2)
+			}
+			preview.set(x, y, 0, c0)
+			preview.set(x, y, 1, c1)
+			preview.set(x, y, 2, c2)
+		}
+	}
+	return preview.toneMap(), info, nil
+}
+
+// readRGBEScanline reads one scanline into dst (4 bytes per pixel) and reports whether it used
+// the adaptive run-length encoding, which stores the four components separately.
+func readRGBEScanline(br *bufio.Reader, dst []byte) (bool, error) {
+	width := len(dst) / 4
+	var head [4]byte
+	if _, err := io.ReadFull(br, head[:]); err != nil {
+		return false, err
+	}
+	if width < 8 || width > 0x7fff || head[0] != 2 || head[1] != 2 || head[2]&0x80 != 0 {
+		return false, readFlatRGBE(br, head, dst)
+	}
+	if int(head[2])<<8|int(head[3]) != width {
+		return true, errors.New("scanline width mismatch")
+	}
+
+	for c := 0; c < 4; c++ {
+		for x := 0; x < width; {
+			count, err := br.ReadByte()
+			if err != nil {
+				return true, err
+			}
+			if count > 128 {
+				n := int(count) - 128
+				v, err := br.ReadByte()
+				if err != nil {
+					return true, err
+				}
+				if x+n > width {
+					return true, errors.New("run exceeds scanline")
+				}
+				for ; n > 0; n-- {
+					dst[x*4+c] = v
+					x++
+				}
+				continue
+			}
+			n := int(count)
+			if n == 0 || x+n > width {
+				return true, errors.New("invalid literal run")
+			}
+			for ; n > 0; n-- {
+				v, err := br.ReadByte()
+				if err != nil {
+					return true, err
+				}
+				dst[x*4+c] = v
+				x++
+			}
+		}
+	}
+	return true, nil
+}
+
+// readFlatRGBE reads uncompressed pixels, expanding the old-style (1, 1, 1, n) repeat markers.
+func readFlatRGBE(br *bufio.Reader, first [4]byte, dst []byte) error {
+	width := len(dst) / 4
+	pixel := first
+	pending := true // the first pixel was already read while probing for RLE
+	shift := 0
+	for x := 0; x < width; {
+		if !pending {
+			if _, err := io.ReadFull(br, pixel[:]); err != nil {
+				return err
+			}
+		}
+		pending = false
+		if pixel[0] == 1 && pixel[1] == 1 && pixel[2] == 1 {
+			if x == 0 {
+				return errors.New("repeat marker without a previous pixel")
+			}
+			n := int(pixel[3]) << shift
+			if x+n > width {
+				return errors.New("repeat exceeds scanline")
+			}
+			for ; n > 0; n-- {
+				copy(dst[x*4:x*4+4], dst[(x-1)*4:x*4])
+				x++
+			}
+			shift += 8
+			continue
+		}
+		copy(dst[x*4:x*4+4], pixel[:])
+		shift = 0
+		x++
+	}
+	return nil
+}
+
+// rgbeToFloat converts a shared-exponent pixel to linear floats.
+func rgbeToFloat(p []byte) (float32, float32, float32) {
+	if p[3] == 0 {
+		return 0, 0, 0
+	}
+	f := math.Ldexp(1, int(p[3])-(128+8))
+	return float32((float64(p[0]) + 0.5) * f), float32((float64(p[1]) + 0.5) * f), float32((float64(p[2]) + 0.5) * f)
+}
+
+func xyzToLinearSRGB(x, y, z float32) (float32, float32, float32) {
+	return 3.2406*x - 1.5372*y - 0.4986*z,
+		-0.9689*x + 1.8758*y + 0.0415*z,
+		0.0557*x - 0.2040*y + 1.0570*z
+}
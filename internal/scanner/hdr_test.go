@@ -0,0 +1,168 @@
+package scanner
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"math"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// testRadiance buduje plik .hdr; rle=false zapisuje piksele bez kompresji.
+func testRadiance(w, h int, rle bool, orientation string, pixel func(x, y int) [3]float64) []byte {
+	var buf bytes.Buffer
+	buf.WriteString("#?RADIANCE\n# test\nFORMAT=32-bit_rle_rgbe\nEXPOSURE=1.0\n\n")
+	fmt.Fprintf(&buf, "%s %d +X %d\n", orientation, h, w)
+
+	for row := 0; row < h; row++ {
+		y := row
+		if orientation == "+Y" {
+			y = h - 1 - row // pierwszy zapisany wiersz to dół obrazu
+		}
+		scanline := make([]byte, w*4)
+		for x := 0; x < w; x++ {
+			copy(scanline[x*4:], floatToRGBE(pixel(x, y)))
+		}
+		if !rle {
+			buf.Write(scanline)
+			continue
+		}
+		buf.Write([]byte{2, 2, byte(w >> 8), byte(w)})
+		for c := 0; c < 4; c++ {
+			for x := 0; x < w; {
+				run := 1
+				for x+run < w && run < 127 && scanline[(x+run)*4+c] == scanline[x*4+c] {
+					run++
+				}
+				if run >= 3 {
+					buf.Write([]byte{byte(128 + run), scanline[x*4+c]})
+					x += run
+					continue
+				}
+				n := min(w-x, 2)
+				buf.WriteByte(byte(n))
+				for i := 0; i < n; i++ {
+					buf.WriteByte(scanline[(x+i)*4+c])
+				}
+				x += n
+			}
+		}
+	}
+	return buf.Bytes()
+}
+
+func floatToRGBE(c [3]float64) []byte {
+	v := max(c[0], c[1], c[2])
+	if v < 1e-32 {
+		return []byte{0, 0, 0, 0}
+	}
+	frac, exp := math.Frexp(v)
+	scale := frac * 256 / v
+	return []byte{byte(c[0] * scale), byte(c[1] * scale), byte(c[2] * scale), byte(exp + 128)}
+}
+
+func redBluePixel(w int) func(x, y int) [3]float64 {
+	return func(x, y int) [3]float64 {
+		if x < w/2 {
+			return [3]float64{12, 0.2, 0.2}
+		}
+		return [3]float64{0.02, 0.02, 0.3}
+	}
+}
+
+func TestDecodeRadiance(t *testing.T) {
+	t.Run("RLE", func(t *testing.T) {
+		img, info, err := DecodeRadiance(bytes.NewReader(testRadiance(32, 10, true, "-Y", redBluePixel(32))))
+		require.NoError(t, err)
+		assert.Equal(t, HDRInfo{Width: 32, Height: 10, BitDepth: 32, Channels: []string{"R", "G", "B"}, Compression: "RLE"}, info)
+		r, _, b, _ := img.At(2, 5).RGBA()
+		assert.Greater(t, r, b)
+		r, _, b, _ = img.At(30, 5).RGBA()
+		assert.Greater(t, b, r)
+	})
+
+	t.Run("Flat scanlines", func(t *testing.T) {
+		// Szerokość < 8 nie pozwala na RLE.
+		img, info, err := DecodeRadiance(bytes.NewReader(testRadiance(6, 4, false, "-Y", redBluePixel(6))))
+		require.NoError(t, err)
+		assert.Equal(t, "none", info.Compression)
+		r, _, b, _ := img.At(0, 0).RGBA()
+		assert.Greater(t, r, b)
+	})
+
+	t.Run("Old style repeat markers", func(t *testing.T) {
+		data := []byte("#?RGBE\n\n-Y 1 +X 6\n")
+		data = append(data, 200, 20, 20, 131, 1, 1, 1, 5)
+		img, _, err := DecodeRadiance(bytes.NewReader(data))
+		require.NoError(t, err)
+		r0, _, _, _ := img.At(0, 0).RGBA()
+		r5, _, b5, _ := img.At(5, 0).RGBA()
+		assert.Equal(t, r0, r5)
+		assert.Greater(t, r5, b5)
+	})
+
+	t.Run("Bottom-up orientation", func(t *testing.T) {
+		pixel := func(x, y int) [3]float64 {
+			if y == 0 {
+				return [3]float64{5, 0.1, 0.1}
+			}
+			return [3]float64{0.1, 0.1, 5}
+		}
+		img, _, err := DecodeRadiance(bytes.NewReader(testRadiance(16, 4, true, "+Y", pixel)))
+		require.NoError(t, err)
+		r, _, b, _ := img.At(8, 0).RGBA()
+		assert.Greater(t, r, b, "Górny wiersz musi pozostać na górze")
+	})
+}
+
+func TestDecodeRadiance_AutoExposure(t *testing.T) {
+	// Ten sam obraz 100x jaśniejszy daje praktycznie ten sam podgląd.
+	dim := testRadiance(16, 4, true, "-Y", func(x, y int) [3]float64 { return [3]float64{0.01 * float64(x+1), 0.01, 0.01} })
+	bright := testRadiance(16, 4, true, "-Y", func(x, y int) [3]float64 { return [3]float64{float64(x + 1), 1, 1} })
+	imgDim, _, err := DecodeRadiance(bytes.NewReader(dim))
+	require.NoError(t, err)
+	imgBright, _, err := DecodeRadiance(bytes.NewReader(bright))
+	require.NoError(t, err)
+	for _, x := range []int{0, 7, 15} {
+		r1, _, _, _ := imgDim.At(x, 1).RGBA()
+		r2, _, _, _ := imgBright.At(x, 1).RGBA()
+		assert.InDelta(t, r1, r2, 0x300)
+	}
+}
+
+func TestDecodeRadiance_InvalidInput(t *testing.T) {
+	for name, data := range map[string]string{
+		"Not radiance":       "P6 1 1 255\n",
+		"Unsupported format": "#?RADIANCE\nFORMAT=rgb\n\n-Y 1 +X 1\n",
+		"Rotated image":      "#?RADIANCE\n\n+X 2 -Y 2\n",
+		"Truncated":          "#?RADIANCE\n\n-Y 2 +X 2\n\x80\x80\x80",
+	} {
+		t.Run(name, func(t *testing.T) {
+			_, _, err := DecodeRadiance(bytes.NewReader([]byte(data)))
+			assert.Error(t, err)
+		})
+	}
+}
+
+func TestDiskThumbnailGenerator_RadianceHDR(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "sky.hdr")
+	assert.NoError(t, os.WriteFile(path, testRadiance(64, 32, true, "-Y", redBluePixel(64)), 0644))
+
+	generator := NewDiskThumbnailGenerator(dir, slog.New(slog.NewTextHandler(io.Discard, nil)))
+	res, err := generator.Generate(context.Background(), path)
+	assert.NoError(t, err)
+	assert.False(t, res.IsPlaceholder)
+	assert.Equal(t, 64, res.Metadata.Width)
+	assert.Equal(t, 32, res.Metadata.Height)
+	assert.Equal(t, 32, res.Metadata.BitDepth)
+	assert.False(t, res.Metadata.HasAlphaChannel)
+	assert.Equal(t, "R,G,B", res.Metadata.Properties[PropertyChannels])
+}
@@ -0,0 +1,148 @@
+package scanner
+
+import (
+	"bytes"
+	"encoding/binary"
+	"io"
+	"os"
+)
+
+// imageHeader is what the PNG and TIFF headers say about an image. The decoded image.Image can't
+// tell 1-bit from 8-bit PNGs, and x/image/tiff rejects floating point TIFFs altogether.
+type imageHeader struct {
+	Width    int
+	Height   int
+	BitDepth int
+	HasAlpha bool
+}
+
+var pngSignature = []byte("\x89PNG\r\n\x1a\n")
+
+const (
+	tiffTagImageWidth    = 256
+	tiffTagImageLength   = 257
+	tiffTagBitsPerSample = 258
+	tiffTagExtraSamples  = 338
+)
+
+// readImageHeader reads the header of a PNG or TIFF file. ok is false for other formats
+// or unreadable headers.
+func readImageHeader(path string) (imageHeader, bool) {
+	f, err := os.Open(path)
+	if err != nil {
+		return imageHeader{}, false
+	}
+	defer f.Close()
+
+	var magic [8]byte
+	if _, err := f.ReadAt(magic[:], 0); err != nil {
+		return imageHeader{}, false
+	}
+	switch {
+	case bytes.Equal(magic[:], pngSignature):
+		return readPNGHeader(f)
+	case string(magic[:4]) == "II*\x00" || string(magic[:4]) == "MM\x00*":
+		return readTIFFHeader(f, magic[:])
+	}
+	return imageHeader{}, false
+}
+
+// readPNGHeader reads IHDR and walks the chunks up to the image data looking for tRNS.
+func readPNGHeader(r io.ReaderAt) (imageHeader, bool) {
+	var hdr imageHeader
+	off := int64(len(pngSignature))
+	var chunk [8]byte
+	for i := 0; ; i++ {
+		if _, err := r.ReadAt(chunk[:], off); err != nil {
+			return hdr, i > 0
+		}
+		length, kind := int64(binary.BigEndian.Uint32(chunk[:4])), string(chunk[4:])
+		switch {
+		case i == 0 && kind != "IHDR":
+			return hdr, false
+		case kind == "IHDR":
+			var ihdr [13]byte
+			if _, err := r.ReadAt(ihdr[:], off+8); err != nil {
+				return hdr, false
+			}
+			hdr.Width = int(binary.BigEndian.Uint32(ihdr[0:]))
+			hdr.Height = int(binary.BigEndian.Uint32(ihdr[4:]))
+			hdr.BitDepth = int(ihdr[8])
+			switch colorType := ihdr[9]; colorType {
+			case 3: // palette entries are always 8-bit
+				hdr.BitDepth = 8
+			case 4, 6:
+				hdr.HasAlpha = true
+			}
+		case kind == "tRNS":
+			hdr.HasAlpha = true
+		case kind == "IDAT" || kind == "IEND":
+			return hdr, true
+		}
+		off += 8 + length + 4 // length, type, data, CRC
+	}
+}
+
+// readTIFFHeader reads the first IFD of a classic (non-Big) TIFF.
+func readTIFFHeader(r io.ReaderAt, magic []byte) (imageHeader, bool) {
+	var order binary.ByteOrder = binary.LittleEndian
+	if magic[0] == 'M' {
+		order = binary.BigEndian
+	}
+	ifd := int64(order.Uint32(magic[4:]))
+	var count [2]byte
+	if _, err := r.ReadAt(count[:], ifd); err != nil {
+		return imageHeader{}, false
+	}
+	entries := make([]byte, 12*int(order.Uint16(count[:])))
+	if _, err := r.ReadAt(entries, ifd+2); err != nil {
+		return imageHeader{}, false
+	}
+
+	// value returns the first value of an entry; SHORT values are left-justified in the field.
+	value := func(e []byte) int {
+		if order.Uint16(e[2:]) == 3 {
+			return int(order.Uint16(e[8:]))
+		}
+		return int(order.Uint32(e[8:]))
+	}
+	// values returns all SHORT values of an entry, reading them from the offset when they don't fit.
+	values := func(e []byte) []int {
+		n := int(order.Uint32(e[4:]))
+		if order.Uint16(e[2:]) != 3 || n > 64 {
+			return []int{value(e)}
+		}
+		data := e[8:12]
+		if n > 2 {
+			data = make([]byte, 2*n)
+			if _, err := r.ReadAt(data, int64(order.Uint32(e[8:]))); err != nil {
+				return nil
+			}
+		}
+		out := make([]int, n)
+		for i := range out {
+			out[i] = int(order.Uint16(data[2*i:]))
+		}
+		return out
+	}
+
+	hdr := imageHeader{BitDepth: 1} // BitsPerSample defaults to 1
+	for i := 0; i < len(entries); i += 12 {
+		e := entries[i : i+12]
+		switch order.Uint16(e) {
+		case tiffTagImageWidth:
+			hdr.Width = value(e)
+		case tiffTagImageLength:
+			hdr.Height = value(e)
+		case tiffTagBitsPerSample:
+			if v := values(e); len(v) > 0 {
+				hdr.BitDepth = v[0]
+			}
+		case tiffTagExtraSamples:
+			for _, v := range values(e) {
+				hdr.HasAlpha = hdr.HasAlpha || v == 1 || v == 2 // associated or unassociated alpha
+			}
+		}
+	}
+	return hdr, hdr.Width > 0 && hdr.Height > 0
+}
@@ -0,0 +1,132 @@
+package scanner
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"image"
+	"image/color"
+	"image/png"
+	"io"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/image/tiff"
+)
+
+// testFloatTIFF zapisuje minimalny, nieskompresowany TIFF z 32-bitowymi próbkami float (RGB).
+func testFloatTIFF(w, h int) []byte {
+	var buf bytes.Buffer
+	buf.WriteString("II*\x00")
+	_ = binary.Write(&buf, binary.LittleEndian, uint32(8))
+
+	pixelsOffset := uint32(8 + 2 + 9*12 + 4 + 6)
+	bitsOffset := pixelsOffset - 6
+	entries := [][3]uint32{ // tag, typ, wartość (count zawsze 1 poza BitsPerSample)
+		{256, 3, uint32(w)},
+		{257, 3, uint32(h)},
+		{258, 3, bitsOffset},
+		{259, 3, 1},
+		{262, 3, 2},
+		{273, 4, pixelsOffset},
+		{277, 3, 3},
+		{279, 4, uint32(w * h * 12)},
+		{339, 3, 3},
+	}
+	_ = binary.Write(&buf, binary.LittleEndian, uint16(len(entries)))
+	for _, e := range entries {
+		count := uint32(1)
+		if e[0] == 258 {
+			count = 3
+		}
+		_ = binary.Write(&buf, binary.LittleEndian, uint16(e[0]))
+		_ = binary.Write(&buf, binary.LittleEndian, uint16(e[1]))
+		_ = binary.Write(&buf, binary.LittleEndian, count)
+		_ = binary.Write(&buf, binary.LittleEndian, e[2])
+	}
+	_ = binary.Write(&buf, binary.LittleEndian, uint32(0))
+	_ = binary.Write(&buf, binary.LittleEndian, []uint16{32, 32, 32})
+	buf.Write(make([]byte, w*h*12))
+	return buf.Bytes()
+}
+
+func TestReadImageHeader(t *testing.T) {
+	dir := t.TempDir()
+	write := func(name string, data []byte) string {
+		path := filepath.Join(dir, name)
+		require.NoError(t, os.WriteFile(path, data, 0644))
+		return path
+	}
+	encodePNG := func(img image.Image) []byte {
+		var buf bytes.Buffer
+		require.NoError(t, png.Encode(&buf, img))
+		return buf.Bytes()
+	}
+
+	t.Run("16-bit PNG", func(t *testing.T) {
+		hdr, ok := readImageHeader(write("gray16.png", encodePNG(image.NewGray16(image.Rect(0, 0, 5, 3)))))
+		require.True(t, ok)
+		assert.Equal(t, imageHeader{Width: 5, Height: 3, BitDepth: 16}, hdr)
+	})
+
+	t.Run("Paletted PNG with transparency", func(t *testing.T) {
+		img := image.NewPaletted(image.Rect(0, 0, 2, 2), color.Palette{color.NRGBA{A: 0}, color.NRGBA{R: 255, A: 255}})
+		hdr, ok := readImageHeader(write("paletted.png", encodePNG(img)))
+		require.True(t, ok)
+		assert.Equal(t, 8, hdr.BitDepth)
+		assert.True(t, hdr.HasAlpha, "tRNS oznacza przezroczystość")
+	})
+
+	t.Run("16-bit TIFF with alpha", func(t *testing.T) {
+		var buf bytes.Buffer
+		require.NoError(t, tiff.Encode(&buf, image.NewNRGBA64(image.Rect(0, 0, 4, 6)), nil))
+		hdr, ok := readImageHeader(write("rgba16.tif", buf.Bytes()))
+		require.True(t, ok)
+		assert.Equal(t, imageHeader{Width: 4, Height: 6, BitDepth: 16, HasAlpha: true}, hdr)
+	})
+
+	t.Run("Float TIFF", func(t *testing.T) {
+		hdr, ok := readImageHeader(write("float.tif", testFloatTIFF(3, 2)))
+		require.True(t, ok)
+		assert.Equal(t, imageHeader{Width: 3, Height: 2, BitDepth: 32}, hdr)
+	})
+
+	t.Run("Other formats", func(t *testing.T) {
+		_, ok := readImageHeader(write("image.jpg", []byte("\xff\xd8\xff\xe0 not png")))
+		assert.False(t, ok)
+	})
+}
+
+func TestDiskThumbnailGenerator_TrueBitDepth(t *testing.T) {
+	dir := t.TempDir()
+	generator := NewDiskThumbnailGenerator(dir, slog.New(slog.NewTextHandler(io.Discard, nil)))
+
+	t.Run("16-bit PNG", func(t *testing.T) {
+		img := image.NewRGBA64(image.Rect(0, 0, 8, 8))
+		var buf bytes.Buffer
+		require.NoError(t, png.Encode(&buf, img))
+		path := filepath.Join(dir, "height.png")
+		require.NoError(t, os.WriteFile(path, buf.Bytes(), 0644))
+
+		res, err := generator.Generate(context.Background(), path)
+		require.NoError(t, err)
+		assert.False(t, res.IsPlaceholder)
+		assert.Equal(t, 16, res.Metadata.BitDepth)
+	})
+
+	t.Run("Float TIFF keeps header metadata", func(t *testing.T) {
+		path := filepath.Join(dir, "displacement.tif")
+		require.NoError(t, os.WriteFile(path, testFloatTIFF(16, 8), 0644))
+
+		res, err := generator.Generate(context.Background(), path)
+		require.NoError(t, err)
+		assert.True(t, res.IsPlaceholder)
+		assert.Equal(t, 16, res.Metadata.Width)
+		assert.Equal(t, 8, res.Metadata.Height)
+		assert.Equal(t, 32, res.Metadata.BitDepth)
+	})
+}
@@ -0,0 +1,181 @@
+package scanner
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+)
+
+var ktx2Identifier = []byte{0xAB, 'K', 'T', 'X', ' ', '2', '0', 0xBB, '\r', '\n', 0x1A, '\n'}
+
+// ktx2HeaderSize covers the identifier, the fixed header and the index up to the level index.
+const ktx2HeaderSize = 80
+
+// ktx2MaxDFDSize guards against corrupt data format descriptor lengths.
+const ktx2MaxDFDSize = 64 << 10
+
+// Data format descriptor color models.
+const (
+	khrDFModelBC1A  = 128
+	khrDFModelETC1S = 163
+	khrDFModelUASTC = 166
+)
+
+const khrDFChannelAlpha = 15
+
+var ktx2Supercompression = map[uint32]string{1: "BasisLZ", 2: "Zstandard", 3: "ZLIB"}
+
+type ktx2Format struct {
+	name     string
+	bitDepth int
+}
+
+var ktx2Formats = map[uint32]ktx2Format{
+	9: {"R8_UNORM", 8}, 16: {"R8G8_UNORM", 8}, 23: {"R8G8B8_UNORM", 8}, 29: {"R8G8B8_SRGB", 8},
+	37: {"R8G8B8A8_UNORM", 8}, 43: {"R8G8B8A8_SRGB", 8}, 44: {"B8G8R8A8_UNORM", 8}, 50: {"B8G8R8A8_SRGB", 8},
+	76: {"R16_SFLOAT", 16}, 83: {"R16G16_SFLOAT", 16}, 91: {"R16G16B16A16_UNORM", 16}, 97: {"R16G16B16A16_SFLOAT", 16},
+	100: {"R32_SFLOAT", 32}, 103: {"R32G32_SFLOAT", 32}, 109: {"R32G32B32A32_SFLOAT", 32},
+
+	131: {"BC1_RGB_UNORM", 8}, 132: {"BC1_RGB_SRGB", 8}, 133: {"BC1_RGBA_UNORM", 8}, 134: {"BC1_RGBA_SRGB", 8},
+	135: {"BC2_UNORM", 8}, 136: {"BC2_SRGB", 8}, 137: {"BC3_UNORM", 8}, 138: {"BC3_SRGB", 8},
+	139: {"BC4_UNORM", 8}, 140: {"BC4_SNORM", 8}, 141: {"BC5_UNORM", 8}, 142: {"BC5_SNORM", 8},
+	143: {"BC6H_UFLOAT", 16}, 144: {"BC6H_SFLOAT", 16}, 145: {"BC7_UNORM", 8}, 146: {"BC7_SRGB", 8},
+
+	147: {"ETC2_R8G8B8_UNORM", 8}, 148: {"ETC2_R8G8B8_SRGB", 8}, 149: {"ETC2_R8G8B8A1_UNORM", 8}, 150: {"ETC2_R8G8B8A1_SRGB", 8},
+	151: {"ETC2_R8G8B8A8_UNORM", 8}, 152: {"ETC2_R8G8B8A8_SRGB", 8},
+	153: {"EAC_R11_UNORM", 16}, 154: {"EAC_R11_SNORM", 16}, 155: {"EAC_R11G11_UNORM", 16}, 156: {"EAC_R11G11_SNORM", 16},
+}
+
+// astcBlockSizes lists the ASTC formats 157-184 in order, each as UNORM followed by SRGB.
+var astcBlockSizes = []string{"4x4", "5x4", "5x5", "6x5", "6x6", "8x5", "8x6", "8x8", "10x5", "10x6", "10x8", "10x10", "12x10", "12x12"}
+
+func ktx2FormatOf(vkFormat uint32) (ktx2Format, bool) {
+	if vkFormat >= 157 && vkFormat <= 184 {
+		i := vkFormat - 157
+		suffix := "UNORM"
+		if i%2 == 1 {
+			suffix = "SRGB"
+		}
+		return ktx2Format{name: "ASTC_" + astcBlockSizes[i/2] + "_" + suffix, bitDepth: 8}, true
+	}
+	f, ok := ktx2Formats[vkFormat]
+	return f, ok
+}
+
+// ktx2Family returns the block compression family of a format name ("BC7", "ETC2", "ASTC") or "none".
+func ktx2Family(name string) string {
+	family, _, _ := strings.Cut(name, "_")
+	switch {
+	case strings.HasPrefix(family, "BC"), family == "ETC2", family == "EAC", family == "ASTC":
+		return family
+	}
+	return "none"
+}
+
+// ReadKTX2File reads the header of a KTX 2.0 texture.
+func ReadKTX2File(path string) (TextureInfo, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return TextureInfo{}, err
+	}
+	defer f.Close()
+	return ReadKTX2(f)
+}
+
+// ReadKTX2 describes a KTX 2.0 texture from its header and data format descriptor. The pixel data
+// (usually Basis Universal or ASTC) is not decoded.
+func ReadKTX2(r io.ReadSeeker) (TextureInfo, error) {
+	var hdr [ktx2HeaderSize]byte
+	if _, err := io.ReadFull(r, hdr[:]); err != nil {
+		return TextureInfo{}, fmt.Errorf("failed to read header: %w", err)
+	}
+	if !bytes.Equal(hdr[:12], ktx2Identifier) {
+		return TextureInfo{}, errors.New("not a KTX2 file")
+	}
+	u32 := func(off int) uint32 { return binary.LittleEndian.Uint32(hdr[off:]) }
+	vkFormat := u32(12)
+	width, height := int(u32(20)), int(u32(24))
+	levelCount, scheme := int(u32(40)), u32(44)
+	dfdOffset, dfdLength := u32(48), u32(52)
+
+	info := TextureInfo{Width: width, Height: max(1, height), MipCount: max(1, levelCount), BitDepth: 8, Compression: "none"}
+	if width <= 0 || width > textureMaxDimension || info.Height > textureMaxDimension {
+		return info, fmt.Errorf("invalid dimensions %dx%d", width, height)
+	}
+
+	var colorModel uint32
+	var alpha bool
+	if dfdLength > 0 {
+		if dfdLength > ktx2MaxDFDSize {
+			return info, fmt.Errorf("data format descriptor too large: %d bytes", dfdLength)
+		}
+		if _, err := r.Seek(int64(dfdOffset), io.SeekStart); err != nil {
+			return info, err
+		}
+		dfd := make([]byte, dfdLength)
+		if _, err := io.ReadFull(r, dfd); err != nil {
+			return info, fmt.Errorf("failed to read data format descriptor: %w", err)
+		}
+		colorModel, alpha = parseKTX2DFD(dfd)
+	}
+	info.HasAlpha = alpha
+
+	if format, ok := ktx2FormatOf(vkFormat); ok {
+		info.Format = format.name
+		info.BitDepth = format.bitDepth
+		info.Compression = ktx2Family(format.name)
+	} else if vkFormat == 0 {
+		// VK_FORMAT_UNDEFINED: a Basis Universal payload, described only by the DFD.
+		switch colorModel {
+		case khrDFModelETC1S:
+			info.Format, info.Compression = "ETC1S", "ETC1S"
+		case khrDFModelUASTC:
+			info.Format, info.Compression = "UASTC", "UASTC"
+		default:
+			info.Format = "UNDEFINED"
+		}
+	} else {
+		info.Format = fmt.Sprintf("VK_FORMAT_%d", vkFormat)
+	}
+
+	if name, ok := ktx2Supercompression[scheme]; ok {
+		if info.Compression == "none" {
+			info.Compression = name
+		} else {
+			info.Compression += "+" + name
+		}
+	}
+	return info, nil
+}
+
+// parseKTX2DFD returns the color model of the basic descriptor block and whether a sample
+// describes an alpha channel.
+func parseKTX2DFD(dfd []byte) (uint32, bool) {
+	// dfdTotalSize, then the block header: vendor/type, version/size, model/primaries/transfer/flags,
+	// texel block dimensions and 8 bytes of plane sizes.
+	const samplesStart = 4 + 24
+	if len(dfd) < samplesStart {
+		return 0, false
+	}
+	blockSize := int(binary.LittleEndian.Uint16(dfd[10:]))
+	colorModel := uint32(dfd[12])
+	end := min(len(dfd), 4+blockSize)
+
+	alpha := false
+	for off := samplesStart; off+16 <= end; off += 16 {
+		channel := dfd[off+3] & 0x0f
+		switch colorModel {
+		case khrDFModelUASTC:
+			alpha = alpha || channel == 3 || channel == 5 // RGBA, RRRG
+		case khrDFModelBC1A:
+			alpha = alpha || channel == 1 // KHR_DF_CHANNEL_BC1A_ALPHAPRESENT
+		default:
+			alpha = alpha || channel == khrDFChannelAlpha
+		}
+	}
+	return colorModel, alpha
+}
@@ -0,0 +1,109 @@
+package scanner
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"io"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// testKTX2 buduje nagłówek KTX2 z podstawowym blokiem DFD zawierającym podane kanały próbek.
+func testKTX2(vkFormat, w, h, levels, scheme uint32, colorModel byte, channels ...byte) []byte {
+	dfd := make([]byte, 4+24+16*len(channels))
+	binary.LittleEndian.PutUint32(dfd, uint32(len(dfd)))
+	binary.LittleEndian.PutUint16(dfd[10:], uint16(24+16*len(channels)))
+	dfd[12] = colorModel
+	for i, c := range channels {
+		dfd[28+16*i+3] = c
+	}
+
+	out := append([]byte{}, ktx2Identifier...)
+	for _, v := range []uint32{vkFormat, 1, w, h, 0, 0, 1, levels, scheme} {
+		out = binary.LittleEndian.AppendUint32(out, v)
+	}
+	// Indeks: DFD zaraz za nagłówkiem i (pustym) indeksem poziomów.
+	dfdOffset := uint32(ktx2HeaderSize + 24*max(1, levels))
+	out = binary.LittleEndian.AppendUint32(out, dfdOffset)
+	out = binary.LittleEndian.AppendUint32(out, uint32(len(dfd)))
+	out = append(out, make([]byte, 24)...) // kvd, sgd
+	out = append(out, make([]byte, 24*max(1, levels))...)
+	return append(out, dfd...)
+}
+
+func TestReadKTX2(t *testing.T) {
+	tests := []struct {
+		name     string
+		data     []byte
+		expected TextureInfo
+	}{
+		{
+			name:     "BC7 sRGB",
+			data:     testKTX2(146, 512, 256, 10, 0, 128, 0),
+			expected: TextureInfo{Width: 512, Height: 256, MipCount: 10, Format: "BC7_SRGB", Compression: "BC7", BitDepth: 8},
+		},
+		{
+			name:     "ASTC with alpha and Zstandard",
+			data:     testKTX2(172, 64, 64, 1, 2, 162, 0, khrDFChannelAlpha),
+			expected: TextureInfo{Width: 64, Height: 64, MipCount: 1, Format: "ASTC_8x8_SRGB", Compression: "ASTC+Zstandard", BitDepth: 8, HasAlpha: true},
+		},
+		{
+			name:     "UASTC RGBA",
+			data:     testKTX2(0, 128, 128, 0, 2, khrDFModelUASTC, 3),
+			expected: TextureInfo{Width: 128, Height: 128, MipCount: 1, Format: "UASTC", Compression: "UASTC+Zstandard", BitDepth: 8, HasAlpha: true},
+		},
+		{
+			name:     "ETC1S",
+			data:     testKTX2(0, 256, 64, 9, 1, khrDFModelETC1S, 0),
+			expected: TextureInfo{Width: 256, Height: 64, MipCount: 9, Format: "ETC1S", Compression: "ETC1S+BasisLZ", BitDepth: 8},
+		},
+		{
+			name:     "Half float RGBA",
+			data:     testKTX2(97, 32, 32, 1, 0, 1, 0, 1, 2, khrDFChannelAlpha),
+			expected: TextureInfo{Width: 32, Height: 32, MipCount: 1, Format: "R16G16B16A16_SFLOAT", Compression: "none", BitDepth: 16, HasAlpha: true},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			info, err := ReadKTX2(bytes.NewReader(tt.data))
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, info)
+		})
+	}
+}
+
+func TestReadKTX2_InvalidInput(t *testing.T) {
+	valid := testKTX2(37, 4, 4, 1, 0, 1, 0)
+	for name, data := range map[string][]byte{
+		"Not KTX2":   append([]byte("KTX 11"), valid[6:]...),
+		"Truncated":  valid[:40],
+		"Zero width": testKTX2(37, 0, 4, 1, 0, 1, 0),
+	} {
+		t.Run(name, func(t *testing.T) {
+			_, err := ReadKTX2(bytes.NewReader(data))
+			assert.Error(t, err)
+		})
+	}
+}
+
+func TestDiskThumbnailGenerator_KTX2(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "albedo.ktx2")
+	require.NoError(t, os.WriteFile(path, testKTX2(0, 1024, 512, 11, 2, khrDFModelUASTC, 0), 0644))
+
+	generator := NewDiskThumbnailGenerator(dir, slog.New(slog.NewTextHandler(io.Discard, nil)))
+	res, err := generator.Generate(context.Background(), path)
+	require.NoError(t, err)
+	// Bez dekodera Basis Universal zostaje placeholder, ale z metadanymi z nagłówka.
+	assert.True(t, res.IsPlaceholder)
+	assert.Equal(t, 1024, res.Metadata.Width)
+	assert.Equal(t, 512, res.Metadata.Height)
+	assert.Equal(t, "11", res.Metadata.Properties[PropertyMipCount])
+	assert.Equal(t, "UASTC+Zstandard", res.Metadata.Properties[PropertyCompression])
+}
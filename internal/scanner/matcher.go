@@ -3,48 +3,47 @@ package scanner
 import (
 	"context"
 	"database/sql"
+	"eclat/internal/config"
 	"eclat/internal/database"
 	"fmt"
 	"path/filepath"
-	"regexp"
 	"strings"
 )
 
-// versionSuffixes defines regex patterns for common file versioning conventions.
-// These are used to strip suffixes like "_v1", " copy", etc., to find the base name.
-var versionSuffixes = []*regexp.Regexp{
-	regexp.MustCompile(`(?i)[_ -]v\d+$`),          // _v1, -v02, v3
-	regexp.MustCompile(`(?i)[_ -]ver\d+$`),        // _ver1
-	regexp.MustCompile(`(?i)[_ -]version\s*\d+$`), // _version 1
-	regexp.MustCompile(`(?i)[_ -]copy(\s*\d+)?$`), // _copy, -copy 2
-	regexp.MustCompile(`(?i)\s*\(\d+\)$`),         // (1), (2) - Windows/Linux system duplicates
-	regexp.MustCompile(`(?i)[_ -]final$`),         // _final
-	regexp.MustCompile(`(?i)[_ -]robocze$`),       // _robocze (Polish for working)
-	regexp.MustCompile(`(?i)[_ -](work|working|backup|temp|old)$`), // Common variants
-	regexp.MustCompile(`(?i)[_ -]\d+$`),           // _001, -02, etc. (trailing numbers with separator)
+// getBaseName strips version suffixes from a filename to determine its canonical "base" name,
+// using the grouping rules of the scan folder. See splitBaseName.
+func (s *Scanner) getBaseName(folderID int64, filename string) string {
+	base, _ := s.splitBaseName(s.config.GroupingRulesFor(folderID), filename)
+	return base
+}
+
+// materialChannel returns the PBR channel role of a texture map ("normal" for rock_normal_v2.png),
+// or an empty string for files that are not material maps.
+func (s *Scanner) materialChannel(folderID int64, filename string) string {
+	_, channel := s.splitBaseName(s.config.GroupingRulesFor(folderID), filename)
+	return channel
 }
 
-var leadingNumber = regexp.MustCompile(`(?i)^\d+[._ -]+`)
+// isTextureMapExt reports whether files with the extension can be maps of a PBR material.
+func isTextureMapExt(ext string) bool {
+	return isSupportedImageExt(ext) || isHDRExt(ext) || isTextureExt(ext)
+}
 
-// getBaseName strips version suffixes from a filename to determine its canonical "base" name.
-// It iteratively applies regex patterns until the name stabilizes.
-func (s *Scanner) getBaseName(filename string) string {
+// splitBaseName applies the strip patterns of the rules and, for texture maps, strips one
+// material channel suffix, until the name stabilizes. All versions and maps of a texture set
+// reduce to the same base name.
+func (s *Scanner) splitBaseName(rules *config.CompiledGroupingRules, filename string) (base, channel string) {
 	ext := filepath.Ext(filename)
-	base := strings.TrimSuffix(filename, ext)
+	base = strings.TrimSuffix(filename, ext)
 	base = strings.ToLower(base)
+	isTextureMap := isTextureMapExt(strings.ToLower(ext))
 
 	s.logger.Debug("🧠 getBaseName: starting", "file", filename, "ext", ext, "initial_base", base)
 
 	for {
 		original := base
-		// Strip leading numbers
-		base = leadingNumber.ReplaceAllString(base, "")
-		if base != original {
-			s.logger.Debug("🧠 getBaseName: stripped leading numbers", "from", original, "to", base)
-		}
-
-		// Strip version suffixes
-		for _, re := range versionSuffixes {
+		// Strip leading numbers, version suffixes, ...
+		for _, re := range rules.Strip {
 			stripped := re.ReplaceAllString(base, "")
 			if stripped != base {
 				s.logger.Debug("🧠 getBaseName: stripped suffix", "re", re.String(), "from", base, "to", stripped)
@@ -52,36 +51,63 @@ func (s *Scanner) getBaseName(filename string) string {
 			}
 		}
 
+		// Strip the channel suffix of a PBR map (rock_albedo -> rock)
+		if isTextureMap && channel == "" {
+			if material, role, ok := s.config.MatchMaterialChannel(base); ok {
+				s.logger.Debug("🧠 getBaseName: stripped material channel", "channel", role, "from", base, "to", material)
+				base, channel = material, role
+			}
+		}
+
 		if base == original {
 			break
 		}
 		base = strings.TrimSpace(base)
 	}
 
-	s.logger.Debug("🧠 getBaseName: final", "file", filename, "base", base)
-	return base
+	s.logger.Debug("🧠 getBaseName: final", "file", filename, "base", base, "channel", channel)
+	return base, channel
+}
+
+// heuristicBaseName returns the base name a file is matched by under the grouping rules of its
+// scan folder, and the session cache key for it. ok is false if the rules keep the file out of
+// name grouping.
+func (s *Scanner) heuristicBaseName(folderID int64, filename string) (baseName, cacheKey string, ok bool) {
+	rules := s.config.GroupingRulesFor(folderID)
+	if rules.Ignores(filename) {
+		s.logger.Debug("🧠 Heuristic match: file ignored by grouping rules", "file", filename)
+		return "", "", false
+	}
+	baseName, _ = s.splitBaseName(rules, filename)
+	if len(baseName) < rules.MinBaseLength {
+		s.logger.Debug("🧠 Heuristic match: base name too short", "file", filename, "base", baseName)
+		return "", "", false
+	}
+	if rules.CrossFolders {
+		return baseName, "*:" + baseName, true
+	}
+	return baseName, fmt.Sprintf("%d:%s", folderID, baseName), true
 }
 
 // TryHeuristicMatch attempts to find an existing asset group for a file based on its name.
-// It strips version suffixes and looks for potential siblings in the same folder.
+// It strips version suffixes and looks for potential siblings in the same folder, or in all
+// folders if the grouping rules cross folder boundaries.
 // Returns the GroupID if a match is found, otherwise empty string.
 func (s *Scanner) TryHeuristicMatch(ctx context.Context, folderID int64, filename string) (string, bool) {
-	baseName := s.getBaseName(filename)
-
-	if len(baseName) < 3 {
-		s.logger.Debug("🧠 Heuristic match: base name too short", "file", filename, "base", baseName)
+	baseName, cacheKey, ok := s.heuristicBaseName(folderID, filename)
+	if !ok {
 		return "", false
 	}
+	rules := s.config.GroupingRulesFor(folderID)
 
 	// 1. Check Session Cache (In-memory grouping for files in the current scan)
 	s.sessionMu.Lock()
-	cacheKey := fmt.Sprintf("%d:%s", folderID, baseName)
-	if cachedGroupID, ok := s.sessionHeuristicCache[cacheKey]; ok {
-		s.sessionMu.Unlock()
+	cachedGroupID, cached := s.sessionHeuristicCache[cacheKey]
+	s.sessionMu.Unlock()
+	if cached && !s.isGroupLocked(ctx, cachedGroupID) {
 		s.logger.Debug("🧠 Heuristic match: SUCCESS (Session Cache)", "file", filename, "base", baseName, "group_id", cachedGroupID)
 		return cachedGroupID, true
 	}
-	s.sessionMu.Unlock()
 
 	// 2. Check DB (Previously indexed files)
 	// Ask DB for candidates in the same folder matching "%BaseName%"
@@ -90,12 +116,26 @@ func (s *Scanner) TryHeuristicMatch(ctx context.Context, folderID int64, filenam
 	pattern := "%" + baseName + "%"
 	s.logger.Debug("🧠 Heuristic match: looking for siblings in DB", "file", filename, "base", baseName, "pattern", pattern)
 
-	candidates, err := s.db.FindPotentialSiblings(ctx, database.FindPotentialSiblingsParams{
-		ScanFolderID: sql.NullInt64{Int64: folderID, Valid: true},
-		FileName:     pattern,
-		ID:           0,
-		Limit:        50,
-	})
+	var candidates []database.FindPotentialSiblingsRow
+	var err error
+	if rules.CrossFolders {
+		var rows []database.FindPotentialSiblingsInLibraryRow
+		rows, err = s.db.FindPotentialSiblingsInLibrary(ctx, database.FindPotentialSiblingsInLibraryParams{
+			FileName: pattern,
+			ID:       0,
+			Limit:    50,
+		})
+		for _, r := range rows {
+			candidates = append(candidates, database.FindPotentialSiblingsRow(r))
+		}
+	} else {
+		candidates, err = s.db.FindPotentialSiblings(ctx, database.FindPotentialSiblingsParams{
+			ScanFolderID: sql.NullInt64{Int64: folderID, Valid: true},
+			FileName:     pattern,
+			ID:           0,
+			Limit:        50,
+		})
+	}
 
 	if err != nil {
 		s.logger.Warn("Heuristic SQL lookup failed", "error", err)
@@ -107,10 +147,17 @@ func (s *Scanner) TryHeuristicMatch(ctx context.Context, folderID int64, filenam
 	// Verify candidates: SQL LIKE is loose, so we verify if candidates
 	// actually reduce to the exact same base name.
 	for _, cand := range candidates {
-		candidateBase := s.getBaseName(cand.FileName)
+		if rules.Ignores(cand.FileName) {
+			continue
+		}
+		candidateBase, _ := s.splitBaseName(rules, cand.FileName)
 		s.logger.Debug("🧠 Heuristic match: checking candidate", "file", cand.FileName, "candidate_base", candidateBase, "target_base", baseName)
 
 		if candidateBase == baseName {
+			if s.isGroupLocked(ctx, cand.GroupID) {
+				s.logger.Debug("🧠 Heuristic match: candidate group is locked", "file", cand.FileName, "group_id", cand.GroupID)
+				continue
+			}
 			s.logger.Debug("🧠 Heuristic match: SUCCESS", "file", filename, "matched_with", cand.FileName, "group_id", cand.GroupID)
 			return cand.GroupID, true
 		}
@@ -119,3 +166,14 @@ func (s *Scanner) TryHeuristicMatch(ctx context.Context, folderID int64, filenam
 	s.logger.Debug("🧠 Heuristic match: NO MATCH found", "file", filename, "base", baseName)
 	return "", false
 }
+
+// isGroupLocked reports whether the user locked the group, so that no files are added to it
+// automatically.
+func (s *Scanner) isGroupLocked(ctx context.Context, groupID string) bool {
+	locked, err := s.db.IsGroupLocked(ctx, groupID)
+	if err != nil {
+		s.logger.Warn("Group lock lookup failed", "group_id", groupID, "error", err)
+		return false
+	}
+	return locked != 0
+}
@@ -3,6 +3,7 @@ package scanner
 import (
 	"context"
 	"database/sql"
+	"eclat/internal/config"
 	"eclat/internal/database"
 	"io"
 	"log/slog"
@@ -57,21 +58,50 @@ func TestUnit_GetBaseName(t *testing.T) {
 		{"version_control.txt", "version_control"}, // Nie jest na końcu
 		{"my_vacation.jpg", "my_vacation"},         // 'v' w środku słowa
 		{"v2.png", "v2"},                           // Zbyt krótka nazwa po wycięciu (zostawi jak jest lub wytnie do zera - zależnie od logiki, tu zakładamy że regex zadziała)
+
+		// Mapy PBR - sufiks kanału jest wycinany tylko z tekstur
+		{"Rock_Albedo.png", "rock"},
+		{"rock-normal-v2.tga", "rock"},
+		{"Rock Base Color.jpg", "rock"},
+		{"rock_ao_4.exr", "rock"},
+		{"rock_normal.blend", "rock_normal"},
+		{"metal.png", "metal"}, // Sam sufiks bez nazwy materiału
 	}
 
 	// Ponieważ getBaseName jest metodą Scannera, potrzebujemy pustej instancji
 	s := &Scanner{
 		logger: slog.New(slog.NewTextHandler(io.Discard, nil)),
+		config: config.NewScannerConfig(),
 	}
 
 	for _, tt := range tests {
 		t.Run(tt.input, func(t *testing.T) {
-			result := s.getBaseName(tt.input)
+			result := s.getBaseName(0, tt.input)
 			assert.Equal(t, tt.expected, result, "Dla wejścia '%s' oczekiwano '%s'", tt.input, tt.expected)
 		})
 	}
 }
 
+func TestUnit_MaterialChannel(t *testing.T) {
+	s := &Scanner{
+		logger: slog.New(slog.NewTextHandler(io.Discard, nil)),
+		config: config.NewScannerConfig(),
+	}
+
+	assert.Equal(t, config.ChannelBaseColor, s.materialChannel(0, "Rock_BaseColor.png"))
+	assert.Equal(t, config.ChannelBaseColor, s.materialChannel(0, "rock_base_color.png"))
+	assert.Equal(t, config.ChannelNormal, s.materialChannel(0, "rock_nrm_v3.dds"))
+	assert.Equal(t, config.ChannelHeight, s.materialChannel(0, "rock_disp.exr"))
+	assert.Equal(t, config.ChannelOpacity, s.materialChannel(0, "leaf_opacity.tif"))
+	assert.Empty(t, s.materialChannel(0, "rock.png"))
+	assert.Empty(t, s.materialChannel(0, "rock_metal.fbx"), "Tylko tekstury mają kanały")
+
+	// Lista synonimów jest konfigurowalna
+	s.config.SetMaterialChannels([]config.MaterialChannel{{Role: config.ChannelRoughness, Synonyms: []string{"glossless"}}})
+	assert.Equal(t, config.ChannelRoughness, s.materialChannel(0, "rock_glossless.png"))
+	assert.Empty(t, s.materialChannel(0, "rock_roughness.png"))
+}
+
 // TestUnit_TryHeuristicMatch sprawdza, czy funkcja potrafi znaleźć "kolegę" w bazie.
 // Wymaga bazy danych (setupLogicTest), ale testuje tylko jedną funkcję izolowaną.
 func TestUnit_TryHeuristicMatch(t *testing.T) {
@@ -17,7 +17,6 @@ import (
 	_ "image/jpeg"
 	_ "image/png"
 
-	"github.com/EdlinOrg/prominentcolor"
 	"github.com/lucasb-eyer/go-colorful"
 )
 
@@ -38,15 +37,35 @@ type ImageMetadata struct {
 	HasAlphaChannel bool
 	BitDepth        int
 	DominantColor   string
+	PerceptualHash  string          // 16 hex chars, empty if the image could not be decoded
+	ColorHistogram  []byte          // Lab histogram for "find similar", nil if the image could not be decoded
+	Colors          []WeightedColor // Main colors, most dominant first
+	// Properties are format specific details stored in asset_properties (keys: Property* constants).
+	// nil leaves the stored properties untouched.
+	Properties map[string]string
+	// Model is the geometry of glTF/GLB and OBJ files, stored in model_metadata. nil for other formats.
+	Model *ModelMetadata
 }
 
+// Keys of the format specific asset properties.
+const (
+	PropertyColorMode      = "colorMode"
+	PropertyLayerCount     = "layerCount"
+	PropertyChannelCount   = "channelCount"
+	PropertyChannels       = "channels"
+	PropertyCompression    = "compression"
+	PropertyMipCount       = "mipCount"
+	PropertyPixelFormat    = "pixelFormat"
+	PropertyBlenderVersion = "blenderVersion"
+)
+
 // DetermineFileType maps a file extension to a high-level FileType category.
 func DetermineFileType(extension string) string {
 	ext := strings.ToLower(extension)
 
 	switch ext {
 	// Images
-	case ".jpg", ".jpeg", ".png", ".gif", ".webp":
+	case ".jpg", ".jpeg", ".png", ".gif", ".webp", ".bmp":
 		return string(FileTypeImage)
 
 	// 3D Models
@@ -66,7 +85,7 @@ func DetermineFileType(extension string) string {
 		return string(FileTypeModel)
 
 	// Textures
-	case ".psd", ".psb", ".ai", ".eps", ".exr", ".hdr", ".tif", ".tiff", ".tga":
+	case ".psd", ".psb", ".ai", ".eps", ".exr", ".hdr", ".tif", ".tiff", ".tga", ".dds", ".ktx2":
 		return string(FileTypeTexture)
 
 	// Substance
@@ -103,47 +122,27 @@ func CalculateFileHash(filePath string, maxSizeBytes int64) (string, error) {
 	return hex.EncodeToString(hasher.Sum(nil)), nil
 }
 
-// CalculateDominantColor extracts the most prominent color from an image using K-Means clustering.
-// Returns the color in HEX format.
-func CalculateDominantColor(img image.Image) (string, error) {
-	if img == nil {
-		return "", errors.New("image is nil")
-	}
-
-	resizeSize := uint(prominentcolor.DefaultSize)
-	bgmasks := prominentcolor.GetDefaultMasks()
-	k := 1 // We want one main color
-	centroids, err := prominentcolor.KmeansWithAll(
-		k,
-		img,
-		prominentcolor.ArgumentNoCropping,
-		resizeSize,
-		bgmasks,
-	)
-
+// FindClosestPaletteColor finds the nearest color in a predefined palette to the given HEX color.
+// It uses CIELAB color space for perceptual distance calculation.
+func FindClosestPaletteColor(hexInput string, palette []config.PaletteColor) (string, error) {
+	entry, err := FindClosestPaletteEntry(hexInput, palette)
 	if err != nil {
-		return "", fmt.Errorf("kmeans processing failed: %w", err)
-	}
-
-	if len(centroids) == 0 {
-		return "", fmt.Errorf("no dominant color found")
+		return "", err
 	}
-
-	c := centroids[0]
-	hexColor := fmt.Sprintf("#%02X%02X%02X", c.Color.R, c.Color.G, c.Color.B)
-
-	return hexColor, nil
+	return entry.Hex, nil
 }
 
-// FindClosestPaletteColor finds the nearest color in a predefined palette to the given HEX color.
-// It uses CIELAB color space for perceptual distance calculation.
-func FindClosestPaletteColor(hexInput string, palette []config.PaletteColor) (string, error) {
+// FindClosestPaletteEntry is FindClosestPaletteColor returning the whole palette entry (name and hex).
+func FindClosestPaletteEntry(hexInput string, palette []config.PaletteColor) (config.PaletteColor, error) {
 	inputColor, err := colorful.Hex(hexInput)
 	if err != nil {
-		return "", fmt.Errorf("invalid hex color: %w", err)
+		return config.PaletteColor{}, fmt.Errorf("invalid hex color: %w", err)
+	}
+	if len(palette) == 0 {
+		return config.PaletteColor{}, errors.New("palette is empty")
 	}
 
-	var closestHex string
+	var closest config.PaletteColor
 	minDistance := math.MaxFloat64
 
 	for _, paletteItem := range palette {
@@ -157,15 +156,17 @@ func FindClosestPaletteColor(hexInput string, palette []config.PaletteColor) (st
 
 		if dist < minDistance {
 			minDistance = dist
-			closestHex = paletteItem.Hex
+			closest = paletteItem
 		}
 	}
 
-	return closestHex, nil
+	return closest, nil
 }
 
 // GetBitDepth estimates the bit depth per channel of an image based on its Go ColorModel.
 // Returns 8 for standard images, 16 for HDR/RAW formats, or 8 as a fallback.
+// The color model rounds low depths up to 8 and has no float models, so the thumbnail generator
+// prefers the PNG/TIFF header (readImageHeader) and the float decoders report their own depth.
 func GetBitDepth(img image.Image) int {
 	if img == nil {
 		return 0
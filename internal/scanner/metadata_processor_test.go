@@ -20,6 +20,9 @@ func TestDetermineFileType(t *testing.T) {
 		{"Maya ASCII", ".ma", "model"},
 		{"Photoshop File", ".psd", "texture"},
 		{"Substance Painter", ".spp", "texture"},
+		{"DirectDraw Surface", ".dds", "texture"},
+		{"KTX2 Texture", ".ktx2", "texture"},
+		{"Bitmap", ".bmp", "image"},
 		{"Unknown Executable", ".exe", "other"},
 		{"No Extension", "filename_without_ext", "other"},
 		{"Empty String", "", "other"},
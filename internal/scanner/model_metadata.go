@@ -0,0 +1,77 @@
+package scanner
+
+import (
+	"fmt"
+	"math"
+	"path/filepath"
+	"slices"
+	"strings"
+)
+
+// ModelMetadata describes the geometry of a 3D model file. It is stored in model_metadata.
+type ModelMetadata struct {
+	VertexCount   int64
+	TriangleCount int64 // Polygons are counted as the triangles they split into
+	MeshCount     int
+	MaterialCount int
+	TextureCount  int      // Includes textures embedded in the file
+	Textures      []string // Referenced texture files as written in the model, usually relative paths
+	Size          [3]float64
+}
+
+// isModelMetadataExt reports whether ReadModelMetadata understands the extension.
+func isModelMetadataExt(ext string) bool {
+	switch ext {
+	case ".gltf", ".glb", ".obj":
+		return true
+	}
+	return false
+}
+
+// ReadModelMetadata parses a glTF, GLB or OBJ file.
+func ReadModelMetadata(path string) (ModelMetadata, error) {
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".gltf", ".glb":
+		return ReadGLTFFile(path)
+	case ".obj":
+		return ReadOBJFile(path)
+	}
+	return ModelMetadata{}, fmt.Errorf("unsupported model format %q", filepath.Ext(path))
+}
+
+// modelBounds is an axis aligned bounding box that starts out empty.
+type modelBounds struct {
+	min, max [3]float64
+	valid    bool
+}
+
+func (b *modelBounds) add(p [3]float64) {
+	for _, v := range p {
+		if math.IsNaN(v) || math.IsInf(v, 0) {
+			return
+		}
+	}
+	if !b.valid {
+		b.min, b.max, b.valid = p, p, true
+		return
+	}
+	for i := range p {
+		b.min[i] = min(b.min[i], p[i])
+		b.max[i] = max(b.max[i], p[i])
+	}
+}
+
+func (b *modelBounds) size() [3]float64 {
+	if !b.valid {
+		return [3]float64{}
+	}
+	return [3]float64{b.max[0] - b.min[0], b.max[1] - b.min[1], b.max[2] - b.min[2]}
+}
+
+// appendUnique appends s to list unless it is already there.
+func appendUnique(list []string, s string) []string {
+	if slices.Contains(list, s) {
+		return list
+	}
+	return append(list, s)
+}
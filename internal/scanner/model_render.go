@@ -0,0 +1,367 @@
+package scanner
+
+import (
+	"bytes"
+	"context"
+	"eclat/internal/config"
+	"errors"
+	"fmt"
+	"image"
+	"image/color"
+	"image/draw"
+	"math"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/disintegration/imaging"
+)
+
+const (
+	// DefaultModelRenderBudget is how long one model may take to load and render before the
+	// generator gives up and uses the placeholder.
+	DefaultModelRenderBudget = 10 * time.Second
+
+	// modelRenderSize is the side of the model preview. It is rendered at modelRenderSupersample
+	// times the size and scaled down, which smooths the edges.
+	modelRenderSize        = 400
+	modelRenderSupersample = 2
+	// modelRenderMargin is the empty border around the framed model, as a share of the size.
+	modelRenderMargin = 0.06
+	// modelTextureMaxSize bounds decoded base color textures; a thumbnail never needs more.
+	modelTextureMaxSize = 512
+	// modelMaxFileSize guards memory: larger models (and their buffers) are not rendered.
+	modelMaxFileSize = 512 << 20
+	// modelCheckInterval is how many lines, vertices or triangles are processed between budget checks.
+	modelCheckInterval = 4096
+)
+
+// modelBackground is the backdrop of model previews, which are saved as JPEG.
+var modelBackground = color.NRGBA{R: 0x3a, G: 0x3c, B: 0x40, A: 0xff}
+
+// defaultModelColor is used for faces without a material.
+var defaultModelColor = [3]float32{0.8, 0.8, 0.8}
+
+var errModelEmpty = errors.New("model has no triangles to render")
+
+// renderMesh is triangle geometry in world space, ready to be rasterized.
+type renderMesh struct {
+	positions []float32 // x, y, z per vertex
+	uvs       []float32 // u, v per texture coordinate, v pointing down the image
+	faces     []meshFace
+	materials []renderMaterial
+}
+
+// meshCorner indexes the position and texture coordinate of a triangle corner; uv is -1 when
+// the corner has none.
+type meshCorner struct {
+	pos, uv int32
+}
+
+type meshFace struct {
+	corners  [3]meshCorner
+	material int32 // Index into materials, -1 for the default material
+}
+
+// renderMaterial is the base color of a material, multiplied by its texture if it has one.
+type renderMaterial struct {
+	color   [3]float32 // sRGB, 0..1
+	texture *image.NRGBA
+}
+
+func isModelExt(ext string) bool {
+	return ext == ".obj" || ext == ".gltf" || ext == ".glb"
+}
+
+// SetModelRenderBudget changes the time budget of a single model preview.
+func (g *DiskThumbnailGenerator) SetModelRenderBudget(budget time.Duration) {
+	g.modelRenderBudget = budget
+}
+
+func (g *DiskThumbnailGenerator) generateFromModel(ctx context.Context, srcPath, ext string) (ThumbnailResult, error) {
+	ctx, cancel := context.WithTimeout(ctx, g.modelRenderBudget)
+	defer cancel()
+
+	info, err := os.Stat(srcPath)
+	if err != nil {
+		return ThumbnailResult{}, err
+	}
+	if info.Size() > modelMaxFileSize {
+		return ThumbnailResult{}, fmt.Errorf("model too large to render (%d bytes)", info.Size())
+	}
+
+	var mesh *renderMesh
+	if ext == ".obj" {
+		mesh, err = loadOBJMesh(ctx, srcPath)
+	} else {
+		mesh, err = loadGLTFMesh(ctx, srcPath)
+	}
+	if err != nil {
+		return ThumbnailResult{}, err
+	}
+	render, err := renderModel(ctx, mesh, modelRenderSize*modelRenderSupersample)
+	if err != nil {
+		return ThumbnailResult{}, err
+	}
+	thumb := imaging.Resize(render, modelRenderSize, 0, imaging.Linear)
+	flat := flattenOnBackground(thumb, modelBackground)
+
+	// Models have no pixel dimensions, and their colors come from the model, not the backdrop.
+	imgMetadata := g.extractMetadataFromThumb(flat, image.Rectangle{}, 0, false)
+	if colors, err := CalculateColorPalette(thumb, PaletteSize, config.PredefinedPalette); err == nil && len(colors) > 0 {
+		imgMetadata.Colors = colors
+		imgMetadata.DominantColor = colors[0].PaletteHex
+	}
+
+	webPath, err := saveThumbnailJPEG(g.cacheDir, flat)
+	if err != nil {
+		return ThumbnailResult{}, err
+	}
+	return ThumbnailResult{
+		WebPath:  webPath,
+		Metadata: imgMetadata,
+	}, nil
+}
+
+// flattenOnBackground composites img over an opaque color.
+func flattenOnBackground(img image.Image, bg color.NRGBA) *image.NRGBA {
+	bounds := img.Bounds()
+	out := image.NewNRGBA(bounds)
+	draw.Draw(out, bounds, &image.Uniform{C: bg}, image.Point{}, draw.Src)
+	draw.Draw(out, bounds, img, bounds.Min, draw.Over)
+	return out
+}
+
+type vec3 [3]float32
+
+func (a vec3) sub(b vec3) vec3    { return vec3{a[0] - b[0], a[1] - b[1], a[2] - b[2]} }
+func (a vec3) dot(b vec3) float32 { return a[0]*b[0] + a[1]*b[1] + a[2]*b[2] }
+func (a vec3) cross(b vec3) vec3 {
+	return vec3{a[1]*b[2] - a[2]*b[1], a[2]*b[0] - a[0]*b[2], a[0]*b[1] - a[1]*b[0]}
+}
+func (a vec3) scale(s float32) vec3 { return vec3{a[0] * s, a[1] * s, a[2] * s} }
+func (a vec3) add(b vec3) vec3      { return vec3{a[0] + b[0], a[1] + b[1], a[2] + b[2]} }
+func (a vec3) normalize() vec3 {
+	l := float32(math.Sqrt(float64(a.dot(a))))
+	if l == 0 {
+		return a
+	}
+	return a.scale(1 / l)
+}
+
+// renderModel rasterizes the mesh into a size x size image with a transparent background. The
+// camera looks at the model from the front right and above (Y up), orthographically, framed to
+// the projected bounds. Faces are flat shaded with two-sided Lambert lighting, so meshes with
+// flipped normals still read well.
+func renderModel(ctx context.Context, mesh *renderMesh, size int) (*image.NRGBA, error) {
+	if len(mesh.faces) == 0 {
+		return nil, errModelEmpty
+	}
+
+	const azimuth, elevation = 35 * math.Pi / 180, 25 * math.Pi / 180
+	toCamera := vec3{
+		float32(math.Cos(elevation) * math.Sin(azimuth)),
+		float32(math.Sin(elevation)),
+		float32(math.Cos(elevation) * math.Cos(azimuth)),
+	}
+	right := vec3{toCamera[2], 0, -toCamera[0]}.normalize()
+	up := toCamera.cross(right)
+	// Key light from the upper left of the camera.
+	light := toCamera.add(up.scale(0.8)).add(right.scale(-0.5)).normalize()
+
+	// Project into camera space and find the framing.
+	count := len(mesh.positions) / 3
+	projected := make([]float32, 3*count)
+	minX, minY := float32(math.Inf(1)), float32(math.Inf(1))
+	maxX, maxY := float32(math.Inf(-1)), float32(math.Inf(-1))
+	for i := range count {
+		if i%modelCheckInterval == 0 && ctx.Err() != nil {
+			return nil, ctx.Err()
+		}
+		p := vec3(mesh.positions[3*i : 3*i+3])
+		x, y, z := p.dot(right), p.dot(up), p.dot(toCamera)
+		projected[3*i], projected[3*i+1], projected[3*i+2] = x, y, z
+		if isFinite32(x) && isFinite32(y) {
+			minX, maxX = min(minX, x), max(maxX, x)
+			minY, maxY = min(minY, y), max(maxY, y)
+		}
+	}
+	extent := max(maxX-minX, maxY-minY)
+	if !(extent > 0) || !isFinite32(extent) {
+		return nil, errModelEmpty
+	}
+	scale := float32(size) * (1 - 2*modelRenderMargin) / extent
+	centerX, centerY := (minX+maxX)/2, (minY+maxY)/2
+	half := float32(size) / 2
+	for i := range count {
+		projected[3*i] = (projected[3*i]-centerX)*scale + half
+		projected[3*i+1] = half - (projected[3*i+1]-centerY)*scale
+	}
+
+	img := image.NewNRGBA(image.Rect(0, 0, size, size))
+	depth := make([]float32, size*size)
+	for i := range depth {
+		depth[i] = float32(math.Inf(-1))
+	}
+
+	drawn := 0
+	for fi, face := range mesh.faces {
+		if fi%modelCheckInterval == 0 && ctx.Err() != nil {
+			return nil, ctx.Err()
+		}
+		var world [3]vec3
+		var screen [3]vec3
+		for k, c := range face.corners {
+			world[k] = vec3(mesh.positions[3*c.pos : 3*c.pos+3])
+			screen[k] = vec3(projected[3*c.pos : 3*c.pos+3])
+		}
+		normal := world[1].sub(world[0]).cross(world[2].sub(world[0]))
+		length := float32(math.Sqrt(float64(normal.dot(normal))))
+		if !(length > 0) || !isFinite32(length) {
+			continue
+		}
+		lambert := normal.dot(light) / length
+		if lambert < 0 {
+			lambert = -lambert
+		}
+		shade := 0.3 + 0.7*lambert
+
+		base, texture := defaultModelColor, (*image.NRGBA)(nil)
+		if face.material >= 0 && int(face.material) < len(mesh.materials) {
+			m := mesh.materials[face.material]
+			base, texture = m.color, m.texture
+		}
+		var uv [3][2]float32
+		textured := texture != nil
+		for k, c := range face.corners {
+			if c.uv < 0 {
+				textured = false
+				break
+			}
+			uv[k] = [2]float32{mesh.uvs[2*c.uv], mesh.uvs[2*c.uv+1]}
+		}
+
+		if rasterizeTriangle(img, depth, screen, func(w0, w1, w2 float32) [3]float32 {
+			col := base
+			if textured {
+				t := sampleTexture(texture, w0*uv[0][0]+w1*uv[1][0]+w2*uv[2][0], w0*uv[0][1]+w1*uv[1][1]+w2*uv[2][1])
+				col = [3]float32{col[0] * t[0], col[1] * t[1], col[2] * t[2]}
+			}
+			return [3]float32{col[0] * shade, col[1] * shade, col[2] * shade}
+		}) {
+			drawn++
+		}
+	}
+	if drawn == 0 {
+		return nil, errModelEmpty
+	}
+	return img, nil
+}
+
+// rasterizeTriangle fills the pixels whose centers lie in the triangle and are closer to the
+// camera than what is already drawn. shade gets the barycentric weights of the pixel.
+// It reports whether any pixel was written.
+func rasterizeTriangle(img *image.NRGBA, depth []float32, v [3]vec3, shade func(w0, w1, w2 float32) [3]float32) bool {
+	area := edge(v[0], v[1], v[2][0], v[2][1])
+	if area == 0 || !isFinite32(area) {
+		return false
+	}
+	size := img.Rect.Dx()
+	minX := max(int(math.Floor(float64(min(v[0][0], v[1][0], v[2][0])))), 0)
+	maxX := min(int(math.Ceil(float64(max(v[0][0], v[1][0], v[2][0])))), size-1)
+	minY := max(int(math.Floor(float64(min(v[0][1], v[1][1], v[2][1])))), 0)
+	maxY := min(int(math.Ceil(float64(max(v[0][1], v[1][1], v[2][1])))), size-1)
+
+	written := false
+	for y := minY; y <= maxY; y++ {
+		py := float32(y) + 0.5
+		for x := minX; x <= maxX; x++ {
+			px := float32(x) + 0.5
+			w0 := edge(v[1], v[2], px, py) / area
+			w1 := edge(v[2], v[0], px, py) / area
+			w2 := edge(v[0], v[1], px, py) / area
+			if w0 < 0 || w1 < 0 || w2 < 0 {
+				continue
+			}
+			z := w0*v[0][2] + w1*v[1][2] + w2*v[2][2]
+			idx := y*size + x
+			if z <= depth[idx] {
+				continue
+			}
+			depth[idx] = z
+			c := shade(w0, w1, w2)
+			o := img.PixOffset(x, y)
+			img.Pix[o] = unitToByte(c[0])
+			img.Pix[o+1] = unitToByte(c[1])
+			img.Pix[o+2] = unitToByte(c[2])
+			img.Pix[o+3] = 0xff
+			written = true
+		}
+	}
+	return written
+}
+
+// edge is twice the signed area of the triangle a, b, p.
+func edge(a, b vec3, px, py float32) float32 {
+	return (b[0]-a[0])*(py-a[1]) - (b[1]-a[1])*(px-a[0])
+}
+
+// sampleTexture returns the texel at u, v (nearest, repeating) as 0..1 RGB.
+func sampleTexture(t *image.NRGBA, u, v float32) [3]float32 {
+	w, h := t.Rect.Dx(), t.Rect.Dy()
+	x := int((u - float32(math.Floor(float64(u)))) * float32(w))
+	y := int((v - float32(math.Floor(float64(v)))) * float32(h))
+	x, y = min(max(x, 0), w-1), min(max(y, 0), h-1)
+	o := t.PixOffset(t.Rect.Min.X+x, t.Rect.Min.Y+y)
+	return [3]float32{float32(t.Pix[o]) / 255, float32(t.Pix[o+1]) / 255, float32(t.Pix[o+2]) / 255}
+}
+
+func unitToByte(v float32) uint8 {
+	if !(v > 0) {
+		return 0
+	}
+	if v >= 1 {
+		return 0xff
+	}
+	return uint8(v*255 + 0.5)
+}
+
+func isFinite32(v float32) bool {
+	return !math.IsNaN(float64(v)) && !math.IsInf(float64(v), 0)
+}
+
+// linearToSRGB converts a linear color component (glTF factors) to display values.
+func linearToSRGB(v float64) float32 {
+	if v <= 0.0031308 {
+		return float32(max(v, 0) * 12.92)
+	}
+	return float32(min(1.055*math.Pow(v, 1/2.4)-0.055, 1))
+}
+
+// loadModelTexture decodes a texture file referenced by a model, scaled down to modelTextureMaxSize.
+func loadModelTexture(path string) (*image.NRGBA, error) {
+	var img image.Image
+	var err error
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".tga":
+		img, _, err = DecodeTGAFile(path)
+	case ".dds":
+		img, _, err = DecodeDDSFile(path)
+	default:
+		img, err = imaging.Open(path)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return imaging.Fit(img, modelTextureMaxSize, modelTextureMaxSize, imaging.Box), nil
+}
+
+// decodeModelTexture decodes an embedded (GLB or data URI) texture.
+func decodeModelTexture(data []byte) (*image.NRGBA, error) {
+	img, err := imaging.Decode(bytes.NewReader(data))
+	if err != nil {
+		return nil, err
+	}
+	return imaging.Fit(img, modelTextureMaxSize, modelTextureMaxSize, imaging.Box), nil
+}
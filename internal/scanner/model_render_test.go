@@ -0,0 +1,344 @@
+package scanner
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"image"
+	"image/color"
+	"image/png"
+	"io"
+	"log/slog"
+	"math"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// testCubeOBJ to sześcian 2x2x2 z czworokątnymi ścianami i współrzędnymi UV.
+const testCubeOBJ = `mtllib cube.mtl
+v -1 -1 -1
+v 1 -1 -1
+v 1 1 -1
+v -1 1 -1
+v -1 -1 1
+v 1 -1 1
+v 1 1 1
+v -1 1 1
+vt 0 0
+vt 1 0
+vt 1 1
+vt 0 1
+usemtl Painted
+f 1/1 2/2 3/3 4/4
+f 5/1 6/2 7/3 8/4
+f 1/1 2/2 6/3 5/4
+f 4/1 3/2 7/3 8/4
+f 1/1 4/2 8/3 5/4
+f 2/1 3/2 7/3 6/4
+`
+
+// testCubeGLB buduje GLB z sześcianem (pozycje float, indeksy uint16) i materiałem o podanym kolorze
+// bazowym. Jeśli texture nie jest nil, trafia jako PNG do chunka BIN.
+func testCubeGLB(t *testing.T, baseColor [4]float64, texture image.Image, required ...string) []byte {
+	var bin bytes.Buffer
+	corners := [][3]float32{{-1, -1, -1}, {1, -1, -1}, {1, 1, -1}, {-1, 1, -1}, {-1, -1, 1}, {1, -1, 1}, {1, 1, 1}, {-1, 1, 1}}
+	for _, c := range corners {
+		require.NoError(t, binary.Write(&bin, binary.LittleEndian, c))
+	}
+	for _, uv := range [][2]float32{{0, 0}, {1, 0}, {1, 1}, {0, 1}, {0, 0}, {1, 0}, {1, 1}, {0, 1}} {
+		require.NoError(t, binary.Write(&bin, binary.LittleEndian, uv))
+	}
+	indices := []uint16{0, 1, 2, 0, 2, 3, 4, 5, 6, 4, 6, 7, 0, 1, 5, 0, 5, 4, 3, 2, 6, 3, 6, 7, 0, 3, 7, 0, 7, 4, 1, 2, 6, 1, 6, 5}
+	require.NoError(t, binary.Write(&bin, binary.LittleEndian, indices))
+
+	material := map[string]any{"pbrMetallicRoughness": map[string]any{"baseColorFactor": baseColor}}
+	doc := map[string]any{
+		"asset":  map[string]any{"version": "2.0"},
+		"scene":  0,
+		"scenes": []any{map[string]any{"nodes": []int{0}}},
+		"nodes":  []any{map[string]any{"mesh": 0, "scale": []float64{3, 1, 1}}},
+		"meshes": []any{map[string]any{"primitives": []any{map[string]any{
+			"attributes": map[string]int{"POSITION": 0, "TEXCOORD_0": 1}, "indices": 2, "material": 0,
+		}}}},
+		"bufferViews": []any{
+			map[string]any{"buffer": 0, "byteOffset": 0, "byteLength": 96},
+			map[string]any{"buffer": 0, "byteOffset": 96, "byteLength": 64},
+			map[string]any{"buffer": 0, "byteOffset": 160, "byteLength": 72},
+		},
+		"accessors": []any{
+			map[string]any{"bufferView": 0, "componentType": gltfFloat, "count": 8, "type": "VEC3", "min": []float64{-1, -1, -1}, "max": []float64{1, 1, 1}},
+			map[string]any{"bufferView": 1, "componentType": gltfFloat, "count": 8, "type": "VEC2"},
+			map[string]any{"bufferView": 2, "componentType": gltfUnsignedShort, "count": 36, "type": "SCALAR"},
+		},
+		"materials": []any{material},
+	}
+	if len(required) > 0 {
+		doc["extensionsRequired"] = required
+	}
+	if texture != nil {
+		for bin.Len()%4 != 0 {
+			bin.WriteByte(0)
+		}
+		offset := bin.Len()
+		require.NoError(t, png.Encode(&bin, texture))
+		doc["bufferViews"] = append(doc["bufferViews"].([]any), map[string]any{"buffer": 0, "byteOffset": offset, "byteLength": bin.Len() - offset})
+		doc["images"] = []any{map[string]any{"bufferView": 3, "mimeType": "image/png"}}
+		doc["textures"] = []any{map[string]any{"source": 0}}
+		material["pbrMetallicRoughness"].(map[string]any)["baseColorTexture"] = map[string]any{"index": 0}
+	}
+	for bin.Len()%4 != 0 {
+		bin.WriteByte(0)
+	}
+	doc["buffers"] = []any{map[string]any{"byteLength": bin.Len()}}
+
+	js, err := json.Marshal(doc)
+	require.NoError(t, err)
+	for len(js)%4 != 0 {
+		js = append(js, ' ')
+	}
+	out := []byte(glbMagic)
+	out = binary.LittleEndian.AppendUint32(out, 2)
+	out = binary.LittleEndian.AppendUint32(out, uint32(12+8+len(js)+8+bin.Len()))
+	out = binary.LittleEndian.AppendUint32(out, uint32(len(js)))
+	out = binary.LittleEndian.AppendUint32(out, glbChunkJSON)
+	out = append(out, js...)
+	out = binary.LittleEndian.AppendUint32(out, uint32(bin.Len()))
+	out = binary.LittleEndian.AppendUint32(out, glbChunkBIN)
+	return append(out, bin.Bytes()...)
+}
+
+// opaqueBounds zwraca prostokąt obejmujący nieprzezroczyste piksele.
+func opaqueBounds(img *image.NRGBA) image.Rectangle {
+	var r image.Rectangle
+	for y := img.Rect.Min.Y; y < img.Rect.Max.Y; y++ {
+		for x := img.Rect.Min.X; x < img.Rect.Max.X; x++ {
+			if img.NRGBAAt(x, y).A > 0 {
+				r = r.Union(image.Rect(x, y, x+1, y+1))
+			}
+		}
+	}
+	return r
+}
+
+func TestRenderModel_FramesAndShades(t *testing.T) {
+	mesh, err := loadOBJMesh(context.Background(), writeTestFile(t, "cube.obj", testCubeOBJ))
+	require.NoError(t, err)
+	assert.Len(t, mesh.faces, 12, "Quads are split into two triangles")
+	assert.Equal(t, []renderMaterial{{color: defaultModelColor}}, mesh.materials, "Missing library keeps the default color")
+
+	img, err := renderModel(context.Background(), mesh, 200)
+	require.NoError(t, err)
+
+	// Model wypełnia kadr do marginesu w dłuższym wymiarze i jest wyśrodkowany.
+	bounds := opaqueBounds(img)
+	margin := int(200 * modelRenderMargin)
+	assert.InDelta(t, 200-2*margin, max(bounds.Dx(), bounds.Dy()), 2)
+	assert.InDelta(t, 100, (bounds.Min.X+bounds.Max.X)/2, 2)
+	assert.InDelta(t, 100, (bounds.Min.Y+bounds.Max.Y)/2, 2)
+	assert.Equal(t, uint8(0), img.NRGBAAt(1, 1).A, "Background stays transparent")
+
+	// Widać trzy ściany sześcianu, każda oświetlona inaczej.
+	shades := make(map[uint8]bool)
+	for y := bounds.Min.Y; y < bounds.Max.Y; y++ {
+		for x := bounds.Min.X; x < bounds.Max.X; x++ {
+			if c := img.NRGBAAt(x, y); c.A > 0 {
+				shades[c.R] = true
+			}
+		}
+	}
+	assert.Len(t, shades, 3)
+}
+
+func TestRenderModel_EmptyAndDegenerate(t *testing.T) {
+	_, err := renderModel(context.Background(), &renderMesh{}, 64)
+	assert.ErrorIs(t, err, errModelEmpty)
+
+	flat := &renderMesh{
+		positions: []float32{0, 0, 0, 1, 1, 1, 2, 2, 2},
+		faces:     []meshFace{{corners: [3]meshCorner{{0, -1}, {1, -1}, {2, -1}}, material: -1}},
+	}
+	_, err = renderModel(context.Background(), flat, 64)
+	assert.ErrorIs(t, err, errModelEmpty, "A zero-area triangle draws nothing")
+}
+
+func TestLoadOBJMesh_TexturedMaterial(t *testing.T) {
+	dir := t.TempDir()
+	require.NoError(t, os.MkdirAll(filepath.Join(dir, "tex"), 0755))
+	var buf bytes.Buffer
+	require.NoError(t, png.Encode(&buf, solidImage(color.NRGBA{R: 200, G: 30, B: 30, A: 255})))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "tex", "paint red.png"), buf.Bytes(), 0644))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "cube.mtl"), []byte("newmtl Painted\nKd 0.8 0.8 0.8\nmap_Kd -s 1 1 1 tex\\paint red.png\n"), 0644))
+	path := filepath.Join(dir, "cube.obj")
+	require.NoError(t, os.WriteFile(path, []byte(testCubeOBJ+"f -1/-1 -2/-2 -3/-3\nf 1 2 99\n"), 0644))
+
+	mesh, err := loadOBJMesh(context.Background(), path)
+	require.NoError(t, err)
+	assert.Len(t, mesh.faces, 13, "Negative indices resolve, out of range faces are skipped")
+	assert.Equal(t, meshCorner{pos: 7, uv: 3}, mesh.faces[12].corners[0])
+	require.Len(t, mesh.materials, 1)
+	require.NotNil(t, mesh.materials[0].texture)
+	assert.Equal(t, [3]float32{1, 1, 1}, mesh.materials[0].color)
+	assert.Equal(t, []float32{0, 1}, mesh.uvs[:2], "OBJ v is flipped to image rows")
+
+	generator := NewDiskThumbnailGenerator(dir, slog.New(slog.NewTextHandler(io.Discard, nil)))
+	res, err := generator.Generate(context.Background(), path)
+	require.NoError(t, err)
+	assert.False(t, res.IsPlaceholder)
+	assert.FileExists(t, filepath.Join(dir, strings.TrimPrefix(res.WebPath, "/thumbnails/")))
+	assert.Zero(t, res.Metadata.Width, "Models have no pixel dimensions")
+	assert.NotEmpty(t, res.Metadata.PerceptualHash)
+	require.NotEmpty(t, res.Metadata.Colors)
+	for _, c := range res.Metadata.Colors {
+		var r, g, b int
+		_, err := fmt.Sscanf(c.Hex, "#%02X%02X%02X", &r, &g, &b)
+		require.NoError(t, err)
+		assert.Greater(t, r, g+50, "Only the shaded red paint counts, not the gray backdrop (%s)", c.Hex)
+	}
+}
+
+func TestLoadGLTFMesh(t *testing.T) {
+	dir := t.TempDir()
+
+	t.Run("GLB with factor", func(t *testing.T) {
+		path := filepath.Join(dir, "blue.glb")
+		require.NoError(t, os.WriteFile(path, testCubeGLB(t, [4]float64{0, 0, 1, 1}, nil), 0644))
+		mesh, err := loadGLTFMesh(context.Background(), path)
+		require.NoError(t, err)
+		assert.Len(t, mesh.faces, 12)
+		assert.Empty(t, mesh.uvs, "Texture coordinates are only kept for textured materials")
+		assert.Equal(t, [3]float32{0, 0, 1}, mesh.materials[0].color)
+		// Skala węzła trafia do pozycji w przestrzeni świata.
+		assert.Equal(t, []float32{-3, -1, -1}, mesh.positions[:3])
+	})
+
+	t.Run("GLB with embedded texture", func(t *testing.T) {
+		path := filepath.Join(dir, "textured.glb")
+		data := testCubeGLB(t, [4]float64{1, 1, 1, 1}, solidImage(color.NRGBA{G: 255, A: 255}))
+		require.NoError(t, os.WriteFile(path, data, 0644))
+		mesh, err := loadGLTFMesh(context.Background(), path)
+		require.NoError(t, err)
+		require.NotNil(t, mesh.materials[0].texture)
+		assert.Len(t, mesh.uvs, 16)
+
+		img, err := renderModel(context.Background(), mesh, 64)
+		require.NoError(t, err)
+		c := img.NRGBAAt(32, 32)
+		assert.Greater(t, c.G, uint8(100))
+		assert.Zero(t, c.R)
+	})
+
+	t.Run("glTF with data URI buffer", func(t *testing.T) {
+		glb := testCubeGLB(t, [4]float64{1, 0, 0, 1}, nil)
+		jsonLength := binary.LittleEndian.Uint32(glb[12:])
+		var doc map[string]any
+		require.NoError(t, json.Unmarshal(glb[20:20+jsonLength], &doc))
+		bin := glb[20+jsonLength+8:]
+		doc["buffers"] = []any{map[string]any{"byteLength": len(bin), "uri": "data:application/octet-stream;base64," + base64.StdEncoding.EncodeToString(bin)}}
+		js, err := json.Marshal(doc)
+		require.NoError(t, err)
+		path := filepath.Join(dir, "red.gltf")
+		require.NoError(t, os.WriteFile(path, js, 0644))
+
+		mesh, err := loadGLTFMesh(context.Background(), path)
+		require.NoError(t, err)
+		assert.Len(t, mesh.faces, 12)
+	})
+
+	t.Run("Draco", func(t *testing.T) {
+		path := filepath.Join(dir, "draco.glb")
+		require.NoError(t, os.WriteFile(path, testCubeGLB(t, [4]float64{1, 1, 1, 1}, nil, "KHR_draco_mesh_compression"), 0644))
+		_, err := loadGLTFMesh(context.Background(), path)
+		assert.ErrorContains(t, err, "KHR_draco_mesh_compression")
+	})
+}
+
+func TestGLTFLoader_Topologies(t *testing.T) {
+	positions := make([]float64, 0, 15)
+	for i := range 5 {
+		a := float64(i) * math.Pi / 4
+		positions = append(positions, math.Cos(a), math.Sin(a), 0)
+	}
+	for mode, expected := range map[int]int{gltfTriangles: 1, gltfTriangleStrip: 3, gltfTriangleFan: 3, 1: 0} {
+		t.Run(fmt.Sprint(mode), func(t *testing.T) {
+			js := fmt.Sprintf(`{"meshes": [{"primitives": [{"attributes": {"POSITION": 0}, "mode": %d}]}],
+				"accessors": [{"count": 5, "type": "VEC3", "componentType": 5126}]}`, mode)
+			var doc gltfDocument
+			require.NoError(t, json.Unmarshal([]byte(js), &doc))
+			l := &gltfLoader{ctx: context.Background(), doc: &doc, accessors: map[int][]float64{0: positions}, mesh: &renderMesh{}}
+			require.NoError(t, l.addMesh(0, gltfIdentity))
+			assert.Len(t, l.mesh.faces, expected)
+		})
+	}
+}
+
+func TestDiskThumbnailGenerator_ModelBudget(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "statue.glb")
+	require.NoError(t, os.WriteFile(path, testCubeGLB(t, [4]float64{1, 1, 1, 1}, nil), 0644))
+
+	generator := NewDiskThumbnailGenerator(dir, slog.New(slog.NewTextHandler(io.Discard, nil)))
+	res, err := generator.Generate(context.Background(), path)
+	require.NoError(t, err)
+	assert.False(t, res.IsPlaceholder)
+
+	// Przekroczony budżet czasu kończy się placeholderem, nie błędem.
+	generator.SetModelRenderBudget(time.Nanosecond)
+	res, err = generator.Generate(context.Background(), path)
+	require.NoError(t, err)
+	assert.True(t, res.IsPlaceholder)
+}
+
+// Model, który nie zmieścił się w budżecie, jest renderowany ponownie dopiero po zmianie pliku.
+func TestScanner_RescanSkipsModelOverBudget(t *testing.T) {
+	_, queries, scanner, root := setupLogicTest(t)
+	notifier := scanner.notifier.(*MockNotifier)
+	generator := NewDiskThumbnailGenerator(t.TempDir(), slog.New(slog.NewTextHandler(io.Discard, nil)))
+	generator.SetModelRenderBudget(time.Nanosecond)
+	scanner.thumbGen = generator
+	scanner.config.SetAllowedExtensions([]string{".glb"})
+	path := filepath.Join(root, "statue.glb")
+	require.NoError(t, os.WriteFile(path, testCubeGLB(t, [4]float64{1, 1, 1, 1}, nil), 0644))
+
+	scanAndWait(t, scanner)
+	assert.Equal(t, 1, notifier.LastProgress.Processed)
+	asset, err := queries.GetAssetByPath(context.Background(), path)
+	require.NoError(t, err)
+	assert.False(t, asset.PerceptualHash.Valid, "Placeholder nie ma hasha percepcyjnego")
+
+	scanAndWait(t, scanner)
+	assert.Equal(t, 1, notifier.LastProgress.Skipped)
+
+	later := time.Now().Add(time.Minute)
+	require.NoError(t, os.Chtimes(path, later, later))
+	scanAndWait(t, scanner)
+	assert.Equal(t, 1, notifier.LastProgress.Processed)
+}
+
+func TestDecodeDataURI(t *testing.T) {
+	for uri, expected := range map[string]string{
+		"data:application/octet-stream;base64,aGVsbG8=": "hello",
+		"data:application/octet-stream;base64,aGVsbG8":  "hello",
+		"data:text/plain,hello%20world":                 "hello world",
+	} {
+		data, err := decodeDataURI(uri)
+		require.NoError(t, err, uri)
+		assert.Equal(t, expected, string(data))
+	}
+	_, err := decodeDataURI("data:no-comma")
+	assert.Error(t, err)
+}
+
+func writeTestFile(t *testing.T, name, content string) string {
+	path := filepath.Join(t.TempDir(), name)
+	require.NoError(t, os.WriteFile(path, []byte(content), 0644))
+	return path
+}
@@ -0,0 +1,209 @@
+package scanner
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// objMaxLineLength guards against binary files with an .obj extension.
+const objMaxLineLength = 16 << 20
+
+// ReadOBJFile reads the statistics of a Wavefront OBJ file. Material libraries (mtllib) next to the
+// model are read for their texture maps; missing libraries are not an error.
+func ReadOBJFile(path string) (ModelMetadata, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return ModelMetadata{}, err
+	}
+	defer f.Close()
+
+	meta, libraries, err := ReadOBJ(f)
+	if err != nil {
+		return meta, err
+	}
+
+	var defined int
+	for _, lib := range objLibraryFiles(filepath.Dir(path), libraries) {
+		materials, textures, err := readMTLFile(lib)
+		if err != nil {
+			continue
+		}
+		defined += len(materials)
+		for _, t := range textures {
+			meta.Textures = appendUnique(meta.Textures, t)
+		}
+	}
+	if meta.MaterialCount == 0 {
+		meta.MaterialCount = defined
+	}
+	meta.TextureCount = len(meta.Textures)
+	return meta, nil
+}
+
+// ReadOBJ counts vertices, triangles (faces split into fans), objects and used materials, and
+// returns the arguments of the mtllib statements.
+func ReadOBJ(r io.Reader) (ModelMetadata, []string, error) {
+	var meta ModelMetadata
+	var box modelBounds
+	var objects, groups, materials, libraries []string
+
+	sc := bufio.NewScanner(r)
+	sc.Buffer(make([]byte, 64<<10), objMaxLineLength)
+	for sc.Scan() {
+		line := strings.TrimSpace(sc.Text())
+		if line == "" || line[0] == '#' {
+			continue
+		}
+		keyword := strings.Fields(line)[0]
+		rest := strings.TrimSpace(line[len(keyword):])
+		switch keyword {
+		case "v":
+			fields := strings.Fields(rest)
+			if len(fields) < 3 {
+				return meta, nil, fmt.Errorf("invalid vertex %q", line)
+			}
+			var p [3]float64
+			for i := range p {
+				v, err := strconv.ParseFloat(fields[i], 64)
+				if err != nil {
+					return meta, nil, fmt.Errorf("invalid vertex %q: %w", line, err)
+				}
+				p[i] = v
+			}
+			meta.VertexCount++
+			box.add(p)
+		case "f":
+			if n := len(strings.Fields(rest)); n >= 3 {
+				meta.TriangleCount += int64(n - 2)
+			}
+		case "o":
+			objects = appendUnique(objects, rest)
+		case "g":
+			groups = appendUnique(groups, rest)
+		case "usemtl":
+			materials = appendUnique(materials, rest)
+		case "mtllib":
+			libraries = appendUnique(libraries, rest)
+		}
+	}
+	if err := sc.Err(); err != nil {
+		return meta, nil, err
+	}
+
+	switch {
+	case len(objects) > 0:
+		meta.MeshCount = len(objects)
+	case len(groups) > 0:
+		meta.MeshCount = len(groups)
+	case meta.TriangleCount > 0:
+		meta.MeshCount = 1
+	}
+	meta.MaterialCount = len(materials)
+	meta.Size = box.size()
+	return meta, libraries, nil
+}
+
+// objLibraryFiles resolves mtllib arguments. One statement may list several libraries, but exporters
+// also write single file names containing spaces, so an existing file wins over splitting.
+func objLibraryFiles(dir string, statements []string) []string {
+	var files []string
+	for _, s := range statements {
+		if _, err := os.Stat(filepath.Join(dir, s)); err == nil {
+			files = appendUnique(files, filepath.Join(dir, s))
+			continue
+		}
+		for _, name := range strings.Fields(s) {
+			files = appendUnique(files, filepath.Join(dir, name))
+		}
+	}
+	return files
+}
+
+// mtlTextureKeywords are the MTL statements that reference a texture map.
+var mtlTextureKeywords = map[string]bool{
+	"map_ka": true, "map_kd": true, "map_ks": true, "map_ns": true, "map_d": true, "map_bump": true,
+	"bump": true, "disp": true, "decal": true, "refl": true, "norm": true,
+	"map_pr": true, "map_pm": true, "map_ps": true, "map_ke": true, "map_rma": true, "map_orm": true,
+}
+
+// mtlOptionArgs is the number of arguments of texture map options; -o, -s and -t take one to three.
+var mtlOptionArgs = map[string]int{
+	"-blendu": 1, "-blendv": 1, "-bm": 1, "-boost": 1, "-cc": 1, "-clamp": 1, "-imfchan": 1,
+	"-mm": 2, "-o": 3, "-s": 3, "-t": 3, "-texres": 1, "-type": 1,
+}
+
+// mtlMaterial is a material defined in a material library.
+type mtlMaterial struct {
+	name       string
+	diffuse    [3]float32 // Kd
+	diffuseMap string     // map_Kd as written in the library
+}
+
+// readMTLFile returns the materials a material library defines and all its texture files.
+func readMTLFile(path string) ([]mtlMaterial, []string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer f.Close()
+
+	var materials []mtlMaterial
+	var textures []string
+	sc := bufio.NewScanner(f)
+	sc.Buffer(make([]byte, 64<<10), objMaxLineLength)
+	for sc.Scan() {
+		fields := strings.Fields(sc.Text())
+		if len(fields) < 2 {
+			continue
+		}
+		keyword := strings.ToLower(fields[0])
+		switch {
+		case keyword == "newmtl":
+			materials = append(materials, mtlMaterial{
+				name:    strings.Join(fields[1:], " "),
+				diffuse: defaultModelColor,
+			})
+		case keyword == "kd" && len(materials) > 0 && len(fields) >= 4:
+			for i := range 3 {
+				if v, err := strconv.ParseFloat(fields[i+1], 32); err == nil {
+					materials[len(materials)-1].diffuse[i] = float32(v)
+				}
+			}
+		case mtlTextureKeywords[keyword]:
+			file := mtlTextureFile(fields[1:])
+			if file == "" {
+				continue
+			}
+			textures = appendUnique(textures, file)
+			if keyword == "map_kd" && len(materials) > 0 {
+				materials[len(materials)-1].diffuseMap = file
+			}
+		}
+	}
+	return materials, textures, sc.Err()
+}
+
+// mtlTextureFile skips the options of a texture statement; the rest is the file name, which may contain spaces.
+func mtlTextureFile(fields []string) string {
+	i := 0
+	for i < len(fields)-1 { // The last field is always (part of) the file name
+		args, ok := mtlOptionArgs[strings.ToLower(fields[i])]
+		if !ok {
+			break
+		}
+		i++
+		for n := 0; n < args && i < len(fields)-1; n++ {
+			// Options with optional arguments stop at the first non-number.
+			if _, err := strconv.ParseFloat(fields[i], 64); err != nil && args == 3 {
+				break
+			}
+			i++
+		}
+	}
+	return strings.Join(fields[i:], " ")
+}
@@ -0,0 +1,161 @@
+package scanner
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"image"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// loadOBJMesh reads the triangles of an OBJ file for the preview render, with the diffuse colors
+// and maps of the materials its libraries define.
+func loadOBJMesh(ctx context.Context, path string) (*renderMesh, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	mesh := &renderMesh{}
+	materialIndex := make(map[string]int32)
+	current := int32(-1)
+	var libraries []string
+	var corners []meshCorner
+
+	sc := bufio.NewScanner(f)
+	sc.Buffer(make([]byte, 64<<10), objMaxLineLength)
+	for line := 0; sc.Scan(); line++ {
+		if line%modelCheckInterval == 0 && ctx.Err() != nil {
+			return nil, ctx.Err()
+		}
+		text := strings.TrimSpace(sc.Text())
+		if text == "" || text[0] == '#' {
+			continue
+		}
+		fields := strings.Fields(text)
+		switch fields[0] {
+		case "v":
+			if len(fields) < 4 {
+				return nil, fmt.Errorf("invalid vertex %q", text)
+			}
+			for _, field := range fields[1:4] {
+				v, err := strconv.ParseFloat(field, 32)
+				if err != nil {
+					return nil, fmt.Errorf("invalid vertex %q: %w", text, err)
+				}
+				mesh.positions = append(mesh.positions, float32(v))
+			}
+		case "vt":
+			var uv [2]float32
+			for i := 0; i < 2 && i+1 < len(fields); i++ {
+				v, err := strconv.ParseFloat(fields[i+1], 32)
+				if err != nil {
+					return nil, fmt.Errorf("invalid texture coordinate %q: %w", text, err)
+				}
+				uv[i] = float32(v)
+			}
+			// OBJ puts v = 0 at the bottom of the image, the renderer at the top.
+			mesh.uvs = append(mesh.uvs, uv[0], 1-uv[1])
+		case "f":
+			corners = corners[:0]
+			for _, field := range fields[1:] {
+				c, ok := parseOBJCorner(field, len(mesh.positions)/3, len(mesh.uvs)/2)
+				if !ok {
+					corners = corners[:0]
+					break
+				}
+				corners = append(corners, c)
+			}
+			for i := 1; i+1 < len(corners); i++ {
+				mesh.faces = append(mesh.faces, meshFace{
+					corners:  [3]meshCorner{corners[0], corners[i], corners[i+1]},
+					material: current,
+				})
+			}
+		case "usemtl":
+			name := strings.TrimSpace(text[len(fields[0]):])
+			idx, ok := materialIndex[name]
+			if !ok {
+				idx = int32(len(mesh.materials))
+				materialIndex[name] = idx
+				mesh.materials = append(mesh.materials, renderMaterial{color: defaultModelColor})
+			}
+			current = idx
+		case "mtllib":
+			libraries = appendUnique(libraries, strings.TrimSpace(text[len(fields[0]):]))
+		}
+	}
+	if err := sc.Err(); err != nil {
+		return nil, err
+	}
+
+	textures := make(map[string]*image.NRGBA)
+	for _, lib := range objLibraryFiles(filepath.Dir(path), libraries) {
+		materials, _, err := readMTLFile(lib)
+		if err != nil {
+			continue
+		}
+		for _, m := range materials {
+			idx, used := materialIndex[m.name]
+			if !used {
+				continue
+			}
+			mesh.materials[idx].color = m.diffuse
+			if m.diffuseMap == "" {
+				continue
+			}
+			if ctx.Err() != nil {
+				return nil, ctx.Err()
+			}
+			texPath := filepath.Join(filepath.Dir(lib), filepath.FromSlash(strings.ReplaceAll(m.diffuseMap, `\`, "/")))
+			tex, seen := textures[texPath]
+			if !seen {
+				tex, _ = loadModelTexture(texPath) // A missing texture leaves the plain color
+				textures[texPath] = tex
+			}
+			if tex != nil {
+				// Kd tints the map; exporters often leave it at the default gray, which would darken the texture.
+				mesh.materials[idx].color = [3]float32{1, 1, 1}
+				mesh.materials[idx].texture = tex
+			}
+		}
+	}
+	return mesh, nil
+}
+
+// parseOBJCorner parses a face corner (v, v/vt, v//vn or v/vt/vn). Indices are 1-based, negative
+// ones count back from the last vertex.
+func parseOBJCorner(field string, positions, uvs int) (meshCorner, bool) {
+	parts := strings.Split(field, "/")
+	pos, ok := resolveOBJIndex(parts[0], positions)
+	if !ok {
+		return meshCorner{}, false
+	}
+	c := meshCorner{pos: pos, uv: -1}
+	if len(parts) > 1 && parts[1] != "" {
+		if uv, ok := resolveOBJIndex(parts[1], uvs); ok {
+			c.uv = uv
+		}
+	}
+	return c, true
+}
+
+func resolveOBJIndex(s string, count int) (int32, bool) {
+	i, err := strconv.Atoi(s)
+	if err != nil || i == 0 {
+		return 0, false
+	}
+	if i < 0 {
+		i += count
+	} else {
+		i--
+	}
+	if i < 0 || i >= count {
+		return 0, false
+	}
+	return int32(i), true
+}
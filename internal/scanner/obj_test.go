@@ -0,0 +1,100 @@
+package scanner
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestReadOBJ(t *testing.T) {
+	obj := `# Blender 4.2
+mtllib crate.mtl
+o Crate
+v -1.0 0.0 -1.0
+v 1.0 0.0 -1.0
+v 1.0 2.0 -1.0
+v -1.0 2.0 1.0
+vt 0 0
+vn 0 0 1
+usemtl Wood
+f 1/1/1 2/1/1 3/1/1 4/1/1
+o Lid
+v	0.5	3.0	0.0
+usemtl Metal
+f 1 2 5
+f 1 2
+usemtl Wood
+`
+	meta, libraries, err := ReadOBJ(strings.NewReader(obj))
+	require.NoError(t, err)
+	assert.Equal(t, int64(5), meta.VertexCount, "vt/vn are not vertices")
+	assert.Equal(t, int64(3), meta.TriangleCount, "Quads split into two triangles, degenerate faces are skipped")
+	assert.Equal(t, 2, meta.MeshCount)
+	assert.Equal(t, 2, meta.MaterialCount)
+	assert.Equal(t, [3]float64{2, 3, 2}, meta.Size)
+	assert.Equal(t, []string{"crate.mtl"}, libraries)
+
+	t.Run("Groups without objects", func(t *testing.T) {
+		meta, _, err := ReadOBJ(strings.NewReader("g a\nv 0 0 0\nv 1 0 0\nv 0 1 0\nf 1 2 3\ng b\ng a\n"))
+		require.NoError(t, err)
+		assert.Equal(t, 2, meta.MeshCount)
+	})
+
+	t.Run("Invalid vertex", func(t *testing.T) {
+		_, _, err := ReadOBJ(strings.NewReader("v 1 two 3\n"))
+		assert.Error(t, err)
+	})
+}
+
+func TestReadOBJFile_MaterialLibraries(t *testing.T) {
+	dir := t.TempDir()
+	write := func(name, content string) string {
+		path := filepath.Join(dir, name)
+		require.NoError(t, os.WriteFile(path, []byte(content), 0644))
+		return path
+	}
+
+	write("shared materials.mtl", `newmtl Wood
+map_Kd -o 0.5 0.5 -clamp on textures/wood albedo.png
+map_Bump -bm 0.8 textures/wood_normal.png
+bump textures/wood_normal.png
+newmtl Metal
+map_Ks -s 2 textures/metal.png
+`)
+	write("extra.mtl", "newmtl Glass\nmap_d glass_mask.png\n")
+
+	t.Run("Library name with spaces", func(t *testing.T) {
+		path := write("crate.obj", "mtllib shared materials.mtl\nv 0 0 0\nv 1 0 0\nv 0 1 0\nf 1 2 3\n")
+		meta, err := ReadOBJFile(path)
+		require.NoError(t, err)
+		assert.Equal(t, []string{"textures/wood albedo.png", "textures/wood_normal.png", "textures/metal.png"}, meta.Textures)
+		assert.Equal(t, 3, meta.TextureCount)
+		assert.Equal(t, 2, meta.MaterialCount, "Without usemtl the library materials are counted")
+	})
+
+	t.Run("Several libraries and a missing one", func(t *testing.T) {
+		write("other.mtl", "newmtl Stone\n")
+		path := write("scene.obj", "mtllib other.mtl extra.mtl missing.mtl\nusemtl Glass\nv 0 0 0\n")
+		meta, err := ReadOBJFile(path)
+		require.NoError(t, err)
+		assert.Equal(t, []string{"glass_mask.png"}, meta.Textures)
+		assert.Equal(t, 1, meta.MaterialCount, "usemtl wins over library definitions")
+	})
+}
+
+func TestMTLTextureFile(t *testing.T) {
+	tests := map[string]string{
+		"albedo.png":                        "albedo.png",
+		"-o 1 albedo.png":                   "albedo.png",
+		"-s 1 2 3 -mm 0 1 my albedo.png":    "my albedo.png",
+		"-imfchan r -type sphere rough.png": "rough.png",
+		"-bm":                               "-bm",
+	}
+	for in, expected := range tests {
+		assert.Equal(t, expected, mtlTextureFile(strings.Fields(in)), in)
+	}
+}
@@ -0,0 +1,102 @@
+package scanner
+
+import (
+	"errors"
+	"fmt"
+	"image"
+	"math"
+	"math/bits"
+	"slices"
+	"strconv"
+
+	"github.com/disintegration/imaging"
+)
+
+const (
+	phashSampleSize = 32 // the image is reduced to 32x32 before the DCT
+	phashLowFreq    = 8  // only the top-left 8x8 DCT coefficients are kept -> 64 bit hash
+)
+
+// phashCosTable caches cos((2x+1)uπ/2N) for the 32-point DCT.
+var phashCosTable = func() [phashLowFreq][phashSampleSize]float64 {
+	var t [phashLowFreq][phashSampleSize]float64
+	for u := 0; u < phashLowFreq; u++ {
+		for x := 0; x < phashSampleSize; x++ {
+			t[u][x] = math.Cos(float64(2*x+1) * float64(u) * math.Pi / float64(2*phashSampleSize))
+		}
+	}
+	return t
+}()
+
+// CalculatePerceptualHash computes a 64-bit DCT based perceptual hash (pHash) of an image.
+// Unlike the SHA-256 file hash, it survives re-encoding, resizing and small color corrections,
+// so visually identical images end up within a few bits of each other.
+func CalculatePerceptualHash(img image.Image) (uint64, error) {
+	if img == nil {
+		return 0, errors.New("image is nil")
+	}
+	if img.Bounds().Empty() {
+		return 0, errors.New("image is empty")
+	}
+
+	small := imaging.Grayscale(imaging.Resize(img, phashSampleSize, phashSampleSize, imaging.Lanczos))
+
+	var pixels [phashSampleSize][phashSampleSize]float64
+	for y := 0; y < phashSampleSize; y++ {
+		for x := 0; x < phashSampleSize; x++ {
+			// Grayscale image: R == G == B
+			pixels[y][x] = float64(small.Pix[y*small.Stride+x*4])
+		}
+	}
+
+	// Separable 2D DCT-II, computing only the low frequencies we need.
+	var rows [phashSampleSize][phashLowFreq]float64
+	for y := 0; y < phashSampleSize; y++ {
+		for u := 0; u < phashLowFreq; u++ {
+			var sum float64
+			for x := 0; x < phashSampleSize; x++ {
+				sum += pixels[y][x] * phashCosTable[u][x]
+			}
+			rows[y][u] = sum
+		}
+	}
+
+	coeffs := make([]float64, 0, phashLowFreq*phashLowFreq)
+	for v := 0; v < phashLowFreq; v++ {
+		for u := 0; u < phashLowFreq; u++ {
+			var sum float64
+			for y := 0; y < phashSampleSize; y++ {
+				sum += rows[y][u] * phashCosTable[v][y]
+			}
+			coeffs = append(coeffs, sum)
+		}
+	}
+
+	// The DC term only carries the average brightness, so it is left out of the median.
+	sorted := slices.Clone(coeffs[1:])
+	slices.Sort(sorted)
+	median := (sorted[len(sorted)/2-1] + sorted[len(sorted)/2]) / 2
+
+	var hash uint64
+	for i, c := range coeffs {
+		if c > median {
+			hash |= 1 << uint(i)
+		}
+	}
+	return hash, nil
+}
+
+// HammingDistance returns the number of differing bits between two perceptual hashes.
+func HammingDistance(a, b uint64) int {
+	return bits.OnesCount64(a ^ b)
+}
+
+// FormatPerceptualHash encodes a hash the way it is stored in assets.perceptual_hash.
+func FormatPerceptualHash(hash uint64) string {
+	return fmt.Sprintf("%016x", hash)
+}
+
+// ParsePerceptualHash decodes a value stored in assets.perceptual_hash.
+func ParsePerceptualHash(s string) (uint64, error) {
+	return strconv.ParseUint(s, 16, 64)
+}
@@ -0,0 +1,73 @@
+package scanner
+
+import (
+	"bytes"
+	"image"
+	"image/color"
+	"image/jpeg"
+	"math/rand"
+	"testing"
+
+	"github.com/disintegration/imaging"
+	"github.com/stretchr/testify/assert"
+)
+
+// Generuje "teksturę" z losowych bloków kolorów (stały seed), żeby hash miał na czym pracować.
+func makeTestTexture(w, h int, seed int64) image.Image {
+	r := rand.New(rand.NewSource(seed))
+	img := image.NewNRGBA(image.Rect(0, 0, w, h))
+	const blocks = 16
+	for by := 0; by < blocks; by++ {
+		for bx := 0; bx < blocks; bx++ {
+			c := color.NRGBA{R: uint8(r.Intn(256)), G: uint8(r.Intn(256)), B: uint8(r.Intn(256)), A: 255}
+			for y := by * h / blocks; y < (by+1)*h/blocks; y++ {
+				for x := bx * w / blocks; x < (bx+1)*w/blocks; x++ {
+					img.SetNRGBA(x, y, c)
+				}
+			}
+		}
+	}
+	return img
+}
+
+func TestCalculatePerceptualHash(t *testing.T) {
+	original := makeTestTexture(512, 512, 1)
+	hOriginal, err := CalculatePerceptualHash(original)
+	assert.NoError(t, err)
+
+	t.Run("Resized copy stays close", func(t *testing.T) {
+		h, err := CalculatePerceptualHash(imaging.Resize(original, 128, 128, imaging.Linear))
+		assert.NoError(t, err)
+		assert.LessOrEqual(t, HammingDistance(hOriginal, h), 4)
+	})
+
+	t.Run("Re-encoded JPEG stays close", func(t *testing.T) {
+		var buf bytes.Buffer
+		assert.NoError(t, jpeg.Encode(&buf, original, &jpeg.Options{Quality: 40}))
+		decoded, err := jpeg.Decode(&buf)
+		assert.NoError(t, err)
+
+		h, err := CalculatePerceptualHash(decoded)
+		assert.NoError(t, err)
+		assert.LessOrEqual(t, HammingDistance(hOriginal, h), 4)
+	})
+
+	t.Run("Different image is far", func(t *testing.T) {
+		h, err := CalculatePerceptualHash(makeTestTexture(512, 512, 2))
+		assert.NoError(t, err)
+		assert.Greater(t, HammingDistance(hOriginal, h), 12)
+	})
+
+	t.Run("Should handle nil image safe", func(t *testing.T) {
+		_, err := CalculatePerceptualHash(nil)
+		assert.Error(t, err)
+	})
+
+	t.Run("Round trips through the DB format", func(t *testing.T) {
+		s := FormatPerceptualHash(hOriginal)
+		assert.Len(t, s, 16)
+		parsed, err := ParsePerceptualHash(s)
+		assert.NoError(t, err)
+		assert.Equal(t, hOriginal, parsed)
+	})
+}
@@ -0,0 +1,608 @@
+package scanner
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"image"
+	"image/color"
+	"image/jpeg"
+	"io"
+	"math"
+	"os"
+	"strconv"
+
+	"github.com/lucasb-eyer/go-colorful"
+)
+
+// Photoshop color modes as stored in the file header.
+const (
+	psdModeBitmap       = 0
+	psdModeGrayscale    = 1
+	psdModeIndexed      = 2
+	psdModeRGB          = 3
+	psdModeCMYK         = 4
+	psdModeMultichannel = 7
+	psdModeDuotone      = 8
+	psdModeLab          = 9
+)
+
+// Image resource IDs we care about.
+const (
+	psdResourceThumbnailPS4 = 1033 // JPEG thumbnail, BGR (Photoshop 4.0)
+	psdResourceThumbnail    = 1036 // JPEG thumbnail, RGB
+	psdResourceVersionInfo  = 1057 // contains hasRealMergedData
+)
+
+const (
+	// psdPreviewMaxSize is the longer edge of the decoded composite. The composite is subsampled
+	// while it is read, so a 30k x 30k PSB never ends up in memory at full size.
+	psdPreviewMaxSize = 1024
+	// psdMaxDimension guards against corrupt headers (PSB allows 300 000 px, PSD 30 000 px).
+	psdMaxDimension = 300000
+)
+
+var psdColorModeNames = map[int]string{
+	psdModeBitmap:       "Bitmap",
+	psdModeGrayscale:    "Grayscale",
+	psdModeIndexed:      "Indexed",
+	psdModeRGB:          "RGB",
+	psdModeCMYK:         "CMYK",
+	psdModeMultichannel: "Multichannel",
+	psdModeDuotone:      "Duotone",
+	psdModeLab:          "Lab",
+}
+
+// PSDInfo describes a Photoshop document (.psd) or large document (.psb).
+type PSDInfo struct {
+	Width      int
+	Height     int
+	Depth      int // Bits per channel: 1, 8, 16 or 32
+	Channels   int
+	ColorMode  string
+	LayerCount int
+	HasAlpha   bool
+	IsPSB      bool
+}
+
+// Properties returns the format details stored in asset_properties.
+func (i PSDInfo) Properties() map[string]string {
+	return map[string]string{
+		PropertyColorMode:    i.ColorMode,
+		PropertyLayerCount:   strconv.Itoa(i.LayerCount),
+		PropertyChannelCount: strconv.Itoa(i.Channels),
+	}
+}
+
+// psdDocument is the parsed structure of a file up to the composite image data.
+type psdDocument struct {
+	PSDInfo
+	mode          int
+	palette       []byte // Indexed mode: 256 reds, 256 greens, 256 blues
+	thumbnail     []byte // JPEG data of the thumbnail resource
+	thumbnailBGR  bool
+	hasMergedData bool // false when saved without "Maximize compatibility"
+	mergedAlpha   bool // the first extra channel is the transparency of the composite
+	imageData     int64
+}
+
+// DecodePSDFile reads the composite preview and the document info of a .psd/.psb file.
+func DecodePSDFile(path string) (image.Image, PSDInfo, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, PSDInfo{}, err
+	}
+	defer f.Close()
+	return DecodePSD(f)
+}
+
+// DecodePSD reads the flattened composite stored at the end of a Photoshop file, downsampled to
+// at most psdPreviewMaxSize. If the composite is missing or uses an unsupported compression,
+// the embedded JPEG thumbnail resource is returned instead.
+func DecodePSD(r io.ReadSeeker) (image.Image, PSDInfo, error) {
+	doc, err := readPSDStructure(r)
+	if err != nil {
+		return nil, PSDInfo{}, err
+	}
+
+	var compositeErr error
+	if doc.hasMergedData {
+		img, err := doc.decodeComposite(r)
+		if err == nil {
+			return img, doc.PSDInfo, nil
+		}
+		compositeErr = err
+	} else {
+		compositeErr = errors.New("file has no merged composite")
+	}
+
+	if len(doc.thumbnail) == 0 {
+		return nil, doc.PSDInfo, fmt.Errorf("no preview available: %w", compositeErr)
+	}
+	img, err := doc.decodeThumbnail()
+	if err != nil {
+		return nil, doc.PSDInfo, fmt.Errorf("composite: %v, thumbnail: %w", compositeErr, err)
+	}
+	return img, doc.PSDInfo, nil
+}
+
+// ReadPSDInfo reads only the header and the layer count.
+func ReadPSDInfo(r io.ReadSeeker) (PSDInfo, error) {
+	doc, err := readPSDStructure(r)
+	if err != nil {
+		return PSDInfo{}, err
+	}
+	return doc.PSDInfo, nil
+}
+
+func readPSDStructure(r io.ReadSeeker) (*psdDocument, error) {
+	var header struct {
+		Signature [4]byte
+		Version   uint16
+		Reserved  [6]byte
+		Channels  uint16
+		Height    uint32
+		Width     uint32
+		Depth     uint16
+		Mode      uint16
+	}
+	if err := binary.Read(r, binary.BigEndian, &header); err != nil {
+		return nil, fmt.Errorf("failed to read PSD header: %w", err)
+	}
+	if string(header.Signature[:]) != "8BPS" {
+		return nil, errors.New("not a Photoshop file")
+	}
+	if header.Version != 1 && header.Version != 2 {
+		return nil, fmt.Errorf("unsupported PSD version %d", header.Version)
+	}
+	if header.Channels < 1 || header.Channels > 56 {
+		return nil, fmt.Errorf("invalid channel count %d", header.Channels)
+	}
+	if header.Width == 0 || header.Height == 0 || header.Width > psdMaxDimension || header.Height > psdMaxDimension {
+		return nil, fmt.Errorf("invalid dimensions %dx%d", header.Width, header.Height)
+	}
+	switch header.Depth {
+	case 1, 8, 16, 32:
+	default:
+		return nil, fmt.Errorf("invalid bit depth %d", header.Depth)
+	}
+	modeName, ok := psdColorModeNames[int(header.Mode)]
+	if !ok {
+		return nil, fmt.Errorf("unsupported color mode %d", header.Mode)
+	}
+
+	doc := &psdDocument{
+		PSDInfo: PSDInfo{
+			Width:     int(header.Width),
+			Height:    int(header.Height),
+			Depth:     int(header.Depth),
+			Channels:  int(header.Channels),
+			ColorMode: modeName,
+			IsPSB:     header.Version == 2,
+		},
+		mode:          int(header.Mode),
+		hasMergedData: true,
+	}
+
+	// Color mode data: the palette of indexed images, opaque data otherwise.
+	colorData, err := readPSDSection(r, false)
+	if err != nil {
+		return nil, fmt.Errorf("color mode data: %w", err)
+	}
+	if doc.mode == psdModeIndexed {
+		if len(colorData) < 768 {
+			return nil, errors.New("indexed image without a palette")
+		}
+		doc.palette = colorData[:768]
+	}
+
+	resources, err := readPSDSection(r, false)
+	if err != nil {
+		return nil, fmt.Errorf("image resources: %w", err)
+	}
+	doc.parseResources(resources)
+
+	if err := doc.readLayerSection(r); err != nil {
+		return nil, fmt.Errorf("layer section: %w", err)
+	}
+
+	doc.HasAlpha = doc.mergedAlpha || doc.Channels > doc.colorChannels()
+	return doc, nil
+}
+
+// readPSDSection reads a length-prefixed section into memory. Only used for the small sections.
+func readPSDSection(r io.Reader, long bool) ([]byte, error) {
+	length, err := readPSDLength(r, long)
+	if err != nil {
+		return nil, err
+	}
+	if length > 64<<20 {
+		return nil, fmt.Errorf("section too large (%d bytes)", length)
+	}
+	data := make([]byte, length)
+	if _, err := io.ReadFull(r, data); err != nil {
+		return nil, err
+	}
+	return data, nil
+}
+
+// readPSDLength reads a 4-byte length, or an 8-byte one for PSB specific fields.
+func readPSDLength(r io.Reader, long bool) (int64, error) {
+	if long {
+		var n uint64
+		if err := binary.Read(r, binary.BigEndian, &n); err != nil {
+			return 0, err
+		}
+		if n > math.MaxInt64 {
+			return 0, errors.New("invalid length")
+		}
+		return int64(n), nil
+	}
+	var n uint32
+	if err := binary.Read(r, binary.BigEndian, &n); err != nil {
+		return 0, err
+	}
+	return int64(n), nil
+}
+
+// parseResources picks the thumbnail and the merged data flag out of the image resource blocks.
+// Malformed blocks end the parsing silently, resources are optional.
+func (d *psdDocument) parseResources(data []byte) {
+	for len(data) >= 12 {
+		if string(data[:4]) != "8BIM" {
+			return
+		}
+		id := binary.BigEndian.Uint16(data[4:6])
+		nameLen := int(data[6])
+		nameSize := 1 + nameLen
+		if nameSize%2 != 0 {
+			nameSize++ // Pascal string padded to an even size
+		}
+		pos := 6 + nameSize
+		if pos+4 > len(data) {
+			return
+		}
+		size := int(binary.BigEndian.Uint32(data[pos : pos+4]))
+		pos += 4
+		if size < 0 || pos+size > len(data) {
+			return
+		}
+		body := data[pos : pos+size]
+
+		switch id {
+		case psdResourceThumbnail, psdResourceThumbnailPS4:
+			// 28 byte header: format (1 = JPEG), width, height, widthbytes, total size, size, bpp, planes
+			if len(body) > 28 && binary.BigEndian.Uint32(body[:4]) == 1 {
+				if d.thumbnail == nil || id == psdResourceThumbnail {
+					d.thumbnail = body[28:]
+					d.thumbnailBGR = id == psdResourceThumbnailPS4
+				}
+			}
+		case psdResourceVersionInfo:
+			if len(body) >= 5 {
+				d.hasMergedData = body[4] != 0
+			}
+		}
+
+		pos += size
+		if size%2 != 0 {
+			pos++
+		}
+		if pos > len(data) {
+			return
+		}
+		data = data[pos:]
+	}
+}
+
+// readLayerSection reads the layer count and leaves r at the start of the composite image data.
+func (d *psdDocument) readLayerSection(r io.ReadSeeker) error {
+	sectionLen, err := readPSDLength(r, d.IsPSB)
+	if err != nil {
+		return err
+	}
+	start, err := r.Seek(0, io.SeekCurrent)
+	if err != nil {
+		return err
+	}
+	end := start + sectionLen
+	d.imageData = end
+	if sectionLen == 0 {
+		return nil
+	}
+
+	layerInfoLen, err := readPSDLength(r, d.IsPSB)
+	if err != nil {
+		return err
+	}
+	if layerInfoLen >= 2 {
+		var count int16
+		if err := binary.Read(r, binary.BigEndian, &count); err != nil {
+			return err
+		}
+		d.setLayerCount(count)
+	}
+
+	// 16 and 32-bit documents keep the layers in an "Lr16"/"Lr32" tagged block after the global mask.
+	if d.LayerCount == 0 && d.Depth > 8 {
+		lenSize := int64(4)
+		if d.IsPSB {
+			lenSize = 8
+		}
+		if _, err := r.Seek(start+lenSize+layerInfoLen, io.SeekStart); err != nil {
+			return err
+		}
+		if maskLen, err := readPSDLength(r, false); err == nil {
+			if pos, err := r.Seek(maskLen, io.SeekCurrent); err == nil {
+				d.readTaggedLayerCount(r, end-pos)
+			}
+		}
+	}
+
+	_, err = r.Seek(end, io.SeekStart)
+	return err
+}
+
+// readTaggedLayerCount scans the additional layer information for Lr16/Lr32 blocks.
+func (d *psdDocument) readTaggedLayerCount(r io.Reader, remaining int64) {
+	br := bufio.NewReader(io.LimitReader(r, remaining))
+	for {
+		var tag struct {
+			Signature [4]byte
+			Key       [4]byte
+		}
+		if err := binary.Read(br, binary.BigEndian, &tag); err != nil {
+			return
+		}
+		sig, key := string(tag.Signature[:]), string(tag.Key[:])
+		if sig != "8BIM" && sig != "8B64" {
+			return
+		}
+		// In PSB files a few keys, including the layer blocks, have 8-byte lengths.
+		long := d.IsPSB && (key == "Lr16" || key == "Lr32" || key == "Layr" || key == "Mt16" ||
+			key == "Mt32" || key == "Mtrn" || key == "Alph" || key == "FMsk" || key == "lnk2" ||
+			key == "FEid" || key == "FXid" || key == "PxSD")
+		length, err := readPSDLength(br, long)
+		if err != nil {
+			return
+		}
+		if key == "Lr16" || key == "Lr32" {
+			var count int16
+			if err := binary.Read(br, binary.BigEndian, &count); err == nil {
+				d.setLayerCount(count)
+			}
+			return
+		}
+		if _, err := br.Discard(int(length)); err != nil {
+			return
+		}
+	}
+}
+
+// setLayerCount stores the layer count. A negative count means the first extra channel holds
+// the transparency of the merged composite.
+func (d *psdDocument) setLayerCount(count int16) {
+	if count < 0 {
+		d.mergedAlpha = true
+		count = -count
+	}
+	d.LayerCount = int(count)
+}
+
+// colorChannels is the number of channels making up the color of the mode.
+func (d *psdDocument) colorChannels() int {
+	switch d.mode {
+	case psdModeRGB, psdModeLab:
+		return 3
+	case psdModeCMYK:
+		return 4
+	default:
+		return 1
+	}
+}
+
+// decodeComposite reads the image data section, which stores every channel as a separate plane.
+func (d *psdDocument) decodeComposite(r io.ReadSeeker) (image.Image, error) {
+	if _, err := r.Seek(d.imageData, io.SeekStart); err != nil {
+		return nil, err
+	}
+	var compression uint16
+	if err := binary.Read(r, binary.BigEndian, &compression); err != nil {
+		return nil, fmt.Errorf("failed to read compression: %w", err)
+	}
+	if compression != 0 && compression != 1 {
+		return nil, fmt.Errorf("unsupported composite compression %d", compression)
+	}
+
+	needed := d.colorChannels()
+	if d.HasAlpha && d.Channels > needed {
+		needed++
+	}
+	needed = min(needed, d.Channels)
+
+	step := 1
+	if longest := max(d.Width, d.Height); longest > psdPreviewMaxSize {
+		step = (longest + psdPreviewMaxSize - 1) / psdPreviewMaxSize
+	}
+	outW, outH := (d.Width+step-1)/step, (d.Height+step-1)/step
+
+	rowBytes := (d.Width*d.Depth + 7) / 8
+	var rowCounts []int
+	if compression == 1 {
+		// PackBits: byte counts of every row of every channel come first.
+		rowCounts = make([]int, d.Height*d.Channels)
+		for i := range rowCounts {
+			if d.IsPSB {
+				var n uint32
+				if err := binary.Read(r, binary.BigEndian, &n); err != nil {
+					return nil, fmt.Errorf("failed to read row sizes: %w", err)
+				}
+				rowCounts[i] = int(n)
+			} else {
+				var n uint16
+				if err := binary.Read(r, binary.BigEndian, &n); err != nil {
+					return nil, fmt.Errorf("failed to read row sizes: %w", err)
+				}
+				rowCounts[i] = int(n)
+			}
+		}
+	}
+
+	br := bufio.NewReaderSize(r, 64<<10)
+	row := make([]byte, rowBytes)
+	packed := make([]byte, 0, rowBytes+rowBytes/128+1)
+	planes := make([][]uint8, needed)
+	for c := 0; c < needed; c++ {
+		plane := make([]uint8, outW*outH)
+		for y := 0; y < d.Height; y++ {
+			if compression == 1 {
+				n := rowCounts[c*d.Height+y]
+				if cap(packed) < n {
+					packed = make([]byte, n)
+				}
+				packed = packed[:n]
+				if _, err := io.ReadFull(br, packed); err != nil {
+					return nil, fmt.Errorf("channel %d row %d: %w", c, y, err)
+				}
+				if err := unpackBits(packed, row); err != nil {
+					return nil, fmt.Errorf("channel %d row %d: %w", c, y, err)
+				}
+			} else if _, err := io.ReadFull(br, row); err != nil {
+				return nil, fmt.Errorf("channel %d row %d: %w", c, y, err)
+			}
+
+			if y%step != 0 {
+				continue
+			}
+			isAlpha := c >= d.colorChannels()
+			out := plane[(y/step)*outW : (y/step+1)*outW]
+			for ox := range out {
+				out[ox] = d.sample(row, ox*step, isAlpha)
+			}
+		}
+		planes[c] = plane
+	}
+
+	return d.compose(planes, outW, outH), nil
+}
+
+// sample returns the 8-bit value of pixel x of a decoded row.
+func (d *psdDocument) sample(row []byte, x int, isAlpha bool) uint8 {
+	switch d.Depth {
+	case 1:
+		// Bitmap: 1 is black.
+		if row[x/8]&(0x80>>(x%8)) != 0 {
+			return 0
+		}
+		return 255
+	case 16:
+		return row[x*2] // high byte
+	case 32:
+		v := math.Float32frombits(binary.BigEndian.Uint32(row[x*4:]))
+		f := math.Max(0, math.Min(1, float64(v)))
+		if !isAlpha {
+			f = math.Pow(f, 1/2.2) // 32-bit documents are linear
+		}
+		return uint8(f*255 + 0.5)
+	default:
+		return row[x]
+	}
+}
+
+// compose turns the channel planes into an NRGBA preview according to the color mode.
+func (d *psdDocument) compose(planes [][]uint8, w, h int) image.Image {
+	img := image.NewNRGBA(image.Rect(0, 0, w, h))
+	alphaPlane := -1
+	if d.HasAlpha && len(planes) > d.colorChannels() {
+		alphaPlane = d.colorChannels()
+	}
+	for i := 0; i < w*h; i++ {
+		var c color.NRGBA
+		switch d.mode {
+		case psdModeRGB:
+			c = color.NRGBA{R: planes[0][i], G: planes[1][i], B: planes[2][i]}
+		case psdModeCMYK:
+			// Stored inverted: 255 means no ink.
+			k := uint16(planes[3][i])
+			c = color.NRGBA{
+				R: uint8(uint16(planes[0][i]) * k / 255),
+				G: uint8(uint16(planes[1][i]) * k / 255),
+				B: uint8(uint16(planes[2][i]) * k / 255),
+			}
+		case psdModeLab:
+			l := float64(planes[0][i]) / 255
+			a := (float64(planes[1][i]) - 128) / 100
+			b := (float64(planes[2][i]) - 128) / 100
+			r, g, bl := colorful.Lab(l, a, b).Clamped().RGB255()
+			c = color.NRGBA{R: r, G: g, B: bl}
+		case psdModeIndexed:
+			idx := int(planes[0][i])
+			c = color.NRGBA{R: d.palette[idx], G: d.palette[256+idx], B: d.palette[512+idx]}
+		default:
+			v := planes[0][i]
+			c = color.NRGBA{R: v, G: v, B: v}
+		}
+		c.A = 255
+		if alphaPlane >= 0 {
+			c.A = planes[alphaPlane][i]
+		}
+		img.Pix[i*4], img.Pix[i*4+1], img.Pix[i*4+2], img.Pix[i*4+3] = c.R, c.G, c.B, c.A
+	}
+	return img
+}
+
+// decodeThumbnail decodes the JPEG thumbnail resource.
+func (d *psdDocument) decodeThumbnail() (image.Image, error) {
+	img, err := jpeg.Decode(bytes.NewReader(d.thumbnail))
+	if err != nil {
+		return nil, err
+	}
+	if !d.thumbnailBGR {
+		return img, nil
+	}
+	bounds := img.Bounds()
+	swapped := image.NewNRGBA(bounds)
+	for y := bounds.Min.Y; y < bounds.Max.Y; y++ {
+		for x := bounds.Min.X; x < bounds.Max.X; x++ {
+			r, g, b, _ := img.At(x, y).RGBA()
+			swapped.SetNRGBA(x, y, color.NRGBA{R: uint8(b >> 8), G: uint8(g >> 8), B: uint8(r >> 8), A: 255})
+		}
+	}
+	return swapped, nil
+}
+
+// unpackBits decodes a PackBits compressed row into dst, which must have the exact row size.
+func unpackBits(src, dst []byte) error {
+	si, di := 0, 0
+	for si < len(src) && di < len(dst) {
+		n := int(int8(src[si]))
+		si++
+		switch {
+		case n >= 0:
+			count := n + 1
+			if si+count > len(src) || di+count > len(dst) {
+				return errors.New("packbits literal run out of bounds")
+			}
+			copy(dst[di:], src[si:si+count])
+			si += count
+			di += count
+		case n > -128:
+			count := 1 - n
+			if si >= len(src) || di+count > len(dst) {
+				return errors.New("packbits repeat run out of bounds")
+			}
+			for i := 0; i < count; i++ {
+				dst[di+i] = src[si]
+			}
+			si++
+			di += count
+		}
+		// -128 is a no-op
+	}
+	if di != len(dst) {
+		return fmt.Errorf("packbits row decoded to %d of %d bytes", di, len(dst))
+	}
+	return nil
+}
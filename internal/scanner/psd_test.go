@@ -0,0 +1,311 @@
+package scanner
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"image"
+	"image/color"
+	"image/jpeg"
+	"io"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// testPSD opisuje minimalny dokument Photoshopa budowany w pamięci na potrzeby testów.
+type testPSD struct {
+	psb         bool
+	mode        uint16
+	depth       uint16
+	width       int
+	height      int
+	planes      [][]byte // kanały jako surowe wiersze w docelowej głębi
+	compression uint16
+	layerCount  int16
+	lr16Count   int16 // liczba warstw zapisana w bloku "Lr16" zamiast w sekcji warstw
+	resources   []byte
+}
+
+func (p testPSD) bytes(t *testing.T) []byte {
+	var buf bytes.Buffer
+	w := func(v any) { assert.NoError(t, binary.Write(&buf, binary.BigEndian, v)) }
+	length := func(n int, long bool) {
+		if long {
+			w(uint64(n))
+		} else {
+			w(uint32(n))
+		}
+	}
+
+	version := uint16(1)
+	if p.psb {
+		version = 2
+	}
+	buf.WriteString("8BPS")
+	w(version)
+	buf.Write(make([]byte, 6))
+	w(uint16(len(p.planes)))
+	w(uint32(p.height))
+	w(uint32(p.width))
+	w(p.depth)
+	w(p.mode)
+
+	w(uint32(0)) // color mode data
+	w(uint32(len(p.resources)))
+	buf.Write(p.resources)
+
+	// Sekcja warstw: tylko liczba warstw, bez rekordów (czytnik i tak ją pomija)
+	var layers bytes.Buffer
+	lw := func(v any) { assert.NoError(t, binary.Write(&layers, binary.BigEndian, v)) }
+	if p.layerCount != 0 {
+		if p.psb {
+			lw(uint64(2))
+		} else {
+			lw(uint32(2))
+		}
+		lw(p.layerCount)
+	} else {
+		if p.psb {
+			lw(uint64(0))
+		} else {
+			lw(uint32(0))
+		}
+	}
+	lw(uint32(0)) // global layer mask
+	if p.lr16Count != 0 {
+		layers.WriteString("8BIMLr16")
+		if p.psb {
+			lw(uint64(2))
+		} else {
+			lw(uint32(2))
+		}
+		lw(p.lr16Count)
+	}
+	length(layers.Len(), p.psb)
+	buf.Write(layers.Bytes())
+
+	w(p.compression)
+	rowBytes := (p.width*int(p.depth) + 7) / 8
+	switch p.compression {
+	case 1:
+		var rows [][]byte
+		for _, plane := range p.planes {
+			for y := 0; y < p.height; y++ {
+				rows = append(rows, packBits(plane[y*rowBytes:(y+1)*rowBytes]))
+			}
+		}
+		for _, r := range rows {
+			if p.psb {
+				w(uint32(len(r)))
+			} else {
+				w(uint16(len(r)))
+			}
+		}
+		for _, r := range rows {
+			buf.Write(r)
+		}
+	default:
+		for _, plane := range p.planes {
+			buf.Write(plane)
+		}
+	}
+	return buf.Bytes()
+}
+
+// packBits koduje wiersz: powtórzenia od 3 bajtów, reszta jako literały.
+func packBits(row []byte) []byte {
+	var out []byte
+	for i := 0; i < len(row); {
+		run := 1
+		for i+run < len(row) && row[i+run] == row[i] && run < 128 {
+			run++
+		}
+		if run >= 3 {
+			out = append(out, byte(int8(1-run)), row[i])
+			i += run
+			continue
+		}
+		start := i
+		for i < len(row) && i-start < 128 && !(i+2 < len(row) && row[i] == row[i+1] && row[i] == row[i+2]) {
+			i++
+		}
+		out = append(out, byte(i-start-1))
+		out = append(out, row[start:i]...)
+	}
+	return out
+}
+
+func filledPlane(w, h int, v byte) []byte {
+	return bytes.Repeat([]byte{v}, w*h)
+}
+
+// psdResource buduje blok zasobu "8BIM" z pustą nazwą.
+func psdResource(id uint16, body []byte) []byte {
+	var buf bytes.Buffer
+	buf.WriteString("8BIM")
+	binary.Write(&buf, binary.BigEndian, id)
+	buf.Write([]byte{0, 0}) // pusty Pascal string, dopełniony do parzystej długości
+	binary.Write(&buf, binary.BigEndian, uint32(len(body)))
+	buf.Write(body)
+	if len(body)%2 != 0 {
+		buf.WriteByte(0)
+	}
+	return buf.Bytes()
+}
+
+func jpegThumbnailResource(t *testing.T, c color.Color) []byte {
+	img := image.NewRGBA(image.Rect(0, 0, 16, 8))
+	for i := 0; i < 16*8; i++ {
+		img.Set(i%16, i/16, c)
+	}
+	var jpg bytes.Buffer
+	assert.NoError(t, jpeg.Encode(&jpg, img, nil))
+
+	var body bytes.Buffer
+	for _, v := range []uint32{1, 16, 8, 48, 48 * 8, uint32(jpg.Len())} {
+		binary.Write(&body, binary.BigEndian, v)
+	}
+	binary.Write(&body, binary.BigEndian, uint16(24))
+	binary.Write(&body, binary.BigEndian, uint16(1))
+	body.Write(jpg.Bytes())
+	return psdResource(psdResourceThumbnail, body.Bytes())
+}
+
+func TestDecodePSD_RGBWithMergedAlpha(t *testing.T) {
+	w, h := 4, 2
+	alpha := filledPlane(w, h, 255)
+	alpha[0] = 0 // pierwszy piksel przezroczysty
+	data := testPSD{
+		mode: psdModeRGB, depth: 8, width: w, height: h,
+		planes:     [][]byte{filledPlane(w, h, 200), filledPlane(w, h, 10), filledPlane(w, h, 30), alpha},
+		layerCount: -2,
+	}.bytes(t)
+
+	img, info, err := DecodePSD(bytes.NewReader(data))
+	assert.NoError(t, err)
+	assert.Equal(t, PSDInfo{Width: 4, Height: 2, Depth: 8, Channels: 4, ColorMode: "RGB", LayerCount: 2, HasAlpha: true}, info)
+	assert.Equal(t, color.NRGBA{R: 200, G: 10, B: 30, A: 255}, img.At(1, 1))
+	assert.Equal(t, uint8(0), img.(*image.NRGBA).NRGBAAt(0, 0).A)
+	assert.Equal(t, map[string]string{PropertyColorMode: "RGB", PropertyLayerCount: "2", PropertyChannelCount: "4"}, info.Properties())
+}
+
+func TestDecodePSD_PSB16BitGrayscaleRLE(t *testing.T) {
+	w, h := 300, 3
+	plane := make([]byte, w*h*2)
+	for i := 0; i < w*h; i++ {
+		binary.BigEndian.PutUint16(plane[i*2:], 0x8040)
+	}
+	plane[1] = 0xFF // pierwszy piksel jaśniejszy (wymusza literał w PackBits)
+	plane[0] = 0xFF
+	data := testPSD{
+		psb: true, mode: psdModeGrayscale, depth: 16, width: w, height: h,
+		planes: [][]byte{plane}, compression: 1, lr16Count: 3,
+	}.bytes(t)
+
+	img, info, err := DecodePSD(bytes.NewReader(data))
+	assert.NoError(t, err)
+	assert.True(t, info.IsPSB)
+	assert.Equal(t, 16, info.Depth)
+	assert.Equal(t, 3, info.LayerCount, "16-bit documents keep layers in Lr16")
+	assert.False(t, info.HasAlpha)
+	assert.Equal(t, color.NRGBA{R: 0x80, G: 0x80, B: 0x80, A: 255}, img.At(5, 2))
+	assert.Equal(t, color.NRGBA{R: 0xFF, G: 0xFF, B: 0xFF, A: 255}, img.At(0, 0))
+}
+
+func TestDecodePSD_CMYK(t *testing.T) {
+	w, h := 2, 2
+	// CMYK jest zapisany odwrotnie: 255 = brak farby. Pełna magenta + żółty = czerwony.
+	data := testPSD{
+		mode: psdModeCMYK, depth: 8, width: w, height: h,
+		planes: [][]byte{filledPlane(w, h, 255), filledPlane(w, h, 0), filledPlane(w, h, 0), filledPlane(w, h, 255)},
+	}.bytes(t)
+
+	img, info, err := DecodePSD(bytes.NewReader(data))
+	assert.NoError(t, err)
+	assert.Equal(t, "CMYK", info.ColorMode)
+	assert.False(t, info.HasAlpha)
+	assert.Equal(t, color.NRGBA{R: 255, A: 255}, img.At(0, 0))
+}
+
+func TestDecodePSD_ThumbnailFallback(t *testing.T) {
+	w, h := 64, 32
+	planes := [][]byte{filledPlane(w, h, 255), filledPlane(w, h, 0), filledPlane(w, h, 0)}
+
+	t.Run("Unsupported composite compression", func(t *testing.T) {
+		data := testPSD{
+			mode: psdModeRGB, depth: 8, width: w, height: h, planes: planes,
+			compression: 2, resources: jpegThumbnailResource(t, color.RGBA{B: 255, A: 255}),
+		}.bytes(t)
+		img, info, err := DecodePSD(bytes.NewReader(data))
+		assert.NoError(t, err)
+		assert.Equal(t, 64, info.Width, "Dimensions come from the header, not from the thumbnail")
+		assert.Equal(t, 16, img.Bounds().Dx())
+		_, _, b, _ := img.At(8, 4).RGBA()
+		assert.Greater(t, b>>8, uint32(200))
+	})
+
+	t.Run("No real merged data", func(t *testing.T) {
+		versionInfo := psdResource(psdResourceVersionInfo, []byte{0, 0, 0, 1, 0})
+		resources := append(versionInfo, jpegThumbnailResource(t, color.RGBA{G: 255, A: 255})...)
+		data := testPSD{mode: psdModeRGB, depth: 8, width: w, height: h, planes: planes, resources: resources}.bytes(t)
+		img, _, err := DecodePSD(bytes.NewReader(data))
+		assert.NoError(t, err)
+		_, g, _, _ := img.At(8, 4).RGBA()
+		assert.Greater(t, g>>8, uint32(200), "The blank composite must not be used")
+	})
+
+	t.Run("Nothing to fall back to", func(t *testing.T) {
+		data := testPSD{mode: psdModeRGB, depth: 8, width: w, height: h, planes: planes, compression: 2}.bytes(t)
+		_, _, err := DecodePSD(bytes.NewReader(data))
+		assert.Error(t, err)
+	})
+}
+
+func TestDecodePSD_SubsamplesLargeDocuments(t *testing.T) {
+	w, h := 3000, 4
+	data := testPSD{mode: psdModeGrayscale, depth: 8, width: w, height: h, planes: [][]byte{filledPlane(w, h, 90)}}.bytes(t)
+
+	img, info, err := DecodePSD(bytes.NewReader(data))
+	assert.NoError(t, err)
+	assert.Equal(t, 3000, info.Width)
+	assert.Equal(t, image.Rect(0, 0, 1000, 2), img.Bounds())
+}
+
+func TestDecodePSD_InvalidInput(t *testing.T) {
+	_, _, err := DecodePSD(bytes.NewReader([]byte("GIF89a not a psd at all....")))
+	assert.Error(t, err)
+
+	data := testPSD{mode: psdModeRGB, depth: 8, width: 8, height: 8,
+		planes: [][]byte{filledPlane(8, 8, 1), filledPlane(8, 8, 2), filledPlane(8, 8, 3)}}.bytes(t)
+	_, _, err = DecodePSD(bytes.NewReader(data[:len(data)-10]))
+	assert.Error(t, err, "Truncated image data")
+
+	assert.Error(t, unpackBits([]byte{5, 1, 2}, make([]byte, 6)))
+}
+
+func TestDiskThumbnailGenerator_PSD(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "poster.psd")
+	w, h := 40, 20
+	data := testPSD{
+		mode: psdModeRGB, depth: 16, width: w, height: h,
+		planes:     [][]byte{filledPlane(w, h*2, 255), filledPlane(w, h*2, 0), filledPlane(w, h*2, 0)},
+		layerCount: 5,
+	}.bytes(t)
+	assert.NoError(t, os.WriteFile(path, data, 0644))
+
+	generator := NewDiskThumbnailGenerator(dir, slog.New(slog.NewTextHandler(io.Discard, nil)))
+	res, err := generator.Generate(context.Background(), path)
+	assert.NoError(t, err)
+	assert.False(t, res.IsPlaceholder)
+	assert.Equal(t, 40, res.Metadata.Width)
+	assert.Equal(t, 20, res.Metadata.Height)
+	assert.Equal(t, 16, res.Metadata.BitDepth)
+	assert.Equal(t, "5", res.Metadata.Properties[PropertyLayerCount])
+	assert.Equal(t, "#FF0000", res.Metadata.DominantColor)
+	assert.NotEmpty(t, res.Metadata.PerceptualHash)
+}
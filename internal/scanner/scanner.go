@@ -6,18 +6,21 @@ import (
 	"eclat/internal/config"
 	"eclat/internal/database"
 	"eclat/internal/feedback"
+	"encoding/json"
 	"fmt"
 	"io/fs"
 	"log/slog"
 	"os"
 	"path/filepath"
 	goRuntime "runtime"
+	"slices"
 	"strings"
 	"sync"
 	"sync/atomic"
 	"time"
 
 	"github.com/google/uuid"
+	"github.com/lucasb-eyer/go-colorful"
 )
 
 // Scanner is responsible for scanning the file system for assets,
@@ -41,6 +44,11 @@ type Scanner struct {
 	sessionMu             sync.Mutex
 	sessionCache          map[string]string
 	sessionHeuristicCache map[string]string
+
+	// pendingFolderIDs holds folders requested via StartScanFolders while another scan was running.
+	// They are scanned as soon as that scan finishes.
+	pendingMu        sync.Mutex
+	pendingFolderIDs []int64
 }
 
 // ScanJob represents a single file scanning task to be processed by a worker.
@@ -58,6 +66,13 @@ type ScanResult struct {
 	NewAsset      *database.CreateAssetParams
 	ModifiedAsset *database.UpdateAssetFromScanParams
 	ExistingPath  string
+	// Metadata carries image analysis stored outside the assets table (e.g. the color histogram).
+	Metadata ImageMetadata
+	// Skipped marks an unchanged file that was taken over from the previous scan without hashing.
+	Skipped bool
+	// Refreshed marks a modified asset whose content was analysed again, so Metadata replaces
+	// everything derived from the old content, even where it is empty.
+	Refreshed bool
 }
 
 // fileInfoEntry is an adapter that allows fs.FileInfo to satisfy the fs.DirEntry interface.
@@ -100,22 +115,52 @@ func NewScanner(conn *sql.DB, db database.Querier, thumbGen ThumbnailGenerator,
 // CachedAsset represents a minimal subset of asset data needed for synchronization
 // checks during the scanning process.
 type CachedAsset struct {
-	ID           int64
-	LastModified time.Time
-	IsDeleted    bool
-	ScanFolderID sql.NullInt64
-	FilePath     string
+	ID              int64
+	FileSize        int64
+	LastModified    time.Time
+	IsDeleted       bool
+	ScanFolderID    sql.NullInt64
+	FilePath        string
+	AnalysisVersion int64
 }
 
+// analysisVersion is stored with every asset the scanner analyses, whether or not a thumbnail,
+// perceptual hash or geometry could be read. Bump it when the scanner learns to read more from
+// files, so assets analysed by an older version are refreshed once.
+const analysisVersion = 1
+
 // StartScan initiates the background scanning process.
 // It checks all configured folders for new, modified, or deleted files.
 // The scan runs asynchronously and utilizes a worker pool for parallel processing.
 // If a scan is already in progress, this method returns nil immediately.
 func (s *Scanner) StartScan() error {
+	return s.startScan(nil)
+}
+
+// StartScanFolders scans only the given folders. The cleanup phase soft-deletes missing assets
+// of these folders only, the rest of the library is left untouched.
+// If a scan is already in progress, the folders are queued and scanned right after it.
+func (s *Scanner) StartScanFolders(ids []int64) error {
+	if len(ids) == 0 {
+		return nil
+	}
+	return s.startScan(ids)
+}
+
+// startScan runs a scan of the given folders, or of all folders when folderIDs is nil.
+func (s *Scanner) startScan(folderIDs []int64) error {
+	s.pendingMu.Lock()
 	if s.isScanning.Load() {
+		if folderIDs != nil {
+			s.logger.Info("Scan in progress, folders queued", "ids", folderIDs)
+			s.pendingFolderIDs = append(s.pendingFolderIDs, folderIDs...)
+		}
+		s.pendingMu.Unlock()
 		return nil
 	}
 	s.isScanning.Store(true)
+	s.pendingMu.Unlock()
+
 	scanCtx, cancel := context.WithCancel(context.Background())
 	s.cancelFunc = cancel
 
@@ -135,8 +180,8 @@ func (s *Scanner) StartScan() error {
 	s.logger.Info("Starting scanner", "workers", numWorkers)
 
 	go func() {
-		defer s.isScanning.Store(false)
 		defer cancel()
+		defer s.finishScan(scanCtx)
 		var folders []database.ScanFolder
 		var totalToProcess int = 0
 		var err error
@@ -155,6 +200,15 @@ func (s *Scanner) StartScan() error {
 			s.logger.Error("Failed to list folders", slog.String("error", err.Error()))
 			return
 		}
+		var selected map[int64]bool
+		if folderIDs != nil {
+			selected = make(map[int64]bool, len(folderIDs))
+			for _, id := range folderIDs {
+				selected[id] = true
+			}
+			folders = slices.DeleteFunc(folders, func(f database.ScanFolder) bool { return !selected[f.ID] })
+			s.logger.Info("Scanning selected folders", "requested", len(folderIDs), "found", len(folders))
+		}
 
 		// 2. Preparation: Count files to show accurate progress bar.
 		s.logger.Info("Calculating total files...")
@@ -167,7 +221,7 @@ func (s *Scanner) StartScan() error {
 		}
 		s.logger.Info("Total files to scan calculated", "count", totalToProcess)
 		s.notifier.SendScannerStatus(s.ctx, feedback.Scanning)
-		s.notifier.SendScanProgress(s.ctx, 0, totalToProcess, "Initializing...")
+		s.notifier.SendScanProgress(s.ctx, feedback.ScanProgressDTO{Total: totalToProcess, LastFile: "Initializing..."})
 
 		// 3. Start Collector: Runs in background to batch DB writes.
 		go func() {
@@ -180,7 +234,7 @@ func (s *Scanner) StartScan() error {
 		// 4. Start Workers: Parallel processing of files.
 		for i := 0; i < numWorkers; i++ {
 			workersWg.Add(1)
-			go s.Worker(scanCtx, &workersWg, jobs, results)
+			go s.Worker(scanCtx, &workersWg, existingAssets, jobs, results)
 		}
 
 		// 5. Feed Workers: Walk directories and send jobs.
@@ -223,13 +277,16 @@ func (s *Scanner) StartScan() error {
 
 		// 7. Mark-and-Sweep Cleanup:
 		// Any asset in DB (existingAssets) that was NOT found in the current scan (foundOnDisk)
-		// must be marked as deleted.
+		// must be marked as deleted. A folder scan only sweeps assets of the scanned folders.
 		s.logger.Info("Scan finished. Starting Cleanup phase...",
 			"db_cache_size", len(existingAssets),
 			"found_on_disk", len(foundOnDisk))
 
 		cleanupCount := 0
 		for path, cached := range existingAssets {
+			if selected != nil && !selected[cached.ScanFolderID.Int64] {
+				continue
+			}
 			if !foundOnDisk[cached.FilePath] {
 				if !cached.IsDeleted {
 					s.logger.Info("Asset missing or invalid extension - Soft Deleting", "path", path)
@@ -249,6 +306,28 @@ func (s *Scanner) StartScan() error {
 	return nil
 }
 
+// finishScan marks the scanner idle and starts the folder scans queued in the meantime.
+// Queued folders are dropped when the scan was stopped by the user.
+func (s *Scanner) finishScan(scanCtx context.Context) {
+	s.pendingMu.Lock()
+	pending := s.pendingFolderIDs
+	s.pendingFolderIDs = nil
+	s.isScanning.Store(false)
+	s.pendingMu.Unlock()
+
+	if len(pending) == 0 {
+		return
+	}
+	if scanCtx.Err() != nil {
+		s.logger.Info("Scan cancelled, dropping queued folders", "ids", pending)
+		return
+	}
+	s.logger.Info("Starting queued folder scan", "ids", pending)
+	if err := s.startScan(pending); err != nil {
+		s.logger.Error("Failed to start queued folder scan", "error", err)
+	}
+}
+
 // StopScan signals the current scan to cancel and stop.
 func (s *Scanner) StopScan() {
 	if s.cancelFunc != nil {
@@ -258,7 +337,8 @@ func (s *Scanner) StopScan() {
 
 // Worker consumes ScanJobs from the jobs channel, processes them, and sends the results to the results channel.
 // It handles extension filtering, hashing, database lookups, and decides whether to create a new asset or update an existing one.
-func (s *Scanner) Worker(ctx context.Context, wg *sync.WaitGroup, jobs <-chan ScanJob, results chan<- ScanResult) {
+// Files whose size and modification time match the existing snapshot are skipped without hashing.
+func (s *Scanner) Worker(ctx context.Context, wg *sync.WaitGroup, existing map[string]CachedAsset, jobs <-chan ScanJob, results chan<- ScanResult) {
 	defer wg.Done()
 
 	for job := range jobs {
@@ -270,6 +350,13 @@ func (s *Scanner) Worker(ctx context.Context, wg *sync.WaitGroup, jobs <-chan Sc
 			continue
 		}
 
+		// Fast path: nothing changed since the last scan, so there is no need to read the file.
+		if cached, ok := existing[job.Path]; ok && s.isUnchanged(cached, job) {
+			s.logger.Debug("Skipping unchanged file", "path", job.Path)
+			results <- ScanResult{Path: job.Path, ExistingPath: cached.FilePath, Skipped: true}
+			continue
+		}
+
 		s.logger.Debug("Processing file", "path", job.Path, "ext", ext)
 
 		// Calculate hash to detect duplicates or content changes.
@@ -332,7 +419,11 @@ func (s *Scanner) processNewAsset(ctx context.Context, result *ScanResult, job S
 	// Check if this file is a duplicate of something already in the DB.
 	if hash != "" {
 		existingDuplicate, err := s.db.GetAssetByHash(ctx, sql.NullString{String: hash, Valid: true})
-		if err == nil {
+		if err == nil && s.isGroupLocked(ctx, existingDuplicate.GroupID) {
+			s.logger.Debug("🔒 Exact Duplicate found in a locked group, not joining it",
+				"new_path", job.Path,
+				"group_id", existingDuplicate.GroupID)
+		} else if err == nil {
 			s.logger.Debug("🔗 Exact Duplicate found (DB)",
 				"new_path", job.Path,
 				"group_id", existingDuplicate.GroupID)
@@ -347,14 +438,16 @@ func (s *Scanner) processNewAsset(ctx context.Context, result *ScanResult, job S
 	// Check if another worker found this same file content in this current scan session.
 	if !foundMatch && hash != "" {
 		s.sessionMu.Lock()
-		if cachedGroupID, ok := s.sessionCache[hash]; ok {
+		cachedGroupID, ok := s.sessionCache[hash]
+		s.sessionMu.Unlock()
+		// Live scans keep the cache, so the group may have been locked since.
+		if ok && !s.isGroupLocked(ctx, cachedGroupID) {
 			s.logger.Debug("🔗 Exact Duplicate found (Session Cache)",
 				"new_path", job.Path,
 				"group_id", cachedGroupID)
 			targetGroupID = cachedGroupID
 			foundMatch = true
 		}
-		s.sessionMu.Unlock()
 	}
 
 	// === PLAN B: HEURISTIC MATCH (NAME) ===
@@ -375,6 +468,7 @@ func (s *Scanner) processNewAsset(ctx context.Context, result *ScanResult, job S
 	if !foundMatch {
 		s.logger.Debug("✨ No match found, creating new group", "path", job.Path)
 		targetGroupID = uuid.New().String()
+		baseName, cacheKey, cacheable := s.heuristicBaseName(job.FolderId, job.Entry.Name())
 
 		s.sessionMu.Lock()
 		// Cache by hash
@@ -384,22 +478,21 @@ func (s *Scanner) processNewAsset(ctx context.Context, result *ScanResult, job S
 		}
 
 		// Cache by heuristic (base name)
-		baseName := s.getBaseName(job.Entry.Name())
-		if len(baseName) >= 3 {
-			cacheKey := fmt.Sprintf("%d:%s", job.FolderId, baseName)
+		if cacheable {
 			s.sessionHeuristicCache[cacheKey] = targetGroupID
 			s.logger.Debug("✨ Cached new group ID for base name", "base", baseName, "group_id", targetGroupID)
 		}
 		s.sessionMu.Unlock()
 	}
 
-	newAsset, err := s.generateAssetMetadata(ctx, job.Path, job.Entry, job.FolderId, fileType, hash, targetGroupID)
+	newAsset, imgMeta, err := s.generateAssetMetadata(ctx, job.Path, job.Entry, job.FolderId, fileType, hash, targetGroupID)
 	if err != nil {
 		s.logger.Error("Critical failure generating metadata for new asset", "path", job.Path, "error", err)
 		result.Err = err
 		return
 	}
 	result.NewAsset = &newAsset
+	result.Metadata = imgMeta
 }
 
 // processExistingAsset handles the logic for a file that is already present in the database.
@@ -423,12 +516,16 @@ func (s *Scanner) processExistingAsset(ctx context.Context, result *ScanResult,
 			dbTime := exist.LastModified.Unix()
 			diskTime := info.ModTime().Unix()
 
-			// If timestamp changed or we are resurrecting, we regenerate metadata.
-			if dbTime != diskTime || isResurrected {
+			// Assets analysed by an older scanner get their metadata refreshed once.
+			outdated := exist.AnalysisVersion < analysisVersion
+
+			// If timestamp or size changed or we are resurrecting, we regenerate metadata.
+			if dbTime != diskTime || exist.FileSize != info.Size() || isResurrected || outdated {
 				s.logger.Info("📝 File Content Changed or Resurrected: Refreshing metadata", "path", job.Path)
 
-				meta, err := s.generateAssetMetadata(ctx, job.Path, job.Entry, job.FolderId, fileType, hash, exist.GroupID)
+				meta, imgMeta, err := s.generateAssetMetadata(ctx, job.Path, job.Entry, job.FolderId, fileType, hash, exist.GroupID)
 				if err == nil {
+					result.Metadata = imgMeta
 					modifiedAsset := &database.UpdateAssetFromScanParams{
 						ID:              exist.ID,
 						IsDeleted:       sql.NullBool{Bool: false, Valid: true},
@@ -442,8 +539,11 @@ func (s *Scanner) processExistingAsset(ctx context.Context, result *ScanResult,
 						DominantColor:   meta.DominantColor,
 						BitDepth:        meta.BitDepth,
 						HasAlphaChannel: meta.HasAlphaChannel,
+						MaterialChannel: sql.NullString{String: meta.MaterialChannel, Valid: true},
+						AnalysisVersion: sql.NullInt64{Int64: meta.AnalysisVersion, Valid: true},
 					}
 					result.ModifiedAsset = modifiedAsset
+					result.Refreshed = true
 				} else {
 					s.logger.Error("Failed to regenerate metadata for existing asset", "path", job.Path, "error", err)
 					// Fallback: just un-delete if it was resurrected
@@ -501,7 +601,7 @@ func (s *Scanner) Collector(ctx context.Context, totalToProcess int, results <-c
 	buff := make([]ScanResult, 0, batchSize)
 	processed := make(map[string]bool)
 
-	var totalProcessed int
+	progress := feedback.ScanProgressDTO{Total: totalToProcess}
 
 	// Helper to flush current buffer to DB
 	flush := func() {
@@ -532,9 +632,13 @@ func (s *Scanner) Collector(ctx context.Context, totalToProcess int, results <-c
 		if len(buff) >= batchSize {
 			flush()
 		}
-		s.updateAndEmitTotal(&totalProcessed, totalToProcess, emitAfter)
+		s.updateAndEmitTotal(&progress, result, emitAfter)
 	}
 	flush() // Flush remaining items
+
+	// Final event so the UI ends up with the exact skipped/processed split.
+	s.notifier.SendScanProgress(s.ctx, progress)
+	s.logger.Info("Scan results collected", "processed", progress.Processed, "skipped", progress.Skipped)
 	return processed
 }
 
@@ -553,18 +657,32 @@ func (s *Scanner) ApplyBatch(ctx context.Context, buffer []ScanResult) error {
 
 	for _, item := range buffer {
 		if item.NewAsset != nil {
-			_, err := qtx.CreateAsset(ctx, *item.NewAsset)
+			created, err := qtx.CreateAsset(ctx, *item.NewAsset)
 			if err != nil {
 				s.logger.Error("Failed to insert asset", "path", item.Path, "error", err)
 				continue
 			}
+			s.indexForSearch(ctx, qtx, created.ID)
+			s.storeImageFeatures(ctx, qtx, created.ID, item.Metadata, false)
 		}
 		if item.ModifiedAsset != nil {
-			_, err := qtx.UpdateAssetFromScan(ctx, *item.ModifiedAsset)
+			updated, err := qtx.UpdateAssetFromScan(ctx, *item.ModifiedAsset)
 			if err != nil {
 				s.logger.Error("Failed to update asset", "path", item.Path, "error", err)
 				continue
 			}
+			if item.Refreshed {
+				// The hash describes the content, so a file that no longer yields one loses it.
+				err := qtx.UpdateAssetPerceptualHash(ctx, database.UpdateAssetPerceptualHashParams{
+					PerceptualHash: sql.NullString{String: item.Metadata.PerceptualHash, Valid: item.Metadata.PerceptualHash != ""},
+					ID:             updated.ID,
+				})
+				if err != nil {
+					s.logger.Warn("Failed to store perceptual hash", "id", updated.ID, "error", err)
+				}
+			}
+			s.indexForSearch(ctx, qtx, updated.ID)
+			s.storeImageFeatures(ctx, qtx, updated.ID, item.Metadata, item.Refreshed)
 		}
 	}
 
@@ -582,9 +700,127 @@ func (s *Scanner) ApplyBatch(ctx context.Context, buffer []ScanResult) error {
 	return nil
 }
 
+// indexForSearch refreshes the full-text search entry of a single asset.
+// Failures are logged only, because a stale search entry must not block the scan.
+func (s *Scanner) indexForSearch(ctx context.Context, q database.Querier, assetID int64) {
+	if err := database.ReindexAssetSearch(ctx, q, assetID); err != nil {
+		s.logger.Warn("Failed to refresh search index entry", "id", assetID, "error", err)
+	}
+}
+
+// storeImageFeatures saves the analysis kept outside the assets table: the feature vector used
+// by "find similar", the palette, format properties and model geometry. Without refreshed
+// (e.g. moves) missing parts keep whatever was stored before; with it they are replaced even
+// when the new content has none, so searches and filters stop matching the old content.
+func (s *Scanner) storeImageFeatures(ctx context.Context, q database.Querier, assetID int64, meta ImageMetadata, refreshed bool) {
+	if len(meta.ColorHistogram) > 0 {
+		err := q.UpsertAssetFeatures(ctx, database.UpsertAssetFeaturesParams{
+			AssetID:        assetID,
+			ColorHistogram: meta.ColorHistogram,
+		})
+		if err != nil {
+			s.logger.Warn("Failed to store image features", "id", assetID, "error", err)
+		}
+	} else if refreshed {
+		if err := q.DeleteAssetFeatures(ctx, assetID); err != nil {
+			s.logger.Warn("Failed to clear image features", "id", assetID, "error", err)
+		}
+	}
+
+	if len(meta.Colors) > 0 || refreshed {
+		if err := storeAssetColors(ctx, q, assetID, meta.Colors); err != nil {
+			s.logger.Warn("Failed to store asset colors", "id", assetID, "error", err)
+		}
+	}
+
+	if meta.Properties != nil || refreshed {
+		if err := storeAssetProperties(ctx, q, assetID, meta.Properties); err != nil {
+			s.logger.Warn("Failed to store asset properties", "id", assetID, "error", err)
+		}
+	}
+
+	if meta.Model != nil {
+		if err := storeModelMetadata(ctx, q, assetID, *meta.Model); err != nil {
+			s.logger.Warn("Failed to store model metadata", "id", assetID, "error", err)
+		}
+	} else if refreshed {
+		if err := q.DeleteModelMetadata(ctx, assetID); err != nil {
+			s.logger.Warn("Failed to clear model metadata", "id", assetID, "error", err)
+		}
+	}
+}
+
+// storeModelMetadata saves the geometry statistics of a 3D model.
+func storeModelMetadata(ctx context.Context, q database.Querier, assetID int64, model ModelMetadata) error {
+	textures := model.Textures
+	if textures == nil {
+		textures = []string{}
+	}
+	textureFiles, err := json.Marshal(textures)
+	if err != nil {
+		return err
+	}
+	return q.UpsertModelMetadata(ctx, database.UpsertModelMetadataParams{
+		AssetID:       assetID,
+		VertexCount:   model.VertexCount,
+		TriangleCount: model.TriangleCount,
+		MeshCount:     int64(model.MeshCount),
+		MaterialCount: int64(model.MaterialCount),
+		TextureCount:  int64(model.TextureCount),
+		TextureFiles:  string(textureFiles),
+		SizeX:         model.Size[0],
+		SizeY:         model.Size[1],
+		SizeZ:         model.Size[2],
+	})
+}
+
+// storeAssetProperties replaces the format specific properties of an asset.
+func storeAssetProperties(ctx context.Context, q database.Querier, assetID int64, props map[string]string) error {
+	if err := q.DeleteAssetProperties(ctx, assetID); err != nil {
+		return err
+	}
+	for key, value := range props {
+		err := q.InsertAssetProperty(ctx, database.InsertAssetPropertyParams{AssetID: assetID, Key: key, Value: value})
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// storeAssetColors replaces the palette of an asset.
+func storeAssetColors(ctx context.Context, q database.Querier, assetID int64, colors []WeightedColor) error {
+	if err := q.DeleteAssetColors(ctx, assetID); err != nil {
+		return err
+	}
+	for i, c := range colors {
+		parsed, err := colorful.Hex(c.Hex)
+		if err != nil {
+			return fmt.Errorf("invalid color %q: %w", c.Hex, err)
+		}
+		l, a, b := parsed.Lab()
+		err = q.InsertAssetColor(ctx, database.InsertAssetColorParams{
+			AssetID:     assetID,
+			Position:    int64(i),
+			Hex:         c.Hex,
+			PaletteName: c.PaletteName,
+			PaletteHex:  c.PaletteHex,
+			Weight:      c.Weight,
+			LabL:        l,
+			LabA:        a,
+			LabB:        b,
+		})
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
 // generateAssetMetadata creates the necessary metadata parameters for a new or updated asset.
-// It generates a thumbnail and extracts file information.
-func (s *Scanner) generateAssetMetadata(ctx context.Context, path string, entry fs.DirEntry, folderId int64, filetype string, hash string, targetGroupID string) (database.CreateAssetParams, error) {
+// It generates a thumbnail and extracts file information. The returned ImageMetadata carries
+// the analysis results that are not part of the assets row.
+func (s *Scanner) generateAssetMetadata(ctx context.Context, path string, entry fs.DirEntry, folderId int64, filetype string, hash string, targetGroupID string) (database.CreateAssetParams, ImageMetadata, error) {
 	thumb, err := s.thumbGen.Generate(ctx, path)
 	if err != nil {
 		s.logger.Warn("Failed to generate thumbnail, proceeding without it", "path", path, "error", err)
@@ -596,7 +832,22 @@ func (s *Scanner) generateAssetMetadata(ctx context.Context, path string, entry
 	info, err := os.Stat(path)
 	if err != nil {
 		s.logger.Debug("Failed to get file info", "path", path, "error", err)
-		return database.CreateAssetParams{}, err
+		return database.CreateAssetParams{}, ImageMetadata{}, err
+	}
+
+	// Colors are matched against the user's active palette, not the generator's built-in one.
+	if len(thumb.Metadata.Colors) > 0 {
+		thumb.Metadata.Colors = SnapToPalette(thumb.Metadata.Colors, s.config.GetColorPalette())
+		thumb.Metadata.DominantColor = thumb.Metadata.Colors[0].PaletteHex
+	}
+
+	// Models are parsed here rather than by the thumbnail generator so external providers don't lose them.
+	if isModelMetadataExt(strings.ToLower(filepath.Ext(path))) {
+		if model, err := ReadModelMetadata(path); err != nil {
+			s.logger.Debug("Failed to read model metadata", "path", path, "error", err)
+		} else {
+			thumb.Metadata.Model = &model
+		}
 	}
 
 	hasValidDimensions := thumb.Metadata.Width > 0 && thumb.Metadata.Height > 0
@@ -614,11 +865,14 @@ func (s *Scanner) generateAssetMetadata(ctx context.Context, path string, entry
 		DominantColor:   sql.NullString{String: string(thumb.Metadata.DominantColor), Valid: thumb.Metadata.DominantColor != ""},
 		BitDepth:        sql.NullInt64{Int64: int64(thumb.Metadata.BitDepth), Valid: hasValidDimensions},
 		HasAlphaChannel: sql.NullBool{Bool: thumb.Metadata.HasAlphaChannel, Valid: hasValidDimensions},
+		PerceptualHash:  sql.NullString{String: thumb.Metadata.PerceptualHash, Valid: thumb.Metadata.PerceptualHash != ""},
+		MaterialChannel: s.materialChannel(folderId, entry.Name()),
+		AnalysisVersion: analysisVersion,
 		LastModified:    info.ModTime(),
 		LastScanned:     time.Now(),
 	}
 
-	return newAsset, nil
+	return newAsset, thumb.Metadata, nil
 }
 
 // scanDirectory recursively walks a directory, creating ScanJobs for allowed files.
@@ -733,7 +987,12 @@ func (s *Scanner) ScanFile(ctx context.Context, path string) error {
 
 // resolveFolderID matches a file path to the specific ScanFolder ID it belongs to.
 func (s *Scanner) resolveFolderID(ctx context.Context, filePath string) (int64, error) {
-	folders, err := s.db.ListScanFolders(ctx)
+	return ResolveFolderID(ctx, s.db, filePath)
+}
+
+// ResolveFolderID returns the ID of the innermost scan folder containing the path.
+func ResolveFolderID(ctx context.Context, db database.Querier, filePath string) (int64, error) {
+	folders, err := db.ListScanFolders(ctx)
 	if err != nil {
 		return 0, err
 	}
@@ -814,21 +1073,46 @@ func (s *Scanner) loadExistingAssets(ctx context.Context) (map[string]CachedAsse
 
 	for _, row := range rows {
 		existing[row.FilePath] = CachedAsset{
-			ID:           row.ID,
-			LastModified: row.LastModified,
-			IsDeleted:    row.IsDeleted,
-			ScanFolderID: row.ScanFolderID,
-			FilePath:     row.FilePath,
+			ID:              row.ID,
+			FileSize:        row.FileSize,
+			LastModified:    row.LastModified,
+			IsDeleted:       row.IsDeleted,
+			ScanFolderID:    row.ScanFolderID,
+			FilePath:        row.FilePath,
+			AnalysisVersion: row.AnalysisVersion,
 		}
 	}
 	s.logger.Info("Loaded assets cache", "count", len(existing))
 	return existing, nil
 }
 
-// updateAndEmitTotal emits a progress event via the notifier every `emitAfter` items.
-func (s *Scanner) updateAndEmitTotal(total *int, totalToProcess, emitAfter int) {
-	*total++
-	if *total%emitAfter == 0 {
-		s.notifier.SendScanProgress(s.ctx, *total, totalToProcess, "")
+// isUnchanged reports whether a file can be skipped without hashing: its asset is live in the same
+// scan folder, was analysed by the current scanner, and neither the size nor the modification
+// time changed since the last scan.
+func (s *Scanner) isUnchanged(cached CachedAsset, job ScanJob) bool {
+	if cached.IsDeleted || cached.ScanFolderID.Int64 != job.FolderId || job.Entry == nil {
+		return false
+	}
+	if cached.AnalysisVersion < analysisVersion {
+		return false
+	}
+	info, err := job.Entry.Info()
+	if err != nil {
+		return false
+	}
+	return info.Size() == cached.FileSize && info.ModTime().Unix() == cached.LastModified.Unix()
+}
+
+// updateAndEmitTotal counts a collected result and emits a progress event via the notifier every `emitAfter` items.
+func (s *Scanner) updateAndEmitTotal(progress *feedback.ScanProgressDTO, result ScanResult, emitAfter int) {
+	progress.Current++
+	if result.Skipped {
+		progress.Skipped++
+	} else {
+		progress.Processed++
+	}
+	progress.LastFile = result.Path
+	if progress.Current%emitAfter == 0 {
+		s.notifier.SendScanProgress(s.ctx, *progress)
 	}
 }
@@ -3,13 +3,17 @@ package scanner
 import (
 	"context"
 	"database/sql"
+	"eclat/internal/config"
 	"eclat/internal/database"
+	"io"
+	"log/slog"
 	"os"
 	"path/filepath"
 	"testing"
 	"time"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 // 1. TEST: ZOMBIE RESURRECTION 🧟
@@ -321,6 +325,42 @@ func TestIntegration_LiveScan_Heuristic(t *testing.T) {
 	assert.Equal(t, groupID, v2Asset.GroupID, "Plik v2 powinien odziedziczyć GroupID od v1 dzięki heurystyce nazwy")
 }
 
+// TestIntegration_LiveScan_MaterialChannels sprawdza grupowanie map PBR jednego materiału.
+func TestIntegration_LiveScan_MaterialChannels(t *testing.T) {
+	_, queries, scanner, root := setupLogicTest(t)
+	ctx := context.Background()
+
+	expected := map[string]string{
+		"rock_albedo.png":       config.ChannelBaseColor,
+		"Rock_Normal.png":       config.ChannelNormal,
+		"rock_roughness_v2.png": config.ChannelRoughness,
+		"rock_ao.png":           config.ChannelAO,
+		"rock.png":              "",
+	}
+	var groupID string
+	for _, name := range []string{"rock_albedo.png", "Rock_Normal.png", "rock_roughness_v2.png", "rock_ao.png", "rock.png"} {
+		path := filepath.Join(root, name)
+		createContentFile(t, path, "pixels of "+name)
+		require.NoError(t, scanner.ScanFile(ctx, path))
+
+		asset, err := queries.GetAssetByPath(ctx, path)
+		require.NoError(t, err)
+		assert.Equal(t, expected[name], asset.MaterialChannel, name)
+		if groupID == "" {
+			groupID = asset.GroupID
+		}
+		assert.Equal(t, groupID, asset.GroupID, "%s powinien trafić do grupy materiału", name)
+	}
+
+	// Inny materiał w tym samym folderze dostaje własną grupę
+	otherPath := filepath.Join(root, "bark_normal.png")
+	createContentFile(t, otherPath, "bark pixels")
+	require.NoError(t, scanner.ScanFile(ctx, otherPath))
+	other, err := queries.GetAssetByPath(ctx, otherPath)
+	require.NoError(t, err)
+	assert.NotEqual(t, groupID, other.GroupID)
+}
+
 func TestScanner_Logic_NonImageFiles(t *testing.T) {
 	_, queries, scanner, root := setupLogicTest(t)
 	ctx := context.Background()
@@ -386,3 +426,285 @@ func TestScanner_Logic_UnknownAllowedExtension(t *testing.T) {
 	assert.Equal(t, "other", asset.FileType)
 	assert.Contains(t, asset.ThumbnailPath, "generic_placeholder.webp")
 }
+
+// Sprawdza, czy ApplyBatch dodaje nowe assety do indeksu pełnotekstowego (assets_fts).
+func TestScanner_ApplyBatch_IndexesForSearch(t *testing.T) {
+	conn, queries, scanner, root := setupLogicTest(t)
+	ctx := context.Background()
+
+	path := filepath.Join(root, "rock_albedo.png")
+	createDummyFile(t, path)
+
+	err := scanner.ScanFile(ctx, path)
+	assert.NoError(t, err)
+
+	asset, err := queries.GetAssetByPath(ctx, path)
+	assert.NoError(t, err)
+
+	var indexedID int64
+	err = conn.QueryRowContext(ctx, `SELECT rowid FROM assets_fts WHERE assets_fts MATCH '"albedo"'`).Scan(&indexedID)
+	assert.NoError(t, err)
+	assert.Equal(t, asset.ID, indexedID)
+}
+
+func TestScanner_StoreAssetColors_ReplacesPalette(t *testing.T) {
+	_, queries, scanner, root := setupLogicTest(t)
+	ctx := context.Background()
+
+	path := filepath.Join(root, "brick.png")
+	createDummyFile(t, path)
+	assert.NoError(t, scanner.ScanFile(ctx, path))
+	asset, err := queries.GetAssetByPath(ctx, path)
+	assert.NoError(t, err)
+
+	first := []WeightedColor{
+		{Hex: "#E01010", PaletteName: "Red", PaletteHex: "#FF0000", Weight: 0.6},
+		{Hex: "#8A4A20", PaletteName: "Saddle Brown", PaletteHex: "#8B4513", Weight: 0.4},
+	}
+	assert.NoError(t, storeAssetColors(ctx, queries, asset.ID, first))
+
+	// Ponowny skan nadpisuje całą paletę
+	second := []WeightedColor{{Hex: "#303030", PaletteName: "Dark Gray", PaletteHex: "#404040", Weight: 1}}
+	assert.NoError(t, storeAssetColors(ctx, queries, asset.ID, second))
+
+	colors, err := queries.ListAssetColors(ctx, asset.ID)
+	assert.NoError(t, err)
+	if assert.Len(t, colors, 1) {
+		assert.Equal(t, "#404040", colors[0].PaletteHex)
+		assert.InDelta(t, 0.19, colors[0].LabL, 0.01, "Lab is stored in the go-colorful scale")
+	}
+}
+
+// Sprawdza, czy ponowny skan pomija niezmienione pliki (rozmiar + mtime) bez hashowania.
+func TestScanner_Logic_IncrementalScan(t *testing.T) {
+	_, queries, scanner, root := setupLogicTest(t)
+	ctx := context.Background()
+	notifier := scanner.notifier.(*MockNotifier)
+
+	scanAndWait := func() {
+		assert.NoError(t, scanner.StartScan())
+		assert.Eventually(t, func() bool { return !scanner.isScanning.Load() }, 2*time.Second, 20*time.Millisecond)
+	}
+
+	unchangedPath := filepath.Join(root, "unchanged.txt")
+	changedPath := filepath.Join(root, "changed.txt")
+	createDummyFile(t, unchangedPath)
+	createDummyFile(t, changedPath)
+
+	scanAndWait()
+	assert.Equal(t, 2, notifier.LastProgress.Processed)
+	assert.Equal(t, 0, notifier.LastProgress.Skipped)
+
+	// Zmieniamy tylko rozmiar - mtime zostaje taki sam jak w bazie
+	info, err := os.Stat(changedPath)
+	assert.NoError(t, err)
+	createContentFile(t, changedPath, "zupełnie inna, dłuższa treść")
+	assert.NoError(t, os.Chtimes(changedPath, info.ModTime(), info.ModTime()))
+
+	scanAndWait()
+	assert.Equal(t, 2, notifier.LastProgress.Current)
+	assert.Equal(t, 1, notifier.LastProgress.Processed)
+	assert.Equal(t, 1, notifier.LastProgress.Skipped)
+
+	changed, err := queries.GetAssetByPath(ctx, changedPath)
+	assert.NoError(t, err)
+	newHash, _ := CalculateFileHash(changedPath, 0)
+	assert.Equal(t, newHash, changed.FileHash.String, "Changed file is re-hashed")
+
+	unchanged, err := queries.GetAssetByPath(ctx, unchangedPath)
+	assert.NoError(t, err)
+	assert.False(t, unchanged.IsDeleted, "Skipped files are not swept by the cleanup")
+}
+
+// Sprawdza, czy plik, którego nie da się zdekodować, jest analizowany raz, a nie przy każdym skanie.
+func TestScanner_Logic_IncrementalScan_UndecodableImage(t *testing.T) {
+	_, queries, scanner, root := setupLogicTest(t)
+	ctx := context.Background()
+	notifier := scanner.notifier.(*MockNotifier)
+	scanner.thumbGen = NewDiskThumbnailGenerator(t.TempDir(), slog.New(slog.NewTextHandler(io.Discard, nil)))
+
+	scanAndWait := func() {
+		assert.NoError(t, scanner.StartScan())
+		assert.Eventually(t, func() bool { return !scanner.isScanning.Load() }, 2*time.Second, 20*time.Millisecond)
+	}
+
+	// Ucięty PNG - sama sygnatura, bez pikseli i bez hasha percepcyjnego
+	path := filepath.Join(root, "broken.png")
+	createContentFile(t, path, "\x89PNG\r\n\x1a\n")
+
+	scanAndWait()
+	assert.Equal(t, 1, notifier.LastProgress.Processed)
+	broken, err := queries.GetAssetByPath(ctx, path)
+	require.NoError(t, err)
+	assert.False(t, broken.PerceptualHash.Valid)
+
+	scanAndWait()
+	assert.Equal(t, 0, notifier.LastProgress.Processed)
+	assert.Equal(t, 1, notifier.LastProgress.Skipped, "Nieudana analiza też się liczy")
+
+	// Zmiana pliku wymusza ponowną analizę
+	createContentFile(t, path, "\x89PNG\r\n\x1a\n nadal uszkodzony")
+	scanAndWait()
+	assert.Equal(t, 1, notifier.LastProgress.Processed)
+}
+
+// Sprawdza, czy skan wybranych folderów nie rusza (ani nie sprząta) pozostałych folderów.
+func TestScanner_StartScanFolders_ScopedCleanup(t *testing.T) {
+	_, queries, scanner, root := setupLogicTest(t)
+	ctx := context.Background()
+
+	other := t.TempDir()
+	otherFolder, err := queries.CreateScanFolder(ctx, other)
+	assert.NoError(t, err)
+
+	// Folder 1: nowy plik na dysku + "duch" w bazie
+	newPath := filepath.Join(root, "new.txt")
+	createDummyFile(t, newPath)
+	ghost := insertTestAsset(t, queries, 1, filepath.Join(root, "ghost.txt"), "ghost_hash")
+
+	// Folder 2: "duch" i nieprzeskanowany plik - skan folderu 1 nie może ich dotknąć
+	otherGhost := insertTestAsset(t, queries, otherFolder.ID, filepath.Join(other, "ghost.txt"), "other_hash")
+	otherNew := filepath.Join(other, "not_imported.txt")
+	createContentFile(t, otherNew, "inna treść")
+
+	assert.NoError(t, scanner.StartScanFolders([]int64{1}))
+	assert.Eventually(t, func() bool { return !scanner.isScanning.Load() }, 2*time.Second, 20*time.Millisecond)
+
+	_, err = queries.GetAssetByPath(ctx, newPath)
+	assert.NoError(t, err, "Plik z wybranego folderu powinien zostać zaimportowany")
+	a, _ := queries.GetAssetById(ctx, ghost.ID)
+	assert.True(t, a.IsDeleted)
+
+	a, _ = queries.GetAssetById(ctx, otherGhost.ID)
+	assert.False(t, a.IsDeleted, "Cleanup nie może wyjść poza wybrane foldery")
+	_, err = queries.GetAssetByPath(ctx, otherNew)
+	assert.ErrorIs(t, err, sql.ErrNoRows)
+}
+
+// Sprawdza, czy folder zlecony w trakcie innego skanu jest skanowany zaraz po nim.
+func TestScanner_StartScanFolders_QueuedWhileScanning(t *testing.T) {
+	_, queries, scanner, _ := setupLogicTest(t)
+	ctx := context.Background()
+
+	other := t.TempDir()
+	otherFolder, err := queries.CreateScanFolder(ctx, other)
+	assert.NoError(t, err)
+	path := filepath.Join(other, "late.txt")
+	createDummyFile(t, path)
+
+	scanner.isScanning.Store(true) // symulujemy trwający skan
+	assert.NoError(t, scanner.StartScanFolders([]int64{otherFolder.ID}))
+	assert.Equal(t, []int64{otherFolder.ID}, scanner.pendingFolderIDs)
+
+	scanner.finishScan(ctx)
+	assert.Eventually(t, func() bool {
+		_, err := queries.GetAssetByPath(ctx, path)
+		return err == nil && !scanner.isScanning.Load()
+	}, 2*time.Second, 20*time.Millisecond)
+}
+
+func TestScanner_StoreAssetProperties_ReplacesAll(t *testing.T) {
+	_, queries, scanner, root := setupLogicTest(t)
+	ctx := context.Background()
+
+	path := filepath.Join(root, "poster.png")
+	createDummyFile(t, path)
+	assert.NoError(t, scanner.ScanFile(ctx, path))
+	asset, err := queries.GetAssetByPath(ctx, path)
+	assert.NoError(t, err)
+
+	scanner.storeImageFeatures(ctx, queries, asset.ID, ImageMetadata{Properties: map[string]string{PropertyColorMode: "CMYK", PropertyLayerCount: "12"}}, false)
+	scanner.storeImageFeatures(ctx, queries, asset.ID, ImageMetadata{Properties: map[string]string{PropertyColorMode: "RGB"}}, false)
+	// Brak Properties (np. przeniesienie pliku) zostawia zapisane właściwości
+	scanner.storeImageFeatures(ctx, queries, asset.ID, ImageMetadata{}, false)
+
+	props, err := queries.ListAssetProperties(ctx, asset.ID)
+	assert.NoError(t, err)
+	if assert.Len(t, props, 1) {
+		assert.Equal(t, PropertyColorMode, props[0].Key)
+		assert.Equal(t, "RGB", props[0].Value)
+	}
+}
+
+func TestScanner_ScanFile_StoresModelMetadata(t *testing.T) {
+	_, queries, scanner, root := setupLogicTest(t)
+	ctx := context.Background()
+	scanner.config.SetAllowedExtensions([]string{".obj"})
+
+	path := filepath.Join(root, "crate.obj")
+	createContentFile(t, path, "mtllib crate.mtl\no Crate\nv 0 0 0\nv 2 0 0\nv 2 1 0\nv 0 1 3\nusemtl wood\nf 1 2 3 4\n")
+	createContentFile(t, filepath.Join(root, "crate.mtl"), "newmtl wood\nmap_Kd wood_albedo.png\n")
+	assert.NoError(t, scanner.ScanFile(ctx, path))
+
+	asset, err := queries.GetAssetByPath(ctx, path)
+	require.NoError(t, err)
+	model, err := queries.GetModelMetadata(ctx, asset.ID)
+	require.NoError(t, err)
+	assert.Equal(t, int64(4), model.VertexCount)
+	assert.Equal(t, int64(2), model.TriangleCount)
+	assert.Equal(t, int64(1), model.TextureCount)
+	assert.Equal(t, `["wood_albedo.png"]`, model.TextureFiles)
+	assert.Equal(t, []float64{2, 1, 3}, []float64{model.SizeX, model.SizeY, model.SizeZ})
+
+	// Modele przeanalizowane starszą wersją skanera (przed odczytem geometrii) dostają ją przy następnym skanie
+	_, err = scanner.conn.Exec("DELETE FROM model_metadata WHERE asset_id = ?", asset.ID)
+	require.NoError(t, err)
+	_, err = scanner.conn.Exec("UPDATE assets SET analysis_version = 0 WHERE id = ?", asset.ID)
+	require.NoError(t, err)
+	assert.NoError(t, scanner.StartScan())
+	assert.Eventually(t, func() bool {
+		_, err := queries.GetModelMetadata(ctx, asset.ID)
+		return err == nil && !scanner.isScanning.Load()
+	}, 2*time.Second, 20*time.Millisecond)
+}
+
+// Sprawdza, czy zmieniony plik, który nie daje już analizy (np. placeholder), nie zostawia danych starej zawartości.
+func TestScanner_Logic_RefreshClearsStaleAnalysis(t *testing.T) {
+	_, queries, scanner, root := setupLogicTest(t)
+	ctx := context.Background()
+	thumbGen := scanner.thumbGen.(*MockThumbnailGenerator)
+	thumbGen.Metadata = &ImageMetadata{
+		Width:          512,
+		Height:         512,
+		Colors:         []WeightedColor{{Hex: "#FF0000", PaletteName: "Red", PaletteHex: "#FF0000", Weight: 1}},
+		ColorHistogram: make([]byte, ColorHistogramSize),
+		PerceptualHash: "f0f0f0f0f0f0f0f0",
+		Properties:     map[string]string{PropertyColorMode: "RGB"},
+		Model:          &ModelMetadata{VertexCount: 8, TriangleCount: 12},
+	}
+
+	path := filepath.Join(root, "rock.png")
+	createContentFile(t, path, "old rock")
+	require.NoError(t, scanner.ScanFile(ctx, path))
+	asset, err := queries.GetAssetByPath(ctx, path)
+	require.NoError(t, err)
+	require.Equal(t, "f0f0f0f0f0f0f0f0", asset.PerceptualHash.String)
+	colors, err := queries.ListAssetColors(ctx, asset.ID)
+	require.NoError(t, err)
+	require.Len(t, colors, 1)
+	_, err = queries.GetAssetFeatures(ctx, asset.ID)
+	require.NoError(t, err)
+	_, err = queries.GetModelMetadata(ctx, asset.ID)
+	require.NoError(t, err)
+
+	// Nowa zawartość nie daje się zdekodować - generator zwraca placeholder bez analizy
+	thumbGen.ShouldFail = true
+	createContentFile(t, path, "new rock, broken")
+	later := time.Now().Add(time.Minute)
+	require.NoError(t, os.Chtimes(path, later, later))
+	require.NoError(t, scanner.ScanFile(ctx, path))
+
+	colors, err = queries.ListAssetColors(ctx, asset.ID)
+	require.NoError(t, err)
+	assert.Empty(t, colors, "Kolory starej zawartości są usuwane")
+	_, err = queries.GetAssetFeatures(ctx, asset.ID)
+	assert.ErrorIs(t, err, sql.ErrNoRows, "Wektor cech starej zawartości jest usuwany")
+	refreshed, err := queries.GetAssetByPath(ctx, path)
+	require.NoError(t, err)
+	assert.False(t, refreshed.PerceptualHash.Valid, "Hash percepcyjny starej zawartości jest usuwany")
+	props, err := queries.ListAssetProperties(ctx, asset.ID)
+	require.NoError(t, err)
+	assert.Empty(t, props, "Właściwości starej zawartości są usuwane")
+	_, err = queries.GetModelMetadata(ctx, asset.ID)
+	assert.ErrorIs(t, err, sql.ErrNoRows, "Geometria starej zawartości jest usuwana")
+}
@@ -20,9 +20,10 @@ import (
 
 // MockNotifier captures notifications for testing assertions.
 type MockNotifier struct {
-	LastMsg   feedback.ToastField // Stores the last sent toast message
-	CallCount int
-	LastEvent string
+	LastMsg      feedback.ToastField // Stores the last sent toast message
+	CallCount    int
+	LastEvent    string
+	LastProgress feedback.ScanProgressDTO
 }
 
 func (m *MockNotifier) SendToast(ctx context.Context, msg feedback.ToastField) {
@@ -35,8 +36,14 @@ func (m *MockNotifier) SendScannerStatus(ctx context.Context, status feedback.St
 	m.CallCount++
 }
 
-func (m *MockNotifier) SendScanProgress(ctx context.Context, current, total int, message string) {
+func (m *MockNotifier) SendScanProgress(ctx context.Context, progress feedback.ScanProgressDTO) {
 	m.LastEvent = "scan_progress"
+	m.LastProgress = progress
+	m.CallCount++
+}
+
+func (m *MockNotifier) SendFileOperationProgress(ctx context.Context, progress feedback.FileOperationProgressDTO) {
+	m.LastEvent = "file_operation_progress"
 	m.CallCount++
 }
 
@@ -119,6 +126,12 @@ func setupLogicTest(t *testing.T) (*sql.DB, database.Querier, *Scanner, string)
 	return conn, queries, scanner, root
 }
 
+// scanAndWait runs a full scan and waits until it finishes.
+func scanAndWait(t *testing.T, scanner *Scanner) {
+	assert.NoError(t, scanner.StartScan())
+	assert.Eventually(t, func() bool { return !scanner.isScanning.Load() }, 2*time.Second, 20*time.Millisecond)
+}
+
 // createDummyFile helper creates a file with dummy content at the specified path.
 func createDummyFile(t *testing.T, path string) {
 	err := os.WriteFile(path, []byte("dummy content"), 0644)
@@ -130,6 +143,8 @@ func createDummyFile(t *testing.T, path string) {
 // MockThumbnailGenerator mocks the thumbnail generation process.
 type MockThumbnailGenerator struct {
 	ShouldFail bool
+	// Metadata, when set, replaces the metadata returned for thumbnails that are not placeholders.
+	Metadata *ImageMetadata
 }
 
 func (m *MockThumbnailGenerator) Generate(ctx context.Context, sourcePath string) (ThumbnailResult, error) {
@@ -152,6 +167,10 @@ func (m *MockThumbnailGenerator) Generate(ctx context.Context, sourcePath string
 		isPlaceholder = true
 	}
 
+	if m.Metadata != nil && !isPlaceholder {
+		return ThumbnailResult{WebPath: webPath, Metadata: *m.Metadata}, nil
+	}
+
 	return ThumbnailResult{
 		WebPath: webPath,
 		Metadata: ImageMetadata{
@@ -0,0 +1,76 @@
+package scanner
+
+import (
+	"image"
+	"image/color"
+	"strconv"
+)
+
+// texturePreviewMaxSize is the longer edge of decoded TGA/DDS previews, which are subsampled
+// while they are read like PSD composites.
+const texturePreviewMaxSize = 1024
+
+// textureMaxDimension guards against corrupt headers.
+const textureMaxDimension = 1 << 16
+
+// TextureInfo describes a texture file (.tga, .dds, .ktx2).
+type TextureInfo struct {
+	Width       int
+	Height      int
+	MipCount    int
+	Format      string // Pixel format, e.g. "BC7_UNORM_SRGB" or "B8G8R8A8_UNORM"
+	Compression string // Block compression family ("BC1", "BC7", "ASTC", ...) or "none"
+	BitDepth    int
+	HasAlpha    bool
+}
+
+// Properties returns the format details stored in asset_properties.
+func (i TextureInfo) Properties() map[string]string {
+	return map[string]string{
+		PropertyMipCount:    strconv.Itoa(i.MipCount),
+		PropertyPixelFormat: i.Format,
+		PropertyCompression: i.Compression,
+	}
+}
+
+// metadata returns what is known about the texture without a preview, used when only the header
+// could be read (KTX2, unsupported DDS formats).
+func (i TextureInfo) metadata() ImageMetadata {
+	return ImageMetadata{
+		Width:           i.Width,
+		Height:          i.Height,
+		BitDepth:        i.BitDepth,
+		HasAlphaChannel: i.HasAlpha,
+		Properties:      i.Properties(),
+	}
+}
+
+// texturePreview is a subsampled 8-bit canvas. Pixels that are not on the sampling grid
+// are ignored by set.
+type texturePreview struct {
+	img  *image.NRGBA
+	step int
+}
+
+func newTexturePreview(width, height int) *texturePreview {
+	step := 1
+	if longest := max(width, height); longest > texturePreviewMaxSize {
+		step = (longest + texturePreviewMaxSize - 1) / texturePreviewMaxSize
+	}
+	return &texturePreview{
+		img:  image.NewNRGBA(image.Rect(0, 0, (width+step-1)/step, (height+step-1)/step)),
+		step: step,
+	}
+}
+
+// wantsRow reports whether source row y ends up in the preview.
+func (p *texturePreview) wantsRow(y int) bool {
+	return y%p.step == 0
+}
+
+func (p *texturePreview) set(x, y int, c color.NRGBA) {
+	if x%p.step != 0 || y%p.step != 0 {
+		return
+	}
+	p.img.SetNRGBA(x/p.step, y/p.step, c)
+}
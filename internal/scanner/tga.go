@@ -0,0 +1,210 @@
+package scanner
+
+import (
+	"bufio"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"image"
+	"image/color"
+	"io"
+	"os"
+)
+
+// TGA image types; the run-length encoded variants add tgaTypeRLE.
+const (
+	tgaTypeColorMapped = 1
+	tgaTypeTrueColor   = 2
+	tgaTypeGrayscale   = 3
+	tgaTypeRLE         = 8
+)
+
+// Image descriptor bits.
+const (
+	tgaAlphaBitsMask = 0x0f
+	tgaRightToLeft   = 0x10
+	tgaTopToBottom   = 0x20
+)
+
+const tgaHeaderSize = 18
+
+// DecodeTGAFile reads a preview and the info of a Truevision TGA file.
+func DecodeTGAFile(path string) (image.Image, TextureInfo, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, TextureInfo{}, err
+	}
+	defer f.Close()
+	return DecodeTGA(f)
+}
+
+// DecodeTGA decodes true color (15/16/24/32-bit), grayscale and color mapped TGA images,
+// raw or run-length encoded, downsampled to at most texturePreviewMaxSize.
+func DecodeTGA(r io.Reader) (image.Image, TextureInfo, error) {
+	br := bufio.NewReaderSize(r, 64<<10)
+	var hdr [tgaHeaderSize]byte
+	if _, err := io.ReadFull(br, hdr[:]); err != nil {
+		return nil, TextureInfo{}, fmt.Errorf("failed to read header: %w", err)
+	}
+	idLength, colorMapType, imageType := int(hdr[0]), hdr[1], int(hdr[2])
+	mapFirst := int(binary.LittleEndian.Uint16(hdr[3:]))
+	mapLength := int(binary.LittleEndian.Uint16(hdr[5:]))
+	mapDepth := int(hdr[7])
+	width := int(binary.LittleEndian.Uint16(hdr[12:]))
+	height := int(binary.LittleEndian.Uint16(hdr[14:]))
+	depth := int(hdr[16])
+	descriptor := hdr[17]
+	alphaBits := int(descriptor & tgaAlphaBitsMask)
+
+	baseType := imageType &^ tgaTypeRLE
+	rle := imageType&tgaTypeRLE != 0
+	info := TextureInfo{Width: width, Height: height, MipCount: 1, BitDepth: 8, Compression: "none"}
+	if rle {
+		info.Compression = "RLE"
+	}
+
+	switch {
+	case colorMapType > 1 || baseType < tgaTypeColorMapped || baseType > tgaTypeGrayscale:
+		return nil, info, errors.New("not a TGA file")
+	case width == 0 || height == 0:
+		return nil, info, errors.New("image has no pixels")
+	case baseType == tgaTypeTrueColor && depth != 15 && depth != 16 && depth != 24 && depth != 32:
+		return nil, info, fmt.Errorf("unsupported true color depth %d", depth)
+	case baseType == tgaTypeGrayscale && depth != 8 && depth != 16:
+		return nil, info, fmt.Errorf("unsupported grayscale depth %d", depth)
+	case baseType == tgaTypeColorMapped && (colorMapType != 1 || (depth != 8 && depth != 16)):
+		return nil, info, errors.New("invalid color mapped image")
+	case colorMapType == 1 && mapDepth != 15 && mapDepth != 16 && mapDepth != 24 && mapDepth != 32:
+		return nil, info, fmt.Errorf("unsupported color map depth %d", mapDepth)
+	}
+
+	switch baseType {
+	case tgaTypeTrueColor:
+		info.HasAlpha = alphaBits > 0 && (depth == 32 || depth == 16)
+		info.Format = map[int]string{15: "BGR5", 16: "BGR5A1", 24: "BGR8", 32: "BGRA8"}[depth]
+		if depth == 16 && !info.HasAlpha {
+			info.Format = "BGR5"
+		}
+	case tgaTypeGrayscale:
+		info.HasAlpha = depth == 16
+		info.Format = "Gray8"
+		if info.HasAlpha {
+			info.Format = "GrayAlpha8"
+		}
+	case tgaTypeColorMapped:
+		info.HasAlpha = alphaBits > 0 && (mapDepth == 32 || mapDepth == 16)
+		info.Format = "Indexed8"
+	}
+
+	if _, err := br.Discard(idLength); err != nil {
+		return nil, info, fmt.Errorf("failed to skip image id: %w", err)
+	}
+	var palette []color.NRGBA
+	if colorMapType == 1 {
+		entrySize := (mapDepth + 7) / 8
+		raw := make([]byte, mapLength*entrySize)
+		if _, err := io.ReadFull(br, raw); err != nil {
+			return nil, info, fmt.Errorf("failed to read color map: %w", err)
+		}
+		palette = make([]color.NRGBA, mapLength)
+		for i := range palette {
+			palette[i] = tgaTrueColor(raw[i*entrySize:], mapDepth, info.HasAlpha)
+		}
+	}
+
+	bpp := (depth + 7) / 8
+	pixel := func(b []byte) color.NRGBA {
+		switch baseType {
+		case tgaTypeGrayscale:
+			if depth == 16 {
+				return color.NRGBA{R: b[0], G: b[0], B: b[0], A: b[1]}
+			}
+			return color.NRGBA{R: b[0], G: b[0], B: b[0], A: 255}
+		case tgaTypeColorMapped:
+			idx := int(b[0])
+			if depth == 16 {
+				idx = int(binary.LittleEndian.Uint16(b))
+			}
+			if idx -= mapFirst; idx < 0 || idx >= len(palette) {
+				return color.NRGBA{A: 255}
+			}
+			return palette[idx]
+		default:
+			return tgaTrueColor(b, depth, info.HasAlpha)
+		}
+	}
+
+	preview := newTexturePreview(width, height)
+	row := make([]byte, width*bpp)
+	packet := make([]byte, bpp)
+	packetLeft, packetRepeat := 0, false
+	for line := 0; line < height; line++ {
+		if !rle {
+			if _, err := io.ReadFull(br, row); err != nil {
+				return nil, info, fmt.Errorf("row %d: %w", line, err)
+			}
+		} else {
+			// Packets may continue on the next row.
+			for x := 0; x < width; x++ {
+				if packetLeft == 0 {
+					head, err := br.ReadByte()
+					if err != nil {
+						return nil, info, fmt.Errorf("row %d: %w", line, err)
+					}
+					packetLeft = int(head&0x7f) + 1
+					packetRepeat = head&0x80 != 0
+					if packetRepeat {
+						if _, err := io.ReadFull(br, packet); err != nil {
+							return nil, info, fmt.Errorf("row %d: %w", line, err)
+						}
+					}
+				}
+				dst := row[x*bpp : (x+1)*bpp]
+				if packetRepeat {
+					copy(dst, packet)
+				} else if _, err := io.ReadFull(br, dst); err != nil {
+					return nil, info, fmt.Errorf("row %d: %w", line, err)
+				}
+				packetLeft--
+			}
+		}
+
+		y := height - 1 - line // bottom-up unless flagged otherwise
+		if descriptor&tgaTopToBottom != 0 {
+			y = line
+		}
+		if !preview.wantsRow(y) {
+			continue
+		}
+		for i := 0; i < width; i++ {
+			x := i
+			if descriptor&tgaRightToLeft != 0 {
+				x = width - 1 - i
+			}
+			preview.set(x, y, pixel(row[i*bpp:]))
+		}
+	}
+	return preview.img, info, nil
+}
+
+// tgaTrueColor converts a little endian BGR(A) pixel of 15, 16, 24 or 32 bits.
+func tgaTrueColor(b []byte, depth int, alpha bool) color.NRGBA {
+	switch depth {
+	case 15, 16:
+		v := binary.LittleEndian.Uint16(b)
+		expand := func(c uint16) uint8 { return uint8(c<<3 | c>>2) }
+		c := color.NRGBA{R: expand(v >> 10 & 0x1f), G: expand(v >> 5 & 0x1f), B: expand(v & 0x1f), A: 255}
+		if alpha && depth == 16 && v&0x8000 == 0 {
+			c.A = 0
+		}
+		return c
+	case 24:
+		return color.NRGBA{R: b[2], G: b[1], B: b[0], A: 255}
+	default:
+		c := color.NRGBA{R: b[2], G: b[1], B: b[0], A: 255}
+		if alpha {
+			c.A = b[3]
+		}
+		return c
+	}
+}
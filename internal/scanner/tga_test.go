@@ -0,0 +1,149 @@
+package scanner
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"image/color"
+	"io"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// testTGA buduje plik .tga; piksele są podane od górnego wiersza, zapis respektuje flagę topToBottom.
+func testTGA(imageType, depth int, descriptor byte, w, h int, palette []byte, pixel func(x, y int) []byte) []byte {
+	var buf bytes.Buffer
+	hdr := make([]byte, tgaHeaderSize)
+	hdr[0] = 3 // image id
+	hdr[2] = byte(imageType)
+	if palette != nil {
+		hdr[1] = 1
+		binary.LittleEndian.PutUint16(hdr[5:], uint16(len(palette)/3))
+		hdr[7] = 24
+	}
+	binary.LittleEndian.PutUint16(hdr[12:], uint16(w))
+	binary.LittleEndian.PutUint16(hdr[14:], uint16(h))
+	hdr[16] = byte(depth)
+	hdr[17] = descriptor
+	buf.Write(hdr)
+	buf.WriteString("abc")
+	buf.Write(palette)
+
+	rle := imageType&tgaTypeRLE != 0
+	for line := 0; line < h; line++ {
+		y := h - 1 - line
+		if descriptor&tgaTopToBottom != 0 {
+			y = line
+		}
+		for x := 0; x < w; x++ {
+			if rle {
+				// Każdy piksel jako pakiet powtórzenia długości 1 lub surowy pakiet - na zmianę.
+				buf.WriteByte(byte(x%2) << 7)
+			}
+			buf.Write(pixel(x, y))
+		}
+	}
+	return buf.Bytes()
+}
+
+func TestDecodeTGA(t *testing.T) {
+	redLeft := func(x, y int) []byte {
+		if x < 2 {
+			return []byte{0, 0, 255, 128} // BGRA
+		}
+		return []byte{255, 0, 0, 255}
+	}
+
+	t.Run("Uncompressed 24-bit bottom-up", func(t *testing.T) {
+		data := testTGA(tgaTypeTrueColor, 24, 0, 4, 3, nil, func(x, y int) []byte { return redLeft(x, y)[:3] })
+		img, info, err := DecodeTGA(bytes.NewReader(data))
+		require.NoError(t, err)
+		assert.Equal(t, TextureInfo{Width: 4, Height: 3, MipCount: 1, Format: "BGR8", Compression: "none", BitDepth: 8}, info)
+		assert.Equal(t, color.NRGBA{R: 255, A: 255}, img.At(0, 0))
+		assert.Equal(t, color.NRGBA{B: 255, A: 255}, img.At(3, 2))
+	})
+
+	t.Run("RLE 32-bit top-down with alpha", func(t *testing.T) {
+		data := testTGA(tgaTypeTrueColor|tgaTypeRLE, 32, tgaTopToBottom|8, 4, 3, nil, redLeft)
+		img, info, err := DecodeTGA(bytes.NewReader(data))
+		require.NoError(t, err)
+		assert.Equal(t, "RLE", info.Compression)
+		assert.Equal(t, "BGRA8", info.Format)
+		assert.True(t, info.HasAlpha)
+		assert.Equal(t, color.NRGBA{R: 255, A: 128}, img.At(1, 0))
+		assert.Equal(t, color.NRGBA{B: 255, A: 255}, img.At(2, 2))
+	})
+
+	t.Run("Run spanning rows", func(t *testing.T) {
+		hdr := testTGA(tgaTypeGrayscale|tgaTypeRLE, 8, tgaTopToBottom, 3, 2, nil, func(x, y int) []byte { return nil })
+		data := append(hdr, 0x80|5, 77) // jeden pakiet na oba wiersze
+		img, _, err := DecodeTGA(bytes.NewReader(data))
+		require.NoError(t, err)
+		assert.Equal(t, color.NRGBA{R: 77, G: 77, B: 77, A: 255}, img.At(2, 1))
+	})
+
+	t.Run("Color mapped", func(t *testing.T) {
+		palette := []byte{0, 255, 0, 255, 255, 255} // zielony, biały (BGR)
+		data := testTGA(tgaTypeColorMapped, 8, tgaRightToLeft, 2, 1, palette, func(x, y int) []byte { return []byte{byte(x)} })
+		img, info, err := DecodeTGA(bytes.NewReader(data))
+		require.NoError(t, err)
+		assert.Equal(t, "Indexed8", info.Format)
+		assert.False(t, info.HasAlpha)
+		// Zapis od prawej: pierwszy zapisany piksel (indeks 0) ląduje po prawej.
+		assert.Equal(t, color.NRGBA{R: 255, G: 255, B: 255, A: 255}, img.At(0, 0))
+		assert.Equal(t, color.NRGBA{G: 255, A: 255}, img.At(1, 0))
+	})
+
+	t.Run("16-bit with alpha bit", func(t *testing.T) {
+		data := testTGA(tgaTypeTrueColor, 16, 1|tgaTopToBottom, 2, 1, nil, func(x, y int) []byte {
+			if x == 0 {
+				return []byte{0x00, 0xfc} // czerwony, nieprzezroczysty
+			}
+			return []byte{0x1f, 0x00} // niebieski, przezroczysty
+		})
+		img, info, err := DecodeTGA(bytes.NewReader(data))
+		require.NoError(t, err)
+		assert.Equal(t, "BGR5A1", info.Format)
+		assert.Equal(t, color.NRGBA{R: 255, A: 255}, img.At(0, 0))
+		assert.Equal(t, uint8(0), img.At(1, 0).(color.NRGBA).A)
+	})
+}
+
+func TestDecodeTGA_InvalidInput(t *testing.T) {
+	valid := testTGA(tgaTypeTrueColor, 24, 0, 2, 2, nil, func(x, y int) []byte { return []byte{1, 2, 3} })
+	for name, data := range map[string][]byte{
+		"Empty":         nil,
+		"Unknown type":  append([]byte{0, 0, 9}, valid[3:]...),
+		"Odd depth":     append(append([]byte{}, valid[:16]...), append([]byte{12}, valid[17:]...)...),
+		"Truncated":     valid[:len(valid)-2],
+		"Missing color": testTGA(tgaTypeColorMapped, 8, 0, 1, 1, nil, func(x, y int) []byte { return []byte{0} }),
+	} {
+		t.Run(name, func(t *testing.T) {
+			_, _, err := DecodeTGA(bytes.NewReader(data))
+			assert.Error(t, err)
+		})
+	}
+}
+
+func TestDiskThumbnailGenerator_TGA(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "albedo.tga")
+	data := testTGA(tgaTypeTrueColor|tgaTypeRLE, 32, 8, 64, 32, nil, func(x, y int) []byte { return []byte{byte(x * 4), 90, 200, 255} })
+	require.NoError(t, os.WriteFile(path, data, 0644))
+
+	generator := NewDiskThumbnailGenerator(dir, slog.New(slog.NewTextHandler(io.Discard, nil)))
+	res, err := generator.Generate(context.Background(), path)
+	require.NoError(t, err)
+	assert.False(t, res.IsPlaceholder)
+	assert.Equal(t, 64, res.Metadata.Width)
+	assert.Equal(t, 32, res.Metadata.Height)
+	assert.True(t, res.Metadata.HasAlphaChannel)
+	assert.NotEmpty(t, res.Metadata.PerceptualHash)
+	assert.Equal(t, "RLE", res.Metadata.Properties[PropertyCompression])
+	assert.Equal(t, "1", res.Metadata.Properties[PropertyMipCount])
+}
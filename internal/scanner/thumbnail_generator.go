@@ -3,6 +3,7 @@ package scanner
 import (
 	"context"
 	"eclat/internal/config"
+	"errors"
 	"fmt"
 	"image"
 	"image/color"
@@ -12,6 +13,7 @@ import (
 	"path"
 	"path/filepath"
 	"strings"
+	"time"
 
 	_ "image/gif"
 	_ "image/jpeg"
@@ -31,9 +33,10 @@ type ThumbnailGenerator interface {
 // DiskThumbnailGenerator implements ThumbnailGenerator by creating thumbnails on disk
 // or returning static placeholders for unsupported file types.
 type DiskThumbnailGenerator struct {
-	cacheDir       string
-	logger         *slog.Logger
-	placeholderMap map[string]string
+	cacheDir          string
+	logger            *slog.Logger
+	placeholderMap    map[string]string
+	modelRenderBudget time.Duration
 }
 
 // ThumbnailResult contains the path to the generated thumbnail (or placeholder)
@@ -48,8 +51,9 @@ type ThumbnailResult struct {
 // It initializes a map of default placeholders for various 3D and texture formats.
 func NewDiskThumbnailGenerator(cacheDir string, logger *slog.Logger) *DiskThumbnailGenerator {
 	return &DiskThumbnailGenerator{
-		cacheDir: cacheDir,
-		logger:   logger,
+		cacheDir:          cacheDir,
+		logger:            logger,
+		modelRenderBudget: DefaultModelRenderBudget,
 		placeholderMap: map[string]string{
 			".blend":  "blend_placeholder.webp",
 			".blend1": "blend_placeholder.webp",
@@ -94,6 +98,18 @@ func isSupportedImageExt(ext string) bool {
 	return false
 }
 
+func isPSDExt(ext string) bool {
+	return ext == ".psd" || ext == ".psb"
+}
+
+func isHDRExt(ext string) bool {
+	return ext == ".exr" || ext == ".hdr"
+}
+
+func isTextureExt(ext string) bool {
+	return ext == ".tga" || ext == ".dds" || ext == ".ktx2"
+}
+
 // Generate creates a thumbnail for the file at srcPath.
 // If the file is a supported image, it generates a JPEG thumbnail and extracts metadata.
 // For other files, it returns a pre-configured placeholder.
@@ -111,6 +127,61 @@ func (g *DiskThumbnailGenerator) Generate(ctx context.Context, srcPath string) (
 		g.logger.Warn("Thumbnail generation failed, falling back to placeholder", "path", srcPath, "error", err)
 	}
 
+	// Photoshop documents carry a flattened composite that can be read without Photoshop
+	if isPSDExt(ext) {
+		g.logger.Debug("Generating thumbnail from PSD composite", "path", srcPath)
+		res, err := g.generateFromPSD(srcPath)
+		if err == nil {
+			return res, nil
+		}
+		g.logger.Warn("PSD preview extraction failed, falling back to placeholder", "path", srcPath, "error", err)
+	}
+
+	// HDR images (OpenEXR, Radiance) are decoded to floats and tone mapped
+	if isHDRExt(ext) {
+		g.logger.Debug("Generating tone mapped thumbnail from HDR image", "path", srcPath)
+		res, err := g.generateFromHDR(srcPath, ext)
+		if err == nil {
+			return res, nil
+		}
+		g.logger.Warn("HDR decoding failed, falling back to placeholder", "path", srcPath, "error", err)
+	}
+
+	// Game textures; formats without a decoder still report what their header says
+	if isTextureExt(ext) {
+		g.logger.Debug("Generating thumbnail from texture", "path", srcPath)
+		res, err := g.generateFromTexture(srcPath, ext)
+		if err == nil {
+			return res, nil
+		}
+		g.logger.Warn("Texture decoding failed, falling back to placeholder", "path", srcPath, "error", err)
+	}
+
+	// 3D models are rendered on the CPU within a time budget. The scanner records the attempt,
+	// so a model over budget is rendered again only once its size or modification time changes.
+	if isModelExt(ext) {
+		g.logger.Debug("Rendering thumbnail from model", "path", srcPath)
+		res, err := g.generateFromModel(ctx, srcPath, ext)
+		if err == nil {
+			return res, nil
+		}
+		if errors.Is(err, context.DeadlineExceeded) {
+			g.logger.Warn("Model render exceeded its time budget, falling back to placeholder", "path", srcPath, "budget", g.modelRenderBudget)
+		} else {
+			g.logger.Warn("Model render failed, falling back to placeholder", "path", srcPath, "error", err)
+		}
+	}
+
+	// Blender saves a small preview render inside the .blend file
+	if isBlendExt(ext) {
+		g.logger.Debug("Extracting thumbnail embedded in blend file", "path", srcPath)
+		res, err := g.generateFromBlend(srcPath, ext)
+		if err == nil {
+			return res, nil
+		}
+		g.logger.Warn("Blend preview extraction failed, falling back to placeholder", "path", srcPath, "error", err)
+	}
+
 	// 2. Fallback to placeholder for non-image types or failed generation
 	res := g.getPlaceholderResult(ext)
 	g.logger.Debug("Using placeholder", "path", srcPath, "placeholder", res.WebPath)
@@ -118,28 +189,171 @@ func (g *DiskThumbnailGenerator) Generate(ctx context.Context, srcPath string) (
 }
 
 func (g *DiskThumbnailGenerator) generateFromImage(srcPath string) (ThumbnailResult, error) {
+	header, hasHeader := readImageHeader(srcPath)
 	img, err := imaging.Open(srcPath)
 	if err != nil {
+		if hasHeader {
+			// e.g. floating point TIFFs: no preview, but the header is still worth keeping
+			g.logger.Debug("Image could not be decoded, keeping header metadata", "path", srcPath, "error", err)
+			res := g.getPlaceholderResult(strings.ToLower(filepath.Ext(srcPath)))
+			res.Metadata = ImageMetadata{Width: header.Width, Height: header.Height, BitDepth: header.BitDepth, HasAlphaChannel: header.HasAlpha}
+			return res, nil
+		}
 		return ThumbnailResult{}, fmt.Errorf("failed to open image: %w", err)
 	}
 	originalBounds := img.Bounds()
 	hasAlphaChannel := hasAlpha(img)
 	bitDepth := GetBitDepth(img)
+	if hasHeader {
+		// The decoded color model can't express 1-16 bit samples faithfully, the header can
+		bitDepth = header.BitDepth
+	}
 	thumb := imaging.Resize(img, 400, 0, imaging.Linear)
 
 	imgMetadata := g.extractMetadataFromThumb(thumb, originalBounds, bitDepth, hasAlphaChannel)
 
+	webPath, err := saveThumbnailJPEG(g.cacheDir, thumb)
+	if err != nil {
+		return ThumbnailResult{}, err
+	}
+
+	return ThumbnailResult{
+		WebPath:       webPath,
+		Metadata:      imgMetadata,
+		IsPlaceholder: false,
+	}, nil
+}
+
+func (g *DiskThumbnailGenerator) generateFromPSD(srcPath string) (ThumbnailResult, error) {
+	preview, info, err := DecodePSDFile(srcPath)
+	if err != nil {
+		return ThumbnailResult{}, err
+	}
+	thumb := imaging.Resize(preview, 400, 0, imaging.Linear)
+
+	// Dimensions, depth and alpha come from the document, not from the (possibly tiny) preview.
+	imgMetadata := g.extractMetadataFromThumb(thumb, image.Rect(0, 0, info.Width, info.Height), info.Depth, info.HasAlpha)
+	imgMetadata.Properties = info.Properties()
+
+	webPath, err := saveThumbnailJPEG(g.cacheDir, thumb)
+	if err != nil {
+		return ThumbnailResult{}, err
+	}
+	return ThumbnailResult{
+		WebPath:  webPath,
+		Metadata: imgMetadata,
+	}, nil
+}
+
+func (g *DiskThumbnailGenerator) generateFromHDR(srcPath, ext string) (ThumbnailResult, error) {
+	decode := DecodeEXRFile
+	if ext == ".hdr" {
+		decode = DecodeRadianceFile
+	}
+	preview, info, err := decode(srcPath)
+	if err != nil {
+		return ThumbnailResult{}, err
+	}
+	thumb := imaging.Resize(preview, 400, 0, imaging.Linear)
+
+	// The colors are analysed on the tone mapped preview, the rest describes the float data.
+	imgMetadata := g.extractMetadataFromThumb(thumb, image.Rect(0, 0, info.Width, info.Height), info.BitDepth, info.HasAlpha)
+	imgMetadata.Properties = info.Properties()
+
+	webPath, err := saveThumbnailJPEG(g.cacheDir, thumb)
+	if err != nil {
+		return ThumbnailResult{}, err
+	}
+	return ThumbnailResult{
+		WebPath:  webPath,
+		Metadata: imgMetadata,
+	}, nil
+}
+
+func (g *DiskThumbnailGenerator) generateFromTexture(srcPath, ext string) (ThumbnailResult, error) {
+	var preview image.Image
+	var info TextureInfo
+	var err error
+	switch ext {
+	case ".tga":
+		preview, info, err = DecodeTGAFile(srcPath)
+	case ".dds":
+		preview, info, err = DecodeDDSFile(srcPath)
+	default:
+		info, err = ReadKTX2File(srcPath)
+		if err == nil {
+			err = ErrTexturePreviewUnsupported
+		}
+	}
+	if errors.Is(err, ErrTexturePreviewUnsupported) {
+		res := g.getPlaceholderResult(ext)
+		res.Metadata = info.metadata()
+		return res, nil
+	}
+	if err != nil {
+		return ThumbnailResult{}, err
+	}
+	thumb := imaging.Resize(preview, 400, 0, imaging.Linear)
+
+	imgMetadata := g.extractMetadataFromThumb(thumb, image.Rect(0, 0, info.Width, info.Height), info.BitDepth, info.HasAlpha)
+	imgMetadata.Properties = info.Properties()
+
+	webPath, err := saveThumbnailJPEG(g.cacheDir, thumb)
+	if err != nil {
+		return ThumbnailResult{}, err
+	}
+	return ThumbnailResult{
+		WebPath:  webPath,
+		Metadata: imgMetadata,
+	}, nil
+}
+
+// generateFromBlend uses the preview Blender embeds in the file. Files saved without one still
+// report the Blender version that wrote them.
+func (g *DiskThumbnailGenerator) generateFromBlend(srcPath, ext string) (ThumbnailResult, error) {
+	preview, info, err := ReadBlendFile(srcPath)
+	if errors.Is(err, ErrBlendNoThumbnail) {
+		res := g.getPlaceholderResult(ext)
+		res.Metadata = ImageMetadata{Properties: info.Properties()}
+		return res, nil
+	}
+	if err != nil {
+		return ThumbnailResult{}, err
+	}
+	thumb := imaging.Resize(preview, modelRenderSize, 0, imaging.Linear)
+	flat := flattenOnBackground(thumb, modelBackground)
+
+	// Like rendered models, a scene has no pixel dimensions and the backdrop is not its color.
+	imgMetadata := g.extractMetadataFromThumb(flat, image.Rectangle{}, 0, false)
+	if colors, err := CalculateColorPalette(thumb, PaletteSize, config.PredefinedPalette); err == nil && len(colors) > 0 {
+		imgMetadata.Colors = colors
+		imgMetadata.DominantColor = colors[0].PaletteHex
+	}
+	imgMetadata.Properties = info.Properties()
+
+	webPath, err := saveThumbnailJPEG(g.cacheDir, flat)
+	if err != nil {
+		return ThumbnailResult{}, err
+	}
+	return ThumbnailResult{
+		WebPath:  webPath,
+		Metadata: imgMetadata,
+	}, nil
+}
+
+// saveThumbnailJPEG writes thumb into cacheDir under a random name and returns its web path.
+func saveThumbnailJPEG(cacheDir string, thumb image.Image) (string, error) {
 	bounds := thumb.Bounds()
 	imgRGBA := image.NewRGBA(bounds)
 	draw.Draw(imgRGBA, bounds, thumb, bounds.Min, draw.Src)
 
 	id := uuid.New()
 	filename := fmt.Sprintf("%s.jpg", id.String())
-	fullDestPath := filepath.Join(g.cacheDir, filename)
+	fullDestPath := filepath.Join(cacheDir, filename)
 
 	outFile, err := os.Create(fullDestPath)
 	if err != nil {
-		return ThumbnailResult{}, fmt.Errorf("failed to create thumbnail file: %w", err)
+		return "", fmt.Errorf("failed to create thumbnail file: %w", err)
 	}
 	defer outFile.Close()
 
@@ -147,35 +361,40 @@ func (g *DiskThumbnailGenerator) generateFromImage(srcPath string) (ThumbnailRes
 		Quality: 80,
 	})
 	if err != nil {
-		return ThumbnailResult{}, fmt.Errorf("jpeg encode error: %w", err)
+		return "", fmt.Errorf("jpeg encode error: %w", err)
 	}
-
-	return ThumbnailResult{
-		WebPath:       "/thumbnails/" + filename,
-		Metadata:      imgMetadata,
-		IsPlaceholder: false,
-	}, nil
+	return "/thumbnails/" + filename, nil
 }
 
 func (g *DiskThumbnailGenerator) extractMetadataFromThumb(thumb image.Image, origBounds image.Rectangle, bitDepth int, hasAlpha bool) ImageMetadata {
+	return extractThumbnailMetadata(g.logger, thumb, origBounds, bitDepth, hasAlpha)
+}
 
-	domColorHex, err := CalculateDominantColor(thumb)
+// extractThumbnailMetadata analyses a thumbnail (colors, pHash, histogram). Dimensions, bit depth and
+// alpha describe the original image and are passed in by the caller.
+func extractThumbnailMetadata(logger *slog.Logger, thumb image.Image, origBounds image.Rectangle, bitDepth int, hasAlpha bool) ImageMetadata {
 
+	// Main colors with their share of the image. They are snapped to the user's active palette
+	// by the scanner, the built-in palette here only fills DominantColor for other callers.
 	var closestColor string
-	var hexColor string
-
+	colors, err := CalculateColorPalette(thumb, PaletteSize, config.PredefinedPalette)
 	if err != nil {
+		logger.Debug("Skipping color analysis", "reason", err)
+	} else if len(colors) > 0 {
+		closestColor = colors[0].PaletteHex
+	}
 
-		g.logger.Debug("Skipping color analysis", "reason", err)
-		hexColor = ""
+	// The thumbnail is plenty for a 32x32 pHash and much cheaper than the full-size image.
+	var perceptualHash string
+	if phash, err := CalculatePerceptualHash(thumb); err != nil {
+		logger.Debug("Skipping perceptual hash", "reason", err)
 	} else {
-		hexColor = domColorHex
-		closest, err := FindClosestPaletteColor(domColorHex, config.PredefinedPalette)
-		if err != nil {
-			g.logger.Debug("Failed to find closest color", "hex", hexColor, "error", err)
-		} else {
-			closestColor = closest
-		}
+		perceptualHash = FormatPerceptualHash(phash)
+	}
+
+	colorHistogram, err := CalculateColorHistogram(thumb)
+	if err != nil {
+		logger.Debug("Skipping color histogram", "reason", err)
 	}
 
 	meta := ImageMetadata{
@@ -184,6 +403,9 @@ func (g *DiskThumbnailGenerator) extractMetadataFromThumb(thumb image.Image, ori
 		DominantColor:   closestColor,
 		BitDepth:        bitDepth,
 		HasAlphaChannel: hasAlpha,
+		PerceptualHash:  perceptualHash,
+		ColorHistogram:  colorHistogram,
+		Colors:          colors,
 	}
 	return meta
 }
@@ -0,0 +1,164 @@
+package scanner
+
+import (
+	"bytes"
+	"context"
+	"eclat/internal/config"
+	"errors"
+	"fmt"
+	"image"
+	"log/slog"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/disintegration/imaging"
+)
+
+const (
+	// providerOutputName is the file the provider is asked to write. Most tools pick the
+	// format from the extension, and PNG keeps alpha.
+	providerOutputName = "thumbnail.png"
+	// providerWaitDelay bounds how long we wait for the output pipes of a killed provider,
+	// e.g. when a wrapper script leaves child processes behind.
+	providerWaitDelay = 2 * time.Second
+	// providerLogLimit is how much of the tool output ends up in the log on failure.
+	providerLogLimit = 2048
+)
+
+// ExternalThumbnailGenerator renders thumbnails with external tools (Blender, ImageMagick, ...)
+// configured per extension in ScannerConfig. It is chained in front of another ThumbnailGenerator:
+// files without a provider, and renders that fail or time out, are passed on to next,
+// which falls back to the placeholder.
+type ExternalThumbnailGenerator struct {
+	cacheDir string
+	logger   *slog.Logger
+	config   *config.ScannerConfig
+	next     ThumbnailGenerator
+
+	mu    sync.Mutex
+	slots map[string]providerSlots
+}
+
+// providerSlots limits the number of concurrent renders of one provider.
+type providerSlots struct {
+	limit int
+	ch    chan struct{}
+}
+
+// NewExternalThumbnailGenerator creates a provider registry in front of next.
+// Providers are read from cfg on every call, so changes in the settings apply to the next file.
+func NewExternalThumbnailGenerator(cacheDir string, logger *slog.Logger, cfg *config.ScannerConfig, next ThumbnailGenerator) *ExternalThumbnailGenerator {
+	return &ExternalThumbnailGenerator{
+		cacheDir: cacheDir,
+		logger:   logger,
+		config:   cfg,
+		next:     next,
+		slots:    make(map[string]providerSlots),
+	}
+}
+
+// Generate renders a thumbnail with the first provider configured for the file extension.
+// Without a matching provider, or when the render fails, the next generator is used.
+func (g *ExternalThumbnailGenerator) Generate(ctx context.Context, srcPath string) (ThumbnailResult, error) {
+	ext := strings.ToLower(filepath.Ext(srcPath))
+	for _, provider := range g.config.GetThumbnailProviders() {
+		if !provider.Handles(ext) {
+			continue
+		}
+		res, err := g.render(ctx, provider, srcPath)
+		if err == nil {
+			return res, nil
+		}
+		g.logger.Warn("External thumbnail provider failed, falling back", "provider", provider.Name, "path", srcPath, "error", err)
+		break
+	}
+	return g.next.Generate(ctx, srcPath)
+}
+
+// render runs a provider and turns the image it wrote into a regular JPEG thumbnail.
+func (g *ExternalThumbnailGenerator) render(ctx context.Context, provider config.ThumbnailProvider, srcPath string) (ThumbnailResult, error) {
+	slots := g.acquireSlots(provider)
+	select {
+	case slots <- struct{}{}:
+		defer func() { <-slots }()
+	case <-ctx.Done():
+		return ThumbnailResult{}, ctx.Err()
+	}
+
+	workDir, err := os.MkdirTemp("", "eclat-thumb-*")
+	if err != nil {
+		return ThumbnailResult{}, fmt.Errorf("failed to create work dir: %w", err)
+	}
+	defer os.RemoveAll(workDir)
+
+	outPath := filepath.Join(workDir, providerOutputName)
+	args, err := provider.Args(srcPath, outPath)
+	if err != nil {
+		return ThumbnailResult{}, err
+	}
+
+	runCtx, cancel := context.WithTimeout(ctx, provider.Timeout())
+	defer cancel()
+
+	var output bytes.Buffer
+	// The provider keeps the working directory of the app, so relative paths in the
+	// command (e.g. `--python render_thumb.py`) resolve the same way as in a shell.
+	cmd := exec.CommandContext(runCtx, args[0], args[1:]...)
+	cmd.Stdout = &output
+	cmd.Stderr = &output
+	cmd.WaitDelay = providerWaitDelay
+
+	g.logger.Debug("Running thumbnail provider", "provider", provider.Name, "path", srcPath)
+	start := time.Now()
+	if err := cmd.Run(); err != nil {
+		if errors.Is(runCtx.Err(), context.DeadlineExceeded) {
+			return ThumbnailResult{}, fmt.Errorf("timed out after %s", provider.Timeout())
+		}
+		return ThumbnailResult{}, fmt.Errorf("%w: %s", err, tail(output.String(), providerLogLimit))
+	}
+
+	img, err := imaging.Open(outPath)
+	if err != nil {
+		return ThumbnailResult{}, fmt.Errorf("provider did not produce a readable image: %w", err)
+	}
+	thumb := imaging.Resize(img, 400, 0, imaging.Linear)
+
+	webPath, err := saveThumbnailJPEG(g.cacheDir, thumb)
+	if err != nil {
+		return ThumbnailResult{}, err
+	}
+	g.logger.Debug("Thumbnail rendered by provider", "provider", provider.Name, "path", srcPath, "took", time.Since(start))
+
+	// The render says nothing about the source resolution or bit depth, only its look is analysed.
+	return ThumbnailResult{
+		WebPath:  webPath,
+		Metadata: extractThumbnailMetadata(g.logger, thumb, image.Rectangle{}, 0, false),
+	}, nil
+}
+
+// acquireSlots returns the semaphore of a provider, recreating it when its limit was changed.
+func (g *ExternalThumbnailGenerator) acquireSlots(provider config.ThumbnailProvider) chan struct{} {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	limit := provider.Concurrency()
+	slots, ok := g.slots[provider.Name]
+	if !ok || slots.limit != limit {
+		slots = providerSlots{limit: limit, ch: make(chan struct{}, limit)}
+		g.slots[provider.Name] = slots
+	}
+	return slots.ch
+}
+
+// tail returns the last n bytes of s, trimmed.
+func tail(s string, n int) string {
+	s = strings.TrimSpace(s)
+	if len(s) > n {
+		s = "..." + s[len(s)-n:]
+	}
+	return s
+}
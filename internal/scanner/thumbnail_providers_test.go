@@ -0,0 +1,145 @@
+package scanner
+
+import (
+	"context"
+	"eclat/internal/config"
+	"image"
+	"image/color"
+	"image/png"
+	"io"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// setupProviderTest tworzy generator z providerem opartym o skrypt-atrapę zamiast Blendera.
+// Plik źródłowy ".blend" to w rzeczywistości PNG, więc atrapa może go po prostu skopiować.
+func setupProviderTest(t *testing.T, script string, provider config.ThumbnailProvider) (*ExternalThumbnailGenerator, string, string) {
+	if runtime.GOOS == "windows" {
+		t.Skip("stub provider is a shell script")
+	}
+	dir := t.TempDir()
+	cacheDir := filepath.Join(dir, "thumbs")
+	assert.NoError(t, os.Mkdir(cacheDir, 0755))
+
+	scriptPath := filepath.Join(dir, "render_thumb.sh")
+	assert.NoError(t, os.WriteFile(scriptPath, []byte("#!/bin/sh\n"+script+"\n"), 0755))
+
+	src := filepath.Join(dir, "scene file.blend")
+	img := image.NewNRGBA(image.Rect(0, 0, 64, 64))
+	for y := 0; y < 64; y++ {
+		for x := 0; x < 64; x++ {
+			img.Set(x, y, color.NRGBA{R: 255, A: 255})
+		}
+	}
+	f, err := os.Create(src)
+	assert.NoError(t, err)
+	assert.NoError(t, png.Encode(f, img))
+	f.Close()
+
+	provider.Name = "blender"
+	provider.Extensions = []string{".blend"}
+	provider.Command = "sh " + scriptPath + " {in} {out}"
+
+	cfg := config.NewScannerConfig()
+	cfg.SetThumbnailProviders([]config.ThumbnailProvider{provider})
+	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
+	next := NewDiskThumbnailGenerator(cacheDir, logger)
+	return NewExternalThumbnailGenerator(cacheDir, logger, cfg, next), src, cacheDir
+}
+
+func TestExternalThumbnailGenerator_Render(t *testing.T) {
+	gen, src, cacheDir := setupProviderTest(t, `cp "$1" "$2"`, config.ThumbnailProvider{})
+
+	res, err := gen.Generate(context.Background(), src)
+	assert.NoError(t, err)
+	assert.False(t, res.IsPlaceholder)
+	assert.True(t, strings.HasPrefix(res.WebPath, "/thumbnails/"))
+	_, err = os.Stat(filepath.Join(cacheDir, filepath.Base(res.WebPath)))
+	assert.NoError(t, err, "Miniatura powinna trafić do cache")
+
+	if assert.NotEmpty(t, res.Metadata.Colors) {
+		assert.Equal(t, "#FF0000", res.Metadata.Colors[0].PaletteHex)
+	}
+	assert.Zero(t, res.Metadata.Width, "Render nie mówi nic o rozdzielczości źródła")
+}
+
+func TestExternalThumbnailGenerator_RelativeScriptPath(t *testing.T) {
+	gen, src, _ := setupProviderTest(t, `cp "$1" "$2"`, config.ThumbnailProvider{})
+	// Względna ścieżka skryptu, jak w `blender -b {in} --python render_thumb.py -- {out}`,
+	// liczona jest od katalogu roboczego aplikacji.
+	t.Chdir(filepath.Dir(src))
+	providers := gen.config.GetThumbnailProviders()
+	providers[0].Command = "sh render_thumb.sh {in} {out}"
+	gen.config.SetThumbnailProviders(providers)
+
+	res, err := gen.Generate(context.Background(), src)
+	assert.NoError(t, err)
+	assert.False(t, res.IsPlaceholder, "Skrypt ze ścieżką względną powinien zostać znaleziony")
+}
+
+func TestExternalThumbnailGenerator_FallbackToPlaceholder(t *testing.T) {
+	t.Run("Tool fails", func(t *testing.T) {
+		gen, src, _ := setupProviderTest(t, `echo "Blender crashed" >&2; exit 1`, config.ThumbnailProvider{})
+		res, err := gen.Generate(context.Background(), src)
+		assert.NoError(t, err)
+		assert.True(t, res.IsPlaceholder)
+		assert.Contains(t, res.WebPath, "blend_placeholder.webp")
+	})
+
+	t.Run("Tool writes nothing", func(t *testing.T) {
+		gen, src, _ := setupProviderTest(t, `exit 0`, config.ThumbnailProvider{})
+		res, err := gen.Generate(context.Background(), src)
+		assert.NoError(t, err)
+		assert.True(t, res.IsPlaceholder)
+	})
+
+	t.Run("Timeout", func(t *testing.T) {
+		gen, src, _ := setupProviderTest(t, `sleep 10; cp "$1" "$2"`, config.ThumbnailProvider{TimeoutSeconds: 1})
+		res, err := gen.Generate(context.Background(), src)
+		assert.NoError(t, err)
+		assert.True(t, res.IsPlaceholder)
+	})
+
+	t.Run("No provider for extension", func(t *testing.T) {
+		gen, _, _ := setupProviderTest(t, `exit 1`, config.ThumbnailProvider{})
+		res, err := gen.Generate(context.Background(), "model.ma")
+		assert.NoError(t, err)
+		assert.Contains(t, res.WebPath, "ma_placeholder.webp")
+	})
+}
+
+func TestExternalThumbnailGenerator_ConcurrencyLimit(t *testing.T) {
+	// Atrapa zakłada "lock" katalogiem - dwa równoległe renderowania kończą się błędem (placeholder).
+	script := `lock="$(dirname "$0")/lock"
+mkdir "$lock" || exit 1
+sleep 0.2
+cp "$1" "$2"
+rmdir "$lock"`
+	gen, src, _ := setupProviderTest(t, script, config.ThumbnailProvider{MaxConcurrent: 1})
+
+	var wg sync.WaitGroup
+	results := make([]ThumbnailResult, 3)
+	for i := range results {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			results[i], _ = gen.Generate(context.Background(), src)
+		}()
+	}
+	wg.Wait()
+
+	for _, res := range results {
+		assert.False(t, res.IsPlaceholder, "Provider z limitem 1 nie może działać równolegle")
+	}
+}
+
+func TestExternalThumbnailGenerator_ImplementsInterface(t *testing.T) {
+	var _ ThumbnailGenerator = (*ExternalThumbnailGenerator)(nil)
+}
@@ -55,10 +55,23 @@ type FolderWatcher interface {
 	Unwatch(path string)
 }
 
+// FolderScanner defines the interface for importing the contents of selected scan folders
+// and dropping the per-folder scanner settings of removed ones.
+type FolderScanner interface {
+	StartScanFolders(ids []int64) error
+	ResetFolderGroupingRules(folderID int64) error
+}
+
 // KeyAllowedExtensions is the database key for storing allowed file extensions.
 const KeyAllowedExtensions = "allowed_extensions"
 const KeyDebugMode = "debug_mode"
 
+// KeyThumbnailProviders is the database key for storing external thumbnail providers.
+const KeyThumbnailProviders = "thumbnail_providers"
+
+// KeyMaterialChannels is the database key for storing the PBR channel suffix synonyms.
+const KeyMaterialChannels = "material_channels"
+
 // SettingsService manages application configuration, scan folders, and system integration.
 type SettingsService struct {
 	ctx      context.Context
@@ -69,10 +82,11 @@ type SettingsService struct {
 	notifier feedback.Notifier
 	wails    WailsRuntime
 	watcher  FolderWatcher
+	scanner  FolderScanner
 }
 
 // NewSettingsService creates a new instance of SettingsService.
-func NewSettingsService(db database.Querier, logger *slog.Logger, logLevel *slog.LevelVar, notifier feedback.Notifier, watcher FolderWatcher, cfg *config.ScannerConfig) *SettingsService {
+func NewSettingsService(db database.Querier, logger *slog.Logger, logLevel *slog.LevelVar, notifier feedback.Notifier, watcher FolderWatcher, scanner FolderScanner, cfg *config.ScannerConfig) *SettingsService {
 	return &SettingsService{
 		db:       db,
 		logger:   logger,
@@ -81,6 +95,7 @@ func NewSettingsService(db database.Querier, logger *slog.Logger, logLevel *slog
 		config:   cfg,
 		wails:    &RealWailsRuntime{},
 		watcher:  watcher,
+		scanner:  scanner,
 	}
 }
 
@@ -174,6 +189,93 @@ func (s *SettingsService) SetAllowedExtensions(exts []string) error {
 	return nil
 }
 
+// GetThumbnailProviders returns the configured external thumbnail providers.
+func (s *SettingsService) GetThumbnailProviders() []config.ThumbnailProvider {
+	return s.config.GetThumbnailProviders()
+}
+
+// SetThumbnailProviders validates, applies and persists the external thumbnail providers.
+// Files already in the library keep their thumbnails until they change or are rescanned.
+func (s *SettingsService) SetThumbnailProviders(providers []config.ThumbnailProvider) error {
+	normalized := make([]config.ThumbnailProvider, 0, len(providers))
+	names := make(map[string]bool, len(providers))
+	for _, p := range providers {
+		p = p.Normalize()
+		if err := p.Validate(); err != nil {
+			return err
+		}
+		if names[strings.ToLower(p.Name)] {
+			return fmt.Errorf("provider %q is defined twice", p.Name)
+		}
+		names[strings.ToLower(p.Name)] = true
+		normalized = append(normalized, p)
+	}
+
+	jsonBytes, err := json.Marshal(normalized)
+	if err != nil {
+		return fmt.Errorf("failed to save thumbnail providers: %w", err)
+	}
+	err = s.db.SetSystemSetting(s.ctx, database.SetSystemSettingParams{
+		Key:   KeyThumbnailProviders,
+		Value: string(jsonBytes),
+	})
+	if err != nil {
+		s.logger.Error("Failed to persist thumbnail providers to DB", "error", err)
+		return err
+	}
+
+	s.config.SetThumbnailProviders(normalized)
+	s.logger.Info("Thumbnail providers saved", "count", len(normalized))
+	return nil
+}
+
+// GetMaterialChannelSynonyms returns the file name suffixes that mark each PBR texture map role.
+func (s *SettingsService) GetMaterialChannelSynonyms() []config.MaterialChannel {
+	return s.config.GetMaterialChannels()
+}
+
+// SetMaterialChannelSynonyms validates, applies and persists the PBR channel suffixes.
+// A suffix may belong to only one role. Files already in the library keep their groups.
+func (s *SettingsService) SetMaterialChannelSynonyms(channels []config.MaterialChannel) error {
+	normalized := make([]config.MaterialChannel, 0, len(channels))
+	roles := make(map[string]bool, len(channels))
+	owners := make(map[string]string)
+	for _, m := range channels {
+		m = m.Normalize()
+		if err := m.Validate(); err != nil {
+			return err
+		}
+		if roles[m.Role] {
+			return fmt.Errorf("material channel %q is defined twice", m.Role)
+		}
+		roles[m.Role] = true
+		for _, syn := range m.Synonyms {
+			if owner, taken := owners[syn]; taken {
+				return fmt.Errorf("suffix %q is used by both %q and %q", syn, owner, m.Role)
+			}
+			owners[syn] = m.Role
+		}
+		normalized = append(normalized, m)
+	}
+
+	jsonBytes, err := json.Marshal(normalized)
+	if err != nil {
+		return fmt.Errorf("failed to save material channels: %w", err)
+	}
+	err = s.db.SetSystemSetting(s.ctx, database.SetSystemSettingParams{
+		Key:   KeyMaterialChannels,
+		Value: string(jsonBytes),
+	})
+	if err != nil {
+		s.logger.Error("Failed to persist material channels to DB", "error", err)
+		return err
+	}
+
+	s.config.SetMaterialChannels(normalized)
+	s.logger.Info("Material channel synonyms saved", "count", len(normalized))
+	return nil
+}
+
 // --- FOLDER MANAGEMENT ---
 
 // GetFolders retrieves the list of configured scan folders.
@@ -216,12 +318,14 @@ func (s *SettingsService) UpdateFolderStatus(id int64, isActive bool) (ScanFolde
 	statusMsg := "paused"
 	if isActive {
 		statusMsg = "active"
+		// Files added while the folder was paused are picked up right away.
+		s.scanFolder(id)
 	}
 
 	s.notifier.SendToast(s.ctx, feedback.ToastField{
 		Type:    "info",
 		Title:   "Folder Status Updated",
-		Message: fmt.Sprintf("Folder is now %s. Please run a scan to update your library.", statusMsg),
+		Message: fmt.Sprintf("Folder is now %s.", statusMsg),
 	})
 	updatedFolder, err := s.db.GetScanFolderById(s.ctx, id)
 	if err != nil {
@@ -271,6 +375,11 @@ func (s *SettingsService) DeleteFolder(id int64) error {
 	}
 	err = s.db.SoftDeleteScanFolder(s.ctx, id)
 	if err == nil {
+		if s.scanner != nil {
+			if rerr := s.scanner.ResetFolderGroupingRules(id); rerr != nil {
+				s.logger.Warn("Failed to drop grouping rules of deleted folder", "id", id, "error", rerr)
+			}
+		}
 		s.notifier.SendToast(s.ctx, feedback.ToastField{
 			Type:    "info",
 			Title:   "Folder Removed",
@@ -310,12 +419,13 @@ func (s *SettingsService) AddFolder(path string) (ScanFolderDTO, error) {
 			if s.watcher != nil {
 				s.watcher.Watch(absPath)
 			}
+			s.scanFolder(existing.ID)
 			restored, _ := s.db.GetScanFolderById(s.ctx, existing.ID)
 
 			s.notifier.SendToast(s.ctx, feedback.ToastField{
 				Type:    "success",
 				Title:   "Folder Re-added",
-				Message: "This folder was previously removed. Its monitoring has been resumed. Please run a scan to update your library.",
+				Message: "This folder was previously removed. Its monitoring has been resumed and its assets are being re-imported.",
 			})
 
 			return s.mapToDTO(restored), nil
@@ -330,16 +440,28 @@ func (s *SettingsService) AddFolder(path string) (ScanFolderDTO, error) {
 	if s.watcher != nil {
 		s.watcher.Watch(absPath)
 	}
+	s.scanFolder(newFolder.ID)
 
 	s.notifier.SendToast(s.ctx, feedback.ToastField{
 		Type:    "success",
 		Title:   "Folder Added",
-		Message: "New source folder added. Please run a scan to import assets.",
+		Message: "New source folder added. Importing its assets...",
 	})
 
 	return s.mapToDTO(newFolder), nil
 }
 
+// scanFolder starts a background import of a single folder.
+// Failing to start it is not fatal, the folder is still picked up by the next full scan.
+func (s *SettingsService) scanFolder(id int64) {
+	if s.scanner == nil {
+		return
+	}
+	if err := s.scanner.StartScanFolders([]int64{id}); err != nil {
+		s.logger.Error("Failed to start folder scan", "folderId", id, "error", err)
+	}
+}
+
 // mapToDTO converts a database ScanFolder model to a frontend DTO.
 func (s *SettingsService) mapToDTO(f database.ScanFolder) ScanFolderDTO {
 	var lastScannedStr *string
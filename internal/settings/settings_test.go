@@ -34,12 +34,28 @@ type NoOpWatcher struct{}
 func (nw *NoOpWatcher) Watch(path string)   {}
 func (nw *NoOpWatcher) Unwatch(path string) {}
 
+// MockFolderScanner zapisuje foldery, dla których zlecono skan lub usunięto reguły grupowania
+type MockFolderScanner struct {
+	Scanned []int64
+	Reset   []int64
+}
+
+func (m *MockFolderScanner) StartScanFolders(ids []int64) error {
+	m.Scanned = append(m.Scanned, ids...)
+	return nil
+}
+
+func (m *MockFolderScanner) ResetFolderGroupingRules(folderID int64) error {
+	m.Reset = append(m.Reset, folderID)
+	return nil
+}
+
 func TestSettings_ValidatePath(t *testing.T) {
 	mockNotifier := &MockNotifier{}
 	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
 	logLevel := &slog.LevelVar{}
 	// Tutaj nil dla DB jest OK, bo ValidatePath używa tylko os.Stat
-	svc := NewSettingsService(nil, logger, logLevel, mockNotifier, &NoOpWatcher{}, config.NewScannerConfig())
+	svc := NewSettingsService(nil, logger, logLevel, mockNotifier, &NoOpWatcher{}, &MockFolderScanner{}, config.NewScannerConfig())
 
 	t.Run("Should return true for existing directory", func(t *testing.T) {
 		tempDir := t.TempDir()
@@ -71,7 +87,7 @@ func TestSettings_ConfigUpdates(t *testing.T) {
 	cfg := config.NewScannerConfig()
 	logLevel := &slog.LevelVar{}
 	// POPRAWKA 2: Przekazujemy queries zamiast nil
-	svc := NewSettingsService(queries, logger, logLevel, mockNotifier, &NoOpWatcher{}, cfg)
+	svc := NewSettingsService(queries, logger, logLevel, mockNotifier, &NoOpWatcher{}, &MockFolderScanner{}, cfg)
 
 	// POPRAWKA 3: Inicjalizujemy kontekst, bo baza go wymaga
 	ctx := context.Background()
@@ -114,12 +130,44 @@ func TestSettings_ConfigUpdates(t *testing.T) {
 	})
 }
 
+func TestSettings_MaterialChannelSynonyms(t *testing.T) {
+	_, queries := setupTestDB(t)
+	cfg := config.NewScannerConfig()
+	svc := NewSettingsService(queries, slog.New(slog.NewTextHandler(io.Discard, nil)), &slog.LevelVar{}, &MockNotifier{}, &NoOpWatcher{}, &MockFolderScanner{}, cfg)
+	ctx := context.Background()
+	svc.Startup(ctx)
+
+	assert.Equal(t, config.DefaultMaterialChannels, svc.GetMaterialChannelSynonyms())
+
+	err := svc.SetMaterialChannelSynonyms([]config.MaterialChannel{
+		{Role: config.ChannelBaseColor, Synonyms: []string{"Albedo", "BC"}},
+		{Role: config.ChannelNormal, Synonyms: []string{"nrm"}},
+	})
+	assert.NoError(t, err)
+	_, role, ok := cfg.MatchMaterialChannel("rock_bc")
+	assert.True(t, ok)
+	assert.Equal(t, config.ChannelBaseColor, role)
+
+	storedJSON, err := queries.GetSystemSetting(ctx, KeyMaterialChannels)
+	assert.NoError(t, err)
+	assert.Contains(t, storedJSON, `"bc"`, "Synonimy powinny trafić do bazy znormalizowane")
+
+	// Ten sam sufiks w dwóch rolach lub nieznana rola - odrzucone, konfiguracja bez zmian
+	assert.Error(t, svc.SetMaterialChannelSynonyms([]config.MaterialChannel{
+		{Role: config.ChannelBaseColor, Synonyms: []string{"map"}},
+		{Role: config.ChannelNormal, Synonyms: []string{"MAP"}},
+	}))
+	assert.Error(t, svc.SetMaterialChannelSynonyms([]config.MaterialChannel{{Role: "specular", Synonyms: []string{"spec"}}}))
+	assert.Len(t, svc.GetMaterialChannelSynonyms(), 2)
+}
+
 func TestSettings_ScanFolder_CRUD_FullFlow(t *testing.T) {
 	_, queries := setupTestDB(t)
 	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
 	mockNotifier := &MockNotifier{}
 	logLevel := &slog.LevelVar{}
-	svc := NewSettingsService(queries, logger, logLevel, mockNotifier, &NoOpWatcher{}, config.NewScannerConfig())
+	folderScanner := &MockFolderScanner{}
+	svc := NewSettingsService(queries, logger, logLevel, mockNotifier, &NoOpWatcher{}, folderScanner, config.NewScannerConfig())
 	ctx := context.Background()
 	svc.Startup(ctx)
 
@@ -129,6 +177,7 @@ func TestSettings_ScanFolder_CRUD_FullFlow(t *testing.T) {
 		added, err := svc.AddFolder(folderPath)
 		assert.NoError(t, err)
 		assert.Equal(t, folderPath, added.Path)
+		assert.Equal(t, []int64{added.ID}, folderScanner.Scanned, "Dodanie folderu importuje tylko ten folder")
 
 		folders, err := svc.GetFolders()
 		assert.NoError(t, err)
@@ -170,6 +219,7 @@ func TestSettings_ScanFolder_CRUD_FullFlow(t *testing.T) {
 		dbFolder, err := queries.GetScanFolderById(ctx, id)
 		assert.NoError(t, err)
 		assert.True(t, dbFolder.IsDeleted, "Rekord w bazie danych powinien mieć IsDeleted = 1")
+		assert.Equal(t, []int64{id}, folderScanner.Reset, "Usunięcie folderu usuwa jego reguły grupowania")
 	})
 }
 
@@ -177,7 +227,7 @@ func TestSettings_FolderPicker_Mock(t *testing.T) {
 	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
 	mockNotifier := &MockNotifier{}
 	logLevel := &slog.LevelVar{}
-	svc := NewSettingsService(nil, logger, logLevel, mockNotifier, &NoOpWatcher{}, config.NewScannerConfig())
+	svc := NewSettingsService(nil, logger, logLevel, mockNotifier, &NoOpWatcher{}, &MockFolderScanner{}, config.NewScannerConfig())
 	svc.Startup(context.Background())
 
 	t.Run("Successful Selection", func(t *testing.T) {
@@ -195,7 +245,7 @@ func TestSettings_FindBestParent_Logic(t *testing.T) {
 	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
 	mockNotifier := &MockNotifier{}
 	logLevel := &slog.LevelVar{}
-	svc := NewSettingsService(queries, logger, logLevel, mockNotifier, &NoOpWatcher{}, config.NewScannerConfig())
+	svc := NewSettingsService(queries, logger, logLevel, mockNotifier, &NoOpWatcher{}, &MockFolderScanner{}, config.NewScannerConfig())
 	ctx := context.Background()
 	svc.Startup(ctx)
 
@@ -215,7 +265,7 @@ func TestSettings_FindBestParent_Logic(t *testing.T) {
 func TestSettings_MapToDTO_Scenarios(t *testing.T) {
 	mockNotifier := &MockNotifier{}
 	logLevel := &slog.LevelVar{}
-	svc := NewSettingsService(nil, nil, logLevel, mockNotifier, &NoOpWatcher{}, config.NewScannerConfig())
+	svc := NewSettingsService(nil, nil, logLevel, mockNotifier, &NoOpWatcher{}, &MockFolderScanner{}, config.NewScannerConfig())
 
 	t.Run("Map with LastScanned NULL", func(t *testing.T) {
 		f := database.ScanFolder{LastScanned: sql.NullTime{Valid: false}, DateAdded: time.Now()}
@@ -241,7 +291,8 @@ func TestSettings_UpdateFolderStatus_Logic(t *testing.T) {
 	logger := slog.New(slog.NewTextHandler(io.Discard, nil))
 	mockNotifier := &MockNotifier{}
 	logLevel := &slog.LevelVar{}
-	svc := NewSettingsService(queries, logger, logLevel, mockNotifier, &NoOpWatcher{}, config.NewScannerConfig())
+	folderScanner := &MockFolderScanner{}
+	svc := NewSettingsService(queries, logger, logLevel, mockNotifier, &NoOpWatcher{}, folderScanner, config.NewScannerConfig())
 	svc.Startup(ctx)
 	path := filepath.Join(root, "test.png")
 	createDummyFile(t, path)
@@ -251,8 +302,10 @@ func TestSettings_UpdateFolderStatus_Logic(t *testing.T) {
 
 	asset, _ := queries.GetAssetByPath(ctx, path)
 	assert.True(t, asset.IsHidden, "Asset powinien zostać ukryty wraz z folderem")
+	assert.Empty(t, folderScanner.Scanned, "Pauzowanie folderu nie uruchamia skanu")
 
 	svc.UpdateFolderStatus(folderID, true)
 	asset, _ = queries.GetAssetByPath(ctx, path)
 	assert.False(t, asset.IsHidden, "Asset powinien zostać odkryty")
+	assert.Equal(t, []int64{folderID}, folderScanner.Scanned)
 }
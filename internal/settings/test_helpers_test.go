@@ -37,10 +37,15 @@ func (m *MockNotifier) SendScannerStatus(ctx context.Context, status feedback.St
 	m.CallCount++
 }
 
-func (m *MockNotifier) SendScanProgress(ctx context.Context, current, total int, message string) {
+func (m *MockNotifier) SendScanProgress(ctx context.Context, progress feedback.ScanProgressDTO) {
 	m.LastEvent = "scan_progress"
 	m.CallCount++
 }
+func (m *MockNotifier) SendFileOperationProgress(ctx context.Context, progress feedback.FileOperationProgressDTO) {
+	m.LastEvent = "file_operation_progress"
+	m.CallCount++
+}
+
 func (m *MockNotifier) EmitAssetsChanged(ctx context.Context) {
 	m.LastEvent = "assets:changed"
 	m.CallCount++
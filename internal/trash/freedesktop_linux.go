@@ -0,0 +1,211 @@
+//go:build linux
+
+package trash
+
+import (
+	"eclat/internal/fsutil"
+	"errors"
+	"fmt"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"syscall"
+	"time"
+)
+
+// BackendFreedesktop is the name of the freedesktop.org trash backend.
+const BackendFreedesktop = "freedesktop"
+
+// Freedesktop implements the freedesktop.org Trash specification used by Linux desktops, so
+// trashed files show up (and can be restored) in the file manager. Files on the filesystem of
+// the home trash go there; files on other filesystems go to the trash directory at the top of
+// their mount point.
+type Freedesktop struct {
+	homeTrash string
+	uid       int
+}
+
+func systemBackends() []Backend {
+	dir := homeTrashDir()
+	if dir == "" {
+		return nil
+	}
+	return []Backend{NewFreedesktop(dir)}
+}
+
+// homeTrashDir returns $XDG_DATA_HOME/Trash, defaulting to ~/.local/share/Trash.
+func homeTrashDir() string {
+	dataHome := os.Getenv("XDG_DATA_HOME")
+	if dataHome == "" {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return ""
+		}
+		dataHome = filepath.Join(home, ".local", "share")
+	}
+	return filepath.Join(dataHome, "Trash")
+}
+
+// NewFreedesktop returns a freedesktop.org trash backend with the given home trash directory.
+func NewFreedesktop(homeTrash string) *Freedesktop {
+	return &Freedesktop{homeTrash: homeTrash, uid: os.Getuid()}
+}
+
+func (f *Freedesktop) Name() string { return BackendFreedesktop }
+
+func (f *Freedesktop) Trash(path string) (Entry, error) {
+	path, err := filepath.Abs(path)
+	if err != nil {
+		return Entry{}, err
+	}
+	info, err := os.Lstat(path)
+	if err != nil {
+		return Entry{}, err
+	}
+	dev := deviceOf(info)
+
+	trashDir, originalPath := f.homeTrash, path
+	if ensureTrashDir(f.homeTrash) != nil || !onDevice(f.homeTrash, dev) {
+		top := mountPoint(path, dev)
+		if trashDir = f.topTrashDir(top); trashDir == "" {
+			return Entry{}, ErrUnsupported
+		}
+		// Trash directories at the top of a mount point store paths relative to it.
+		if originalPath, err = filepath.Rel(top, path); err != nil {
+			return Entry{}, err
+		}
+	}
+
+	name, infoPath, err := writeTrashInfo(trashDir, filepath.Base(path), originalPath)
+	if err != nil {
+		return Entry{}, err
+	}
+	dest := filepath.Join(trashDir, "files", name)
+	if err := os.Rename(path, dest); err != nil {
+		os.Remove(infoPath)
+		return Entry{}, err
+	}
+	return Entry{Backend: BackendFreedesktop, Path: dest, InfoPath: infoPath}, nil
+}
+
+func (f *Freedesktop) Restore(e Entry, dest string) error {
+	if err := fsutil.MoveFile(e.Path, dest); err != nil {
+		return err
+	}
+	if err := os.Remove(e.InfoPath); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	return nil
+}
+
+func (f *Freedesktop) Purge(e Entry) error {
+	for _, p := range []string{e.Path, e.InfoPath} {
+		if err := os.Remove(p); err != nil && !os.IsNotExist(err) {
+			return err
+		}
+	}
+	return nil
+}
+
+// topTrashDir returns the trash directory of the user at the top of a mount point: the
+// shared $top/.Trash/$uid if an administrator set one up, otherwise $top/.Trash-$uid. It
+// returns "" if neither can be used.
+func (f *Freedesktop) topTrashDir(top string) string {
+	shared := filepath.Join(top, ".Trash")
+	// The spec requires the shared directory to be sticky and not a symlink.
+	if info, err := os.Lstat(shared); err == nil && info.IsDir() && info.Mode()&os.ModeSticky != 0 {
+		dir := filepath.Join(shared, strconv.Itoa(f.uid))
+		if ensureTrashDir(dir) == nil {
+			return dir
+		}
+	}
+	dir := filepath.Join(top, ".Trash-"+strconv.Itoa(f.uid))
+	if ensureTrashDir(dir) == nil {
+		return dir
+	}
+	return ""
+}
+
+// writeTrashInfo reserves a name in the trash by creating its .trashinfo file, which the spec
+// uses as the lock. It returns the name for the files directory and the info file path.
+func writeTrashInfo(trashDir, base, originalPath string) (string, string, error) {
+	content := fmt.Sprintf("[Trash Info]\nPath=%s\nDeletionDate=%s\n",
+		(&url.URL{Path: originalPath}).EscapedPath(), time.Now().Format("2006-01-02T15:04:05"))
+
+	ext := filepath.Ext(base)
+	stem := strings.TrimSuffix(base, ext)
+	for n := 1; n <= 1000; n++ {
+		name := base
+		if n > 1 {
+			name = fmt.Sprintf("%s.%d%s", stem, n, ext)
+		}
+		if _, err := os.Lstat(filepath.Join(trashDir, "files", name)); err == nil {
+			continue
+		}
+		infoPath := filepath.Join(trashDir, "info", name+".trashinfo")
+		file, err := os.OpenFile(infoPath, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0600)
+		if os.IsExist(err) {
+			continue
+		}
+		if err != nil {
+			return "", "", err
+		}
+		_, err = file.WriteString(content)
+		if closeErr := file.Close(); err == nil {
+			err = closeErr
+		}
+		if err != nil {
+			os.Remove(infoPath)
+			return "", "", err
+		}
+		return name, infoPath, nil
+	}
+	return "", "", errors.New("no free name in the trash for " + base)
+}
+
+// ensureTrashDir creates the files and info subdirectories of a trash directory.
+func ensureTrashDir(dir string) error {
+	for _, sub := range []string{"files", "info"} {
+		if err := os.MkdirAll(filepath.Join(dir, sub), 0700); err != nil {
+			return err
+		}
+	}
+	info, err := os.Lstat(dir)
+	if err != nil {
+		return err
+	}
+	if !info.IsDir() {
+		return fmt.Errorf("%s is not a directory", dir)
+	}
+	return nil
+}
+
+// mountPoint walks up from path while the parent directories are on the same device.
+func mountPoint(path string, dev uint64) string {
+	dir := filepath.Dir(path)
+	for {
+		parent := filepath.Dir(dir)
+		if parent == dir {
+			return dir
+		}
+		info, err := os.Stat(parent)
+		if err != nil || deviceOf(info) != dev {
+			return dir
+		}
+		dir = parent
+	}
+}
+
+func onDevice(path string, dev uint64) bool {
+	info, err := os.Stat(path)
+	return err == nil && deviceOf(info) == dev
+}
+
+func deviceOf(info os.FileInfo) uint64 {
+	if st, ok := info.Sys().(*syscall.Stat_t); ok {
+		return uint64(st.Dev)
+	}
+	return 0
+}
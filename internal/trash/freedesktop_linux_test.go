@@ -0,0 +1,61 @@
+//go:build linux
+
+package trash
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFreedesktop_TrashAndRestore(t *testing.T) {
+	root := t.TempDir()
+	homeTrash := filepath.Join(root, "data", "Trash")
+	backend := NewFreedesktop(homeTrash)
+	path := filepath.Join(root, "library", "big rock.png")
+	writeFile(t, path, "rock")
+
+	entry, err := backend.Trash(path)
+	require.NoError(t, err)
+	assert.Equal(t, BackendFreedesktop, entry.Backend)
+	assert.Equal(t, filepath.Join(homeTrash, "files", "big rock.png"), entry.Path)
+	assert.Equal(t, filepath.Join(homeTrash, "info", "big rock.png.trashinfo"), entry.InfoPath)
+	assert.NoFileExists(t, path)
+
+	info, err := os.ReadFile(entry.InfoPath)
+	require.NoError(t, err)
+	lines := strings.Split(strings.TrimSpace(string(info)), "\n")
+	require.Len(t, lines, 3)
+	assert.Equal(t, "[Trash Info]", lines[0])
+	assert.Equal(t, "Path="+strings.ReplaceAll(path, " ", "%20"), lines[1])
+	assert.Regexp(t, `^DeletionDate=\d{4}-\d{2}-\d{2}T\d{2}:\d{2}:\d{2}$`, lines[2])
+
+	// Plik o tej samej nazwie dostaje kolejny numer
+	writeFile(t, path, "another rock")
+	second, err := backend.Trash(path)
+	require.NoError(t, err)
+	assert.Equal(t, filepath.Join(homeTrash, "files", "big rock.2.png"), second.Path)
+
+	require.NoError(t, backend.Restore(entry, path))
+	content, err := os.ReadFile(path)
+	require.NoError(t, err)
+	assert.Equal(t, "rock", string(content))
+	assert.NoFileExists(t, entry.InfoPath)
+
+	require.NoError(t, backend.Purge(second))
+	assert.NoFileExists(t, second.Path)
+	assert.NoFileExists(t, second.InfoPath)
+}
+
+func TestHomeTrashDir(t *testing.T) {
+	t.Setenv("XDG_DATA_HOME", "/data/home")
+	assert.Equal(t, "/data/home/Trash", homeTrashDir())
+
+	t.Setenv("XDG_DATA_HOME", "")
+	t.Setenv("HOME", "/home/user")
+	assert.Equal(t, "/home/user/.local/share/Trash", homeTrashDir())
+}
@@ -0,0 +1,48 @@
+package trash
+
+import (
+	"eclat/internal/fsutil"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// BackendQuarantine is the name of the quarantine backend.
+const BackendQuarantine = "quarantine"
+
+// Quarantine keeps trashed files in a directory managed by the app. It takes any file, so it
+// is the last resort of a Bin.
+type Quarantine struct {
+	dir string
+}
+
+// NewQuarantine returns a quarantine backend storing files in dir.
+func NewQuarantine(dir string) *Quarantine {
+	return &Quarantine{dir: dir}
+}
+
+func (q *Quarantine) Name() string { return BackendQuarantine }
+
+func (q *Quarantine) Trash(path string) (Entry, error) {
+	if err := os.MkdirAll(q.dir, 0700); err != nil {
+		return Entry{}, err
+	}
+	// The timestamp keeps files with the same name apart.
+	dest := filepath.Join(q.dir, fmt.Sprintf("%d-%s", time.Now().UnixNano(), filepath.Base(path)))
+	if err := fsutil.MoveFile(path, dest); err != nil {
+		return Entry{}, err
+	}
+	return Entry{Backend: BackendQuarantine, Path: dest}, nil
+}
+
+func (q *Quarantine) Restore(e Entry, dest string) error {
+	return fsutil.MoveFile(e.Path, dest)
+}
+
+func (q *Quarantine) Purge(e Entry) error {
+	if err := os.Remove(e.Path); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,9 @@
+//go:build !linux
+
+package trash
+
+// systemBackends is empty where no system trash is supported yet; the Bin then uses the
+// quarantine directory only.
+func systemBackends() []Backend {
+	return nil
+}
@@ -0,0 +1,112 @@
+// Package trash moves deleted files to a place they can be restored from: the system trash
+// where the platform has one the app can use, otherwise a quarantine directory managed by the app.
+package trash
+
+import (
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+)
+
+// ErrUnsupported is returned by a backend that cannot take a particular file, e.g. because
+// there is no trash directory on its filesystem. The Bin then tries the next backend.
+var ErrUnsupported = errors.New("trash not available for this file")
+
+// Entry identifies a trashed file.
+type Entry struct {
+	Backend  string `json:"backend"`
+	Path     string `json:"path"`     // Where the file is now
+	InfoPath string `json:"infoPath"` // Metadata written by the backend (.trashinfo); empty if none
+}
+
+// Backend is a place deleted files are moved to.
+type Backend interface {
+	Name() string
+	Trash(path string) (Entry, error)
+	// Restore moves a trashed file to dest, which must not exist.
+	Restore(e Entry, dest string) error
+	// Purge deletes a trashed file for good.
+	Purge(e Entry) error
+}
+
+// Bin trashes files with the first backend that accepts them.
+type Bin struct {
+	backends []Backend
+	logger   *slog.Logger
+}
+
+// New returns a Bin using the system trash where supported and the quarantine directory
+// otherwise.
+func New(quarantineDir string, logger *slog.Logger) *Bin {
+	backends := append(systemBackends(), NewQuarantine(quarantineDir))
+	return NewWithBackends(logger, backends...)
+}
+
+// NewWithBackends returns a Bin trying the given backends in order.
+func NewWithBackends(logger *slog.Logger, backends ...Backend) *Bin {
+	return &Bin{backends: backends, logger: logger}
+}
+
+// Trash moves the file at path to the trash.
+func (b *Bin) Trash(path string) (Entry, error) {
+	err := error(ErrUnsupported)
+	for _, backend := range b.backends {
+		var entry Entry
+		entry, err = backend.Trash(path)
+		if err == nil {
+			b.logger.Info("Moved file to the trash", "path", path, "backend", entry.Backend, "trash_path", entry.Path)
+			return entry, nil
+		}
+		if !errors.Is(err, ErrUnsupported) {
+			b.logger.Warn("Trash backend failed, trying the next one", "backend", backend.Name(), "path", path, "error", err)
+		}
+	}
+	return Entry{}, fmt.Errorf("failed to move %s to the trash: %w", path, err)
+}
+
+// Restore moves a trashed file back to dest, creating its directory if needed. It refuses to
+// overwrite an existing file.
+func (b *Bin) Restore(e Entry, dest string) error {
+	backend, err := b.backend(e)
+	if err != nil {
+		return err
+	}
+	if _, err := os.Lstat(dest); err == nil {
+		return fmt.Errorf("%s already exists", dest)
+	} else if !os.IsNotExist(err) {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(dest), 0755); err != nil {
+		return err
+	}
+	return backend.Restore(e, dest)
+}
+
+// Purge deletes a trashed file for good. Files already gone from the trash are not an error.
+func (b *Bin) Purge(e Entry) error {
+	backend, err := b.backend(e)
+	if err != nil {
+		return err
+	}
+	return backend.Purge(e)
+}
+
+// Exists reports whether the trashed file is still in the trash.
+func (b *Bin) Exists(e Entry) bool {
+	if e.Path == "" {
+		return false
+	}
+	_, err := os.Lstat(e.Path)
+	return err == nil
+}
+
+func (b *Bin) backend(e Entry) (Backend, error) {
+	for _, backend := range b.backends {
+		if backend.Name() == e.Backend {
+			return backend, nil
+		}
+	}
+	return nil, fmt.Errorf("unknown trash backend %q", e.Backend)
+}
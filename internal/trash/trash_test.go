@@ -0,0 +1,71 @@
+package trash
+
+import (
+	"errors"
+	"io"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var testLogger = slog.New(slog.NewTextHandler(io.Discard, nil))
+
+// unsupportedBackend odrzuca każdy plik, jak kosz bez katalogu na danym dysku.
+type unsupportedBackend struct{}
+
+func (unsupportedBackend) Name() string                { return "unsupported" }
+func (unsupportedBackend) Trash(string) (Entry, error) { return Entry{}, ErrUnsupported }
+func (unsupportedBackend) Restore(Entry, string) error { return errors.New("not supported") }
+func (unsupportedBackend) Purge(Entry) error           { return errors.New("not supported") }
+
+func writeFile(t *testing.T, path, content string) {
+	t.Helper()
+	require.NoError(t, os.MkdirAll(filepath.Dir(path), 0755))
+	require.NoError(t, os.WriteFile(path, []byte(content), 0644))
+}
+
+func TestBin_QuarantineFallback(t *testing.T) {
+	quarantineDir := filepath.Join(t.TempDir(), "trash")
+	bin := NewWithBackends(testLogger, unsupportedBackend{}, NewQuarantine(quarantineDir))
+	path := filepath.Join(t.TempDir(), "rock.png")
+	writeFile(t, path, "rock")
+
+	entry, err := bin.Trash(path)
+	require.NoError(t, err)
+	assert.Equal(t, BackendQuarantine, entry.Backend)
+	assert.Equal(t, quarantineDir, filepath.Dir(entry.Path))
+	assert.NoFileExists(t, path)
+	assert.True(t, bin.Exists(entry))
+
+	dest := filepath.Join(t.TempDir(), "restored", "rock.png")
+	require.NoError(t, bin.Restore(entry, dest))
+	content, err := os.ReadFile(dest)
+	require.NoError(t, err)
+	assert.Equal(t, "rock", string(content))
+	assert.False(t, bin.Exists(entry))
+}
+
+func TestBin_RestoreDoesNotOverwrite(t *testing.T) {
+	bin := NewWithBackends(testLogger, NewQuarantine(t.TempDir()))
+	path := filepath.Join(t.TempDir(), "sky.png")
+	writeFile(t, path, "old sky")
+
+	entry, err := bin.Trash(path)
+	require.NoError(t, err)
+	writeFile(t, path, "new sky")
+
+	assert.ErrorContains(t, bin.Restore(entry, path), "already exists")
+	assert.True(t, bin.Exists(entry), "Plik zostaje w koszu")
+
+	require.NoError(t, bin.Purge(entry))
+	assert.False(t, bin.Exists(entry))
+	require.NoError(t, bin.Purge(entry), "Ponowne czyszczenie nie jest błędem")
+
+	_, err = NewWithBackends(testLogger).Trash(path)
+	assert.ErrorIs(t, err, ErrUnsupported)
+	assert.ErrorContains(t, bin.Restore(Entry{Backend: "missing"}, path), "unknown trash backend")
+}
@@ -18,6 +18,10 @@ import (
 // debounceDuration defines the time window to group multiple file events into a single action.
 const debounceDuration = 500 * time.Millisecond
 
+// suppressGrace is how long events for suppressed paths are still ignored after the app is done
+// with them, as file systems report changes with a delay.
+const suppressGrace = 2 * debounceDuration
+
 // Service implements a file system watcher that monitors directories for changes.
 // It reports file creations, modifications, and deletions to the scanner service.
 type Service struct {
@@ -29,6 +33,7 @@ type Service struct {
 	Events       chan string            // Channel to emit file paths that need scanning
 	watchedPaths map[string]bool        // Set of currently watched directories
 	timers       map[string]*time.Timer // Debounce timers for active file events
+	suppressed   map[string]int         // Paths the app itself is changing, see Suppress
 	mu           sync.Mutex
 	shutdownOnce sync.Once
 }
@@ -48,6 +53,7 @@ func NewService(db database.Querier, logger *slog.Logger, cfg *config.ScannerCon
 		config:       cfg,
 		Events:       make(chan string, 1000),
 		timers:       make(map[string]*time.Timer),
+		suppressed:   make(map[string]int),
 		watchedPaths: make(map[string]bool),
 	}, nil
 }
@@ -190,7 +196,7 @@ func (s *Service) startLoop() {
 				}
 			}
 
-			if s.shouldIgnore(event.Name) {
+			if s.shouldIgnore(event.Name) || s.isSuppressed(event.Name) {
 				continue
 			}
 
@@ -239,7 +245,11 @@ func (s *Service) triggerDebounce(path string) {
 			return
 		}
 		delete(s.timers, path)
+		suppressed := s.suppressed[filepath.Clean(path)] > 0
 		s.mu.Unlock()
+		if suppressed {
+			return
+		}
 
 		_, err := os.Stat(path)
 
@@ -257,6 +267,39 @@ func (s *Service) triggerDebounce(path string) {
 	})
 }
 
+// Suppress ignores events for the given files until release is called, and for a short while
+// after. The app uses it when moving or copying files itself, so they are not imported again.
+func (s *Service) Suppress(paths ...string) (release func()) {
+	cleaned := make([]string, len(paths))
+	s.mu.Lock()
+	for i, path := range paths {
+		cleaned[i] = filepath.Clean(path)
+		s.suppressed[cleaned[i]]++
+	}
+	s.mu.Unlock()
+
+	var once sync.Once
+	return func() {
+		once.Do(func() {
+			time.AfterFunc(suppressGrace, func() {
+				s.mu.Lock()
+				defer s.mu.Unlock()
+				for _, path := range cleaned {
+					if s.suppressed[path]--; s.suppressed[path] <= 0 {
+						delete(s.suppressed, path)
+					}
+				}
+			})
+		})
+	}
+}
+
+func (s *Service) isSuppressed(path string) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.suppressed[filepath.Clean(path)] > 0
+}
+
 // sendEvent puts a file path into the events channel for the scanner to consume.
 func (s *Service) sendEvent(path string) {
 	defer func() {
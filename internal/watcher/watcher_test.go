@@ -133,3 +133,26 @@ func TestWatcher_Filter_Extensions(t *testing.T) {
 	// Upewniamy się, że TXT nie wpadł "przypadkiem" wcześniej
 	assertNoEvent(t, svc.Events, 100*time.Millisecond)
 }
+
+// 5. TEST: SUPPRESSED PATHS
+// Pliki przenoszone przez samą aplikację nie trafiają do skanera
+func TestWatcher_Suppress(t *testing.T) {
+	svc, _, root, ctx, cancel := setupWatcherTest(t)
+	defer cancel()
+	defer svc.Shutdown()
+
+	svc.Startup(ctx)
+	time.Sleep(100 * time.Millisecond)
+
+	moved := filepath.Join(root, "moved.png")
+	release := svc.Suppress(moved)
+	createDummyFile(t, moved)
+	release()
+	release()
+	assertNoEvent(t, svc.Events, debounceDuration+200*time.Millisecond)
+
+	// Po upływie okresu karencji zdarzenia znów są zgłaszane
+	time.Sleep(suppressGrace)
+	createDummyFile(t, moved)
+	waitForEvent(t, svc.Events, moved, 2*time.Second)
+}
@@ -73,6 +73,8 @@ func main() {
 			deps.AssetService,
 			deps.MaterialSetService,
 			deps.TagService,
+			deps.SavedSearchService,
+			deps.PaletteService,
 			deps.ScannerService,
 			deps.SettingsService,
 			deps.WatcherService,
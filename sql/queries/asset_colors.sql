@@ -0,0 +1,23 @@
+-- name: DeleteAssetColors :exec
+DELETE FROM asset_colors WHERE asset_id = ?;
+
+-- name: InsertAssetColor :exec
+INSERT INTO asset_colors (asset_id, position, hex, palette_name, palette_hex, weight, lab_l, lab_a, lab_b)
+VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?);
+
+-- name: ListAssetColors :many
+SELECT asset_id, position, hex, palette_name, palette_hex, weight, lab_l, lab_a, lab_b
+FROM asset_colors
+WHERE asset_id = ?
+ORDER BY position ASC;
+
+-- name: ListAssetColorsPage :many
+SELECT asset_id, position, hex
+FROM asset_colors
+WHERE asset_id > sqlc.arg('after_asset_id')
+   OR (asset_id = sqlc.arg('after_asset_id') AND position > sqlc.arg('after_position'))
+ORDER BY asset_id, position
+LIMIT sqlc.arg('limit');
+
+-- name: UpdateAssetColorPalette :exec
+UPDATE asset_colors SET palette_name = ?, palette_hex = ? WHERE asset_id = ? AND position = ?;
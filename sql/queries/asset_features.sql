@@ -0,0 +1,23 @@
+-- name: UpsertAssetFeatures :exec
+INSERT INTO asset_features (asset_id, color_histogram)
+VALUES (?, ?)
+ON CONFLICT(asset_id) DO UPDATE SET color_histogram = excluded.color_histogram;
+
+-- name: DeleteAssetFeatures :exec
+DELETE FROM asset_features WHERE asset_id = ?;
+
+-- name: GetAssetFeatures :one
+SELECT f.asset_id, f.color_histogram, a.perceptual_hash
+FROM asset_features f
+JOIN assets a ON a.id = f.asset_id
+WHERE f.asset_id = ?;
+
+-- name: ListAssetFeatures :many
+SELECT f.asset_id, f.color_histogram, a.perceptual_hash
+FROM asset_features f
+JOIN assets a ON a.id = f.asset_id
+JOIN scan_folders sf ON a.scan_folder_id = sf.id
+WHERE a.is_deleted = 0
+  AND a.is_hidden = 0
+  AND sf.is_deleted = 0
+  AND sf.is_active = 1;
@@ -0,0 +1,19 @@
+-- name: GetAssetGroup :one
+SELECT * FROM asset_groups WHERE group_id = ? LIMIT 1;
+
+-- name: UpsertAssetGroup :exec
+INSERT INTO asset_groups (group_id, is_locked, representative_id)
+VALUES (?, ?, ?)
+ON CONFLICT(group_id) DO UPDATE SET
+    is_locked = excluded.is_locked,
+    representative_id = excluded.representative_id,
+    updated_at = CURRENT_TIMESTAMP;
+
+-- name: DeleteAssetGroup :exec
+DELETE FROM asset_groups WHERE group_id = ?;
+
+-- name: IsGroupLocked :one
+SELECT EXISTS(SELECT 1 FROM asset_groups WHERE group_id = ? AND is_locked = 1);
+
+-- name: ListLockedGroupIDs :many
+SELECT group_id FROM asset_groups WHERE is_locked = 1;
@@ -0,0 +1,12 @@
+-- name: DeleteAssetProperties :exec
+DELETE FROM asset_properties WHERE asset_id = ?;
+
+-- name: InsertAssetProperty :exec
+INSERT INTO asset_properties (asset_id, key, value)
+VALUES (?, ?, ?);
+
+-- name: ListAssetProperties :many
+SELECT asset_id, key, value
+FROM asset_properties
+WHERE asset_id = ?
+ORDER BY key ASC;
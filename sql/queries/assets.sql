@@ -3,9 +3,9 @@ INSERT INTO assets (
     scan_folder_id, file_name, file_path, file_type, file_size,
     thumbnail_path, file_hash,
     image_width, image_height, dominant_color, bit_depth, has_alpha_channel,
-    last_modified, last_scanned, group_id
+    last_modified, last_scanned, group_id, perceptual_hash, material_channel, analysis_version
 ) VALUES (
-    ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?
+    ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?
 )
 RETURNING *;
 
@@ -22,6 +22,27 @@ SELECT * FROM assets
 WHERE file_hash = ? AND file_hash IS NOT NULL
 LIMIT 1;
 
+-- name: ListDuplicateAssets :many
+SELECT id, file_name, file_path, file_size, file_hash, group_id, thumbnail_path, date_added
+FROM assets
+WHERE is_deleted = 0 AND is_hidden = 0 AND file_hash IN (
+    SELECT file_hash FROM assets
+    WHERE is_deleted = 0 AND is_hidden = 0 AND file_hash IS NOT NULL AND file_hash != ''
+    GROUP BY file_hash
+    HAVING COUNT(*) > 1
+)
+ORDER BY file_hash, date_added, id;
+
+-- name: ListAssetsByHash :many
+SELECT id, file_name, file_path, file_size, file_hash, group_id, thumbnail_path, date_added
+FROM assets
+WHERE file_hash = ? AND is_deleted = 0 AND is_hidden = 0
+ORDER BY date_added, id;
+
+-- name: ListAssetsByIDs :many
+SELECT * FROM assets
+WHERE id IN (sqlc.slice('ids'));
+
 -- name: UpdateAssetFromScan :one
 UPDATE assets
 SET
@@ -42,10 +63,15 @@ SET
     image_height = COALESCE(sqlc.narg('image_height'), image_height),
     dominant_color = COALESCE(sqlc.narg('dominant_color'), dominant_color),
     bit_depth = COALESCE(sqlc.narg('bit_depth'), bit_depth),
-    has_alpha_channel = COALESCE(sqlc.narg('has_alpha_channel'), has_alpha_channel)
+    has_alpha_channel = COALESCE(sqlc.narg('has_alpha_channel'), has_alpha_channel),
+    material_channel = COALESCE(sqlc.narg('material_channel'), material_channel),
+    analysis_version = COALESCE(sqlc.narg('analysis_version'), analysis_version)
 WHERE id = sqlc.arg('id')
 RETURNING *;
 
+-- name: UpdateAssetPerceptualHash :exec
+UPDATE assets SET perceptual_hash = ? WHERE id = ?;
+
 -- name: UpdateAssetMetadata :one
 UPDATE assets
 SET
@@ -123,8 +149,19 @@ WHERE at.tag_id IS NULL
 GROUP BY a.id
 LIMIT ? OFFSET ?;
 
+-- name: ListPerceptualHashes :many
+SELECT a.id, a.file_name, a.file_path, a.thumbnail_path, a.file_size, a.image_width, a.image_height, a.perceptual_hash
+FROM assets a
+JOIN scan_folders f ON a.scan_folder_id = f.id
+WHERE a.perceptual_hash IS NOT NULL
+  AND a.is_deleted = 0
+  AND a.is_hidden = 0
+  AND f.is_deleted = 0
+  AND f.is_active = 1;
+
 -- name: ListAssetsForCache :many
-SELECT id,file_path,last_modified,is_deleted,scan_folder_id FROM assets;
+SELECT id,file_path,file_size,last_modified,is_deleted,scan_folder_id,analysis_version
+FROM assets;
 
 -- name: SetAssetRating :exec
 UPDATE assets SET rating = ? WHERE id = ?;
@@ -195,7 +232,27 @@ WHERE a.is_deleted = 0
   AND f.is_deleted = 0
   AND f.is_active = 1
   AND is_hidden = 0
-  AND dominant_color IS NOT NULL AND dominant_color != '';
+  AND dominant_color IS NOT NULL AND dominant_color != ''
+UNION
+SELECT DISTINCT ac.palette_hex
+FROM asset_colors ac
+JOIN assets a ON a.id = ac.asset_id
+JOIN scan_folders f ON a.scan_folder_id = f.id
+WHERE a.is_deleted = 0
+  AND f.is_deleted = 0
+  AND f.is_active = 1
+  AND a.is_hidden = 0
+  AND ac.palette_hex != ''
+  AND ac.weight >= ?;
+
+-- name: ListLegacyDominantColors :many
+SELECT a.id, a.dominant_color
+FROM assets a
+WHERE a.dominant_color IS NOT NULL AND a.dominant_color != ''
+  AND NOT EXISTS (SELECT 1 FROM asset_colors ac WHERE ac.asset_id = a.id);
+
+-- name: UpdateAssetDominantColor :exec
+UPDATE assets SET dominant_color = ? WHERE id = ?;
 
 -- name: MoveAssetsToFolder :exec
 UPDATE assets SET scan_folder_id = ? WHERE scan_folder_id = ?;
@@ -214,6 +271,25 @@ WHERE scan_folder_id = ?
   AND id != ?
 LIMIT ?;
 
+-- name: FindPotentialSiblingsInLibrary :many
+SELECT id, group_id, file_name
+FROM assets
+WHERE file_name LIKE ?
+  AND id != ?
+LIMIT ?;
+
+-- name: ListAssetsForGrouping :many
+SELECT id, scan_folder_id, group_id, file_name, file_hash
+FROM assets
+WHERE is_deleted = 0
+ORDER BY id;
+
+-- name: UpdateAssetGroup :exec
+UPDATE assets SET group_id = ? WHERE id = ?;
+
+-- name: ReassignAssetGroup :exec
+UPDATE assets SET group_id = sqlc.arg('new_group_id') WHERE group_id = sqlc.arg('old_group_id');
+
 -- name: SoftDeleteAssets :exec
 UPDATE assets
 SET is_deleted = 1, is_hidden = 0, deleted_at = CURRENT_TIMESTAMP
@@ -235,6 +311,12 @@ FROM assets
 WHERE group_id = ? AND is_deleted = 0
 ORDER BY last_modified DESC;
 
+-- name: ListGroupMaterialChannels :many
+SELECT id, file_name, file_path, thumbnail_path, material_channel
+FROM assets
+WHERE group_id = ? AND material_channel != '' AND is_deleted = 0
+ORDER BY file_name;
+
 -- name: RenameAsset :one
 UPDATE assets
 SET file_name = ?, file_path = ?
@@ -246,6 +328,37 @@ UPDATE assets
 SET file_type = ?
 WHERE id = ?;
 
--- name: CleanupOldDeletedAssets :exec
-DELETE FROM assets
-WHERE is_deleted = 1 AND deleted_at < datetime('now', '-7 days');
+-- name: ListExpiredDeletedAssetIDs :many
+SELECT id FROM assets
+WHERE is_deleted = 1 AND deleted_at < datetime('now', '-7 days')
+ORDER BY id;
+
+-- name: CopyAssetTags :exec
+INSERT INTO asset_tags (asset_id, tag_id)
+SELECT CAST(sqlc.arg('new_asset_id') AS INTEGER), tag_id
+FROM asset_tags WHERE asset_id = sqlc.arg('asset_id');
+
+-- name: CopyAssetMaterialSets :exec
+INSERT INTO asset_material_sets (asset_id, material_set_id)
+SELECT CAST(sqlc.arg('new_asset_id') AS INTEGER), material_set_id
+FROM asset_material_sets WHERE asset_id = sqlc.arg('asset_id');
+
+-- name: CopyAssetColors :exec
+INSERT INTO asset_colors (asset_id, position, hex, palette_name, palette_hex, weight, lab_l, lab_a, lab_b)
+SELECT CAST(sqlc.arg('new_asset_id') AS INTEGER), position, hex, palette_name, palette_hex, weight, lab_l, lab_a, lab_b
+FROM asset_colors WHERE asset_id = sqlc.arg('asset_id');
+
+-- name: CopyAssetFeatures :exec
+INSERT INTO asset_features (asset_id, color_histogram)
+SELECT CAST(sqlc.arg('new_asset_id') AS INTEGER), color_histogram
+FROM asset_features WHERE asset_id = sqlc.arg('asset_id');
+
+-- name: CopyAssetProperties :exec
+INSERT INTO asset_properties (asset_id, key, value)
+SELECT CAST(sqlc.arg('new_asset_id') AS INTEGER), key, value
+FROM asset_properties WHERE asset_id = sqlc.arg('asset_id');
+
+-- name: CopyModelMetadata :exec
+INSERT INTO model_metadata (asset_id, vertex_count, triangle_count, mesh_count, material_count, texture_count, texture_files, size_x, size_y, size_z)
+SELECT CAST(sqlc.arg('new_asset_id') AS INTEGER), vertex_count, triangle_count, mesh_count, material_count, texture_count, texture_files, size_x, size_y, size_z
+FROM model_metadata WHERE asset_id = sqlc.arg('asset_id');
@@ -0,0 +1,31 @@
+-- name: ListColorPalettes :many
+SELECT * FROM color_palettes ORDER BY name;
+
+-- name: GetColorPaletteById :one
+SELECT * FROM color_palettes WHERE id = ? LIMIT 1;
+
+-- name: GetColorPaletteByName :one
+SELECT * FROM color_palettes WHERE name = ? LIMIT 1;
+
+-- name: GetActiveColorPalette :one
+SELECT * FROM color_palettes WHERE is_active = 1 LIMIT 1;
+
+-- name: CreateColorPalette :one
+INSERT INTO color_palettes (name, colors_json) VALUES (?, ?) RETURNING *;
+
+-- name: UpdateColorPalette :one
+UPDATE color_palettes
+SET
+    name = COALESCE(sqlc.narg('name'), name),
+    colors_json = COALESCE(sqlc.narg('colors_json'), colors_json)
+WHERE id = sqlc.arg('id')
+RETURNING *;
+
+-- name: DeleteColorPalette :exec
+DELETE FROM color_palettes WHERE id = ?;
+
+-- name: DeactivateColorPalettes :exec
+UPDATE color_palettes SET is_active = 0 WHERE is_active = 1;
+
+-- name: ActivateColorPalette :exec
+UPDATE color_palettes SET is_active = 1 WHERE id = ?;
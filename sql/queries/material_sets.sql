@@ -39,6 +39,9 @@ INSERT OR IGNORE INTO asset_material_sets (material_set_id, asset_id) VALUES (?,
 -- name: RemoveAssetFromMaterialSet :exec
 DELETE FROM asset_material_sets WHERE material_set_id = ? AND asset_id = ?;
 
+-- name: ListMaterialSetIDsByAsset :many
+SELECT material_set_id FROM asset_material_sets WHERE asset_id = ? ORDER BY material_set_id;
+
 -- name: ListAssetsInMaterialSet :many
 SELECT a.* FROM assets a
 JOIN asset_material_sets ams ON a.id = ams.asset_id
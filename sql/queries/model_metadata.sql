@@ -0,0 +1,21 @@
+-- name: UpsertModelMetadata :exec
+INSERT INTO model_metadata (asset_id, vertex_count, triangle_count, mesh_count, material_count, texture_count, texture_files, size_x, size_y, size_z)
+VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+ON CONFLICT(asset_id) DO UPDATE SET
+    vertex_count = excluded.vertex_count,
+    triangle_count = excluded.triangle_count,
+    mesh_count = excluded.mesh_count,
+    material_count = excluded.material_count,
+    texture_count = excluded.texture_count,
+    texture_files = excluded.texture_files,
+    size_x = excluded.size_x,
+    size_y = excluded.size_y,
+    size_z = excluded.size_z;
+
+-- name: DeleteModelMetadata :exec
+DELETE FROM model_metadata WHERE asset_id = ?;
+
+-- name: GetModelMetadata :one
+SELECT asset_id, vertex_count, triangle_count, mesh_count, material_count, texture_count, texture_files, size_x, size_y, size_z
+FROM model_metadata
+WHERE asset_id = ?;
@@ -0,0 +1,32 @@
+-- name: CreateJournalEntry :one
+INSERT INTO operation_journal (kind, description, payload)
+VALUES (?, ?, ?)
+RETURNING *;
+
+-- name: GetLastActiveJournalEntry :one
+SELECT * FROM operation_journal
+WHERE is_undone = 0
+ORDER BY id DESC
+LIMIT 1;
+
+-- name: GetFirstUndoneJournalEntry :one
+SELECT * FROM operation_journal
+WHERE is_undone = 1
+ORDER BY id
+LIMIT 1;
+
+-- name: SetJournalEntryUndone :exec
+UPDATE operation_journal SET is_undone = ? WHERE id = ?;
+
+-- name: ListJournalEntries :many
+SELECT * FROM operation_journal ORDER BY id DESC LIMIT ?;
+
+-- name: DeleteUndoneJournalEntries :exec
+DELETE FROM operation_journal WHERE is_undone = 1;
+
+-- name: PruneJournal :exec
+DELETE FROM operation_journal
+WHERE id NOT IN (SELECT id FROM operation_journal ORDER BY id DESC LIMIT ?);
+
+-- name: ClearJournal :exec
+DELETE FROM operation_journal;
@@ -6,3 +6,17 @@ INSERT INTO saved_searches (name, filter_json) VALUES (?, ?) RETURNING *;
 
 -- name: DeleteSavedSearch :exec
 DELETE FROM saved_searches WHERE id = ?;
+
+-- name: GetSavedSearchById :one
+SELECT * FROM saved_searches WHERE id = ? LIMIT 1;
+
+-- name: GetSavedSearchByName :one
+SELECT * FROM saved_searches WHERE name = ? LIMIT 1;
+
+-- name: UpdateSavedSearch :one
+UPDATE saved_searches
+SET
+    name = COALESCE(sqlc.narg('name'), name),
+    filter_json = COALESCE(sqlc.narg('filter_json'), filter_json)
+WHERE id = sqlc.arg('id')
+RETURNING *;
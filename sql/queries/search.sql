@@ -0,0 +1,18 @@
+-- name: DeleteAssetSearchIndex :exec
+DELETE FROM assets_fts WHERE rowid = ?;
+
+-- name: IndexAssetForSearch :exec
+INSERT INTO assets_fts (rowid, file_name, file_path, description, tags)
+SELECT
+    a.id,
+    a.file_name,
+    a.file_path,
+    COALESCE(a.description, ''),
+    COALESCE((
+        SELECT group_concat(t.name, ' ')
+        FROM asset_tags at
+        JOIN tags t ON t.id = at.tag_id
+        WHERE at.asset_id = a.id
+    ), '')
+FROM assets a
+WHERE a.id = ?;
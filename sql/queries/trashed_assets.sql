@@ -0,0 +1,20 @@
+-- name: CreateTrashedAsset :one
+INSERT INTO trashed_assets (
+    original_path, backend, trash_path, info_path,
+    asset_json, tags_json, material_set_ids_json
+) VALUES (?, ?, ?, ?, ?, ?, ?)
+RETURNING *;
+
+-- name: GetTrashedAsset :one
+SELECT * FROM trashed_assets WHERE id = ? LIMIT 1;
+
+-- name: ListTrashedAssets :many
+SELECT * FROM trashed_assets ORDER BY deleted_at DESC, id DESC;
+
+-- name: ListExpiredTrashedAssets :many
+SELECT * FROM trashed_assets
+WHERE deleted_at < datetime('now', '-30 days')
+ORDER BY id;
+
+-- name: DeleteTrashedAsset :exec
+DELETE FROM trashed_assets WHERE id = ?;
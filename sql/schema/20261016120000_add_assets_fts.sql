@@ -0,0 +1,38 @@
+-- +goose Up
+-- Indeks pełnotekstowy (FTS5) dla wyszukiwarki galerii.
+-- rowid = assets.id, kolumna "tags" to nazwy tagów oddzielone spacją.
+CREATE VIRTUAL TABLE assets_fts USING fts5(
+    file_name,
+    file_path,
+    description,
+    tags,
+    tokenize = 'unicode61 remove_diacritics 2',
+    prefix = '2 3'
+);
+
+-- Wypełniamy indeks istniejącymi assetami
+INSERT INTO assets_fts (rowid, file_name, file_path, description, tags)
+SELECT
+    a.id,
+    a.file_name,
+    a.file_path,
+    COALESCE(a.description, ''),
+    COALESCE((
+        SELECT group_concat(t.name, ' ')
+        FROM asset_tags at
+        JOIN tags t ON t.id = at.tag_id
+        WHERE at.asset_id = a.id
+    ), '')
+FROM assets a;
+
+-- Trwałe usunięcie assetu (również kaskadowe) usuwa go z indeksu
+-- +goose StatementBegin
+CREATE TRIGGER assets_fts_after_delete AFTER DELETE ON assets
+BEGIN
+    DELETE FROM assets_fts WHERE rowid = old.id;
+END;
+-- +goose StatementEnd
+
+-- +goose Down
+DROP TRIGGER IF EXISTS assets_fts_after_delete;
+DROP TABLE IF EXISTS assets_fts;
@@ -0,0 +1,7 @@
+-- +goose Up
+-- Hash percepcyjny (pHash, 64 bity zapisane jako 16 znaków hex) do wykrywania podobnych obrazów.
+-- NULL dla modeli i plików, których nie udało się zdekodować.
+ALTER TABLE assets ADD COLUMN perceptual_hash TEXT;
+
+-- +goose Down
+ALTER TABLE assets DROP COLUMN perceptual_hash;
@@ -0,0 +1,12 @@
+-- +goose Up
+-- Wektor cech wizualnych do wyszukiwania "podobnych" assetów.
+-- color_histogram: histogram kolorów w przestrzeni Lab (4 x 6 x 6 = 144 przedziały, 1 bajt na przedział).
+-- Hash percepcyjny leży w assets.perceptual_hash.
+CREATE TABLE asset_features (
+    asset_id INTEGER PRIMARY KEY,
+    color_histogram BLOB NOT NULL,
+    FOREIGN KEY (asset_id) REFERENCES assets(id) ON DELETE CASCADE
+);
+
+-- +goose Down
+DROP TABLE asset_features;
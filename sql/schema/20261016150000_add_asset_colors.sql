@@ -0,0 +1,25 @@
+-- +goose Up
+-- Paleta głównych kolorów assetu (do 5), od najbardziej dominującego.
+-- hex: surowy kolor klastra, palette_name/palette_hex: najbliższy kolor z palety.
+-- weight: udział koloru w obrazku (0..1), lab_*: kolor w przestrzeni Lab (skala go-colorful, L 0..1)
+-- do wyszukiwania po dowolnym HEX z tolerancją.
+-- assets.dominant_color zostaje jako kolor na pozycji 0 (i fallback dla starszych skanów).
+CREATE TABLE asset_colors (
+    asset_id INTEGER NOT NULL,
+    position INTEGER NOT NULL,
+    hex TEXT NOT NULL,
+    palette_name TEXT NOT NULL DEFAULT '',
+    palette_hex TEXT NOT NULL DEFAULT '',
+    weight REAL NOT NULL,
+    lab_l REAL NOT NULL,
+    lab_a REAL NOT NULL,
+    lab_b REAL NOT NULL,
+    PRIMARY KEY (asset_id, position),
+    FOREIGN KEY (asset_id) REFERENCES assets(id) ON DELETE CASCADE
+);
+
+CREATE INDEX idx_asset_colors_palette ON asset_colors(palette_hex, weight);
+
+-- +goose Down
+DROP INDEX IF EXISTS idx_asset_colors_palette;
+DROP TABLE asset_colors;
@@ -0,0 +1,20 @@
+-- +goose Up
+-- Palety kolorów definiowane przez użytkownika (np. paleta marki, "biomy").
+-- colors_json: tablica [{"name": "...", "hex": "#RRGGBB"}]. Aktywna może być tylko jedna paleta.
+CREATE TABLE color_palettes (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    name TEXT NOT NULL UNIQUE,
+    colors_json TEXT NOT NULL,
+    is_active BOOLEAN NOT NULL DEFAULT 0,
+    date_added DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE UNIQUE INDEX idx_color_palettes_active ON color_palettes(is_active) WHERE is_active = 1;
+
+-- Domyślna paleta = dotychczasowa config.PredefinedPalette
+INSERT INTO color_palettes (name, colors_json, is_active) VALUES
+('Default', '[{"name":"Black","hex":"#000000"},{"name":"White","hex":"#FFFFFF"},{"name":"Dark Gray","hex":"#404040"},{"name":"Gray","hex":"#808080"},{"name":"Light Gray","hex":"#C0C0C0"},{"name":"Dark Red","hex":"#8B0000"},{"name":"Red","hex":"#FF0000"},{"name":"Crimson","hex":"#DC143C"},{"name":"Pink","hex":"#FFC0CB"},{"name":"Hot Pink","hex":"#FF69B4"},{"name":"Coral","hex":"#FF7F50"},{"name":"Brown","hex":"#A52A2A"},{"name":"Saddle Brown","hex":"#8B4513"},{"name":"Orange","hex":"#FFA500"},{"name":"Gold","hex":"#FFD700"},{"name":"Yellow","hex":"#FFFF00"},{"name":"Beige","hex":"#F5F5DC"},{"name":"Olive","hex":"#808000"},{"name":"Dark Green","hex":"#006400"},{"name":"Green","hex":"#008000"},{"name":"Lime","hex":"#00FF00"},{"name":"Teal","hex":"#008080"},{"name":"Cyan","hex":"#00FFFF"},{"name":"Sky Blue","hex":"#87CEEB"},{"name":"Blue","hex":"#0000FF"},{"name":"Navy","hex":"#000080"},{"name":"Turquoise","hex":"#40E0D0"},{"name":"Indigo","hex":"#4B0082"},{"name":"Purple","hex":"#800080"},{"name":"Violet","hex":"#EE82EE"},{"name":"Lavender","hex":"#E6E6FA"},{"name":"Magenta","hex":"#FF00FF"}]', 1);
+
+-- +goose Down
+DROP INDEX IF EXISTS idx_color_palettes_active;
+DROP TABLE color_palettes;
@@ -0,0 +1,14 @@
+-- +goose Up
+-- Dodatkowe właściwości formatu odczytane z pliku (np. tryb koloru i liczba warstw PSD, kanały EXR,
+-- kompresja DDS, wersja Blendera). Klucze są stałymi z pakietu scanner, wartości to tekst.
+-- Wymiary, bit depth i alpha zostają w kolumnach assets, bo po nich się filtruje.
+CREATE TABLE asset_properties (
+    asset_id INTEGER NOT NULL,
+    key TEXT NOT NULL,
+    value TEXT NOT NULL,
+    PRIMARY KEY (asset_id, key),
+    FOREIGN KEY (asset_id) REFERENCES assets(id) ON DELETE CASCADE
+);
+
+-- +goose Down
+DROP TABLE asset_properties;
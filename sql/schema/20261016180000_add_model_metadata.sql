@@ -0,0 +1,24 @@
+-- +goose Up
+-- Statystyki modeli 3D (glTF/GLB, OBJ) odczytane przy skanowaniu - artyści budżetują po liczbie trójkątów.
+-- texture_count: liczba tekstur (także osadzonych w GLB), texture_files: JSON z listą plików tekstur,
+-- do których model się odwołuje (ścieżki jak w pliku, zwykle względne).
+-- size_*: wymiary bounding boxa w jednostkach pliku.
+CREATE TABLE model_metadata (
+    asset_id INTEGER PRIMARY KEY,
+    vertex_count INTEGER NOT NULL,
+    triangle_count INTEGER NOT NULL,
+    mesh_count INTEGER NOT NULL,
+    material_count INTEGER NOT NULL,
+    texture_count INTEGER NOT NULL,
+    texture_files TEXT NOT NULL DEFAULT '[]',
+    size_x REAL NOT NULL,
+    size_y REAL NOT NULL,
+    size_z REAL NOT NULL,
+    FOREIGN KEY (asset_id) REFERENCES assets(id) ON DELETE CASCADE
+);
+
+CREATE INDEX idx_model_metadata_triangles ON model_metadata(triangle_count);
+
+-- +goose Down
+DROP INDEX IF EXISTS idx_model_metadata_triangles;
+DROP TABLE model_metadata;
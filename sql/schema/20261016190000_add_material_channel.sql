@@ -0,0 +1,7 @@
+-- +goose Up
+-- Rola mapy tekstury w zestawie PBR (baseColor, normal, roughness, ...) rozpoznana z sufiksu nazwy pliku.
+-- Pusty tekst dla plików, które nie są mapami materiału.
+ALTER TABLE assets ADD COLUMN material_channel TEXT NOT NULL DEFAULT '';
+
+-- +goose Down
+ALTER TABLE assets DROP COLUMN material_channel;
@@ -0,0 +1,13 @@
+-- +goose Up
+-- Ręczne decyzje użytkownika o grupach assetów (group_id z tabeli assets).
+-- is_locked: skaner nie dołącza do grupy nowych plików i nie przegrupowuje jej członków.
+-- representative_id: przypięty reprezentant grupy w widoku "tylko reprezentanci".
+CREATE TABLE asset_groups (
+    group_id TEXT NOT NULL PRIMARY KEY,
+    is_locked BOOLEAN NOT NULL DEFAULT 0,
+    representative_id INTEGER REFERENCES assets(id) ON DELETE SET NULL,
+    updated_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+-- +goose Down
+DROP TABLE asset_groups;
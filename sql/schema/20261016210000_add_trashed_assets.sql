@@ -0,0 +1,20 @@
+-- +goose Up
+-- Assety usunięte na stałe. Plik trafia do kosza (systemowego albo kwarantanny aplikacji),
+-- a tutaj zostaje migawka wiersza, tagów i kolekcji, z której można asset przywrócić.
+-- backend: 'freedesktop' / 'quarantine'; pusty, gdy plik nie był przenoszony (np. nie było go już na dysku).
+CREATE TABLE trashed_assets (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    original_path TEXT NOT NULL,
+    backend TEXT NOT NULL DEFAULT '',
+    trash_path TEXT NOT NULL DEFAULT '',
+    info_path TEXT NOT NULL DEFAULT '',
+    asset_json TEXT NOT NULL,
+    tags_json TEXT NOT NULL DEFAULT '[]',
+    material_set_ids_json TEXT NOT NULL DEFAULT '[]',
+    deleted_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX idx_trashed_assets_deleted_at ON trashed_assets(deleted_at);
+
+-- +goose Down
+DROP TABLE trashed_assets;
@@ -0,0 +1,15 @@
+-- +goose Up
+-- Dziennik operacji na bibliotece (zmiana nazwy, tagi, ocena, kosz, typ, kolekcje) do cofania i ponawiania.
+-- payload: JSON operacji ze stanem przed i po, dzięki któremu można ją zastosować w obie strony.
+-- is_undone: operacja cofnięta, czeka na ponowienie. Nowa operacja usuwa wszystkie cofnięte wpisy.
+CREATE TABLE operation_journal (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    kind TEXT NOT NULL,
+    description TEXT NOT NULL,
+    payload TEXT NOT NULL,
+    is_undone BOOLEAN NOT NULL DEFAULT 0,
+    created_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+-- +goose Down
+DROP TABLE operation_journal;
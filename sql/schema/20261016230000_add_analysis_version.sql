@@ -0,0 +1,11 @@
+-- +goose Up
+-- Wersja analizy skanera, która ostatnio przetworzyła plik (miniatura, hash percepcyjny, geometria).
+-- Ustawiana także wtedy, gdy analiza się nie udała, więc uszkodzone pliki nie są analizowane przy każdym skanie.
+-- Pliki z hashem percepcyjnym albo geometrią zostały już przeanalizowane; pozostałe zostaną odświeżone raz.
+ALTER TABLE assets ADD COLUMN analysis_version INTEGER NOT NULL DEFAULT 0;
+UPDATE assets SET analysis_version = 1
+WHERE perceptual_hash IS NOT NULL
+   OR EXISTS (SELECT 1 FROM model_metadata mm WHERE mm.asset_id = assets.id);
+
+-- +goose Down
+ALTER TABLE assets DROP COLUMN analysis_version;